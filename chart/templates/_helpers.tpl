@@ -111,7 +111,7 @@ Telemetry adapter enabled check
 Returns true if telemetry adapter is enabled and properly configured
 */}}
 {{- define "language-operator.telemetryAdapter.enabled" -}}
-{{- if and .Values.telemetry.queryBackend.enabled .Values.telemetry.queryBackend.endpoint -}}
+{{- if and .Values.telemetry.queryBackend.enabled (or .Values.telemetry.queryBackend.endpoint (eq .Values.telemetry.queryBackend.type "datadog")) -}}
 {{- true -}}
 {{- else -}}
 {{- false -}}
@@ -137,7 +137,7 @@ Telemetry adapter type validation
 Returns the adapter type if valid, otherwise "noop"
 */}}
 {{- define "language-operator.telemetryAdapter.type" -}}
-{{- $validTypes := list "signoz" "jaeger" "tempo" "noop" -}}
+{{- $validTypes := list "signoz" "prometheus" "jaeger" "tempo" "datadog" "noop" -}}
 {{- if has .Values.telemetry.queryBackend.type $validTypes -}}
 {{- .Values.telemetry.queryBackend.type -}}
 {{- else -}}
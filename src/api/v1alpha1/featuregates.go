@@ -0,0 +1,98 @@
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Feature gates toggle experimental reconcile behaviors. They are set operator-wide with
+// --feature-gates and can be overridden per agent with spec.featureGates.
+const (
+	// FeatureGateUnhealthyPodDetection counts pods that stay not-ready past the unhealthy
+	// threshold as failures for self-healing, in addition to crashed pods
+	FeatureGateUnhealthyPodDetection = "UnhealthyPodDetection"
+
+	// FeatureGateGoalAsInstructions synthesizes code from spec.goal when spec.instructions is empty
+	FeatureGateGoalAsInstructions = "GoalAsInstructions"
+
+	// FeatureGatePartialSynthesis regenerates only the tasks affected by an instruction change
+	// instead of re-synthesizing the whole agent
+	FeatureGatePartialSynthesis = "PartialSynthesis"
+
+	// FeatureGatePersonaConstraintValidation rejects synthesized code that violates the
+	// constraints (maxToolCalls, blockedTopics) of the agent's composed persona
+	FeatureGatePersonaConstraintValidation = "PersonaConstraintValidation"
+
+	// FeatureGateModelHealthRouting leaves models whose provider health check failed out of the
+	// agent's model endpoints while any referenced model is reachable. Off by default, since each
+	// change in a model's health rewrites the endpoints and rolls the agents referencing it.
+	FeatureGateModelHealthRouting = "ModelHealthRouting"
+)
+
+// defaultFeatureGates holds the built-in state of every known feature gate
+var defaultFeatureGates = map[string]bool{
+	FeatureGateUnhealthyPodDetection:       true,
+	FeatureGateGoalAsInstructions:          false,
+	FeatureGatePartialSynthesis:            true,
+	FeatureGatePersonaConstraintValidation: false,
+	FeatureGateModelHealthRouting:          false,
+}
+
+// FeatureGateDefault returns the built-in state of a feature gate and whether the gate is known
+func FeatureGateDefault(name string) (enabled bool, known bool) {
+	enabled, known = defaultFeatureGates[name]
+	return enabled, known
+}
+
+// KnownFeatureGates returns the sorted names of all recognized feature gates
+func KnownFeatureGates() []string {
+	names := make([]string, 0, len(defaultFeatureGates))
+	for name := range defaultFeatureGates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// UnknownFeatureGates returns the sorted names in gates that are not recognized feature gates
+func UnknownFeatureGates(gates map[string]bool) []string {
+	var unknown []string
+	for name := range gates {
+		if _, known := defaultFeatureGates[name]; !known {
+			unknown = append(unknown, name)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+// ParseFeatureGates parses a comma-separated list of Name=true|false pairs (e.g. "UnhealthyPodDetection=false")
+func ParseFeatureGates(s string) (map[string]bool, error) {
+	gates := make(map[string]bool)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		name, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid feature gate %q, expected Name=true|false", pair)
+		}
+
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("invalid feature gate %q, name is empty", pair)
+		}
+
+		enabled, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for feature gate %s: %w", name, err)
+		}
+
+		gates[name] = enabled
+	}
+	return gates, nil
+}
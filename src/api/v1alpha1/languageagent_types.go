@@ -11,17 +11,25 @@ type LanguageAgentSpec struct {
 	// +optional
 	ClusterRef string `json:"clusterRef,omitempty"`
 
-	// Image is the container image to run for this agent
-	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:MinLength=1
-	Image string `json:"image"`
+	// Image is the container image to run for this agent. When empty, the agent runs the
+	// defaultAgentImage of the cluster referenced by clusterRef.
+	// +optional
+	Image string `json:"image,omitempty"`
 
-	// ImagePullPolicy defines when to pull the container image
+	// ImagePullPolicy defines when to pull the agent and tool sidecar images.
+	// Defaults to the referenced cluster's imagePullPolicy, then to the Kubernetes default for the image tag.
 	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
-	// +kubebuilder:default=IfNotPresent
 	// +optional
 	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
 
+	// LogLevel is the log verbosity of the agent runtime, passed as LOG_LEVEL and AGENT_LOG_LEVEL.
+	// When set, spec.env may not set those variables. When unset, a level set through spec.env is
+	// kept; otherwise it defaults to the referenced cluster's defaultLogLevel, then to the runtime's
+	// own default. Changing it rolls the agent's pods.
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	// +optional
+	LogLevel string `json:"logLevel,omitempty"`
+
 	// ImagePullSecrets is a list of references to secrets for pulling images
 	// +optional
 	ImagePullSecrets []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
@@ -35,11 +43,25 @@ type LanguageAgentSpec struct {
 	// +optional
 	ToolRefs []ToolReference `json:"toolRefs,omitempty"`
 
+	// ToolSelector selects LanguageTools in the agent's namespace by label, in addition to ToolRefs
+	// Tools are picked up or dropped automatically as matching LanguageTools are created or deleted
+	// +optional
+	ToolSelector *metav1.LabelSelector `json:"toolSelector,omitempty"`
+
 	// PersonaRefs is a list of LanguagePersona references that compose in order of importance
 	// Personas are merged with later personas taking precedence over earlier ones
 	// +optional
 	PersonaRefs []PersonaReference `json:"personaRefs,omitempty"`
 
+	// IgnoreDefaultPersona opts the agent out of the namespace's default personas
+	// (LanguagePersonas labeled langop.io/default-persona: "true")
+	// +optional
+	IgnoreDefaultPersona bool `json:"ignoreDefaultPersona,omitempty"`
+
+	// DependsOn lists LanguageAgents that must be Ready before this agent's workload is created
+	// +optional
+	DependsOn []AgentReference `json:"dependsOn,omitempty"`
+
 	// Goal defines the agent's objective (for autonomous agents)
 	// +optional
 	Goal string `json:"goal,omitempty"`
@@ -82,7 +104,21 @@ type LanguageAgentSpec struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
-	// Env contains environment variables for the agent container
+	// DisruptionBudget is the minimum number of agent pods kept available during voluntary
+	// disruptions such as node drains. It only applies to agents running more than one replica
+	// and must be lower than spec.replicas. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	DisruptionBudget *int32 `json:"disruptionBudget,omitempty"`
+
+	// DeploymentStrategy configures how the agent Deployment replaces pods when the agent
+	// changes. Unset rolling update parameters default to maxSurge=1 and maxUnavailable=0,
+	// so a new pod is ready before the old one is terminated.
+	// +optional
+	DeploymentStrategy *UpdateStrategySpec `json:"deploymentStrategy,omitempty"`
+
+	// Env contains environment variables for the agent container. Names the operator manages
+	// (see IsReservedAgentEnvVar) are rejected, and ignored on agents admitted before they were.
 	// +optional
 	Env []corev1.EnvVar `json:"env,omitempty"`
 
@@ -122,6 +158,17 @@ type LanguageAgentSpec struct {
 	// +optional
 	Volumes []corev1.Volume `json:"volumes,omitempty"`
 
+	// StartupProbe defines the startup probe for the agent container.
+	// Liveness and readiness checks are held off until it succeeds, giving slow
+	// starting agents (e.g. installing gems) time to initialize.
+	// If not set, a TCP probe on the webhook port allows up to 5 minutes.
+	// +optional
+	StartupProbe *corev1.Probe `json:"startupProbe,omitempty"`
+
+	// Lifecycle configures how Deployment-based agent pods shut down
+	// +optional
+	Lifecycle *AgentLifecycleSpec `json:"lifecycle,omitempty"`
+
 	// PodAnnotations are annotations to add to the Pods
 	// +optional
 	PodAnnotations map[string]string `json:"podAnnotations,omitempty"`
@@ -142,6 +189,76 @@ type LanguageAgentSpec struct {
 	// +optional
 	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
 
+	// WarmReload delivers new code (from learning or self-healing) to the running agent
+	// instead of restarting its pods. Requires an agent runtime that serves the reload
+	// endpoint; pods are restarted when the reload is unsupported or fails.
+	// +optional
+	WarmReload bool `json:"warmReload,omitempty"`
+
+	// StrictCleanup blocks removal of the agent's finalizer until cleanup of its routes,
+	// services, and cross-namespace ReferenceGrants succeeds, so no orphans are left behind.
+	// Deletion proceeds anyway once cleanup has been failing for 10 minutes.
+	// When false, cleanup failures are logged and deletion proceeds immediately.
+	// +optional
+	StrictCleanup bool `json:"strictCleanup,omitempty"`
+
+	// GenerateDocs also synthesizes a markdown summary of the agent's behavior, tasks,
+	// tools, and triggers, stored in the <agent>-docs ConfigMap and regenerated whenever
+	// the code changes
+	// +optional
+	GenerateDocs bool `json:"generateDocs,omitempty"`
+
+	// Synthesis overrides the synthesis model's generation parameters when synthesizing this
+	// agent's code, e.g. more output tokens for agents whose code would otherwise be truncated
+	// +optional
+	Synthesis *AgentSynthesisSettings `json:"synthesis,omitempty"`
+
+	// SynthesisCandidates is the number of candidate implementations to synthesize for each
+	// full synthesis. Candidates that fail validation are discarded and the shortest valid
+	// one is deployed. Each candidate is a separate LLM call, so generation stops early once
+	// the namespace synthesis cost quota is used up.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=5
+	// +optional
+	SynthesisCandidates int32 `json:"synthesisCandidates,omitempty"`
+
+	// RequireCostData fails synthesis with a CostDataUnavailable reason when the synthesis model
+	// has no pricing configured or reports no cost, instead of synthesizing without charging the
+	// cost quota. Set pricing to zero on the model to acknowledge a free model.
+	// +optional
+	RequireCostData bool `json:"requireCostData,omitempty"`
+
+	// SynthesisQuota caps this agent's own daily synthesis spend and attempts. It is enforced
+	// in addition to the namespace quota, so the stricter of the two applies.
+	// +optional
+	SynthesisQuota *AgentSynthesisQuota `json:"synthesisQuota,omitempty"`
+
+	// RequiredModelCapabilities lists capabilities every referenced model must support,
+	// e.g. tool-calling for agents that use tools. Agents whose models lack a required
+	// capability are rejected at admission.
+	// +kubebuilder:validation:items:Enum=tool-calling;vision;json-mode
+	// +optional
+	RequiredModelCapabilities []string `json:"requiredModelCapabilities,omitempty"`
+
+	// SynthesisExampleSet selects a set of curated instruction and code examples from the
+	// operator's synthesis example library to include as few-shot examples during synthesis.
+	// The most relevant examples by tag are used. An unknown set is reported with an event
+	// and synthesis proceeds without examples.
+	// +optional
+	SynthesisExampleSet string `json:"synthesisExampleSet,omitempty"`
+
+	// ModelRequestTimeout bounds each request the agent makes to its models (e.g., "90s", "5m").
+	// It is passed to the agent runtime as MODEL_REQUEST_TIMEOUT and also limits the model
+	// requests made while synthesizing the agent's code. When unset, synthesis uses the
+	// model's spec.timeout and the runtime keeps its own default.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ns|us|µs|ms|s|m|h)$`
+	// +optional
+	ModelRequestTimeout string `json:"modelRequestTimeout,omitempty"`
+
+	// Learning configures review of the learned optimizations applied to this agent's tasks
+	// +optional
+	Learning *LearningSpec `json:"learning,omitempty"`
+
 	// MemoryStore configures conversation memory persistence
 	// +optional
 	MemoryStore *MemoryStoreSpec `json:"memoryStore,omitempty"`
@@ -150,6 +267,15 @@ type LanguageAgentSpec struct {
 	// +optional
 	Observability *AgentObservabilitySpec `json:"observability,omitempty"`
 
+	// Telemetry customizes the OpenTelemetry data emitted by the agent
+	// +optional
+	Telemetry *AgentTelemetrySpec `json:"telemetry,omitempty"`
+
+	// FeatureGates enables or disables experimental behaviors for this agent,
+	// overriding the operator-wide defaults. Unknown gates are ignored.
+	// +optional
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+
 	// RateLimits defines rate limiting for this agent
 	// +optional
 	RateLimits *AgentRateLimitSpec `json:"rateLimits,omitempty"`
@@ -158,16 +284,73 @@ type LanguageAgentSpec struct {
 	// +optional
 	SafetyConfig *SafetyConfigSpec `json:"safetyConfig,omitempty"`
 
+	// Chaos injects failures into agent runs to exercise self-healing. It only takes effect when
+	// the operator runs with --enable-chaos and the agent's namespace is labeled for chaos testing.
+	// +optional
+	Chaos *AgentChaosSpec `json:"chaos,omitempty"`
+
 	// Workspace defines persistent storage for the agent
 	// +optional
 	Workspace *WorkspaceSpec `json:"workspace,omitempty"`
 
+	// WebhookRoutes routes webhook requests by path prefix to ports of the agent container.
+	// Prefixes may not overlap. When unset, all paths are routed to the agent's webhook server.
+	// +optional
+	WebhookRoutes []WebhookRouteRule `json:"webhookRoutes,omitempty"`
+
 	// Egress defines external network access rules for this agent
 	// By default, agents can access all resources within the cluster but no external endpoints
+	// On Cilium, rules with DNS names are also enforced by name through a CiliumNetworkPolicy
 	// +optional
 	Egress []NetworkRule `json:"egress,omitempty"`
+
+	// InheritClusterEgress includes the spec.defaultEgress of the agent's LanguageCluster in its
+	// egress, with the agent's own rules allowing access in addition to it. Defaults to true; set
+	// it to false to allow only the agent's own rules.
+	// +optional
+	InheritClusterEgress *bool `json:"inheritClusterEgress,omitempty"`
 }
 
+// Model roles for spec.modelRefs
+const (
+	// ModelRolePrimary marks the model the agent uses first
+	ModelRolePrimary = "primary"
+	// ModelRoleFallback marks a model the agent fails over to when the others are unavailable
+	ModelRoleFallback = "fallback"
+)
+
+// Rolling update parameters of agent Deployments when spec.deploymentStrategy leaves them unset
+const (
+	DefaultAgentMaxSurge       int32 = 1
+	DefaultAgentMaxUnavailable int32 = 0
+)
+
+// Agent runtime log levels for spec.logLevel
+const (
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+)
+
+// ForceWorkloadAnnotation forces the workload type of an agent regardless of spec.executionMode:
+// "deployment" or "cronjob"
+const ForceWorkloadAnnotation = "langop.io/force-workload"
+
+// AllowPartialToolsAnnotation lets an agent synthesize while some of its tools are not ready or
+// haven't published their schemas yet, when set to "true"
+const AllowPartialToolsAnnotation = "langop.io/allow-partial-tools"
+
+// Annotations that control learning for an agent
+const (
+	// PinnedCodeVersionAnnotation pins the agent to a code version, such as "3" or "v3": its pods
+	// run the code of the versioned ConfigMap <agent>-v<version> rather than the latest synthesized
+	// code. Learning creates no new versions for pinned agents, since they would be ignored.
+	PinnedCodeVersionAnnotation = "langop.io/pinned-code-version"
+	// LearningDisabledAnnotation disables learning for the agent when set to "true"
+	LearningDisabledAnnotation = "langop.io/learning-disabled"
+)
+
 // ModelReference references a LanguageModel
 type ModelReference struct {
 	// Name is the name of the LanguageModel
@@ -178,9 +361,10 @@ type ModelReference struct {
 	// +optional
 	Namespace string `json:"namespace,omitempty"`
 
-	// Role defines the purpose of this model (primary, fallback, specialized)
+	// Role defines the purpose of this model (primary, fallback, specialized). Primary models are
+	// offered to the agent first and fallback models last; models without a role count as primary.
+	// At most one model may declare the primary role.
 	// +kubebuilder:validation:Enum=primary;fallback;reasoning;tool-calling;summarization
-	// +kubebuilder:default=primary
 	// +optional
 	Role string `json:"role,omitempty"`
 
@@ -220,6 +404,18 @@ type PersonaReference struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// AgentReference references another LanguageAgent
+type AgentReference struct {
+	// Name is the name of the LanguageAgent
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+
+	// Namespace is the namespace of the LanguageAgent (defaults to same namespace).
+	// Dependencies must be in the same namespace as the agent.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
 // EventTriggerSpec defines an event trigger
 type EventTriggerSpec struct {
 	// Type is the event type (webhook, kubernetes-event, message-queue)
@@ -236,6 +432,40 @@ type EventTriggerSpec struct {
 	Filter map[string]string `json:"filter,omitempty"`
 }
 
+// LearningSpec configures how learned task conversions are exposed for review and promoted
+type LearningSpec struct {
+	// ExportAnalysis writes the full pattern analysis behind each task conversion, including
+	// the execution trace evidence it was derived from, to the <agent>-learning-analysis ConfigMap
+	// +optional
+	ExportAnalysis bool `json:"exportAnalysis,omitempty"`
+
+	// RequireApproval holds learned code back until a human approves the task by listing it
+	// in the comma-separated langop.io/learning-approved annotation. Implies ExportAnalysis,
+	// so the analysis can be reviewed before approving.
+	// +optional
+	RequireApproval bool `json:"requireApproval,omitempty"`
+
+	// TraceWindow is how far back execution traces are analyzed for this agent (e.g., "6h"),
+	// overriding the operator's --learning-trace-window
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ns|us|µs|ms|s|m|h)$`
+	// +optional
+	TraceWindow string `json:"traceWindow,omitempty"`
+
+	// MinTraceAge excludes executions that completed more recently than this (e.g., "2m"),
+	// overriding the operator's --min-trace-age
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ns|us|µs|ms|s|m|h)$`
+	// +optional
+	MinTraceAge string `json:"minTraceAge,omitempty"`
+
+	// HealthGateWindow is how long the error rate of a task is observed after a learned version
+	// is promoted (e.g., "30m"). The promotion is rolled back if the task's error rate rose above
+	// its rate before promotion. Overrides the operator's --learning-health-gate-window; "0s"
+	// disables the gate for this agent.
+	// +kubebuilder:validation:Pattern=`^[0-9]+(ns|us|µs|ms|s|m|h)$`
+	// +optional
+	HealthGateWindow string `json:"healthGateWindow,omitempty"`
+}
+
 // MemoryStoreSpec configures conversation memory
 type MemoryStoreSpec struct {
 	// Type specifies the memory backend
@@ -288,6 +518,46 @@ type AgentObservabilitySpec struct {
 	LogConversations bool `json:"logConversations,omitempty"`
 }
 
+// AgentLifecycleSpec defines agent pod shutdown behavior
+type AgentLifecycleSpec struct {
+	// PreStopDelaySeconds keeps a terminating agent serving webhook traffic for this long before
+	// it is signalled to stop, so the pod is removed from Service endpoints first. The pod's
+	// termination grace period is extended to match. 0 disables the delay.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=10
+	// +optional
+	PreStopDelaySeconds *int32 `json:"preStopDelaySeconds,omitempty"`
+}
+
+// DefaultAgentPreStopDelaySeconds is the preStop delay used when spec.lifecycle.preStopDelaySeconds is unset
+const DefaultAgentPreStopDelaySeconds int32 = 10
+
+// AgentTelemetrySpec defines agent telemetry enrichment
+type AgentTelemetrySpec struct {
+	// ResourceAttributes are added to the OpenTelemetry resource of the agent (e.g. team, environment).
+	// Operator-managed attributes such as the agent name and UID take precedence.
+	// +optional
+	ResourceAttributes map[string]string `json:"resourceAttributes,omitempty"`
+
+	// Protocol is the OTLP protocol the agent exports with. "http" (the default) rewrites a
+	// collector endpoint on port 4317 to the HTTP port 4318; "grpc" keeps the endpoint as-is.
+	// +kubebuilder:validation:Enum=http;grpc
+	// +kubebuilder:default=http
+	// +optional
+	Protocol string `json:"protocol,omitempty"`
+
+	// MetricsEnabled exports the metrics the agent emits (task counts, durations, tool call
+	// rates) over OTLP alongside its traces and logs
+	// +optional
+	MetricsEnabled bool `json:"metricsEnabled,omitempty"`
+}
+
+// OTLP export protocols for spec.telemetry.protocol
+const (
+	TelemetryProtocolHTTP = "http"
+	TelemetryProtocolGRPC = "grpc"
+)
+
 // AgentRateLimitSpec defines agent-level rate limiting
 type AgentRateLimitSpec struct {
 	// RequestsPerMinute limits requests per minute
@@ -303,6 +573,70 @@ type AgentRateLimitSpec struct {
 	ToolCallsPerMinute *int32 `json:"toolCallsPerMinute,omitempty"`
 }
 
+// ChaosNamespaceLabel marks a namespace as a chaos testing namespace when set to "true".
+// spec.chaos is ignored for agents in other namespaces.
+const ChaosNamespaceLabel = "langop.io/chaos-testing"
+
+// AgentChaosSpec configures failure injection for validating self-healing
+type AgentChaosSpec struct {
+	// InjectFailureRate is the fraction of agent runs the runtime deliberately fails, from 0 (none)
+	// to 1 (every run)
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	InjectFailureRate float64 `json:"injectFailureRate"`
+}
+
+// WebhookRouteRule routes webhook requests under a path prefix to a port of the agent container
+type WebhookRouteRule struct {
+	// PathPrefix is the request path prefix routed by this rule (e.g., "/webhook", "/api/v1")
+	// +kubebuilder:validation:Pattern=`^/`
+	// +kubebuilder:validation:Required
+	PathPrefix string `json:"pathPrefix"`
+
+	// Port is the agent container port serving the prefix. Defaults to the webhook server port 8080.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	Port int32 `json:"port,omitempty"`
+}
+
+// AgentSynthesisQuota defines an agent's daily synthesis budget
+type AgentSynthesisQuota struct {
+	// MaxCostPerDay limits the synthesis cost the agent may incur per day, in the operator's
+	// quota currency. It must be positive; omit it to leave the cost bounded by the namespace
+	// quota alone.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:ExclusiveMinimum=true
+	// +optional
+	MaxCostPerDay *float64 `json:"maxCostPerDay,omitempty"`
+
+	// MaxAttemptsPerDay limits the synthesis attempts the agent may make per day. Omit it to
+	// leave the attempts bounded by the namespace quota alone.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	MaxAttemptsPerDay *int32 `json:"maxAttemptsPerDay,omitempty"`
+}
+
+// MaxSynthesisMaxTokens is the largest output token limit an agent may set for synthesis
+const MaxSynthesisMaxTokens = 131072
+
+// AgentSynthesisSettings overrides the generation parameters used to synthesize an agent
+type AgentSynthesisSettings struct {
+	// Temperature controls randomness of synthesis (0.0 to 2.0, at most 1.0 for anthropic
+	// models). Defaults to the synthesis model's configured temperature, or 0.3.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=2
+	// +optional
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// MaxTokens is the maximum number of tokens of synthesized code (1 to 131072). Defaults to
+	// the synthesis model's configured maxTokens, or 8192.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=131072
+	// +optional
+	MaxTokens *int32 `json:"maxTokens,omitempty"`
+}
+
 // SafetyConfigSpec defines safety constraints
 type SafetyConfigSpec struct {
 	// MaxToolCallsPerIteration limits tool calls per reasoning loop
@@ -374,6 +708,45 @@ type WorkspaceSpec struct {
 	// +kubebuilder:default="/workspace"
 	// +optional
 	MountPath string `json:"mountPath,omitempty"`
+
+	// Seed populates the workspace before the agent first starts
+	// +optional
+	Seed *WorkspaceSeed `json:"seed,omitempty"`
+}
+
+// WorkspaceSeed populates an agent's workspace from a Git repository or a ConfigMap. An init
+// container copies the content into the workspace before the agent and its sidecar tools start.
+// A workspace is seeded once; later pod starts keep its contents. Exactly one source must be set.
+type WorkspaceSeed struct {
+	// GitRepo clones a Git repository into the workspace
+	// +optional
+	GitRepo *GitRepoSeed `json:"gitRepo,omitempty"`
+
+	// ConfigMapRef copies each key of a ConfigMap in the agent's namespace into the workspace as a file
+	// +optional
+	ConfigMapRef *corev1.LocalObjectReference `json:"configMapRef,omitempty"`
+
+	// Image is the image of the seed init container. It must provide sh and, for Git sources, git.
+	// Defaults to alpine/git.
+	// +optional
+	Image string `json:"image,omitempty"`
+}
+
+// GitRepoSeed identifies a Git repository to clone into a workspace
+type GitRepoSeed struct {
+	// URL is the repository's clone URL
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	URL string `json:"url"`
+
+	// Ref is the branch or tag to check out. Defaults to the repository's default branch.
+	// +optional
+	Ref string `json:"ref,omitempty"`
+
+	// SecretRef references a Secret in the agent's namespace with "username" and "password"
+	// keys used to authenticate HTTPS clones
+	// +optional
+	SecretRef *corev1.LocalObjectReference `json:"secretRef,omitempty"`
 }
 
 // LanguageAgentStatus defines the observed state of LanguageAgent
@@ -467,6 +840,10 @@ type LanguageAgentStatus struct {
 	// +optional
 	SynthesisInfo *SynthesisInfo `json:"synthesisInfo,omitempty"`
 
+	// SynthesisQuota reports the synthesis quota the agent has left today
+	// +optional
+	SynthesisQuota *AgentSynthesisQuotaStatus `json:"synthesisQuota,omitempty"`
+
 	// UUID is a unique identifier for this agent instance
 	// Used for webhook routing (e.g., <uuid>.domain.com)
 	// +optional
@@ -476,10 +853,26 @@ type LanguageAgentStatus struct {
 	// +optional
 	WebhookURLs []string `json:"webhookURLs,omitempty"`
 
+	// WebhookRoute records the Gateway listener the agent's HTTPRoute is attached to.
+	// Unset when webhooks are served through an Ingress.
+	// +optional
+	WebhookRoute *WebhookRouteStatus `json:"webhookRoute,omitempty"`
+
 	// RuntimeErrors contains recent runtime errors for self-healing
 	// +optional
 	RuntimeErrors []RuntimeError `json:"runtimeErrors,omitempty"`
 
+	// CountedUnhealthyPods records the unready episodes of the agent's current pods that were
+	// already counted as failures, so each episode is counted only once
+	// +optional
+	CountedUnhealthyPods []UnhealthyPodEpisode `json:"countedUnhealthyPods,omitempty"`
+
+	// DeletedModels lists the LanguageModels, as namespace/name, that were force-deleted while the
+	// agent referenced them. The agent runs without them until they are recreated or their
+	// references are removed; other missing models still fail model resolution.
+	// +optional
+	DeletedModels []string `json:"deletedModels,omitempty"`
+
 	// LastCrashLog contains the last 100 lines of logs before crash
 	// +optional
 	LastCrashLog string `json:"lastCrashLog,omitempty"`
@@ -488,19 +881,75 @@ type LanguageAgentStatus struct {
 	// +optional
 	ConsecutiveFailures int32 `json:"consecutiveFailures,omitempty"`
 
-	// FailureReason categorizes the failure type (Synthesis|Runtime|Infrastructure)
+	// FailureReason categorizes the most recent failure for dashboards and alerting
+	// +kubebuilder:validation:Enum=Runtime;Unhealthy;Synthesis;Validation;Quota;Network;OOM;ImagePull;Dependency;Unknown
 	// +optional
-	FailureReason string `json:"failureReason,omitempty"`
+	FailureReason FailureReason `json:"failureReason,omitempty"`
+
+	// FailureMessage is a human-readable description of the most recent failure
+	// +optional
+	FailureMessage string `json:"failureMessage,omitempty"`
 
 	// SelfHealingAttempts tracks how many self-healing synthesis attempts have been made
 	// +optional
 	SelfHealingAttempts int32 `json:"selfHealingAttempts,omitempty"`
 
-	// LastSuccessfulCode stores the last known working code for rollback
+	// LastSuccessfulCode stores the last known working code for rollback. Self-healed code
+	// replaces it once the agent has run the code without failures for the stability window.
 	// +optional
 	LastSuccessfulCode string `json:"lastSuccessfulCode,omitempty"`
+
+	// SelectedTools lists the LanguageTools matched by spec.toolSelector at the last reconcile
+	// +optional
+	SelectedTools []string `json:"selectedTools,omitempty"`
+
+	// DefaultPersonas lists the namespace's default LanguagePersonas composed beneath
+	// spec.personaRefs at the last reconcile
+	// +optional
+	DefaultPersonas []string `json:"defaultPersonas,omitempty"`
+
+	// UsedTools lists the granted LanguageTools the synthesized code calls, with the tool
+	// functions it calls on each
+	// +optional
+	UsedTools []UsedTool `json:"usedTools,omitempty"`
+
+	// UnusedToolGrants lists the granted LanguageTools the synthesized code never calls.
+	// Removing them from spec.toolRefs narrows the agent to the tools it needs.
+	// +optional
+	UnusedToolGrants []string `json:"unusedToolGrants,omitempty"`
+
+	// UngrantedToolCalls lists the tools the synthesized code calls that no granted LanguageTool provides
+	// +optional
+	UngrantedToolCalls []string `json:"ungrantedToolCalls,omitempty"`
 }
 
+// FailureReason is the category of an agent failure
+type FailureReason string
+
+// Failure reasons reported in LanguageAgentStatus.FailureReason
+const (
+	// FailureReasonRuntime means the agent container crashed
+	FailureReasonRuntime FailureReason = "Runtime"
+	// FailureReasonUnhealthy means the agent pod kept failing its readiness checks without crashing
+	FailureReasonUnhealthy FailureReason = "Unhealthy"
+	// FailureReasonSynthesis means code synthesis failed
+	FailureReasonSynthesis FailureReason = "Synthesis"
+	// FailureReasonValidation means the agent spec or synthesized code was rejected
+	FailureReasonValidation FailureReason = "Validation"
+	// FailureReasonQuota means a synthesis rate limit or quota was exhausted
+	FailureReasonQuota FailureReason = "Quota"
+	// FailureReasonNetwork means network isolation could not be configured
+	FailureReasonNetwork FailureReason = "Network"
+	// FailureReasonOOM means the agent container was killed for exceeding its memory limit
+	FailureReasonOOM FailureReason = "OOM"
+	// FailureReasonImagePull means the agent image could not be pulled
+	FailureReasonImagePull FailureReason = "ImagePull"
+	// FailureReasonDependency means a declared dependency is not ready
+	FailureReasonDependency FailureReason = "Dependency"
+	// FailureReasonUnknown covers failures that fit no other category, such as API server errors
+	FailureReasonUnknown FailureReason = "Unknown"
+)
+
 // SynthesisInfo contains metadata about agent code synthesis
 type SynthesisInfo struct {
 	// LastSynthesisTime is when the code was last synthesized
@@ -511,6 +960,24 @@ type SynthesisInfo struct {
 	// +optional
 	SynthesisModel string `json:"synthesisModel,omitempty"`
 
+	// SynthesisProvider is the provider API the code was synthesized with: "anthropic" for the
+	// native Anthropic Messages API, "openai" for the OpenAI-compatible chat completions API
+	// +optional
+	SynthesisProvider string `json:"synthesisProvider,omitempty"`
+
+	// SynthesisFallbackModel is the cluster fallback LanguageModel (namespace/name) used for the
+	// last synthesis because the agent's own synthesis model was unavailable
+	// +optional
+	SynthesisFallbackModel string `json:"synthesisFallbackModel,omitempty"`
+
+	// Temperature is the temperature the code was last synthesized with
+	// +optional
+	Temperature *float64 `json:"temperature,omitempty"`
+
+	// MaxTokens is the output token limit the code was last synthesized with
+	// +optional
+	MaxTokens int32 `json:"maxTokens,omitempty"`
+
 	// SynthesisDuration is how long synthesis took (in seconds)
 	// +optional
 	SynthesisDuration float64 `json:"synthesisDuration,omitempty"`
@@ -530,6 +997,69 @@ type SynthesisInfo struct {
 	// SynthesisAttempts is the number of synthesis attempts for current instructions
 	// +optional
 	SynthesisAttempts int32 `json:"synthesisAttempts,omitempty"`
+
+	// Candidates is the number of candidate implementations generated by the last synthesis
+	// +optional
+	Candidates int32 `json:"candidates,omitempty"`
+
+	// SelectionRationale explains which candidate was deployed and why
+	// +optional
+	SelectionRationale string `json:"selectionRationale,omitempty"`
+
+	// RedactedValues is the number of distinct sensitive values replaced with placeholders
+	// in the last synthesis request and restored in the generated code
+	// +optional
+	RedactedValues int32 `json:"redactedValues,omitempty"`
+
+	// PromptTrimmed lists the prompt sections trimmed from the last synthesis request so it
+	// fit the synthesis model's context window
+	// +optional
+	PromptTrimmed []string `json:"promptTrimmed,omitempty"`
+
+	// Phase is the current phase of an in-progress synthesis (validating, generating,
+	// validating-output); empty when no synthesis is running
+	// +optional
+	Phase string `json:"phase,omitempty"`
+}
+
+// AgentSynthesisQuotaStatus reports an agent's remaining daily synthesis quota: the lesser of
+// what its own spec.synthesisQuota and its namespace's quota leave
+type AgentSynthesisQuotaStatus struct {
+	// RemainingCost is the synthesis cost the agent can still incur today
+	RemainingCost float64 `json:"remainingCost"`
+
+	// RemainingAttempts is the number of synthesis attempts the agent can still make today
+	RemainingAttempts int32 `json:"remainingAttempts"`
+
+	// Currency is the currency of RemainingCost
+	// +optional
+	Currency string `json:"currency,omitempty"`
+}
+
+// WebhookRouteStatus identifies the Gateway listener serving an agent's webhooks
+type WebhookRouteStatus struct {
+	// GatewayName is the name of the Gateway the HTTPRoute is attached to
+	GatewayName string `json:"gatewayName"`
+
+	// GatewayNamespace is the namespace of the Gateway
+	GatewayNamespace string `json:"gatewayNamespace"`
+
+	// SectionName is the Gateway listener the HTTPRoute is attached to, if one is targeted
+	// +optional
+	SectionName string `json:"sectionName,omitempty"`
+
+	// Protocol is the scheme webhooks are served over
+	// +kubebuilder:validation:Enum=http;https
+	Protocol string `json:"protocol"`
+}
+
+// UnhealthyPodEpisode identifies a period during which an agent pod was running but not ready
+type UnhealthyPodEpisode struct {
+	// PodUID is the UID of the unready pod
+	PodUID string `json:"podUID"`
+
+	// NotReadySince is when the pod's Ready condition last became false
+	NotReadySince metav1.Time `json:"notReadySince"`
 }
 
 // RuntimeError captures runtime failure information for self-healing
@@ -626,6 +1156,16 @@ type ToolUsageSpec struct {
 	AverageLatency *int32 `json:"averageLatency,omitempty"`
 }
 
+// UsedTool records a LanguageTool called by the synthesized code
+type UsedTool struct {
+	// Name is the name of the LanguageTool
+	Name string `json:"name"`
+
+	// Methods are the tool functions the code calls, when the calls name them
+	// +optional
+	Methods []string `json:"methods,omitempty"`
+}
+
 // ModelUsageSpec tracks model usage
 type ModelUsageSpec struct {
 	// ModelName is the name of the model
@@ -682,6 +1222,12 @@ const (
 	WebhookRouteCreatedCondition = "WebhookRouteCreated"
 	// WebhookRouteReadyCondition indicates that the webhook route is ready and serving traffic
 	WebhookRouteReadyCondition = "WebhookRouteReady"
+	// WebhookRouteFallbackCondition indicates that an Ingress is serving the webhook because the HTTPRoute
+	// could not be created or no Gateway accepted it
+	WebhookRouteFallbackCondition = "WebhookRouteFallback"
+	// SelfHealingRolledBackCondition indicates that self-healed code failed within the rollback window
+	// and the agent was reverted to its last known good code
+	SelfHealingRolledBackCondition = "SelfHealingRolledBack"
 )
 
 // +kubebuilder:resource:scope=Namespaced,shortName=lagent
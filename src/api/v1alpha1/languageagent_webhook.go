@@ -19,13 +19,24 @@ package v1alpha1
 import (
 	"context"
 	"fmt"
+	"net"
 	"os"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
@@ -35,6 +46,41 @@ import (
 
 var _ webhook.Defaulter = &LanguageAgent{}
 var _ webhook.Validator = &LanguageAgent{}
+var _ admission.CustomValidator = &LanguageAgentCustomValidator{}
+
+// LanguageAgentCustomValidator validates LanguageAgents, looking up the agents and models they
+// reference through Reader. When Reader is nil, only checks that don't need other objects are performed.
+// +kubebuilder:object:generate=false
+type LanguageAgentCustomValidator struct {
+	Reader client.Reader
+}
+
+// ValidateCreate implements admission.CustomValidator
+func (v *LanguageAgentCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	agent, ok := obj.(*LanguageAgent)
+	if !ok {
+		return nil, fmt.Errorf("expected a LanguageAgent but got %T", obj)
+	}
+	return agent.validateCreate(ctx, v.Reader)
+}
+
+// ValidateUpdate implements admission.CustomValidator
+func (v *LanguageAgentCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	agent, ok := newObj.(*LanguageAgent)
+	if !ok {
+		return nil, fmt.Errorf("expected a LanguageAgent but got %T", newObj)
+	}
+	return agent.validateUpdate(ctx, v.Reader, oldObj)
+}
+
+// ValidateDelete implements admission.CustomValidator
+func (v *LanguageAgentCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	agent, ok := obj.(*LanguageAgent)
+	if !ok {
+		return nil, fmt.Errorf("expected a LanguageAgent but got %T", obj)
+	}
+	return agent.ValidateDelete()
+}
 
 // Default implements webhook.Defaulter
 func (a *LanguageAgent) Default() {
@@ -49,9 +95,13 @@ func (a *LanguageAgent) Default() {
 	}
 }
 
-// ValidateCreate implements webhook.Validator
+// ValidateCreate implements webhook.Validator without the checks that need other objects
 func (a *LanguageAgent) ValidateCreate() (admission.Warnings, error) {
-	ctx := context.Background()
+	return a.validateCreate(context.Background(), nil)
+}
+
+// validateCreate validates a new agent, reading the objects it references through reader when set
+func (a *LanguageAgent) validateCreate(ctx context.Context, reader client.Reader) (admission.Warnings, error) {
 	warnings := admission.Warnings{}
 
 	// Basic validation
@@ -59,17 +109,63 @@ func (a *LanguageAgent) ValidateCreate() (admission.Warnings, error) {
 		return warnings, err
 	}
 
+	// Reject a schedule on an agent that won't run as a CronJob
+	if err := a.validateScheduleMode(); err != nil {
+		return warnings, err
+	}
+
+	if err := validateModelRoles(a.Spec.ModelRefs); err != nil {
+		return warnings, fmt.Errorf("spec.modelRefs: %w", err)
+	}
+
+	if err := validateReservedAgentEnv(a.Spec.Env, nil); err != nil {
+		return warnings, fmt.Errorf("spec.env: %w", err)
+	}
+
+	if err := a.validatePinnedCodeVersion(); err != nil {
+		return warnings, err
+	}
+
 	// Perform cost validation to prevent expensive agents during controller lag
 	if err := a.validateCost(ctx); err != nil {
 		return warnings, err
 	}
 
+	// Reject invalid, cyclic, or cross-namespace dependencies
+	if err := a.validateDependencies(ctx, reader); err != nil {
+		return warnings, fmt.Errorf("spec.dependsOn: %w", err)
+	}
+	if err := a.validateDependencyNamespaces(); err != nil {
+		return warnings, fmt.Errorf("spec.dependsOn: %w", err)
+	}
+
+	if err := a.validateSynthesisTemperature(ctx, reader); err != nil {
+		return warnings, fmt.Errorf("spec.synthesis.temperature: %w", err)
+	}
+
+	// Reject models that can't provide the capabilities the agent requires
+	capabilityWarnings, err := a.validateModelCapabilities(ctx, reader)
+	if err != nil {
+		return warnings, fmt.Errorf("spec.requiredModelCapabilities: %w", err)
+	}
+	warnings = append(warnings, capabilityWarnings...)
+
+	// Unknown feature gates are ignored rather than rejected
+	for _, gate := range UnknownFeatureGates(a.Spec.FeatureGates) {
+		warnings = append(warnings, fmt.Sprintf("spec.featureGates: unknown feature gate %q will be ignored", gate))
+	}
+	warnings = append(warnings, a.pinnedLearningWarnings()...)
+
 	return warnings, nil
 }
 
-// ValidateUpdate implements webhook.Validator
+// ValidateUpdate implements webhook.Validator without the checks that need other objects
 func (a *LanguageAgent) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
-	ctx := context.Background()
+	return a.validateUpdate(context.Background(), nil, old)
+}
+
+// validateUpdate validates an updated agent, reading the objects it references through reader when set
+func (a *LanguageAgent) validateUpdate(ctx context.Context, reader client.Reader, old runtime.Object) (admission.Warnings, error) {
 	warnings := admission.Warnings{}
 
 	// Basic validation
@@ -77,14 +173,116 @@ func (a *LanguageAgent) ValidateUpdate(old runtime.Object) (admission.Warnings,
 		return warnings, err
 	}
 
+	// Reject a schedule on an agent that won't run as a CronJob. Agents admitted before this
+	// check keep validating until their mode or schedule changes.
+	if oldAgent, ok := old.(*LanguageAgent); !ok ||
+		oldAgent.Spec.ExecutionMode != a.Spec.ExecutionMode || oldAgent.Spec.Schedule != a.Spec.Schedule {
+		if err := a.validateScheduleMode(); err != nil {
+			return warnings, err
+		}
+	}
+
+	// Reject conflicting model roles. Agents admitted before this check keep validating until their
+	// primary models change, and an agent being deleted must not have its finalizers blocked.
+	if oldAgent, ok := old.(*LanguageAgent); a.DeletionTimestamp == nil &&
+		(!ok || !slices.Equal(primaryModels(oldAgent.Spec.ModelRefs), primaryModels(a.Spec.ModelRefs))) {
+		if err := validateModelRoles(a.Spec.ModelRefs); err != nil {
+			return warnings, fmt.Errorf("spec.modelRefs: %w", err)
+		}
+	}
+
+	// Reserved names an agent already set are ignored by the controller rather than rejected, so
+	// agents admitted before the names were reserved can still be updated and deleted
+	var previousEnv []corev1.EnvVar
+	if oldAgent, ok := old.(*LanguageAgent); ok {
+		previousEnv = oldAgent.Spec.Env
+	}
+	if err := validateReservedAgentEnv(a.Spec.Env, previousEnv); err != nil {
+		return warnings, fmt.Errorf("spec.env: %w", err)
+	}
+
+	// Pins set before this check keep validating until they change
+	if oldAgent, ok := old.(*LanguageAgent); !ok ||
+		oldAgent.Annotations[PinnedCodeVersionAnnotation] != a.Annotations[PinnedCodeVersionAnnotation] {
+		if err := a.validatePinnedCodeVersion(); err != nil {
+			return warnings, err
+		}
+	}
+
 	// Perform cost validation to prevent expensive agents during controller lag
 	if err := a.validateCost(ctx); err != nil {
 		return warnings, err
 	}
 
+	// Reject invalid or cyclic dependencies
+	if err := a.validateDependencies(ctx, reader); err != nil {
+		return warnings, fmt.Errorf("spec.dependsOn: %w", err)
+	}
+
+	// Agents admitted with cross-namespace dependencies keep validating until their dependencies change
+	if oldAgent, ok := old.(*LanguageAgent); !ok || !slices.Equal(oldAgent.Spec.DependsOn, a.Spec.DependsOn) {
+		if err := a.validateDependencyNamespaces(); err != nil {
+			return warnings, fmt.Errorf("spec.dependsOn: %w", err)
+		}
+	}
+
+	// Reject models that can't provide the capabilities the agent requires, or the synthesis
+	// temperature. An agent being deleted is only updated to remove its finalizers, which must
+	// not be blocked.
+	if a.DeletionTimestamp == nil {
+		if err := a.validateSynthesisTemperature(ctx, reader); err != nil {
+			return warnings, fmt.Errorf("spec.synthesis.temperature: %w", err)
+		}
+		capabilityWarnings, err := a.validateModelCapabilities(ctx, reader)
+		if err != nil {
+			return warnings, fmt.Errorf("spec.requiredModelCapabilities: %w", err)
+		}
+		warnings = append(warnings, capabilityWarnings...)
+	}
+
+	// Unknown feature gates are ignored rather than rejected
+	for _, gate := range UnknownFeatureGates(a.Spec.FeatureGates) {
+		warnings = append(warnings, fmt.Sprintf("spec.featureGates: unknown feature gate %q will be ignored", gate))
+	}
+	warnings = append(warnings, a.pinnedLearningWarnings()...)
+
 	return warnings, nil
 }
 
+// ParsePinnedCodeVersion parses the value of the pinned code version annotation, a positive
+// version number with an optional "v" prefix
+func ParsePinnedCodeVersion(value string) (int, error) {
+	version, err := strconv.Atoi(strings.TrimPrefix(value, "v"))
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("must be a positive code version such as \"3\" or \"v3\", got %q", value)
+	}
+	return version, nil
+}
+
+// validatePinnedCodeVersion rejects a pinned code version that doesn't name a versioned ConfigMap
+func (a *LanguageAgent) validatePinnedCodeVersion() error {
+	value, ok := a.Annotations[PinnedCodeVersionAnnotation]
+	if !ok {
+		return nil
+	}
+	if _, err := ParsePinnedCodeVersion(value); err != nil {
+		return fmt.Errorf("metadata.annotations[%s]: %w", PinnedCodeVersionAnnotation, err)
+	}
+	return nil
+}
+
+// pinnedLearningWarnings warns when a pinned agent still has learning enabled, since learning
+// skips pinned agents and never promotes their learned code
+func (a *LanguageAgent) pinnedLearningWarnings() admission.Warnings {
+	version := a.Annotations[PinnedCodeVersionAnnotation]
+	if version == "" || a.Annotations[LearningDisabledAnnotation] == "true" {
+		return nil
+	}
+	return admission.Warnings{fmt.Sprintf(
+		"metadata.annotations: agent is pinned to code version %q, so learning will skip it; remove %s or set %s: \"true\"",
+		version, PinnedCodeVersionAnnotation, LearningDisabledAnnotation)}
+}
+
 // ValidateDelete implements webhook.Validator
 func (a *LanguageAgent) ValidateDelete() (admission.Warnings, error) {
 	// No validation needed on delete
@@ -93,9 +291,54 @@ func (a *LanguageAgent) ValidateDelete() (admission.Warnings, error) {
 
 // validateSpec performs basic spec validation
 func (a *LanguageAgent) validateSpec() error {
-	// Instructions are required
-	if a.Spec.Instructions == "" {
-		return fmt.Errorf("spec.instructions is required")
+	// Instructions are required, though spec.goal can stand in for them when the
+	// GoalAsInstructions feature gate is enabled
+	if a.Spec.Instructions == "" && a.Spec.Goal == "" {
+		return fmt.Errorf("spec.instructions or spec.goal is required")
+	}
+
+	// Without an image the agent runs its cluster's default agent image
+	if a.Spec.Image == "" && a.Spec.ClusterRef == "" {
+		return fmt.Errorf("spec.image is required when spec.clusterRef is not set")
+	}
+
+	if err := validateImagePullPolicy(a.Spec.ImagePullPolicy); err != nil {
+		return fmt.Errorf("spec.imagePullPolicy: %w", err)
+	}
+
+	if err := validateLogLevel(a.Spec.LogLevel); err != nil {
+		return fmt.Errorf("spec.logLevel: %w", err)
+	}
+
+	if a.Spec.ToolSelector != nil {
+		if _, err := metav1.LabelSelectorAsSelector(a.Spec.ToolSelector); err != nil {
+			return fmt.Errorf("spec.toolSelector: %w", err)
+		}
+	}
+
+	if err := validateAgentEnv(a.Spec.Env); err != nil {
+		return fmt.Errorf("spec.env: %w", err)
+	}
+
+	// spec.env may set the log level only while spec.logLevel doesn't
+	if a.Spec.LogLevel != "" {
+		for _, e := range a.Spec.Env {
+			if IsLogLevelAgentEnvVar(e.Name) {
+				return fmt.Errorf("spec.env: %q conflicts with spec.logLevel; set the level in one place", e.Name)
+			}
+		}
+	}
+
+	// A budget that keeps every replica available would block node drains indefinitely
+	if a.Spec.DisruptionBudget != nil && a.Spec.Replicas != nil && *a.Spec.Replicas > 1 &&
+		*a.Spec.DisruptionBudget >= *a.Spec.Replicas {
+		return fmt.Errorf("spec.disruptionBudget must be lower than spec.replicas (%d), got %d", *a.Spec.Replicas, *a.Spec.DisruptionBudget)
+	}
+
+	if a.Spec.DeploymentStrategy != nil {
+		if err := validateDeploymentStrategy(a.Spec.DeploymentStrategy); err != nil {
+			return fmt.Errorf("spec.deploymentStrategy: %w", err)
+		}
 	}
 
 	// Validate safety config if present
@@ -124,12 +367,68 @@ func (a *LanguageAgent) validateSpec() error {
 			return fmt.Errorf("spec.workspace.size: %w", err)
 		}
 	}
+	if a.Spec.Workspace != nil && a.Spec.Workspace.Seed != nil {
+		if !a.Spec.Workspace.Enabled {
+			return fmt.Errorf("spec.workspace.seed requires spec.workspace.enabled")
+		}
+		if err := validateWorkspaceSeed(a.Spec.Workspace.Seed); err != nil {
+			return fmt.Errorf("spec.workspace.seed: %w", err)
+		}
+	}
 
 	// Validate schedule configuration for scheduled agents
 	if err := a.validateSchedule(); err != nil {
 		return fmt.Errorf("spec.schedule: %w", err)
 	}
 
+	// Validate startup probe shape if present
+	if a.Spec.StartupProbe != nil {
+		if err := validateStartupProbe(a.Spec.StartupProbe); err != nil {
+			return fmt.Errorf("spec.startupProbe: %w", err)
+		}
+	}
+
+	if a.Spec.Lifecycle != nil && a.Spec.Lifecycle.PreStopDelaySeconds != nil && *a.Spec.Lifecycle.PreStopDelaySeconds < 0 {
+		return fmt.Errorf("spec.lifecycle.preStopDelaySeconds must be non-negative")
+	}
+
+	if a.Spec.ModelRequestTimeout != "" {
+		if err := validatePositiveDuration(a.Spec.ModelRequestTimeout); err != nil {
+			return fmt.Errorf("spec.modelRequestTimeout: %w", err)
+		}
+	}
+
+	if a.Spec.Learning != nil {
+		if err := validateLearningSpec(a.Spec.Learning); err != nil {
+			return fmt.Errorf("spec.learning.%w", err)
+		}
+	}
+
+	if a.Spec.Synthesis != nil {
+		if err := validateSynthesisSettings(a.Spec.Synthesis); err != nil {
+			return fmt.Errorf("spec.synthesis.%w", err)
+		}
+	}
+
+	// Validate telemetry resource attributes if present
+	if a.Spec.Telemetry != nil {
+		if err := validateResourceAttributes(a.Spec.Telemetry.ResourceAttributes); err != nil {
+			return fmt.Errorf("spec.telemetry.resourceAttributes: %w", err)
+		}
+	}
+
+	if err := validateWebhookRoutes(a.Spec.WebhookRoutes); err != nil {
+		return fmt.Errorf("spec.webhookRoutes: %w", err)
+	}
+
+	// Malformed egress rules would otherwise only fail when the NetworkPolicy is created. Rules of
+	// an agent being deleted no longer matter and must not block removing its finalizers.
+	if a.DeletionTimestamp == nil {
+		if err := validateEgressRules(a.Spec.Egress); err != nil {
+			return fmt.Errorf("spec.egress%w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -159,6 +458,25 @@ func (a *LanguageAgent) validateWorkspaceSize(size string) error {
 	return nil
 }
 
+// validateWorkspaceSeed requires exactly one seed source
+func validateWorkspaceSeed(seed *WorkspaceSeed) error {
+	switch {
+	case seed.GitRepo != nil && seed.ConfigMapRef != nil:
+		return fmt.Errorf("only one of gitRepo and configMapRef may be set")
+	case seed.GitRepo != nil:
+		if seed.GitRepo.URL == "" {
+			return fmt.Errorf("gitRepo.url is required")
+		}
+	case seed.ConfigMapRef != nil:
+		if seed.ConfigMapRef.Name == "" {
+			return fmt.Errorf("configMapRef.name is required")
+		}
+	default:
+		return fmt.Errorf("one of gitRepo or configMapRef is required")
+	}
+	return nil
+}
+
 // validateSchedule validates the cron schedule format and constraints
 func (a *LanguageAgent) validateSchedule() error {
 	// If execution mode is scheduled, schedule is required
@@ -181,6 +499,474 @@ func (a *LanguageAgent) validateSchedule() error {
 	return nil
 }
 
+// validateAgentEnv rejects invalid environment variable names
+func validateAgentEnv(env []corev1.EnvVar) error {
+	for _, e := range env {
+		if errs := validation.IsEnvVarName(e.Name); len(errs) > 0 {
+			return fmt.Errorf("invalid name %q: %s", e.Name, strings.Join(errs, "; "))
+		}
+	}
+	return nil
+}
+
+// validateReservedAgentEnv rejects names the operator manages, which would otherwise shadow the
+// operator's values or be dropped. Names already set in previous, the env of an agent admitted
+// before they were reserved, are left for the controller to ignore and report.
+func validateReservedAgentEnv(env, previous []corev1.EnvVar) error {
+	for _, e := range env {
+		if !IsReservedAgentEnvVar(e.Name) {
+			continue
+		}
+		if !slices.ContainsFunc(previous, func(p corev1.EnvVar) bool { return p.Name == e.Name }) {
+			return fmt.Errorf("%q is reserved by the operator", e.Name)
+		}
+	}
+	return nil
+}
+
+// validateScheduleMode rejects a schedule on agents whose execution mode is not scheduled, which
+// would otherwise be silently ignored. Agents forced to run as a CronJob keep their schedule
+// whatever the mode. Scheduled agents without a schedule are rejected by validateSchedule.
+func (a *LanguageAgent) validateScheduleMode() error {
+	if a.Annotations[ForceWorkloadAnnotation] == "cronjob" {
+		return nil
+	}
+	if a.Spec.Schedule != "" && a.Spec.ExecutionMode != "" && a.Spec.ExecutionMode != "scheduled" {
+		return fmt.Errorf("spec.schedule: only valid when spec.executionMode is 'scheduled', got executionMode %q", a.Spec.ExecutionMode)
+	}
+	return nil
+}
+
+// validateModelRoles rejects model references that declare more than one primary model, which
+// would leave the agent without a well-defined model to use first
+func validateModelRoles(refs []ModelReference) error {
+	if primaries := primaryModels(refs); len(primaries) > 1 {
+		return fmt.Errorf("at most one model may have role %q, found %s", ModelRolePrimary, strings.Join(primaries, ", "))
+	}
+	return nil
+}
+
+// primaryModels returns the names of the models referenced with the primary role, in order
+func primaryModels(refs []ModelReference) []string {
+	var primaries []string
+	for _, ref := range refs {
+		if ref.Role == ModelRolePrimary {
+			primaries = append(primaries, ref.Name)
+		}
+	}
+	return primaries
+}
+
+// validateDeploymentStrategy validates the rolling update parameters, applying the agent
+// defaults to unset values the way the controller does
+func validateDeploymentStrategy(strategy *UpdateStrategySpec) error {
+	if strategy.RollingUpdate == nil {
+		return nil
+	}
+	if strategy.Type == "Recreate" {
+		return fmt.Errorf("rollingUpdate may not be set when type is Recreate")
+	}
+
+	maxSurge, maxUnavailable := DefaultAgentMaxSurge, DefaultAgentMaxUnavailable
+	if strategy.RollingUpdate.MaxSurge != nil {
+		maxSurge = *strategy.RollingUpdate.MaxSurge
+	}
+	if strategy.RollingUpdate.MaxUnavailable != nil {
+		maxUnavailable = *strategy.RollingUpdate.MaxUnavailable
+	}
+	if maxSurge < 0 {
+		return fmt.Errorf("rollingUpdate.maxSurge must be non-negative, got %d", maxSurge)
+	}
+	if maxUnavailable < 0 {
+		return fmt.Errorf("rollingUpdate.maxUnavailable must be non-negative, got %d", maxUnavailable)
+	}
+	if maxSurge == 0 && maxUnavailable == 0 {
+		return fmt.Errorf("rollingUpdate.maxSurge and rollingUpdate.maxUnavailable cannot both be 0")
+	}
+	return nil
+}
+
+// validateStartupProbe validates the startup probe has a single handler and sane thresholds
+func validateStartupProbe(probe *corev1.Probe) error {
+	handlers := 0
+	if probe.Exec != nil {
+		handlers++
+	}
+	if probe.HTTPGet != nil {
+		handlers++
+	}
+	if probe.TCPSocket != nil {
+		handlers++
+	}
+	if probe.GRPC != nil {
+		handlers++
+	}
+	if handlers != 1 {
+		return fmt.Errorf("exactly one of exec, httpGet, tcpSocket, or grpc must be specified, got %d", handlers)
+	}
+
+	if probe.InitialDelaySeconds < 0 {
+		return fmt.Errorf("initialDelaySeconds must be non-negative")
+	}
+	if probe.PeriodSeconds < 0 {
+		return fmt.Errorf("periodSeconds must be non-negative")
+	}
+	if probe.TimeoutSeconds < 0 {
+		return fmt.Errorf("timeoutSeconds must be non-negative")
+	}
+	if probe.FailureThreshold < 0 {
+		return fmt.Errorf("failureThreshold must be non-negative")
+	}
+
+	// Kubernetes requires startup probes to succeed exactly once
+	if probe.SuccessThreshold != 0 && probe.SuccessThreshold != 1 {
+		return fmt.Errorf("successThreshold must be 1, got %d", probe.SuccessThreshold)
+	}
+
+	return nil
+}
+
+// validatePositiveDuration requires a positive duration
+func validatePositiveDuration(duration string) error {
+	d, err := time.ParseDuration(duration)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q: %w", duration, err)
+	}
+	if d <= 0 {
+		return fmt.Errorf("must be positive, got %s", duration)
+	}
+	return nil
+}
+
+// validateLearningSpec validates the learning trace window and health gate overrides
+func validateLearningSpec(learning *LearningSpec) error {
+	if learning.TraceWindow != "" {
+		if err := validatePositiveDuration(learning.TraceWindow); err != nil {
+			return fmt.Errorf("traceWindow: %w", err)
+		}
+	}
+	if learning.MinTraceAge != "" {
+		if _, err := time.ParseDuration(learning.MinTraceAge); err != nil {
+			return fmt.Errorf("minTraceAge: invalid duration %q: %w", learning.MinTraceAge, err)
+		}
+	}
+	if learning.HealthGateWindow != "" {
+		if _, err := time.ParseDuration(learning.HealthGateWindow); err != nil {
+			return fmt.Errorf("healthGateWindow: invalid duration %q: %w", learning.HealthGateWindow, err)
+		}
+	}
+	return nil
+}
+
+// validateSynthesisSettings checks the synthesis generation parameter overrides are in the
+// ranges providers accept. The temperature is checked against the synthesis model's provider by
+// validateSynthesisTemperature.
+func validateSynthesisSettings(settings *AgentSynthesisSettings) error {
+	if settings.Temperature != nil && (*settings.Temperature < 0 || *settings.Temperature > 2) {
+		return fmt.Errorf("temperature must be between 0 and 2, got %g", *settings.Temperature)
+	}
+	if settings.MaxTokens != nil && (*settings.MaxTokens < 1 || *settings.MaxTokens > MaxSynthesisMaxTokens) {
+		return fmt.Errorf("maxTokens must be between 1 and %d, got %d", MaxSynthesisMaxTokens, *settings.MaxTokens)
+	}
+	return nil
+}
+
+// validateWebhookRoutes rejects route rules whose path prefixes overlap, since the Gateway or
+// Ingress would silently pick one of them, and rules on the agent Service's own port 80
+func validateWebhookRoutes(routes []WebhookRouteRule) error {
+	for i, route := range routes {
+		if !strings.HasPrefix(route.PathPrefix, "/") {
+			return fmt.Errorf("pathPrefix %q must start with /", route.PathPrefix)
+		}
+		if route.Port == 80 {
+			return fmt.Errorf("pathPrefix %q: port 80 is reserved for the agent Service, route to the container port instead", route.PathPrefix)
+		}
+		for _, other := range routes[:i] {
+			if pathPrefixesOverlap(route.PathPrefix, other.PathPrefix) {
+				return fmt.Errorf("pathPrefix %q overlaps %q", route.PathPrefix, other.PathPrefix)
+			}
+		}
+	}
+	return nil
+}
+
+// validateEgressRules checks the syntax of egress rules: CIDRs and selectors parse, hostnames
+// are valid DNS names (optionally with a leading "*." wildcard), and ports and protocols are
+// ones a NetworkPolicy accepts. Errors are prefixed with the offending rule's index and field.
+func validateEgressRules(rules []NetworkRule) error {
+	for i, rule := range rules {
+		if to := rule.To; to != nil {
+			if to.CIDR != "" {
+				if _, _, err := net.ParseCIDR(to.CIDR); err != nil {
+					return fmt.Errorf("[%d].to.cidr: invalid CIDR %q", i, to.CIDR)
+				}
+			}
+			for j, host := range to.DNS {
+				if err := validateEgressHostname(host); err != nil {
+					return fmt.Errorf("[%d].to.dns[%d]: %w", i, j, err)
+				}
+			}
+			if to.Service != nil && to.Service.Name == "" {
+				return fmt.Errorf("[%d].to.service.name is required", i)
+			}
+			if to.NamespaceSelector != nil {
+				if _, err := metav1.LabelSelectorAsSelector(to.NamespaceSelector); err != nil {
+					return fmt.Errorf("[%d].to.namespaceSelector: %w", i, err)
+				}
+			}
+			if to.PodSelector != nil {
+				if _, err := metav1.LabelSelectorAsSelector(to.PodSelector); err != nil {
+					return fmt.Errorf("[%d].to.podSelector: %w", i, err)
+				}
+			}
+		}
+
+		for j, port := range rule.Ports {
+			if port.Port < 1 || port.Port > 65535 {
+				return fmt.Errorf("[%d].ports[%d].port must be between 1 and 65535, got %d", i, j, port.Port)
+			}
+			switch corev1.Protocol(port.Protocol) {
+			case "", corev1.ProtocolTCP, corev1.ProtocolUDP, corev1.ProtocolSCTP:
+			default:
+				return fmt.Errorf("[%d].ports[%d].protocol: unsupported protocol %q, must be one of TCP, UDP, SCTP", i, j, port.Protocol)
+			}
+		}
+	}
+	return nil
+}
+
+// validateEgressHostname accepts a DNS name, matched case-insensitively, or a wildcard
+// "*.<domain>" matching its subdomains
+func validateEgressHostname(host string) error {
+	name := strings.ToLower(host)
+	var errs []string
+	if strings.HasPrefix(name, "*") {
+		errs = validation.IsWildcardDNS1123Subdomain(name)
+	} else {
+		errs = validation.IsDNS1123Subdomain(name)
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid hostname %q: %s", host, strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// pathPrefixesOverlap reports whether a request path can match both prefixes. Prefixes match
+// whole path segments, so "/api" overlaps "/api/v1" but not "/apis".
+func pathPrefixesOverlap(a, b string) bool {
+	a, b = strings.TrimSuffix(a, "/")+"/", strings.TrimSuffix(b, "/")+"/"
+	return strings.HasPrefix(a, b) || strings.HasPrefix(b, a)
+}
+
+// validateImagePullPolicy accepts an empty policy or one of the Kubernetes pull policies
+func validateImagePullPolicy(policy corev1.PullPolicy) error {
+	switch policy {
+	case "", corev1.PullAlways, corev1.PullIfNotPresent, corev1.PullNever:
+		return nil
+	default:
+		return fmt.Errorf("unsupported pull policy %q, must be one of Always, IfNotPresent, Never", policy)
+	}
+}
+
+// validateLogLevel accepts an empty log level or one of the agent runtime log levels
+func validateLogLevel(level string) error {
+	switch level {
+	case "", LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError:
+		return nil
+	default:
+		return fmt.Errorf("unsupported log level %q, must be one of debug, info, warn, error", level)
+	}
+}
+
+// validateResourceAttributes ensures attributes can be rendered into OTEL_RESOURCE_ATTRIBUTES,
+// which uses ',' to separate pairs and '=' to separate keys from values. Values may hold any
+// character since the controller percent-encodes them.
+func validateResourceAttributes(attrs map[string]string) error {
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if key == "" {
+			return fmt.Errorf("attribute key cannot be empty")
+		}
+		if strings.ContainsAny(key, ",= \t\n") {
+			return fmt.Errorf("attribute key %q must not contain ',', '=', or whitespace", key)
+		}
+	}
+
+	return nil
+}
+
+// validateDependencies rejects self, duplicate, and cyclic agent dependencies
+func (a *LanguageAgent) validateDependencies(ctx context.Context, reader client.Reader) error {
+	self := agentReferenceKey(AgentReference{Name: a.Name}, a.Namespace)
+	seen := make(map[string]bool, len(a.Spec.DependsOn))
+	for _, dep := range a.Spec.DependsOn {
+		if dep.Name == "" {
+			return fmt.Errorf("name is required")
+		}
+		key := agentReferenceKey(dep, a.Namespace)
+		if key == self {
+			return fmt.Errorf("agent cannot depend on itself")
+		}
+		if seen[key] {
+			return fmt.Errorf("duplicate dependency %s", key)
+		}
+		seen[key] = true
+	}
+
+	if reader == nil || len(a.Spec.DependsOn) == 0 {
+		return nil
+	}
+
+	if cycle, err := a.findDependencyCycle(ctx, reader); err != nil {
+		return fmt.Errorf("failed to check for dependency cycles: %w", err)
+	} else if len(cycle) > 0 {
+		return fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+
+	return nil
+}
+
+// validateModelCapabilities checks that every referenced model supports the agent's required
+// capabilities. Models that don't exist yet or whose capabilities are unknown only produce warnings.
+func (a *LanguageAgent) validateModelCapabilities(ctx context.Context, reader client.Reader) (admission.Warnings, error) {
+	for _, capability := range a.Spec.RequiredModelCapabilities {
+		if !IsKnownModelCapability(capability) {
+			return nil, fmt.Errorf("unknown capability %q, expected one of %s, %s, %s",
+				capability, ModelCapabilityToolCalling, ModelCapabilityVision, ModelCapabilityJSONMode)
+		}
+	}
+	if reader == nil || len(a.Spec.RequiredModelCapabilities) == 0 {
+		return nil, nil
+	}
+
+	var warnings admission.Warnings
+	for _, ref := range a.Spec.ModelRefs {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = a.Namespace
+		}
+		model := &LanguageModel{}
+		if err := reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, model); err != nil {
+			if apierrors.IsNotFound(err) {
+				warnings = append(warnings, fmt.Sprintf("spec.requiredModelCapabilities: LanguageModel %s/%s not found, capabilities will not be checked", namespace, ref.Name))
+				continue
+			}
+			return nil, fmt.Errorf("failed to get LanguageModel %s/%s: %w", namespace, ref.Name, err)
+		}
+
+		capabilities, known := ModelCapabilities(model)
+		if !known {
+			warnings = append(warnings, fmt.Sprintf("spec.requiredModelCapabilities: capabilities of model %q (LanguageModel %s/%s) are unknown; set spec.capabilities on the LanguageModel to check them",
+				model.Spec.ModelName, namespace, ref.Name))
+			continue
+		}
+		if missing := MissingModelCapabilities(capabilities, a.Spec.RequiredModelCapabilities); len(missing) > 0 {
+			return warnings, fmt.Errorf("model %q (LanguageModel %s/%s) does not support %s",
+				model.Spec.ModelName, namespace, ref.Name, strings.Join(missing, ", "))
+		}
+	}
+	return warnings, nil
+}
+
+// validateSynthesisTemperature rejects a spec.synthesis.temperature above what the provider of the
+// agent's synthesis model, its primary or else its first model, accepts
+func (a *LanguageAgent) validateSynthesisTemperature(ctx context.Context, reader client.Reader) error {
+	if reader == nil || a.Spec.Synthesis == nil || a.Spec.Synthesis.Temperature == nil || len(a.Spec.ModelRefs) == 0 {
+		return nil
+	}
+
+	ref := a.Spec.ModelRefs[0]
+	for _, candidate := range a.Spec.ModelRefs {
+		if candidate.Role == ModelRolePrimary || candidate.Role == "" {
+			ref = candidate
+			break
+		}
+	}
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = a.Namespace
+	}
+	model := &LanguageModel{}
+	if err := reader.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, model); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get LanguageModel %s/%s: %w", namespace, ref.Name, err)
+	}
+
+	if limit := MaxModelTemperature(model.Spec.Provider); *a.Spec.Synthesis.Temperature > limit {
+		return fmt.Errorf("must be at most %g for %s model %q (LanguageModel %s/%s), got %g",
+			limit, model.Spec.Provider, model.Spec.ModelName, namespace, ref.Name, *a.Spec.Synthesis.Temperature)
+	}
+	return nil
+}
+
+// validateDependencyNamespaces rejects dependencies on agents in other namespaces. Waiting on an
+// agent reveals whether it exists and is Ready, which users of one namespace may not be allowed to see.
+func (a *LanguageAgent) validateDependencyNamespaces() error {
+	for _, dep := range a.Spec.DependsOn {
+		if dep.Namespace != "" && dep.Namespace != a.Namespace {
+			return fmt.Errorf("dependency %s/%s must be in the agent's namespace %q", dep.Namespace, dep.Name, a.Namespace)
+		}
+	}
+	return nil
+}
+
+// findDependencyCycle walks the dependency graph from this agent and returns the path
+// of the first cycle that leads back to it. Missing agents are treated as having no dependencies.
+func (a *LanguageAgent) findDependencyCycle(ctx context.Context, reader client.Reader) ([]string, error) {
+	self := agentReferenceKey(AgentReference{Name: a.Name}, a.Namespace)
+	visited := map[string]bool{self: true}
+
+	var walk func(deps []AgentReference, namespace string, path []string) ([]string, error)
+	walk = func(deps []AgentReference, namespace string, path []string) ([]string, error) {
+		for _, dep := range deps {
+			key := agentReferenceKey(dep, namespace)
+			if key == self {
+				return append(path, key), nil
+			}
+			if visited[key] {
+				continue
+			}
+			visited[key] = true
+
+			depNamespace := dep.Namespace
+			if depNamespace == "" {
+				depNamespace = namespace
+			}
+			other := &LanguageAgent{}
+			if err := reader.Get(ctx, types.NamespacedName{Name: dep.Name, Namespace: depNamespace}, other); err != nil {
+				if apierrors.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+
+			cycle, err := walk(other.Spec.DependsOn, depNamespace, append(path, key))
+			if err != nil || len(cycle) > 0 {
+				return cycle, err
+			}
+		}
+		return nil, nil
+	}
+
+	return walk(a.Spec.DependsOn, a.Namespace, []string{self})
+}
+
+// agentReferenceKey returns the namespace/name key of an agent reference
+func agentReferenceKey(ref AgentReference, defaultNamespace string) string {
+	namespace := ref.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	return namespace + "/" + ref.Name
+}
+
 // validateCost performs cost validation to prevent expensive agents during controller lag
 func (a *LanguageAgent) validateCost(ctx context.Context) error {
 	// Get cost configuration from environment (same as main.go)
@@ -253,5 +1039,6 @@ func (a *LanguageAgent) estimateSynthesisCost() float64 {
 func (a *LanguageAgent) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(a).
+		WithValidator(&LanguageAgentCustomValidator{Reader: mgr.GetClient()}).
 		Complete()
 }
@@ -17,9 +17,14 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
 	"testing"
 
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestLanguageAgentDefault(t *testing.T) {
@@ -198,6 +203,23 @@ func TestLanguageAgentValidateCreate(t *testing.T) {
 			},
 			expectErr: true,
 		},
+		{
+			name: "goal without instructions",
+			agent: &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-agent",
+					Namespace: "default",
+				},
+				Spec: LanguageAgentSpec{
+					Image: "test:latest",
+					ModelRefs: []ModelReference{
+						{Name: "test-model"},
+					},
+					Goal: "Keep the team informed about outages",
+				},
+			},
+			expectErr: false,
+		},
 		{
 			name: "negative rate limit",
 			agent: &LanguageAgent{
@@ -631,6 +653,7 @@ func TestLanguageAgentValidateSchedule(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			agent := &LanguageAgent{
 				Spec: LanguageAgentSpec{
+					Image:         "test:latest",
 					ExecutionMode: tt.executionMode,
 					Schedule:      tt.schedule,
 				},
@@ -761,6 +784,43 @@ func TestLanguageAgentValidateCreateWithSchedule(t *testing.T) {
 					Schedule:      "0 9 * * *",
 				},
 			},
+			expectErr: true,
+			errMsg:    "spec.schedule: only valid when spec.executionMode is 'scheduled', got executionMode \"autonomous\"",
+		},
+		{
+			name: "interactive agent with schedule",
+			agent: &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-agent",
+					Namespace: "default",
+				},
+				Spec: LanguageAgentSpec{
+					Image:         "test:latest",
+					ModelRefs:     []ModelReference{{Name: "test-model"}},
+					Instructions:  "test instructions",
+					ExecutionMode: "interactive",
+					Schedule:      "@daily",
+				},
+			},
+			expectErr: true,
+			errMsg:    "spec.schedule: only valid when spec.executionMode is 'scheduled'",
+		},
+		{
+			name: "autonomous agent forced to a CronJob keeps its schedule",
+			agent: &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "test-agent",
+					Namespace:   "default",
+					Annotations: map[string]string{ForceWorkloadAnnotation: "cronjob"},
+				},
+				Spec: LanguageAgentSpec{
+					Image:         "test:latest",
+					ModelRefs:     []ModelReference{{Name: "test-model"}},
+					Instructions:  "test instructions",
+					ExecutionMode: "autonomous",
+					Schedule:      "0 9 * * *",
+				},
+			},
 			expectErr: false,
 		},
 	}
@@ -782,3 +842,1238 @@ func TestLanguageAgentValidateCreateWithSchedule(t *testing.T) {
 		})
 	}
 }
+
+func TestLanguageAgentValidateStartupProbe(t *testing.T) {
+	tcpHandler := corev1.ProbeHandler{
+		TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)},
+	}
+
+	tests := []struct {
+		name      string
+		probe     *corev1.Probe
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:      "valid tcp probe",
+			probe:     &corev1.Probe{ProbeHandler: tcpHandler, PeriodSeconds: 10, FailureThreshold: 30},
+			expectErr: false,
+		},
+		{
+			name: "valid exec probe with success threshold 1",
+			probe: &corev1.Probe{
+				ProbeHandler:     corev1.ProbeHandler{Exec: &corev1.ExecAction{Command: []string{"true"}}},
+				SuccessThreshold: 1,
+			},
+			expectErr: false,
+		},
+		{
+			name:      "no handler",
+			probe:     &corev1.Probe{PeriodSeconds: 10},
+			expectErr: true,
+			errMsg:    "exactly one of exec, httpGet, tcpSocket, or grpc",
+		},
+		{
+			name: "multiple handlers",
+			probe: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					TCPSocket: &corev1.TCPSocketAction{Port: intstr.FromInt(8080)},
+					HTTPGet:   &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromInt(8080)},
+				},
+			},
+			expectErr: true,
+			errMsg:    "exactly one of exec, httpGet, tcpSocket, or grpc",
+		},
+		{
+			name:      "negative failure threshold",
+			probe:     &corev1.Probe{ProbeHandler: tcpHandler, FailureThreshold: -1},
+			expectErr: true,
+			errMsg:    "failureThreshold must be non-negative",
+		},
+		{
+			name:      "success threshold above 1",
+			probe:     &corev1.Probe{ProbeHandler: tcpHandler, SuccessThreshold: 2},
+			expectErr: true,
+			errMsg:    "successThreshold must be 1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					StartupProbe: tt.probe,
+				},
+			}
+
+			err := agent.validateSpec()
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+				return
+			}
+
+			if tt.expectErr && err != nil && tt.errMsg != "" {
+				if !contains(err.Error(), tt.errMsg) {
+					t.Errorf("validateSpec() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateResourceAttributes(t *testing.T) {
+	tests := []struct {
+		name      string
+		attrs     map[string]string
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:      "valid attributes",
+			attrs:     map[string]string{"team": "payments", "deployment.environment": "prod", "cost_center": "cc-42"},
+			expectErr: false,
+		},
+		{
+			name:      "empty key",
+			attrs:     map[string]string{"": "value"},
+			expectErr: true,
+			errMsg:    "attribute key cannot be empty",
+		},
+		{
+			name:      "key with separator",
+			attrs:     map[string]string{"team=owner": "payments"},
+			expectErr: true,
+			errMsg:    "must not contain ',', '=', or whitespace",
+		},
+		{
+			name:      "key with whitespace",
+			attrs:     map[string]string{"cost center": "cc-42"},
+			expectErr: true,
+			errMsg:    "must not contain ',', '=', or whitespace",
+		},
+		{
+			name:      "value with separators",
+			attrs:     map[string]string{"team": "payments,billing", "owner": "role=admin\n"},
+			expectErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					Telemetry:    &AgentTelemetrySpec{ResourceAttributes: tt.attrs},
+				},
+			}
+
+			err := agent.validateSpec()
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+				return
+			}
+
+			if tt.expectErr && err != nil && tt.errMsg != "" {
+				if !contains(err.Error(), tt.errMsg) {
+					t.Errorf("validateSpec() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateImagePullPolicy(t *testing.T) {
+	tests := []struct {
+		name      string
+		policy    corev1.PullPolicy
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "unset", policy: "", expectErr: false},
+		{name: "Always", policy: corev1.PullAlways, expectErr: false},
+		{name: "IfNotPresent", policy: corev1.PullIfNotPresent, expectErr: false},
+		{name: "Never", policy: corev1.PullNever, expectErr: false},
+		{name: "wrong case", policy: "always", expectErr: true, errMsg: "spec.imagePullPolicy: unsupported pull policy \"always\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:           "test:latest",
+					Instructions:    "test instructions",
+					ImagePullPolicy: tt.policy,
+				},
+			}
+			cluster := &LanguageCluster{
+				Spec: LanguageClusterSpec{ImagePullPolicy: tt.policy},
+			}
+
+			for kind, err := range map[string]error{"LanguageAgent": agent.validateSpec(), "LanguageCluster": cluster.validate()} {
+				if (err != nil) != tt.expectErr {
+					t.Errorf("%s validation error = %v, expectErr %v", kind, err, tt.expectErr)
+					continue
+				}
+				if tt.expectErr && err != nil && !contains(err.Error(), tt.errMsg) {
+					t.Errorf("%s validation error = %v, expected to contain %q", kind, err.Error(), tt.errMsg)
+				}
+			}
+		})
+	}
+}
+
+func newDependencyAgent(name string, dependsOn ...string) *LanguageAgent {
+	agent := &LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: LanguageAgentSpec{
+			Image:        "test:latest",
+			Instructions: "test instructions",
+		},
+	}
+	for _, dep := range dependsOn {
+		agent.Spec.DependsOn = append(agent.Spec.DependsOn, AgentReference{Name: dep})
+	}
+	return agent
+}
+
+func TestLanguageAgentValidateDependencies(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to build scheme: %v", err)
+	}
+
+	// Existing graph: agent-b -> agent-c -> agent-a, agent-d has no dependencies
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newDependencyAgent("agent-b", "agent-c"),
+		newDependencyAgent("agent-c", "agent-a"),
+		newDependencyAgent("agent-d"),
+	).Build()
+
+	tests := []struct {
+		name      string
+		agent     *LanguageAgent
+		expectErr bool
+		errMsg    string
+	}{
+		{
+			name:      "no dependencies",
+			agent:     newDependencyAgent("agent-a"),
+			expectErr: false,
+		},
+		{
+			name:      "acyclic dependency",
+			agent:     newDependencyAgent("agent-a", "agent-d"),
+			expectErr: false,
+		},
+		{
+			name:      "dependency on agent that does not exist yet",
+			agent:     newDependencyAgent("agent-a", "agent-z"),
+			expectErr: false,
+		},
+		{
+			name:      "self dependency",
+			agent:     newDependencyAgent("agent-a", "agent-a"),
+			expectErr: true,
+			errMsg:    "agent cannot depend on itself",
+		},
+		{
+			name:      "duplicate dependency",
+			agent:     newDependencyAgent("agent-a", "agent-d", "agent-d"),
+			expectErr: true,
+			errMsg:    "duplicate dependency default/agent-d",
+		},
+		{
+			name:      "transitive cycle",
+			agent:     newDependencyAgent("agent-a", "agent-d", "agent-b"),
+			expectErr: true,
+			errMsg:    "dependency cycle detected: default/agent-a -> default/agent-b -> default/agent-c -> default/agent-a",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.agent.validateDependencies(context.Background(), fakeClient)
+
+			if (err != nil) != tt.expectErr {
+				t.Errorf("validateDependencies() error = %v, expectErr %v", err, tt.expectErr)
+				return
+			}
+
+			if tt.expectErr && err != nil && tt.errMsg != "" {
+				if !contains(err.Error(), tt.errMsg) {
+					t.Errorf("validateDependencies() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguageAgentDependsOnOtherNamespace(t *testing.T) {
+	agent := newDependencyAgent("agent-a")
+	agent.Spec.DependsOn = []AgentReference{{Name: "agent-b", Namespace: "other"}}
+
+	_, err := agent.ValidateCreate()
+	if err == nil || !contains(err.Error(), `spec.dependsOn: dependency other/agent-b must be in the agent's namespace "default"`) {
+		t.Errorf("Expected a cross-namespace dependency to be rejected, got %v", err)
+	}
+
+	sameNamespace := newDependencyAgent("agent-a")
+	sameNamespace.Spec.DependsOn = []AgentReference{{Name: "agent-b", Namespace: "default"}}
+	if _, err := sameNamespace.ValidateCreate(); err != nil {
+		t.Errorf("Expected a dependency in the agent's namespace to be admitted, got %v", err)
+	}
+
+	// Agents admitted before the check keep validating until their dependencies change
+	updated := agent.DeepCopy()
+	updated.Spec.Instructions = "updated instructions"
+	if _, err := updated.ValidateUpdate(agent); err != nil {
+		t.Errorf("Expected unchanged dependencies to keep validating, got %v", err)
+	}
+	updated.Spec.DependsOn = append(updated.Spec.DependsOn, AgentReference{Name: "agent-c", Namespace: "other"})
+	if _, err := updated.ValidateUpdate(agent); err == nil {
+		t.Error("Expected a changed cross-namespace dependency to be rejected")
+	}
+}
+
+func TestLanguageAgentValidateCreateRejectsDependencyCycle(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to build scheme: %v", err)
+	}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newDependencyAgent("agent-b", "agent-a"),
+	).Build()
+	validator := &LanguageAgentCustomValidator{Reader: reader}
+
+	_, err := validator.ValidateCreate(context.Background(), newDependencyAgent("agent-a", "agent-b"))
+	if err == nil {
+		t.Fatal("Expected ValidateCreate to reject a dependency cycle")
+	}
+	if !contains(err.Error(), "spec.dependsOn: dependency cycle detected") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestLanguageAgentUnknownFeatureGatesWarn(t *testing.T) {
+	agent := &LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "gated-agent", Namespace: "default"},
+		Spec: LanguageAgentSpec{
+			Image:        "test:latest",
+			Instructions: "test instructions",
+			FeatureGates: map[string]bool{
+				FeatureGateUnhealthyPodDetection: false,
+				"NoSuchGate":                     true,
+			},
+		},
+	}
+
+	warnings, err := agent.ValidateCreate()
+	if err != nil {
+		t.Fatalf("Expected unknown feature gates not to fail validation, got %v", err)
+	}
+	if len(warnings) != 1 || !contains(warnings[0], `unknown feature gate "NoSuchGate"`) {
+		t.Errorf("Expected a single warning for the unknown gate, got %v", warnings)
+	}
+}
+
+func TestLanguageAgentPinnedLearningWarns(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		expectWarn  bool
+	}{
+		{
+			name:        "pinned with learning enabled",
+			annotations: map[string]string{PinnedCodeVersionAnnotation: "3"},
+			expectWarn:  true,
+		},
+		{
+			name: "pinned with learning disabled",
+			annotations: map[string]string{
+				PinnedCodeVersionAnnotation: "3",
+				LearningDisabledAnnotation:  "true",
+			},
+		},
+		{
+			name: "not pinned",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "pinned-agent", Namespace: "default", Annotations: tt.annotations},
+				Spec:       LanguageAgentSpec{Image: "test:latest", Instructions: "test instructions"},
+			}
+
+			warnings, err := agent.ValidateCreate()
+			if err != nil {
+				t.Fatalf("Expected pinning not to fail validation, got %v", err)
+			}
+			warned := len(warnings) == 1 && contains(warnings[0], "learning will skip it")
+			if warned != tt.expectWarn {
+				t.Errorf("Expected warning=%v, got %v", tt.expectWarn, warnings)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidatePinnedCodeVersion(t *testing.T) {
+	tests := []struct {
+		version   string
+		expectErr bool
+	}{
+		{version: "3"},
+		{version: "v3"},
+		{version: "latest", expectErr: true},
+		{version: "0", expectErr: true},
+		{version: "v-1", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.version, func(t *testing.T) {
+			agent := &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "pinned-agent",
+					Namespace:   "default",
+					Annotations: map[string]string{PinnedCodeVersionAnnotation: tt.version, LearningDisabledAnnotation: "true"},
+				},
+				Spec: LanguageAgentSpec{Image: "test:latest", Instructions: "test instructions"},
+			}
+			_, err := agent.ValidateCreate()
+			if (err != nil) != tt.expectErr {
+				t.Errorf("ValidateCreate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+		})
+	}
+
+	t.Run("pinned before the check", func(t *testing.T) {
+		agent := &LanguageAgent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "pinned-agent",
+				Namespace:   "default",
+				Annotations: map[string]string{PinnedCodeVersionAnnotation: "latest", LearningDisabledAnnotation: "true"},
+			},
+			Spec: LanguageAgentSpec{Image: "test:latest", Instructions: "test instructions"},
+		}
+		updated := agent.DeepCopy()
+		updated.Spec.Instructions = "updated instructions"
+		if _, err := updated.ValidateUpdate(agent); err != nil {
+			t.Errorf("Expected an unchanged pin to keep validating, got %v", err)
+		}
+	})
+}
+
+func TestParseFeatureGates(t *testing.T) {
+	gates, err := ParseFeatureGates("UnhealthyPodDetection=false, Other=true,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(gates) != 2 || gates[FeatureGateUnhealthyPodDetection] || !gates["Other"] {
+		t.Errorf("Unexpected parsed gates: %v", gates)
+	}
+
+	for _, invalid := range []string{"UnhealthyPodDetection", "=true", "UnhealthyPodDetection=maybe"} {
+		if _, err := ParseFeatureGates(invalid); err == nil {
+			t.Errorf("Expected error parsing %q", invalid)
+		}
+	}
+}
+
+func newCapabilityModel(name, modelName string, capabilities []string) *LanguageModel {
+	return &LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       LanguageModelSpec{Provider: "openai", ModelName: modelName, Capabilities: capabilities},
+	}
+}
+
+func TestLanguageAgentValidateModelCapabilities(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to build scheme: %v", err)
+	}
+
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newCapabilityModel("gpt4o", "gpt-4o", nil),
+		newCapabilityModel("legacy", "gpt-3.5-turbo-instruct", nil),
+		newCapabilityModel("bedrock-claude", "anthropic.claude-3-haiku-20240307-v1:0", nil),
+		newCapabilityModel("local-llama", "llama3-8b", nil),
+		newCapabilityModel("declared", "llama3-8b", []string{ModelCapabilityToolCalling}),
+	).Build()
+	validator := &LanguageAgentCustomValidator{Reader: reader}
+
+	tests := []struct {
+		name          string
+		models        []string
+		required      []string
+		errMsg        string
+		expectWarning string
+	}{
+		{name: "compatible model", models: []string{"gpt4o"}, required: []string{ModelCapabilityToolCalling, ModelCapabilityVision}},
+		{name: "no requirements", models: []string{"legacy"}},
+		{
+			name:     "model without tool calling",
+			models:   []string{"gpt4o", "legacy"},
+			required: []string{ModelCapabilityToolCalling},
+			errMsg:   `model "gpt-3.5-turbo-instruct" (LanguageModel default/legacy) does not support tool-calling`,
+		},
+		{name: "provider-qualified model name", models: []string{"bedrock-claude"}, required: []string{ModelCapabilityToolCalling}},
+		{name: "declared capabilities", models: []string{"declared"}, required: []string{ModelCapabilityToolCalling}},
+		{
+			name:     "declared capabilities missing vision",
+			models:   []string{"declared"},
+			required: []string{ModelCapabilityVision},
+			errMsg:   "does not support vision",
+		},
+		{
+			name:          "unknown model",
+			models:        []string{"local-llama"},
+			required:      []string{ModelCapabilityToolCalling},
+			expectWarning: "are unknown",
+		},
+		{
+			name:          "model not created yet",
+			models:        []string{"missing"},
+			required:      []string{ModelCapabilityToolCalling},
+			expectWarning: "LanguageModel default/missing not found",
+		},
+		{name: "unknown capability", models: []string{"gpt4o"}, required: []string{"telepathy"}, errMsg: `unknown capability "telepathy"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "capable-agent", Namespace: "default"},
+				Spec: LanguageAgentSpec{
+					Image:                     "test:latest",
+					Instructions:              "test instructions",
+					RequiredModelCapabilities: tt.required,
+				},
+			}
+			for _, model := range tt.models {
+				agent.Spec.ModelRefs = append(agent.Spec.ModelRefs, ModelReference{Name: model})
+			}
+
+			warnings, err := validator.ValidateCreate(context.Background(), agent)
+			if tt.errMsg != "" {
+				if err == nil || !contains(err.Error(), tt.errMsg) {
+					t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if tt.expectWarning == "" {
+				if len(warnings) != 0 {
+					t.Errorf("Expected no warnings, got %v", warnings)
+				}
+				return
+			}
+			if len(warnings) != 1 || !contains(warnings[0], tt.expectWarning) {
+				t.Errorf("Expected a warning containing %q, got %v", tt.expectWarning, warnings)
+			}
+		})
+	}
+
+	t.Run("agent being deleted", func(t *testing.T) {
+		now := metav1.Now()
+		agent := &LanguageAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "capable-agent", Namespace: "default", DeletionTimestamp: &now, Finalizers: []string{"langop.io/finalizer"}},
+			Spec: LanguageAgentSpec{
+				Image:                     "test:latest",
+				Instructions:              "test instructions",
+				ModelRefs:                 []ModelReference{{Name: "legacy"}},
+				RequiredModelCapabilities: []string{ModelCapabilityToolCalling},
+			},
+		}
+		if _, err := agent.ValidateUpdate(agent.DeepCopy()); err != nil {
+			t.Errorf("Expected removing the finalizers of a deleted agent to be allowed, got %v", err)
+		}
+	})
+}
+
+func TestLanguageAgentValidateModelRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name      string
+		timeout   string
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "unset", timeout: "", expectErr: false},
+		{name: "seconds", timeout: "90s", expectErr: false},
+		{name: "minutes", timeout: "5m", expectErr: false},
+		{name: "not a duration", timeout: "ninety", expectErr: true, errMsg: "spec.modelRequestTimeout: invalid duration \"ninety\""},
+		{name: "missing unit", timeout: "90", expectErr: true, errMsg: "spec.modelRequestTimeout: invalid duration"},
+		{name: "zero", timeout: "0s", expectErr: true, errMsg: "spec.modelRequestTimeout: must be positive"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:               "test:latest",
+					Instructions:        "test instructions",
+					ModelRequestTimeout: tt.timeout,
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("validateSpec() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateLearningTraceWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		learning  *LearningSpec
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "unset", learning: &LearningSpec{}, expectErr: false},
+		{name: "window and age", learning: &LearningSpec{TraceWindow: "6h", MinTraceAge: "2m"}, expectErr: false},
+		{name: "zero age", learning: &LearningSpec{MinTraceAge: "0s"}, expectErr: false},
+		{name: "zero window", learning: &LearningSpec{TraceWindow: "0s"}, expectErr: true, errMsg: "spec.learning.traceWindow: must be positive"},
+		{name: "invalid window", learning: &LearningSpec{TraceWindow: "a day"}, expectErr: true, errMsg: "spec.learning.traceWindow: invalid duration"},
+		{name: "invalid age", learning: &LearningSpec{MinTraceAge: "soon"}, expectErr: true, errMsg: "spec.learning.minTraceAge: invalid duration"},
+		{name: "health gate window", learning: &LearningSpec{HealthGateWindow: "30m"}, expectErr: false},
+		{name: "disabled health gate", learning: &LearningSpec{HealthGateWindow: "0s"}, expectErr: false},
+		{name: "invalid health gate window", learning: &LearningSpec{HealthGateWindow: "half an hour"}, expectErr: true, errMsg: "spec.learning.healthGateWindow: invalid duration"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					Learning:     tt.learning,
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("validateSpec() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateModelRoles(t *testing.T) {
+	tests := []struct {
+		name      string
+		refs      []ModelReference
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "no models", refs: nil},
+		{name: "no roles", refs: []ModelReference{{Name: "gpt-4o"}, {Name: "claude"}}},
+		{name: "one primary with fallbacks", refs: []ModelReference{
+			{Name: "local-llama", Role: ModelRoleFallback},
+			{Name: "gpt-4o", Role: ModelRolePrimary},
+			{Name: "claude", Role: ModelRoleFallback},
+		}},
+		{name: "conflicting primaries", refs: []ModelReference{
+			{Name: "gpt-4o", Role: ModelRolePrimary},
+			{Name: "claude", Role: ModelRolePrimary},
+		}, expectErr: true, errMsg: "spec.modelRefs: at most one model may have role \"primary\", found gpt-4o, claude"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					ModelRefs:    tt.refs,
+				},
+			}
+
+			_, err := agent.ValidateCreate()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ValidateCreate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("ValidateCreate() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+
+	conflicting := &LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy-agent", Namespace: "default"},
+		Spec: LanguageAgentSpec{
+			Image:        "test:latest",
+			Instructions: "test instructions",
+			ModelRefs: []ModelReference{
+				{Name: "gpt-4o", Role: ModelRolePrimary},
+				{Name: "claude", Role: ModelRolePrimary},
+			},
+		},
+	}
+
+	t.Run("admitted before the check", func(t *testing.T) {
+		updated := conflicting.DeepCopy()
+		updated.Spec.Instructions = "updated instructions"
+		if _, err := updated.ValidateUpdate(conflicting); err != nil {
+			t.Errorf("Expected an unrelated update of an existing agent to be allowed, got %v", err)
+		}
+	})
+
+	t.Run("primary models changed", func(t *testing.T) {
+		updated := conflicting.DeepCopy()
+		updated.Spec.ModelRefs = append(updated.Spec.ModelRefs, ModelReference{Name: "gemini", Role: ModelRolePrimary})
+		if _, err := updated.ValidateUpdate(conflicting); err == nil {
+			t.Error("Expected changed primary models to be validated")
+		}
+	})
+
+	t.Run("agent being deleted", func(t *testing.T) {
+		now := metav1.Now()
+		updated := conflicting.DeepCopy()
+		updated.DeletionTimestamp = &now
+		updated.Finalizers = []string{"langop.io/finalizer"}
+		if _, err := updated.ValidateUpdate(&LanguageAgent{}); err != nil {
+			t.Errorf("Expected removing the finalizers of a deleted agent to be allowed, got %v", err)
+		}
+	})
+}
+
+func TestLanguageAgentValidateDisruptionBudget(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	tests := []struct {
+		name      string
+		replicas  *int32
+		budget    *int32
+		expectErr bool
+	}{
+		{name: "default budget", replicas: int32Ptr(3)},
+		{name: "budget below replicas", replicas: int32Ptr(3), budget: int32Ptr(2)},
+		{name: "budget equal to replicas", replicas: int32Ptr(3), budget: int32Ptr(3), expectErr: true},
+		{name: "budget above replicas", replicas: int32Ptr(2), budget: int32Ptr(5), expectErr: true},
+		{name: "single replica ignores budget", replicas: int32Ptr(1), budget: int32Ptr(1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:            "test:latest",
+					Instructions:     "test instructions",
+					Replicas:         tt.replicas,
+					DisruptionBudget: tt.budget,
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), "spec.disruptionBudget must be lower than spec.replicas") {
+				t.Errorf("validateSpec() error = %v, expected a disruption budget error", err.Error())
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateDeploymentStrategy(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	tests := []struct {
+		name      string
+		strategy  *UpdateStrategySpec
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "no strategy"},
+		{name: "defaults", strategy: &UpdateStrategySpec{Type: "RollingUpdate"}},
+		{name: "recreate", strategy: &UpdateStrategySpec{Type: "Recreate"}},
+		{name: "custom parameters", strategy: &UpdateStrategySpec{
+			RollingUpdate: &RollingUpdateSpec{MaxSurge: int32Ptr(2), MaxUnavailable: int32Ptr(1)},
+		}},
+		{name: "no surge with default unavailable", strategy: &UpdateStrategySpec{
+			RollingUpdate: &RollingUpdateSpec{MaxSurge: int32Ptr(0)},
+		}, expectErr: true, errMsg: "rollingUpdate.maxSurge and rollingUpdate.maxUnavailable cannot both be 0"},
+		{name: "negative surge", strategy: &UpdateStrategySpec{
+			RollingUpdate: &RollingUpdateSpec{MaxSurge: int32Ptr(-1)},
+		}, expectErr: true, errMsg: "rollingUpdate.maxSurge must be non-negative"},
+		{name: "negative unavailable", strategy: &UpdateStrategySpec{
+			RollingUpdate: &RollingUpdateSpec{MaxUnavailable: int32Ptr(-1)},
+		}, expectErr: true, errMsg: "rollingUpdate.maxUnavailable must be non-negative"},
+		{name: "rolling update with recreate", strategy: &UpdateStrategySpec{
+			Type:          "Recreate",
+			RollingUpdate: &RollingUpdateSpec{MaxSurge: int32Ptr(1)},
+		}, expectErr: true, errMsg: "rollingUpdate may not be set when type is Recreate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:              "test:latest",
+					Instructions:       "test instructions",
+					DeploymentStrategy: tt.strategy,
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), "spec.deploymentStrategy: "+tt.errMsg) {
+				t.Errorf("validateSpec() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateEnv(t *testing.T) {
+	tests := []struct {
+		name      string
+		env       []corev1.EnvVar
+		logLevel  string
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "no env"},
+		{name: "user variables", env: []corev1.EnvVar{{Name: "SLACK_CHANNEL", Value: "#ops"}, {Name: "api.region", Value: "eu"}}},
+		{name: "reserved name", env: []corev1.EnvVar{{Name: "AGENT_NAME", Value: "other"}}, expectErr: true, errMsg: "\"AGENT_NAME\" is reserved by the operator"},
+		{name: "log level without spec.logLevel", env: []corev1.EnvVar{{Name: "LOG_LEVEL", Value: "debug"}}},
+		{name: "log level with spec.logLevel", env: []corev1.EnvVar{{Name: "AGENT_LOG_LEVEL", Value: "debug"}}, logLevel: LogLevelInfo, expectErr: true, errMsg: "\"AGENT_LOG_LEVEL\" conflicts with spec.logLevel"},
+		{name: "reserved api key", env: []corev1.EnvVar{{Name: "OPENAI_API_KEY", Value: "sk-real"}}, expectErr: true, errMsg: "\"OPENAI_API_KEY\" is reserved by the operator"},
+		{name: "reserved otel variable", env: []corev1.EnvVar{{Name: "OTEL_SERVICE_NAME", Value: "custom"}}, expectErr: true, errMsg: "\"OTEL_SERVICE_NAME\" is reserved by the operator"},
+		{name: "unmanaged otel variable", env: []corev1.EnvVar{{Name: "OTEL_BSP_SCHEDULE_DELAY", Value: "1000"}}},
+		{name: "invalid name", env: []corev1.EnvVar{{Name: "MY VAR", Value: "x"}}, expectErr: true, errMsg: "invalid name \"MY VAR\""},
+		{name: "name starting with digit", env: []corev1.EnvVar{{Name: "1VAR", Value: "x"}}, expectErr: true, errMsg: "invalid name \"1VAR\""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "env-agent", Namespace: "default"},
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					Env:          tt.env,
+					LogLevel:     tt.logLevel,
+				},
+			}
+
+			_, err := agent.ValidateCreate()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ValidateCreate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), "spec.env: "+tt.errMsg) {
+				t.Errorf("ValidateCreate() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+
+	// Agents admitted before a name was reserved keep it, but can't add more reserved names
+	existing := &LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "env-agent", Namespace: "default"},
+		Spec: LanguageAgentSpec{
+			Image:        "test:latest",
+			Instructions: "test instructions",
+			Env:          []corev1.EnvVar{{Name: "OTEL_SERVICE_NAME", Value: "custom"}},
+		},
+	}
+	t.Run("grandfathered reserved name", func(t *testing.T) {
+		updated := existing.DeepCopy()
+		updated.Spec.Instructions = "updated instructions"
+		if _, err := updated.ValidateUpdate(existing); err != nil {
+			t.Errorf("Expected a reserved name set before the update to be allowed, got %v", err)
+		}
+	})
+	t.Run("added reserved name", func(t *testing.T) {
+		updated := existing.DeepCopy()
+		updated.Spec.Env = append(updated.Spec.Env, corev1.EnvVar{Name: "AGENT_NAME", Value: "other"})
+		_, err := updated.ValidateUpdate(existing)
+		if err == nil || !contains(err.Error(), "\"AGENT_NAME\" is reserved by the operator") {
+			t.Errorf("Expected the added reserved name to be rejected, got %v", err)
+		}
+	})
+}
+
+func TestLanguageAgentValidateUpdateScheduleMode(t *testing.T) {
+	newAgent := func(mode, schedule string) *LanguageAgent {
+		return &LanguageAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+			Spec: LanguageAgentSpec{
+				Image:         "test:latest",
+				Instructions:  "test instructions",
+				ExecutionMode: mode,
+				Schedule:      schedule,
+			},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		old       *LanguageAgent
+		updated   *LanguageAgent
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "scheduled agent changes schedule", old: newAgent("scheduled", "0 9 * * *"), updated: newAgent("scheduled", "0 10 * * *")},
+		{name: "scheduled agent becomes autonomous and drops schedule", old: newAgent("scheduled", "0 9 * * *"), updated: newAgent("autonomous", "")},
+		{name: "scheduled agent becomes autonomous keeping schedule", old: newAgent("scheduled", "0 9 * * *"), updated: newAgent("autonomous", "0 9 * * *"),
+			expectErr: true, errMsg: "spec.schedule: only valid when spec.executionMode is 'scheduled'"},
+		{name: "autonomous agent gains schedule", old: newAgent("autonomous", ""), updated: newAgent("autonomous", "0 9 * * *"),
+			expectErr: true, errMsg: "spec.schedule: only valid when spec.executionMode is 'scheduled'"},
+		{name: "scheduled agent drops schedule", old: newAgent("scheduled", "0 9 * * *"), updated: newAgent("scheduled", ""),
+			expectErr: true, errMsg: "spec.schedule: schedule is required when executionMode is 'scheduled'"},
+		{name: "scheduled agent gets invalid schedule", old: newAgent("scheduled", "0 9 * * *"), updated: newAgent("scheduled", "0 9 * *"),
+			expectErr: true, errMsg: "spec.schedule: invalid cron expression"},
+		{name: "existing mismatch is left alone", old: newAgent("autonomous", "0 9 * * *"), updated: newAgent("autonomous", "0 9 * * *")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.updated.ValidateUpdate(tt.old)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ValidateUpdate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("ValidateUpdate() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateImage(t *testing.T) {
+	tests := []struct {
+		name       string
+		image      string
+		clusterRef string
+		expectErr  bool
+	}{
+		{name: "image", image: "test:latest"},
+		{name: "cluster default image", clusterRef: "fleet"},
+		{name: "image and cluster", image: "test:latest", clusterRef: "fleet"},
+		{name: "neither", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:        tt.image,
+					ClusterRef:   tt.clusterRef,
+					Instructions: "test instructions",
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), "spec.image is required") {
+				t.Errorf("validateSpec() error = %v, expected an image error", err.Error())
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateLogLevel(t *testing.T) {
+	tests := []struct {
+		name      string
+		level     string
+		expectErr bool
+	}{
+		{name: "unset"},
+		{name: "debug", level: LogLevelDebug},
+		{name: "info", level: LogLevelInfo},
+		{name: "warn", level: LogLevelWarn},
+		{name: "error", level: LogLevelError},
+		{name: "unsupported", level: "trace", expectErr: true},
+		{name: "wrong case", level: "DEBUG", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					LogLevel:     tt.level,
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), "spec.logLevel: unsupported log level") {
+				t.Errorf("validateSpec() error = %v, expected a log level error", err.Error())
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateWebhookRoutes(t *testing.T) {
+	tests := []struct {
+		name      string
+		routes    []WebhookRouteRule
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "no routes"},
+		{name: "disjoint prefixes", routes: []WebhookRouteRule{{PathPrefix: "/webhook"}, {PathPrefix: "/api/v1", Port: 9090}, {PathPrefix: "/apis"}}},
+		{name: "nested prefixes", routes: []WebhookRouteRule{{PathPrefix: "/api"}, {PathPrefix: "/api/v1", Port: 9090}}, expectErr: true, errMsg: "spec.webhookRoutes: pathPrefix \"/api/v1\" overlaps \"/api\""},
+		{name: "duplicate prefixes", routes: []WebhookRouteRule{{PathPrefix: "/webhook/"}, {PathPrefix: "/webhook"}}, expectErr: true, errMsg: "overlaps"},
+		{name: "catch-all overlaps everything", routes: []WebhookRouteRule{{PathPrefix: "/webhook"}, {PathPrefix: "/"}}, expectErr: true, errMsg: "overlaps"},
+		{name: "relative prefix", routes: []WebhookRouteRule{{PathPrefix: "webhook"}}, expectErr: true, errMsg: "must start with /"},
+		{name: "service port", routes: []WebhookRouteRule{{PathPrefix: "/webhook", Port: 80}}, expectErr: true, errMsg: "port 80 is reserved"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:         "test:latest",
+					Instructions:  "test instructions",
+					WebhookRoutes: tt.routes,
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("validateSpec() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateWorkspaceSeed(t *testing.T) {
+	gitRepo := &GitRepoSeed{URL: "https://github.com/example/runbooks.git"}
+	configMap := &corev1.LocalObjectReference{Name: "templates"}
+	tests := []struct {
+		name      string
+		workspace *WorkspaceSpec
+		errMsg    string
+	}{
+		{name: "git seed", workspace: &WorkspaceSpec{Enabled: true, Size: "1Gi", Seed: &WorkspaceSeed{GitRepo: gitRepo}}},
+		{name: "configmap seed", workspace: &WorkspaceSpec{Enabled: true, Size: "1Gi", Seed: &WorkspaceSeed{ConfigMapRef: configMap}}},
+		{name: "both sources", workspace: &WorkspaceSpec{Enabled: true, Size: "1Gi", Seed: &WorkspaceSeed{GitRepo: gitRepo, ConfigMapRef: configMap}},
+			errMsg: "spec.workspace.seed: only one of gitRepo and configMapRef may be set"},
+		{name: "no source", workspace: &WorkspaceSpec{Enabled: true, Size: "1Gi", Seed: &WorkspaceSeed{}},
+			errMsg: "spec.workspace.seed: one of gitRepo or configMapRef is required"},
+		{name: "disabled workspace", workspace: &WorkspaceSpec{Size: "1Gi", Seed: &WorkspaceSeed{GitRepo: gitRepo}},
+			errMsg: "spec.workspace.seed requires spec.workspace.enabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					Workspace:    tt.workspace,
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != (tt.errMsg != "") {
+				t.Fatalf("validateSpec() error = %v, expected error %q", err, tt.errMsg)
+			}
+			if err != nil && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("validateSpec() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateEgress(t *testing.T) {
+	to := func(peer NetworkPeer) []NetworkRule { return []NetworkRule{{To: &peer}} }
+	ports := func(ports ...NetworkPort) []NetworkRule { return []NetworkRule{{Ports: ports}} }
+
+	tests := []struct {
+		name      string
+		egress    []NetworkRule
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "no egress"},
+		{name: "valid rules", egress: []NetworkRule{
+			{To: &NetworkPeer{CIDR: "10.0.0.0/8"}, Ports: []NetworkPort{{Port: 443}, {Protocol: "UDP", Port: 53}}},
+			{To: &NetworkPeer{DNS: []string{"api.openai.com", "*.googleapis.com", "API.Example.com"}}},
+			{To: &NetworkPeer{CIDR: "2001:db8::/32", Service: &ServiceReference{Name: "db"}}},
+		}},
+		{name: "malformed CIDR", egress: to(NetworkPeer{CIDR: "10.0.0/8"}), expectErr: true, errMsg: "spec.egress[0].to.cidr: invalid CIDR \"10.0.0/8\""},
+		{name: "address without prefix length", egress: to(NetworkPeer{CIDR: "10.0.0.1"}), expectErr: true, errMsg: "spec.egress[0].to.cidr"},
+		{name: "invalid hostname", egress: to(NetworkPeer{DNS: []string{"api.openai.com", "bad_host.com"}}), expectErr: true, errMsg: "spec.egress[0].to.dns[1]: invalid hostname \"bad_host.com\""},
+		{name: "wildcard in the middle", egress: to(NetworkPeer{DNS: []string{"api.*.com"}}), expectErr: true, errMsg: "spec.egress[0].to.dns[0]"},
+		{name: "service without name", egress: to(NetworkPeer{Service: &ServiceReference{Namespace: "data"}}), expectErr: true, errMsg: "spec.egress[0].to.service.name is required"},
+		{name: "invalid selector", egress: to(NetworkPeer{NamespaceSelector: &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "team", Operator: "Near"}},
+		}}), expectErr: true, errMsg: "spec.egress[0].to.namespaceSelector"},
+		{name: "port out of range", egress: ports(NetworkPort{Port: 70000}), expectErr: true, errMsg: "spec.egress[0].ports[0].port must be between 1 and 65535, got 70000"},
+		{name: "zero port", egress: ports(NetworkPort{Port: 443}, NetworkPort{}), expectErr: true, errMsg: "spec.egress[0].ports[1].port"},
+		{name: "invalid protocol", egress: ports(NetworkPort{Protocol: "ICMP", Port: 443}), expectErr: true, errMsg: "spec.egress[0].ports[0].protocol: unsupported protocol \"ICMP\""},
+		{name: "error names the rule", egress: []NetworkRule{{To: &NetworkPeer{CIDR: "10.0.0.0/8"}}, {To: &NetworkPeer{CIDR: "nope"}}}, expectErr: true, errMsg: "spec.egress[1].to.cidr"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					Egress:       tt.egress,
+				},
+			}
+
+			_, err := agent.ValidateCreate()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("ValidateCreate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("ValidateCreate() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+
+			_, err = agent.ValidateUpdate(agent.DeepCopy())
+			if (err != nil) != tt.expectErr {
+				t.Errorf("ValidateUpdate() error = %v, expectErr %v", err, tt.expectErr)
+			}
+
+			// Removing the finalizers of an agent being deleted is never blocked
+			now := metav1.Now()
+			deleted := agent.DeepCopy()
+			deleted.DeletionTimestamp = &now
+			deleted.Finalizers = []string{"langop.io/finalizer"}
+			if _, err := deleted.ValidateUpdate(agent); err != nil {
+				t.Errorf("Expected removing the finalizers of a deleted agent to be allowed, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateSynthesisSettings(t *testing.T) {
+	temperature := func(v float64) *float64 { return &v }
+	tokens := func(v int32) *int32 { return &v }
+
+	tests := []struct {
+		name      string
+		settings  *AgentSynthesisSettings
+		expectErr bool
+		errMsg    string
+	}{
+		{name: "unset"},
+		{name: "empty", settings: &AgentSynthesisSettings{}},
+		{name: "bounds", settings: &AgentSynthesisSettings{Temperature: temperature(2), MaxTokens: tokens(131072)}},
+		{name: "deterministic", settings: &AgentSynthesisSettings{Temperature: temperature(0), MaxTokens: tokens(1)}},
+		{name: "negative temperature", settings: &AgentSynthesisSettings{Temperature: temperature(-0.1)}, expectErr: true,
+			errMsg: "spec.synthesis.temperature must be between 0 and 2, got -0.1"},
+		{name: "temperature too high", settings: &AgentSynthesisSettings{Temperature: temperature(2.5)}, expectErr: true,
+			errMsg: "spec.synthesis.temperature"},
+		{name: "zero max tokens", settings: &AgentSynthesisSettings{MaxTokens: tokens(0)}, expectErr: true,
+			errMsg: "spec.synthesis.maxTokens must be between 1 and 131072, got 0"},
+		{name: "max tokens too high", settings: &AgentSynthesisSettings{MaxTokens: tokens(200000)}, expectErr: true,
+			errMsg: "spec.synthesis.maxTokens"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &LanguageAgent{
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					Synthesis:    tt.settings,
+				},
+			}
+
+			err := agent.validateSpec()
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("validateSpec() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if tt.expectErr && !contains(err.Error(), tt.errMsg) {
+				t.Errorf("validateSpec() error = %v, expected to contain %q", err.Error(), tt.errMsg)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentValidateSynthesisTemperature(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to build scheme: %v", err)
+	}
+
+	claude := newCapabilityModel("claude", "claude-sonnet-4", nil)
+	claude.Spec.Provider = "anthropic"
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newCapabilityModel("gpt4o", "gpt-4o", nil),
+		claude,
+	).Build()
+	validator := &LanguageAgentCustomValidator{Reader: reader}
+
+	tests := []struct {
+		name        string
+		models      []ModelReference
+		temperature float64
+		errMsg      string
+	}{
+		{name: "openai accepts up to 2", models: []ModelReference{{Name: "gpt4o"}}, temperature: 1.5},
+		{name: "anthropic accepts up to 1", models: []ModelReference{{Name: "claude"}}, temperature: 1.0},
+		{name: "anthropic rejects above 1", models: []ModelReference{{Name: "claude"}}, temperature: 1.5,
+			errMsg: "spec.synthesis.temperature: must be at most 1 for anthropic model \"claude-sonnet-4\" (LanguageModel default/claude), got 1.5"},
+		{name: "primary model decides", models: []ModelReference{{Name: "gpt4o", Role: ModelRoleFallback}, {Name: "claude", Role: ModelRolePrimary}}, temperature: 1.5,
+			errMsg: "spec.synthesis.temperature"},
+		{name: "missing model isn't checked", models: []ModelReference{{Name: "unknown"}}, temperature: 1.5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			temperature := tt.temperature
+			agent := &LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+				Spec: LanguageAgentSpec{
+					Image:        "test:latest",
+					Instructions: "test instructions",
+					ModelRefs:    tt.models,
+					Synthesis:    &AgentSynthesisSettings{Temperature: &temperature},
+				},
+			}
+
+			_, err := validator.ValidateCreate(context.Background(), agent)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Expected the agent to be admitted, got %v", err)
+				}
+				return
+			}
+			if err == nil || !contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected an error containing %q, got %v", tt.errMsg, err)
+			}
+
+			// An agent being deleted can still have its finalizers removed
+			now := metav1.Now()
+			deleted := agent.DeepCopy()
+			deleted.DeletionTimestamp = &now
+			if _, err := validator.ValidateUpdate(context.Background(), agent, deleted); err != nil {
+				t.Errorf("Expected removing the finalizers of a deleted agent to be allowed, got %v", err)
+			}
+		})
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -36,6 +37,54 @@ type LanguageClusterSpec struct {
 	// IngressConfig defines ingress/gateway configuration for the cluster
 	// +optional
 	IngressConfig *IngressConfig `json:"ingressConfig,omitempty"`
+
+	// MinScheduleInterval is the shortest interval allowed between runs of scheduled agents
+	// referencing this cluster (e.g. "15m"). Applies to user-specified and synthesized schedules.
+	// +optional
+	MinScheduleInterval *metav1.Duration `json:"minScheduleInterval,omitempty"`
+
+	// ImagePullPolicy is the default pull policy for agents referencing this cluster
+	// that don't set spec.imagePullPolicy
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	// +optional
+	ImagePullPolicy corev1.PullPolicy `json:"imagePullPolicy,omitempty"`
+
+	// DefaultAgentImage is the image run by agents referencing this cluster that don't set
+	// spec.image. Changing it rolls those agents to the new image, staggered by the operator's
+	// restart coordinator; agents with an explicit image are left alone.
+	// +optional
+	DefaultAgentImage string `json:"defaultAgentImage,omitempty"`
+
+	// DefaultLogLevel is the log level of agents referencing this cluster that don't set
+	// spec.logLevel. Changing it rolls those agents.
+	// +kubebuilder:validation:Enum=debug;info;warn;error
+	// +optional
+	DefaultLogLevel string `json:"defaultLogLevel,omitempty"`
+
+	// DefaultEgress is the baseline egress allowed to every agent referencing this cluster, in
+	// addition to the agent's own spec.egress. Agents opt out with spec.inheritClusterEgress: false.
+	// +optional
+	DefaultEgress []NetworkRule `json:"defaultEgress,omitempty"`
+
+	// SynthesisFallbackModels is an ordered chain of LanguageModels that agents referencing this
+	// cluster synthesize with when their own synthesis model is missing or unreachable. The first
+	// reachable model in the chain is used. Namespaces default to the agent's namespace.
+	// +optional
+	SynthesisFallbackModels []ModelReference `json:"synthesisFallbackModels,omitempty"`
+
+	// Defaults are applied to the workloads of agents referencing this cluster, overriding the
+	// operator's defaults
+	// +optional
+	Defaults *ClusterDefaults `json:"defaults,omitempty"`
+}
+
+// ClusterDefaults holds cluster-wide defaults for agent workloads
+type ClusterDefaults struct {
+	// Resources are the default requests and limits of agent and sidecar tool containers. Each
+	// request or limit applies when the agent or tool doesn't set it, and takes precedence over
+	// the operator's --default-agent-* flags.
+	// +optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
 }
 
 // IngressConfig defines ingress/gateway configuration
@@ -54,6 +103,11 @@ type IngressConfig struct {
 	// +optional
 	GatewayNamespace string `json:"gatewayNamespace,omitempty"`
 
+	// GatewaySectionName attaches agent HTTPRoutes to a specific Gateway listener.
+	// If empty, routes attach to every listener that accepts them.
+	// +optional
+	GatewaySectionName string `json:"gatewaySectionName,omitempty"`
+
 	// Deprecated: Use GatewayName instead. This field actually refers to a Gateway resource name, not a GatewayClass.
 	// GatewayClassName specifies the Gateway API GatewayClass to use
 	// If empty, will attempt auto-detection or fall back to Ingress
@@ -64,6 +118,12 @@ type IngressConfig struct {
 	// Only used when Gateway API is not available
 	// +optional
 	IngressClassName string `json:"ingressClassName,omitempty"`
+
+	// FallbackToIngress creates an Ingress when Gateway API is available but the
+	// HTTPRoute cannot be created (e.g. Gateway TLS validation fails).
+	// When false, the HTTPRoute failure is reported and reconciliation fails.
+	// +optional
+	FallbackToIngress bool `json:"fallbackToIngress,omitempty"`
 }
 
 // IngressTLSConfig defines TLS configuration
@@ -181,6 +241,30 @@ type LanguageClusterStatus struct {
 
 	// Conditions
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+
+	// ImageUpgrade tracks the rollout of the latest defaultAgentImage to the agents using it
+	// +optional
+	ImageUpgrade *AgentImageUpgradeStatus `json:"imageUpgrade,omitempty"`
+}
+
+// AgentImageUpgradeStatus tracks agents rolling to a new cluster default agent image
+type AgentImageUpgradeStatus struct {
+	// Image is the default agent image being rolled out
+	Image string `json:"image"`
+
+	// TotalAgents is the number of agents using the cluster's default agent image
+	TotalAgents int32 `json:"totalAgents"`
+
+	// UpdatedAgents is the number of those agents fully rolled out with Image
+	UpdatedAgents int32 `json:"updatedAgents"`
+
+	// StartedAt is when the cluster's default agent image changed to Image
+	// +optional
+	StartedAt *metav1.Time `json:"startedAt,omitempty"`
+
+	// CompletedAt is when every agent using the default image finished rolling out Image
+	// +optional
+	CompletedAt *metav1.Time `json:"completedAt,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -17,24 +17,87 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+//+kubebuilder:webhook:path=/mutate-langop-io-v1alpha1-languagecluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=langop.io,resources=languageclusters,verbs=create;update,versions=v1alpha1,name=mlanguagecluster.kb.io,admissionReviewVersions=v1
 //+kubebuilder:webhook:path=/validate-langop-io-v1alpha1-languagecluster,mutating=false,failurePolicy=fail,sideEffects=None,groups=langop.io,resources=languageclusters,verbs=create;update,versions=v1alpha1,name=vlanguagecluster.kb.io,admissionReviewVersions=v1
 
+//+kubebuilder:rbac:groups=cert-manager.io,resources=issuers;clusterissuers,verbs=get
+//+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=gateways,verbs=get
+
+var _ webhook.Defaulter = &LanguageCluster{}
 var _ webhook.Validator = &LanguageCluster{}
 
+const (
+	// certManagerGroup is the API group of cert-manager issuers
+	certManagerGroup = "cert-manager.io"
+	// defaultIssuerKind is the issuer kind used when issuerRef.kind is unset
+	defaultIssuerKind = "ClusterIssuer"
+)
+
+// clusterWebhookReader looks up the issuers and Gateways a LanguageCluster references.
+// When nil, references are not checked.
+var clusterWebhookReader client.Reader
+
+// Default implements webhook.Defaulter
+func (c *LanguageCluster) Default() {
+	c.Spec.Domain = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(c.Spec.Domain)), ".")
+
+	ingress := c.Spec.IngressConfig
+	if ingress == nil {
+		return
+	}
+	// Agents route through a Gateway in the cluster's namespace unless told otherwise
+	if ingress.GatewayName != "" && ingress.GatewayNamespace == "" {
+		ingress.GatewayNamespace = c.Namespace
+	}
+	if ingress.TLS != nil && ingress.TLS.IssuerRef != nil {
+		if ingress.TLS.IssuerRef.Kind == "" {
+			ingress.TLS.IssuerRef.Kind = defaultIssuerKind
+		}
+		if ingress.TLS.IssuerRef.Group == "" {
+			ingress.TLS.IssuerRef.Group = certManagerGroup
+		}
+	}
+}
+
 // ValidateCreate implements webhook.Validator
 func (c *LanguageCluster) ValidateCreate() (admission.Warnings, error) {
-	return nil, c.validate()
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	if err := c.validateGatewayClassName(); err != nil {
+		return nil, fmt.Errorf("spec.ingressConfig: %w", err)
+	}
+	return c.referenceWarnings(context.Background()), nil
 }
 
 // ValidateUpdate implements webhook.Validator
 func (c *LanguageCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
-	return nil, c.validate()
+	if err := c.validate(); err != nil {
+		return nil, err
+	}
+	// Clusters admitted before the gateway name check keep validating until their gateway changes
+	if oldCluster, ok := old.(*LanguageCluster); !ok || gatewayChanged(oldCluster.Spec.IngressConfig, c.Spec.IngressConfig) {
+		if err := c.validateGatewayClassName(); err != nil {
+			return nil, fmt.Errorf("spec.ingressConfig: %w", err)
+		}
+	}
+	return c.referenceWarnings(context.Background()), nil
 }
 
 // ValidateDelete implements webhook.Validator
@@ -43,13 +106,163 @@ func (c *LanguageCluster) ValidateDelete() (admission.Warnings, error) {
 }
 
 func (c *LanguageCluster) validate() error {
-	// No validation needed for LanguageCluster
-	// (LanguageCluster now only manages namespace creation)
+	if c.Spec.MinScheduleInterval != nil && c.Spec.MinScheduleInterval.Duration < 0 {
+		return fmt.Errorf("spec.minScheduleInterval must be non-negative")
+	}
+	if err := validateImagePullPolicy(c.Spec.ImagePullPolicy); err != nil {
+		return fmt.Errorf("spec.imagePullPolicy: %w", err)
+	}
+	if err := validateLogLevel(c.Spec.DefaultLogLevel); err != nil {
+		return fmt.Errorf("spec.defaultLogLevel: %w", err)
+	}
+	if err := validateEgressRules(c.Spec.DefaultEgress); err != nil {
+		return fmt.Errorf("spec.defaultEgress%w", err)
+	}
+	if c.Spec.Domain != "" {
+		// Agent webhooks are served at <uuid>.<domain>, so the domain must be a plain DNS name
+		if errs := validation.IsDNS1123Subdomain(c.Spec.Domain); len(errs) > 0 {
+			return fmt.Errorf("spec.domain %q is not a valid DNS name: %s", c.Spec.Domain, strings.Join(errs, "; "))
+		}
+	}
+	if c.Spec.IngressConfig != nil {
+		if err := c.validateIngressConfig(); err != nil {
+			return fmt.Errorf("spec.ingressConfig: %w", err)
+		}
+	}
+	return nil
+}
+
+// validateIngressConfig checks that the gateway and TLS settings are consistent
+func (c *LanguageCluster) validateIngressConfig() error {
+	ingress := c.Spec.IngressConfig
+
+	if ingress.GatewayNamespace != "" {
+		if ingress.GatewayName == "" {
+			return fmt.Errorf("gatewayNamespace requires gatewayName")
+		}
+		if errs := validation.IsDNS1123Label(ingress.GatewayNamespace); len(errs) > 0 {
+			return fmt.Errorf("gatewayNamespace %q is invalid: %s", ingress.GatewayNamespace, strings.Join(errs, "; "))
+		}
+	}
+	if ingress.GatewayName != "" {
+		if errs := validation.IsDNS1123Subdomain(ingress.GatewayName); len(errs) > 0 {
+			return fmt.Errorf("gatewayName %q is invalid: %s", ingress.GatewayName, strings.Join(errs, "; "))
+		}
+	}
+
+	if ingress.TLS == nil || ingress.TLS.IssuerRef == nil {
+		return nil
+	}
+	issuer := ingress.TLS.IssuerRef
+	if issuer.Name == "" {
+		return fmt.Errorf("tls.issuerRef.name is required")
+	}
+	if issuer.Kind != "" && issuer.Kind != "Issuer" && issuer.Kind != defaultIssuerKind {
+		return fmt.Errorf("tls.issuerRef.kind must be Issuer or ClusterIssuer, got %q", issuer.Kind)
+	}
+	if issuer.Group != "" && issuer.Group != certManagerGroup {
+		return fmt.Errorf("tls.issuerRef.group must be %s, got %q", certManagerGroup, issuer.Group)
+	}
 	return nil
 }
 
+// validateGatewayClassName rejects a deprecated gatewayClassName that names a different Gateway
+// than gatewayName
+func (c *LanguageCluster) validateGatewayClassName() error {
+	ingress := c.Spec.IngressConfig
+	if ingress == nil || ingress.GatewayName == "" || ingress.GatewayClassName == "" {
+		return nil
+	}
+	if ingress.GatewayClassName != ingress.GatewayName {
+		return fmt.Errorf("gatewayName %q conflicts with deprecated gatewayClassName %q; remove gatewayClassName",
+			ingress.GatewayName, ingress.GatewayClassName)
+	}
+	return nil
+}
+
+// gatewayChanged reports whether an update changes the Gateway agents route through
+func gatewayChanged(old, updated *IngressConfig) bool {
+	var oldName, oldClassName, name, className string
+	if old != nil {
+		oldName, oldClassName = old.GatewayName, old.GatewayClassName
+	}
+	if updated != nil {
+		name, className = updated.GatewayName, updated.GatewayClassName
+	}
+	return oldName != name || oldClassName != className
+}
+
+// referenceWarnings reports referenced issuers and Gateways that don't exist. They may be
+// created later, so a missing reference is a warning rather than an error.
+func (c *LanguageCluster) referenceWarnings(ctx context.Context) admission.Warnings {
+	ingress := c.Spec.IngressConfig
+	if ingress == nil {
+		return nil
+	}
+
+	var warnings admission.Warnings
+	if ingress.GatewayClassName != "" && ingress.GatewayName == "" {
+		warnings = append(warnings, "spec.ingressConfig.gatewayClassName is deprecated, use gatewayName instead")
+	}
+	if ingress.TLS != nil && ingress.TLS.Enabled && c.Spec.Domain == "" {
+		warnings = append(warnings, "spec.ingressConfig.tls has no effect without spec.domain")
+	}
+	if clusterWebhookReader == nil {
+		return warnings
+	}
+
+	if ingress.TLS != nil && ingress.TLS.IssuerRef != nil && ingress.TLS.IssuerRef.Name != "" {
+		issuer := ingress.TLS.IssuerRef
+		kind := issuer.Kind
+		if kind == "" {
+			kind = defaultIssuerKind
+		}
+		namespace := ""
+		if kind == "Issuer" {
+			namespace = c.Namespace
+		}
+		gvk := schema.GroupVersionKind{Group: certManagerGroup, Version: "v1", Kind: kind}
+		if warning := checkReference(ctx, gvk, namespace, issuer.Name); warning != "" {
+			warnings = append(warnings, "spec.ingressConfig.tls.issuerRef: "+warning)
+		}
+	}
+
+	if ingress.GatewayName != "" {
+		namespace := ingress.GatewayNamespace
+		if namespace == "" {
+			namespace = c.Namespace
+		}
+		gvk := schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "Gateway"}
+		if warning := checkReference(ctx, gvk, namespace, ingress.GatewayName); warning != "" {
+			warnings = append(warnings, "spec.ingressConfig.gatewayName: "+warning)
+		}
+	}
+	return warnings
+}
+
+// checkReference returns a warning when the referenced object can't be found
+func checkReference(ctx context.Context, gvk schema.GroupVersionKind, namespace, name string) string {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(gvk)
+	err := clusterWebhookReader.Get(ctx, client.ObjectKey{Namespace: namespace, Name: name}, obj)
+	switch {
+	case err == nil:
+		return ""
+	case meta.IsNoMatchError(err):
+		return fmt.Sprintf("%s is not installed in this cluster (%s API not found)", gvk.Kind, gvk.Group)
+	case apierrors.IsNotFound(err):
+		if namespace != "" {
+			return fmt.Sprintf("%s %s/%s not found", gvk.Kind, namespace, name)
+		}
+		return fmt.Sprintf("%s %s not found", gvk.Kind, name)
+	default:
+		return fmt.Sprintf("could not check %s %s: %v", gvk.Kind, name, err)
+	}
+}
+
 // SetupWebhookWithManager sets up the webhook with the Manager
 func (c *LanguageCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	clusterWebhookReader = mgr.GetAPIReader()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(c).
 		Complete()
@@ -0,0 +1,262 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func newIngressCluster(domain string, ingress *IngressConfig) *LanguageCluster {
+	return &LanguageCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "agents"},
+		Spec:       LanguageClusterSpec{Domain: domain, IngressConfig: ingress},
+	}
+}
+
+func newReferencedObject(group, kind, namespace, name string) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schema.GroupVersionKind{Group: group, Version: "v1", Kind: kind})
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestLanguageClusterValidateUpdateGatewayClassName(t *testing.T) {
+	conflicting := &IngressConfig{GatewayName: "public", GatewayClassName: "internal"}
+	old := newIngressCluster("ai.example.com", conflicting.DeepCopy())
+
+	// Clusters admitted before the check can still be updated while their gateway is unchanged
+	updated := newIngressCluster("ai.example.com", conflicting.DeepCopy())
+	updated.Spec.DefaultLogLevel = "debug"
+	if _, err := updated.ValidateUpdate(old); err != nil {
+		t.Errorf("Expected an update leaving the gateway alone to be allowed, got %v", err)
+	}
+
+	// Changing the gateway validates it
+	updated.Spec.IngressConfig.GatewayName = "external"
+	if _, err := updated.ValidateUpdate(old); err == nil || !strings.Contains(err.Error(), "conflicts with deprecated gatewayClassName") {
+		t.Errorf("Expected a changed conflicting gateway to be rejected, got %v", err)
+	}
+}
+
+func TestLanguageClusterDefault(t *testing.T) {
+	cluster := newIngressCluster("AI.Example.COM.", &IngressConfig{
+		GatewayName: "public",
+		TLS:         &IngressTLSConfig{Enabled: true, IssuerRef: &CertIssuerReference{Name: "letsencrypt"}},
+	})
+
+	cluster.Default()
+
+	if cluster.Spec.Domain != "ai.example.com" {
+		t.Errorf("Expected normalized domain, got %q", cluster.Spec.Domain)
+	}
+	if cluster.Spec.IngressConfig.GatewayNamespace != "agents" {
+		t.Errorf("Expected gatewayNamespace to default to the cluster namespace, got %q", cluster.Spec.IngressConfig.GatewayNamespace)
+	}
+	issuer := cluster.Spec.IngressConfig.TLS.IssuerRef
+	if issuer.Kind != "ClusterIssuer" || issuer.Group != "cert-manager.io" {
+		t.Errorf("Expected issuerRef to default to a cert-manager ClusterIssuer, got %+v", issuer)
+	}
+}
+
+func TestLanguageClusterValidateDomainAndIngress(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *LanguageCluster
+		errMsg  string
+	}{
+		{name: "valid domain", cluster: newIngressCluster("ai.example.com", nil)},
+		{name: "no domain", cluster: newIngressCluster("", nil)},
+		{name: "domain with scheme", cluster: newIngressCluster("https://ai.example.com", nil), errMsg: "spec.domain"},
+		{name: "wildcard domain", cluster: newIngressCluster("*.example.com", nil), errMsg: "spec.domain"},
+		{name: "domain with underscore", cluster: newIngressCluster("ai_agents.example.com", nil), errMsg: "spec.domain"},
+		{
+			name:    "consistent gateway config",
+			cluster: newIngressCluster("ai.example.com", &IngressConfig{GatewayName: "public", GatewayNamespace: "gateways"}),
+		},
+		{
+			name:    "gateway namespace without gateway name",
+			cluster: newIngressCluster("ai.example.com", &IngressConfig{GatewayNamespace: "gateways"}),
+			errMsg:  "gatewayNamespace requires gatewayName",
+		},
+		{
+			name:    "invalid gateway namespace",
+			cluster: newIngressCluster("ai.example.com", &IngressConfig{GatewayName: "public", GatewayNamespace: "Gateways"}),
+			errMsg:  "gatewayNamespace",
+		},
+		{
+			name:    "gateway name conflicts with deprecated gatewayClassName",
+			cluster: newIngressCluster("ai.example.com", &IngressConfig{GatewayName: "public", GatewayClassName: "internal"}),
+			errMsg:  "conflicts with deprecated gatewayClassName",
+		},
+		{
+			name: "issuer without name",
+			cluster: newIngressCluster("ai.example.com", &IngressConfig{
+				TLS: &IngressTLSConfig{Enabled: true, IssuerRef: &CertIssuerReference{Kind: "ClusterIssuer"}},
+			}),
+			errMsg: "tls.issuerRef.name is required",
+		},
+		{
+			name: "issuer with unknown kind",
+			cluster: newIngressCluster("ai.example.com", &IngressConfig{
+				TLS: &IngressTLSConfig{Enabled: true, IssuerRef: &CertIssuerReference{Name: "letsencrypt", Kind: "Certificate"}},
+			}),
+			errMsg: "tls.issuerRef.kind",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.cluster.ValidateCreate()
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestLanguageClusterReferenceWarnings(t *testing.T) {
+	scheme := runtime.NewScheme()
+	if err := AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to build scheme: %v", err)
+	}
+
+	tls := func(kind, name string) *IngressTLSConfig {
+		return &IngressTLSConfig{Enabled: true, IssuerRef: &CertIssuerReference{Name: name, Kind: kind}}
+	}
+
+	tests := []struct {
+		name           string
+		ingress        *IngressConfig
+		apiMissing     bool
+		expectWarnings []string
+	}{
+		{
+			name:    "existing issuer and gateway",
+			ingress: &IngressConfig{GatewayName: "public", GatewayNamespace: "gateways", TLS: tls("ClusterIssuer", "letsencrypt")},
+		},
+		{
+			name:    "existing namespaced issuer",
+			ingress: &IngressConfig{TLS: tls("Issuer", "team-issuer")},
+		},
+		{
+			name:           "missing cluster issuer",
+			ingress:        &IngressConfig{TLS: tls("ClusterIssuer", "staging")},
+			expectWarnings: []string{"ClusterIssuer staging not found"},
+		},
+		{
+			name:           "issuer in another namespace",
+			ingress:        &IngressConfig{TLS: tls("Issuer", "letsencrypt")},
+			expectWarnings: []string{"Issuer agents/letsencrypt not found"},
+		},
+		{
+			name:           "missing gateway",
+			ingress:        &IngressConfig{GatewayName: "private", GatewayNamespace: "gateways"},
+			expectWarnings: []string{"Gateway gateways/private not found"},
+		},
+		{
+			name:           "cert-manager not installed",
+			ingress:        &IngressConfig{TLS: tls("ClusterIssuer", "letsencrypt")},
+			apiMissing:     true,
+			expectWarnings: []string{"ClusterIssuer is not installed"},
+		},
+		{
+			name:           "deprecated gatewayClassName",
+			ingress:        &IngressConfig{GatewayClassName: "public"},
+			expectWarnings: []string{"gatewayClassName is deprecated"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			builder := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				newReferencedObject("cert-manager.io", "ClusterIssuer", "", "letsencrypt"),
+				newReferencedObject("cert-manager.io", "Issuer", "agents", "team-issuer"),
+				newReferencedObject("gateway.networking.k8s.io", "Gateway", "gateways", "public"),
+			)
+			if tt.apiMissing {
+				builder = builder.WithInterceptorFuncs(interceptor.Funcs{
+					Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+						gvk := obj.GetObjectKind().GroupVersionKind()
+						return &meta.NoKindMatchError{GroupKind: gvk.GroupKind(), SearchedVersions: []string{gvk.Version}}
+					},
+				})
+			}
+
+			originalReader := clusterWebhookReader
+			clusterWebhookReader = builder.Build()
+			defer func() { clusterWebhookReader = originalReader }()
+
+			cluster := newIngressCluster("ai.example.com", tt.ingress)
+			warnings, err := cluster.ValidateCreate()
+			if err != nil {
+				t.Fatalf("Expected missing references to warn rather than fail, got %v", err)
+			}
+			if len(warnings) != len(tt.expectWarnings) {
+				t.Fatalf("Expected warnings %v, got %v", tt.expectWarnings, warnings)
+			}
+			for i, expected := range tt.expectWarnings {
+				if !strings.Contains(warnings[i], expected) {
+					t.Errorf("Expected warning containing %q, got %q", expected, warnings[i])
+				}
+			}
+		})
+	}
+}
+
+func TestLanguageClusterValidateDefaultLogLevel(t *testing.T) {
+	cluster := newIngressCluster("", nil)
+	cluster.Spec.DefaultLogLevel = LogLevelDebug
+	if _, err := cluster.ValidateCreate(); err != nil {
+		t.Errorf("Expected no error for a supported log level, got %v", err)
+	}
+
+	cluster.Spec.DefaultLogLevel = "verbose"
+	if _, err := cluster.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.defaultLogLevel: unsupported log level") {
+		t.Errorf("Expected an unsupported log level error, got %v", err)
+	}
+}
+
+func TestLanguageClusterValidateDefaultEgress(t *testing.T) {
+	cluster := newIngressCluster("", nil)
+	cluster.Spec.DefaultEgress = []NetworkRule{{To: &NetworkPeer{DNS: []string{"api.openai.com"}}}}
+	if _, err := cluster.ValidateCreate(); err != nil {
+		t.Errorf("Expected no error for a valid default egress, got %v", err)
+	}
+
+	cluster.Spec.DefaultEgress = []NetworkRule{{To: &NetworkPeer{CIDR: "10.0.0.0/33"}}}
+	if _, err := cluster.ValidateCreate(); err == nil || !strings.Contains(err.Error(), "spec.defaultEgress[0].to.cidr") {
+		t.Errorf("Expected an invalid CIDR error, got %v", err)
+	}
+}
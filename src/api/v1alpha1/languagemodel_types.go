@@ -4,6 +4,21 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// AnthropicMessagesAPIAnnotation makes an anthropic LanguageModel with a custom spec.endpoint
+// synthesize with the native Anthropic Messages API when set to "true". Without it, custom
+// endpoints keep the OpenAI-compatible chat completions API, as proxies in front of Anthropic
+// often only speak that.
+const AnthropicMessagesAPIAnnotation = "langop.io/anthropic-messages-api"
+
+// MaxModelTemperature returns the highest sampling temperature a provider accepts: 1.0 for
+// anthropic, 2.0 for every other provider
+func MaxModelTemperature(provider string) float64 {
+	if provider == "anthropic" {
+		return 1.0
+	}
+	return 2.0
+}
+
 // LanguageModelSpec defines the desired state of LanguageModel
 type LanguageModelSpec struct {
 	// Provider specifies the LLM provider type
@@ -62,6 +77,13 @@ type LanguageModelSpec struct {
 	// +optional
 	CostTracking *CostTrackingSpec `json:"costTracking,omitempty"`
 
+	// Capabilities declares what the model supports (tool-calling, vision, json-mode), overriding
+	// the operator's table of well-known models. Agents requiring capabilities the model lacks
+	// are rejected at admission.
+	// +kubebuilder:validation:items:Enum=tool-calling;vision;json-mode
+	// +optional
+	Capabilities []string `json:"capabilities,omitempty"`
+
 	// Regions specifies which regions this model is deployed in (for multi-region)
 	// +optional
 	Regions []RegionSpec `json:"regions,omitempty"`
@@ -94,6 +116,12 @@ type ProviderConfiguration struct {
 	// +optional
 	MaxTokens *int32 `json:"maxTokens,omitempty"`
 
+	// ContextWindow is the model's context window in tokens. Synthesis prompts are trimmed to
+	// leave maxTokens of it for the response. Defaults to the known window of well-known models.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	ContextWindow *int32 `json:"contextWindow,omitempty"`
+
 	// Temperature controls randomness (0.0 to 2.0)
 	// +optional
 	Temperature *float64 `json:"temperature,omitempty"`
@@ -363,6 +391,10 @@ type LanguageModelStatus struct {
 	// +optional
 	LastHealthCheck *metav1.Time `json:"lastHealthCheck,omitempty"`
 
+	// HealthCheckFailures counts consecutive failed health checks, backing off the check interval
+	// +optional
+	HealthCheckFailures int32 `json:"healthCheckFailures,omitempty"`
+
 	// EndpointStatus shows status of each load-balanced endpoint
 	// +optional
 	EndpointStatus []EndpointStatusSpec `json:"endpointStatus,omitempty"`
@@ -379,6 +411,10 @@ type LanguageModelStatus struct {
 	// +optional
 	CostMetrics *CostMetrics `json:"costMetrics,omitempty"`
 
+	// RateLimit reports usage against spec.rateLimits, observed from telemetry
+	// +optional
+	RateLimit *RateLimitStatus `json:"rateLimit,omitempty"`
+
 	// LastUpdateTime is the last time the status was updated
 	// +optional
 	LastUpdateTime *metav1.Time `json:"lastUpdateTime,omitempty"`
@@ -469,6 +505,32 @@ type ModelMetrics struct {
 	P99Latency *int32 `json:"p99Latency,omitempty"`
 }
 
+// RateLimitStatus reports a model's usage against its per-minute rate limits
+type RateLimitStatus struct {
+	// CurrentUsage is the usage observed over the last minute
+	// +optional
+	CurrentUsage RateLimitUsage `json:"currentUsage,omitempty"`
+
+	// ObservedTime is when CurrentUsage was observed
+	// +optional
+	ObservedTime *metav1.Time `json:"observedTime,omitempty"`
+
+	// ResetTime is when the model's rate limit buckets are full again if no further requests are made
+	// +optional
+	ResetTime *metav1.Time `json:"resetTime,omitempty"`
+}
+
+// RateLimitUsage counts requests and tokens over a one-minute window
+type RateLimitUsage struct {
+	// Requests is the number of requests
+	// +optional
+	Requests int64 `json:"requests,omitempty"`
+
+	// Tokens is the number of tokens processed
+	// +optional
+	Tokens int64 `json:"tokens,omitempty"`
+}
+
 // CostMetrics contains cost tracking data
 type CostMetrics struct {
 	// TotalCost is the total cost incurred
@@ -0,0 +1,81 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// LanguageModelGrantSpec defines which namespaces may reference the LanguageModels in the
+// grant's namespace. It is modeled on the Gateway API ReferenceGrant: the grant lives in the
+// namespace of the referenced models, so only that namespace can open them up.
+type LanguageModelGrantSpec struct {
+	// From lists the namespaces whose agents may reference the models
+	// +kubebuilder:validation:MinItems=1
+	From []ModelGrantFrom `json:"from"`
+
+	// To lists the models that may be referenced. When empty, every model in the grant's
+	// namespace may be referenced.
+	// +optional
+	To []ModelGrantTo `json:"to,omitempty"`
+}
+
+// ModelGrantFrom identifies a namespace whose agents are granted access
+type ModelGrantFrom struct {
+	// Namespace is the namespace of the referencing agents
+	// +kubebuilder:validation:MinLength=1
+	Namespace string `json:"namespace"`
+}
+
+// ModelGrantTo identifies a LanguageModel access is granted to
+type ModelGrantTo struct {
+	// Name is the name of the LanguageModel in the grant's namespace
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// Permits reports whether the grant allows agents in namespace to reference the model named model
+func (s *LanguageModelGrantSpec) Permits(namespace, model string) bool {
+	fromAllowed := false
+	for _, from := range s.From {
+		if from.Namespace == namespace {
+			fromAllowed = true
+			break
+		}
+	}
+	if !fromAllowed {
+		return false
+	}
+	if len(s.To) == 0 {
+		return true
+	}
+	for _, to := range s.To {
+		if to.Name == model {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Namespaced,shortName=lmgrant
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// LanguageModelGrant is the Schema for the languagemodelgrants API
+type LanguageModelGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec LanguageModelGrantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// LanguageModelGrantList contains a list of LanguageModelGrant
+type LanguageModelGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []LanguageModelGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&LanguageModelGrant{}, &LanguageModelGrantList{})
+}
@@ -81,6 +81,12 @@ type LanguagePersonaSpec struct {
 	ParentPersona *PersonaReference `json:"parentPersona,omitempty"`
 }
 
+// Defaults of spec.tone and spec.language for personas that leave them unset
+const (
+	DefaultPersonaTone     = "professional"
+	DefaultPersonaLanguage = "en"
+)
+
 // PersonaRule defines a conditional behavior rule
 type PersonaRule struct {
 	// Name is a unique identifier for this rule
@@ -180,6 +186,14 @@ type ToolPreferencesSpec struct {
 	ExplainToolUse bool `json:"explainToolUse,omitempty"`
 }
 
+// Tool usage strategies for spec.toolPreferences.strategy
+const (
+	ToolStrategyConservative = "conservative"
+	ToolStrategyBalanced     = "balanced"
+	ToolStrategyAggressive   = "aggressive"
+	ToolStrategyMinimal      = "minimal"
+)
+
 // KnowledgeSourceSpec references an external knowledge base
 type KnowledgeSourceSpec struct {
 	// Name is the knowledge source identifier
@@ -0,0 +1,209 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+//+kubebuilder:webhook:path=/mutate-langop-io-v1alpha1-languagepersona,mutating=true,failurePolicy=fail,sideEffects=None,groups=langop.io,resources=languagepersonas,verbs=create;update,versions=v1alpha1,name=mlanguagepersona.kb.io,admissionReviewVersions=v1
+//+kubebuilder:webhook:path=/validate-langop-io-v1alpha1-languagepersona,mutating=false,failurePolicy=fail,sideEffects=None,groups=langop.io,resources=languagepersonas,verbs=create;update,versions=v1alpha1,name=vlanguagepersona.kb.io,admissionReviewVersions=v1
+
+var _ webhook.Defaulter = &LanguagePersona{}
+var _ webhook.Validator = &LanguagePersona{}
+
+// Default implements webhook.Defaulter
+func (p *LanguagePersona) Default() {
+	if p.Spec.Tone == "" {
+		p.Spec.Tone = DefaultPersonaTone
+	}
+	if p.Spec.Language == "" {
+		p.Spec.Language = DefaultPersonaLanguage
+	}
+}
+
+// ValidateCreate implements webhook.Validator
+func (p *LanguagePersona) ValidateCreate() (admission.Warnings, error) {
+	return nil, p.validate()
+}
+
+// ValidateUpdate implements webhook.Validator
+func (p *LanguagePersona) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+	return nil, p.validate()
+}
+
+// ValidateDelete implements webhook.Validator
+func (p *LanguagePersona) ValidateDelete() (admission.Warnings, error) {
+	return nil, nil
+}
+
+func (p *LanguagePersona) validate() error {
+	if errs := p.ValidateSpec(); len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return nil
+}
+
+// ValidateSpec returns the problems with the persona's response format, tool preferences, and
+// constraints. The persona controller runs it too, so personas admitted without the webhook
+// don't become Ready with settings agents can't apply.
+func (p *LanguagePersona) ValidateSpec() []string {
+	var errs []string
+
+	if format := p.Spec.ResponseFormat; format != nil {
+		switch format.Type {
+		case "", "text", "markdown", "json", "structured", "list", "table":
+		default:
+			errs = append(errs, fmt.Sprintf("spec.responseFormat.type: unsupported type %q, must be one of text, markdown, json, structured, list, table", format.Type))
+		}
+		if format.MaxLength != nil && *format.MaxLength < 0 {
+			errs = append(errs, fmt.Sprintf("spec.responseFormat.maxLength: must be non-negative, got %d", *format.MaxLength))
+		}
+		if format.Schema != "" {
+			if err := validateResponseSchema(format.Schema); err != nil {
+				errs = append(errs, fmt.Sprintf("spec.responseFormat.schema: %v", err))
+			}
+		}
+	}
+
+	if prefs := p.Spec.ToolPreferences; prefs != nil {
+		switch prefs.Strategy {
+		case "", ToolStrategyConservative, ToolStrategyBalanced, ToolStrategyAggressive, ToolStrategyMinimal:
+		default:
+			errs = append(errs, fmt.Sprintf("spec.toolPreferences.strategy: unsupported strategy %q, must be one of conservative, balanced, aggressive, minimal", prefs.Strategy))
+		}
+	}
+
+	if constraints := p.Spec.Constraints; constraints != nil {
+		limits := []struct {
+			field string
+			value *int32
+		}{
+			{"maxResponseTokens", constraints.MaxResponseTokens},
+			{"maxToolCalls", constraints.MaxToolCalls},
+			{"maxKnowledgeQueries", constraints.MaxKnowledgeQueries},
+		}
+		for _, limit := range limits {
+			if limit.value != nil && *limit.value < 0 {
+				errs = append(errs, fmt.Sprintf("spec.constraints.%s: must be non-negative, got %d", limit.field, *limit.value))
+			}
+		}
+		if constraints.ResponseTimeout != "" {
+			if err := validatePositiveDuration(constraints.ResponseTimeout); err != nil {
+				errs = append(errs, fmt.Sprintf("spec.constraints.responseTimeout: %v", err))
+			}
+		}
+	}
+
+	return errs
+}
+
+// IsStructuredResponseFormat reports whether the response format asks the model for
+// machine-readable output, which needs a model that can be constrained to emit JSON
+func IsStructuredResponseFormat(format *ResponseFormatSpec) bool {
+	return format != nil && (format.Type == "json" || format.Type == "structured")
+}
+
+// jsonSchemaTypes are the values the JSON schema type keyword accepts
+var jsonSchemaTypes = map[string]bool{
+	"array": true, "boolean": true, "integer": true, "null": true, "number": true, "object": true, "string": true,
+}
+
+// validateResponseSchema checks that schema is a JSON schema document. Only the keywords
+// structured output relies on are checked: type, properties, items, and required.
+func validateResponseSchema(schema string) error {
+	var document map[string]interface{}
+	if err := json.Unmarshal([]byte(schema), &document); err != nil {
+		return fmt.Errorf("must be a JSON schema object: %v", err)
+	}
+	return validateSchemaNode(document, "")
+}
+
+func validateSchemaNode(node map[string]interface{}, path string) error {
+	if value, ok := node["type"]; ok {
+		types, isList := value.([]interface{})
+		if !isList {
+			types = []interface{}{value}
+		}
+		for _, t := range types {
+			name, isString := t.(string)
+			if !isString || !jsonSchemaTypes[name] {
+				return fmt.Errorf("%stype: unsupported type %v, must be one of array, boolean, integer, null, number, object, string", path, t)
+			}
+		}
+	}
+
+	if value, ok := node["properties"]; ok {
+		properties, isObject := value.(map[string]interface{})
+		if !isObject {
+			return fmt.Errorf("%sproperties: must be an object", path)
+		}
+		names := make([]string, 0, len(properties))
+		for name := range properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			property, isObject := properties[name].(map[string]interface{})
+			if !isObject {
+				return fmt.Errorf("%sproperties.%s: must be a schema object", path, name)
+			}
+			if err := validateSchemaNode(property, fmt.Sprintf("%sproperties.%s.", path, name)); err != nil {
+				return err
+			}
+		}
+	}
+
+	if value, ok := node["items"]; ok {
+		items, isObject := value.(map[string]interface{})
+		if !isObject {
+			return fmt.Errorf("%sitems: must be a schema object", path)
+		}
+		if err := validateSchemaNode(items, path+"items."); err != nil {
+			return err
+		}
+	}
+
+	if value, ok := node["required"]; ok {
+		required, isList := value.([]interface{})
+		if !isList {
+			return fmt.Errorf("%srequired: must be a list of property names", path)
+		}
+		for _, name := range required {
+			if _, isString := name.(string); !isString {
+				return fmt.Errorf("%srequired: must be a list of property names, got %v", path, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// SetupWebhookWithManager sets up the webhook with the Manager
+func (p *LanguagePersona) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(p).
+		Complete()
+}
@@ -0,0 +1,179 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"strings"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestPersona(spec LanguagePersonaSpec) *LanguagePersona {
+	spec.DisplayName = "Support"
+	spec.Description = "Answers support questions"
+	spec.SystemPrompt = "You are a support engineer."
+	return &LanguagePersona{
+		ObjectMeta: metav1.ObjectMeta{Name: "support", Namespace: "default"},
+		Spec:       spec,
+	}
+}
+
+func TestLanguagePersonaDefault(t *testing.T) {
+	persona := newTestPersona(LanguagePersonaSpec{})
+	persona.Default()
+	if persona.Spec.Tone != DefaultPersonaTone {
+		t.Errorf("Expected tone to default to %q, got %q", DefaultPersonaTone, persona.Spec.Tone)
+	}
+	if persona.Spec.Language != DefaultPersonaLanguage {
+		t.Errorf("Expected language to default to %q, got %q", DefaultPersonaLanguage, persona.Spec.Language)
+	}
+
+	persona = newTestPersona(LanguagePersonaSpec{Tone: "casual", Language: "de"})
+	persona.Default()
+	if persona.Spec.Tone != "casual" || persona.Spec.Language != "de" {
+		t.Errorf("Expected explicit tone and language to be kept, got %q and %q", persona.Spec.Tone, persona.Spec.Language)
+	}
+}
+
+func TestLanguagePersonaValidate(t *testing.T) {
+	negative := int32(-1)
+	limit := int32(500)
+
+	tests := []struct {
+		name   string
+		spec   LanguagePersonaSpec
+		errMsg string
+	}{
+		{name: "minimal persona"},
+		{
+			name: "fully specified persona",
+			spec: LanguagePersonaSpec{
+				ResponseFormat:  &ResponseFormatSpec{Type: "markdown", MaxLength: &limit},
+				ToolPreferences: &ToolPreferencesSpec{Strategy: ToolStrategyConservative},
+				Constraints: &PersonaConstraints{
+					MaxResponseTokens:   &limit,
+					MaxToolCalls:        &limit,
+					MaxKnowledgeQueries: &limit,
+					ResponseTimeout:     "90s",
+				},
+			},
+		},
+		{
+			name:   "invalid response timeout",
+			spec:   LanguagePersonaSpec{Constraints: &PersonaConstraints{ResponseTimeout: "soon"}},
+			errMsg: "spec.constraints.responseTimeout",
+		},
+		{
+			name:   "zero response timeout",
+			spec:   LanguagePersonaSpec{Constraints: &PersonaConstraints{ResponseTimeout: "0s"}},
+			errMsg: "spec.constraints.responseTimeout: must be positive",
+		},
+		{
+			name:   "negative max response tokens",
+			spec:   LanguagePersonaSpec{Constraints: &PersonaConstraints{MaxResponseTokens: &negative}},
+			errMsg: "spec.constraints.maxResponseTokens: must be non-negative",
+		},
+		{
+			name:   "negative max tool calls",
+			spec:   LanguagePersonaSpec{Constraints: &PersonaConstraints{MaxToolCalls: &negative}},
+			errMsg: "spec.constraints.maxToolCalls",
+		},
+		{
+			name:   "negative max length",
+			spec:   LanguagePersonaSpec{ResponseFormat: &ResponseFormatSpec{MaxLength: &negative}},
+			errMsg: "spec.responseFormat.maxLength",
+		},
+		{
+			name:   "unknown response format type",
+			spec:   LanguagePersonaSpec{ResponseFormat: &ResponseFormatSpec{Type: "yaml"}},
+			errMsg: "spec.responseFormat.type",
+		},
+		{
+			name: "json response format with schema",
+			spec: LanguagePersonaSpec{ResponseFormat: &ResponseFormatSpec{
+				Type:   "json",
+				Schema: `{"type": "object", "properties": {"answer": {"type": "string"}, "sources": {"type": "array", "items": {"type": "string"}}}, "required": ["answer"]}`,
+			}},
+		},
+		{
+			name:   "response schema that isn't JSON",
+			spec:   LanguagePersonaSpec{ResponseFormat: &ResponseFormatSpec{Type: "json", Schema: "type: object"}},
+			errMsg: "spec.responseFormat.schema: must be a JSON schema object",
+		},
+		{
+			name:   "response schema that isn't an object",
+			spec:   LanguagePersonaSpec{ResponseFormat: &ResponseFormatSpec{Type: "structured", Schema: `["answer"]`}},
+			errMsg: "spec.responseFormat.schema: must be a JSON schema object",
+		},
+		{
+			name: "response schema with unknown nested type",
+			spec: LanguagePersonaSpec{ResponseFormat: &ResponseFormatSpec{
+				Type:   "json",
+				Schema: `{"type": "object", "properties": {"score": {"type": "float"}}}`,
+			}},
+			errMsg: "spec.responseFormat.schema: properties.score.type: unsupported type float",
+		},
+		{
+			name:   "response schema with malformed required",
+			spec:   LanguagePersonaSpec{ResponseFormat: &ResponseFormatSpec{Type: "json", Schema: `{"type": "object", "required": "answer"}`}},
+			errMsg: "spec.responseFormat.schema: required: must be a list of property names",
+		},
+		{
+			name:   "response schema with malformed items",
+			spec:   LanguagePersonaSpec{ResponseFormat: &ResponseFormatSpec{Type: "json", Schema: `{"type": "array", "items": "string"}`}},
+			errMsg: "spec.responseFormat.schema: items: must be a schema object",
+		},
+		{
+			name:   "unknown tool strategy",
+			spec:   LanguagePersonaSpec{ToolPreferences: &ToolPreferencesSpec{Strategy: "reckless"}},
+			errMsg: `unsupported strategy "reckless"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			persona := newTestPersona(tt.spec)
+			_, createErr := persona.ValidateCreate()
+			_, updateErr := persona.ValidateUpdate(persona.DeepCopy())
+			for _, err := range []error{createErr, updateErr} {
+				if tt.errMsg == "" {
+					if err != nil {
+						t.Errorf("Expected no error, got %v", err)
+					}
+					continue
+				}
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguagePersonaValidateReportsEveryProblem(t *testing.T) {
+	negative := int32(-5)
+	persona := newTestPersona(LanguagePersonaSpec{
+		ToolPreferences: &ToolPreferencesSpec{Strategy: "reckless"},
+		Constraints:     &PersonaConstraints{MaxResponseTokens: &negative, ResponseTimeout: "later"},
+	})
+
+	errs := persona.ValidateSpec()
+	if len(errs) != 3 {
+		t.Fatalf("Expected 3 validation errors, got %d: %v", len(errs), errs)
+	}
+}
@@ -81,7 +81,9 @@ type LanguageToolSpec struct {
 	// DeploymentMode specifies how this tool should be deployed
 	// - "service": Deployed as a standalone Deployment+Service (default, shared across agents)
 	// - "sidecar": Deployed as a sidecar container in each agent pod (dedicated, with workspace access)
-	// +kubebuilder:validation:Enum=service;sidecar
+	// - "cluster": Deployed as a single Deployment+Service in the cluster's namespace, shared by agents in
+	//   every namespace and only running while at least one agent references it
+	// +kubebuilder:validation:Enum=service;sidecar;cluster
 	// +kubebuilder:default=service
 	// +optional
 	DeploymentMode string `json:"deploymentMode,omitempty"`
@@ -250,6 +252,11 @@ type LanguageToolStatus struct {
 	// +optional
 	ToolSchemas []ToolSchema `json:"toolSchemas,omitempty"`
 
+	// ReferencingAgents lists the agents, as namespace/name, that reference a cluster mode tool.
+	// The shared server is removed when none remain.
+	// +optional
+	ReferencingAgents []string `json:"referencingAgents,omitempty"`
+
 	// ReadyReplicas is the number of pods ready and passing health checks
 	// +optional
 	ReadyReplicas int32 `json:"readyReplicas,omitempty"`
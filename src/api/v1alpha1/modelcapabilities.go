@@ -0,0 +1,92 @@
+package v1alpha1
+
+import (
+	"sort"
+	"strings"
+)
+
+// Model capabilities an agent can require with spec.requiredModelCapabilities
+const (
+	// ModelCapabilityToolCalling means the model can call tools (functions)
+	ModelCapabilityToolCalling = "tool-calling"
+
+	// ModelCapabilityVision means the model accepts image inputs
+	ModelCapabilityVision = "vision"
+
+	// ModelCapabilityJSONMode means the model can be constrained to emit valid JSON
+	ModelCapabilityJSONMode = "json-mode"
+)
+
+// knownModelCapabilities is the capability table of well-known models, matched by model name
+// prefix. More specific prefixes must come before the prefixes they extend.
+var knownModelCapabilities = []struct {
+	prefix       string
+	capabilities []string
+}{
+	{"gpt-4o", []string{ModelCapabilityToolCalling, ModelCapabilityVision, ModelCapabilityJSONMode}},
+	{"gpt-4.1", []string{ModelCapabilityToolCalling, ModelCapabilityVision, ModelCapabilityJSONMode}},
+	{"gpt-4-turbo", []string{ModelCapabilityToolCalling, ModelCapabilityVision, ModelCapabilityJSONMode}},
+	{"gpt-4-vision", []string{ModelCapabilityVision}},
+	{"gpt-4", []string{ModelCapabilityToolCalling}},
+	{"gpt-3.5-turbo-instruct", []string{}},
+	{"gpt-3.5-turbo", []string{ModelCapabilityToolCalling, ModelCapabilityJSONMode}},
+	{"o1-mini", []string{}},
+	{"o1", []string{ModelCapabilityToolCalling, ModelCapabilityVision, ModelCapabilityJSONMode}},
+	{"o3", []string{ModelCapabilityToolCalling, ModelCapabilityVision, ModelCapabilityJSONMode}},
+	{"claude-2", []string{}},
+	{"claude-instant", []string{}},
+	{"claude-3", []string{ModelCapabilityToolCalling, ModelCapabilityVision}},
+	{"claude-", []string{ModelCapabilityToolCalling, ModelCapabilityVision}},
+	{"gemini-1.0-pro-vision", []string{ModelCapabilityVision}},
+	{"gemini-", []string{ModelCapabilityToolCalling, ModelCapabilityVision, ModelCapabilityJSONMode}},
+	{"text-davinci", []string{}},
+}
+
+// IsKnownModelCapability reports whether name is a capability agents can require
+func IsKnownModelCapability(name string) bool {
+	switch name {
+	case ModelCapabilityToolCalling, ModelCapabilityVision, ModelCapabilityJSONMode:
+		return true
+	}
+	return false
+}
+
+// ModelCapabilities returns the capabilities of a model and whether they are known. Capabilities
+// declared in spec.capabilities take precedence over the built-in table of well-known models.
+func ModelCapabilities(model *LanguageModel) (capabilities []string, known bool) {
+	if model.Spec.Capabilities != nil {
+		return model.Spec.Capabilities, true
+	}
+
+	// Provider-qualified names such as "openai/gpt-4o" or Bedrock's "anthropic.claude-3-haiku"
+	// are matched by the part after the provider
+	name := strings.ToLower(model.Spec.ModelName)
+	for {
+		for _, entry := range knownModelCapabilities {
+			if strings.HasPrefix(name, entry.prefix) {
+				return entry.capabilities, true
+			}
+		}
+		i := strings.IndexAny(name, "/.")
+		if i < 0 {
+			return nil, false
+		}
+		name = name[i+1:]
+	}
+}
+
+// MissingModelCapabilities returns the required capabilities the model lacks, sorted
+func MissingModelCapabilities(capabilities, required []string) []string {
+	have := make(map[string]bool, len(capabilities))
+	for _, capability := range capabilities {
+		have[capability] = true
+	}
+	var missing []string
+	for _, capability := range required {
+		if !have[capability] {
+			missing = append(missing, capability)
+		}
+	}
+	sort.Strings(missing)
+	return missing
+}
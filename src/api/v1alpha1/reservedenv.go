@@ -0,0 +1,70 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+// reservedAgentEnvVars are the environment variables the operator sets on every agent container.
+// spec.env may not set them, since the agent runtime relies on the operator's values.
+var reservedAgentEnvVars = map[string]bool{
+	"CONFIG_PATH":           true,
+	"AGENT_NAME":            true,
+	"AGENT_NAMESPACE":       true,
+	"AGENT_MODE":            true,
+	"AGENT_GOAL":            true,
+	"AGENT_INSTRUCTIONS":    true,
+	"PERSONA_NAME":          true,
+	"PERSONA_TONE":          true,
+	"PERSONA_LANGUAGE":      true,
+	"MODEL_ENDPOINTS":       true,
+	"MODEL_ROLES":           true,
+	"LLM_MODEL":             true,
+	"MODEL_REQUEST_TIMEOUT": true,
+	"OPENAI_API_KEY":        true,
+	"HTTPX_NO_IO_URING":     true,
+	"MCP_SERVERS":           true,
+	// Failure injection is gated by the operator; spec.env must not bypass the gates
+	"AGENT_CHAOS_FAILURE_RATE": true,
+	// OpenTelemetry settings the operator derives from its own environment and spec.telemetry.
+	// Other OTEL_ variables are left to spec.env.
+	"OTEL_EXPORTER_OTLP_ENDPOINT": true,
+	"OTEL_EXPORTER_OTLP_PROTOCOL": true,
+	"OTEL_TRACES_EXPORTER":        true,
+	"OTEL_LOGS_EXPORTER":          true,
+	"OTEL_METRICS_EXPORTER":       true,
+	"OTEL_TRACES_SAMPLER":         true,
+	"OTEL_TRACES_SAMPLER_ARG":     true,
+	"OTEL_SERVICE_NAME":           true,
+	"OTEL_RESOURCE_ATTRIBUTES":    true,
+}
+
+// IsReservedAgentEnvVar reports whether name is an environment variable the operator manages
+// on agent containers
+func IsReservedAgentEnvVar(name string) bool {
+	return reservedAgentEnvVars[name]
+}
+
+// logLevelAgentEnvVars carry spec.logLevel to the runtime. Agents that don't set spec.logLevel may
+// set them in spec.env instead.
+var logLevelAgentEnvVars = map[string]bool{
+	"LOG_LEVEL":       true,
+	"AGENT_LOG_LEVEL": true,
+}
+
+// IsLogLevelAgentEnvVar reports whether name is an environment variable the operator sets from
+// spec.logLevel, reserved only on agents that set it
+func IsLogLevelAgentEnvVar(name string) bool {
+	return logLevelAgentEnvVars[name]
+}
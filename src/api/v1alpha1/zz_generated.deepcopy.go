@@ -26,6 +26,21 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentChaosSpec) DeepCopyInto(out *AgentChaosSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentChaosSpec.
+func (in *AgentChaosSpec) DeepCopy() *AgentChaosSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentChaosSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentContentFilterSpec) DeepCopyInto(out *AgentContentFilterSpec) {
 	*out = *in
@@ -70,6 +85,49 @@ func (in *AgentCostMetrics) DeepCopy() *AgentCostMetrics {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentImageUpgradeStatus) DeepCopyInto(out *AgentImageUpgradeStatus) {
+	*out = *in
+	if in.StartedAt != nil {
+		in, out := &in.StartedAt, &out.StartedAt
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletedAt != nil {
+		in, out := &in.CompletedAt, &out.CompletedAt
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentImageUpgradeStatus.
+func (in *AgentImageUpgradeStatus) DeepCopy() *AgentImageUpgradeStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentImageUpgradeStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentLifecycleSpec) DeepCopyInto(out *AgentLifecycleSpec) {
+	*out = *in
+	if in.PreStopDelaySeconds != nil {
+		in, out := &in.PreStopDelaySeconds, &out.PreStopDelaySeconds
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentLifecycleSpec.
+func (in *AgentLifecycleSpec) DeepCopy() *AgentLifecycleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentLifecycleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentMetrics) DeepCopyInto(out *AgentMetrics) {
 	*out = *in
@@ -160,6 +218,108 @@ func (in *AgentRateLimitSpec) DeepCopy() *AgentRateLimitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentReference) DeepCopyInto(out *AgentReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentReference.
+func (in *AgentReference) DeepCopy() *AgentReference {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentSynthesisQuota) DeepCopyInto(out *AgentSynthesisQuota) {
+	*out = *in
+	if in.MaxCostPerDay != nil {
+		in, out := &in.MaxCostPerDay, &out.MaxCostPerDay
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxAttemptsPerDay != nil {
+		in, out := &in.MaxAttemptsPerDay, &out.MaxAttemptsPerDay
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSynthesisQuota.
+func (in *AgentSynthesisQuota) DeepCopy() *AgentSynthesisQuota {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentSynthesisQuota)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentSynthesisQuotaStatus) DeepCopyInto(out *AgentSynthesisQuotaStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSynthesisQuotaStatus.
+func (in *AgentSynthesisQuotaStatus) DeepCopy() *AgentSynthesisQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentSynthesisQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentSynthesisSettings) DeepCopyInto(out *AgentSynthesisSettings) {
+	*out = *in
+	if in.Temperature != nil {
+		in, out := &in.Temperature, &out.Temperature
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSynthesisSettings.
+func (in *AgentSynthesisSettings) DeepCopy() *AgentSynthesisSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentSynthesisSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentTelemetrySpec) DeepCopyInto(out *AgentTelemetrySpec) {
+	*out = *in
+	if in.ResourceAttributes != nil {
+		in, out := &in.ResourceAttributes, &out.ResourceAttributes
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentTelemetrySpec.
+func (in *AgentTelemetrySpec) DeepCopy() *AgentTelemetrySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentTelemetrySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CachingSpec) DeepCopyInto(out *CachingSpec) {
 	*out = *in
@@ -195,6 +355,26 @@ func (in *CertIssuerReference) DeepCopy() *CertIssuerReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ClusterDefaults) DeepCopyInto(out *ClusterDefaults) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ClusterDefaults.
+func (in *ClusterDefaults) DeepCopy() *ClusterDefaults {
+	if in == nil {
+		return nil
+	}
+	out := new(ClusterDefaults)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CostMetrics) DeepCopyInto(out *CostMetrics) {
 	*out = *in
@@ -325,6 +505,26 @@ func (in *EventTriggerSpec) DeepCopy() *EventTriggerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GitRepoSeed) DeepCopyInto(out *GitRepoSeed) {
+	*out = *in
+	if in.SecretRef != nil {
+		in, out := &in.SecretRef, &out.SecretRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GitRepoSeed.
+func (in *GitRepoSeed) DeepCopy() *GitRepoSeed {
+	if in == nil {
+		return nil
+	}
+	out := new(GitRepoSeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *HealthCheckSpec) DeepCopyInto(out *HealthCheckSpec) {
 	*out = *in
@@ -494,11 +694,21 @@ func (in *LanguageAgentSpec) DeepCopyInto(out *LanguageAgentSpec) {
 		*out = make([]ToolReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.ToolSelector != nil {
+		in, out := &in.ToolSelector, &out.ToolSelector
+		*out = new(metav1.LabelSelector)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PersonaRefs != nil {
 		in, out := &in.PersonaRefs, &out.PersonaRefs
 		*out = make([]PersonaReference, len(*in))
 		copy(*out, *in)
 	}
+	if in.DependsOn != nil {
+		in, out := &in.DependsOn, &out.DependsOn
+		*out = make([]AgentReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.EventTriggers != nil {
 		in, out := &in.EventTriggers, &out.EventTriggers
 		*out = make([]EventTriggerSpec, len(*in))
@@ -516,6 +726,16 @@ func (in *LanguageAgentSpec) DeepCopyInto(out *LanguageAgentSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.DisruptionBudget != nil {
+		in, out := &in.DisruptionBudget, &out.DisruptionBudget
+		*out = new(int32)
+		**out = **in
+	}
+	if in.DeploymentStrategy != nil {
+		in, out := &in.DeploymentStrategy, &out.DeploymentStrategy
+		*out = new(UpdateStrategySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Env != nil {
 		in, out := &in.Env, &out.Env
 		*out = make([]v1.EnvVar, len(*in))
@@ -569,6 +789,16 @@ func (in *LanguageAgentSpec) DeepCopyInto(out *LanguageAgentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.StartupProbe != nil {
+		in, out := &in.StartupProbe, &out.StartupProbe
+		*out = new(v1.Probe)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Lifecycle != nil {
+		in, out := &in.Lifecycle, &out.Lifecycle
+		*out = new(AgentLifecycleSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.PodAnnotations != nil {
 		in, out := &in.PodAnnotations, &out.PodAnnotations
 		*out = make(map[string]string, len(*in))
@@ -588,6 +818,26 @@ func (in *LanguageAgentSpec) DeepCopyInto(out *LanguageAgentSpec) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Synthesis != nil {
+		in, out := &in.Synthesis, &out.Synthesis
+		*out = new(AgentSynthesisSettings)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SynthesisQuota != nil {
+		in, out := &in.SynthesisQuota, &out.SynthesisQuota
+		*out = new(AgentSynthesisQuota)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequiredModelCapabilities != nil {
+		in, out := &in.RequiredModelCapabilities, &out.RequiredModelCapabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Learning != nil {
+		in, out := &in.Learning, &out.Learning
+		*out = new(LearningSpec)
+		**out = **in
+	}
 	if in.MemoryStore != nil {
 		in, out := &in.MemoryStore, &out.MemoryStore
 		*out = new(MemoryStoreSpec)
@@ -598,6 +848,18 @@ func (in *LanguageAgentSpec) DeepCopyInto(out *LanguageAgentSpec) {
 		*out = new(AgentObservabilitySpec)
 		**out = **in
 	}
+	if in.Telemetry != nil {
+		in, out := &in.Telemetry, &out.Telemetry
+		*out = new(AgentTelemetrySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FeatureGates != nil {
+		in, out := &in.FeatureGates, &out.FeatureGates
+		*out = make(map[string]bool, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.RateLimits != nil {
 		in, out := &in.RateLimits, &out.RateLimits
 		*out = new(AgentRateLimitSpec)
@@ -608,11 +870,21 @@ func (in *LanguageAgentSpec) DeepCopyInto(out *LanguageAgentSpec) {
 		*out = new(SafetyConfigSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Chaos != nil {
+		in, out := &in.Chaos, &out.Chaos
+		*out = new(AgentChaosSpec)
+		**out = **in
+	}
 	if in.Workspace != nil {
 		in, out := &in.Workspace, &out.Workspace
 		*out = new(WorkspaceSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.WebhookRoutes != nil {
+		in, out := &in.WebhookRoutes, &out.WebhookRoutes
+		*out = make([]WebhookRouteRule, len(*in))
+		copy(*out, *in)
+	}
 	if in.Egress != nil {
 		in, out := &in.Egress, &out.Egress
 		*out = make([]NetworkRule, len(*in))
@@ -620,6 +892,11 @@ func (in *LanguageAgentSpec) DeepCopyInto(out *LanguageAgentSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.InheritClusterEgress != nil {
+		in, out := &in.InheritClusterEgress, &out.InheritClusterEgress
+		*out = new(bool)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LanguageAgentSpec.
@@ -677,11 +954,21 @@ func (in *LanguageAgentStatus) DeepCopyInto(out *LanguageAgentStatus) {
 		*out = new(SynthesisInfo)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.SynthesisQuota != nil {
+		in, out := &in.SynthesisQuota, &out.SynthesisQuota
+		*out = new(AgentSynthesisQuotaStatus)
+		**out = **in
+	}
 	if in.WebhookURLs != nil {
 		in, out := &in.WebhookURLs, &out.WebhookURLs
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.WebhookRoute != nil {
+		in, out := &in.WebhookRoute, &out.WebhookRoute
+		*out = new(WebhookRouteStatus)
+		**out = **in
+	}
 	if in.RuntimeErrors != nil {
 		in, out := &in.RuntimeErrors, &out.RuntimeErrors
 		*out = make([]RuntimeError, len(*in))
@@ -689,6 +976,45 @@ func (in *LanguageAgentStatus) DeepCopyInto(out *LanguageAgentStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.CountedUnhealthyPods != nil {
+		in, out := &in.CountedUnhealthyPods, &out.CountedUnhealthyPods
+		*out = make([]UnhealthyPodEpisode, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.DeletedModels != nil {
+		in, out := &in.DeletedModels, &out.DeletedModels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.SelectedTools != nil {
+		in, out := &in.SelectedTools, &out.SelectedTools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.DefaultPersonas != nil {
+		in, out := &in.DefaultPersonas, &out.DefaultPersonas
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UsedTools != nil {
+		in, out := &in.UsedTools, &out.UsedTools
+		*out = make([]UsedTool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.UnusedToolGrants != nil {
+		in, out := &in.UnusedToolGrants, &out.UnusedToolGrants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.UngrantedToolCalls != nil {
+		in, out := &in.UngrantedToolCalls, &out.UngrantedToolCalls
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LanguageAgentStatus.
@@ -768,6 +1094,30 @@ func (in *LanguageClusterSpec) DeepCopyInto(out *LanguageClusterSpec) {
 		*out = new(IngressConfig)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MinScheduleInterval != nil {
+		in, out := &in.MinScheduleInterval, &out.MinScheduleInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.DefaultEgress != nil {
+		in, out := &in.DefaultEgress, &out.DefaultEgress
+		*out = make([]NetworkRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.SynthesisFallbackModels != nil {
+		in, out := &in.SynthesisFallbackModels, &out.SynthesisFallbackModels
+		*out = make([]ModelReference, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Defaults != nil {
+		in, out := &in.Defaults, &out.Defaults
+		*out = new(ClusterDefaults)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LanguageClusterSpec.
@@ -790,6 +1140,11 @@ func (in *LanguageClusterStatus) DeepCopyInto(out *LanguageClusterStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ImageUpgrade != nil {
+		in, out := &in.ImageUpgrade, &out.ImageUpgrade
+		*out = new(AgentImageUpgradeStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LanguageClusterStatus.
@@ -829,6 +1184,89 @@ func (in *LanguageModel) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LanguageModelGrant) DeepCopyInto(out *LanguageModelGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LanguageModelGrant.
+func (in *LanguageModelGrant) DeepCopy() *LanguageModelGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(LanguageModelGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LanguageModelGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LanguageModelGrantList) DeepCopyInto(out *LanguageModelGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]LanguageModelGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LanguageModelGrantList.
+func (in *LanguageModelGrantList) DeepCopy() *LanguageModelGrantList {
+	if in == nil {
+		return nil
+	}
+	out := new(LanguageModelGrantList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *LanguageModelGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LanguageModelGrantSpec) DeepCopyInto(out *LanguageModelGrantSpec) {
+	*out = *in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]ModelGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]ModelGrantTo, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LanguageModelGrantSpec.
+func (in *LanguageModelGrantSpec) DeepCopy() *LanguageModelGrantSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LanguageModelGrantSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LanguageModelList) DeepCopyInto(out *LanguageModelList) {
 	*out = *in
@@ -911,6 +1349,11 @@ func (in *LanguageModelSpec) DeepCopyInto(out *LanguageModelSpec) {
 		*out = new(CostTrackingSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Regions != nil {
 		in, out := &in.Regions, &out.Regions
 		*out = make([]RegionSpec, len(*in))
@@ -975,6 +1418,11 @@ func (in *LanguageModelStatus) DeepCopyInto(out *LanguageModelStatus) {
 		*out = new(CostMetrics)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RateLimitStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.LastUpdateTime != nil {
 		in, out := &in.LastUpdateTime, &out.LastUpdateTime
 		*out = (*in).DeepCopy()
@@ -1388,6 +1836,11 @@ func (in *LanguageToolStatus) DeepCopyInto(out *LanguageToolStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.ReferencingAgents != nil {
+		in, out := &in.ReferencingAgents, &out.ReferencingAgents
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.LastUpdateTime != nil {
 		in, out := &in.LastUpdateTime, &out.LastUpdateTime
 		*out = (*in).DeepCopy()
@@ -1408,6 +1861,21 @@ func (in *LanguageToolStatus) DeepCopy() *LanguageToolStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LearningSpec) DeepCopyInto(out *LearningSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LearningSpec.
+func (in *LearningSpec) DeepCopy() *LearningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(LearningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *LoadBalancingSpec) DeepCopyInto(out *LoadBalancingSpec) {
 	*out = *in
@@ -1500,6 +1968,36 @@ func (in *ModelFallbackSpec) DeepCopy() *ModelFallbackSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelGrantFrom) DeepCopyInto(out *ModelGrantFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelGrantFrom.
+func (in *ModelGrantFrom) DeepCopy() *ModelGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelGrantTo) DeepCopyInto(out *ModelGrantTo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelGrantTo.
+func (in *ModelGrantTo) DeepCopy() *ModelGrantTo {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelGrantTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ModelLoggingSpec) DeepCopyInto(out *ModelLoggingSpec) {
 	*out = *in
@@ -1893,6 +2391,11 @@ func (in *ProviderConfiguration) DeepCopyInto(out *ProviderConfiguration) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.ContextWindow != nil {
+		in, out := &in.ContextWindow, &out.ContextWindow
+		*out = new(int32)
+		**out = **in
+	}
 	if in.Temperature != nil {
 		in, out := &in.Temperature, &out.Temperature
 		*out = new(float64)
@@ -1967,6 +2470,45 @@ func (in *RateLimitSpec) DeepCopy() *RateLimitSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitStatus) DeepCopyInto(out *RateLimitStatus) {
+	*out = *in
+	out.CurrentUsage = in.CurrentUsage
+	if in.ObservedTime != nil {
+		in, out := &in.ObservedTime, &out.ObservedTime
+		*out = (*in).DeepCopy()
+	}
+	if in.ResetTime != nil {
+		in, out := &in.ResetTime, &out.ResetTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitStatus.
+func (in *RateLimitStatus) DeepCopy() *RateLimitStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RateLimitUsage) DeepCopyInto(out *RateLimitUsage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RateLimitUsage.
+func (in *RateLimitUsage) DeepCopy() *RateLimitUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(RateLimitUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RegionSpec) DeepCopyInto(out *RegionSpec) {
 	*out = *in
@@ -2204,11 +2746,21 @@ func (in *SynthesisInfo) DeepCopyInto(out *SynthesisInfo) {
 		in, out := &in.LastSynthesisTime, &out.LastSynthesisTime
 		*out = (*in).DeepCopy()
 	}
+	if in.Temperature != nil {
+		in, out := &in.Temperature, &out.Temperature
+		*out = new(float64)
+		**out = **in
+	}
 	if in.ValidationErrors != nil {
 		in, out := &in.ValidationErrors, &out.ValidationErrors
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.PromptTrimmed != nil {
+		in, out := &in.PromptTrimmed, &out.PromptTrimmed
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SynthesisInfo.
@@ -2388,6 +2940,22 @@ func (in *TopicFrequency) DeepCopy() *TopicFrequency {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UnhealthyPodEpisode) DeepCopyInto(out *UnhealthyPodEpisode) {
+	*out = *in
+	in.NotReadySince.DeepCopyInto(&out.NotReadySince)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UnhealthyPodEpisode.
+func (in *UnhealthyPodEpisode) DeepCopy() *UnhealthyPodEpisode {
+	if in == nil {
+		return nil
+	}
+	out := new(UnhealthyPodEpisode)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *UpdateStrategySpec) DeepCopyInto(out *UpdateStrategySpec) {
 	*out = *in
@@ -2408,6 +2976,81 @@ func (in *UpdateStrategySpec) DeepCopy() *UpdateStrategySpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *UsedTool) DeepCopyInto(out *UsedTool) {
+	*out = *in
+	if in.Methods != nil {
+		in, out := &in.Methods, &out.Methods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new UsedTool.
+func (in *UsedTool) DeepCopy() *UsedTool {
+	if in == nil {
+		return nil
+	}
+	out := new(UsedTool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookRouteRule) DeepCopyInto(out *WebhookRouteRule) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookRouteRule.
+func (in *WebhookRouteRule) DeepCopy() *WebhookRouteRule {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookRouteRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WebhookRouteStatus) DeepCopyInto(out *WebhookRouteStatus) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WebhookRouteStatus.
+func (in *WebhookRouteStatus) DeepCopy() *WebhookRouteStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(WebhookRouteStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WorkspaceSeed) DeepCopyInto(out *WorkspaceSeed) {
+	*out = *in
+	if in.GitRepo != nil {
+		in, out := &in.GitRepo, &out.GitRepo
+		*out = new(GitRepoSeed)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ConfigMapRef != nil {
+		in, out := &in.ConfigMapRef, &out.ConfigMapRef
+		*out = new(v1.LocalObjectReference)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSeed.
+func (in *WorkspaceSeed) DeepCopy() *WorkspaceSeed {
+	if in == nil {
+		return nil
+	}
+	out := new(WorkspaceSeed)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 	*out = *in
@@ -2416,6 +3059,11 @@ func (in *WorkspaceSpec) DeepCopyInto(out *WorkspaceSpec) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(WorkspaceSeed)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WorkspaceSpec.
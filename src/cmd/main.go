@@ -21,6 +21,7 @@ import (
 	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 	"time"
@@ -29,24 +30,29 @@ import (
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	"k8s.io/client-go/kubernetes"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 	webhook "sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
 	"github.com/language-operator/language-operator/controllers"
+	"github.com/language-operator/language-operator/pkg/audit"
 	"github.com/language-operator/language-operator/pkg/cni"
 	registryconfig "github.com/language-operator/language-operator/pkg/config"
 	"github.com/language-operator/language-operator/pkg/learning"
 	"github.com/language-operator/language-operator/pkg/synthesis"
 	"github.com/language-operator/language-operator/pkg/telemetry"
 	"github.com/language-operator/language-operator/pkg/telemetry/adapters"
+	"github.com/language-operator/language-operator/pkg/validation"
 	//+kubebuilder:scaffold:imports
 )
 
@@ -75,6 +81,12 @@ func initializeTelemetryAdapter() telemetry.TelemetryAdapter {
 	switch strings.ToLower(adapterType) {
 	case "signoz":
 		return initializeSigNozAdapter()
+	case "prometheus":
+		return initializePrometheusAdapter()
+	case "tempo":
+		return initializeTempoAdapter()
+	case "datadog":
+		return initializeDatadogAdapter()
 	case "noop", "disabled":
 		setupLog.Info("Telemetry adapter explicitly disabled")
 		return telemetry.NewNoOpAdapter()
@@ -85,6 +97,121 @@ func initializeTelemetryAdapter() telemetry.TelemetryAdapter {
 	}
 }
 
+// initializePrometheusAdapter creates a Prometheus telemetry adapter from environment variables
+func initializePrometheusAdapter() telemetry.TelemetryAdapter {
+	endpoint := os.Getenv("TELEMETRY_ADAPTER_ENDPOINT")
+	if endpoint == "" {
+		setupLog.Error(nil, "Prometheus adapter requires TELEMETRY_ADAPTER_ENDPOINT environment variable")
+		return telemetry.NewNoOpAdapter()
+	}
+
+	// Basic auth is optional
+	username := os.Getenv("TELEMETRY_ADAPTER_USERNAME")
+	password := os.Getenv("TELEMETRY_ADAPTER_PASSWORD")
+
+	timeout := 30 * time.Second
+	if timeoutStr := os.Getenv("TELEMETRY_ADAPTER_TIMEOUT"); timeoutStr != "" {
+		if parsedTimeout, err := time.ParseDuration(timeoutStr); err == nil {
+			timeout = parsedTimeout
+		} else {
+			setupLog.Error(err, "Invalid TELEMETRY_ADAPTER_TIMEOUT, using default 30s", "value", timeoutStr)
+		}
+	}
+
+	adapter, err := adapters.NewPrometheusAdapter(endpoint, username, password, timeout)
+	if err != nil {
+		setupLog.Error(err, "Failed to create Prometheus telemetry adapter, falling back to NoOpAdapter")
+		return telemetry.NewNoOpAdapter()
+	}
+
+	setupLog.Info("Prometheus telemetry adapter initialized successfully",
+		"endpoint", endpoint,
+		"timeout", timeout,
+		"basicAuth", username != "")
+
+	return adapter
+}
+
+// initializeTempoAdapter creates a Grafana Tempo telemetry adapter from environment variables
+func initializeTempoAdapter() telemetry.TelemetryAdapter {
+	endpoint := os.Getenv("TELEMETRY_ADAPTER_ENDPOINT")
+	if endpoint == "" {
+		setupLog.Error(nil, "Tempo adapter requires TELEMETRY_ADAPTER_ENDPOINT environment variable")
+		return telemetry.NewNoOpAdapter()
+	}
+
+	// Tenant and credentials are optional
+	config := adapters.TempoConfig{
+		Endpoint: endpoint,
+		TenantID: os.Getenv("TELEMETRY_ADAPTER_TENANT"),
+		Username: os.Getenv("TELEMETRY_ADAPTER_USERNAME"),
+		Password: os.Getenv("TELEMETRY_ADAPTER_PASSWORD"),
+		APIKey:   os.Getenv("TELEMETRY_ADAPTER_API_KEY"),
+		Timeout:  30 * time.Second,
+	}
+	if timeoutStr := os.Getenv("TELEMETRY_ADAPTER_TIMEOUT"); timeoutStr != "" {
+		if parsedTimeout, err := time.ParseDuration(timeoutStr); err == nil {
+			config.Timeout = parsedTimeout
+		} else {
+			setupLog.Error(err, "Invalid TELEMETRY_ADAPTER_TIMEOUT, using default 30s", "value", timeoutStr)
+		}
+	}
+
+	adapter, err := adapters.NewTempoAdapter(config)
+	if err != nil {
+		setupLog.Error(err, "Failed to create Tempo telemetry adapter, falling back to NoOpAdapter")
+		return telemetry.NewNoOpAdapter()
+	}
+
+	setupLog.Info("Tempo telemetry adapter initialized successfully",
+		"endpoint", endpoint,
+		"timeout", config.Timeout,
+		"tenant", config.TenantID,
+		"basicAuth", config.Username != "")
+
+	return adapter
+}
+
+// initializeDatadogAdapter creates a Datadog APM telemetry adapter from environment variables
+func initializeDatadogAdapter() telemetry.TelemetryAdapter {
+	config := adapters.DatadogConfig{
+		Site:           getEnvOrDefault("DD_SITE", adapters.DefaultDatadogSite),
+		APIKey:         getEnvOrDefault("DD_API_KEY", os.Getenv("TELEMETRY_ADAPTER_API_KEY")),
+		ApplicationKey: os.Getenv("DD_APPLICATION_KEY"),
+		Timeout:        30 * time.Second,
+	}
+
+	// Span search needs both the API key and an application key
+	if config.APIKey == "" {
+		setupLog.Error(nil, "Datadog adapter requires DD_API_KEY or TELEMETRY_ADAPTER_API_KEY environment variable")
+		return telemetry.NewNoOpAdapter()
+	}
+	if config.ApplicationKey == "" {
+		setupLog.Error(nil, "Datadog adapter requires DD_APPLICATION_KEY environment variable")
+		return telemetry.NewNoOpAdapter()
+	}
+
+	if timeoutStr := os.Getenv("TELEMETRY_ADAPTER_TIMEOUT"); timeoutStr != "" {
+		if parsedTimeout, err := time.ParseDuration(timeoutStr); err == nil {
+			config.Timeout = parsedTimeout
+		} else {
+			setupLog.Error(err, "Invalid TELEMETRY_ADAPTER_TIMEOUT, using default 30s", "value", timeoutStr)
+		}
+	}
+
+	adapter, err := adapters.NewDatadogAdapter(config)
+	if err != nil {
+		setupLog.Error(err, "Failed to create Datadog telemetry adapter, falling back to NoOpAdapter")
+		return telemetry.NewNoOpAdapter()
+	}
+
+	setupLog.Info("Datadog telemetry adapter initialized successfully",
+		"site", config.Site,
+		"timeout", config.Timeout)
+
+	return adapter
+}
+
 // initializeSigNozAdapter creates a SigNoz telemetry adapter from environment variables
 func initializeSigNozAdapter() telemetry.TelemetryAdapter {
 	endpoint := os.Getenv("TELEMETRY_ADAPTER_ENDPOINT")
@@ -146,6 +273,47 @@ func main() {
 	var requireNetworkPolicy bool
 	var networkPolicyTimeout time.Duration
 	var networkPolicyRetries int
+	var unhealthyThreshold time.Duration
+	var conditionMetricTypes string
+	var exchangeRates string
+	var maxConcurrentLearningRollouts int
+	var featureGates string
+	var auditSink string
+	var maxConcurrentSynthesis int
+	var synthesisFairness string
+	var driftPolicy string
+	var auditConfigMapName string
+	var modelDeletionPolicy string
+	var modelHealthCheckInterval time.Duration
+	var validatorTimeout time.Duration
+	var validatorMemoryLimit string
+	var reconcilePriority bool
+	var maxConcurrentAgentRestarts int
+	var synthesisQuotaBackoffThreshold int
+	var synthesisQuotaBackoffInterval time.Duration
+	var synthesisExampleLibrary string
+	var synthesisRedactPatterns []string
+	var synthesisCacheEnabled bool
+	var synthesisCacheTTL time.Duration
+	var synthesisCacheMaxEntries int
+	var synthesisCacheConfigMap string
+	var selfHealingStabilityWindow time.Duration
+	var selfHealingRollbackWindow time.Duration
+	var batchStatusUpdates bool
+	var enableChaos bool
+	var learningTraceWindow time.Duration
+	var learningHealthGateWindow time.Duration
+	var minTraceAge time.Duration
+	var enableConfigEndpoint bool
+	var debugAddr string
+	var artifactTTL time.Duration
+	var costReportInterval time.Duration
+	var costReportRetention int
+	var defaultAgentCPURequest string
+	var defaultAgentCPULimit string
+	var defaultAgentMemoryRequest string
+	var defaultAgentMemoryLimit string
+	var verifyAgentImage bool
 
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8443", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
@@ -159,6 +327,96 @@ func main() {
 		"Timeout for NetworkPolicy operations. Increase for slow CNI plugins.")
 	flag.IntVar(&networkPolicyRetries, "network-policy-retries", 3,
 		"Number of retry attempts for NetworkPolicy operations.")
+	flag.DurationVar(&unhealthyThreshold, "unhealthy-threshold", 5*time.Minute,
+		"How long a running agent pod may fail readiness before it counts toward self-healing. Set to 0 to disable.")
+	flag.StringVar(&conditionMetricTypes, "condition-metrics-types", "",
+		"Comma-separated list of agent condition types to export as langop_agent_condition metrics. Empty exports all.")
+	flag.StringVar(&exchangeRates, "synthesis-exchange-rates", "",
+		"Comma-separated CURRENCY=RATE pairs giving units of each currency per 1 USD, used to convert synthesis costs for quota enforcement (e.g. EUR=0.92,GBP=0.79).")
+	flag.IntVar(&maxConcurrentLearningRollouts, "max-concurrent-learning-rollouts", 2,
+		"Maximum number of learning-driven Deployment rollouts in progress across the cluster. Additional rollouts queue. Set to 0 to disable the limit.")
+	flag.StringVar(&featureGates, "feature-gates", "",
+		"Comma-separated Name=true|false pairs setting the default state of experimental agent features (e.g. UnhealthyPodDetection=false). Agents can override gates with spec.featureGates.")
+	flag.IntVar(&maxConcurrentAgentRestarts, "max-concurrent-agent-restarts", 0,
+		"Maximum number of agent Deployment rollouts in progress at once. When a shared change affects many agents, the rest queue and roll out as earlier ones finish. Zero or less means unlimited.")
+	flag.IntVar(&synthesisQuotaBackoffThreshold, "synthesis-quota-backoff-threshold", 3,
+		"Number of consecutive reconciles refused by the synthesis rate limit or quota after which an agent stops retrying synthesis for --synthesis-quota-backoff-interval and gets a SynthesisQuotaBackoff condition. A spec change retries right away. Zero or less disables the backoff.")
+	flag.DurationVar(&synthesisQuotaBackoffInterval, "synthesis-quota-backoff-interval", 30*time.Minute,
+		"How long an agent in synthesis quota backoff waits before retrying synthesis.")
+	flag.IntVar(&maxConcurrentSynthesis, "max-concurrent-synthesis", 3,
+		"Maximum number of LLM synthesis calls running at once across all agents. Zero or less means unlimited.")
+	flag.StringVar(&synthesisFairness, "synthesis-fairness-key", synthesis.FairnessByNamespace,
+		"How queued synthesis requests share slots: \"namespace\" (round-robin between namespaces) or \"agent\" (round-robin between agents).")
+	flag.StringVar(&driftPolicy, "drift-policy", controllers.DriftPolicyCorrect,
+		"How to handle external edits to agent code ConfigMaps and Deployments: \"correct\" restores the operator's state, \"warn\" only emits a DriftDetected event.")
+	flag.StringVar(&modelDeletionPolicy, "model-deletion-policy", controllers.ModelDeletionPolicyBlock,
+		"How to handle deleting a LanguageModel that agents still reference: \"block\" keeps it until the references are removed (or it is annotated langop.io/force-delete=true), \"warn\" deletes it and marks dependent agents with a ModelDeleted condition.")
+	flag.DurationVar(&modelHealthCheckInterval, "model-health-check-interval", 2*time.Minute,
+		"How often each LanguageModel's provider endpoint is checked for reachability. Failed checks back off exponentially up to 30m. Set to 0 to disable.")
+	flag.DurationVar(&validatorTimeout, "validator-timeout", 0,
+		"Wall-clock budget for each run of the synthesized code validators. Validators over budget fail validation with reason ValidationTimeout. Zero keeps each validator's built-in timeout.")
+	flag.StringVar(&validatorMemoryLimit, "validator-memory-limit", "0",
+		"Address space limit for validator subprocesses as a Kubernetes quantity (e.g. 8Gi). Runtimes reserve far more address space than they use, so keep it generous. 0 disables the limit. Enforced on Linux only.")
+	flag.BoolVar(&reconcilePriority, "reconcile-priority", false,
+		"Reconcile agents labeled langop.io/priority=high before normal and low priority agents when the work queue is backed up. "+
+			"Lower-priority reconciles are deferred and requeued while higher-priority ones wait, which adds queue churn.")
+	flag.StringVar(&auditSink, "audit-sink", "",
+		"Where to write the audit stream of synthesis, self-healing, and learning changes: \"log\" or \"configmap\". Empty disables auditing.")
+	flag.StringVar(&auditConfigMapName, "audit-configmap-name", "langop-audit",
+		"Name prefix of the append-only ConfigMaps in the operator namespace used by --audit-sink=configmap.")
+	flag.StringVar(&synthesisExampleLibrary, "synthesis-example-library", "",
+		"Name of the ConfigMap in the operator namespace holding curated synthesis example sets, selected per agent with spec.synthesisExampleSet. Empty disables examples.")
+	flag.BoolVar(&synthesisCacheEnabled, "synthesis-cache-enabled", false,
+		"Reuse previously synthesized code for agents whose instructions, tools, models, and personas are unchanged, e.g. after an operator restart or a deleted code ConfigMap. Self-healing always synthesizes fresh code.")
+	flag.DurationVar(&synthesisCacheTTL, "synthesis-cache-ttl", synthesis.DefaultCacheTTL,
+		"How long cached synthesized code is reused.")
+	flag.IntVar(&synthesisCacheMaxEntries, "synthesis-cache-max-entries", synthesis.DefaultCacheMaxEntries,
+		"Maximum number of cached synthesis results. The least recently used results are evicted first.")
+	flag.StringVar(&synthesisCacheConfigMap, "synthesis-cache-configmap", "langop-synthesis-cache",
+		"Name of the ConfigMap in the operator namespace that persists the synthesis cache across restarts. Empty keeps the cache in memory.")
+	flag.Func("synthesis-redact-pattern",
+		"Regular expression whose matches in agent instructions are replaced with placeholders before synthesis input is sent to the synthesis model, and restored in the generated code. Repeat the flag for multiple patterns.",
+		func(pattern string) error {
+			synthesisRedactPatterns = append(synthesisRedactPatterns, pattern)
+			return nil
+		})
+	flag.DurationVar(&selfHealingStabilityWindow, "self-healing-stability-window", 10*time.Minute,
+		"How long self-healed agent code must run without failures before it becomes the last known good code and self-healing attempts reset.")
+	flag.DurationVar(&selfHealingRollbackWindow, "self-healing-rollback-window", 10*time.Minute,
+		"How long after deploying self-healed agent code a repeat failure rolls the agent back to its last known good code instead of healing again. 0 disables rollback.")
+	flag.BoolVar(&batchStatusUpdates, "batch-status-updates", true,
+		"Write the status changes of each LanguageAgent reconcile in a single API request instead of one request per reconcile step.")
+	flag.BoolVar(&enableChaos, "enable-chaos", false,
+		"Permit spec.chaos failure injection for agents in namespaces labeled "+langopv1alpha1.ChaosNamespaceLabel+"=true. Only enable it on test clusters.")
+	flag.DurationVar(&learningTraceWindow, "learning-trace-window", 24*time.Hour,
+		"How far back execution traces are analyzed for learning. Agents can override it with spec.learning.traceWindow.")
+	flag.DurationVar(&learningHealthGateWindow, "learning-health-gate-window", 30*time.Minute,
+		"How long a task's error rate is observed after a learned version is promoted. The promotion is rolled back if the error rate rose. Agents can override it with spec.learning.healthGateWindow. 0 disables the gate.")
+	flag.DurationVar(&minTraceAge, "min-trace-age", time.Minute,
+		"Executions that completed more recently than this are left out of learning analysis, so in-flight executions aren't analyzed. Agents can override it with spec.learning.minTraceAge.")
+	flag.StringVar(&defaultAgentCPURequest, "default-agent-cpu-request", "",
+		"CPU request of agent and sidecar tool containers that don't set one, unless the agent's LanguageCluster sets spec.defaults.resources (e.g. 100m). Empty leaves it unset.")
+	flag.StringVar(&defaultAgentCPULimit, "default-agent-cpu-limit", "",
+		"CPU limit of agent and sidecar tool containers that don't set one, unless the agent's LanguageCluster sets spec.defaults.resources. Empty leaves it unset.")
+	flag.StringVar(&defaultAgentMemoryRequest, "default-agent-memory-request", "",
+		"Memory request of agent and sidecar tool containers that don't set one, unless the agent's LanguageCluster sets spec.defaults.resources (e.g. 256Mi). Empty leaves it unset.")
+	flag.StringVar(&defaultAgentMemoryLimit, "default-agent-memory-limit", "",
+		"Memory limit of agent and sidecar tool containers that don't set one, unless the agent's LanguageCluster sets spec.defaults.resources (e.g. 1Gi). Empty leaves it unset.")
+	flag.DurationVar(&artifactTTL, "artifact-ttl", 72*time.Hour,
+		"How long operator-generated debug, preview, and analysis ConfigMaps are kept after their last write before they are deleted. Analyses awaiting approval are kept until reviewed. Artifacts of deleted agents are always removed with the agent. Set to 0 to keep them for the agent's lifetime.")
+	flag.DurationVar(&costReportInterval, "cost-report-interval", 0,
+		"How often each agent's synthesis cost, token usage, and attempts are snapshotted into its <agent>-cost-report ConfigMap (e.g. 24h). Set to 0 to disable cost reports.")
+	flag.IntVar(&costReportRetention, "cost-report-retention", controllers.DefaultCostReportRetention,
+		"Number of snapshots each cost report keeps before the oldest are rotated out.")
+	flag.BoolVar(&verifyAgentImage, "verify-agent-image", false,
+		"Inspect each agent image through its registry before deploying it, and refuse images without the "+controllers.AgentRuntimeLabel+" label "+
+			"or without a linux/amd64 or linux/arm64 variant matching the cluster's node architecture.")
+	flag.BoolVar(&enableConfigEndpoint, "enable-config-endpoint", false,
+		"Serve the operator's effective configuration as JSON at "+controllers.EffectiveConfigPath+" on the metrics server. "+
+			"Values named or shaped like credentials are redacted, but the endpoint still describes the operator's setup to anyone who can reach the metrics server.")
+	flag.StringVar(&debugAddr, "debug-addr", "",
+		"The address the debug server binds to, serving the live state of every agent at "+controllers.DebugAgentsPath+". "+
+			"An address without a host (e.g. :8082) binds to localhost only. Empty disables the debug server.")
 	flag.DurationVar(&leaseDuration, "leader-elect-lease-duration", 15*time.Second,
 		"The duration that non-leader candidates will wait after observing a leadership renewal.")
 	flag.DurationVar(&renewDeadline, "leader-elect-renew-deadline", 10*time.Second,
@@ -166,7 +424,7 @@ func main() {
 	flag.DurationVar(&retryPeriod, "leader-elect-retry-period", 2*time.Second,
 		"The duration the clients should wait between attempting acquisition and renewal of a leadership.")
 	flag.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
-		"The resync period for controllers.")
+		"The resync period for controllers. Shorter periods pick up drift in unwatched resources sooner but increase API server load.")
 	flag.StringVar(&watchNamespaces, "watch-namespaces", "",
 		"Comma-separated list of namespaces to watch. Empty means all namespaces.")
 	flag.IntVar(&concurrency, "concurrency", 5,
@@ -291,12 +549,20 @@ func main() {
 	} else {
 		setupLog.Info("Watching all namespaces")
 	}
+	setupLog.Info("Controller resync period configured", "syncPeriod", syncPeriod)
+
+	// The config handler is registered now and given the reconcilers once they are built
+	configHandler := &controllers.EffectiveConfigHandler{Flags: flag.CommandLine, CNI: cniCaps}
+	metricsOptions := metricsserver.Options{
+		BindAddress: metricsAddr,
+	}
+	if enableConfigEndpoint {
+		metricsOptions.ExtraHandlers = map[string]http.Handler{controllers.EffectiveConfigPath: configHandler}
+	}
 
 	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme: scheme,
-		Metrics: metricsserver.Options{
-			BindAddress: metricsAddr,
-		},
+		Scheme:  scheme,
+		Metrics: metricsOptions,
 		WebhookServer: webhook.NewServer(webhook.Options{
 			Port: 9443,
 		}),
@@ -306,16 +572,51 @@ func main() {
 		LeaseDuration:          &leaseDuration,
 		RenewDeadline:          &renewDeadline,
 		RetryPeriod:            &retryPeriod,
-		//Cache: cache.Options{
-		//	DefaultNamespaces: namespaces,
-		//	SyncPeriod:        &syncPeriod,
-		//},
+		Cache:                  buildCacheOptions(syncPeriod),
 	})
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
 	}
 
+	auditEmitter, err := buildAuditEmitter(mgr, auditSink, auditConfigMapName)
+	if err != nil {
+		setupLog.Error(err, "unable to configure audit stream")
+		os.Exit(1)
+	}
+	// Audit records are queued by the controllers and written to the sink in the background
+	if auditEmitter != nil {
+		if err := mgr.Add(auditEmitter); err != nil {
+			setupLog.Error(err, "unable to set up audit stream")
+			os.Exit(1)
+		}
+	}
+
+	parsedDriftPolicy, err := controllers.ParseDriftPolicy(driftPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid drift policy")
+		os.Exit(1)
+	}
+
+	parsedModelDeletionPolicy, err := controllers.ParseModelDeletionPolicy(modelDeletionPolicy)
+	if err != nil {
+		setupLog.Error(err, "invalid model deletion policy")
+		os.Exit(1)
+	}
+
+	validatorMemory, err := resource.ParseQuantity(validatorMemoryLimit)
+	if err == nil && validatorMemory.Sign() < 0 {
+		err = fmt.Errorf("validator memory limit must not be negative")
+	}
+	if err != nil {
+		setupLog.Error(err, "invalid validator memory limit", "value", validatorMemoryLimit)
+		os.Exit(1)
+	}
+	validation.SetLimits(validation.Limits{
+		Timeout:          validatorTimeout,
+		MemoryLimitBytes: uint64(validatorMemory.Value()),
+	})
+
 	// Setup LanguageTool controller
 	if err = (&controllers.LanguageToolReconciler{
 		Client:          mgr.GetClient(),
@@ -327,29 +628,105 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Initialize telemetry adapter for model rate limit tracking and the learning system
+	telemetryAdapter := initializeTelemetryAdapter()
+
 	// Setup LanguageModel controller
 	if err = (&controllers.LanguageModelReconciler{
-		Client: mgr.GetClient(),
-		Scheme: mgr.GetScheme(),
-		Log:    ctrl.Log.WithName("controllers").WithName("LanguageModel"),
+		Client:              mgr.GetClient(),
+		Scheme:              mgr.GetScheme(),
+		Log:                 ctrl.Log.WithName("controllers").WithName("LanguageModel"),
+		Recorder:            mgr.GetEventRecorderFor("languagemodel-controller"),
+		DeletionPolicy:      parsedModelDeletionPolicy,
+		HealthChecker:       &controllers.HTTPModelHealthChecker{},
+		HealthCheckInterval: modelHealthCheckInterval,
+		RateLimits:          controllers.NewRateLimitTracker(telemetryAdapter),
 	}).SetupWithManager(mgr, concurrency); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LanguageModel")
 		os.Exit(1)
 	}
 
+	defaultResources, err := controllers.ParseDefaultResources(defaultAgentCPURequest, defaultAgentCPULimit,
+		defaultAgentMemoryRequest, defaultAgentMemoryLimit)
+	if err != nil {
+		setupLog.Error(err, "invalid default agent resources")
+		os.Exit(1)
+	}
+
 	// Setup LanguageAgent controller with optional synthesizer
 	agentReconciler := &controllers.LanguageAgentReconciler{
-		Client:               mgr.GetClient(),
-		Scheme:               mgr.GetScheme(),
-		Log:                  ctrl.Log.WithName("controllers").WithName("LanguageAgent"),
-		Recorder:             mgr.GetEventRecorderFor("languageagent-controller"),
-		RegistryManager:      registryManager,
-		NetworkPolicyTimeout: networkPolicyTimeout,
-		NetworkPolicyRetries: networkPolicyRetries,
+		Client:                     mgr.GetClient(),
+		Scheme:                     mgr.GetScheme(),
+		Log:                        ctrl.Log.WithName("controllers").WithName("LanguageAgent"),
+		Recorder:                   mgr.GetEventRecorderFor("languageagent-controller"),
+		RegistryManager:            registryManager,
+		NetworkPolicyTimeout:       networkPolicyTimeout,
+		NetworkPolicyRetries:       networkPolicyRetries,
+		UnhealthyThreshold:         unhealthyThreshold,
+		ConditionMetricTypes:       splitAndTrim(conditionMetricTypes, ","),
+		Audit:                      auditEmitter,
+		DriftPolicy:                parsedDriftPolicy,
+		MaxConcurrentAgentRestarts: int32(maxConcurrentAgentRestarts),
+		SelfHealingStabilityWindow: selfHealingStabilityWindow,
+		SelfHealingRollbackWindow:  selfHealingRollbackWindow,
+		BatchStatusUpdates:         batchStatusUpdates,
+		DefaultResources:           defaultResources,
+		ChaosEnabled:               enableChaos,
+		QuotaBackoffThreshold:      synthesisQuotaBackoffThreshold,
+		QuotaBackoffInterval:       synthesisQuotaBackoffInterval,
+		VerifyAgentImage:           verifyAgentImage,
+	}
+	if reconcilePriority {
+		agentReconciler.Priority = controllers.NewReconcilePrioritizer()
+	}
+	if synthesisExampleLibrary != "" {
+		namespace := os.Getenv("POD_NAMESPACE")
+		if namespace == "" {
+			setupLog.Error(nil, "POD_NAMESPACE must be set to use --synthesis-example-library")
+			os.Exit(1)
+		}
+		agentReconciler.ExampleLibrary = &synthesis.ExampleLibrary{
+			Reader:    mgr.GetAPIReader(),
+			Namespace: namespace,
+			Name:      synthesisExampleLibrary,
+		}
+		setupLog.Info("Synthesis example library enabled", "namespace", namespace, "configMap", synthesisExampleLibrary)
+	}
+
+	if len(synthesisRedactPatterns) > 0 {
+		redactor, err := synthesis.NewRedactor(synthesisRedactPatterns)
+		if err != nil {
+			setupLog.Error(err, "invalid synthesis redaction pattern")
+			os.Exit(1)
+		}
+		agentReconciler.SynthesisRedactor = redactor
+		setupLog.Info("Synthesis input redaction enabled", "patterns", len(synthesisRedactPatterns))
+	}
+
+	if synthesisCacheEnabled {
+		if synthesisCacheConfigMap != "" {
+			namespace := os.Getenv("POD_NAMESPACE")
+			if namespace == "" {
+				setupLog.Error(nil, "POD_NAMESPACE must be set to persist the synthesis cache, set --synthesis-cache-configmap= to keep it in memory")
+				os.Exit(1)
+			}
+			agentReconciler.SynthesisCache = synthesis.NewConfigMapCache(mgr.GetClient(), mgr.GetAPIReader(), namespace,
+				synthesisCacheConfigMap, synthesisCacheTTL, synthesisCacheMaxEntries)
+			setupLog.Info("Synthesis cache enabled", "namespace", namespace, "configMap", synthesisCacheConfigMap,
+				"ttl", synthesisCacheTTL, "maxEntries", synthesisCacheMaxEntries)
+		} else {
+			agentReconciler.SynthesisCache = synthesis.NewMemoryCache(synthesisCacheTTL, synthesisCacheMaxEntries)
+			setupLog.Info("Synthesis cache enabled in memory", "ttl", synthesisCacheTTL, "maxEntries", synthesisCacheMaxEntries)
+		}
 	}
 
-	// Initialize Gateway API cache
-	agentReconciler.InitializeGatewayCache()
+	// Detect the Gateway API once for every controller, refreshed in the background
+	gatewayAPI := controllers.NewGatewayAPIDetector(mgr.GetConfig())
+	if err := mgr.Add(gatewayAPI); err != nil {
+		setupLog.Error(err, "unable to set up Gateway API detection")
+		os.Exit(1)
+	}
+	agentReconciler.GatewayAPI = gatewayAPI
 
 	// Initialize rate limiter and quota manager for synthesis cost controls
 	maxSynthesisPerHour := 500 // Default: 500 synthesis per namespace per hour
@@ -363,9 +740,50 @@ func main() {
 	agentReconciler.QuotaManager = quotaManager
 	setupLog.Info("Synthesis quota manager initialized", "maxCostPerDay", maxCostPerDay, "maxAttemptsPerDay", maxAttemptsPerDay)
 
+	// Expose the rate limiter fill level and remaining quota per namespace on the metrics server
+	for _, collector := range synthesis.QuotaMetrics() {
+		if err := metrics.Registry.Register(collector); err != nil {
+			setupLog.Error(err, "unable to register synthesis quota metrics")
+			os.Exit(1)
+		}
+	}
+
+	synthesisSlots, err := synthesis.NewSlotScheduler(maxConcurrentSynthesis, synthesisFairness)
+	if err != nil {
+		setupLog.Error(err, "invalid synthesis fairness key")
+		os.Exit(1)
+	}
+	agentReconciler.SynthesisSlots = synthesisSlots
+	setupLog.Info("Synthesis slot scheduler initialized", "maxConcurrent", maxConcurrentSynthesis, "fairnessKey", synthesisFairness)
+
+	if exchangeRates != "" {
+		rates, err := synthesis.ParseExchangeRates(exchangeRates)
+		if err != nil {
+			setupLog.Error(err, "invalid synthesis exchange rates")
+			os.Exit(1)
+		}
+		quotaManager.SetExchangeRateProvider(synthesis.NewStaticExchangeRates("USD", rates))
+		setupLog.Info("Synthesis cost currency conversion enabled", "rates", rates)
+	}
+
+	if featureGates != "" {
+		gates, err := langopv1alpha1.ParseFeatureGates(featureGates)
+		if err != nil {
+			setupLog.Error(err, "invalid feature gates")
+			os.Exit(1)
+		}
+		for _, gate := range langopv1alpha1.UnknownFeatureGates(gates) {
+			setupLog.Info("WARNING: ignoring unknown feature gate", "gate", gate)
+		}
+		agentReconciler.FeatureGates = gates
+		setupLog.Info("Feature gates configured", "gates", gates)
+	}
+
 	// Synthesis is now configured per-agent via ModelRefs - no global setup needed
 	setupLog.Info("Synthesis engine uses per-agent ModelRefs configuration")
 
+	configHandler.AgentReconciler = agentReconciler
+
 	if err = agentReconciler.SetupWithManager(mgr, concurrency); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "LanguageAgent")
 		os.Exit(1)
@@ -401,38 +819,89 @@ func main() {
 		Log:    learningLog.WithName("configmap"),
 	}
 
-	// Initialize telemetry adapter for learning system
-	telemetryAdapter := initializeTelemetryAdapter()
-
-	if err = (&controllers.LearningReconciler{
-		Client:                      mgr.GetClient(),
-		Scheme:                      mgr.GetScheme(),
-		Log:                         learningLog,
-		Recorder:                    mgr.GetEventRecorderFor("learning-controller"),
-		ConfigMapManager:            configMapManager,
-		MetricsCollector:            metricsCollector,
-		EventProcessor:              eventProcessor,
-		TelemetryAdapter:            telemetryAdapter,
-		SuccessRateAggregator:       make(map[string]*learning.LearningSuccessRateAggregator),
-		LearningEnabled:             true,
-		LearningThreshold:           10,              // Trigger learning after 10 traces
-		LearningInterval:            5 * time.Minute, // 5 minute cooldown between attempts
-		MaxVersions:                 5,               // Keep last 5 ConfigMap versions
-		PatternConfidenceMin:        0.8,             // Require 80% confidence
-		ErrorFailureThreshold:       3,               // Re-synthesize after 3 consecutive failures
-		ErrorCooldownPeriod:         5 * time.Minute, // 5 minute cooldown for error re-synthesis
-		MaxErrorResynthesisAttempts: 3,               // Max 3 error re-synthesis attempts per task
-	}).SetupWithManager(mgr); err != nil {
+	learningReconciler := &controllers.LearningReconciler{
+		Client:                        mgr.GetClient(),
+		Scheme:                        mgr.GetScheme(),
+		Log:                           learningLog,
+		Recorder:                      mgr.GetEventRecorderFor("learning-controller"),
+		ConfigMapManager:              configMapManager,
+		MetricsCollector:              metricsCollector,
+		EventProcessor:                eventProcessor,
+		TelemetryAdapter:              telemetryAdapter,
+		SuccessRateAggregator:         make(map[string]*learning.LearningSuccessRateAggregator),
+		LearningEnabled:               true,
+		LearningThreshold:             10,              // Trigger learning after 10 traces
+		LearningInterval:              5 * time.Minute, // 5 minute cooldown between attempts
+		LearningTraceWindow:           learningTraceWindow,
+		MinTraceAge:                   minTraceAge,
+		HealthGateWindow:              learningHealthGateWindow,
+		MaxVersions:                   5,               // Keep last 5 ConfigMap versions
+		PatternConfidenceMin:          0.8,             // Require 80% confidence
+		ErrorFailureThreshold:         3,               // Re-synthesize after 3 consecutive failures
+		ErrorCooldownPeriod:           5 * time.Minute, // 5 minute cooldown for error re-synthesis
+		MaxErrorResynthesisAttempts:   3,               // Max 3 error re-synthesis attempts per task
+		MaxConcurrentLearningRollouts: int32(maxConcurrentLearningRollouts),
+		Audit:                         auditEmitter,
+		ArtifactTTL:                   artifactTTL,
+	}
+	configHandler.LearningReconciler = learningReconciler
+	if err = learningReconciler.SetupWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Learning")
 		os.Exit(1)
 	}
 
+	// Expired artifacts are cleaned up even if the TTL was since disabled
+	if err := mgr.Add(&controllers.ArtifactCleaner{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("artifact-cleaner"),
+		Interval: controllers.DefaultArtifactCleanupInterval,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up artifact cleaner")
+		os.Exit(1)
+	}
+
+	// Versions orphaned before startup aren't swept by agent deletions, so they are swept periodically
+	if err := mgr.Add(&controllers.OrphanSweeper{
+		Client:   mgr.GetClient(),
+		Log:      ctrl.Log.WithName("orphan-sweeper"),
+		Interval: controllers.DefaultOrphanSweepInterval,
+	}); err != nil {
+		setupLog.Error(err, "unable to set up orphan sweeper")
+		os.Exit(1)
+	}
+
+	if costReportInterval > 0 {
+		if err := mgr.Add(&controllers.CostReporter{
+			Client:       mgr.GetClient(),
+			QuotaManager: quotaManager,
+			Log:          ctrl.Log.WithName("cost-reporter"),
+			Interval:     costReportInterval,
+			Retention:    costReportRetention,
+		}); err != nil {
+			setupLog.Error(err, "unable to set up cost reporter")
+			os.Exit(1)
+		}
+	}
+
+	if debugAddr != "" {
+		if err := mgr.Add(controllers.NewDebugServer(debugAddr, mgr.GetCache())); err != nil {
+			setupLog.Error(err, "unable to set up debug server")
+			os.Exit(1)
+		}
+	}
+
 	// Setup LanguageCluster webhook
 	if err = (&langopv1alpha1.LanguageCluster{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "LanguageCluster")
 		os.Exit(1)
 	}
 
+	// Setup LanguagePersona webhook
+	if err = (&langopv1alpha1.LanguagePersona{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "LanguagePersona")
+		os.Exit(1)
+	}
+
 	// Setup LanguageAgent webhook for synthesis cost controls
 	if err = (&langopv1alpha1.LanguageAgent{}).SetupWebhookWithManager(mgr); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "LanguageAgent")
@@ -458,6 +927,37 @@ func main() {
 	}
 }
 
+// buildCacheOptions builds the manager cache options.
+// The sync period controls how often every cached object is re-reconciled even when
+// nothing changed: shorter periods correct drift sooner at the cost of more API load.
+func buildCacheOptions(syncPeriod time.Duration) cache.Options {
+	return cache.Options{
+		SyncPeriod: &syncPeriod,
+	}
+}
+
+// buildAuditEmitter creates the audit emitter for the configured sink, or nil when auditing is disabled
+func buildAuditEmitter(mgr ctrl.Manager, sink, configMapName string) (*audit.Emitter, error) {
+	auditLog := ctrl.Log.WithName("audit")
+
+	switch sink {
+	case "":
+		return nil, nil
+	case "log":
+		setupLog.Info("Audit stream enabled", "sink", sink)
+		return audit.NewEmitter(audit.NewLogSink(auditLog), auditLog), nil
+	case "configmap":
+		namespace := os.Getenv("POD_NAMESPACE")
+		if namespace == "" {
+			return nil, fmt.Errorf("POD_NAMESPACE must be set to use the configmap audit sink")
+		}
+		setupLog.Info("Audit stream enabled", "sink", sink, "namespace", namespace, "configMap", configMapName)
+		return audit.NewEmitter(audit.NewConfigMapSink(mgr.GetClient(), mgr.GetAPIReader(), namespace, configMapName), auditLog), nil
+	default:
+		return nil, fmt.Errorf("unknown audit sink %q, expected \"log\" or \"configmap\"", sink)
+	}
+}
+
 func parseNamespaces(namespaces string) []string {
 	var result []string
 	for _, ns := range splitAndTrim(namespaces, ",") {
@@ -89,6 +89,65 @@ func TestInitializeTelemetryAdapter(t *testing.T) {
 			expectedType: "*adapters.SignozAdapter",
 			shouldBeNoop: false,
 		},
+		{
+			name: "prometheus without endpoint - falls back to NoOpAdapter",
+			envVars: map[string]string{
+				"TELEMETRY_ADAPTER_TYPE":     "prometheus",
+				"TELEMETRY_ADAPTER_ENDPOINT": "",
+			},
+			expectedType: "*telemetry.NoOpAdapter",
+			shouldBeNoop: true,
+		},
+		{
+			name: "prometheus with basic auth - creates PrometheusAdapter",
+			envVars: map[string]string{
+				"TELEMETRY_ADAPTER_TYPE":     "prometheus",
+				"TELEMETRY_ADAPTER_ENDPOINT": "http://127.0.0.1:1",
+				"TELEMETRY_ADAPTER_USERNAME": "user",
+				"TELEMETRY_ADAPTER_PASSWORD": "secret",
+			},
+			expectedType: "*adapters.PrometheusAdapter",
+			shouldBeNoop: false,
+		},
+		{
+			name: "tempo without endpoint - falls back to NoOpAdapter",
+			envVars: map[string]string{
+				"TELEMETRY_ADAPTER_TYPE":     "tempo",
+				"TELEMETRY_ADAPTER_ENDPOINT": "",
+			},
+			expectedType: "*telemetry.NoOpAdapter",
+			shouldBeNoop: true,
+		},
+		{
+			name: "tempo with tenant - creates TempoAdapter",
+			envVars: map[string]string{
+				"TELEMETRY_ADAPTER_TYPE":     "tempo",
+				"TELEMETRY_ADAPTER_ENDPOINT": "http://127.0.0.1:1",
+				"TELEMETRY_ADAPTER_TENANT":   "team-a",
+			},
+			expectedType: "*adapters.TempoAdapter",
+			shouldBeNoop: false,
+		},
+		{
+			name: "datadog without application key - falls back to NoOpAdapter",
+			envVars: map[string]string{
+				"TELEMETRY_ADAPTER_TYPE": "datadog",
+				"DD_API_KEY":             "test-api-key",
+			},
+			expectedType: "*telemetry.NoOpAdapter",
+			shouldBeNoop: true,
+		},
+		{
+			name: "datadog with unknown site - falls back to NoOpAdapter",
+			envVars: map[string]string{
+				"TELEMETRY_ADAPTER_TYPE": "datadog",
+				"DD_API_KEY":             "test-api-key",
+				"DD_APPLICATION_KEY":     "test-app-key",
+				"DD_SITE":                "datadog.example.com",
+			},
+			expectedType: "*telemetry.NoOpAdapter",
+			shouldBeNoop: true,
+		},
 		{
 			name: "unknown adapter type - falls back to NoOpAdapter",
 			envVars: map[string]string{
@@ -106,6 +165,12 @@ func TestInitializeTelemetryAdapter(t *testing.T) {
 			os.Unsetenv("TELEMETRY_ADAPTER_ENDPOINT")
 			os.Unsetenv("TELEMETRY_ADAPTER_API_KEY")
 			os.Unsetenv("TELEMETRY_ADAPTER_TIMEOUT")
+			os.Unsetenv("TELEMETRY_ADAPTER_USERNAME")
+			os.Unsetenv("TELEMETRY_ADAPTER_PASSWORD")
+			os.Unsetenv("TELEMETRY_ADAPTER_TENANT")
+			os.Unsetenv("DD_SITE")
+			os.Unsetenv("DD_API_KEY")
+			os.Unsetenv("DD_APPLICATION_KEY")
 
 			// Set test env vars
 			for key, value := range tc.envVars {
@@ -137,6 +202,14 @@ func TestInitializeTelemetryAdapter(t *testing.T) {
 				if _, ok := adapter.(*adapters.SignozAdapter); !ok {
 					t.Errorf("Expected SignozAdapter, got %T", adapter)
 				}
+			case "*adapters.PrometheusAdapter":
+				if _, ok := adapter.(*adapters.PrometheusAdapter); !ok {
+					t.Errorf("Expected PrometheusAdapter, got %T", adapter)
+				}
+			case "*adapters.TempoAdapter":
+				if _, ok := adapter.(*adapters.TempoAdapter); !ok {
+					t.Errorf("Expected TempoAdapter, got %T", adapter)
+				}
 			}
 		})
 	}
@@ -304,3 +377,16 @@ func TestStartupTimeoutConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildCacheOptionsSyncPeriod(t *testing.T) {
+	for _, syncPeriod := range []time.Duration{30 * time.Second, 10 * time.Minute, 2 * time.Hour} {
+		opts := buildCacheOptions(syncPeriod)
+
+		if opts.SyncPeriod == nil {
+			t.Fatalf("Expected SyncPeriod to be set for %v", syncPeriod)
+		}
+		if *opts.SyncPeriod != syncPeriod {
+			t.Errorf("Expected SyncPeriod %v, got %v", syncPeriod, *opts.SyncPeriod)
+		}
+	}
+}
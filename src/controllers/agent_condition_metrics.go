@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// agentConditionStatuses are the label values exported for every condition,
+// mirroring kube-state-metrics so alerts can match on status="false" directly
+var agentConditionStatuses = []metav1.ConditionStatus{
+	metav1.ConditionTrue,
+	metav1.ConditionFalse,
+	metav1.ConditionUnknown,
+}
+
+// AgentCondition exposes the current status of each LanguageAgent condition
+var AgentCondition = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "langop_agent_condition",
+		Help: "The current status of a LanguageAgent condition by type, status, namespace, and name",
+	},
+	[]string{"type", "status", "namespace", "name"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(AgentCondition)
+}
+
+// recordAgentConditionMetrics replaces the exported condition gauges for an agent with its
+// current conditions. Only condition types in exportTypes are exported; an empty list exports all.
+func recordAgentConditionMetrics(agent *langopv1alpha1.LanguageAgent, exportTypes []string) {
+	deleteAgentConditionMetrics(agent.Namespace, agent.Name)
+
+	for _, condition := range agent.Status.Conditions {
+		if !shouldExportConditionType(condition.Type, exportTypes) {
+			continue
+		}
+		for _, status := range agentConditionStatuses {
+			value := 0.0
+			if condition.Status == status {
+				value = 1.0
+			}
+			AgentCondition.WithLabelValues(condition.Type, strings.ToLower(string(status)), agent.Namespace, agent.Name).Set(value)
+		}
+	}
+}
+
+// deleteAgentConditionMetrics removes all condition gauges for an agent
+func deleteAgentConditionMetrics(namespace, name string) {
+	AgentCondition.DeletePartialMatch(prometheus.Labels{"namespace": namespace, "name": name})
+}
+
+// shouldExportConditionType reports whether a condition type is in the export allowlist
+func shouldExportConditionType(conditionType string, exportTypes []string) bool {
+	if len(exportTypes) == 0 {
+		return true
+	}
+	for _, t := range exportTypes {
+		if t == conditionType {
+			return true
+		}
+	}
+	return false
+}
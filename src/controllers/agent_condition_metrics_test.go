@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newConditionMetricsTestAgent(name string) *langopv1alpha1.LanguageAgent {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "metrics-ns",
+		},
+	}
+	SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionTrue, "Ready", "ready", 1)
+	SetCondition(&agent.Status.Conditions, "Synthesized", metav1.ConditionFalse, "SynthesisFailed", "failed", 1)
+	return agent
+}
+
+func TestAgentConditionMetrics_ReflectCurrentStatus(t *testing.T) {
+	agent := newConditionMetricsTestAgent("condition-status")
+	defer deleteAgentConditionMetrics(agent.Namespace, agent.Name)
+
+	recordAgentConditionMetrics(agent, nil)
+
+	expected := map[[2]string]float64{
+		{"Ready", "true"}:        1,
+		{"Ready", "false"}:       0,
+		{"Ready", "unknown"}:     0,
+		{"Synthesized", "true"}:  0,
+		{"Synthesized", "false"}: 1,
+	}
+	for labels, want := range expected {
+		got := promtestutil.ToFloat64(AgentCondition.WithLabelValues(labels[0], labels[1], agent.Namespace, agent.Name))
+		if got != want {
+			t.Errorf("Expected %s=%s gauge to be %v, got %v", labels[0], labels[1], want, got)
+		}
+	}
+
+	// A status transition must flip the gauges on the next record
+	SetCondition(&agent.Status.Conditions, "Synthesized", metav1.ConditionTrue, "CodeGenerated", "ok", 2)
+	recordAgentConditionMetrics(agent, nil)
+
+	if got := promtestutil.ToFloat64(AgentCondition.WithLabelValues("Synthesized", "true", agent.Namespace, agent.Name)); got != 1 {
+		t.Errorf("Expected Synthesized=true gauge to be 1 after transition, got %v", got)
+	}
+	if got := promtestutil.ToFloat64(AgentCondition.WithLabelValues("Synthesized", "false", agent.Namespace, agent.Name)); got != 0 {
+		t.Errorf("Expected Synthesized=false gauge to be 0 after transition, got %v", got)
+	}
+}
+
+func TestAgentConditionMetrics_FiltersConditionTypes(t *testing.T) {
+	agent := newConditionMetricsTestAgent("condition-filter")
+	defer deleteAgentConditionMetrics(agent.Namespace, agent.Name)
+
+	before := promtestutil.CollectAndCount(AgentCondition)
+	recordAgentConditionMetrics(agent, []string{"Synthesized"})
+
+	// Only the three status series for Synthesized should have been added
+	if got := promtestutil.CollectAndCount(AgentCondition) - before; got != 3 {
+		t.Errorf("Expected 3 new series for the allowed condition type, got %d", got)
+	}
+}
+
+func TestAgentConditionMetrics_RemovedOnDeletion(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	agent := newConditionMetricsTestAgent("condition-deleted")
+
+	before := promtestutil.CollectAndCount(AgentCondition)
+	recordAgentConditionMetrics(agent, nil)
+	if promtestutil.CollectAndCount(AgentCondition) == before {
+		t.Fatal("Expected condition gauges to be recorded")
+	}
+
+	// The agent no longer exists, so reconcile must clean up its gauges
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	reconciler := &LanguageAgentReconciler{
+		Client:   fakeClient,
+		Scheme:   scheme,
+		Log:      logr.Discard(),
+		Recorder: record.NewFakeRecorder(10),
+	}
+
+	_, err := reconciler.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace},
+	})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if got := promtestutil.CollectAndCount(AgentCondition); got != before {
+		t.Errorf("Expected condition gauges to be removed after deletion, %d series remain", got-before)
+	}
+}
@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// agentDocsKey is the key of the markdown documentation in the agent's docs ConfigMap
+const agentDocsKey = "README.md"
+
+// reconcileAgentDocs keeps the <agent>-docs ConfigMap documenting code in sync with
+// spec.generateDocs. Documentation is only synthesized when the code it was generated from
+// changed, and removed when the agent no longer asks for it.
+func (r *LanguageAgentReconciler) reconcileAgentDocs(ctx context.Context, agent *langopv1alpha1.LanguageAgent, code string) error {
+	docsConfigMapName := GenerateConfigMapName(agent.Name, "docs")
+
+	existing := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: docsConfigMapName, Namespace: agent.Namespace}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	found := err == nil
+
+	if !agent.Spec.GenerateDocs {
+		if found {
+			return DeleteConfigMap(ctx, r.Client, docsConfigMapName, agent.Namespace)
+		}
+		return nil
+	}
+	if code == "" || (found && existing.Annotations[codeHashAnnotation] == hashString(code)) {
+		return nil
+	}
+
+	synthesizer, _, err := r.createSynthesizer(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("failed to create synthesizer: %w", err)
+	}
+	return r.writeAgentDocs(ctx, agent, synthesizer, code)
+}
+
+// writeAgentDocs synthesizes documentation of code and stores it in the agent's docs ConfigMap,
+// annotated with the hash of the code it describes. A failed attempt isn't retried for the same
+// code until its backoff passes, since every attempt is a paid model call.
+func (r *LanguageAgentReconciler) writeAgentDocs(ctx context.Context, agent *langopv1alpha1.LanguageAgent, synthesizer synthesis.AgentSynthesizer, code string) error {
+	log := log.FromContext(ctx)
+
+	docsSynthesizer, ok := synthesizer.(synthesis.DocsSynthesizer)
+	if !ok {
+		log.V(1).Info("Synthesizer does not support documentation, skipping", "agent", agent.Name)
+		return nil
+	}
+
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	codeHash := hashString(code)
+	if wait := r.getDocsFailures().Remaining(key, codeHash); wait > 0 {
+		log.V(1).Info("Agent documentation failed recently, skipping", "agent", agent.Name, "retryIn", wait)
+		return nil
+	}
+
+	docs, err := r.synthesizeAgentDocs(ctx, agent, docsSynthesizer, code)
+	if synthesis.IsNoSynthesisSlot(err) {
+		// Not a failure: the next reconcile documents the code once a slot is free
+		log.V(1).Info("No synthesis slot free for documentation, retrying later", "agent", agent.Name)
+		return nil
+	}
+	if err != nil {
+		backoff := r.getDocsFailures().Failed(key, codeHash)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "DocsGenerationFailed",
+				"Agent documentation synthesis failed, retrying in %s: %v", backoff, err)
+		}
+		return fmt.Errorf("documentation synthesis failed: %w", err)
+	}
+	r.getDocsFailures().Forget(key)
+
+	docsConfigMapName := GenerateConfigMapName(agent.Name, "docs")
+	annotations := map[string]string{
+		codeHashAnnotation:         codeHash,
+		"langop.io/synthesized-at": metav1.Now().Format("2006-01-02T15:04:05Z"),
+	}
+	if err := CreateOrUpdateConfigMapWithAnnotations(ctx, r.Client, r.Scheme, agent, docsConfigMapName, agent.Namespace,
+		map[string]string{agentDocsKey: docs}, annotations); err != nil {
+		return err
+	}
+
+	log.Info("Agent documentation synthesized", "agent", agent.Name, "configMap", docsConfigMapName)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeNormal, "DocsGenerated", "Documented the agent's code in ConfigMap %s", docsConfigMapName)
+	}
+	return nil
+}
+
+// synthesizeAgentDocs calls the model for documentation of code, metered like code synthesis:
+// it consumes the synthesis rate limit and an attempt of the synthesis quota, and its cost is
+// charged to the agent
+func (r *LanguageAgentReconciler) synthesizeAgentDocs(ctx context.Context, agent *langopv1alpha1.LanguageAgent, docsSynthesizer synthesis.DocsSynthesizer, code string) (string, error) {
+	log := log.FromContext(ctx)
+	req := synthesis.DocsSynthesisRequest{
+		AgentSynthesisRequest: synthesis.AgentSynthesisRequest{
+			Instructions: r.synthesisInstructions(agent),
+			Tools:        r.getToolNames(agent),
+			ToolSchemas:  r.getToolSchemas(ctx, agent),
+			AgentName:    agent.Name,
+			Namespace:    agent.Namespace,
+		},
+		Code: code,
+	}
+
+	if r.RateLimiter != nil {
+		if err := r.RateLimiter.CheckAndConsume(ctx, agent.Namespace); err != nil {
+			synthesis.RecordSynthesisRateLimitExceeded(agent.Namespace)
+			return "", &synthesis.QuotaExceededError{Err: fmt.Errorf("synthesis rate limit exceeded: %w", err)}
+		}
+	}
+
+	if r.QuotaManager != nil {
+		defer r.recordSynthesisQuota(agent)
+		if err := r.QuotaManager.ReserveAgentAttempt(ctx, agent.Namespace, agent.Name, agentQuotaLimits(agent)); err != nil {
+			synthesis.RecordSynthesisQuotaExceeded(agent.Namespace, "attempts")
+			return "", &synthesis.QuotaExceededError{Err: fmt.Errorf("synthesis attempt quota exceeded: %w", err)}
+		}
+		if err := r.checkSynthesisCostEstimate(ctx, agent, req.AgentSynthesisRequest); err != nil {
+			r.QuotaManager.ReleaseAgentAttempt(agent.Namespace, agent.Name)
+			return "", err
+		}
+	}
+
+	if err := r.SynthesisSlots.Acquire(ctx, agent.Namespace, agent.Name); err != nil {
+		if r.QuotaManager != nil {
+			r.QuotaManager.ReleaseAgentAttempt(agent.Namespace, agent.Name)
+		}
+		return "", fmt.Errorf("failed to acquire synthesis slot: %w", err)
+	}
+	resp, err := docsSynthesizer.SynthesizeDocs(ctx, req)
+	r.SynthesisSlots.Release()
+
+	if r.QuotaManager != nil {
+		errorMsg := ""
+		if err != nil {
+			errorMsg = err.Error()
+		}
+		r.QuotaManager.CompleteAgentAttempt(ctx, agent.Namespace, agent.Name, err == nil, errorMsg)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Like code synthesis, documentation the synthesizer reported no cost for is priced with the
+	// model's configured pricing
+	if resp.Cost == nil {
+		// Without a model there's no pricing, which only agents requiring cost data refuse
+		model, _, err := r.resolveSynthesisModel(ctx, agent)
+		if err != nil {
+			log.V(1).Info("Failed to resolve the synthesis model to price documentation with", "agent", agent.Name, "error", err.Error())
+		}
+		// The documented code is sent to the model along with the request
+		pricedReq := req.AgentSynthesisRequest
+		pricedReq.LastKnownGoodCode = req.Code
+		if resp.Cost, err = r.pricedCost(ctx, agent, model, pricedReq, resp.Docs); err != nil {
+			return "", err
+		}
+	}
+	if r.QuotaManager != nil && resp.Cost != nil {
+		if err := r.QuotaManager.RecordCost(ctx, agent.Namespace, agent.Name, resp.Cost); err != nil {
+			log.Error(err, "Failed to record documentation synthesis cost")
+		}
+	}
+	return resp.Docs, nil
+}
+
+// docsFailure is the last failed documentation attempt of an agent
+type docsFailure struct {
+	codeHash string
+	failures int32
+	retryAt  time.Time
+}
+
+// docsFailureTracker backs off documentation synthesis of agents whose last attempt failed.
+// The docs ConfigMap records only successful attempts, so without it a failing agent would
+// call the model again on every reconcile.
+type docsFailureTracker struct {
+	mu     sync.Mutex
+	agents map[types.NamespacedName]*docsFailure
+	now    func() time.Time
+}
+
+func newDocsFailureTracker() *docsFailureTracker {
+	return &docsFailureTracker{agents: make(map[types.NamespacedName]*docsFailure), now: time.Now}
+}
+
+// Remaining returns how long documenting codeHash stays paused for the agent. Changed code is
+// documented right away.
+func (t *docsFailureTracker) Remaining(key types.NamespacedName, codeHash string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failure, ok := t.agents[key]
+	if !ok || failure.codeHash != codeHash {
+		return 0
+	}
+	return max(failure.retryAt.Sub(t.now()), 0)
+}
+
+// Failed records a failed attempt to document codeHash and returns how long the agent backs off,
+// doubling from a minute up to 16 minutes with each consecutive failure
+func (t *docsFailureTracker) Failed(key types.NamespacedName, codeHash string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	failure, ok := t.agents[key]
+	if !ok || failure.codeHash != codeHash {
+		failure = &docsFailure{codeHash: codeHash}
+		t.agents[key] = failure
+	}
+	backoff := calculateBackoff(failure.failures)
+	failure.failures++
+	failure.retryAt = t.now().Add(backoff)
+	return backoff
+}
+
+// Forget clears the agent's failures, after documentation succeeded
+func (t *docsFailureTracker) Forget(key types.NamespacedName) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.agents, key)
+}
+
+func (r *LanguageAgentReconciler) getDocsFailures() *docsFailureTracker {
+	r.docsFailuresOnce.Do(func() {
+		r.docsFailures = newDocsFailureTracker()
+	})
+	return r.docsFailures
+}
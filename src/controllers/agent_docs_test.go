@@ -0,0 +1,250 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// docsSynthesizer is a MockSynthesizer that also documents code
+type docsSynthesizer struct {
+	MockSynthesizer
+	req   *synthesis.DocsSynthesisRequest
+	calls int
+	err   error
+	cost  *synthesis.SynthesisCost
+}
+
+func (s *docsSynthesizer) SynthesizeDocs(ctx context.Context, req synthesis.DocsSynthesisRequest) (*synthesis.DocsSynthesisResponse, error) {
+	s.req = &req
+	s.calls++
+	if s.err != nil {
+		return &synthesis.DocsSynthesisResponse{Cost: s.cost}, s.err
+	}
+	return &synthesis.DocsSynthesisResponse{Docs: "## Overview\nDocuments " + req.AgentName, Cost: s.cost}, nil
+}
+
+func newDocsAgent(generateDocs bool) *langopv1alpha1.LanguageAgent {
+	return &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "documented", Namespace: "default", UID: "documented-uid"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Instructions: "Summarize the news every morning",
+			GenerateDocs: generateDocs,
+		},
+	}
+}
+
+func TestLanguageAgentController_WriteAgentDocs(t *testing.T) {
+	agent := newDocsAgent(true)
+	reconciler, fakeClient := newForceWorkloadReconciler(t)
+	synthesizer := &docsSynthesizer{}
+	code := "agent \"documented\" do\nend"
+
+	if err := reconciler.writeAgentDocs(context.Background(), agent, synthesizer, code); err != nil {
+		t.Fatalf("writeAgentDocs failed: %v", err)
+	}
+	if synthesizer.req == nil || synthesizer.req.Code != code || synthesizer.req.Instructions != agent.Spec.Instructions {
+		t.Fatalf("Expected the code and instructions to be documented, got %+v", synthesizer.req)
+	}
+
+	docs := &corev1.ConfigMap{}
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "documented-docs", Namespace: "default"}, docs); err != nil {
+		t.Fatalf("Expected the docs ConfigMap to be created: %v", err)
+	}
+	if docs.Data[agentDocsKey] != "## Overview\nDocuments documented" {
+		t.Errorf("Expected the documentation to be stored, got %q", docs.Data[agentDocsKey])
+	}
+	if docs.Annotations[codeHashAnnotation] != hashString(code) {
+		t.Errorf("Expected the docs to record the hash of the code they describe, got %q", docs.Annotations[codeHashAnnotation])
+	}
+	if len(docs.OwnerReferences) != 1 || docs.OwnerReferences[0].UID != agent.UID {
+		t.Errorf("Expected the docs ConfigMap to be owned by the agent, got %+v", docs.OwnerReferences)
+	}
+	if events := drainEvents(reconciler.Recorder.(*record.FakeRecorder)); !hasEvent(events, "DocsGenerated") {
+		t.Errorf("Expected a DocsGenerated event, got %v", events)
+	}
+
+	// Synthesizers that can't document code are skipped
+	if err := reconciler.writeAgentDocs(context.Background(), newDocsAgent(true), &MockSynthesizer{}, "changed"); err != nil {
+		t.Errorf("Expected synthesizers without documentation support to be skipped, got %v", err)
+	}
+}
+
+func TestLanguageAgentController_ReconcileAgentDocs(t *testing.T) {
+	code := "agent \"documented\" do\nend"
+	newDocsConfigMap := func() *corev1.ConfigMap {
+		return &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "documented-docs",
+				Namespace:   "default",
+				Annotations: map[string]string{codeHashAnnotation: hashString(code)},
+			},
+			Data: map[string]string{agentDocsKey: "## Overview"},
+		}
+	}
+
+	t.Run("up-to-date docs are not regenerated", func(t *testing.T) {
+		// Without a synthesis model any regeneration would fail
+		reconciler, _ := newForceWorkloadReconciler(t, newDocsConfigMap())
+		if err := reconciler.reconcileAgentDocs(context.Background(), newDocsAgent(true), code); err != nil {
+			t.Errorf("Expected up-to-date docs to be kept, got %v", err)
+		}
+	})
+
+	t.Run("changed code regenerates docs", func(t *testing.T) {
+		reconciler, _ := newForceWorkloadReconciler(t, newDocsConfigMap())
+		if err := reconciler.reconcileAgentDocs(context.Background(), newDocsAgent(true), "agent \"documented\" do\n  # changed\nend"); err == nil {
+			t.Error("Expected regeneration to be attempted for changed code")
+		}
+	})
+
+	t.Run("disabled docs are not generated", func(t *testing.T) {
+		reconciler, fakeClient := newForceWorkloadReconciler(t)
+		if err := reconciler.reconcileAgentDocs(context.Background(), newDocsAgent(false), code); err != nil {
+			t.Fatalf("reconcileAgentDocs failed: %v", err)
+		}
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "documented-docs", Namespace: "default"}, &corev1.ConfigMap{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("Expected no docs ConfigMap, got %v", err)
+		}
+	})
+
+	t.Run("disabling docs removes them", func(t *testing.T) {
+		reconciler, fakeClient := newForceWorkloadReconciler(t, newDocsConfigMap())
+		if err := reconciler.reconcileAgentDocs(context.Background(), newDocsAgent(false), code); err != nil {
+			t.Fatalf("reconcileAgentDocs failed: %v", err)
+		}
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "documented-docs", Namespace: "default"}, &corev1.ConfigMap{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("Expected the docs ConfigMap to be deleted, got %v", err)
+		}
+	})
+}
+
+func TestLanguageAgentController_WriteAgentDocsChargesQuota(t *testing.T) {
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpt-4", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai", ModelName: "gpt-4"},
+	}
+	agent := newDocsAgent(true)
+	agent.Spec.ModelRefs = []langopv1alpha1.ModelReference{{Name: "gpt-4"}}
+	reconciler, _ := newForceWorkloadReconciler(t, model)
+	reconciler.QuotaManager = synthesis.NewQuotaManager(10.0, 100, "USD", logr.Discard())
+	synthesizer := &docsSynthesizer{cost: &synthesis.SynthesisCost{InputTokens: 900, OutputTokens: 300, TotalCost: 0.25, Currency: "USD"}}
+
+	ctx := context.Background()
+	if err := reconciler.writeAgentDocs(ctx, agent, synthesizer, "agent \"documented\" do\nend"); err != nil {
+		t.Fatalf("writeAgentDocs failed: %v", err)
+	}
+	usage := reconciler.QuotaManager.GetAgentUsage(agent.Namespace, agent.Name, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if usage.Attempts != 1 || usage.Cost != 0.25 {
+		t.Errorf("Expected the documentation call to use an attempt and cost 0.25, got %+v", usage)
+	}
+
+	// Without attempts left, documentation isn't synthesized
+	agent.Spec.SynthesisQuota = &langopv1alpha1.AgentSynthesisQuota{MaxAttemptsPerDay: int32Ptr(1)}
+	err := reconciler.writeAgentDocs(ctx, agent, synthesizer, "agent \"documented\" do\n  # changed\nend")
+	if !synthesis.IsQuotaExceeded(err) {
+		t.Errorf("Expected a quota error, got %v", err)
+	}
+	if synthesizer.calls != 1 {
+		t.Errorf("Expected no model call over quota, got %d calls", synthesizer.calls)
+	}
+}
+
+func TestLanguageAgentController_WriteAgentDocsPricesUnreportedCost(t *testing.T) {
+	inputCost, outputCost := 0.01, 0.03
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpt-4", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:     "openai",
+			ModelName:    "gpt-4",
+			CostTracking: &langopv1alpha1.CostTrackingSpec{Enabled: true, Currency: "USD", InputTokenCost: &inputCost, OutputTokenCost: &outputCost},
+		},
+	}
+	agent := newDocsAgent(true)
+	agent.Spec.ModelRefs = []langopv1alpha1.ModelReference{{Name: "gpt-4"}}
+	agent.Spec.RequireCostData = true
+	reconciler, _ := newForceWorkloadReconciler(t, model)
+	reconciler.QuotaManager = synthesis.NewQuotaManager(10.0, 100, "USD", logr.Discard())
+
+	ctx := context.Background()
+	if err := reconciler.writeAgentDocs(ctx, agent, &docsSynthesizer{}, "agent \"documented\" do\nend"); err != nil {
+		t.Fatalf("writeAgentDocs failed: %v", err)
+	}
+	usage := reconciler.QuotaManager.GetAgentUsage(agent.Namespace, agent.Name, time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if usage.Cost <= 0 {
+		t.Errorf("Expected the unreported documentation cost to be priced with the model's pricing, got %+v", usage)
+	}
+
+	// Without pricing, an agent requiring cost data refuses the documentation
+	model.Spec.CostTracking = nil
+	reconciler, _ = newForceWorkloadReconciler(t, model)
+	reconciler.QuotaManager = synthesis.NewQuotaManager(10.0, 100, "USD", logr.Discard())
+	err := reconciler.writeAgentDocs(ctx, agent, &docsSynthesizer{}, "agent \"documented\" do\nend")
+	if !synthesis.IsCostDataUnavailable(err) {
+		t.Errorf("Expected a cost data error, got %v", err)
+	}
+}
+
+func TestLanguageAgentController_WriteAgentDocsBacksOff(t *testing.T) {
+	agent := newDocsAgent(true)
+	reconciler, fakeClient := newForceWorkloadReconciler(t)
+	synthesizer := &docsSynthesizer{err: errors.NewServiceUnavailable("model overloaded")}
+	code := "agent \"documented\" do\nend"
+	ctx := context.Background()
+
+	now := time.Now()
+	reconciler.getDocsFailures().now = func() time.Time { return now }
+
+	if err := reconciler.writeAgentDocs(ctx, agent, synthesizer, code); err == nil {
+		t.Fatal("Expected the failed documentation synthesis to be reported")
+	}
+	if events := drainEvents(reconciler.Recorder.(*record.FakeRecorder)); !hasEvent(events, "DocsGenerationFailed") {
+		t.Errorf("Expected a DocsGenerationFailed event, got %v", events)
+	}
+
+	// Reconciles during the backoff don't call the model again for the same code
+	for i := 0; i < 3; i++ {
+		if err := reconciler.writeAgentDocs(ctx, agent, synthesizer, code); err != nil {
+			t.Errorf("Expected documentation to be skipped during the backoff, got %v", err)
+		}
+	}
+	if synthesizer.calls != 1 {
+		t.Errorf("Expected a single model call during the backoff, got %d", synthesizer.calls)
+	}
+
+	// Changed code is documented right away, and the backoff doubles for repeated failures
+	if err := reconciler.writeAgentDocs(ctx, agent, synthesizer, "changed"); err == nil || synthesizer.calls != 2 {
+		t.Errorf("Expected changed code to be documented right away, got err=%v after %d calls", err, synthesizer.calls)
+	}
+	now = now.Add(time.Minute)
+	if err := reconciler.writeAgentDocs(ctx, agent, synthesizer, "changed"); err == nil || synthesizer.calls != 3 {
+		t.Errorf("Expected a retry once the first backoff passed, got err=%v after %d calls", err, synthesizer.calls)
+	}
+	now = now.Add(time.Minute)
+	if err := reconciler.writeAgentDocs(ctx, agent, synthesizer, "changed"); err != nil || synthesizer.calls != 3 {
+		t.Errorf("Expected the second failure to back off longer, got err=%v after %d calls", err, synthesizer.calls)
+	}
+
+	// Once the backoff passes, a successful attempt stores the docs
+	now = now.Add(time.Minute)
+	synthesizer.err = nil
+	if err := reconciler.writeAgentDocs(ctx, agent, synthesizer, "changed"); err != nil {
+		t.Fatalf("writeAgentDocs failed: %v", err)
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "documented-docs", Namespace: "default"}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Expected the docs ConfigMap after the backoff, got %v", err)
+	}
+}
@@ -0,0 +1,164 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// imageUpgradeRequeueInterval is how often a cluster rechecks agents rolling to a new default image
+const imageUpgradeRequeueInterval = 30 * time.Second
+
+// usesDefaultAgentImage reports whether the agent runs the default agent image of the cluster
+func usesDefaultAgentImage(agent *langopv1alpha1.LanguageAgent, cluster *langopv1alpha1.LanguageCluster) bool {
+	return agent.Spec.ClusterRef == cluster.Name && agent.Namespace == cluster.Namespace && agent.Spec.Image == ""
+}
+
+// usesDefaultLogLevel reports whether the agent inherits the default log level of the cluster
+func usesDefaultLogLevel(agent *langopv1alpha1.LanguageAgent, cluster *langopv1alpha1.LanguageCluster) bool {
+	return agent.Spec.ClusterRef == cluster.Name && agent.Namespace == cluster.Namespace && agent.Spec.LogLevel == ""
+}
+
+// agentsForCluster maps a LanguageCluster event to the agents running its default agent image or
+// log level or inheriting its default egress, so changes to those defaults reach them
+func (r *LanguageAgentReconciler) agentsForCluster(ctx context.Context, obj client.Object) []reconcile.Request {
+	cluster, ok := obj.(*langopv1alpha1.LanguageCluster)
+	if !ok {
+		return nil
+	}
+	agents := &langopv1alpha1.LanguageAgentList{}
+	if err := r.List(ctx, agents, client.InNamespace(cluster.Namespace)); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list agents for cluster", "cluster", cluster.Name)
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, agent := range agents.Items {
+		if !usesDefaultAgentImage(&agent, cluster) && !usesDefaultLogLevel(&agent, cluster) && !inheritsClusterEgress(&agent, cluster) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace},
+		})
+	}
+	return requests
+}
+
+// clusterDefaultsChanged passes LanguageCluster updates that change the defaults agentsForCluster
+// fans out, so status updates and unrelated spec changes don't requeue every agent of the cluster
+func clusterDefaultsChanged() predicate.Predicate {
+	return predicate.Funcs{
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldCluster, okOld := e.ObjectOld.(*langopv1alpha1.LanguageCluster)
+			newCluster, okNew := e.ObjectNew.(*langopv1alpha1.LanguageCluster)
+			if !okOld || !okNew {
+				return false
+			}
+			return oldCluster.Spec.DefaultAgentImage != newCluster.Spec.DefaultAgentImage ||
+				oldCluster.Spec.DefaultLogLevel != newCluster.Spec.DefaultLogLevel ||
+				!equality.Semantic.DeepEqual(oldCluster.Spec.DefaultEgress, newCluster.Spec.DefaultEgress)
+		},
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// updateImageUpgradeStatus records in the cluster status how many agents using the default agent
+// image have rolled out the current default. It returns how long until the progress should be
+// checked again, or 0 when no upgrade is in progress.
+func (r *LanguageClusterReconciler) updateImageUpgradeStatus(ctx context.Context, cluster *langopv1alpha1.LanguageCluster) (time.Duration, error) {
+	image := cluster.Spec.DefaultAgentImage
+	if image == "" {
+		cluster.Status.ImageUpgrade = nil
+		return 0, nil
+	}
+
+	agents := &langopv1alpha1.LanguageAgentList{}
+	if err := r.List(ctx, agents, client.InNamespace(cluster.Namespace)); err != nil {
+		return 0, fmt.Errorf("failed to list agents in namespace %s: %w", cluster.Namespace, err)
+	}
+
+	var total, updated int32
+	for i := range agents.Items {
+		agent := &agents.Items[i]
+		if !usesDefaultAgentImage(agent, cluster) {
+			continue
+		}
+		total++
+		rolledOut, err := r.agentRunsImage(ctx, agent, image)
+		if err != nil {
+			return 0, err
+		}
+		if rolledOut {
+			updated++
+		}
+	}
+
+	upgrade := cluster.Status.ImageUpgrade
+	if upgrade == nil || upgrade.Image != image {
+		now := metav1.Now()
+		upgrade = &langopv1alpha1.AgentImageUpgradeStatus{Image: image, StartedAt: &now}
+		cluster.Status.ImageUpgrade = upgrade
+	}
+	upgrade.TotalAgents = total
+	upgrade.UpdatedAgents = updated
+
+	if updated < total {
+		upgrade.CompletedAt = nil
+		return imageUpgradeRequeueInterval, nil
+	}
+	if upgrade.CompletedAt == nil {
+		now := metav1.Now()
+		upgrade.CompletedAt = &now
+		log.FromContext(ctx).Info("Agents rolled out default agent image", "image", image, "agents", total)
+	}
+	return 0, nil
+}
+
+// agentRunsImage reports whether the agent's workload has finished rolling out the image. Agents
+// running as a CronJob are updated once the job template uses the image.
+func (r *LanguageClusterReconciler) agentRunsImage(ctx context.Context, agent *langopv1alpha1.LanguageAgent, image string) (bool, error) {
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+
+	deployment := &appsv1.Deployment{}
+	err := r.Get(ctx, key, deployment)
+	if err == nil {
+		return agentContainerImage(deployment.Spec.Template.Spec.Containers) == image && deploymentRolledOut(deployment), nil
+	}
+	if !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get deployment for agent %s: %w", agent.Name, err)
+	}
+
+	cronJob := &batchv1.CronJob{}
+	if err := r.Get(ctx, key, cronJob); err != nil {
+		if errors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get cronjob for agent %s: %w", agent.Name, err)
+	}
+	return agentContainerImage(cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers) == image, nil
+}
+
+// agentContainerImage returns the image of the agent container, or "" if there is none
+func agentContainerImage(containers []corev1.Container) string {
+	for _, container := range containers {
+		if container.Name == "agent" {
+			return container.Image
+		}
+	}
+	return ""
+}
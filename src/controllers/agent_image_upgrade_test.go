@@ -0,0 +1,274 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func newImageUpgradeCluster(image string) *langopv1alpha1.LanguageCluster {
+	return &langopv1alpha1.LanguageCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet", Namespace: "default", Finalizers: []string{FinalizerName}},
+		Spec:       langopv1alpha1.LanguageClusterSpec{DefaultAgentImage: image},
+		Status:     langopv1alpha1.LanguageClusterStatus{Phase: "Ready"},
+	}
+}
+
+func newImageUpgradeAgent(name, image string) *langopv1alpha1.LanguageAgent {
+	return &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ClusterRef:    "fleet",
+			Image:         image,
+			ExecutionMode: "autonomous",
+		},
+	}
+}
+
+// newAgentDeployment returns an agent Deployment running the image, rolled out when rolledOut is set
+func newAgentDeployment(name, image string, rolledOut bool) *appsv1.Deployment {
+	replicas := int32(1)
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Generation: 2},
+		Spec: appsv1.DeploymentSpec{
+			Replicas: &replicas,
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "agent", Image: image}}},
+			},
+		},
+		Status: appsv1.DeploymentStatus{ObservedGeneration: 1},
+	}
+	if rolledOut {
+		deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: 2, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	}
+	return deployment
+}
+
+func TestLanguageAgentController_ResolveAgentImage(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		newImageUpgradeCluster("ghcr.io/language-operator/agent:v2"),
+		&langopv1alpha1.LanguageCluster{ObjectMeta: metav1.ObjectMeta{Name: "bare", Namespace: "default"}},
+	).Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+	tests := []struct {
+		name       string
+		clusterRef string
+		image      string
+		expected   string
+		expectErr  bool
+	}{
+		{name: "explicit image", clusterRef: "fleet", image: "example.com/custom:v1", expected: "example.com/custom:v1"},
+		{name: "cluster default", clusterRef: "fleet", expected: "ghcr.io/language-operator/agent:v2"},
+		{name: "cluster without default", clusterRef: "bare", expectErr: true},
+		{name: "no image and no cluster", expectErr: true},
+		{name: "missing cluster", clusterRef: "missing", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := newImageUpgradeAgent("agent", tt.image)
+			agent.Spec.ClusterRef = tt.clusterRef
+
+			image, err := reconciler.resolveAgentImage(context.Background(), agent)
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("resolveAgentImage() error = %v, expectErr %v", err, tt.expectErr)
+			}
+			if image != tt.expected {
+				t.Errorf("Expected image %q, got %q", tt.expected, image)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_AgentsForCluster(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	other := newImageUpgradeAgent("other-cluster", "")
+	other.Spec.ClusterRef = "elsewhere"
+	defaultImage := newImageUpgradeAgent("default-image", "")
+	defaultImage.Spec.LogLevel = langopv1alpha1.LogLevelInfo
+	defaultLogLevel := newImageUpgradeAgent("default-log-level", "example.com/custom:v1")
+	explicit := newImageUpgradeAgent("explicit", "example.com/custom:v1")
+	explicit.Spec.LogLevel = langopv1alpha1.LogLevelInfo
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(defaultImage, defaultLogLevel, explicit, other).Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+	requests := reconciler.agentsForCluster(context.Background(), newImageUpgradeCluster("ghcr.io/language-operator/agent:v2"))
+	enqueued := map[string]bool{}
+	for _, request := range requests {
+		enqueued[request.Name] = true
+	}
+	if len(requests) != 2 || !enqueued["default-image"] || !enqueued["default-log-level"] {
+		t.Errorf("Expected only the agents using a cluster default to be enqueued, got %v", requests)
+	}
+}
+
+func TestClusterDefaultsChanged(t *testing.T) {
+	cluster := newImageUpgradeCluster("ghcr.io/language-operator/agent:v1")
+	newImage := cluster.DeepCopy()
+	newImage.Spec.DefaultAgentImage = "ghcr.io/language-operator/agent:v2"
+	newLogLevel := cluster.DeepCopy()
+	newLogLevel.Spec.DefaultLogLevel = langopv1alpha1.LogLevelDebug
+	newEgress := cluster.DeepCopy()
+	newEgress.Spec.DefaultEgress = []langopv1alpha1.NetworkRule{{To: &langopv1alpha1.NetworkPeer{DNS: []string{"api.example.com"}}}}
+	statusOnly := cluster.DeepCopy()
+	statusOnly.Status.Phase = "Failed"
+
+	tests := []struct {
+		name     string
+		new      client.Object
+		expected bool
+	}{
+		{name: "default image", new: newImage, expected: true},
+		{name: "default log level", new: newLogLevel, expected: true},
+		{name: "default egress", new: newEgress, expected: true},
+		{name: "status only", new: statusOnly},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := clusterDefaultsChanged().Update(event.UpdateEvent{ObjectOld: cluster, ObjectNew: tt.new})
+			if got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_DefaultImageChangeRollsAgents(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	cluster := newImageUpgradeCluster("ghcr.io/language-operator/agent:v1")
+	defaultAgent := newImageUpgradeAgent("default-image", "")
+	explicitAgent := newImageUpgradeAgent("explicit-image", "example.com/custom:v1")
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(cluster, defaultAgent, explicitAgent).
+		WithStatusSubresource(cluster, defaultAgent, explicitAgent, &appsv1.Deployment{}).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        record.NewFakeRecorder(100),
+		RegistryManager: &mockRegistryManager{registries: []string{"ghcr.io", "example.com"}},
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	reconcileAgents := func() {
+		t.Helper()
+		for _, name := range []string{"default-image", "explicit-image"} {
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}}); err != nil {
+				t.Fatalf("Reconcile of %s failed: %v", name, err)
+			}
+		}
+	}
+	deploymentImage := func(name string) string {
+		t.Helper()
+		deployment := &appsv1.Deployment{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, deployment); err != nil {
+			t.Fatalf("Failed to get Deployment %s: %v", name, err)
+		}
+		return agentContainerImage(deployment.Spec.Template.Spec.Containers)
+	}
+
+	reconcileAgents()
+	if image := deploymentImage("default-image"); image != "ghcr.io/language-operator/agent:v1" {
+		t.Errorf("Expected the cluster default image, got %s", image)
+	}
+
+	current := &langopv1alpha1.LanguageCluster{}
+	if err := fakeClient.Get(ctx, client.ObjectKeyFromObject(cluster), current); err != nil {
+		t.Fatalf("Failed to get cluster: %v", err)
+	}
+	current.Spec.DefaultAgentImage = "ghcr.io/language-operator/agent:v2"
+	if err := fakeClient.Update(ctx, current); err != nil {
+		t.Fatalf("Failed to update cluster: %v", err)
+	}
+	reconcileAgents()
+
+	if image := deploymentImage("default-image"); image != "ghcr.io/language-operator/agent:v2" {
+		t.Errorf("Expected the agent using the default image to roll to v2, got %s", image)
+	}
+	if image := deploymentImage("explicit-image"); image != "example.com/custom:v1" {
+		t.Errorf("Expected the agent with an explicit image to be left alone, got %s", image)
+	}
+}
+
+func TestLanguageClusterController_TracksImageUpgrade(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	cluster := newImageUpgradeCluster("ghcr.io/language-operator/agent:v2")
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			cluster,
+			newImageUpgradeAgent("updated", ""),
+			newImageUpgradeAgent("rolling", ""),
+			newImageUpgradeAgent("explicit-image", "example.com/custom:v1"),
+			newAgentDeployment("updated", "ghcr.io/language-operator/agent:v2", true),
+			newAgentDeployment("rolling", "ghcr.io/language-operator/agent:v2", false),
+			newAgentDeployment("explicit-image", "example.com/custom:v1", true),
+		).
+		WithStatusSubresource(cluster, &appsv1.Deployment{}).
+		Build()
+	reconciler := &LanguageClusterReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(cluster)}
+	reconcileCluster := func() (ctrl.Result, *langopv1alpha1.AgentImageUpgradeStatus) {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, req)
+		if err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		current := &langopv1alpha1.LanguageCluster{}
+		if err := fakeClient.Get(ctx, req.NamespacedName, current); err != nil {
+			t.Fatalf("Failed to get cluster: %v", err)
+		}
+		if current.Status.ImageUpgrade == nil {
+			t.Fatal("Expected the image upgrade to be tracked in status")
+		}
+		return result, current.Status.ImageUpgrade
+	}
+
+	result, upgrade := reconcileCluster()
+	if upgrade.Image != "ghcr.io/language-operator/agent:v2" || upgrade.TotalAgents != 2 || upgrade.UpdatedAgents != 1 {
+		t.Errorf("Expected 1 of 2 agents updated to v2, got %+v", upgrade)
+	}
+	if upgrade.StartedAt == nil || upgrade.CompletedAt != nil {
+		t.Errorf("Expected a started, incomplete upgrade, got %+v", upgrade)
+	}
+	if result.RequeueAfter != imageUpgradeRequeueInterval {
+		t.Errorf("Expected a requeue while agents roll out, got %+v", result)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "rolling", Namespace: "default"}, deployment); err != nil {
+		t.Fatalf("Failed to get Deployment: %v", err)
+	}
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.Generation, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	if err := fakeClient.Status().Update(ctx, deployment); err != nil {
+		t.Fatalf("Failed to update Deployment status: %v", err)
+	}
+
+	result, upgrade = reconcileCluster()
+	if upgrade.UpdatedAgents != 2 || upgrade.CompletedAt == nil {
+		t.Errorf("Expected the upgrade to complete, got %+v", upgrade)
+	}
+	if result.RequeueAfter != 0 {
+		t.Errorf("Expected no requeue once the upgrade completed, got %+v", result)
+	}
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"context"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// agentQuotaLimits returns the daily synthesis limits the agent sets in spec.synthesisQuota
+func agentQuotaLimits(agent *langopv1alpha1.LanguageAgent) synthesis.AgentQuota {
+	var limits synthesis.AgentQuota
+	if quota := agent.Spec.SynthesisQuota; quota != nil {
+		if quota.MaxCostPerDay != nil {
+			limits.MaxCostPerDay = *quota.MaxCostPerDay
+		}
+		if quota.MaxAttemptsPerDay != nil {
+			limits.MaxAttemptsPerDay = int(*quota.MaxAttemptsPerDay)
+		}
+	}
+	return limits
+}
+
+// recordSynthesisQuota records the synthesis quota the agent has left today in status.synthesisQuota
+func (r *LanguageAgentReconciler) recordSynthesisQuota(agent *langopv1alpha1.LanguageAgent) {
+	if r.QuotaManager == nil {
+		return
+	}
+	remainingCost, remainingAttempts := r.QuotaManager.GetRemainingAgentQuota(agent.Namespace, agent.Name, agentQuotaLimits(agent))
+	_, _, currency := r.QuotaManager.Limits()
+	agent.Status.SynthesisQuota = &langopv1alpha1.AgentSynthesisQuotaStatus{
+		RemainingCost:     remainingCost,
+		RemainingAttempts: int32(remainingAttempts),
+		Currency:          currency,
+	}
+}
+
+// candidateBudget meters candidate synthesis against the agent's quotas, or returns nil when
+// quotas aren't enforced
+func (r *LanguageAgentReconciler) candidateBudget(ctx context.Context, agent *langopv1alpha1.LanguageAgent) synthesis.CandidateBudget {
+	if r.QuotaManager == nil {
+		return nil
+	}
+	limits := agentQuotaLimits(agent)
+	remainingCost, _ := r.QuotaManager.GetRemainingAgentQuota(agent.Namespace, agent.Name, limits)
+	return &quotaCandidateBudget{
+		ctx:             ctx,
+		quota:           r.QuotaManager,
+		namespace:       agent.Namespace,
+		agentName:       agent.Name,
+		limits:          limits,
+		remainingCost:   remainingCost,
+		requireCostData: agent.Spec.RequireCostData,
+	}
+}
+
+// quotaCandidateBudget charges every candidate after the first a synthesis attempt of its own,
+// and only admits a candidate while the cost spent so far plus its projected cost fits the cost
+// quota that remained when synthesis started. Agents with spec.requireCostData get no further
+// candidates once one reported no cost, since the cost quota can't meter them.
+type quotaCandidateBudget struct {
+	ctx             context.Context
+	quota           *synthesis.QuotaManager
+	namespace       string
+	agentName       string
+	limits          synthesis.AgentQuota
+	remainingCost   float64
+	requireCostData bool
+}
+
+// Allow implements synthesis.CandidateBudget
+func (b *quotaCandidateBudget) Allow(generated int, spent *synthesis.SynthesisCost) bool {
+	if spent == nil && generated > 0 && b.requireCostData {
+		return false
+	}
+	if spent != nil && generated > 0 {
+		cost, ok := b.quota.ConvertCost(spent.TotalCost, spent.Currency)
+		// Project the next candidate to cost as much as the average one so far
+		if !ok || cost+cost/float64(generated) > b.remainingCost {
+			return false
+		}
+	}
+	return b.quota.ReserveAgentAttempt(b.ctx, b.namespace, b.agentName, b.limits) == nil
+}
+
+// Done implements synthesis.CandidateBudget
+func (b *quotaCandidateBudget) Done(success bool, errorMsg string) {
+	b.quota.CompleteAgentAttempt(b.ctx, b.namespace, b.agentName, success, errorMsg)
+}
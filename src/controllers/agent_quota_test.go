@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAgentQuotaLimits(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{}
+	if limits := agentQuotaLimits(agent); limits != (synthesis.AgentQuota{}) {
+		t.Errorf("Expected no limits without spec.synthesisQuota, got %+v", limits)
+	}
+
+	maxCost := 2.5
+	agent.Spec.SynthesisQuota = &langopv1alpha1.AgentSynthesisQuota{MaxCostPerDay: &maxCost, MaxAttemptsPerDay: int32Ptr(3)}
+	if limits := agentQuotaLimits(agent); limits != (synthesis.AgentQuota{MaxCostPerDay: 2.5, MaxAttemptsPerDay: 3}) {
+		t.Errorf("Expected limits from spec.synthesisQuota, got %+v", limits)
+	}
+}
+
+func TestLanguageAgentController_AgentSynthesisQuotaExceeded(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpt-4", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai", ModelName: "gpt-4"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "thrifty-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:          "ghcr.io/language-operator/agent:latest",
+			Instructions:   "Summarize open incidents",
+			ModelRefs:      []langopv1alpha1.ModelReference{{Name: "gpt-4"}},
+			SynthesisQuota: &langopv1alpha1.AgentSynthesisQuota{MaxAttemptsPerDay: int32Ptr(1)},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(model, agent).
+		WithStatusSubresource(agent).
+		Build()
+	recorder := record.NewFakeRecorder(20)
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        recorder,
+		RegistryManager: &mockRegistryManager{},
+		QuotaManager:    synthesis.NewQuotaManager(10.0, 100, "USD", logr.Discard()),
+	}
+	reconciler.InitializeGatewayCache()
+
+	// The agent already used its one attempt today; the namespace has plenty left
+	ctx := context.Background()
+	reconciler.QuotaManager.RecordAttempt(ctx, agent.Namespace, agent.Name, false, "validation failed")
+
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if !synthesis.IsQuotaExceeded(err) {
+		t.Fatalf("Expected a quota error, got %v", err)
+	}
+	if events := drainEvents(recorder); !hasEvent(events, "QuotaExceeded") || hasEvent(events, "SynthesisStarted") {
+		t.Errorf("Expected QuotaExceeded before synthesis started, got %v", events)
+	}
+	if _, remainingAttempts := reconciler.QuotaManager.GetRemainingQuota(agent.Namespace); remainingAttempts != 99 {
+		t.Errorf("Expected 99 remaining namespace attempts, got %d", remainingAttempts)
+	}
+
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if updated.Status.FailureReason != langopv1alpha1.FailureReasonQuota {
+		t.Errorf("Expected failure reason %q, got %q", langopv1alpha1.FailureReasonQuota, updated.Status.FailureReason)
+	}
+	expected := langopv1alpha1.AgentSynthesisQuotaStatus{RemainingCost: 10.0, RemainingAttempts: 0, Currency: "USD"}
+	if updated.Status.SynthesisQuota == nil || *updated.Status.SynthesisQuota != expected {
+		t.Errorf("Expected synthesis quota status %+v, got %+v", expected, updated.Status.SynthesisQuota)
+	}
+}
+
+func TestQuotaCandidateBudget(t *testing.T) {
+	ctx := context.Background()
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "picky-agent", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{SynthesisQuota: &langopv1alpha1.AgentSynthesisQuota{MaxAttemptsPerDay: int32Ptr(3)}},
+	}
+	quota := synthesis.NewQuotaManager(1.0, 100, "USD", logr.Discard())
+	reconciler := &LanguageAgentReconciler{QuotaManager: quota}
+	budget := reconciler.candidateBudget(ctx, agent)
+
+	// The next candidate is projected to cost as much as the average one so far
+	if budget.Allow(2, &synthesis.SynthesisCost{TotalCost: 0.7, Currency: "USD"}) {
+		t.Error("Expected a candidate projected past the remaining cost quota to be refused")
+	}
+	if !budget.Allow(1, &synthesis.SynthesisCost{TotalCost: 0.4, Currency: "USD"}) {
+		t.Fatal("Expected a candidate within the remaining cost quota to be admitted")
+	}
+	budget.Done(false, "validation failed")
+	if _, attempts := quota.GetRemainingAgentQuota(agent.Namespace, agent.Name, agentQuotaLimits(agent)); attempts != 2 {
+		t.Errorf("Expected the candidate to use one of the agent's attempts, got %d remaining", attempts)
+	}
+
+	// Each candidate reserves an attempt, so the agent's attempt limit caps candidates too
+	if !budget.Allow(1, nil) || !budget.Allow(1, nil) {
+		t.Fatal("Expected the remaining attempts to admit two more candidates")
+	}
+	if budget.Allow(1, nil) {
+		t.Error("Expected a candidate beyond the agent's attempt limit to be refused")
+	}
+}
+
+func TestQuotaCandidateBudget_RequireCostData(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "strict-agent", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{RequireCostData: true},
+	}
+	reconciler := &LanguageAgentReconciler{QuotaManager: synthesis.NewQuotaManager(1.0, 100, "USD", logr.Discard())}
+	budget := reconciler.candidateBudget(context.Background(), agent)
+
+	if budget.Allow(1, nil) {
+		t.Error("Expected no further candidates once a candidate reported no cost")
+	}
+	if !budget.Allow(1, &synthesis.SynthesisCost{TotalCost: 0.1, Currency: "USD"}) {
+		t.Error("Expected candidates with reported costs to be metered as usual")
+	}
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ArtifactTTLAnnotation marks an operator-generated debug, preview, or analysis ConfigMap as
+// disposable. The value is a duration measured from the artifact's last write, after which the
+// ArtifactCleaner deletes it. Owner references still remove artifacts when their agent is deleted.
+const ArtifactTTLAnnotation = "langop.io/artifact-ttl"
+
+// ArtifactWrittenAtAnnotation records when an artifact was last written, in RFC 3339. Artifacts
+// without it expire relative to their creation.
+const ArtifactWrittenAtAnnotation = "langop.io/artifact-written-at"
+
+// ArtifactPendingApprovalAnnotation is set to "true" on an artifact a human still has to review;
+// it is kept past its TTL until the annotation is removed
+const ArtifactPendingApprovalAnnotation = "langop.io/artifact-pending-approval"
+
+// DefaultArtifactCleanupInterval is how often expired artifacts are looked for
+const DefaultArtifactCleanupInterval = 10 * time.Minute
+
+// setArtifactTTL marks obj as an artifact expiring ttl after now, its latest write; zero or less
+// leaves it unmarked. Writers call it on every write so a rewritten artifact isn't deleted early.
+func setArtifactTTL(obj metav1.Object, ttl time.Duration, now time.Time) {
+	if ttl <= 0 {
+		return
+	}
+	annotations := obj.GetAnnotations()
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[ArtifactTTLAnnotation] = ttl.String()
+	annotations[ArtifactWrittenAtAnnotation] = now.UTC().Format(time.RFC3339)
+	obj.SetAnnotations(annotations)
+}
+
+// setArtifactPendingApproval marks obj as awaiting review, or clears the mark
+func setArtifactPendingApproval(obj metav1.Object, pending bool) {
+	annotations := obj.GetAnnotations()
+	if !pending {
+		delete(annotations, ArtifactPendingApprovalAnnotation)
+		obj.SetAnnotations(annotations)
+		return
+	}
+	if annotations == nil {
+		annotations = make(map[string]string)
+	}
+	annotations[ArtifactPendingApprovalAnnotation] = "true"
+	obj.SetAnnotations(annotations)
+}
+
+// artifactExpired reports whether obj carries an artifact TTL that has elapsed at now, counted
+// from its last write. Objects with a missing or unparsable TTL, or pending approval, never expire.
+func artifactExpired(obj metav1.Object, now time.Time) bool {
+	annotations := obj.GetAnnotations()
+	value, ok := annotations[ArtifactTTLAnnotation]
+	if !ok || annotations[ArtifactPendingApprovalAnnotation] == "true" {
+		return false
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		return false
+	}
+	writtenAt := obj.GetCreationTimestamp().Time
+	if value, ok := annotations[ArtifactWrittenAtAnnotation]; ok {
+		if parsed, err := time.Parse(time.RFC3339, value); err == nil && parsed.After(writtenAt) {
+			writtenAt = parsed
+		}
+	}
+	return !now.Before(writtenAt.Add(ttl))
+}
+
+// ArtifactCleaner periodically deletes operator-generated ConfigMaps whose artifact TTL has
+// expired, so debug tooling doesn't clutter the namespaces of long-lived agents
+type ArtifactCleaner struct {
+	Client   client.Client
+	Log      logr.Logger
+	Interval time.Duration
+
+	now func() time.Time
+}
+
+// Start runs a cleanup pass every Interval until ctx is done. It implements manager.Runnable.
+func (c *ArtifactCleaner) Start(ctx context.Context) error {
+	interval := c.Interval
+	if interval <= 0 {
+		interval = DefaultArtifactCleanupInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := c.Cleanup(ctx); err != nil {
+			c.Log.Error(err, "Failed to clean up expired artifacts")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Cleanup deletes the agent ConfigMaps whose artifact TTL has expired and returns how many were deleted
+func (c *ArtifactCleaner) Cleanup(ctx context.Context) (int, error) {
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+
+	configMaps := &corev1.ConfigMapList{}
+	if err := c.Client.List(ctx, configMaps, client.HasLabels{"langop.io/agent"}); err != nil {
+		return 0, err
+	}
+
+	deleted := 0
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		if !artifactExpired(configMap, now) {
+			continue
+		}
+		if err := c.Client.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+			c.Log.Error(err, "Failed to delete expired artifact", "configMap", configMap.Name, "namespace", configMap.Namespace)
+			continue
+		}
+		c.Log.V(1).Info("Deleted expired artifact", "configMap", configMap.Name, "namespace", configMap.Namespace,
+			"ttl", configMap.Annotations[ArtifactTTLAnnotation])
+		deleted++
+	}
+	return deleted, nil
+}
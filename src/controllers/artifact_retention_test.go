@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestArtifactCleaner_Cleanup(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	artifact := func(name string, age time.Duration, ttl string, annotations ...string) *corev1.ConfigMap {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:              name,
+				Namespace:         "default",
+				Labels:            map[string]string{"langop.io/agent": "reviewer"},
+				CreationTimestamp: metav1.NewTime(now.Add(-age)),
+			},
+		}
+		if ttl != "" {
+			configMap.Annotations = map[string]string{ArtifactTTLAnnotation: ttl}
+		}
+		for i := 0; i+1 < len(annotations); i += 2 {
+			configMap.Annotations[annotations[i]] = annotations[i+1]
+		}
+		return configMap
+	}
+
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("failed to build scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		artifact("expired-analysis", 48*time.Hour, "24h"),
+		artifact("fresh-analysis", time.Hour, "24h"),
+		artifact("agent-code", 48*time.Hour, ""),
+		artifact("invalid-ttl", 48*time.Hour, "forever"),
+		artifact("rewritten-analysis", 48*time.Hour, "24h", ArtifactWrittenAtAnnotation, now.Add(-time.Hour).Format(time.RFC3339)),
+		artifact("stale-rewrite", 72*time.Hour, "24h", ArtifactWrittenAtAnnotation, now.Add(-48*time.Hour).Format(time.RFC3339)),
+		artifact("pending-analysis", 48*time.Hour, "24h", ArtifactPendingApprovalAnnotation, "true"),
+	).Build()
+
+	cleaner := &ArtifactCleaner{Client: fakeClient, Log: logr.Discard(), now: func() time.Time { return now }}
+	deleted, err := cleaner.Cleanup(context.Background())
+	if err != nil {
+		t.Fatalf("Cleanup failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Expected 2 deleted artifacts, got %d", deleted)
+	}
+
+	tests := []struct {
+		name         string
+		expectExists bool
+	}{
+		{name: "expired-analysis", expectExists: false},
+		{name: "fresh-analysis", expectExists: true},
+		{name: "agent-code", expectExists: true},
+		{name: "invalid-ttl", expectExists: true},
+		{name: "rewritten-analysis", expectExists: true},
+		{name: "stale-rewrite", expectExists: false},
+		{name: "pending-analysis", expectExists: true},
+	}
+	for _, tt := range tests {
+		err := fakeClient.Get(context.Background(), types.NamespacedName{Name: tt.name, Namespace: "default"}, &corev1.ConfigMap{})
+		if tt.expectExists && err != nil {
+			t.Errorf("Expected %s to be retained, got error: %v", tt.name, err)
+		}
+		if !tt.expectExists && !errors.IsNotFound(err) {
+			t.Errorf("Expected %s to be deleted, got err=%v", tt.name, err)
+		}
+	}
+}
+
+func TestSetArtifactTTL(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	configMap := &corev1.ConfigMap{}
+	setArtifactTTL(configMap, 0, now)
+	if _, ok := configMap.Annotations[ArtifactTTLAnnotation]; ok {
+		t.Errorf("Expected no TTL annotation for a zero TTL")
+	}
+
+	setArtifactTTL(configMap, 72*time.Hour, now)
+	if ttl := configMap.Annotations[ArtifactTTLAnnotation]; ttl != "72h0m0s" {
+		t.Errorf("Expected TTL annotation 72h0m0s, got %q", ttl)
+	}
+	if writtenAt := configMap.Annotations[ArtifactWrittenAtAnnotation]; writtenAt != "2025-06-01T12:00:00Z" {
+		t.Errorf("Expected written-at annotation 2025-06-01T12:00:00Z, got %q", writtenAt)
+	}
+}
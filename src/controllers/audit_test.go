@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/audit"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// recordingSink collects audit records in memory
+type recordingSink struct {
+	mu      sync.Mutex
+	records []audit.Record
+}
+
+func (s *recordingSink) Write(ctx context.Context, record audit.Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.records = append(s.records, record)
+	return nil
+}
+
+func (s *recordingSink) actions() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var actions []string
+	for _, record := range s.records {
+		actions = append(actions, record.Action)
+	}
+	return actions
+}
+
+// runAuditEmitter starts an emitter writing to sink and returns it with a function stopping it
+// once its queued records are written
+func runAuditEmitter(t *testing.T, sink audit.Sink) (*audit.Emitter, func()) {
+	t.Helper()
+	emitter := audit.NewEmitter(sink, logr.Discard())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- emitter.Start(ctx) }()
+	return emitter, func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Start failed: %v", err)
+		}
+	}
+}
+
+func TestLanguageAgentController_AuditsSynthesisFailure(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	// Self-healing attempts are exhausted, so synthesis fails without calling a model
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "audited-agent", Namespace: "default", UID: "agent-uid"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			Instructions:  "Summarize the news",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+		},
+		Status: langopv1alpha1.LanguageAgentStatus{
+			ConsecutiveFailures: 3,
+			SelfHealingAttempts: 2,
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent).
+		WithStatusSubresource(agent).
+		Build()
+
+	sink := &recordingSink{}
+	emitter, stopAudit := runAuditEmitter(t, sink)
+	reconciler := &LanguageAgentReconciler{
+		Client:                 fakeClient,
+		Scheme:                 scheme,
+		Log:                    logr.Discard(),
+		Recorder:               &record.FakeRecorder{},
+		RegistryManager:        &mockRegistryManager{},
+		SelfHealingEnabled:     true,
+		MaxSelfHealingAttempts: 2,
+		Audit:                  emitter,
+	}
+	reconciler.InitializeGatewayCache()
+
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: key}); err == nil {
+		t.Fatal("Expected Reconcile to fail when self-healing attempts are exhausted")
+	}
+	stopAudit()
+
+	if len(sink.records) != 1 {
+		t.Fatalf("Expected 1 audit record, got %v", sink.actions())
+	}
+	got := sink.records[0]
+	if got.Action != audit.ActionSynthesisFailed || got.Controller != auditControllerLanguageAgent {
+		t.Errorf("Expected %s from %s, got %s from %s", audit.ActionSynthesisFailed, auditControllerLanguageAgent, got.Action, got.Controller)
+	}
+	if got.Name != agent.Name || got.UID != "agent-uid" {
+		t.Errorf("Expected record for agent %s, got %s (uid %s)", agent.Name, got.Name, got.UID)
+	}
+	if got.Details["reason"] != "SynthesisFailed" {
+		t.Errorf("Expected reason SynthesisFailed, got %q", got.Details["reason"])
+	}
+}
+
+func TestLearningController_AuditsConversion(t *testing.T) {
+	sink := &recordingSink{}
+	emitter, stopAudit := runAuditEmitter(t, sink)
+	reconciler := &LearningReconciler{
+		Log:      logr.Discard(),
+		Recorder: record.NewFakeRecorder(10),
+		Audit:    emitter,
+	}
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "learning-agent", Namespace: "default"},
+	}
+	trigger := LearningEvent{
+		AgentName:  agent.Name,
+		Namespace:  agent.Namespace,
+		TaskName:   "fetch_news",
+		EventType:  "traces_accumulated",
+		Confidence: 0.92,
+		Timestamp:  time.Now(),
+	}
+
+	reconciler.recordLearningEvent(context.Background(), agent, trigger, 4)
+	stopAudit()
+
+	if len(sink.records) != 1 {
+		t.Fatalf("Expected 1 audit record, got %v", sink.actions())
+	}
+	got := sink.records[0]
+	if got.Action != audit.ActionLearningConverted || got.Controller != auditControllerLearning {
+		t.Errorf("Expected %s from %s, got %s from %s", audit.ActionLearningConverted, auditControllerLearning, got.Action, got.Controller)
+	}
+	if got.Details["task"] != "fetch_news" || got.Details["version"] != "4" {
+		t.Errorf("Unexpected details: %v", got.Details)
+	}
+}
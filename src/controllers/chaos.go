@@ -0,0 +1,63 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ChaosFailureRateEnvVar tells the agent runtime which fraction of its runs to fail deliberately
+const ChaosFailureRateEnvVar = "AGENT_CHAOS_FAILURE_RATE"
+
+// chaosFailureRate returns the fraction of runs the agent should fail, or zero when failure
+// injection doesn't apply. spec.chaos is honored only when the operator runs with --enable-chaos
+// and the agent's namespace carries the chaos testing label, so a stray spec.chaos can't break
+// agents outside a test namespace.
+func (r *LanguageAgentReconciler) chaosFailureRate(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (float64, error) {
+	if agent.Spec.Chaos == nil || agent.Spec.Chaos.InjectFailureRate <= 0 {
+		return 0, nil
+	}
+	logger := log.FromContext(ctx)
+	if !r.ChaosEnabled {
+		logger.Info("Ignoring spec.chaos because failure injection is disabled in the operator", "agent", agent.Name)
+		return 0, nil
+	}
+
+	namespace := &corev1.Namespace{}
+	if err := r.Get(ctx, types.NamespacedName{Name: agent.Namespace}, namespace); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get namespace %s: %w", agent.Namespace, err)
+	}
+	if namespace.Labels[langopv1alpha1.ChaosNamespaceLabel] != "true" {
+		logger.Info("Ignoring spec.chaos because the namespace is not labeled for chaos testing",
+			"agent", agent.Name, "label", langopv1alpha1.ChaosNamespaceLabel)
+		return 0, nil
+	}
+	return min(agent.Spec.Chaos.InjectFailureRate, 1), nil
+}
+
+// chaosEnv returns the failure injection env of the agent container, if failure injection applies
+func (r *LanguageAgentReconciler) chaosEnv(ctx context.Context, agent *langopv1alpha1.LanguageAgent) []corev1.EnvVar {
+	rate, err := r.chaosFailureRate(ctx, agent)
+	if err != nil {
+		// Failing closed keeps failures out of agents whose namespace can't be checked
+		log.FromContext(ctx).Error(err, "Failed to check chaos testing gates, not injecting failures", "agent", agent.Name)
+		return nil
+	}
+	if rate <= 0 {
+		return nil
+	}
+	return []corev1.EnvVar{{
+		Name:  ChaosFailureRateEnvVar,
+		Value: strconv.FormatFloat(rate, 'f', -1, 64),
+	}}
+}
@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+func TestLanguageAgentController_ChaosFailureInjection(t *testing.T) {
+	chaosNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "chaos-tests",
+		Labels: map[string]string{langopv1alpha1.ChaosNamespaceLabel: "true"},
+	}}
+	plainNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "production"}}
+	optedOutNamespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:   "staging",
+		Labels: map[string]string{langopv1alpha1.ChaosNamespaceLabel: "false"},
+	}}
+
+	tests := []struct {
+		name         string
+		chaosEnabled bool
+		namespace    string
+		chaos        *langopv1alpha1.AgentChaosSpec
+		expected     string
+	}{
+		{name: "both gates satisfied", chaosEnabled: true, namespace: "chaos-tests",
+			chaos: &langopv1alpha1.AgentChaosSpec{InjectFailureRate: 0.25}, expected: "0.25"},
+		{name: "every run fails", chaosEnabled: true, namespace: "chaos-tests",
+			chaos: &langopv1alpha1.AgentChaosSpec{InjectFailureRate: 1}, expected: "1"},
+		{name: "operator flag disabled", chaosEnabled: false, namespace: "chaos-tests",
+			chaos: &langopv1alpha1.AgentChaosSpec{InjectFailureRate: 0.25}},
+		{name: "namespace not labeled", chaosEnabled: true, namespace: "production",
+			chaos: &langopv1alpha1.AgentChaosSpec{InjectFailureRate: 0.25}},
+		{name: "namespace label not true", chaosEnabled: true, namespace: "staging",
+			chaos: &langopv1alpha1.AgentChaosSpec{InjectFailureRate: 0.25}},
+		{name: "namespace not found", chaosEnabled: true, namespace: "missing",
+			chaos: &langopv1alpha1.AgentChaosSpec{InjectFailureRate: 0.25}},
+		{name: "zero rate", chaosEnabled: true, namespace: "chaos-tests",
+			chaos: &langopv1alpha1.AgentChaosSpec{InjectFailureRate: 0}},
+		{name: "chaos unset", chaosEnabled: true, namespace: "chaos-tests"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "chaos-agent", Namespace: tt.namespace},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image: "ghcr.io/language-operator/agent:latest",
+					Chaos: tt.chaos,
+				},
+			}
+			reconciler, _ := newForceWorkloadReconciler(t, chaosNamespace, plainNamespace, optedOutNamespace)
+			reconciler.ChaosEnabled = tt.chaosEnabled
+
+			var value string
+			var found bool
+			for _, env := range reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil) {
+				if env.Name == ChaosFailureRateEnvVar {
+					value, found = env.Value, true
+				}
+			}
+			if found != (tt.expected != "") {
+				t.Fatalf("Expected %s set=%v, got set=%v", ChaosFailureRateEnvVar, tt.expected != "", found)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected %s %q, got %q", ChaosFailureRateEnvVar, tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_ChaosEnvNotSettableFromSpec(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "chaos-agent", Namespace: "production"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image: "ghcr.io/language-operator/agent:latest",
+			Env:   []corev1.EnvVar{{Name: ChaosFailureRateEnvVar, Value: "1"}},
+		},
+	}
+	reconciler, _ := newForceWorkloadReconciler(t)
+
+	for _, env := range reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil) {
+		if env.Name == ChaosFailureRateEnvVar {
+			t.Errorf("Expected spec.env to be unable to set %s, got %q", ChaosFailureRateEnvVar, env.Value)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// inheritsClusterEgress reports whether the agent's egress includes the default egress of the cluster
+func inheritsClusterEgress(agent *langopv1alpha1.LanguageAgent, cluster *langopv1alpha1.LanguageCluster) bool {
+	return len(cluster.Spec.DefaultEgress) > 0 &&
+		agent.Spec.ClusterRef == cluster.Name && agent.Namespace == cluster.Namespace &&
+		(agent.Spec.InheritClusterEgress == nil || *agent.Spec.InheritClusterEgress)
+}
+
+// resolveEgress returns the egress rules of the agent: its cluster's default egress followed by
+// the agent's own rules, which only ever allow more. Agents without a clusterRef, or that set
+// spec.inheritClusterEgress to false, get just their own rules.
+func (r *LanguageAgentReconciler) resolveEgress(ctx context.Context, agent *langopv1alpha1.LanguageAgent) ([]langopv1alpha1.NetworkRule, error) {
+	if agent.Spec.ClusterRef == "" || (agent.Spec.InheritClusterEgress != nil && !*agent.Spec.InheritClusterEgress) {
+		return agent.Spec.Egress, nil
+	}
+
+	cluster := &langopv1alpha1.LanguageCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.ClusterRef, Namespace: agent.Namespace}, cluster); err != nil {
+		if errors.IsNotFound(err) {
+			return agent.Spec.Egress, nil
+		}
+		return nil, fmt.Errorf("failed to get cluster %s: %w", agent.Spec.ClusterRef, err)
+	}
+	if len(cluster.Spec.DefaultEgress) == 0 {
+		return agent.Spec.Egress, nil
+	}
+
+	egress := make([]langopv1alpha1.NetworkRule, 0, len(cluster.Spec.DefaultEgress)+len(agent.Spec.Egress))
+	egress = append(egress, cluster.Spec.DefaultEgress...)
+	return append(egress, agent.Spec.Egress...), nil
+}
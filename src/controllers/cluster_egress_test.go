@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+func TestLanguageAgentController_ClusterDefaultEgress(t *testing.T) {
+	cluster := &langopv1alpha1.LanguageCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "prod", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageClusterSpec{
+			DefaultEgress: []langopv1alpha1.NetworkRule{{
+				Description: "internal proxy",
+				To:          &langopv1alpha1.NetworkPeer{CIDR: "10.20.0.0/16"},
+			}},
+		},
+	}
+	agentRule := langopv1alpha1.NetworkRule{
+		Description: "search API",
+		To:          &langopv1alpha1.NetworkPeer{CIDR: "203.0.113.0/24"},
+	}
+
+	tests := []struct {
+		name       string
+		clusterRef string
+		inherit    *bool
+		expected   []string
+	}{
+		{name: "cluster default merged before the agent's rules", clusterRef: "prod", expected: []string{"10.20.0.0/16", "203.0.113.0/24"}},
+		{name: "explicitly inherited", clusterRef: "prod", inherit: ptr.To(true), expected: []string{"10.20.0.0/16", "203.0.113.0/24"}},
+		{name: "inheritance turned off", clusterRef: "prod", inherit: ptr.To(false), expected: []string{"203.0.113.0/24"}},
+		{name: "no cluster ref", expected: []string{"203.0.113.0/24"}},
+		{name: "missing cluster", clusterRef: "staging", expected: []string{"203.0.113.0/24"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					ClusterRef:           tt.clusterRef,
+					Egress:               []langopv1alpha1.NetworkRule{agentRule},
+					InheritClusterEgress: tt.inherit,
+				},
+			}
+			reconciler, fakeClient := newForceWorkloadReconciler(t, cluster.DeepCopy(), agent)
+			reconciler.NetworkPolicyTimeout = 5 * time.Second
+			ctx := context.Background()
+
+			egress, err := reconciler.resolveEgress(ctx, agent)
+			if err != nil {
+				t.Fatalf("resolveEgress failed: %v", err)
+			}
+			var cidrs []string
+			for _, rule := range egress {
+				cidrs = append(cidrs, rule.To.CIDR)
+			}
+			if len(cidrs) != len(tt.expected) {
+				t.Fatalf("Expected egress to %v, got %v", tt.expected, cidrs)
+			}
+			for i := range cidrs {
+				if cidrs[i] != tt.expected[i] {
+					t.Errorf("Expected egress to %v, got %v", tt.expected, cidrs)
+				}
+			}
+
+			if err := reconciler.reconcileNetworkPolicy(ctx, agent); err != nil {
+				t.Fatalf("reconcileNetworkPolicy failed: %v", err)
+			}
+			policy := &networkingv1.NetworkPolicy{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, policy); err != nil {
+				t.Fatalf("Failed to get NetworkPolicy: %v", err)
+			}
+			allowed := map[string]bool{}
+			for _, rule := range policy.Spec.Egress {
+				for _, peer := range rule.To {
+					if peer.IPBlock != nil {
+						allowed[peer.IPBlock.CIDR] = true
+					}
+				}
+			}
+			if inherited := allowed["10.20.0.0/16"]; inherited != (len(tt.expected) == 2) {
+				t.Errorf("Expected the cluster default in the NetworkPolicy: %v, got egress %v", len(tt.expected) == 2, policy.Spec.Egress)
+			}
+			if !allowed["203.0.113.0/24"] {
+				t.Errorf("Expected the agent's own rule in the NetworkPolicy, got egress %v", policy.Spec.Egress)
+			}
+		})
+	}
+}
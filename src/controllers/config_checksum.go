@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+const (
+	// codeChecksumAnnotation records the hash of the mounted agent code on the pod template, so a
+	// code change rolls the Deployment instead of leaving pods on stale code
+	codeChecksumAnnotation = "langop.io/code-checksum"
+	// configChecksumAnnotation records the hash of the agent config ConfigMap on the pod template
+	configChecksumAnnotation = "langop.io/config-checksum"
+)
+
+// checksumAnnotations are the pod template annotations whose change restarts the agent's pods
+var checksumAnnotations = []string{codeChecksumAnnotation, configChecksumAnnotation}
+
+// podTemplateChecksums returns the checksum annotations for the agent's pod template. Agents with
+// spec.warmReload get new code delivered in place, so they get no code checksum; warm reload
+// doesn't deliver config, so their config checksum still restarts them. The code checksum is left
+// off until the code ConfigMap exists; the ConfigMap watch reconciles again once it does.
+func (r *LanguageAgentReconciler) podTemplateChecksums(ctx context.Context, agent *langopv1alpha1.LanguageAgent, persona *langopv1alpha1.LanguagePersona) (map[string]string, error) {
+	// Hash the config as it is written rather than re-reading it, which could return a stale copy.
+	// The config embeds the whole spec, but scaling must not restart the running pods.
+	unscaled := agent.DeepCopy()
+	unscaled.Spec.Replicas = nil
+	configData, err := agentConfigData(unscaled, persona)
+	if err != nil {
+		return nil, err
+	}
+	checksums := map[string]string{configChecksumAnnotation: configDataChecksum(configData)}
+
+	if r.usesSynthesizedCode(agent) && !agent.Spec.WarmReload {
+		codeConfigMap := &corev1.ConfigMap{}
+		err := r.Get(ctx, types.NamespacedName{Name: codeVolumeConfigMapName(agent), Namespace: agent.Namespace}, codeConfigMap)
+		if err == nil {
+			checksums[codeChecksumAnnotation] = hashString(codeConfigMap.Data["agent.rb"])
+		} else if !errors.IsNotFound(err) {
+			return nil, fmt.Errorf("failed to get code ConfigMap: %w", err)
+		}
+	}
+	return checksums, nil
+}
+
+// configDataChecksum hashes ConfigMap data. Only the data is hashed, never metadata such as the
+// resource version, so an unchanged config always yields the same checksum.
+func configDataChecksum(data map[string]string) string {
+	// Marshalling a string map cannot fail; map keys are sorted
+	encoded, _ := json.Marshal(data)
+	return hashString(string(encoded))
+}
+
+// checksumFingerprint fingerprints the checksum annotations of a pod template
+func checksumFingerprint(template *corev1.PodTemplateSpec) string {
+	checksums := make(map[string]string, len(checksumAnnotations))
+	for _, key := range checksumAnnotations {
+		if value, ok := template.Annotations[key]; ok {
+			checksums[key] = value
+		}
+	}
+	return configDataChecksum(checksums)
+}
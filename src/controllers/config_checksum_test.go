@@ -0,0 +1,154 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func newChecksumTestObjects(warmReload bool) (*langopv1alpha1.LanguageAgent, *corev1.ConfigMap, []client.Object) {
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{ModelName: "gpt-4"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "checksum-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			Instructions:  "Watch for outages",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+			WarmReload:    warmReload,
+		},
+	}
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace},
+		Data:       map[string]string{"agent.rb": "agent 'checksum-agent' do\nend"},
+	}
+	return agent, codeConfigMap, []client.Object{model, agent, codeConfigMap}
+}
+
+func getChecksumDeployment(t *testing.T, c client.Client, agent *langopv1alpha1.LanguageAgent) *appsv1.Deployment {
+	t.Helper()
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(context.Background(), types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, deployment); err != nil {
+		t.Fatalf("Failed to get deployment: %v", err)
+	}
+	return deployment
+}
+
+func TestLanguageAgentController_PodTemplateChecksums(t *testing.T) {
+	agent, codeConfigMap, objects := newChecksumTestObjects(false)
+	reconciler, fakeClient := newForceWorkloadReconciler(t, objects...)
+	ctx := context.Background()
+
+	if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	deployment := getChecksumDeployment(t, fakeClient, agent)
+	annotations := deployment.Spec.Template.Annotations
+	if annotations[codeChecksumAnnotation] != hashString(codeConfigMap.Data["agent.rb"]) {
+		t.Errorf("Expected code checksum %q, got %q", hashString(codeConfigMap.Data["agent.rb"]), annotations[codeChecksumAnnotation])
+	}
+	configChecksum := annotations[configChecksumAnnotation]
+	if configChecksum == "" {
+		t.Fatal("Expected a config checksum on the pod template")
+	}
+
+	// An unchanged reconcile leaves the Deployment alone
+	if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	unchanged := getChecksumDeployment(t, fakeClient, agent)
+	if unchanged.ResourceVersion != deployment.ResourceVersion {
+		t.Errorf("Expected no update without changes, resource version went from %s to %s", deployment.ResourceVersion, unchanged.ResourceVersion)
+	}
+
+	// Scaling doesn't restart the pods
+	replicas := int32(3)
+	agent.Spec.Replicas = &replicas
+	if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	if checksum := getChecksumDeployment(t, fakeClient, agent).Spec.Template.Annotations[configChecksumAnnotation]; checksum != configChecksum {
+		t.Errorf("Expected scaling to keep the config checksum %q, got %q", configChecksum, checksum)
+	}
+
+	// New instructions and the code synthesized from them roll the pods
+	agent.Spec.Instructions = "Watch for outages and page the on-call engineer"
+	codeConfigMap.Data["agent.rb"] = "agent 'checksum-agent' do\n  # pages on-call\nend"
+	if err := fakeClient.Update(ctx, codeConfigMap); err != nil {
+		t.Fatalf("Failed to update code ConfigMap: %v", err)
+	}
+	if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	annotations = getChecksumDeployment(t, fakeClient, agent).Spec.Template.Annotations
+	if annotations[codeChecksumAnnotation] != hashString(codeConfigMap.Data["agent.rb"]) {
+		t.Errorf("Expected the code checksum to follow the new code, got %q", annotations[codeChecksumAnnotation])
+	}
+	if annotations[configChecksumAnnotation] == configChecksum {
+		t.Error("Expected new instructions to bump the config checksum")
+	}
+}
+
+func TestLanguageAgentController_WarmReloadSkipsCodeChecksum(t *testing.T) {
+	agent, codeConfigMap, objects := newChecksumTestObjects(true)
+	reconciler, fakeClient := newForceWorkloadReconciler(t, objects...)
+	ctx := context.Background()
+
+	if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	deployment := getChecksumDeployment(t, fakeClient, agent)
+	annotations := deployment.Spec.Template.Annotations
+	if _, ok := annotations[codeChecksumAnnotation]; ok {
+		t.Errorf("Expected no %s annotation on a warm reload agent", codeChecksumAnnotation)
+	}
+	configChecksum := annotations[configChecksumAnnotation]
+	if configChecksum == "" {
+		t.Fatal("Expected a config checksum on a warm reload agent's pod template")
+	}
+
+	// New code is warm reloaded, so it leaves the pod template alone
+	codeConfigMap.Data["agent.rb"] = "agent 'checksum-agent' do\n  # reloaded in place\nend"
+	if err := fakeClient.Update(ctx, codeConfigMap); err != nil {
+		t.Fatalf("Failed to update code ConfigMap: %v", err)
+	}
+	if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	if unchanged := getChecksumDeployment(t, fakeClient, agent); unchanged.ResourceVersion != deployment.ResourceVersion {
+		t.Errorf("Expected new code not to update the Deployment, resource version went from %s to %s", deployment.ResourceVersion, unchanged.ResourceVersion)
+	}
+
+	// Config isn't warm reloaded, so a config change still rolls the pods
+	agent.Spec.Instructions = "Watch for outages and page the on-call engineer"
+	if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	if checksum := getChecksumDeployment(t, fakeClient, agent).Spec.Template.Annotations[configChecksumAnnotation]; checksum == configChecksum {
+		t.Error("Expected a config change to bump a warm reload agent's config checksum")
+	}
+
+	// A rollout restart after a failed warm reload survives the next reconcile
+	if err := rolloutRestartAgent(context.Background(), fakeClient, agent); err != nil {
+		t.Fatalf("rolloutRestartAgent failed: %v", err)
+	}
+	restartedAt := getChecksumDeployment(t, fakeClient, agent).Spec.Template.Annotations[restartedAtAnnotation]
+	if restartedAt == "" {
+		t.Fatal("Expected the rollout restart to annotate the pod template")
+	}
+	if err := reconciler.reconcileDeployment(context.Background(), agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	if got := getChecksumDeployment(t, fakeClient, agent).Spec.Template.Annotations[restartedAtAnnotation]; got != restartedAt {
+		t.Errorf("Expected the restart annotation %q to be kept, got %q", restartedAt, got)
+	}
+}
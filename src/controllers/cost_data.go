@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// checkSynthesisPricing refuses synthesis for agents with spec.requireCostData when the synthesis
+// model has no pricing configured, since the cost quota couldn't account for it. Other agents
+// synthesize with unpriced models as before.
+func (r *LanguageAgentReconciler) checkSynthesisPricing(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
+	if !agent.Spec.RequireCostData {
+		return nil
+	}
+
+	model, _, err := r.resolveSynthesisModel(ctx, agent)
+	if err != nil {
+		return err
+	}
+	if synthesis.HasPricing(model) {
+		return nil
+	}
+	return r.costDataUnavailable(agent, fmt.Sprintf("synthesis model %s/%s has no pricing configured in spec.costTracking",
+		model.Namespace, model.Name))
+}
+
+// checkReportedCost fills in the cost of a synthesis the synthesizer reported no cost for from
+// the model's configured pricing, with every candidate priced like the selected one. Agents with
+// spec.requireCostData fail synthesis when neither a reported cost nor pricing is available.
+func (r *LanguageAgentReconciler) checkReportedCost(ctx context.Context, agent *langopv1alpha1.LanguageAgent, model *langopv1alpha1.LanguageModel,
+	req synthesis.AgentSynthesisRequest, resp *synthesis.AgentSynthesisResponse) error {
+	if resp.Cost != nil {
+		return nil
+	}
+
+	cost, err := r.pricedCost(ctx, agent, model, req, resp.DSLCode)
+	if err != nil || cost == nil {
+		return err
+	}
+	if resp.Candidates != nil && resp.Candidates.Generated > 1 {
+		generated := int64(resp.Candidates.Generated)
+		cost = synthesis.NewCostTracker(model).CalculateCost(cost.InputTokens*generated, cost.OutputTokens*generated, cost.ModelName)
+	}
+	resp.Cost = cost
+	return nil
+}
+
+// pricedCost prices output generated for req with the model's configured pricing. It returns
+// nil when the model has no pricing, which is an error for agents with spec.requireCostData.
+func (r *LanguageAgentReconciler) pricedCost(ctx context.Context, agent *langopv1alpha1.LanguageAgent, model *langopv1alpha1.LanguageModel,
+	req synthesis.AgentSynthesisRequest, output string) (*synthesis.SynthesisCost, error) {
+	if r.QuotaManager != nil && synthesis.HasPricing(model) {
+		cost, err := r.QuotaManager.PricedCost(req, output, model)
+		if err == nil {
+			return cost, nil
+		}
+		log.FromContext(ctx).Error(err, "Failed to price synthesis with the model's configured pricing", "agent", agent.Name)
+	}
+	if !agent.Spec.RequireCostData {
+		return nil, nil
+	}
+	return nil, r.costDataUnavailable(agent, "synthesis model reported no cost and it couldn't be priced")
+}
+
+// costDataUnavailable emits a warning event and returns the error failing synthesis
+func (r *LanguageAgentReconciler) costDataUnavailable(agent *langopv1alpha1.LanguageAgent, message string) error {
+	if r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeWarning, synthesis.ReasonCostDataUnavailable,
+			"%s; configure the model's pricing or unset spec.requireCostData to synthesize without cost tracking", message)
+	}
+	return fmt.Errorf("%w: %s", synthesis.ErrCostDataUnavailable, message)
+}
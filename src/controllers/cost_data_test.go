@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+func TestLanguageAgentController_CostDataPolicy(t *testing.T) {
+	zero := 0.0
+	localModel := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai-compatible", ModelName: "llama3"},
+	}
+	freeModel := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "free", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:     "openai-compatible",
+			ModelName:    "llama3",
+			CostTracking: &langopv1alpha1.CostTrackingSpec{Enabled: true, InputTokenCost: &zero, OutputTokenCost: &zero},
+		},
+	}
+
+	tests := []struct {
+		name            string
+		model           string
+		requireCostData bool
+		quota           bool
+		cost            *synthesis.SynthesisCost
+		expectErr       bool
+		expectCost      bool
+	}{
+		{name: "missing pricing tolerated by default", model: "local", quota: true},
+		{name: "missing pricing rejected under the strict policy", model: "local", requireCostData: true, expectErr: true},
+		{name: "zero pricing acknowledges a free model", model: "free", requireCostData: true, cost: &synthesis.SynthesisCost{}, expectCost: true},
+		{name: "unreported cost priced with the model's pricing", model: "free", requireCostData: true, quota: true, expectCost: true},
+		{name: "unpriced unreported cost rejected under the strict policy", model: "free", requireCostData: true, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					ModelRefs:       []langopv1alpha1.ModelReference{{Name: tt.model}},
+					RequireCostData: tt.requireCostData,
+				},
+			}
+			reconciler, _ := newForceWorkloadReconciler(t, localModel.DeepCopy(), freeModel.DeepCopy())
+			if tt.quota {
+				reconciler.QuotaManager = synthesis.NewQuotaManager(10.0, 100, "USD", logr.Discard())
+			}
+
+			ctx := context.Background()
+			resp := &synthesis.AgentSynthesisResponse{DSLCode: "agent \"agent\" do\nend", Cost: tt.cost}
+			err := reconciler.checkSynthesisPricing(ctx, agent)
+			if err == nil {
+				model, _, modelErr := reconciler.resolveSynthesisModel(ctx, agent)
+				if modelErr != nil {
+					t.Fatalf("Failed to resolve the synthesis model: %v", modelErr)
+				}
+				err = reconciler.checkReportedCost(ctx, agent, model, synthesis.AgentSynthesisRequest{Instructions: "Summarize the news"}, resp)
+			}
+			if tt.expectCost != (resp.Cost != nil) {
+				t.Errorf("Expected a synthesis cost %v, got %+v", tt.expectCost, resp.Cost)
+			}
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+			if !tt.expectErr {
+				return
+			}
+
+			if !synthesis.IsCostDataUnavailable(err) {
+				t.Errorf("Expected a cost data error, got %v", err)
+			}
+			if reason := classifySynthesisFailure(err); reason != langopv1alpha1.FailureReasonValidation {
+				t.Errorf("Expected failure reason %s, got %s", langopv1alpha1.FailureReasonValidation, reason)
+			}
+			events := drainEvents(reconciler.Recorder.(*record.FakeRecorder))
+			if !hasEvent(events, synthesis.ReasonCostDataUnavailable) {
+				t.Errorf("Expected a %s event, got %v", synthesis.ReasonCostDataUnavailable, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// costReportComponent labels the ConfigMaps holding an agent's cost report snapshots
+const costReportComponent = "cost-report"
+
+// costReportKeyLayout names each snapshot in the ConfigMap after the end of its period, so keys
+// sort chronologically
+const costReportKeyLayout = "20060102T150405Z"
+
+// DefaultCostReportRetention is how many snapshots a cost report keeps, a month of daily reports
+const DefaultCostReportRetention = 30
+
+// CostReport is a snapshot of an agent's synthesis cost and usage over one reporting period
+type CostReport struct {
+	Agent       string    `json:"agent"`
+	Namespace   string    `json:"namespace"`
+	PeriodStart time.Time `json:"periodStart"`
+	PeriodEnd   time.Time `json:"periodEnd"`
+
+	// Cost is the synthesis cost incurred during the period, in Currency
+	Cost     float64 `json:"cost"`
+	Currency string  `json:"currency"`
+
+	InputTokens  int64 `json:"inputTokens"`
+	OutputTokens int64 `json:"outputTokens"`
+	TotalTokens  int64 `json:"totalTokens"`
+
+	Attempts           int `json:"attempts"`
+	SuccessfulAttempts int `json:"successfulAttempts"`
+	FailedAttempts     int `json:"failedAttempts"`
+
+	// LastSynthesisCost is the cost of the agent's most recent synthesis, from its status
+	LastSynthesisCost *float64 `json:"lastSynthesisCost,omitempty"`
+	Phase             string   `json:"phase,omitempty"`
+}
+
+// costReportConfigMapName returns the name of the ConfigMap holding an agent's cost reports
+func costReportConfigMapName(agent *langopv1alpha1.LanguageAgent) string {
+	return agent.Name + "-cost-report"
+}
+
+// CostReporter periodically snapshots each agent's synthesis cost, token usage, and attempts
+// into a <agent>-cost-report ConfigMap, giving a cost record without a metrics or billing
+// backend. Figures come from the QuotaManager, which only keeps a week of history in memory, so
+// the first period after an operator restart reports only what was recorded since.
+type CostReporter struct {
+	Client       client.Client
+	QuotaManager *synthesis.QuotaManager
+	Log          logr.Logger
+
+	// Interval is the length of a reporting period; it must be positive
+	Interval time.Duration
+
+	// Retention is how many snapshots each report keeps; older ones are rotated out
+	Retention int
+
+	now func() time.Time
+}
+
+// Start writes a snapshot every Interval until ctx is done. It implements manager.Runnable.
+// Unlike the ArtifactCleaner it doesn't report on start, which would add a short period on every
+// operator restart.
+func (c *CostReporter) Start(ctx context.Context) error {
+	ticker := time.NewTicker(c.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		if _, err := c.Report(ctx); err != nil {
+			c.Log.Error(err, "Failed to write cost reports")
+		}
+	}
+}
+
+// Report writes a snapshot of the period since each agent's previous snapshot and returns how
+// many were written. Agents without usage get no report until they incur some.
+func (c *CostReporter) Report(ctx context.Context) (int, error) {
+	now := time.Now()
+	if c.now != nil {
+		now = c.now()
+	}
+
+	agents := &langopv1alpha1.LanguageAgentList{}
+	if err := c.Client.List(ctx, agents); err != nil {
+		return 0, err
+	}
+
+	written := 0
+	for i := range agents.Items {
+		agent := &agents.Items[i]
+		if !agent.DeletionTimestamp.IsZero() {
+			continue
+		}
+		ok, err := c.reportAgent(ctx, agent, now)
+		if err != nil {
+			c.Log.Error(err, "Failed to write cost report", "agent", agent.Name, "namespace", agent.Namespace)
+			continue
+		}
+		if ok {
+			written++
+		}
+	}
+	return written, nil
+}
+
+// reportAgent appends the agent's snapshot ending at now to its cost report and rotates out
+// snapshots beyond the retention. It reports whether a snapshot was written.
+func (c *CostReporter) reportAgent(ctx context.Context, agent *langopv1alpha1.LanguageAgent, now time.Time) (bool, error) {
+	configMap := &corev1.ConfigMap{}
+	err := c.Client.Get(ctx, types.NamespacedName{Name: costReportConfigMapName(agent), Namespace: agent.Namespace}, configMap)
+	exists := err == nil
+	if err != nil && !errors.IsNotFound(err) {
+		return false, fmt.Errorf("failed to get cost report ConfigMap: %w", err)
+	}
+
+	// Periods follow on from the previous snapshot, so missed ticks are covered by the next one
+	start := now.Add(-c.Interval)
+	if previous, ok := latestCostReport(configMap); ok && previous.PeriodEnd.Before(now) {
+		start = previous.PeriodEnd
+	}
+	report := c.buildCostReport(agent, start, now)
+	if !exists && report.Attempts == 0 && report.Cost == 0 {
+		return false, nil
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return false, fmt.Errorf("failed to serialize cost report: %w", err)
+	}
+	key := now.UTC().Format(costReportKeyLayout) + ".json"
+
+	if !exists {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      costReportConfigMapName(agent),
+				Namespace: agent.Namespace,
+				Labels: map[string]string{
+					"langop.io/agent":     agent.Name,
+					"langop.io/component": costReportComponent,
+				},
+			},
+			Data: map[string]string{key: string(data)},
+		}
+		if err := controllerutil.SetControllerReference(agent, configMap, c.Client.Scheme()); err != nil {
+			return false, fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		if err := c.Client.Create(ctx, configMap); err != nil {
+			return false, fmt.Errorf("failed to create cost report ConfigMap: %w", err)
+		}
+		return true, nil
+	}
+
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[key] = string(data)
+	rotateCostReports(configMap, c.retention())
+	if err := c.Client.Update(ctx, configMap); err != nil {
+		return false, fmt.Errorf("failed to update cost report ConfigMap: %w", err)
+	}
+	return true, nil
+}
+
+// buildCostReport aggregates the agent's usage between start and end
+func (c *CostReporter) buildCostReport(agent *langopv1alpha1.LanguageAgent, start, end time.Time) CostReport {
+	report := CostReport{
+		Agent:       agent.Name,
+		Namespace:   agent.Namespace,
+		PeriodStart: start.UTC(),
+		PeriodEnd:   end.UTC(),
+		Phase:       agent.Status.Phase,
+	}
+	if c.QuotaManager != nil {
+		usage := c.QuotaManager.GetAgentUsage(agent.Namespace, agent.Name, start, end)
+		report.Cost = usage.Cost
+		report.Currency = usage.Currency
+		report.InputTokens = usage.InputTokens
+		report.OutputTokens = usage.OutputTokens
+		report.TotalTokens = usage.InputTokens + usage.OutputTokens
+		report.Attempts = usage.Attempts
+		report.SuccessfulAttempts = usage.SuccessfulAttempts
+		report.FailedAttempts = usage.FailedAttempts
+	}
+	if metrics := agent.Status.CostMetrics; metrics != nil {
+		report.LastSynthesisCost = metrics.TotalCost
+	}
+	return report
+}
+
+func (c *CostReporter) retention() int {
+	if c.Retention <= 0 {
+		return DefaultCostReportRetention
+	}
+	return c.Retention
+}
+
+// costReportKeys returns the snapshot keys of a cost report ConfigMap, oldest first
+func costReportKeys(configMap *corev1.ConfigMap) []string {
+	keys := make([]string, 0, len(configMap.Data))
+	for key := range configMap.Data {
+		if strings.HasSuffix(key, ".json") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// latestCostReport returns the newest snapshot of a cost report ConfigMap
+func latestCostReport(configMap *corev1.ConfigMap) (CostReport, bool) {
+	keys := costReportKeys(configMap)
+	for i := len(keys) - 1; i >= 0; i-- {
+		var report CostReport
+		if err := json.Unmarshal([]byte(configMap.Data[keys[i]]), &report); err == nil {
+			return report, true
+		}
+	}
+	return CostReport{}, false
+}
+
+// rotateCostReports drops the oldest snapshots beyond retention
+func rotateCostReports(configMap *corev1.ConfigMap, retention int) {
+	keys := costReportKeys(configMap)
+	for len(keys) > retention {
+		delete(configMap.Data, keys[0])
+		keys = keys[1:]
+	}
+}
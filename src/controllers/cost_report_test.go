@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+func TestCostReporter_Report(t *testing.T) {
+	ctx := context.Background()
+	lastCost := 0.5
+	active := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviewer", Namespace: "default", UID: "reviewer-uid"},
+		Status: langopv1alpha1.LanguageAgentStatus{
+			Phase:       "Running",
+			CostMetrics: &langopv1alpha1.AgentCostMetrics{TotalCost: &lastCost, Currency: "USD"},
+		},
+	}
+	idle := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "idle", Namespace: "default", UID: "idle-uid"},
+	}
+
+	quotaManager := synthesis.NewQuotaManager(100, 100, "USD", logr.Discard())
+	quotaManager.SetRemainingCostGauge(nil)
+	quotaManager.RecordCost(ctx, "default", "reviewer", &synthesis.SynthesisCost{TotalCost: 0.25, InputTokens: 1000, OutputTokens: 200, Currency: "USD"})
+	quotaManager.RecordCost(ctx, "default", "reviewer", &synthesis.SynthesisCost{TotalCost: 0.5, InputTokens: 2000, OutputTokens: 300, Currency: "USD"})
+	quotaManager.RecordAttempt(ctx, "default", "reviewer", true, "")
+	quotaManager.RecordAttempt(ctx, "default", "reviewer", false, "validation failed")
+	quotaManager.RecordAttempt(ctx, "default", "other", true, "")
+
+	fakeClient := fake.NewClientBuilder().WithScheme(testutil.SetupTestScheme(t)).WithObjects(active, idle).Build()
+	now := time.Now().Add(time.Minute)
+	reporter := &CostReporter{
+		Client:       fakeClient,
+		QuotaManager: quotaManager,
+		Log:          logr.Discard(),
+		Interval:     24 * time.Hour,
+		Retention:    2,
+		now:          func() time.Time { return now },
+	}
+
+	written, err := reporter.Report(ctx)
+	if err != nil {
+		t.Fatalf("Report failed: %v", err)
+	}
+	if written != 1 {
+		t.Errorf("Expected 1 report written, got %d", written)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "reviewer-cost-report", Namespace: "default"}, configMap); err != nil {
+		t.Fatalf("Expected a cost report ConfigMap: %v", err)
+	}
+	if configMap.Labels["langop.io/component"] != costReportComponent || configMap.Labels["langop.io/agent"] != "reviewer" {
+		t.Errorf("Expected cost report labels, got %v", configMap.Labels)
+	}
+	if len(configMap.OwnerReferences) != 1 || configMap.OwnerReferences[0].UID != active.UID {
+		t.Errorf("Expected the report to be owned by the agent, got %v", configMap.OwnerReferences)
+	}
+	firstKey := now.UTC().Format(costReportKeyLayout) + ".json"
+	var report CostReport
+	if err := json.Unmarshal([]byte(configMap.Data[firstKey]), &report); err != nil {
+		t.Fatalf("Expected a snapshot at %s: %v", firstKey, err)
+	}
+	if report.Cost != 0.75 || report.Currency != "USD" {
+		t.Errorf("Expected a cost of 0.75 USD, got %f %s", report.Cost, report.Currency)
+	}
+	if report.InputTokens != 3000 || report.OutputTokens != 500 || report.TotalTokens != 3500 {
+		t.Errorf("Expected 3000 input, 500 output, and 3500 total tokens, got %d, %d, and %d",
+			report.InputTokens, report.OutputTokens, report.TotalTokens)
+	}
+	if report.Attempts != 2 || report.SuccessfulAttempts != 1 || report.FailedAttempts != 1 {
+		t.Errorf("Expected 2 attempts with 1 success and 1 failure, got %d, %d, and %d",
+			report.Attempts, report.SuccessfulAttempts, report.FailedAttempts)
+	}
+	if !report.PeriodEnd.Equal(now) || !report.PeriodStart.Equal(now.Add(-24*time.Hour)) {
+		t.Errorf("Expected the period to be the day before %s, got %s to %s", now, report.PeriodStart, report.PeriodEnd)
+	}
+	if report.LastSynthesisCost == nil || *report.LastSynthesisCost != lastCost || report.Phase != "Running" {
+		t.Errorf("Expected the agent status figures in the report, got %+v", report)
+	}
+
+	idleReport := &corev1.ConfigMap{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "idle-cost-report", Namespace: "default"}, idleReport); err == nil {
+		t.Error("Expected no cost report for an agent without usage")
+	}
+
+	// Later periods follow on from the previous snapshot and rotate out the oldest
+	var keys []string
+	for i := 1; i <= 2; i++ {
+		previous := now
+		now = now.Add(24 * time.Hour)
+		if _, err := reporter.Report(ctx); err != nil {
+			t.Fatalf("Report failed: %v", err)
+		}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: "reviewer-cost-report", Namespace: "default"}, configMap); err != nil {
+			t.Fatalf("Failed to get cost report: %v", err)
+		}
+		key := now.UTC().Format(costReportKeyLayout) + ".json"
+		keys = append(keys, key)
+		if err := json.Unmarshal([]byte(configMap.Data[key]), &report); err != nil {
+			t.Fatalf("Expected a snapshot at %s: %v", key, err)
+		}
+		if !report.PeriodStart.Equal(previous) {
+			t.Errorf("Expected the period to start at the previous snapshot %s, got %s", previous, report.PeriodStart)
+		}
+		if report.Attempts != 0 || report.Cost != 0 {
+			t.Errorf("Expected usage to be counted in one period only, got %+v", report)
+		}
+	}
+
+	if len(configMap.Data) != 2 {
+		t.Errorf("Expected 2 retained snapshots, got %d", len(configMap.Data))
+	}
+	if _, ok := configMap.Data[firstKey]; ok {
+		t.Errorf("Expected the oldest snapshot %s to be rotated out", firstKey)
+	}
+	for _, key := range keys {
+		if _, ok := configMap.Data[key]; !ok {
+			t.Errorf("Expected snapshot %s to be retained", key)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// DebugAgentsPath is the debug server path serving the live state of every LanguageAgent
+const DebugAgentsPath = "/debug/agents"
+
+// debugServerShutdownTimeout bounds how long the debug server waits for requests on shutdown
+const debugServerShutdownTimeout = 5 * time.Second
+
+// AgentDebugState is the live state of a LanguageAgent as the operator sees it
+type AgentDebugState struct {
+	Name                string             `json:"name"`
+	Namespace           string             `json:"namespace"`
+	Phase               string             `json:"phase,omitempty"`
+	Conditions          []metav1.Condition `json:"conditions,omitempty"`
+	ExecutionMode       string             `json:"executionMode,omitempty"`
+	ForcedWorkload      string             `json:"forcedWorkload,omitempty"`
+	CodeHash            string             `json:"codeHash,omitempty"`
+	InstructionsHash    string             `json:"instructionsHash,omitempty"`
+	ConsecutiveFailures int32              `json:"consecutiveFailures,omitempty"`
+	FailureReason       string             `json:"failureReason,omitempty"`
+	WebhookURLs         []string           `json:"webhookURLs,omitempty"`
+}
+
+// AgentDebugHandler serves the state of every LanguageAgent as JSON. Agents are read from
+// Reader, normally the manager's cache, so requests don't reach the API server.
+type AgentDebugHandler struct {
+	Reader client.Reader
+}
+
+// ServeHTTP implements http.Handler
+func (h *AgentDebugHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	agents, err := h.AgentStates(req.Context())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(agents); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// AgentStates returns the state of every LanguageAgent, ordered by namespace and name
+func (h *AgentDebugHandler) AgentStates(ctx context.Context) ([]AgentDebugState, error) {
+	list := &langopv1alpha1.LanguageAgentList{}
+	if err := h.Reader.List(ctx, list); err != nil {
+		return nil, err
+	}
+
+	states := make([]AgentDebugState, 0, len(list.Items))
+	for i := range list.Items {
+		agent := &list.Items[i]
+		state := AgentDebugState{
+			Name:                agent.Name,
+			Namespace:           agent.Namespace,
+			Phase:               agent.Status.Phase,
+			Conditions:          agent.Status.Conditions,
+			ExecutionMode:       agent.Spec.ExecutionMode,
+			ForcedWorkload:      forcedWorkload(agent),
+			ConsecutiveFailures: agent.Status.ConsecutiveFailures,
+			FailureReason:       string(agent.Status.FailureReason),
+			WebhookURLs:         agent.Status.WebhookURLs,
+		}
+		if info := agent.Status.SynthesisInfo; info != nil {
+			state.CodeHash = info.CodeHash
+			state.InstructionsHash = info.InstructionsHash
+		}
+		states = append(states, state)
+	}
+	sort.Slice(states, func(i, j int) bool {
+		if states[i].Namespace != states[j].Namespace {
+			return states[i].Namespace < states[j].Namespace
+		}
+		return states[i].Name < states[j].Name
+	})
+	return states, nil
+}
+
+// DebugServer serves the operator's debug endpoints on their own address, separate from the
+// metrics server. It runs on every replica, not just the leader.
+type DebugServer struct {
+	// Addr is the listen address. An address without a host binds to localhost only.
+	Addr    string
+	Handler http.Handler
+}
+
+// NewDebugServer creates a debug server on addr serving the state of the agents in reader
+func NewDebugServer(addr string, reader client.Reader) *DebugServer {
+	mux := http.NewServeMux()
+	mux.Handle(DebugAgentsPath, &AgentDebugHandler{Reader: reader})
+	return &DebugServer{Addr: addr, Handler: mux}
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable
+func (s *DebugServer) NeedLeaderElection() bool {
+	return false
+}
+
+// Start implements manager.Runnable, serving until ctx is done
+func (s *DebugServer) Start(ctx context.Context) error {
+	addr, err := debugListenAddress(s.Addr)
+	if err != nil {
+		return err
+	}
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+
+	server := &http.Server{Handler: s.Handler, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), debugServerShutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	log.FromContext(ctx).Info("Serving debug endpoints", "addr", listener.Addr().String())
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// debugListenAddress binds addresses without a host, such as ":8082", to localhost so debug
+// state isn't exposed outside the pod unless a host is given explicitly
+func debugListenAddress(addr string) (string, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "", err
+	}
+	if host == "" {
+		host = "127.0.0.1"
+	}
+	return net.JoinHostPort(host, port), nil
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+)
+
+func TestAgentDebugHandler(t *testing.T) {
+	running := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "summarizer", Namespace: "team-b"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{ExecutionMode: "autonomous"},
+		Status: langopv1alpha1.LanguageAgentStatus{
+			Phase: "Running",
+			Conditions: []metav1.Condition{{
+				Type:   "Ready",
+				Status: metav1.ConditionTrue,
+				Reason: "DeploymentReady",
+			}},
+			SynthesisInfo: &langopv1alpha1.SynthesisInfo{CodeHash: "abc123", InstructionsHash: "def456"},
+			WebhookURLs:   []string{"https://summarizer.team-b.example.com/webhook"},
+		},
+	}
+	failing := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "reporter",
+			Namespace:   "team-a",
+			Annotations: map[string]string{ForceWorkloadAnnotation: WorkloadCronJob},
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{ExecutionMode: "scheduled"},
+		Status: langopv1alpha1.LanguageAgentStatus{
+			Phase:               "Failed",
+			ConsecutiveFailures: 3,
+		},
+	}
+	reader := fake.NewClientBuilder().
+		WithScheme(testutil.SetupTestScheme(t)).
+		WithObjects(running, failing).
+		Build()
+	handler := &AgentDebugHandler{Reader: reader}
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, DebugAgentsPath, nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+
+	var states []AgentDebugState
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &states))
+	require.Len(t, states, 2)
+
+	assert.Equal(t, "team-a", states[0].Namespace, "agents are ordered by namespace")
+	assert.Equal(t, "reporter", states[0].Name)
+	assert.Equal(t, "Failed", states[0].Phase)
+	assert.Equal(t, "scheduled", states[0].ExecutionMode)
+	assert.Equal(t, WorkloadCronJob, states[0].ForcedWorkload)
+	assert.Equal(t, int32(3), states[0].ConsecutiveFailures)
+	assert.Empty(t, states[0].CodeHash)
+
+	assert.Equal(t, "summarizer", states[1].Name)
+	assert.Equal(t, "Running", states[1].Phase)
+	assert.Equal(t, "autonomous", states[1].ExecutionMode)
+	assert.Equal(t, "abc123", states[1].CodeHash)
+	assert.Equal(t, "def456", states[1].InstructionsHash)
+	assert.Equal(t, []string{"https://summarizer.team-b.example.com/webhook"}, states[1].WebhookURLs)
+	require.Len(t, states[1].Conditions, 1)
+	assert.Equal(t, "Ready", states[1].Conditions[0].Type)
+
+	recorder = httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, DebugAgentsPath, nil))
+	assert.Equal(t, http.StatusMethodNotAllowed, recorder.Code)
+	assert.Equal(t, http.MethodGet, recorder.Header().Get("Allow"))
+}
+
+func TestDebugListenAddress(t *testing.T) {
+	tests := []struct {
+		addr     string
+		expected string
+	}{
+		{addr: ":8082", expected: "127.0.0.1:8082"},
+		{addr: "0.0.0.0:8082", expected: "0.0.0.0:8082"},
+		{addr: "[::1]:8082", expected: "[::1]:8082"},
+	}
+	for _, tt := range tests {
+		addr, err := debugListenAddress(tt.addr)
+		require.NoError(t, err)
+		assert.Equal(t, tt.expected, addr)
+	}
+
+	_, err := debugListenAddress("8082")
+	assert.Error(t, err)
+}
@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// DefaultPersonaLabel marks a LanguagePersona that is composed beneath the personas of every
+// agent in its namespace
+const DefaultPersonaLabel = "langop.io/default-persona"
+
+// resolveDefaultPersonas records the ready default personas of the agent's namespace in
+// status.defaultPersonas. Personas already listed in spec.personaRefs are not repeated.
+func (r *LanguageAgentReconciler) resolveDefaultPersonas(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
+	if agent.Spec.IgnoreDefaultPersona {
+		agent.Status.DefaultPersonas = nil
+		return nil
+	}
+
+	personas := &langopv1alpha1.LanguagePersonaList{}
+	if err := r.List(ctx, personas, client.InNamespace(agent.Namespace), client.MatchingLabels{DefaultPersonaLabel: "true"}); err != nil {
+		return fmt.Errorf("failed to list default personas: %w", err)
+	}
+
+	explicit := make(map[string]bool, len(agent.Spec.PersonaRefs))
+	for _, ref := range agent.Spec.PersonaRefs {
+		if ref.Namespace == "" || ref.Namespace == agent.Namespace {
+			explicit[ref.Name] = true
+		}
+	}
+
+	var defaults []string
+	for _, persona := range personas.Items {
+		if !explicit[persona.Name] && persona.Status.Phase == "Ready" {
+			defaults = append(defaults, persona.Name)
+		}
+	}
+	sort.Strings(defaults)
+
+	agent.Status.DefaultPersonas = defaults
+	return nil
+}
+
+// fetchDefaultPersonas returns the default personas recorded in status.defaultPersonas.
+// A default persona deleted since the last reconcile is skipped rather than failing the agent.
+func (r *LanguageAgentReconciler) fetchDefaultPersonas(ctx context.Context, agent *langopv1alpha1.LanguageAgent) ([]*langopv1alpha1.LanguagePersona, error) {
+	var personas []*langopv1alpha1.LanguagePersona
+	for _, name := range agent.Status.DefaultPersonas {
+		persona := &langopv1alpha1.LanguagePersona{}
+		if err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: agent.Namespace}, persona); err != nil {
+			if errors.IsNotFound(err) {
+				log.FromContext(ctx).Info("Default persona no longer exists, skipping it", "persona", name)
+				continue
+			}
+			return nil, fmt.Errorf("failed to get default persona %s/%s: %w", agent.Namespace, name, err)
+		}
+		personas = append(personas, persona)
+	}
+	return personas, nil
+}
+
+// agentsForDefaultPersona maps a LanguagePersona event to the agents in its namespace that
+// compose it as a default persona, or now should
+func (r *LanguageAgentReconciler) agentsForDefaultPersona(ctx context.Context, obj client.Object) []reconcile.Request {
+	agents := &langopv1alpha1.LanguageAgentList{}
+	if err := r.List(ctx, agents, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list agents for persona", "persona", obj.GetName())
+		return nil
+	}
+
+	isDefault := obj.GetLabels()[DefaultPersonaLabel] == "true"
+	var requests []reconcile.Request
+	for _, agent := range agents.Items {
+		if !defaultPersonaOf(&agent, obj.GetName()) && (!isDefault || agent.Spec.IgnoreDefaultPersona) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace},
+		})
+	}
+	return requests
+}
+
+// defaultPersonaOf reports whether the persona was a default persona of the agent at its last reconcile
+func defaultPersonaOf(agent *langopv1alpha1.LanguageAgent, name string) bool {
+	for _, persona := range agent.Status.DefaultPersonas {
+		if persona == name {
+			return true
+		}
+	}
+	return false
+}
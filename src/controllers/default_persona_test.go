@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestPersona(name, namespace string, isDefault bool, spec langopv1alpha1.LanguagePersonaSpec) *langopv1alpha1.LanguagePersona {
+	persona := &langopv1alpha1.LanguagePersona{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       spec,
+		Status:     langopv1alpha1.LanguagePersonaStatus{Phase: "Ready"},
+	}
+	if isDefault {
+		persona.Labels = map[string]string{DefaultPersonaLabel: "true"}
+	}
+	return persona
+}
+
+func newDefaultPersonaTestReconciler(t *testing.T, objects ...client.Object) *LanguageAgentReconciler {
+	scheme := testutil.SetupTestScheme(t)
+	otherNamespace := newTestPersona("other-default", "other", true, langopv1alpha1.LanguagePersonaSpec{SystemPrompt: "Other team"})
+	objects = append(objects,
+		newTestPersona("corporate", "default", true, langopv1alpha1.LanguagePersonaSpec{
+			SystemPrompt: "You represent Acme Corp.",
+			Tone:         "professional",
+			Limitations:  []string{"Never share customer data"},
+		}),
+		newTestPersona("support", "default", false, langopv1alpha1.LanguagePersonaSpec{
+			SystemPrompt: "You are a friendly support agent.",
+			Limitations:  []string{"Escalate refunds over $100"},
+		}),
+		otherNamespace,
+	)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	return &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+}
+
+func TestFetchPersona_ComposesDefaultPersonaAsBase(t *testing.T) {
+	ctx := context.Background()
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "support-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			PersonaRefs: []langopv1alpha1.PersonaReference{{Name: "support"}},
+		},
+	}
+	reconciler := newDefaultPersonaTestReconciler(t, agent)
+
+	if err := reconciler.resolveDefaultPersonas(ctx, agent); err != nil {
+		t.Fatalf("resolveDefaultPersonas failed: %v", err)
+	}
+	if got := strings.Join(agent.Status.DefaultPersonas, ","); got != "corporate" {
+		t.Fatalf("Expected default personas [corporate], got [%s]", got)
+	}
+
+	persona, err := reconciler.fetchPersona(ctx, agent)
+	if err != nil {
+		t.Fatalf("fetchPersona failed: %v", err)
+	}
+	if persona == nil {
+		t.Fatal("Expected a composed persona")
+	}
+	if persona.Spec.SystemPrompt != "You are a friendly support agent." {
+		t.Errorf("Expected the agent's persona to override the default, got system prompt %q", persona.Spec.SystemPrompt)
+	}
+	if persona.Spec.Tone != "professional" {
+		t.Errorf("Expected the default persona's tone to be kept, got %q", persona.Spec.Tone)
+	}
+	if got := strings.Join(persona.Spec.Limitations, "|"); got != "Never share customer data|Escalate refunds over $100" {
+		t.Errorf("Expected default limitations before the agent's limitations, got %q", got)
+	}
+	if got := strings.Join(reconciler.getPersonaNames(agent), ","); got != "corporate,support" {
+		t.Errorf("Expected persona names to include the default persona, got %q", got)
+	}
+}
+
+func TestFetchPersona_DefaultPersonaWithoutPersonaRefs(t *testing.T) {
+	ctx := context.Background()
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "plain-agent", Namespace: "default"},
+	}
+	reconciler := newDefaultPersonaTestReconciler(t, agent)
+
+	if err := reconciler.resolveDefaultPersonas(ctx, agent); err != nil {
+		t.Fatalf("resolveDefaultPersonas failed: %v", err)
+	}
+	persona, err := reconciler.fetchPersona(ctx, agent)
+	if err != nil {
+		t.Fatalf("fetchPersona failed: %v", err)
+	}
+	if persona == nil || persona.Name != "corporate" {
+		t.Fatalf("Expected the default persona to apply on its own, got %+v", persona)
+	}
+}
+
+func TestFetchPersona_IgnoreDefaultPersona(t *testing.T) {
+	ctx := context.Background()
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "opted-out-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			PersonaRefs:          []langopv1alpha1.PersonaReference{{Name: "support"}},
+			IgnoreDefaultPersona: true,
+		},
+		Status: langopv1alpha1.LanguageAgentStatus{DefaultPersonas: []string{"corporate"}},
+	}
+	reconciler := newDefaultPersonaTestReconciler(t, agent)
+
+	if err := reconciler.resolveDefaultPersonas(ctx, agent); err != nil {
+		t.Fatalf("resolveDefaultPersonas failed: %v", err)
+	}
+	if len(agent.Status.DefaultPersonas) != 0 {
+		t.Fatalf("Expected no default personas for an opted-out agent, got %v", agent.Status.DefaultPersonas)
+	}
+
+	persona, err := reconciler.fetchPersona(ctx, agent)
+	if err != nil {
+		t.Fatalf("fetchPersona failed: %v", err)
+	}
+	if persona.Spec.Tone != "" || len(persona.Spec.Limitations) != 1 {
+		t.Errorf("Expected only the agent's own persona, got tone %q and limitations %v", persona.Spec.Tone, persona.Spec.Limitations)
+	}
+}
+
+func TestResolveDefaultPersonas_SkipsExplicitAndUnreadyPersonas(t *testing.T) {
+	ctx := context.Background()
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "support-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			PersonaRefs: []langopv1alpha1.PersonaReference{{Name: "corporate"}},
+		},
+	}
+	pending := newTestPersona("compliance", "default", true, langopv1alpha1.LanguagePersonaSpec{SystemPrompt: "Follow policy"})
+	pending.Status.Phase = "Pending"
+	reconciler := newDefaultPersonaTestReconciler(t, agent, pending)
+
+	if err := reconciler.resolveDefaultPersonas(ctx, agent); err != nil {
+		t.Fatalf("resolveDefaultPersonas failed: %v", err)
+	}
+	if len(agent.Status.DefaultPersonas) != 0 {
+		t.Errorf("Expected explicitly referenced and unready personas to be skipped, got %v", agent.Status.DefaultPersonas)
+	}
+}
+
+func TestAgentsForDefaultPersona(t *testing.T) {
+	ctx := context.Background()
+	reconciler := newDefaultPersonaTestReconciler(t,
+		&langopv1alpha1.LanguageAgent{ObjectMeta: metav1.ObjectMeta{Name: "composes", Namespace: "default"}},
+		&langopv1alpha1.LanguageAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "opted-out", Namespace: "default"},
+			Spec:       langopv1alpha1.LanguageAgentSpec{IgnoreDefaultPersona: true},
+		},
+		&langopv1alpha1.LanguageAgent{ObjectMeta: metav1.ObjectMeta{Name: "elsewhere", Namespace: "other"}},
+	)
+
+	requests := reconciler.agentsForDefaultPersona(ctx, newTestPersona("corporate", "default", true, langopv1alpha1.LanguagePersonaSpec{}))
+	if len(requests) != 1 || requests[0].Name != "composes" {
+		t.Errorf("Expected only the composing agent to be enqueued, got %v", requests)
+	}
+
+	// Personas without the label only matter to agents that composed them before
+	requests = reconciler.agentsForDefaultPersona(ctx, newTestPersona("support", "default", false, langopv1alpha1.LanguagePersonaSpec{}))
+	if len(requests) != 0 {
+		t.Errorf("Expected no agents for a regular persona, got %v", requests)
+	}
+}
@@ -0,0 +1,39 @@
+package controllers
+
+import (
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// agentDeploymentStrategy returns the Deployment strategy for spec.deploymentStrategy. Rolling
+// updates surge a new pod before terminating an old one by default, so single-replica agents
+// keep serving while new code rolls out.
+func agentDeploymentStrategy(agent *langopv1alpha1.LanguageAgent) appsv1.DeploymentStrategy {
+	spec := agent.Spec.DeploymentStrategy
+	if spec != nil && spec.Type == string(appsv1.RecreateDeploymentStrategyType) {
+		return appsv1.DeploymentStrategy{Type: appsv1.RecreateDeploymentStrategyType}
+	}
+
+	maxSurge := langopv1alpha1.DefaultAgentMaxSurge
+	maxUnavailable := langopv1alpha1.DefaultAgentMaxUnavailable
+	if spec != nil && spec.RollingUpdate != nil {
+		if spec.RollingUpdate.MaxSurge != nil {
+			maxSurge = *spec.RollingUpdate.MaxSurge
+		}
+		if spec.RollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = *spec.RollingUpdate.MaxUnavailable
+		}
+	}
+
+	surge := intstr.FromInt32(maxSurge)
+	unavailable := intstr.FromInt32(maxUnavailable)
+	return appsv1.DeploymentStrategy{
+		Type: appsv1.RollingUpdateDeploymentStrategyType,
+		RollingUpdate: &appsv1.RollingUpdateDeployment{
+			MaxSurge:       &surge,
+			MaxUnavailable: &unavailable,
+		},
+	}
+}
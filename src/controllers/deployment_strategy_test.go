@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+func TestAgentDeploymentStrategy(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	tests := []struct {
+		name                string
+		strategy            *langopv1alpha1.UpdateStrategySpec
+		expectType          appsv1.DeploymentStrategyType
+		expectSurge         int
+		expectUnavailable   int
+		expectRollingUpdate bool
+	}{
+		{name: "unset uses zero-downtime defaults", expectType: appsv1.RollingUpdateDeploymentStrategyType,
+			expectSurge: 1, expectUnavailable: 0, expectRollingUpdate: true},
+		{name: "custom parameters", strategy: &langopv1alpha1.UpdateStrategySpec{
+			Type:          "RollingUpdate",
+			RollingUpdate: &langopv1alpha1.RollingUpdateSpec{MaxSurge: int32Ptr(2), MaxUnavailable: int32Ptr(1)},
+		}, expectType: appsv1.RollingUpdateDeploymentStrategyType, expectSurge: 2, expectUnavailable: 1, expectRollingUpdate: true},
+		{name: "partial parameters keep the other default", strategy: &langopv1alpha1.UpdateStrategySpec{
+			RollingUpdate: &langopv1alpha1.RollingUpdateSpec{MaxUnavailable: int32Ptr(1)},
+		}, expectType: appsv1.RollingUpdateDeploymentStrategyType, expectSurge: 1, expectUnavailable: 1, expectRollingUpdate: true},
+		{name: "recreate", strategy: &langopv1alpha1.UpdateStrategySpec{Type: "Recreate"},
+			expectType: appsv1.RecreateDeploymentStrategyType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{Spec: langopv1alpha1.LanguageAgentSpec{DeploymentStrategy: tt.strategy}}
+
+			strategy := agentDeploymentStrategy(agent)
+			if strategy.Type != tt.expectType {
+				t.Errorf("Expected strategy type %s, got %s", tt.expectType, strategy.Type)
+			}
+			if !tt.expectRollingUpdate {
+				if strategy.RollingUpdate != nil {
+					t.Errorf("Expected no rolling update parameters, got %+v", strategy.RollingUpdate)
+				}
+				return
+			}
+			if strategy.RollingUpdate == nil {
+				t.Fatal("Expected rolling update parameters")
+			}
+			if got := strategy.RollingUpdate.MaxSurge.IntValue(); got != tt.expectSurge {
+				t.Errorf("Expected maxSurge %d, got %d", tt.expectSurge, got)
+			}
+			if got := strategy.RollingUpdate.MaxUnavailable.IntValue(); got != tt.expectUnavailable {
+				t.Errorf("Expected maxUnavailable %d, got %d", tt.expectUnavailable, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// defaultDisruptionBudget is the minimum number of available pods when spec.disruptionBudget is unset
+const defaultDisruptionBudget = int32(1)
+
+// reconcilePDB keeps a PodDisruptionBudget for agents running more than one replica, so a node
+// drain can't evict every replica at once and drop in-flight webhook requests. Single-replica
+// and scheduled agents have no budget, and a budget left over from a scale down is deleted.
+func (r *LanguageAgentReconciler) reconcilePDB(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
+	pdb := &policyv1.PodDisruptionBudget{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      agent.Name,
+			Namespace: agent.Namespace,
+		},
+	}
+
+	replicas := int32(1)
+	if agent.Spec.Replicas != nil {
+		replicas = *agent.Spec.Replicas
+	}
+	if agentWorkload(agent) != WorkloadDeployment || replicas <= 1 {
+		if err := r.Get(ctx, types.NamespacedName{Name: pdb.Name, Namespace: pdb.Namespace}, pdb); err != nil {
+			return client.IgnoreNotFound(err)
+		}
+		if !metav1.IsControlledBy(pdb, agent) {
+			return nil
+		}
+		log.FromContext(ctx).Info("Deleting PodDisruptionBudget of agent no longer running multiple replicas", "replicas", replicas)
+		if err := r.Delete(ctx, pdb); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete PodDisruptionBudget: %w", err)
+		}
+		return nil
+	}
+
+	minAvailable := defaultDisruptionBudget
+	if agent.Spec.DisruptionBudget != nil {
+		minAvailable = *agent.Spec.DisruptionBudget
+	}
+
+	// Select the same pods as the Deployment
+	labels := GetCommonLabels(agent.Name, "LanguageAgent")
+	if agent.Spec.ClusterRef != "" {
+		labels["langop.io/cluster"] = agent.Spec.ClusterRef
+	}
+
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, pdb, func() error {
+		if err := controllerutil.SetControllerReference(agent, pdb, r.Scheme); err != nil {
+			return err
+		}
+		pdb.Labels = labels
+		pdb.Spec.MinAvailable = &intstr.IntOrString{Type: intstr.Int, IntVal: minAvailable}
+		pdb.Spec.Selector = &metav1.LabelSelector{MatchLabels: labels}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to reconcile PodDisruptionBudget: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	policyv1 "k8s.io/api/policy/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newPDBTestAgent(mode string, replicas int32) *langopv1alpha1.LanguageAgent {
+	return &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "support-agent", Namespace: "default", UID: "agent-uid"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Instructions:  "Answer support questions",
+			ExecutionMode: mode,
+			Replicas:      &replicas,
+		},
+	}
+}
+
+func TestReconcilePDB_MultiReplicaAgent(t *testing.T) {
+	ctx := context.Background()
+	scheme := testutil.SetupTestScheme(t)
+	agent := newPDBTestAgent("interactive", 3)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+
+	if err := reconciler.reconcilePDB(ctx, agent); err != nil {
+		t.Fatalf("reconcilePDB failed: %v", err)
+	}
+	pdb := &policyv1.PodDisruptionBudget{}
+	if err := fakeClient.Get(ctx, key, pdb); err != nil {
+		t.Fatalf("Expected a PodDisruptionBudget for a 3 replica agent: %v", err)
+	}
+	if pdb.Spec.MinAvailable == nil || pdb.Spec.MinAvailable.IntValue() != 1 {
+		t.Errorf("Expected minAvailable 1 by default, got %v", pdb.Spec.MinAvailable)
+	}
+	if pdb.Spec.Selector == nil || pdb.Spec.Selector.MatchLabels["app.kubernetes.io/name"] != agent.Name {
+		t.Errorf("Expected the budget to select the agent's pods, got %v", pdb.Spec.Selector)
+	}
+	if !metav1.IsControlledBy(pdb, agent) {
+		t.Error("Expected the budget to be owned by the agent")
+	}
+
+	// A configured budget is applied
+	agent.Spec.DisruptionBudget = &[]int32{2}[0]
+	if err := reconciler.reconcilePDB(ctx, agent); err != nil {
+		t.Fatalf("reconcilePDB failed: %v", err)
+	}
+	if err := fakeClient.Get(ctx, key, pdb); err != nil {
+		t.Fatalf("Failed to get PodDisruptionBudget: %v", err)
+	}
+	if pdb.Spec.MinAvailable.IntValue() != 2 {
+		t.Errorf("Expected minAvailable 2, got %v", pdb.Spec.MinAvailable)
+	}
+
+	// Scaling back to a single replica removes the budget
+	agent.Spec.Replicas = &[]int32{1}[0]
+	if err := reconciler.reconcilePDB(ctx, agent); err != nil {
+		t.Fatalf("reconcilePDB failed: %v", err)
+	}
+	if err := fakeClient.Get(ctx, key, pdb); !errors.IsNotFound(err) {
+		t.Errorf("Expected the PodDisruptionBudget to be deleted after scaling to 1 replica, got %v", err)
+	}
+}
+
+func TestReconcilePDB_SkipsSingleReplicaAndScheduledAgents(t *testing.T) {
+	ctx := context.Background()
+	scheme := testutil.SetupTestScheme(t)
+
+	for _, agent := range []*langopv1alpha1.LanguageAgent{
+		newPDBTestAgent("interactive", 1),
+		newPDBTestAgent("scheduled", 3),
+	} {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).Build()
+		reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+		if err := reconciler.reconcilePDB(ctx, agent); err != nil {
+			t.Fatalf("reconcilePDB failed: %v", err)
+		}
+		pdb := &policyv1.PodDisruptionBudget{}
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, pdb)
+		if !errors.IsNotFound(err) {
+			t.Errorf("Expected no PodDisruptionBudget for a %s agent with %d replica(s), got %v",
+				agent.Spec.ExecutionMode, *agent.Spec.Replicas, err)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// Drift policies for owned resources edited outside the operator
+const (
+	// DriftPolicyCorrect restores the operator's intended state and emits a DriftCorrected event
+	DriftPolicyCorrect = "correct"
+	// DriftPolicyWarn leaves external edits in place and emits a DriftDetected event
+	DriftPolicyWarn = "warn"
+)
+
+const (
+	// codeHashAnnotation records the hash of the code the operator wrote to the code ConfigMap
+	codeHashAnnotation = "langop.io/code-hash"
+	// appliedHashAnnotation records the fingerprint of the Deployment spec the operator applied
+	appliedHashAnnotation = "langop.io/applied-hash"
+)
+
+// AgentDrift counts out-of-band edits to agent-owned resources
+var AgentDrift = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "langop_agent_drift_total",
+		Help: "Number of external edits detected on agent-owned resources by resource and action (corrected or warned)",
+	},
+	[]string{"namespace", "resource", "action"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(AgentDrift)
+}
+
+// ParseDriftPolicy validates a drift policy, defaulting to correct when empty
+func ParseDriftPolicy(policy string) (string, error) {
+	switch policy {
+	case "", DriftPolicyCorrect:
+		return DriftPolicyCorrect, nil
+	case DriftPolicyWarn:
+		return DriftPolicyWarn, nil
+	default:
+		return "", fmt.Errorf("unknown drift policy %q, expected %q or %q", policy, DriftPolicyCorrect, DriftPolicyWarn)
+	}
+}
+
+// correctsDrift reports whether external edits to owned resources are reverted
+func (r *LanguageAgentReconciler) correctsDrift() bool {
+	return r.DriftPolicy != DriftPolicyWarn
+}
+
+// recordDrift emits an event and metric for an external edit to an owned resource
+func (r *LanguageAgentReconciler) recordDrift(agent *langopv1alpha1.LanguageAgent, resource, name string, corrected bool) {
+	action := "warned"
+	if corrected {
+		action = "corrected"
+	}
+	AgentDrift.WithLabelValues(agent.Namespace, resource, action).Inc()
+
+	if r.Recorder == nil {
+		return
+	}
+	if corrected {
+		r.Recorder.Eventf(agent, corev1.EventTypeWarning, "DriftCorrected",
+			"%s %s was edited outside the operator and has been restored", resource, name)
+	} else {
+		r.Recorder.Eventf(agent, corev1.EventTypeWarning, "DriftDetected",
+			"%s %s was edited outside the operator; leaving it unchanged because the drift policy is %s", resource, name, DriftPolicyWarn)
+	}
+}
+
+// driftWarningTracker remembers the drifted state each resource was last reported in, so the warn
+// policy reports an external edit once instead of on every reconcile that finds it still in place
+type driftWarningTracker struct {
+	mu     sync.Mutex
+	warned map[string]string
+}
+
+func newDriftWarningTracker() *driftWarningTracker {
+	return &driftWarningTracker{warned: make(map[string]string)}
+}
+
+// transition records the drifted state of a resource, "" when it isn't drifted, and reports
+// whether the resource has drifted into a state that wasn't reported yet
+func (t *driftWarningTracker) transition(key, state string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if state == "" {
+		delete(t.warned, key)
+		return false
+	}
+	if t.warned[key] == state {
+		return false
+	}
+	t.warned[key] = state
+	return true
+}
+
+// getDriftWarnings returns the tracker of drift reported under the warn policy
+func (r *LanguageAgentReconciler) getDriftWarnings() *driftWarningTracker {
+	r.driftWarningsOnce.Do(func() {
+		r.driftWarnings = newDriftWarningTracker()
+	})
+	return r.driftWarnings
+}
+
+// warnDriftTransition reports drift of a resource the warn policy leaves in place, only when it
+// drifts into a new state. state identifies the drifted content and is "" when it isn't drifted.
+func (r *LanguageAgentReconciler) warnDriftTransition(agent *langopv1alpha1.LanguageAgent, resource, name, state string) {
+	key := agent.Namespace + "/" + resource + "/" + name
+	if r.getDriftWarnings().transition(key, state) {
+		r.recordDrift(agent, resource, name, false)
+	}
+}
+
+// codeDrifted reports whether the code ConfigMap content differs from the code the operator last wrote.
+// ConfigMaps written before code hashes were recorded are never reported as drifted.
+func codeDrifted(configMap *corev1.ConfigMap) bool {
+	expected, ok := configMap.Annotations[codeHashAnnotation]
+	if !ok {
+		return false
+	}
+	return hashString(configMap.Data["agent.rb"]) != expected
+}
+
+// containerFingerprint holds the container fields the operator sets that the API server never defaults
+type containerFingerprint struct {
+	Name    string
+	Image   string
+	Command []string
+	Args    []string
+	Env     map[string]string
+}
+
+// deploymentFingerprint hashes the parts of a Deployment spec that are commonly edited by hand
+// (replicas, images, commands, and literal env vars). Defaulted fields are excluded so the live
+// object and the operator's intended spec produce the same fingerprint.
+func deploymentFingerprint(spec *appsv1.DeploymentSpec) string {
+	fingerprint := struct {
+		Replicas       int32
+		InitContainers []containerFingerprint
+		Containers     []containerFingerprint
+	}{Replicas: 1}
+
+	if spec.Replicas != nil {
+		fingerprint.Replicas = *spec.Replicas
+	}
+	for _, container := range spec.Template.Spec.InitContainers {
+		fingerprint.InitContainers = append(fingerprint.InitContainers, newContainerFingerprint(container))
+	}
+	for _, container := range spec.Template.Spec.Containers {
+		fingerprint.Containers = append(fingerprint.Containers, newContainerFingerprint(container))
+	}
+
+	// Marshalling plain strings, slices, and maps cannot fail; map keys are sorted
+	data, _ := json.Marshal(fingerprint)
+	return hashString(string(data))
+}
+
+func newContainerFingerprint(container corev1.Container) containerFingerprint {
+	fingerprint := containerFingerprint{
+		Name:    container.Name,
+		Image:   container.Image,
+		Command: container.Command,
+		Args:    container.Args,
+		Env:     make(map[string]string),
+	}
+	for _, env := range container.Env {
+		if env.ValueFrom == nil {
+			fingerprint.Env[env.Name] = env.Value
+		}
+	}
+	return fingerprint
+}
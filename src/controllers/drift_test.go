@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// drainEvents returns all events recorded so far
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func hasEvent(events []string, reason string) bool {
+	for _, event := range events {
+		if strings.Contains(event, " "+reason+" ") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLanguageAgentController_DeploymentDrift(t *testing.T) {
+	tests := []struct {
+		name          string
+		policy        string
+		expectedImage string
+		expectedEvent string
+	}{
+		{name: "correct restores the deployment", policy: DriftPolicyCorrect, expectedImage: "ghcr.io/language-operator/agent:v1", expectedEvent: "DriftCorrected"},
+		{name: "warn keeps the external edit", policy: DriftPolicyWarn, expectedImage: "ghcr.io/someone/patched:dev", expectedEvent: "DriftDetected"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "drift-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:         "ghcr.io/language-operator/agent:v1",
+					ExecutionMode: "autonomous",
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent).
+				WithStatusSubresource(agent).
+				Build()
+
+			recorder := record.NewFakeRecorder(100)
+			reconciler := &LanguageAgentReconciler{
+				Client:          fakeClient,
+				Scheme:          scheme,
+				Log:             logr.Discard(),
+				Recorder:        recorder,
+				RegistryManager: &mockRegistryManager{},
+				DriftPolicy:     tt.policy,
+			}
+			reconciler.InitializeGatewayCache()
+
+			ctx := context.Background()
+			key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			// A second reconcile without external edits must not report drift
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			if events := drainEvents(recorder); hasEvent(events, "DriftCorrected") || hasEvent(events, "DriftDetected") {
+				t.Fatalf("Expected no drift before external edits, got events %v", events)
+			}
+
+			deployment := &appsv1.Deployment{}
+			if err := fakeClient.Get(ctx, key, deployment); err != nil {
+				t.Fatalf("Failed to get Deployment: %v", err)
+			}
+			deployment.Spec.Template.Spec.Containers[0].Image = "ghcr.io/someone/patched:dev"
+			if err := fakeClient.Update(ctx, deployment); err != nil {
+				t.Fatalf("Failed to edit Deployment: %v", err)
+			}
+
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			if err := fakeClient.Get(ctx, key, deployment); err != nil {
+				t.Fatalf("Failed to get Deployment: %v", err)
+			}
+			if image := deployment.Spec.Template.Spec.Containers[0].Image; image != tt.expectedImage {
+				t.Errorf("Expected image %q after reconcile, got %q", tt.expectedImage, image)
+			}
+			if events := drainEvents(recorder); !hasEvent(events, tt.expectedEvent) {
+				t.Errorf("Expected %s event, got %v", tt.expectedEvent, events)
+			}
+
+			// A drift the warn policy leaves in place is reported only once
+			if tt.policy == DriftPolicyWarn {
+				if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+					t.Fatalf("Reconcile failed: %v", err)
+				}
+				if events := drainEvents(recorder); hasEvent(events, "DriftDetected") {
+					t.Errorf("Expected the unchanged drift not to be reported again, got %v", events)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_CodeConfigMapDriftWarn(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{ModelName: "gpt-4"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "code-drift-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			Instructions:  "Summarize the news",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+		},
+	}
+
+	synthesized := "agent 'code-drift-agent' do\nend"
+	tampered := "agent 'code-drift-agent' do\n  # patched by hand\nend"
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateConfigMapName(agent.Name, "code"),
+			Namespace: agent.Namespace,
+			Annotations: map[string]string{
+				codeHashAnnotation:            hashString(synthesized),
+				"langop.io/instructions-hash": hashString(agent.Spec.Instructions),
+				"langop.io/tools-hash":        hashString(""),
+				"langop.io/models-hash":       hashString("test-model"),
+				"langop.io/persona-hash":      hashString(""),
+			},
+		},
+		Data: map[string]string{"agent.rb": tampered},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(model, agent, codeConfigMap).
+		WithStatusSubresource(agent).
+		Build()
+
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        recorder,
+		RegistryManager: &mockRegistryManager{},
+		DriftPolicy:     DriftPolicyWarn,
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	events := drainEvents(recorder)
+	if !hasEvent(events, "DriftDetected") {
+		t.Errorf("Expected DriftDetected event, got %v", events)
+	}
+	if hasEvent(events, "SynthesisStarted") {
+		t.Errorf("Expected warn-only drift not to trigger synthesis, got %v", events)
+	}
+
+	updated := &corev1.ConfigMap{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: codeConfigMap.Name, Namespace: codeConfigMap.Namespace}, updated); err != nil {
+		t.Fatalf("Failed to get code ConfigMap: %v", err)
+	}
+	if updated.Data["agent.rb"] != tampered {
+		t.Errorf("Expected warn-only drift to keep the edited code")
+	}
+}
+
+func TestDriftWarningTracker(t *testing.T) {
+	tracker := newDriftWarningTracker()
+	steps := []struct {
+		state    string
+		expected bool
+	}{
+		{state: "", expected: false},
+		{state: "edit-1", expected: true},
+		{state: "edit-1", expected: false},
+		{state: "edit-2", expected: true},
+		{state: "", expected: false},
+		{state: "edit-2", expected: true},
+	}
+	for i, step := range steps {
+		if got := tracker.transition("default/Deployment/agent", step.state); got != step.expected {
+			t.Errorf("Step %d (%q): expected %v, got %v", i, step.state, step.expected, got)
+		}
+	}
+}
+
+func TestCodeDrifted(t *testing.T) {
+	code := "agent 'a' do\nend"
+	tests := []struct {
+		name        string
+		annotations map[string]string
+		data        string
+		expected    bool
+	}{
+		{name: "matching hash", annotations: map[string]string{codeHashAnnotation: hashString(code)}, data: code, expected: false},
+		{name: "edited code", annotations: map[string]string{codeHashAnnotation: hashString(code)}, data: code + "\n# edit", expected: true},
+		{name: "no recorded hash", data: code, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Annotations: tt.annotations},
+				Data:       map[string]string{"agent.rb": tt.data},
+			}
+			if got := codeDrifted(configMap); got != tt.expected {
+				t.Errorf("Expected drifted=%v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestParseDriftPolicy(t *testing.T) {
+	if policy, err := ParseDriftPolicy(""); err != nil || policy != DriftPolicyCorrect {
+		t.Errorf("Expected empty policy to default to %q, got %q (err %v)", DriftPolicyCorrect, policy, err)
+	}
+	if _, err := ParseDriftPolicy("ignore"); err == nil {
+		t.Error("Expected error for unknown drift policy")
+	}
+}
@@ -0,0 +1,320 @@
+package controllers
+
+import (
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/cni"
+)
+
+// EffectiveConfigPath is the metrics server path serving the operator's effective configuration
+const EffectiveConfigPath = "/debug/config"
+
+// redactedValue replaces the value of settings that look like credentials
+const redactedValue = "<redacted>"
+
+// effectiveConfigEnvPrefixes selects the environment variables reported by the config endpoint
+var effectiveConfigEnvPrefixes = []string{"TELEMETRY_ADAPTER_", "OTEL_", "POD_NAMESPACE", "STARTUP_TIMEOUT"}
+
+// sensitiveSettingMarkers mark flag and environment variable names whose values are redacted.
+// OTLP headers commonly carry authorization tokens.
+var sensitiveSettingMarkers = []string{"api-key", "api_key", "apikey", "token", "secret", "password", "credential", "headers"}
+
+// sensitiveValuePatterns match values that are credentials whatever the setting is called:
+// authorization schemes, JWTs, and well-known API key prefixes
+var sensitiveValuePatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(bearer|basic)\s+\S+`),
+	regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+\.`),
+	regexp.MustCompile(`\b(sk|pk|rk|ghp|gho|ghs|glpat|xox[abpr])[-_][A-Za-z0-9_-]{8,}`),
+	regexp.MustCompile(`\bAKIA[0-9A-Z]{16}\b`),
+}
+
+// opaqueKeyPattern matches a long run of letters, digits, and key punctuation, which is how
+// generated keys and tokens look when they also contain a digit
+var opaqueKeyPattern = regexp.MustCompile(`^[A-Za-z0-9_+=-]{32,}$`)
+
+// EffectiveConfig is the operator's effective runtime configuration
+type EffectiveConfig struct {
+	Flags         map[string]string    `json:"flags,omitempty"`
+	Environment   map[string]string    `json:"environment,omitempty"`
+	CNI           *CNIConfig           `json:"cni,omitempty"`
+	LanguageAgent *LanguageAgentConfig `json:"languageAgent,omitempty"`
+	Learning      *LearningConfig      `json:"learning,omitempty"`
+}
+
+// CNIConfig reports the CNI detected at startup
+type CNIConfig struct {
+	Name                  string `json:"name"`
+	Version               string `json:"version,omitempty"`
+	SupportsNetworkPolicy bool   `json:"supportsNetworkPolicy"`
+}
+
+// GatewayAPIConfig reports the cached Gateway API detection result
+type GatewayAPIConfig struct {
+	Available bool       `json:"available"`
+	CheckedAt *time.Time `json:"checkedAt,omitempty"`
+}
+
+// SynthesisLimitsConfig reports the synthesis rate, quota, and concurrency limits
+type SynthesisLimitsConfig struct {
+	MaxPerHour        int     `json:"maxPerHour,omitempty"`
+	MaxCostPerDay     float64 `json:"maxCostPerDay,omitempty"`
+	MaxAttemptsPerDay int     `json:"maxAttemptsPerDay,omitempty"`
+	Currency          string  `json:"currency,omitempty"`
+	MaxConcurrent     int     `json:"maxConcurrent,omitempty"`
+	Fairness          string  `json:"fairness,omitempty"`
+}
+
+// LanguageAgentConfig reports the effective settings of the LanguageAgent controller
+type LanguageAgentConfig struct {
+	SelfHealingEnabled         bool                   `json:"selfHealingEnabled"`
+	MaxSelfHealingAttempts     int32                  `json:"maxSelfHealingAttempts"`
+	SelfHealingStabilityWindow string                 `json:"selfHealingStabilityWindow"`
+	SelfHealingRollbackWindow  string                 `json:"selfHealingRollbackWindow"`
+	UnhealthyThreshold         string                 `json:"unhealthyThreshold"`
+	NetworkPolicyTimeout       string                 `json:"networkPolicyTimeout"`
+	NetworkPolicyRetries       int                    `json:"networkPolicyRetries"`
+	ConditionMetricTypes       []string               `json:"conditionMetricTypes,omitempty"`
+	FeatureGates               map[string]bool        `json:"featureGates"`
+	DriftPolicy                string                 `json:"driftPolicy"`
+	MaxConcurrentAgentRestarts int32                  `json:"maxConcurrentAgentRestarts"`
+	BatchStatusUpdates         bool                   `json:"batchStatusUpdates"`
+	ReconcilePriority          bool                   `json:"reconcilePriority"`
+	AuditEnabled               bool                   `json:"auditEnabled"`
+	ChaosEnabled               bool                   `json:"chaosEnabled"`
+	SynthesisExampleLibrary    string                 `json:"synthesisExampleLibrary,omitempty"`
+	SynthesisRedactPatterns    int                    `json:"synthesisRedactPatterns,omitempty"`
+	Synthesis                  *SynthesisLimitsConfig `json:"synthesis,omitempty"`
+	GatewayAPI                 *GatewayAPIConfig      `json:"gatewayAPI,omitempty"`
+	Registries                 []string               `json:"registries,omitempty"`
+}
+
+// LearningConfig reports the effective settings of the Learning controller
+type LearningConfig struct {
+	Enabled                       bool    `json:"enabled"`
+	Threshold                     int32   `json:"threshold"`
+	Interval                      string  `json:"interval"`
+	TraceWindow                   string  `json:"traceWindow"`
+	MinTraceAge                   string  `json:"minTraceAge"`
+	HealthGateWindow              string  `json:"healthGateWindow"`
+	MaxVersions                   int32   `json:"maxVersions"`
+	PatternConfidenceMin          float64 `json:"patternConfidenceMin"`
+	ErrorFailureThreshold         int32   `json:"errorFailureThreshold"`
+	ErrorCooldownPeriod           string  `json:"errorCooldownPeriod"`
+	MaxErrorResynthesisAttempts   int32   `json:"maxErrorResynthesisAttempts"`
+	MaxConcurrentLearningRollouts int32   `json:"maxConcurrentLearningRollouts"`
+	TelemetryAvailable            bool    `json:"telemetryAvailable"`
+	AuditEnabled                  bool    `json:"auditEnabled"`
+}
+
+// EffectiveConfigHandler serves the operator's effective runtime configuration as JSON.
+// Its fields are read on every request and must be set before the manager starts.
+type EffectiveConfigHandler struct {
+	// Flags holds the parsed command line flags (nil omits them)
+	Flags *flag.FlagSet
+	// CNI is the CNI detected at startup (nil if detection failed)
+	CNI *cni.CNICapabilities
+	// AgentReconciler and LearningReconciler are the running controllers (nil omits them)
+	AgentReconciler    *LanguageAgentReconciler
+	LearningReconciler *LearningReconciler
+}
+
+// ServeHTTP implements http.Handler
+func (h *EffectiveConfigHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if req.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(h.EffectiveConfig()); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// EffectiveConfig collects the operator's current effective configuration
+func (h *EffectiveConfigHandler) EffectiveConfig() *EffectiveConfig {
+	config := &EffectiveConfig{
+		Environment: effectiveEnvironment(),
+	}
+
+	if h.Flags != nil {
+		config.Flags = make(map[string]string)
+		h.Flags.VisitAll(func(f *flag.Flag) {
+			config.Flags[f.Name] = redactSetting(f.Name, f.Value.String())
+		})
+	}
+	if h.CNI != nil {
+		config.CNI = &CNIConfig{
+			Name:                  h.CNI.Name,
+			Version:               h.CNI.Version,
+			SupportsNetworkPolicy: h.CNI.SupportsNetworkPolicy,
+		}
+	}
+	if h.AgentReconciler != nil {
+		config.LanguageAgent = h.AgentReconciler.effectiveConfig()
+	}
+	if h.LearningReconciler != nil {
+		config.Learning = h.LearningReconciler.effectiveConfig()
+	}
+	return config
+}
+
+// effectiveConfig reports the reconciler's settings with defaults applied
+func (r *LanguageAgentReconciler) effectiveConfig() *LanguageAgentConfig {
+	stabilityWindow := r.SelfHealingStabilityWindow
+	if stabilityWindow <= 0 {
+		stabilityWindow = defaultSelfHealingStabilityWindow
+	}
+	driftPolicy := r.DriftPolicy
+	if driftPolicy == "" {
+		driftPolicy = DriftPolicyCorrect
+	}
+
+	featureGates := make(map[string]bool)
+	for _, gate := range langopv1alpha1.KnownFeatureGates() {
+		featureGates[gate] = r.featureGateEnabled(&langopv1alpha1.LanguageAgent{}, gate)
+	}
+
+	config := &LanguageAgentConfig{
+		SelfHealingEnabled:         r.SelfHealingEnabled,
+		MaxSelfHealingAttempts:     r.MaxSelfHealingAttempts,
+		SelfHealingStabilityWindow: stabilityWindow.String(),
+		SelfHealingRollbackWindow:  r.SelfHealingRollbackWindow.String(),
+		UnhealthyThreshold:         r.UnhealthyThreshold.String(),
+		NetworkPolicyTimeout:       r.NetworkPolicyTimeout.String(),
+		NetworkPolicyRetries:       r.NetworkPolicyRetries,
+		ConditionMetricTypes:       r.ConditionMetricTypes,
+		FeatureGates:               featureGates,
+		DriftPolicy:                driftPolicy,
+		MaxConcurrentAgentRestarts: r.MaxConcurrentAgentRestarts,
+		BatchStatusUpdates:         r.BatchStatusUpdates,
+		ReconcilePriority:          r.Priority != nil,
+		AuditEnabled:               r.Audit != nil,
+		ChaosEnabled:               r.ChaosEnabled,
+	}
+	if r.ExampleLibrary != nil {
+		config.SynthesisExampleLibrary = r.ExampleLibrary.Namespace + "/" + r.ExampleLibrary.Name
+	}
+	// Only the number of patterns is reported, since the patterns describe what they protect
+	if r.SynthesisRedactor != nil {
+		config.SynthesisRedactPatterns = r.SynthesisRedactor.Patterns()
+	}
+	if r.RateLimiter != nil || r.QuotaManager != nil || r.SynthesisSlots != nil {
+		config.Synthesis = &SynthesisLimitsConfig{}
+		if r.RateLimiter != nil {
+			config.Synthesis.MaxPerHour = r.RateLimiter.MaxPerHour()
+		}
+		if r.QuotaManager != nil {
+			config.Synthesis.MaxCostPerDay, config.Synthesis.MaxAttemptsPerDay, config.Synthesis.Currency = r.QuotaManager.Limits()
+		}
+		if r.SynthesisSlots != nil {
+			config.Synthesis.MaxConcurrent, config.Synthesis.Fairness = r.SynthesisSlots.Slots()
+		}
+	}
+	if r.GatewayAPI != nil {
+		available, checkedAt := r.GatewayAPI.LastResult()
+		config.GatewayAPI = &GatewayAPIConfig{Available: available}
+		if !checkedAt.IsZero() {
+			config.GatewayAPI.CheckedAt = &checkedAt
+		}
+	}
+	if r.RegistryManager != nil {
+		config.Registries = r.RegistryManager.GetRegistries()
+	}
+	return config
+}
+
+// effectiveConfig reports the reconciler's settings with defaults applied
+func (r *LearningReconciler) effectiveConfig() *LearningConfig {
+	agent := &langopv1alpha1.LanguageAgent{}
+	return &LearningConfig{
+		Enabled:                       r.LearningEnabled,
+		Threshold:                     r.LearningThreshold,
+		Interval:                      r.LearningInterval.String(),
+		TraceWindow:                   r.learningTraceWindow(agent).String(),
+		MinTraceAge:                   r.minTraceAge(agent).String(),
+		HealthGateWindow:              r.healthGateWindow(agent).String(),
+		MaxVersions:                   r.MaxVersions,
+		PatternConfidenceMin:          r.PatternConfidenceMin,
+		ErrorFailureThreshold:         r.ErrorFailureThreshold,
+		ErrorCooldownPeriod:           r.ErrorCooldownPeriod.String(),
+		MaxErrorResynthesisAttempts:   r.MaxErrorResynthesisAttempts,
+		MaxConcurrentLearningRollouts: r.MaxConcurrentLearningRollouts,
+		TelemetryAvailable:            r.TelemetryAdapter != nil && r.TelemetryAdapter.Available(),
+		AuditEnabled:                  r.Audit != nil,
+	}
+}
+
+// effectiveEnvironment returns the operator's configuration environment variables
+func effectiveEnvironment() map[string]string {
+	env := make(map[string]string)
+	for _, entry := range os.Environ() {
+		name, value, _ := strings.Cut(entry, "=")
+		for _, prefix := range effectiveConfigEnvPrefixes {
+			if strings.HasPrefix(name, prefix) {
+				env[name] = redactSetting(name, value)
+				break
+			}
+		}
+	}
+	return env
+}
+
+// redactSetting hides the value of a flag or environment variable that is or looks like a credential.
+// Values are checked as well as names, since a secret can end up in a setting named after
+// something else, such as a token in an endpoint URL.
+func redactSetting(name, value string) string {
+	if value == "" {
+		return value
+	}
+	if isSensitiveName(name) {
+		return redactedValue
+	}
+	return redactValue(value)
+}
+
+// isSensitiveName reports whether a setting or parameter name marks a credential
+func isSensitiveName(name string) bool {
+	lower := strings.ToLower(name)
+	for _, marker := range sensitiveSettingMarkers {
+		if strings.Contains(lower, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactValue hides credentials found in a setting's value. A URL keeps its host and path with
+// the password replaced, unless a query parameter carries a credential.
+func redactValue(value string) string {
+	if u, err := url.Parse(value); err == nil && u.Host != "" {
+		for key := range u.Query() {
+			if isSensitiveName(key) {
+				return redactedValue
+			}
+		}
+		if _, ok := u.User.Password(); ok {
+			return u.Redacted()
+		}
+	}
+	for _, pattern := range sensitiveValuePatterns {
+		if pattern.MatchString(value) {
+			return redactedValue
+		}
+	}
+	if opaqueKeyPattern.MatchString(value) && strings.ContainsAny(value, "0123456789") {
+		return redactedValue
+	}
+	return value
+}
@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+	"github.com/language-operator/language-operator/pkg/validation"
+)
+
+// setFailure records the category and detail of an agent failure in its status
+func setFailure(agent *langopv1alpha1.LanguageAgent, reason langopv1alpha1.FailureReason, message string) {
+	agent.Status.FailureReason = reason
+	agent.Status.FailureMessage = message
+}
+
+// clearFailure removes a recorded failure once the agent reconciles successfully. Runtime failures
+// are kept while self-healing still tracks consecutive pod failures. Returns true if status changed.
+func clearFailure(agent *langopv1alpha1.LanguageAgent) bool {
+	if agent.Status.ConsecutiveFailures > 0 {
+		return false
+	}
+	if agent.Status.FailureReason == "" && agent.Status.FailureMessage == "" {
+		return false
+	}
+	setFailure(agent, "", "")
+	return true
+}
+
+// classifySynthesisFailure maps a synthesis error to its failure category
+func classifySynthesisFailure(err error) langopv1alpha1.FailureReason {
+	switch {
+	case synthesis.IsQuotaExceeded(err):
+		return langopv1alpha1.FailureReasonQuota
+	case synthesis.IsMissingToolReference(err), synthesis.IsPersonaConstraintViolation(err), validation.IsLimitExceeded(err),
+		errors.Is(err, synthesis.ErrValidationFailed), synthesis.IsCostDataUnavailable(err):
+		return langopv1alpha1.FailureReasonValidation
+	default:
+		return langopv1alpha1.FailureReasonSynthesis
+	}
+}
+
+// classifyPodFailure maps the agent container state of a failed pod to its failure category.
+// A container in CrashLoopBackOff only reports an OOM kill in its last termination state.
+func classifyPodFailure(pod *corev1.Pod) langopv1alpha1.FailureReason {
+	for _, containerStatus := range pod.Status.ContainerStatuses {
+		if containerStatus.Name != "agent" {
+			continue
+		}
+
+		var reasons []string
+		if containerStatus.State.Waiting != nil {
+			reasons = append(reasons, containerStatus.State.Waiting.Reason)
+		}
+		if containerStatus.State.Terminated != nil {
+			reasons = append(reasons, containerStatus.State.Terminated.Reason)
+		}
+		if containerStatus.LastTerminationState.Terminated != nil {
+			reasons = append(reasons, containerStatus.LastTerminationState.Terminated.Reason)
+		}
+
+		for _, reason := range reasons {
+			switch reason {
+			case "OOMKilled":
+				return langopv1alpha1.FailureReasonOOM
+			case "ImagePullBackOff", "ErrImagePull", "InvalidImageName":
+				return langopv1alpha1.FailureReasonImagePull
+			}
+		}
+	}
+	return langopv1alpha1.FailureReasonRuntime
+}
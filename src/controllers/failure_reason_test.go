@@ -0,0 +1,253 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+	"github.com/language-operator/language-operator/pkg/validation"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestClassifySynthesisFailure(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected langopv1alpha1.FailureReason
+	}{
+		{
+			name:     "rate limit",
+			err:      fmt.Errorf("failed to synthesize: %w", &synthesis.QuotaExceededError{Err: fmt.Errorf("synthesis rate limit exceeded")}),
+			expected: langopv1alpha1.FailureReasonQuota,
+		},
+		{
+			name:     "missing tool reference",
+			err:      fmt.Errorf("lint failed: %w", &synthesis.MissingToolReferenceError{Tools: []string{"web_search"}}),
+			expected: langopv1alpha1.FailureReasonValidation,
+		},
+		{
+			name:     "validator over budget",
+			err:      fmt.Errorf("security validation failed: %w", &validation.LimitExceededError{Resource: "time", Limit: "1s"}),
+			expected: langopv1alpha1.FailureReasonValidation,
+		},
+		{
+			name:     "generated code failed validation",
+			err:      fmt.Errorf("synthesis failed: %w: %w", synthesis.ErrValidationFailed, fmt.Errorf("schema validation failed with 2 violations")),
+			expected: langopv1alpha1.FailureReasonValidation,
+		},
+		{
+			name:     "model error",
+			err:      fmt.Errorf("LLM call failed"),
+			expected: langopv1alpha1.FailureReasonSynthesis,
+		},
+		{
+			name:     "provider rejected credentials",
+			err:      fmt.Errorf("synthesis failed: %w", synthesis.ErrProviderAuth),
+			expected: langopv1alpha1.FailureReasonSynthesis,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := classifySynthesisFailure(tt.err); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+// newCrashedPod creates a failed agent pod with the given agent container status
+func newCrashedPod(agent *langopv1alpha1.LanguageAgent, status corev1.ContainerStatus) *corev1.Pod {
+	status.Name = "agent"
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      agent.Name + "-abc",
+			Namespace: agent.Namespace,
+			Labels:    GetCommonLabels(agent.Name, "LanguageAgent"),
+		},
+		Status: corev1.PodStatus{
+			Phase:             corev1.PodRunning,
+			ContainerStatuses: []corev1.ContainerStatus{status},
+		},
+	}
+}
+
+func TestDetectPodFailures_FailureReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   corev1.ContainerStatus
+		expected langopv1alpha1.FailureReason
+	}{
+		{
+			name: "crash loop after OOM kill",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}},
+				LastTerminationState: corev1.ContainerState{
+					Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137},
+				},
+			},
+			expected: langopv1alpha1.FailureReasonOOM,
+		},
+		{
+			name: "image pull back-off",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff", Message: "Back-off pulling image"}},
+			},
+			expected: langopv1alpha1.FailureReasonImagePull,
+		},
+		{
+			name: "application error",
+			status: corev1.ContainerStatus{
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}},
+			},
+			expected: langopv1alpha1.FailureReasonRuntime,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			agent := newSelfHealingTestAgent()
+			pod := newCrashedPod(agent, tt.status)
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent, pod).
+				WithStatusSubresource(agent).
+				Build()
+
+			reconciler := &LanguageAgentReconciler{
+				Client:             fakeClient,
+				Scheme:             scheme,
+				Log:                logr.Discard(),
+				Recorder:           record.NewFakeRecorder(10),
+				SelfHealingEnabled: true,
+				UnhealthyThreshold: 5 * time.Minute,
+			}
+
+			ctx := context.Background()
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			if err := reconciler.detectPodFailures(ctx, agent); err != nil {
+				t.Fatalf("detectPodFailures failed: %v", err)
+			}
+
+			if agent.Status.FailureReason != tt.expected {
+				t.Errorf("Expected failure reason %q, got %q", tt.expected, agent.Status.FailureReason)
+			}
+			if agent.Status.FailureMessage == "" {
+				t.Error("Expected a failure message")
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_FailureReason(t *testing.T) {
+	tests := []struct {
+		name       string
+		mutate     func(agent *langopv1alpha1.LanguageAgent)
+		registries []string
+		expectErr  bool
+		expected   langopv1alpha1.FailureReason
+	}{
+		{
+			name:       "registry not allowed",
+			registries: []string{"quay.io"},
+			expectErr:  true,
+			expected:   langopv1alpha1.FailureReasonValidation,
+		},
+		{
+			name: "dependency not ready",
+			mutate: func(agent *langopv1alpha1.LanguageAgent) {
+				agent.Spec.DependsOn = []langopv1alpha1.AgentReference{{Name: "missing-agent"}}
+			},
+			expected: langopv1alpha1.FailureReasonDependency,
+		},
+		{
+			name: "synthesis failed",
+			mutate: func(agent *langopv1alpha1.LanguageAgent) {
+				// Self-healing attempts are exhausted, so synthesis fails without calling a model
+				agent.Spec.Instructions = "Summarize the news"
+				agent.Spec.ModelRefs = []langopv1alpha1.ModelReference{{Name: "test-model"}}
+				agent.Status.ConsecutiveFailures = 3
+				agent.Status.SelfHealingAttempts = 2
+			},
+			expectErr: true,
+			expected:  langopv1alpha1.FailureReasonSynthesis,
+		},
+		{
+			name: "success clears a previous failure",
+			mutate: func(agent *langopv1alpha1.LanguageAgent) {
+				agent.Status.FailureReason = langopv1alpha1.FailureReasonDependency
+				agent.Status.FailureMessage = "dependency default/missing-agent not found"
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "failing-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:         "ghcr.io/language-operator/agent:latest",
+					ExecutionMode: "autonomous",
+				},
+			}
+			if tt.mutate != nil {
+				tt.mutate(agent)
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent).
+				WithStatusSubresource(agent).
+				Build()
+
+			reconciler := &LanguageAgentReconciler{
+				Client:                 fakeClient,
+				Scheme:                 scheme,
+				Log:                    logr.Discard(),
+				Recorder:               &record.FakeRecorder{},
+				RegistryManager:        &mockRegistryManager{registries: tt.registries},
+				SelfHealingEnabled:     true,
+				MaxSelfHealingAttempts: 2,
+			}
+			reconciler.InitializeGatewayCache()
+
+			ctx := context.Background()
+			key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Reconcile error = %v, expectErr %v", err, tt.expectErr)
+			}
+
+			updated := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, key, updated); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			if updated.Status.FailureReason != tt.expected {
+				t.Errorf("Expected failure reason %q, got %q", tt.expected, updated.Status.FailureReason)
+			}
+			if tt.expected == "" && updated.Status.FailureMessage != "" {
+				t.Errorf("Expected failure message to be cleared, got %q", updated.Status.FailureMessage)
+			}
+			if tt.expected != "" && updated.Status.FailureMessage == "" {
+				t.Error("Expected a failure message")
+			}
+		})
+	}
+}
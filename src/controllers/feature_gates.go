@@ -0,0 +1,18 @@
+package controllers
+
+import (
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// featureGateEnabled resolves a feature gate for an agent. The agent's spec.featureGates takes
+// precedence over the operator-wide FeatureGates, which take precedence over the built-in default.
+func (r *LanguageAgentReconciler) featureGateEnabled(agent *langopv1alpha1.LanguageAgent, gate string) bool {
+	if enabled, ok := agent.Spec.FeatureGates[gate]; ok {
+		return enabled
+	}
+	if enabled, ok := r.FeatureGates[gate]; ok {
+		return enabled
+	}
+	enabled, _ := langopv1alpha1.FeatureGateDefault(gate)
+	return enabled
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestFeatureGateEnabled(t *testing.T) {
+	gate := langopv1alpha1.FeatureGateUnhealthyPodDetection
+
+	tests := []struct {
+		name         string
+		operatorGate map[string]bool
+		agentGate    map[string]bool
+		expected     bool
+	}{
+		{name: "built-in default", expected: true},
+		{name: "operator disables", operatorGate: map[string]bool{gate: false}, expected: false},
+		{name: "agent overrides operator", operatorGate: map[string]bool{gate: false}, agentGate: map[string]bool{gate: true}, expected: true},
+		{name: "agent disables", agentGate: map[string]bool{gate: false}, expected: false},
+		{name: "unknown gates are ignored", agentGate: map[string]bool{"NoSuchGate": false}, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := &LanguageAgentReconciler{FeatureGates: tt.operatorGate}
+			agent := newSelfHealingTestAgent()
+			agent.Spec.FeatureGates = tt.agentGate
+
+			if enabled := reconciler.featureGateEnabled(agent, gate); enabled != tt.expected {
+				t.Errorf("Expected gate %s enabled=%v, got %v", gate, tt.expected, enabled)
+			}
+		})
+	}
+}
+
+func TestFeatureGate_UnhealthyPodDetectionPerAgent(t *testing.T) {
+	tests := []struct {
+		name             string
+		agentGates       map[string]bool
+		expectedFailures int32
+	}{
+		{name: "gate enabled counts unready pods", agentGates: map[string]bool{langopv1alpha1.FeatureGateUnhealthyPodDetection: true}, expectedFailures: 1},
+		{name: "gate disabled skips unready pods", agentGates: map[string]bool{langopv1alpha1.FeatureGateUnhealthyPodDetection: false}, expectedFailures: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			agent := newSelfHealingTestAgent()
+			agent.Spec.FeatureGates = tt.agentGates
+			pod := newUnreadyPod("unhealthy-agent-abc", agent, time.Now().Add(-10*time.Minute))
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent, pod).
+				WithStatusSubresource(agent).
+				Build()
+
+			// The operator-wide default is the opposite of the agent's gate, so only the per-agent setting decides
+			reconciler := &LanguageAgentReconciler{
+				Client:             fakeClient,
+				Scheme:             scheme,
+				Log:                logr.Discard(),
+				Recorder:           record.NewFakeRecorder(10),
+				SelfHealingEnabled: true,
+				UnhealthyThreshold: 5 * time.Minute,
+				FeatureGates: map[string]bool{
+					langopv1alpha1.FeatureGateUnhealthyPodDetection: tt.expectedFailures == 0,
+				},
+			}
+
+			ctx := context.Background()
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			if err := reconciler.detectPodFailures(ctx, agent); err != nil {
+				t.Fatalf("detectPodFailures failed: %v", err)
+			}
+
+			if agent.Status.ConsecutiveFailures != tt.expectedFailures {
+				t.Errorf("Expected %d consecutive failures, got %d", tt.expectedFailures, agent.Status.ConsecutiveFailures)
+			}
+		})
+	}
+}
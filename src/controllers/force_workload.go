@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ForceWorkloadAnnotation forces the workload type of an agent: "deployment" or "cronjob".
+//
+// The workload type is decided by, in order of precedence:
+//  1. this annotation, when set to a supported value
+//  2. spec.executionMode (scheduled runs as a CronJob, every other mode as a Deployment)
+//
+// Without the annotation, synthesis rewrites spec.executionMode and spec.schedule to the mode
+// detected in the synthesized code. With it, the detected mode is ignored and the spec is left
+// as written, so e.g. a scheduled agent can run as a Deployment with its own scheduler.
+const ForceWorkloadAnnotation = langopv1alpha1.ForceWorkloadAnnotation
+
+// Workload types selected by ForceWorkloadAnnotation
+const (
+	WorkloadDeployment = "deployment"
+	WorkloadCronJob    = "cronjob"
+)
+
+// forcedWorkload returns the workload type forced by the annotation, or "" if none is forced
+func forcedWorkload(agent *langopv1alpha1.LanguageAgent) string {
+	switch workload := agent.GetAnnotations()[ForceWorkloadAnnotation]; workload {
+	case WorkloadDeployment, WorkloadCronJob:
+		return workload
+	default:
+		return ""
+	}
+}
+
+// agentWorkload returns the workload type to run the agent as, or "" while the execution mode
+// is still unknown
+func agentWorkload(agent *langopv1alpha1.LanguageAgent) string {
+	if workload := forcedWorkload(agent); workload != "" {
+		return workload
+	}
+	switch agent.Spec.ExecutionMode {
+	case "autonomous", "interactive", "event-driven":
+		return WorkloadDeployment
+	case "scheduled":
+		return WorkloadCronJob
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,214 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestAgentWorkload(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       string
+		annotation string
+		expected   string
+	}{
+		{name: "autonomous runs as Deployment", mode: "autonomous", expected: WorkloadDeployment},
+		{name: "scheduled runs as CronJob", mode: "scheduled", expected: WorkloadCronJob},
+		{name: "unknown mode waits", mode: "", expected: ""},
+		{name: "forced Deployment for scheduled agent", mode: "scheduled", annotation: "deployment", expected: WorkloadDeployment},
+		{name: "forced CronJob for autonomous agent", mode: "autonomous", annotation: "cronjob", expected: WorkloadCronJob},
+		{name: "forced workload before mode is known", mode: "", annotation: "cronjob", expected: WorkloadCronJob},
+		{name: "unsupported value is ignored", mode: "scheduled", annotation: "statefulset", expected: WorkloadCronJob},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				Spec: langopv1alpha1.LanguageAgentSpec{ExecutionMode: tt.mode},
+			}
+			if tt.annotation != "" {
+				agent.Annotations = map[string]string{ForceWorkloadAnnotation: tt.annotation}
+			}
+			if workload := agentWorkload(agent); workload != tt.expected {
+				t.Errorf("Expected workload %q, got %q", tt.expected, workload)
+			}
+		})
+	}
+}
+
+func newForceWorkloadReconciler(t *testing.T, objects ...client.Object) (*LanguageAgentReconciler, client.Client) {
+	scheme := testutil.SetupTestScheme(t)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&langopv1alpha1.LanguageAgent{}).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        record.NewFakeRecorder(20),
+		RegistryManager: &mockRegistryManager{},
+	}
+	reconciler.InitializeGatewayCache()
+	return reconciler, fakeClient
+}
+
+func TestLanguageAgentController_ForceWorkloadDeployment(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "forced-deployment",
+			Namespace:   "default",
+			Annotations: map[string]string{ForceWorkloadAnnotation: WorkloadDeployment},
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "scheduled",
+			Schedule:      "*/15 * * * *",
+		},
+	}
+	reconciler, fakeClient := newForceWorkloadReconciler(t, agent)
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, key, &appsv1.Deployment{}); err != nil {
+		t.Errorf("Expected a Deployment for the forced workload, got error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, key, &batchv1.CronJob{}); !errors.IsNotFound(err) {
+		t.Errorf("Expected no CronJob for a scheduled agent forced to a Deployment, got err=%v", err)
+	}
+}
+
+func TestLanguageAgentController_ForceWorkloadIgnoresDetectedMode(t *testing.T) {
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai", ModelName: "gpt-4"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "forced-cronjob",
+			Namespace:   "default",
+			Annotations: map[string]string{ForceWorkloadAnnotation: WorkloadCronJob},
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "scheduled",
+			Schedule:      "*/15 * * * *",
+			Instructions:  "Summarize the news",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+		},
+	}
+
+	// The synthesized code declares autonomous mode, which would normally rewrite the spec
+	reconciler, fakeClient := newForceWorkloadReconciler(t, model, agent)
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateConfigMapName(agent.Name, "code"),
+			Namespace: agent.Namespace,
+			Annotations: map[string]string{
+				"langop.io/instructions-hash": hashString(reconciler.synthesisInstructions(agent)),
+				"langop.io/tools-hash":        hashString(strings.Join(reconciler.getToolNames(agent), ",")),
+				"langop.io/models-hash":       hashString(strings.Join(reconciler.getModelNames(agent), ",")),
+				"langop.io/persona-hash":      hashString(strings.Join(reconciler.getPersonaNames(agent), ",")),
+			},
+		},
+		Data: map[string]string{"agent.rb": "agent \"forced-cronjob\" do\n  mode :autonomous\nend\n"},
+	}
+	ctx := context.Background()
+	if err := fakeClient.Create(ctx, codeConfigMap); err != nil {
+		t.Fatalf("Failed to create code ConfigMap: %v", err)
+	}
+
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if updated.Spec.ExecutionMode != "scheduled" {
+		t.Errorf("Expected executionMode to be left as scheduled, got %q", updated.Spec.ExecutionMode)
+	}
+	if err := fakeClient.Get(ctx, key, &batchv1.CronJob{}); err != nil {
+		t.Errorf("Expected a CronJob for the forced workload, got error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, key, &appsv1.Deployment{}); !errors.IsNotFound(err) {
+		t.Errorf("Expected no Deployment despite the detected autonomous mode, got err=%v", err)
+	}
+}
+
+func TestLanguageAgentController_DetectedModeClearsSchedule(t *testing.T) {
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai", ModelName: "gpt-4"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "detected-autonomous", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "scheduled",
+			Schedule:      "*/15 * * * *",
+			Instructions:  "Watch the news",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+		},
+	}
+
+	// Without a forced workload, the autonomous mode in the code replaces the scheduled spec,
+	// and the schedule goes with it so the webhook accepts the update
+	reconciler, fakeClient := newForceWorkloadReconciler(t, model, agent)
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateConfigMapName(agent.Name, "code"),
+			Namespace: agent.Namespace,
+			Annotations: map[string]string{
+				"langop.io/instructions-hash": hashString(reconciler.synthesisInstructions(agent)),
+				"langop.io/tools-hash":        hashString(strings.Join(reconciler.getToolNames(agent), ",")),
+				"langop.io/models-hash":       hashString(strings.Join(reconciler.getModelNames(agent), ",")),
+				"langop.io/persona-hash":      hashString(strings.Join(reconciler.getPersonaNames(agent), ",")),
+			},
+		},
+		Data: map[string]string{"agent.rb": "agent \"detected-autonomous\" do\n  mode :autonomous\nend\n"},
+	}
+	ctx := context.Background()
+	if err := fakeClient.Create(ctx, codeConfigMap); err != nil {
+		t.Fatalf("Failed to create code ConfigMap: %v", err)
+	}
+
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if updated.Spec.ExecutionMode != "autonomous" {
+		t.Errorf("Expected executionMode autonomous, got %q", updated.Spec.ExecutionMode)
+	}
+	if updated.Spec.Schedule != "" {
+		t.Errorf("Expected schedule to be cleared with the mode change, got %q", updated.Spec.Schedule)
+	}
+}
@@ -0,0 +1,191 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ciliumNetworkPolicyGVK identifies Cilium's namespaced policy, which is managed as unstructured
+// to avoid a dependency on the Cilium API
+var ciliumNetworkPolicyGVK = schema.GroupVersionKind{
+	Group:   "cilium.io",
+	Version: "v2",
+	Kind:    "CiliumNetworkPolicy",
+}
+
+// fqdnEgressCondition reports whether DNS-based egress rules are enforced by name
+const fqdnEgressCondition = "FQDNEgressEnforced"
+
+// hasFQDNEgress reports whether any egress rule allows traffic to DNS names
+func hasFQDNEgress(rules []langopv1alpha1.NetworkRule) bool {
+	for _, rule := range rules {
+		if rule.To != nil && len(rule.To.DNS) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// buildFQDNEgressRules translates DNS-based egress rules into Cilium toFQDNs rules. The first rule
+// sends DNS lookups through Cilium's DNS proxy, which is how Cilium learns the IPs behind each name.
+func buildFQDNEgressRules(rules []langopv1alpha1.NetworkRule) []interface{} {
+	egress := []interface{}{
+		map[string]interface{}{
+			"toEndpoints": []interface{}{
+				map[string]interface{}{
+					"matchLabels": map[string]interface{}{
+						"k8s:io.kubernetes.pod.namespace": "kube-system",
+						"k8s:k8s-app":                     "kube-dns",
+					},
+				},
+			},
+			"toPorts": []interface{}{
+				map[string]interface{}{
+					"ports": []interface{}{
+						map[string]interface{}{"port": "53", "protocol": "ANY"},
+					},
+					"rules": map[string]interface{}{
+						"dns": []interface{}{
+							map[string]interface{}{"matchPattern": "*"},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	for _, rule := range rules {
+		if rule.To == nil || len(rule.To.DNS) == 0 {
+			continue
+		}
+
+		var fqdns []interface{}
+		for _, name := range rule.To.DNS {
+			if strings.Contains(name, "*") {
+				fqdns = append(fqdns, map[string]interface{}{"matchPattern": name})
+			} else {
+				fqdns = append(fqdns, map[string]interface{}{"matchName": name})
+			}
+		}
+		egressRule := map[string]interface{}{"toFQDNs": fqdns}
+
+		var ports []interface{}
+		for _, port := range rule.Ports {
+			protocol := port.Protocol
+			if protocol == "" {
+				protocol = string(corev1.ProtocolTCP)
+			}
+			ports = append(ports, map[string]interface{}{
+				"port":     strconv.Itoa(int(port.Port)),
+				"protocol": protocol,
+			})
+		}
+		if len(ports) > 0 {
+			egressRule["toPorts"] = []interface{}{
+				map[string]interface{}{"ports": ports},
+			}
+		}
+
+		egress = append(egress, egressRule)
+	}
+
+	return egress
+}
+
+// reconcileFQDNEgress enforces the agent's DNS-based egress rules by name. The standard NetworkPolicy
+// can only allow the IPs the names resolved to at reconcile time, so on Cilium a CiliumNetworkPolicy
+// with toFQDNs rules is created alongside it. Other CNIs keep the resolved IPs and get a warning.
+func (r *LanguageAgentReconciler) reconcileFQDNEgress(ctx context.Context, agent *langopv1alpha1.LanguageAgent, cni string) error {
+	log := log.FromContext(ctx)
+
+	egress, err := r.resolveEgress(ctx, agent)
+	if err != nil {
+		return err
+	}
+
+	if !hasFQDNEgress(egress) {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, fqdnEgressCondition)
+		if cni == "cilium" {
+			return r.deleteCiliumNetworkPolicy(ctx, agent)
+		}
+		return nil
+	}
+
+	if cni != "cilium" {
+		message := fmt.Sprintf("CNI '%s' cannot enforce egress by DNS name; DNS rules are resolved to IP addresses at reconcile time instead", cni)
+		if SetCondition(&agent.Status.Conditions, fqdnEgressCondition, metav1.ConditionFalse, "FQDNEgressUnsupported", message, agent.Generation) && r.Recorder != nil {
+			r.Recorder.Event(agent, corev1.EventTypeWarning, "FQDNEgressUnsupported", message)
+		}
+		return nil
+	}
+
+	labels := GetCommonLabels(agent.Name, "LanguageAgent")
+	matchLabels := make(map[string]interface{}, len(labels))
+	for k, v := range labels {
+		matchLabels[k] = v
+	}
+	spec := map[string]interface{}{
+		"endpointSelector": map[string]interface{}{"matchLabels": matchLabels},
+		"egress":           buildFQDNEgressRules(egress),
+	}
+
+	existing := &unstructured.Unstructured{}
+	existing.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+	err = r.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, existing)
+	if err != nil {
+		if !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to get CiliumNetworkPolicy: %w", err)
+		}
+		policy := &unstructured.Unstructured{}
+		policy.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+		policy.SetName(agent.Name)
+		policy.SetNamespace(agent.Namespace)
+		policy.SetLabels(labels)
+		policy.Object["spec"] = spec
+		if err := controllerutil.SetControllerReference(agent, policy, r.Scheme); err != nil {
+			return err
+		}
+		log.Info("Creating CiliumNetworkPolicy for DNS egress", "name", agent.Name, "namespace", agent.Namespace)
+		if err := r.Create(ctx, policy); err != nil {
+			return fmt.Errorf("failed to create CiliumNetworkPolicy: %w", err)
+		}
+	} else if !equality.Semantic.DeepEqual(existing.Object["spec"], spec) || !equality.Semantic.DeepEqual(existing.GetLabels(), labels) {
+		existing.Object["spec"] = spec
+		existing.SetLabels(labels)
+		log.Info("Updating CiliumNetworkPolicy for DNS egress", "name", agent.Name, "namespace", agent.Namespace)
+		if err := r.Update(ctx, existing); err != nil {
+			return fmt.Errorf("failed to update CiliumNetworkPolicy: %w", err)
+		}
+	}
+
+	SetCondition(&agent.Status.Conditions, fqdnEgressCondition, metav1.ConditionTrue, "Enforced",
+		"DNS egress rules enforced by name through a CiliumNetworkPolicy", agent.Generation)
+	return nil
+}
+
+// deleteCiliumNetworkPolicy removes the agent's CiliumNetworkPolicy once it has no DNS egress rules
+func (r *LanguageAgentReconciler) deleteCiliumNetworkPolicy(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+	policy.SetName(agent.Name)
+	policy.SetNamespace(agent.Namespace)
+	if err := r.Delete(ctx, policy); err != nil && !errors.IsNotFound(err) && !meta.IsNoMatchError(err) {
+		return fmt.Errorf("failed to delete CiliumNetworkPolicy: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestLanguageAgentController_FQDNEgress(t *testing.T) {
+	tests := []struct {
+		name         string
+		cniDaemonSet string
+		egress       []langopv1alpha1.NetworkRule
+		expectPolicy bool
+		expectStatus metav1.ConditionStatus
+		expectReason string
+	}{
+		{
+			name:         "cilium enforces DNS rules by name",
+			cniDaemonSet: "cilium",
+			egress: []langopv1alpha1.NetworkRule{{
+				To:    &langopv1alpha1.NetworkPeer{DNS: []string{"api.openai.com", "*.googleapis.com"}},
+				Ports: []langopv1alpha1.NetworkPort{{Port: 443}},
+			}},
+			expectPolicy: true,
+			expectStatus: metav1.ConditionTrue,
+			expectReason: "Enforced",
+		},
+		{
+			name:         "calico falls back to resolved IPs",
+			cniDaemonSet: "calico-node",
+			egress: []langopv1alpha1.NetworkRule{{
+				To: &langopv1alpha1.NetworkPeer{DNS: []string{"api.openai.com"}},
+			}},
+			expectStatus: metav1.ConditionFalse,
+			expectReason: "FQDNEgressUnsupported",
+		},
+		{
+			name: "unknown CNI falls back to resolved IPs",
+			egress: []langopv1alpha1.NetworkRule{{
+				To: &langopv1alpha1.NetworkPeer{DNS: []string{"api.openai.com"}},
+			}},
+			expectStatus: metav1.ConditionFalse,
+			expectReason: "FQDNEgressUnsupported",
+		},
+		{
+			name:         "cilium without DNS rules",
+			cniDaemonSet: "cilium",
+			egress: []langopv1alpha1.NetworkRule{{
+				To: &langopv1alpha1.NetworkPeer{CIDR: "10.0.0.0/8"},
+			}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "egress-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:  "ghcr.io/language-operator/agent:latest",
+					Egress: tt.egress,
+				},
+			}
+			objects := []client.Object{agent}
+			if tt.cniDaemonSet != "" {
+				objects = append(objects, &appsv1.DaemonSet{
+					ObjectMeta: metav1.ObjectMeta{Name: tt.cniDaemonSet, Namespace: "kube-system"},
+				})
+			}
+			reconciler, fakeClient := newForceWorkloadReconciler(t, objects...)
+
+			ctx := context.Background()
+			_, cni := reconciler.detectNetworkPolicySupport(ctx)
+			if err := reconciler.reconcileFQDNEgress(ctx, agent, cni); err != nil {
+				t.Fatalf("reconcileFQDNEgress failed: %v", err)
+			}
+
+			policy := &unstructured.Unstructured{}
+			policy.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+			err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, policy)
+			if !tt.expectPolicy {
+				if !errors.IsNotFound(err) {
+					t.Errorf("Expected no CiliumNetworkPolicy, got err=%v", err)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("Expected a CiliumNetworkPolicy, got error: %v", err)
+				}
+				egress, _, _ := unstructured.NestedSlice(policy.Object, "spec", "egress")
+				// DNS proxy rule plus one rule per DNS egress rule
+				if len(egress) != 2 {
+					t.Fatalf("Expected 2 egress rules, got %d", len(egress))
+				}
+				fqdns, _, _ := unstructured.NestedSlice(egress[1].(map[string]interface{}), "toFQDNs")
+				if len(fqdns) != 2 {
+					t.Fatalf("Expected 2 toFQDNs entries, got %d", len(fqdns))
+				}
+				if name := fqdns[0].(map[string]interface{})["matchName"]; name != "api.openai.com" {
+					t.Errorf("Expected matchName api.openai.com, got %v", name)
+				}
+				if pattern := fqdns[1].(map[string]interface{})["matchPattern"]; pattern != "*.googleapis.com" {
+					t.Errorf("Expected matchPattern *.googleapis.com, got %v", pattern)
+				}
+				if refs := policy.GetOwnerReferences(); len(refs) != 1 || refs[0].Name != agent.Name {
+					t.Errorf("Expected the policy to be owned by the agent, got %v", refs)
+				}
+
+				// An unchanged reconcile doesn't write the policy again
+				if err := reconciler.reconcileFQDNEgress(ctx, agent, cni); err != nil {
+					t.Fatalf("reconcileFQDNEgress failed: %v", err)
+				}
+				unchanged := &unstructured.Unstructured{}
+				unchanged.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+				if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, unchanged); err != nil {
+					t.Fatalf("Failed to get CiliumNetworkPolicy: %v", err)
+				}
+				if unchanged.GetResourceVersion() != policy.GetResourceVersion() {
+					t.Errorf("Expected no update without changes, resource version went from %s to %s", policy.GetResourceVersion(), unchanged.GetResourceVersion())
+				}
+			}
+
+			condition := meta.FindStatusCondition(agent.Status.Conditions, fqdnEgressCondition)
+			if tt.expectReason == "" {
+				if condition != nil {
+					t.Errorf("Expected no %s condition, got %+v", fqdnEgressCondition, condition)
+				}
+				return
+			}
+			if condition == nil {
+				t.Fatalf("Expected a %s condition", fqdnEgressCondition)
+			}
+			if condition.Status != tt.expectStatus || condition.Reason != tt.expectReason {
+				t.Errorf("Expected %s/%s, got %s/%s", tt.expectStatus, tt.expectReason, condition.Status, condition.Reason)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_FQDNEgressRemoved(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "egress-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image: "ghcr.io/language-operator/agent:latest",
+			Egress: []langopv1alpha1.NetworkRule{{
+				To: &langopv1alpha1.NetworkPeer{DNS: []string{"api.openai.com"}},
+			}},
+		},
+	}
+	cilium := &appsv1.DaemonSet{ObjectMeta: metav1.ObjectMeta{Name: "cilium", Namespace: "kube-system"}}
+	reconciler, fakeClient := newForceWorkloadReconciler(t, agent, cilium)
+
+	ctx := context.Background()
+	if err := reconciler.reconcileFQDNEgress(ctx, agent, "cilium"); err != nil {
+		t.Fatalf("reconcileFQDNEgress failed: %v", err)
+	}
+
+	agent.Spec.Egress = nil
+	if err := reconciler.reconcileFQDNEgress(ctx, agent, "cilium"); err != nil {
+		t.Fatalf("reconcileFQDNEgress failed: %v", err)
+	}
+
+	policy := &unstructured.Unstructured{}
+	policy.SetGroupVersionKind(ciliumNetworkPolicyGVK)
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, policy); !errors.IsNotFound(err) {
+		t.Errorf("Expected the CiliumNetworkPolicy to be deleted, got err=%v", err)
+	}
+	if condition := meta.FindStatusCondition(agent.Status.Conditions, fqdnEgressCondition); condition != nil {
+		t.Errorf("Expected no %s condition, got %+v", fqdnEgressCondition, condition)
+	}
+}
@@ -1,192 +1,194 @@
 package controllers
 
 import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
-
-	"github.com/go-logr/logr"
-	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/client-go/tools/record"
-	"sigs.k8s.io/controller-runtime/pkg/client/fake"
-
-	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
 )
 
-func TestGatewayAPICaching(t *testing.T) {
-	// Create a basic reconciler with cache
-	scheme := runtime.NewScheme()
-	_ = langopv1alpha1.AddToScheme(scheme)
+// detectedGatewayAPI returns a detector that has already discovered available
+func detectedGatewayAPI(available bool) *GatewayAPIDetector {
+	return &GatewayAPIDetector{
+		ttl:       gatewayAPICacheTTL,
+		available: available,
+		lastCheck: time.Now(),
+		discover: func(context.Context) (bool, error) {
+			return available, nil
+		},
+	}
+}
 
-	fakeClient := fake.NewClientBuilder().
-		WithScheme(scheme).
-		Build()
+// countingDetector returns a detector whose discovery reports available after a short delay,
+// and the number of discoveries run
+func countingDetector(available bool, err error) (*GatewayAPIDetector, *int32) {
+	var discoveries int32
+	return &GatewayAPIDetector{
+		ttl: gatewayAPICacheTTL,
+		discover: func(context.Context) (bool, error) {
+			atomic.AddInt32(&discoveries, 1)
+			time.Sleep(10 * time.Millisecond)
+			return available, err
+		},
+	}, &discoveries
+}
 
-	reconciler := &LanguageAgentReconciler{
-		Client:   fakeClient,
-		Scheme:   scheme,
-		Log:      logr.Discard(),
-		Recorder: &record.FakeRecorder{},
+func TestGatewayAPIDetector_ConcurrentCallersShareOneDiscovery(t *testing.T) {
+	detector, discoveries := countingDetector(true, nil)
+
+	const callers = 20
+	var wg sync.WaitGroup
+	results := make(chan bool, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			available, err := detector.Available(context.Background())
+			if err != nil {
+				t.Errorf("Available failed: %v", err)
+			}
+			results <- available
+		}()
 	}
-	reconciler.InitializeGatewayCache()
-
-	t.Run("Cache Structure and TTL Constants", func(t *testing.T) {
-		// Verify cache is initialized properly
-		if reconciler.gatewayCache == nil {
-			t.Fatalf("Cache should be initialized")
-		}
+	wg.Wait()
+	close(results)
 
-		// Verify TTL constant
-		if gatewayAPICacheTTL != 5*time.Minute {
-			t.Errorf("Expected cache TTL to be 5 minutes, got %v", gatewayAPICacheTTL)
+	for available := range results {
+		if !available {
+			t.Error("Expected every caller to see the discovered result")
 		}
+	}
+	if got := atomic.LoadInt32(discoveries); got != 1 {
+		t.Errorf("Expected 1 discovery for concurrent callers, got %d", got)
+	}
 
-		// Verify initial state
-		if !reconciler.gatewayCache.lastCheck.IsZero() {
-			t.Errorf("Initial lastCheck should be zero")
-		}
-	})
-
-	t.Run("Cache TTL Logic", func(t *testing.T) {
-		// Simulate fresh cache (recently checked)
-		reconciler.gatewayCache.mutex.Lock()
-		reconciler.gatewayCache.available = true
-		reconciler.gatewayCache.lastCheck = time.Now()
-		reconciler.gatewayCache.mutex.Unlock()
-
-		// Check if cache is considered fresh
-		reconciler.gatewayCache.mutex.RLock()
-		isFresh := time.Since(reconciler.gatewayCache.lastCheck) < gatewayAPICacheTTL
-		reconciler.gatewayCache.mutex.RUnlock()
-
-		if !isFresh {
-			t.Errorf("Recently set cache should be considered fresh")
-		}
+	// Within the TTL the cached result is reused
+	if _, err := detector.Available(context.Background()); err != nil {
+		t.Fatalf("Available failed: %v", err)
+	}
+	if got := atomic.LoadInt32(discoveries); got != 1 {
+		t.Errorf("Expected no discovery within the TTL, got %d discoveries", got)
+	}
 
-		// Simulate stale cache
-		reconciler.gatewayCache.mutex.Lock()
-		reconciler.gatewayCache.lastCheck = time.Now().Add(-10 * time.Minute)
-		reconciler.gatewayCache.mutex.Unlock()
+	// Once the result is older than the TTL the next caller refreshes it
+	detector.mutex.Lock()
+	detector.lastCheck = time.Now().Add(-gatewayAPICacheTTL - time.Second)
+	detector.mutex.Unlock()
+	if _, err := detector.Available(context.Background()); err != nil {
+		t.Fatalf("Available failed: %v", err)
+	}
+	if got := atomic.LoadInt32(discoveries); got != 2 {
+		t.Errorf("Expected a stale result to be refreshed once, got %d discoveries", got)
+	}
+}
 
-		// Check if cache is considered stale
-		reconciler.gatewayCache.mutex.RLock()
-		isStale := time.Since(reconciler.gatewayCache.lastCheck) >= gatewayAPICacheTTL
-		reconciler.gatewayCache.mutex.RUnlock()
+func TestGatewayAPIDetector_DiscoveryErrors(t *testing.T) {
+	detector, _ := countingDetector(false, errors.New("discovery unavailable"))
+	if _, err := detector.Available(context.Background()); err == nil {
+		t.Error("Expected an error when nothing was ever discovered")
+	}
 
-		if !isStale {
-			t.Errorf("Old cache should be considered stale")
-		}
-	})
-
-	t.Run("Concurrent Cache Access Safety", func(t *testing.T) {
-		// Test that multiple goroutines can safely read/write cache
-		const numGoroutines = 10
-		done := make(chan bool, numGoroutines)
-
-		// Launch multiple goroutines that manipulate cache
-		for i := 0; i < numGoroutines; i++ {
-			go func(index int) {
-				// Simulate cache read
-				reconciler.gatewayCache.mutex.RLock()
-				_ = reconciler.gatewayCache.available
-				_ = reconciler.gatewayCache.lastCheck
-				reconciler.gatewayCache.mutex.RUnlock()
-
-				// Simulate cache write
-				reconciler.gatewayCache.mutex.Lock()
-				reconciler.gatewayCache.available = (index%2 == 0)
-				reconciler.gatewayCache.lastCheck = time.Now()
-				reconciler.gatewayCache.mutex.Unlock()
-
-				done <- true
-			}(i)
-		}
+	// A failed refresh keeps serving the last known result
+	detector.available = true
+	detector.lastCheck = time.Now().Add(-gatewayAPICacheTTL - time.Second)
+	available, err := detector.Available(context.Background())
+	if err != nil || !available {
+		t.Errorf("Expected the stale result to be served, got %v, %v", available, err)
+	}
+}
 
-		// Wait for all goroutines to complete
-		for i := 0; i < numGoroutines; i++ {
-			select {
-			case <-done:
-				// Success
-			case <-time.After(5 * time.Second):
-				t.Fatalf("Goroutine %d didn't complete in time", i)
-			}
-		}
+func TestGatewayAPIDetector_RefreshGoroutine(t *testing.T) {
+	detector, discoveries := countingDetector(true, nil)
+	detector.ttl = 50 * time.Millisecond
 
-		// Verify final state is consistent (no race conditions)
-		reconciler.gatewayCache.mutex.RLock()
-		available := reconciler.gatewayCache.available
-		lastCheck := reconciler.gatewayCache.lastCheck
-		reconciler.gatewayCache.mutex.RUnlock()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- detector.Start(ctx) }()
 
-		// Check that values are reasonable (not corrupted by races)
-		if lastCheck.IsZero() || lastCheck.After(time.Now().Add(time.Second)) {
-			t.Errorf("lastCheck timestamp appears corrupted: %v", lastCheck)
+	// Callers never discover while the refresh goroutine keeps the result current
+	deadline := time.Now().Add(time.Second)
+	for {
+		if _, checkedAt := detector.LastResult(); !checkedAt.IsZero() {
+			break
 		}
-		// available is either true or false, both are valid
-		_ = available
-	})
-}
-
-func TestInitializeGatewayCache(t *testing.T) {
-	reconciler := &LanguageAgentReconciler{}
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the refresh goroutine to discover the Gateway API")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	time.Sleep(120 * time.Millisecond)
+	for i := 0; i < 10; i++ {
+		if available, err := detector.Available(ctx); err != nil || !available {
+			t.Errorf("Expected the refreshed result, got %v, %v", available, err)
+		}
+	}
 
-	// Before initialization, cache should be nil
-	if reconciler.gatewayCache != nil {
-		t.Errorf("Cache should be nil before initialization")
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start returned %v", err)
+	}
+	// One discovery at start and one per elapsed TTL, none from callers
+	if got := atomic.LoadInt32(discoveries); got < 2 || got > 5 {
+		t.Errorf("Expected discovery once per TTL, got %d discoveries", got)
 	}
+}
 
-	// After initialization, cache should be ready
-	reconciler.InitializeGatewayCache()
-	if reconciler.gatewayCache == nil {
-		t.Errorf("Cache should not be nil after initialization")
+func TestGatewayAPIDetector_RefreshDoesNotBlockCallers(t *testing.T) {
+	detector := detectedGatewayAPI(true)
+	started := make(chan struct{})
+	release := make(chan struct{})
+	detector.discover = func(context.Context) (bool, error) {
+		close(started)
+		<-release
+		return true, nil
 	}
 
-	// lastCheck should be zero initially
-	if !reconciler.gatewayCache.lastCheck.IsZero() {
-		t.Errorf("Initial lastCheck should be zero")
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- detector.Start(ctx) }()
+	<-started
+
+	// Callers read the cached result while the refresh goroutine is discovering
+	read := make(chan bool)
+	go func() {
+		available, _ := detector.Available(ctx)
+		read <- available
+	}()
+	select {
+	case available := <-read:
+		if !available {
+			t.Error("Expected the cached result")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected Available not to wait on the refresh goroutine's discovery")
 	}
-}
 
-func TestGatewayCacheTTL(t *testing.T) {
-	if gatewayAPICacheTTL != 5*time.Minute {
-		t.Errorf("Expected cache TTL to be 5 minutes, got %v", gatewayAPICacheTTL)
+	close(release)
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start returned %v", err)
 	}
 }
 
-// TestGatewayAPICache_Integration tests the integration between cache and discovery
-// but in a way that doesn't require real Kubernetes API access
-func TestGatewayAPICache_Integration(t *testing.T) {
+func TestInitializeGatewayCache(t *testing.T) {
 	reconciler := &LanguageAgentReconciler{}
 	reconciler.InitializeGatewayCache()
-
-	// Test that cache correctly determines freshness
-	now := time.Now()
-
-	// Test fresh cache
-	reconciler.gatewayCache.mutex.Lock()
-	reconciler.gatewayCache.lastCheck = now
-	reconciler.gatewayCache.available = true
-	reconciler.gatewayCache.mutex.Unlock()
-
-	reconciler.gatewayCache.mutex.RLock()
-	timeSince := time.Since(reconciler.gatewayCache.lastCheck)
-	isFresh := timeSince < gatewayAPICacheTTL
-	reconciler.gatewayCache.mutex.RUnlock()
-
-	if !isFresh {
-		t.Errorf("Cache should be fresh, time since: %v, TTL: %v", timeSince, gatewayAPICacheTTL)
+	if reconciler.GatewayAPI != SharedGatewayAPIDetector() {
+		t.Error("Expected reconcilers to share the process-wide detector")
 	}
 
-	// Test stale cache
-	reconciler.gatewayCache.mutex.Lock()
-	reconciler.gatewayCache.lastCheck = now.Add(-6 * time.Minute) // Older than 5min TTL
-	reconciler.gatewayCache.mutex.Unlock()
-
-	reconciler.gatewayCache.mutex.RLock()
-	timeSince = time.Since(reconciler.gatewayCache.lastCheck)
-	isStale := timeSince >= gatewayAPICacheTTL
-	reconciler.gatewayCache.mutex.RUnlock()
+	other := &LanguageAgentReconciler{}
+	other.InitializeGatewayCache()
+	if other.GatewayAPI != reconciler.GatewayAPI {
+		t.Error("Expected every reconciler to use the same detector")
+	}
 
-	if !isStale {
-		t.Errorf("Cache should be stale, time since: %v, TTL: %v", timeSince, gatewayAPICacheTTL)
+	injected := detectedGatewayAPI(true)
+	reconciler = &LanguageAgentReconciler{GatewayAPI: injected}
+	reconciler.InitializeGatewayCache()
+	if reconciler.GatewayAPI != injected {
+		t.Error("Expected an injected detector to be kept")
 	}
 }
@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// gatewayAPICacheTTL is how long a Gateway API detection result is used before it is refreshed
+const gatewayAPICacheTTL = 5 * time.Minute
+
+// GatewayAPIDetector reports whether the Gateway API is installed in the cluster. One detector is
+// shared by every controller that needs to know, so discovery runs at most once per TTL however
+// many reconcilers ask. Added to the manager, a single goroutine refreshes the result every TTL
+// and callers never wait on discovery after the first.
+type GatewayAPIDetector struct {
+	discover func(ctx context.Context) (bool, error)
+	ttl      time.Duration
+
+	mutex      sync.RWMutex
+	available  bool
+	lastCheck  time.Time
+	refreshing bool
+}
+
+// NewGatewayAPIDetector creates a detector discovering the Gateway API through cfg, or through
+// the default kubeconfig when cfg is nil
+func NewGatewayAPIDetector(cfg *rest.Config) *GatewayAPIDetector {
+	return &GatewayAPIDetector{
+		ttl: gatewayAPICacheTTL,
+		discover: func(context.Context) (bool, error) {
+			return discoverGatewayAPI(cfg)
+		},
+	}
+}
+
+var (
+	sharedGatewayAPIDetector     *GatewayAPIDetector
+	sharedGatewayAPIDetectorOnce sync.Once
+)
+
+// SharedGatewayAPIDetector returns the process-wide detector used by controllers that aren't
+// given one
+func SharedGatewayAPIDetector() *GatewayAPIDetector {
+	sharedGatewayAPIDetectorOnce.Do(func() {
+		sharedGatewayAPIDetector = NewGatewayAPIDetector(nil)
+	})
+	return sharedGatewayAPIDetector
+}
+
+// Available reports whether the Gateway API is installed. The cached result is used while it is
+// fresh, or for as long as the refresh goroutine keeps it current; otherwise concurrent callers
+// wait on a single discovery. A failed discovery keeps returning the last known result.
+func (d *GatewayAPIDetector) Available(ctx context.Context) (bool, error) {
+	d.mutex.RLock()
+	if d.fresh() {
+		available := d.available
+		d.mutex.RUnlock()
+		return available, nil
+	}
+	d.mutex.RUnlock()
+
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	// Another caller may have refreshed while we waited for the lock
+	if d.fresh() {
+		return d.available, nil
+	}
+	return d.refreshLocked(ctx)
+}
+
+// LastResult returns the cached detection result and when it was discovered, zero if never
+func (d *GatewayAPIDetector) LastResult() (bool, time.Time) {
+	d.mutex.RLock()
+	defer d.mutex.RUnlock()
+	return d.available, d.lastCheck
+}
+
+// Start implements manager.Runnable, refreshing the detection result every TTL until ctx is done
+func (d *GatewayAPIDetector) Start(ctx context.Context) error {
+	d.mutex.Lock()
+	d.refreshing = true
+	d.mutex.Unlock()
+	defer func() {
+		d.mutex.Lock()
+		d.refreshing = false
+		d.mutex.Unlock()
+	}()
+
+	ticker := time.NewTicker(d.ttl)
+	defer ticker.Stop()
+
+	for {
+		// Discover without the lock so callers keep reading the cached result meanwhile
+		available, err := d.discover(ctx)
+		d.mutex.Lock()
+		_, err = d.recordLocked(available, err)
+		d.mutex.Unlock()
+		if err != nil {
+			log.FromContext(ctx).Error(err, "Failed to discover the Gateway API")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// fresh reports whether the cached result may be used. Callers must hold the mutex.
+func (d *GatewayAPIDetector) fresh() bool {
+	if d.lastCheck.IsZero() {
+		return false
+	}
+	return d.refreshing || time.Since(d.lastCheck) < d.ttl
+}
+
+// refreshLocked runs discovery and caches its result. Callers must hold the write lock.
+func (d *GatewayAPIDetector) refreshLocked(ctx context.Context) (bool, error) {
+	return d.recordLocked(d.discover(ctx))
+}
+
+// recordLocked caches a discovery result. Callers must hold the write lock.
+func (d *GatewayAPIDetector) recordLocked(available bool, err error) (bool, error) {
+	if err != nil {
+		// Keep serving the stale result rather than failing callers
+		if !d.lastCheck.IsZero() {
+			return d.available, nil
+		}
+		return false, err
+	}
+	d.available = available
+	d.lastCheck = time.Now()
+	return available, nil
+}
+
+// discoverGatewayAPI checks whether the HTTPRoute resource of the Gateway API is served
+func discoverGatewayAPI(cfg *rest.Config) (bool, error) {
+	if cfg == nil {
+		var err error
+		if cfg, err = ctrl.GetConfig(); err != nil {
+			return false, err
+		}
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return false, err
+	}
+
+	// Check if HTTPRoute CRD exists (gateway.networking.k8s.io/v1)
+	gvr := schema.GroupVersionResource{
+		Group:    "gateway.networking.k8s.io",
+		Version:  "v1",
+		Resource: "httproutes",
+	}
+
+	_, apiResourcesList, err := discoveryClient.ServerGroupsAndResources()
+	if err != nil {
+		// Partial errors are acceptable - some API groups might be unavailable
+		if !discovery.IsGroupDiscoveryFailedError(err) {
+			return false, err
+		}
+	}
+
+	for _, apiResources := range apiResourcesList {
+		if apiResources.GroupVersion == gvr.Group+"/"+gvr.Version {
+			for _, resource := range apiResources.APIResources {
+				if resource.Name == gvr.Resource {
+					return true, nil
+				}
+			}
+		}
+	}
+
+	return false, nil
+}
@@ -0,0 +1,58 @@
+package controllers
+
+import (
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// defaultTerminationGracePeriodSeconds is the Kubernetes default time a pod gets to exit after SIGTERM
+const defaultTerminationGracePeriodSeconds int64 = 30
+
+// agentPreStopDelay returns the number of seconds a terminating agent keeps serving webhook traffic
+func agentPreStopDelay(agent *langopv1alpha1.LanguageAgent) int32 {
+	if agent.Spec.Lifecycle != nil && agent.Spec.Lifecycle.PreStopDelaySeconds != nil {
+		return *agent.Spec.Lifecycle.PreStopDelaySeconds
+	}
+	return langopv1alpha1.DefaultAgentPreStopDelaySeconds
+}
+
+// applyAgentWebhookDraining configures a Deployment-based agent pod to stop receiving webhook traffic
+// before it exits. Interactive agents, which serve webhook requests, get a readiness probe that keeps
+// traffic off pods whose webhook server isn't listening. The runtime has no dedicated health endpoint,
+// so the probe checks the port rather than an HTTP path. A preStop sleep holds SIGTERM until the
+// endpoints controller has removed the pod from the Service, and the grace period is extended by the
+// delay so the agent still gets the default time to shut down.
+func applyAgentWebhookDraining(agent *langopv1alpha1.LanguageAgent, podSpec *corev1.PodSpec) {
+	container := &podSpec.Containers[0]
+	if agent.Spec.ExecutionMode == "interactive" {
+		container.ReadinessProbe = &corev1.Probe{
+			ProbeHandler: corev1.ProbeHandler{
+				TCPSocket: &corev1.TCPSocketAction{
+					Port: intstr.FromInt(agentWebhookPort),
+				},
+			},
+			PeriodSeconds:    5,
+			TimeoutSeconds:   1,
+			SuccessThreshold: 1,
+			FailureThreshold: 3,
+		}
+	}
+
+	delay := agentPreStopDelay(agent)
+	if delay <= 0 {
+		return
+	}
+	container.Lifecycle = &corev1.Lifecycle{
+		PreStop: &corev1.LifecycleHandler{
+			Exec: &corev1.ExecAction{
+				Command: []string{"sleep", strconv.Itoa(int(delay))},
+			},
+		},
+	}
+	gracePeriod := defaultTerminationGracePeriodSeconds + int64(delay)
+	podSpec.TerminationGracePeriodSeconds = &gracePeriod
+}
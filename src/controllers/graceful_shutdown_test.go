@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestLanguageAgentController_WebhookDraining(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+	tests := []struct {
+		name              string
+		lifecycle         *langopv1alpha1.AgentLifecycleSpec
+		expectPreStop     []string
+		expectGracePeriod *int64
+	}{
+		{name: "default delay", expectPreStop: []string{"sleep", "10"}, expectGracePeriod: &[]int64{40}[0]},
+		{name: "custom delay", lifecycle: &langopv1alpha1.AgentLifecycleSpec{PreStopDelaySeconds: int32Ptr(25)},
+			expectPreStop: []string{"sleep", "25"}, expectGracePeriod: &[]int64{55}[0]},
+		{name: "delay disabled", lifecycle: &langopv1alpha1.AgentLifecycleSpec{PreStopDelaySeconds: int32Ptr(0)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "interactive-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:         "ghcr.io/language-operator/agent:latest",
+					ExecutionMode: "interactive",
+					Lifecycle:     tt.lifecycle,
+				},
+			}
+			reconciler, fakeClient := newForceWorkloadReconciler(t, agent)
+
+			ctx := context.Background()
+			key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			deployment := &appsv1.Deployment{}
+			if err := fakeClient.Get(ctx, key, deployment); err != nil {
+				t.Fatalf("Failed to get Deployment: %v", err)
+			}
+			podSpec := deployment.Spec.Template.Spec
+			container := podSpec.Containers[0]
+
+			probe := container.ReadinessProbe
+			if probe == nil || probe.TCPSocket == nil {
+				t.Fatalf("Expected a TCP readiness probe, got %+v", probe)
+			}
+			if probe.TCPSocket.Port.IntValue() != 8080 {
+				t.Errorf("Expected readiness probe on port 8080, got %s", probe.TCPSocket.Port.String())
+			}
+
+			if tt.expectPreStop == nil {
+				if container.Lifecycle != nil {
+					t.Errorf("Expected no lifecycle hooks, got %+v", container.Lifecycle)
+				}
+				if podSpec.TerminationGracePeriodSeconds != nil {
+					t.Errorf("Expected the default termination grace period, got %d", *podSpec.TerminationGracePeriodSeconds)
+				}
+				return
+			}
+			if container.Lifecycle == nil || container.Lifecycle.PreStop == nil || container.Lifecycle.PreStop.Exec == nil {
+				t.Fatalf("Expected a preStop exec hook, got %+v", container.Lifecycle)
+			}
+			if got := container.Lifecycle.PreStop.Exec.Command; len(got) != len(tt.expectPreStop) || got[0] != tt.expectPreStop[0] || got[1] != tt.expectPreStop[1] {
+				t.Errorf("Expected preStop command %v, got %v", tt.expectPreStop, got)
+			}
+			if podSpec.TerminationGracePeriodSeconds == nil || *podSpec.TerminationGracePeriodSeconds != *tt.expectGracePeriod {
+				t.Errorf("Expected termination grace period %d, got %v", *tt.expectGracePeriod, podSpec.TerminationGracePeriodSeconds)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_NoReadinessProbeForAutonomousAgents(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "autonomous-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+		},
+	}
+	reconciler, fakeClient := newForceWorkloadReconciler(t, agent)
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("Failed to get Deployment: %v", err)
+	}
+	container := deployment.Spec.Template.Spec.Containers[0]
+	if container.ReadinessProbe != nil {
+		t.Errorf("Expected no readiness probe for an autonomous agent, got %+v", container.ReadinessProbe)
+	}
+	if container.Lifecycle == nil || container.Lifecycle.PreStop == nil {
+		t.Errorf("Expected the preStop delay to still apply, got %+v", container.Lifecycle)
+	}
+}
+
+func TestLanguageAgentController_NoWebhookDrainingForScheduledAgents(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "scheduled-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "scheduled",
+			Schedule:      "*/15 * * * *",
+		},
+	}
+	reconciler, fakeClient := newForceWorkloadReconciler(t, agent)
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	cronJob := &batchv1.CronJob{}
+	if err := fakeClient.Get(ctx, key, cronJob); err != nil {
+		t.Fatalf("Failed to get CronJob: %v", err)
+	}
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	if podSpec.Containers[0].ReadinessProbe != nil {
+		t.Errorf("Expected no readiness probe for a scheduled agent, got %+v", podSpec.Containers[0].ReadinessProbe)
+	}
+	if podSpec.Containers[0].Lifecycle != nil {
+		t.Errorf("Expected no lifecycle hooks for a scheduled agent, got %+v", podSpec.Containers[0].Lifecycle)
+	}
+	if podSpec.TerminationGracePeriodSeconds != nil {
+		t.Errorf("Expected the default termination grace period for a scheduled agent, got %d", *podSpec.TerminationGracePeriodSeconds)
+	}
+}
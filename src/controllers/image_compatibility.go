@@ -0,0 +1,467 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ImageCompatibleCondition reports whether the agent image is a langop agent runtime built for
+// the architecture of the nodes it runs on. Only set with --verify-agent-image.
+const ImageCompatibleCondition = "ImageCompatible"
+
+// AgentRuntimeLabel is the image label that marks a langop agent runtime image
+const AgentRuntimeLabel = "langop.io/agent-runtime"
+
+const (
+	// imageInspectionTTL is how long an inspected image is trusted before its tag is checked again
+	imageInspectionTTL = 10 * time.Minute
+	// imageInspectionRetryInterval is how long a failed inspection is remembered before retrying
+	imageInspectionRetryInterval = time.Minute
+	// imageInspectionTimeout bounds the registry requests of a single inspection
+	imageInspectionTimeout = 15 * time.Second
+	// maxRegistryResponseSize caps manifests, image configs, and token responses
+	maxRegistryResponseSize = 4 << 20
+)
+
+// supportedAgentPlatforms are the platforms the agent runtime is built for
+var supportedAgentPlatforms = []string{"linux/amd64", "linux/arm64"}
+
+// manifestAcceptTypes are the manifest and index media types the inspector understands
+var manifestAcceptTypes = []string{
+	"application/vnd.oci.image.index.v1+json",
+	"application/vnd.docker.distribution.manifest.list.v2+json",
+	"application/vnd.oci.image.manifest.v1+json",
+	"application/vnd.docker.distribution.manifest.v2+json",
+}
+
+// ImageInfo is what an image's registry manifest and config tell about it
+type ImageInfo struct {
+	// Labels are the image config labels
+	Labels map[string]string
+	// Platforms are the os/arch pairs the image is built for
+	Platforms []string
+}
+
+// ImageInspector reads an image's labels and platforms without pulling it
+type ImageInspector interface {
+	Inspect(ctx context.Context, image string) (*ImageInfo, error)
+}
+
+// RegistryImageInspector reads image manifests and configs through the registry HTTP API. Only
+// anonymous pulls are supported, using the registry's token service when it asks for one.
+type RegistryImageInspector struct {
+	Client *http.Client
+}
+
+// imageReference is an image split into what the registry API addresses
+type imageReference struct {
+	registry   string
+	repository string
+	reference  string
+}
+
+// parseImageReference splits an image into registry host, repository, and tag or digest,
+// applying the Docker Hub defaults for images without a registry
+func parseImageReference(image string) (imageReference, error) {
+	if image == "" {
+		return imageReference{}, fmt.Errorf("image reference cannot be empty")
+	}
+
+	name, reference := image, "latest"
+	if i := strings.Index(name, "@"); i != -1 {
+		name, reference = name[:i], name[i+1:]
+	} else if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		name, reference = name[:i], name[i+1:]
+	}
+
+	registry := "docker.io"
+	if i := strings.Index(name, "/"); i != -1 {
+		first := name[:i]
+		if strings.ContainsAny(first, ".:") || first == "localhost" {
+			registry, name = first, name[i+1:]
+		}
+	}
+	if registry == "docker.io" {
+		registry = "registry-1.docker.io"
+		if !strings.Contains(name, "/") {
+			name = "library/" + name
+		}
+	}
+	if name == "" || reference == "" {
+		return imageReference{}, fmt.Errorf("invalid image reference %q", image)
+	}
+	return imageReference{registry: registry, repository: name, reference: reference}, nil
+}
+
+// registryManifest covers the fields of image manifests and indexes the inspector reads
+type registryManifest struct {
+	Manifests []struct {
+		Digest   string `json:"digest"`
+		Platform *struct {
+			OS           string `json:"os"`
+			Architecture string `json:"architecture"`
+		} `json:"platform,omitempty"`
+	} `json:"manifests,omitempty"`
+	Config *struct {
+		Digest string `json:"digest"`
+	} `json:"config,omitempty"`
+}
+
+// registryImageConfig covers the fields of an image config blob the inspector reads
+type registryImageConfig struct {
+	OS           string `json:"os"`
+	Architecture string `json:"architecture"`
+	Config       struct {
+		Labels map[string]string `json:"Labels"`
+	} `json:"config"`
+}
+
+// Inspect implements ImageInspector. For multi-platform images the labels are read from the
+// first supported platform's config.
+func (i *RegistryImageInspector) Inspect(ctx context.Context, image string) (*ImageInfo, error) {
+	ref, err := parseImageReference(image)
+	if err != nil {
+		return nil, err
+	}
+	session := &registrySession{client: i.Client, ref: ref}
+	if session.client == nil {
+		session.client = &http.Client{Timeout: imageInspectionTimeout}
+	}
+
+	var manifest registryManifest
+	if err := session.get(ctx, "manifests/"+ref.reference, strings.Join(manifestAcceptTypes, ", "), &manifest); err != nil {
+		return nil, err
+	}
+
+	info := &ImageInfo{}
+	if len(manifest.Manifests) > 0 {
+		digest := ""
+		for _, entry := range manifest.Manifests {
+			// Attestation manifests have no platform, or unknown/unknown
+			if entry.Platform == nil || entry.Platform.OS == "unknown" {
+				continue
+			}
+			platform := entry.Platform.OS + "/" + entry.Platform.Architecture
+			info.Platforms = append(info.Platforms, platform)
+			if digest == "" && containsString(supportedAgentPlatforms, platform) {
+				digest = entry.Digest
+			}
+		}
+		if digest == "" {
+			return info, nil
+		}
+		manifest = registryManifest{}
+		if err := session.get(ctx, "manifests/"+digest, strings.Join(manifestAcceptTypes, ", "), &manifest); err != nil {
+			return nil, err
+		}
+	}
+	if manifest.Config == nil || manifest.Config.Digest == "" {
+		return nil, fmt.Errorf("manifest of %s has no image config", image)
+	}
+
+	var config registryImageConfig
+	if err := session.get(ctx, "blobs/"+manifest.Config.Digest, "", &config); err != nil {
+		return nil, err
+	}
+	info.Labels = config.Config.Labels
+	if len(info.Platforms) == 0 {
+		info.Platforms = []string{config.OS + "/" + config.Architecture}
+	}
+	return info, nil
+}
+
+// registrySession performs the registry requests of one inspection, reusing its pull token
+type registrySession struct {
+	client *http.Client
+	ref    imageReference
+	token  string
+}
+
+// bearerChallengeParam matches the key="value" pairs of a WWW-Authenticate challenge
+var bearerChallengeParam = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// get fetches a manifest or blob of the session's repository into out, requesting a pull token
+// when the registry challenges the anonymous request
+func (s *registrySession) get(ctx context.Context, path, accept string, out interface{}) error {
+	endpoint := fmt.Sprintf("https://%s/v2/%s/%s", s.ref.registry, s.ref.repository, path)
+	resp, err := s.do(ctx, endpoint, accept)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && s.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if s.token, err = s.fetchToken(ctx, challenge); err != nil {
+			return err
+		}
+		if resp, err = s.do(ctx, endpoint, accept); err != nil {
+			return err
+		}
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("registry %s returned status %d for %s", s.ref.registry, resp.StatusCode, path)
+	}
+	return decodeRegistryResponse(resp.Body, out)
+}
+
+func (s *registrySession) do(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("registry request to %s failed: %w", s.ref.registry, err)
+	}
+	return resp, nil
+}
+
+// fetchToken requests an anonymous pull token from the token service named in a Bearer challenge
+func (s *registrySession) fetchToken(ctx context.Context, challenge string) (string, error) {
+	if !strings.HasPrefix(strings.ToLower(challenge), "bearer ") {
+		return "", fmt.Errorf("registry %s requires credentials", s.ref.registry)
+	}
+	params := make(map[string]string)
+	for _, match := range bearerChallengeParam.FindAllStringSubmatch(challenge, -1) {
+		params[strings.ToLower(match[1])] = match[2]
+	}
+	if params["realm"] == "" {
+		return "", fmt.Errorf("registry %s sent a token challenge without a realm", s.ref.registry)
+	}
+
+	tokenURL, err := url.Parse(params["realm"])
+	if err != nil {
+		return "", fmt.Errorf("invalid token realm %q: %w", params["realm"], err)
+	}
+	query := tokenURL.Query()
+	if params["service"] != "" {
+		query.Set("service", params["service"])
+	}
+	scope := params["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", s.ref.repository)
+	}
+	query.Set("scope", scope)
+	tokenURL.RawQuery = query.Encode()
+
+	resp, err := s.do(ctx, tokenURL.String(), "")
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token service of %s returned status %d", s.ref.registry, resp.StatusCode)
+	}
+
+	var token struct {
+		Token       string `json:"token"`
+		AccessToken string `json:"access_token"`
+	}
+	if err := decodeRegistryResponse(resp.Body, &token); err != nil {
+		return "", err
+	}
+	if token.Token != "" {
+		return token.Token, nil
+	}
+	if token.AccessToken != "" {
+		return token.AccessToken, nil
+	}
+	return "", fmt.Errorf("token service of %s returned no token", s.ref.registry)
+}
+
+func decodeRegistryResponse(body io.Reader, out interface{}) error {
+	data, err := io.ReadAll(io.LimitReader(body, maxRegistryResponseSize+1))
+	if err != nil {
+		return fmt.Errorf("failed to read registry response: %w", err)
+	}
+	if len(data) > maxRegistryResponseSize {
+		return fmt.Errorf("registry response exceeds %d bytes", maxRegistryResponseSize)
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("failed to parse registry response: %w", err)
+	}
+	return nil
+}
+
+// imageCompatibilityProblem returns the reason and message why an image can't run as an agent
+// on nodes of the given architectures, or empty strings when it can. Without known node
+// architectures only the supported platforms are checked.
+func imageCompatibilityProblem(image string, info *ImageInfo, architectures []string) (string, string) {
+	if info.Labels[AgentRuntimeLabel] == "" {
+		return "NotAgentRuntime", fmt.Sprintf("Image %s has no %s label; agent images must be built from the langop agent runtime", image, AgentRuntimeLabel)
+	}
+
+	var supported []string
+	for _, platform := range info.Platforms {
+		if containsString(supportedAgentPlatforms, platform) {
+			supported = append(supported, platform)
+		}
+	}
+	if len(supported) == 0 {
+		return "UnsupportedPlatform", fmt.Sprintf("Image %s is built for %s, but agents run on %s",
+			image, strings.Join(info.Platforms, ", "), strings.Join(supportedAgentPlatforms, " or "))
+	}
+
+	if len(architectures) == 0 {
+		return "", ""
+	}
+	for _, arch := range architectures {
+		if containsString(supported, "linux/"+arch) {
+			return "", ""
+		}
+	}
+	return "ArchitectureMismatch", fmt.Sprintf("Image %s is built for %s, but the agent's nodes are %s",
+		image, strings.Join(supported, ", "), strings.Join(architectures, ", "))
+}
+
+// agentNodeArchitectures returns the CPU architectures the agent's pods can be scheduled on: the
+// kubernetes.io/arch node selector when set, otherwise those of the cluster's nodes
+func (r *LanguageAgentReconciler) agentNodeArchitectures(ctx context.Context, agent *langopv1alpha1.LanguageAgent) ([]string, error) {
+	if arch := agent.Spec.NodeSelector[corev1.LabelArchStable]; arch != "" {
+		return []string{arch}, nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := r.List(ctx, nodes); err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	seen := make(map[string]bool)
+	var architectures []string
+	for _, node := range nodes.Items {
+		arch := node.Labels[corev1.LabelArchStable]
+		if arch == "" {
+			arch = node.Status.NodeInfo.Architecture
+		}
+		if arch != "" && !seen[arch] {
+			seen[arch] = true
+			architectures = append(architectures, arch)
+		}
+	}
+	sort.Strings(architectures)
+	return architectures, nil
+}
+
+// imageInspection is a cached inspection result
+type imageInspection struct {
+	info    *ImageInfo
+	err     error
+	expires time.Time
+}
+
+// imageInspectionCache remembers inspections so every reconcile doesn't hit the registry
+type imageInspectionCache struct {
+	mu      sync.Mutex
+	entries map[string]imageInspection
+	// nextSweep is when expired entries of images no longer inspected are next removed
+	nextSweep time.Time
+}
+
+// store caches an inspection, sweeping expired entries at most once per imageInspectionTTL so
+// images no agent uses anymore don't accumulate. The caller must hold mu.
+func (c *imageInspectionCache) store(image string, inspection imageInspection, now time.Time) {
+	if now.After(c.nextSweep) {
+		for key, entry := range c.entries {
+			if !now.Before(entry.expires) {
+				delete(c.entries, key)
+			}
+		}
+		c.nextSweep = now.Add(imageInspectionTTL)
+	}
+	c.entries[image] = inspection
+}
+
+func (r *LanguageAgentReconciler) getImageInspections() *imageInspectionCache {
+	r.imageInspectionsOnce.Do(func() {
+		r.imageInspections = &imageInspectionCache{entries: make(map[string]imageInspection)}
+	})
+	return r.imageInspections
+}
+
+// inspectAgentImage inspects an image through the configured inspector, serving cached results
+func (r *LanguageAgentReconciler) inspectAgentImage(ctx context.Context, image string) (*ImageInfo, error) {
+	cache := r.getImageInspections()
+	cache.mu.Lock()
+	cached, ok := cache.entries[image]
+	cache.mu.Unlock()
+	if ok && time.Now().Before(cached.expires) {
+		return cached.info, cached.err
+	}
+
+	inspector := r.ImageInspector
+	if inspector == nil {
+		inspector = &RegistryImageInspector{}
+	}
+	inspectCtx, cancel := context.WithTimeout(ctx, imageInspectionTimeout)
+	info, err := inspector.Inspect(inspectCtx, image)
+	cancel()
+
+	ttl := imageInspectionTTL
+	if err != nil {
+		ttl = imageInspectionRetryInterval
+	}
+	now := time.Now()
+	cache.mu.Lock()
+	cache.store(image, imageInspection{info: info, err: err, expires: now.Add(ttl)}, now)
+	cache.mu.Unlock()
+	return info, err
+}
+
+// checkAgentImage verifies, with --verify-agent-image, that the agent image is an agent runtime
+// built for the agent's nodes and records the ImageCompatible condition. It returns false only
+// for images known to be incompatible; images that can't be inspected are let through with the
+// condition Unknown, so a registry outage doesn't take agents down.
+func (r *LanguageAgentReconciler) checkAgentImage(ctx context.Context, agent *langopv1alpha1.LanguageAgent, image string) (bool, error) {
+	if !r.VerifyAgentImage {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, ImageCompatibleCondition)
+		return true, nil
+	}
+
+	info, err := r.inspectAgentImage(ctx, image)
+	if err != nil {
+		SetCondition(&agent.Status.Conditions, ImageCompatibleCondition, metav1.ConditionUnknown, "InspectionFailed",
+			fmt.Sprintf("Could not inspect image %s: %v", image, err), agent.Generation)
+		return true, nil
+	}
+
+	architectures, err := r.agentNodeArchitectures(ctx, agent)
+	if err != nil {
+		return false, err
+	}
+
+	reason, message := imageCompatibilityProblem(image, info, architectures)
+	if reason == "" {
+		SetCondition(&agent.Status.Conditions, ImageCompatibleCondition, metav1.ConditionTrue, "Compatible",
+			fmt.Sprintf("Image %s is an agent runtime built for %s", image, strings.Join(info.Platforms, ", ")), agent.Generation)
+		return true, nil
+	}
+
+	previous := meta.FindStatusCondition(agent.Status.Conditions, ImageCompatibleCondition)
+	if r.Recorder != nil && (previous == nil || previous.Status != metav1.ConditionFalse || previous.Reason != reason) {
+		r.Recorder.Event(agent, corev1.EventTypeWarning, "ImageIncompatible", message)
+	}
+	SetCondition(&agent.Status.Conditions, ImageCompatibleCondition, metav1.ConditionFalse, reason, message, agent.Generation)
+	return false, nil
+}
@@ -0,0 +1,302 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+// stubRegistry serves manifests and blobs of the agent/runtime repository by path
+type stubRegistry struct {
+	// documents maps a path below /v2/agent/runtime/ to the JSON served for it
+	documents map[string]interface{}
+	// token, when set, is required as a Bearer token handed out by the /token endpoint
+	token string
+}
+
+func (s *stubRegistry) start(t *testing.T) *httptest.Server {
+	t.Helper()
+	var server *httptest.Server
+	server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if req.URL.Path == "/token" {
+			if req.URL.Query().Get("scope") != "repository:agent/runtime:pull" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]string{"token": s.token})
+			return
+		}
+		if s.token != "" && req.Header.Get("Authorization") != "Bearer "+s.token {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="`+server.URL+`/token",service="stub-registry"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		document, ok := s.documents[strings.TrimPrefix(req.URL.Path, "/v2/agent/runtime/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(document)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// labeledIndexDocuments is a multi-platform agent runtime image with an attestation manifest
+func labeledIndexDocuments() map[string]interface{} {
+	return map[string]interface{}{
+		"manifests/latest": map[string]interface{}{
+			"manifests": []map[string]interface{}{
+				{"digest": "sha256:amd64", "platform": map[string]string{"os": "linux", "architecture": "amd64"}},
+				{"digest": "sha256:arm64", "platform": map[string]string{"os": "linux", "architecture": "arm64"}},
+				{"digest": "sha256:attestation", "platform": map[string]string{"os": "unknown", "architecture": "unknown"}},
+			},
+		},
+		"manifests/sha256:amd64": map[string]interface{}{"config": map[string]string{"digest": "sha256:amd64-config"}},
+		"blobs/sha256:amd64-config": map[string]interface{}{
+			"os":           "linux",
+			"architecture": "amd64",
+			"config":       map[string]interface{}{"Labels": map[string]string{AgentRuntimeLabel: "0.4.0"}},
+		},
+	}
+}
+
+// amd64ManifestDocuments is a single-platform linux/amd64 image with the given labels
+func amd64ManifestDocuments(labels map[string]string) map[string]interface{} {
+	return map[string]interface{}{
+		"manifests/latest": map[string]interface{}{"config": map[string]string{"digest": "sha256:config"}},
+		"blobs/sha256:config": map[string]interface{}{
+			"os":           "linux",
+			"architecture": "amd64",
+			"config":       map[string]interface{}{"Labels": labels},
+		},
+	}
+}
+
+func stubRegistryImage(server *httptest.Server) string {
+	return strings.TrimPrefix(server.URL, "https://") + "/agent/runtime:latest"
+}
+
+func TestRegistryImageInspector_Inspect(t *testing.T) {
+	tests := []struct {
+		name            string
+		registry        *stubRegistry
+		expectErr       bool
+		expectLabels    map[string]string
+		expectPlatforms []string
+	}{
+		{
+			name:            "labeled multi-platform index",
+			registry:        &stubRegistry{documents: labeledIndexDocuments()},
+			expectLabels:    map[string]string{AgentRuntimeLabel: "0.4.0"},
+			expectPlatforms: []string{"linux/amd64", "linux/arm64"},
+		},
+		{
+			name:            "unlabeled single manifest",
+			registry:        &stubRegistry{documents: amd64ManifestDocuments(map[string]string{"maintainer": "someone"})},
+			expectLabels:    map[string]string{"maintainer": "someone"},
+			expectPlatforms: []string{"linux/amd64"},
+		},
+		{
+			name:            "anonymous pull token",
+			registry:        &stubRegistry{documents: labeledIndexDocuments(), token: "pull-token"},
+			expectLabels:    map[string]string{AgentRuntimeLabel: "0.4.0"},
+			expectPlatforms: []string{"linux/amd64", "linux/arm64"},
+		},
+		{
+			name:      "missing image",
+			registry:  &stubRegistry{documents: map[string]interface{}{}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := tt.registry.start(t)
+			inspector := &RegistryImageInspector{Client: server.Client()}
+
+			info, err := inspector.Inspect(context.Background(), stubRegistryImage(server))
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", info)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Inspect failed: %v", err)
+			}
+			if !reflect.DeepEqual(info.Labels, tt.expectLabels) {
+				t.Errorf("Expected labels %v, got %v", tt.expectLabels, info.Labels)
+			}
+			if !reflect.DeepEqual(info.Platforms, tt.expectPlatforms) {
+				t.Errorf("Expected platforms %v, got %v", tt.expectPlatforms, info.Platforms)
+			}
+		})
+	}
+}
+
+func TestParseImageReference(t *testing.T) {
+	tests := []struct {
+		image     string
+		expected  imageReference
+		expectErr bool
+	}{
+		{image: "ubuntu", expected: imageReference{registry: "registry-1.docker.io", repository: "library/ubuntu", reference: "latest"}},
+		{image: "langop/agent:1.2", expected: imageReference{registry: "registry-1.docker.io", repository: "langop/agent", reference: "1.2"}},
+		{image: "ghcr.io/language-operator/agent:latest", expected: imageReference{registry: "ghcr.io", repository: "language-operator/agent", reference: "latest"}},
+		{image: "localhost:5000/agent", expected: imageReference{registry: "localhost:5000", repository: "agent", reference: "latest"}},
+		{image: "ghcr.io/agent@sha256:abc", expected: imageReference{registry: "ghcr.io", repository: "agent", reference: "sha256:abc"}},
+		{image: "", expectErr: true},
+		{image: "ghcr.io/agent:", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.image, func(t *testing.T) {
+			ref, err := parseImageReference(tt.image)
+			if tt.expectErr {
+				if err == nil {
+					t.Errorf("Expected an error, got %+v", ref)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseImageReference failed: %v", err)
+			}
+			if ref != tt.expected {
+				t.Errorf("Expected %+v, got %+v", tt.expected, ref)
+			}
+		})
+	}
+}
+
+func TestImageCompatibilityProblem(t *testing.T) {
+	runtimeLabels := map[string]string{AgentRuntimeLabel: "0.4.0"}
+	tests := []struct {
+		name          string
+		info          *ImageInfo
+		architectures []string
+		expected      string
+	}{
+		{name: "compatible", info: &ImageInfo{Labels: runtimeLabels, Platforms: []string{"linux/amd64"}}, architectures: []string{"amd64"}},
+		{name: "any node architecture matches", info: &ImageInfo{Labels: runtimeLabels, Platforms: []string{"linux/arm64"}}, architectures: []string{"amd64", "arm64"}},
+		{name: "unknown node architectures", info: &ImageInfo{Labels: runtimeLabels, Platforms: []string{"linux/arm64"}}},
+		{name: "missing label", info: &ImageInfo{Platforms: []string{"linux/amd64"}}, architectures: []string{"amd64"}, expected: "NotAgentRuntime"},
+		{name: "unsupported platform", info: &ImageInfo{Labels: runtimeLabels, Platforms: []string{"windows/amd64", "linux/s390x"}}, expected: "UnsupportedPlatform"},
+		{name: "architecture mismatch", info: &ImageInfo{Labels: runtimeLabels, Platforms: []string{"linux/amd64"}}, architectures: []string{"arm64"}, expected: "ArchitectureMismatch"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, message := imageCompatibilityProblem("agent:latest", tt.info, tt.architectures)
+			if reason != tt.expected {
+				t.Errorf("Expected reason %q, got %q (%s)", tt.expected, reason, message)
+			}
+			if (reason == "") != (message == "") {
+				t.Errorf("Expected a message exactly when there is a reason, got %q", message)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_VerifyAgentImage(t *testing.T) {
+	tests := []struct {
+		name             string
+		documents        map[string]interface{}
+		nodeArch         string
+		expectStatus     metav1.ConditionStatus
+		expectReason     string
+		expectDeployment bool
+	}{
+		{name: "compatible image", documents: labeledIndexDocuments(), nodeArch: "arm64", expectStatus: metav1.ConditionTrue, expectReason: "Compatible", expectDeployment: true},
+		{name: "unlabeled image", documents: amd64ManifestDocuments(map[string]string{"maintainer": "someone"}), nodeArch: "amd64", expectStatus: metav1.ConditionFalse, expectReason: "NotAgentRuntime"},
+		{name: "wrong architecture", documents: amd64ManifestDocuments(map[string]string{AgentRuntimeLabel: "0.4.0"}), nodeArch: "arm64", expectStatus: metav1.ConditionFalse, expectReason: "ArchitectureMismatch"},
+		{name: "uninspectable image", documents: map[string]interface{}{}, nodeArch: "amd64", expectStatus: metav1.ConditionUnknown, expectReason: "InspectionFailed", expectDeployment: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := (&stubRegistry{documents: tt.documents}).start(t)
+			node := &corev1.Node{
+				ObjectMeta: metav1.ObjectMeta{Name: "node-1", Labels: map[string]string{corev1.LabelArchStable: tt.nodeArch}},
+			}
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "verified-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:         stubRegistryImage(server),
+					ExecutionMode: "autonomous",
+				},
+			}
+			reconciler, fakeClient := newForceWorkloadReconciler(t, node, agent)
+			reconciler.RegistryManager = &mockRegistryManager{registries: []string{}}
+			reconciler.VerifyAgentImage = true
+			reconciler.ImageInspector = &RegistryImageInspector{Client: server.Client()}
+
+			ctx := context.Background()
+			key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			updated := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, key, updated); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			condition := meta.FindStatusCondition(updated.Status.Conditions, ImageCompatibleCondition)
+			if condition == nil {
+				t.Fatalf("Expected %s condition", ImageCompatibleCondition)
+			}
+			if condition.Status != tt.expectStatus || condition.Reason != tt.expectReason {
+				t.Errorf("Expected %s/%s, got %s/%s: %s", tt.expectStatus, tt.expectReason, condition.Status, condition.Reason, condition.Message)
+			}
+
+			err := fakeClient.Get(ctx, key, &appsv1.Deployment{})
+			if tt.expectDeployment && err != nil {
+				t.Errorf("Expected a Deployment, got error: %v", err)
+			}
+			if !tt.expectDeployment && !errors.IsNotFound(err) {
+				t.Errorf("Expected no Deployment for an incompatible image, got err=%v", err)
+			}
+		})
+	}
+}
+
+func TestImageInspectionCache_SweepsExpiredEntries(t *testing.T) {
+	now := time.Now()
+	cache := &imageInspectionCache{entries: map[string]imageInspection{
+		"old:latest":   {expires: now.Add(-time.Second)},
+		"fresh:latest": {expires: now.Add(time.Minute)},
+	}}
+
+	cache.store("new:latest", imageInspection{expires: now.Add(imageInspectionTTL)}, now)
+	if _, ok := cache.entries["old:latest"]; ok {
+		t.Error("Expected the expired inspection to be swept")
+	}
+	if len(cache.entries) != 2 {
+		t.Errorf("Expected the fresh and new inspections to remain, got %v", cache.entries)
+	}
+
+	// Sweeps run at most once per TTL
+	cache.entries["stale:latest"] = imageInspection{expires: now}
+	cache.store("other:latest", imageInspection{expires: now.Add(imageInspectionTTL)}, now.Add(time.Second))
+	if _, ok := cache.entries["stale:latest"]; !ok {
+		t.Error("Expected no sweep before the next sweep time")
+	}
+	cache.store("other:latest", imageInspection{expires: now.Add(2 * imageInspectionTTL)}, now.Add(imageInspectionTTL+time.Second))
+	if _, ok := cache.entries["stale:latest"]; ok {
+		t.Error("Expected the expired inspection to be swept once the sweep interval passed")
+	}
+}
@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSynthesisInstructions(t *testing.T) {
+	tests := []struct {
+		name         string
+		instructions string
+		gates        map[string]bool
+		expected     string
+	}{
+		{name: "instructions", instructions: "Summarize the news", expected: "Summarize the news"},
+		{name: "goal ignored by default", expected: ""},
+		{name: "goal used when gate enabled", gates: map[string]bool{langopv1alpha1.FeatureGateGoalAsInstructions: true}, expected: "Watch for outages"},
+		{name: "instructions win over goal", instructions: "Summarize the news", gates: map[string]bool{langopv1alpha1.FeatureGateGoalAsInstructions: true}, expected: "Summarize the news"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := &LanguageAgentReconciler{FeatureGates: tt.gates}
+			agent := &langopv1alpha1.LanguageAgent{
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Instructions: tt.instructions,
+					Goal:         "Watch for outages",
+					ModelRefs:    []langopv1alpha1.ModelReference{{Name: "test-model"}},
+				},
+			}
+
+			if got := reconciler.synthesisInstructions(agent); got != tt.expected {
+				t.Errorf("Expected synthesis instructions %q, got %q", tt.expected, got)
+			}
+			if uses := reconciler.usesSynthesizedCode(agent); uses != (tt.expected != "") {
+				t.Errorf("Expected usesSynthesizedCode=%v, got %v", tt.expected != "", uses)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_InstructionsRequiredCondition(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{ModelName: "gpt-4"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "goal-only-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			Goal:          "Watch for outages",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(model, agent).
+		WithStatusSubresource(agent).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        &record.FakeRecorder{},
+		RegistryManager: &mockRegistryManager{},
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	condition := meta.FindStatusCondition(updated.Status.Conditions, "InstructionsRequired")
+	if condition == nil {
+		t.Fatal("Expected InstructionsRequired condition")
+	}
+	if condition.Status != metav1.ConditionFalse || condition.Reason != "InstructionsMissing" {
+		t.Errorf("Expected InstructionsRequired=False/InstructionsMissing, got %s/%s", condition.Status, condition.Reason)
+	}
+	if !strings.Contains(condition.Message, langopv1alpha1.FeatureGateGoalAsInstructions) {
+		t.Errorf("Expected message to mention the %s feature gate, got %q", langopv1alpha1.FeatureGateGoalAsInstructions, condition.Message)
+	}
+	if meta.FindStatusCondition(updated.Status.Conditions, "Synthesized") != nil {
+		t.Error("Expected no synthesis without instructions")
+	}
+}
+
+func TestLanguageAgentController_GoalAsInstructions(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{ModelName: "gpt-4"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "goal-only-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			Goal:          "Watch for outages",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+			FeatureGates:  map[string]bool{langopv1alpha1.FeatureGateGoalAsInstructions: true},
+		},
+	}
+
+	// Code already synthesized from the goal, so reconcile reuses it without calling a model
+	code := "agent 'goal-only-agent' do\nend"
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateConfigMapName(agent.Name, "code"),
+			Namespace: agent.Namespace,
+			Annotations: map[string]string{
+				codeHashAnnotation:            hashString(code),
+				"langop.io/instructions-hash": hashString(agent.Spec.Goal),
+				"langop.io/tools-hash":        hashString(""),
+				"langop.io/models-hash":       hashString("test-model"),
+				"langop.io/persona-hash":      hashString(""),
+			},
+		},
+		Data: map[string]string{"agent.rb": code},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(model, agent, codeConfigMap).
+		WithStatusSubresource(agent).
+		Build()
+
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        recorder,
+		RegistryManager: &mockRegistryManager{},
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	if events := drainEvents(recorder); hasEvent(events, "SynthesisStarted") {
+		t.Errorf("Expected code synthesized from the goal to be reused, got %v", events)
+	}
+
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if meta.FindStatusCondition(updated.Status.Conditions, "InstructionsRequired") != nil {
+		t.Error("Expected no InstructionsRequired condition when synthesizing from the goal")
+	}
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, "Synthesized") {
+		t.Error("Expected Synthesized condition to be true")
+	}
+}
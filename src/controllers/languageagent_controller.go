@@ -7,6 +7,9 @@ import (
 	"fmt"
 	"os"
 	"regexp"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,38 +23,43 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	policyv1 "k8s.io/api/policy/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
 	"k8s.io/apimachinery/pkg/util/intstr"
-	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/audit"
 	"github.com/language-operator/language-operator/pkg/reconciler"
 	"github.com/language-operator/language-operator/pkg/synthesis"
 	"github.com/language-operator/language-operator/pkg/validation"
 )
 
-// gatewayAPICache holds cached Gateway API availability information
-type gatewayAPICache struct {
-	available bool
-	lastCheck time.Time
-	mutex     sync.RWMutex
-}
-
 const (
-	// Gateway API cache TTL - how long to cache the availability result
-	gatewayAPICacheTTL = 5 * time.Minute
+	// dependencyRequeueInterval is how often an agent waiting on dependencies is rechecked
+	dependencyRequeueInterval = 15 * time.Second
+
+	// codeRequeueInterval is how often an agent waiting for its synthesized code is rechecked
+	codeRequeueInterval = 10 * time.Second
+
+	// defaultSelfHealingStabilityWindow is how long self-healed code must run without failures
+	// before it is promoted to the last known good code
+	defaultSelfHealingStabilityWindow = 10 * time.Minute
 )
 
 // RegistryManager interface for registry configuration management
@@ -72,9 +80,85 @@ type LanguageAgentReconciler struct {
 	RegistryManager        RegistryManager
 	NetworkPolicyTimeout   time.Duration
 	NetworkPolicyRetries   int
-	gatewayCache           *gatewayAPICache
+	// SelfHealingStabilityWindow is how long self-healed code must run without failures
+	// before it becomes the last known good code. Zero uses defaultSelfHealingStabilityWindow.
+	SelfHealingStabilityWindow time.Duration
+	// SelfHealingRollbackWindow is how long after deploying self-healed code a repeat failure
+	// reverts the agent to its last known good code instead of healing again. Zero disables rollback.
+	SelfHealingRollbackWindow time.Duration
+	// UnhealthyThreshold is how long a running pod may stay not-ready before it
+	// counts as a failure for self-healing. Zero disables health-based detection.
+	UnhealthyThreshold time.Duration
+	// ConditionMetricTypes limits which condition types are exported as
+	// langop_agent_condition gauges. Empty exports all condition types.
+	ConditionMetricTypes []string
+	// FeatureGates sets the operator-wide state of feature gates. Agents can
+	// override individual gates with spec.featureGates.
+	FeatureGates map[string]bool
+	// Audit records synthesis and self-healing transitions to the audit stream.
+	// Nil disables auditing.
+	Audit *audit.Emitter
+	// ExampleLibrary provides the few-shot example sets agents select with
+	// spec.synthesisExampleSet. Nil means no library is configured.
+	ExampleLibrary *synthesis.ExampleLibrary
+	// SynthesisRedactor replaces sensitive strings in synthesis input with placeholders before it
+	// is sent to the synthesis model. Nil sends synthesis input verbatim.
+	SynthesisRedactor *synthesis.Redactor
+	// SynthesisCache serves previously synthesized code for unchanged agents instead of calling
+	// the synthesis model. Nil always synthesizes.
+	SynthesisCache *synthesis.SynthesisCache
+	// SynthesisSlots bounds concurrent LLM synthesis calls and shares them fairly
+	// across agents or namespaces. Nil means unlimited.
+	SynthesisSlots *synthesis.SlotScheduler
+	// DriftPolicy controls how external edits to the code ConfigMap and Deployment
+	// are handled: DriftPolicyCorrect (the default) or DriftPolicyWarn.
+	DriftPolicy string
+	// Reloader delivers new code to agents with spec.warmReload (nil uses HTTPAgentReloader)
+	Reloader AgentReloader
+	// Priority orders reconciles by the langop.io/priority label (nil reconciles in FIFO order)
+	Priority *ReconcilePrioritizer
+	// MaxConcurrentAgentRestarts bounds agent Deployment rollouts in progress at once, so a
+	// change affecting many agents restarts them in a staggered way (0 = unlimited)
+	MaxConcurrentAgentRestarts int32
+	// BatchStatusUpdates accumulates the status changes of a reconcile and writes them once
+	// at the end, instead of once per reconcile step
+	BatchStatusUpdates bool
+	// DefaultResources are the operator-level requests and limits of agent and sidecar tool
+	// containers that neither the container nor the agent's cluster set
+	DefaultResources corev1.ResourceRequirements
+	// ChaosEnabled permits spec.chaos failure injection for agents in namespaces labeled
+	// langop.io/chaos-testing=true. Disabled, spec.chaos is ignored everywhere.
+	ChaosEnabled bool
+	// GatewayAPI detects whether the Gateway API is installed. Nil uses the process-wide
+	// SharedGatewayAPIDetector.
+	GatewayAPI *GatewayAPIDetector
+	// QuotaBackoffThreshold is how many consecutive reconciles refused by the synthesis rate
+	// limit or quota pause an agent's synthesis for QuotaBackoffInterval (0 = never pause)
+	QuotaBackoffThreshold int
+	// QuotaBackoffInterval is how long synthesis stays paused by quota backoff. Zero uses
+	// defaultQuotaBackoffInterval.
+	QuotaBackoffInterval time.Duration
+	// VerifyAgentImage inspects agent images in their registry before deploying them and refuses
+	// images that aren't a langop agent runtime built for the nodes' architecture
+	VerifyAgentImage bool
+	// ImageInspector reads agent image labels and platforms (nil uses RegistryImageInspector)
+	ImageInspector ImageInspector
+
+	restarts             *restartCoordinator
+	restartsOnce         sync.Once
+	quotaBackoff         *quotaBackoffTracker
+	quotaBackoffOnce     sync.Once
+	imageInspections     *imageInspectionCache
+	imageInspectionsOnce sync.Once
+	driftWarnings        *driftWarningTracker
+	driftWarningsOnce    sync.Once
+	docsFailures         *docsFailureTracker
+	docsFailuresOnce     sync.Once
 }
 
+// auditControllerLanguageAgent identifies the LanguageAgent controller in audit records
+const auditControllerLanguageAgent = "languageagent"
+
 // agentTracer is used by methods that haven't been refactored yet
 var agentTracer = otel.Tracer("language-operator/agent-controller")
 
@@ -85,9 +169,20 @@ const (
 	LangopGroupID = 101
 )
 
-// InitializeGatewayCache initializes the Gateway API cache
+// getRestartCoordinator returns the coordinator shared by all agent Deployment rollouts
+func (r *LanguageAgentReconciler) getRestartCoordinator() *restartCoordinator {
+	r.restartsOnce.Do(func() {
+		r.restarts = newRestartCoordinator(r.MaxConcurrentAgentRestarts)
+	})
+	return r.restarts
+}
+
+// InitializeGatewayCache makes the reconciler use the shared Gateway API detector unless one
+// was injected
 func (r *LanguageAgentReconciler) InitializeGatewayCache() {
-	r.gatewayCache = &gatewayAPICache{}
+	if r.GatewayAPI == nil {
+		r.GatewayAPI = SharedGatewayAPIDetector()
+	}
 }
 
 //+kubebuilder:rbac:groups=langop.io,resources=languageagents,verbs=get;list;watch;create;update;patch;delete
@@ -95,21 +190,32 @@ func (r *LanguageAgentReconciler) InitializeGatewayCache() {
 //+kubebuilder:rbac:groups=langop.io,resources=languageagents/finalizers,verbs=update
 //+kubebuilder:rbac:groups=langop.io,resources=languagepersonas,verbs=get;list;watch
 //+kubebuilder:rbac:groups=langop.io,resources=languageclusters,verbs=get;list;watch
+//+kubebuilder:rbac:groups=langop.io,resources=languagemodelgrants,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=apps,resources=daemonsets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create;delete
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=persistentvolumeclaims,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+//+kubebuilder:rbac:groups="",resources=nodes,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
 //+kubebuilder:rbac:groups="",resources=pods/log,verbs=get
+//+kubebuilder:rbac:groups=policy,resources=poddisruptionbudgets,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=networking.k8s.io,resources=ingresses,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=httproutes,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=gateway.networking.k8s.io,resources=referencegrants,verbs=get;list;watch;create;update;patch;delete
+//+kubebuilder:rbac:groups=cilium.io,resources=ciliumnetworkpolicies,verbs=get;list;watch;create;update;patch;delete
 
 // Reconcile is part of the main kubernetes reconciliation loop
-func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, retErr error) {
+	// Step aside while a higher-priority agent is still waiting for a worker
+	if r.Priority != nil && r.Priority.Defer(req.NamespacedName) {
+		return ctrl.Result{RequeueAfter: priorityDeferInterval}, nil
+	}
+
 	// Use the reconciler helper for common setup
 	helper := &reconciler.ReconcileHelper[*langopv1alpha1.LanguageAgent]{
 		Client:       r.Client,
@@ -124,6 +230,12 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 	if result == nil {
 		// Resource was deleted
+		deleteAgentConditionMetrics(req.Namespace, req.Name)
+		if r.Priority != nil {
+			r.Priority.Forget(req.NamespacedName)
+		}
+		r.getRestartCoordinator().Forget(req.NamespacedName)
+		r.getQuotaBackoff().Forget(req.NamespacedName)
 		return ctrl.Result{}, nil
 	}
 
@@ -133,10 +245,38 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		result.CompleteReconcile(reconcileErr)
 	}()
 
+	// Export the conditions as they stand at the end of this reconcile
+	defer func() {
+		if agent.DeletionTimestamp.IsZero() {
+			recordAgentConditionMetrics(agent, r.ConditionMetricTypes)
+		} else {
+			deleteAgentConditionMetrics(agent.Namespace, agent.Name)
+		}
+	}()
+
 	ctx = result.Ctx
 	span := result.Span
 	log := log.FromContext(ctx)
 
+	// Write the status changes of every reconcile step at once
+	statusBatch := newAgentStatusBatch(agent)
+	if r.BatchStatusUpdates {
+		defer func() {
+			if !agent.DeletionTimestamp.IsZero() {
+				return
+			}
+			if err := statusBatch.flush(ctx, r.Client, agent); err != nil {
+				log.Error(err, "Failed to update LanguageAgent status")
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "Failed to update status")
+				if retErr == nil {
+					reconcileErr = err
+					retErr = err
+				}
+			}
+		}()
+	}
+
 	// Add agent-specific attributes to span
 	span.SetAttributes(
 		attribute.String("agent.mode", agent.Spec.ExecutionMode),
@@ -152,6 +292,9 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 				reconcileErr = err
 				return ctrl.Result{}, err
 			}
+			if r.QuotaManager != nil {
+				r.QuotaManager.ForgetAgent(agent.Namespace, agent.Name)
+			}
 			controllerutil.RemoveFinalizer(agent, FinalizerName)
 			if err := r.Update(ctx, agent); err != nil {
 				span.RecordError(err)
@@ -175,16 +318,36 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		}
 	}
 
+	// Unknown feature gates are ignored so agents keep working across operator versions
+	if unknown := langopv1alpha1.UnknownFeatureGates(agent.Spec.FeatureGates); len(unknown) > 0 {
+		log.Info("Ignoring unknown feature gates", "gates", unknown)
+	}
+
+	// Agents without spec.image run their cluster's default agent image
+	image, err := r.resolveAgentImage(ctx, agent)
+	if err != nil {
+		log.Error(err, "Failed to resolve agent image")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to resolve agent image")
+		setFailure(agent, langopv1alpha1.FailureReasonValidation, err.Error())
+		if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
+			log.Error(updateErr, "Failed to update status after image resolution failure")
+		}
+		reconcileErr = err
+		return ctrl.Result{}, err
+	}
+
 	// Validate image registry against whitelist
-	if err := r.validateImageRegistry(agent); err != nil {
-		log.Error(err, "Image registry validation failed", "image", agent.Spec.Image)
+	if err := r.validateImageRegistry(image); err != nil {
+		log.Error(err, "Image registry validation failed", "image", image)
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Image registry validation failed")
 		SetCondition(&agent.Status.Conditions, "RegistryValidated", metav1.ConditionFalse, "RegistryNotAllowed", err.Error(), agent.Generation)
+		setFailure(agent, langopv1alpha1.FailureReasonValidation, err.Error())
 		if r.Recorder != nil {
-			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "RegistryValidationFailed", "Image registry not in whitelist: %s", agent.Spec.Image)
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "RegistryValidationFailed", "Image registry not in whitelist: %s", image)
 		}
-		if updateErr := r.Status().Update(ctx, agent); updateErr != nil {
+		if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
 			log.Error(updateErr, "Failed to update status after registry validation failure")
 		}
 		reconcileErr = err
@@ -192,28 +355,140 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 	SetCondition(&agent.Status.Conditions, "RegistryValidated", metav1.ConditionTrue, "Validated", "Image registry is in whitelist", agent.Generation)
 
-	// Detect pod failures for self-healing (if enabled)
+	// Images that aren't an agent runtime for these nodes would only crash loop, so don't deploy them
+	compatible, err := r.checkAgentImage(ctx, agent, image)
+	if err != nil {
+		log.Error(err, "Failed to verify agent image", "image", image)
+		span.RecordError(err)
+		reconcileErr = err
+		return ctrl.Result{}, err
+	}
+	if !compatible {
+		log.Info("Agent image is incompatible, not deploying it", "image", image)
+		span.SetStatus(codes.Error, "Agent image incompatible")
+		condition := meta.FindStatusCondition(agent.Status.Conditions, ImageCompatibleCondition)
+		setFailure(agent, langopv1alpha1.FailureReasonValidation, condition.Message)
+		if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
+			log.Error(updateErr, "Failed to update status after image verification failure")
+		}
+		// Check again once the inspection expires, in case the tag was pushed again
+		return ctrl.Result{RequeueAfter: imageInspectionTTL}, nil
+	}
+
+	// Resolve spec.toolSelector before anything reads the agent's tools
+	if err := r.resolveToolSelector(ctx, agent); err != nil {
+		log.Error(err, "Failed to resolve tool selector")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to resolve tool selector")
+		reconcileErr = err
+		return ctrl.Result{}, err
+	}
+
+	// Resolve the namespace's default personas before anything reads the agent's personas
+	if err := r.resolveDefaultPersonas(ctx, agent); err != nil {
+		log.Error(err, "Failed to resolve default personas")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to resolve default personas")
+		reconcileErr = err
+		return ctrl.Result{}, err
+	}
+
+	// Models in other namespaces may only be used where that namespace grants access
+	if err := r.enforceModelGrants(ctx, agent); err != nil {
+		log.Error(err, "Failed to authorize model references")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Model reference not granted")
+		if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
+			log.Error(updateErr, "Failed to update status after model grant check")
+		}
+		reconcileErr = err
+		return ctrl.Result{}, err
+	}
+
+	// Detect pod failures for self-healing (if enabled). Self-healed code still inside its
+	// stability window is rechecked once the window ends.
+	var stabilityRequeue time.Duration
 	if r.SelfHealingEnabled {
 		if err := r.detectPodFailures(ctx, agent); err != nil {
 			log.Error(err, "Failed to detect pod failures")
 			// Don't fail reconciliation, just log the error
 		}
+		remaining, err := r.promoteStableSelfHealedCode(ctx, agent)
+		if err != nil {
+			log.Error(err, "Failed to promote self-healed code")
+		}
+		stabilityRequeue = remaining
+	}
+
+	// Agents with modelRefs but nothing to synthesize from run without synthesized code; say why
+	if len(agent.Spec.ModelRefs) > 0 && r.synthesisInstructions(agent) == "" {
+		message := "spec.modelRefs is set but spec.instructions is empty, so no agent code will be synthesized; set spec.instructions"
+		if agent.Spec.Goal != "" {
+			message += fmt.Sprintf(", or enable the %s feature gate to synthesize from spec.goal", langopv1alpha1.FeatureGateGoalAsInstructions)
+		}
+		SetCondition(&agent.Status.Conditions, "InstructionsRequired", metav1.ConditionFalse, "InstructionsMissing", message, agent.Generation)
+	} else {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, "InstructionsRequired")
 	}
 
 	// Synthesize agent code from instructions (if agent has modelRefs and instructions)
-	if len(agent.Spec.ModelRefs) > 0 && agent.Spec.Instructions != "" {
-		if err := r.reconcileCodeConfigMap(ctx, agent); err != nil {
+	var deferredRequeue time.Duration
+	if r.usesSynthesizedCode(agent) {
+		// Agents that keep hitting the synthesis quota wait for it instead of retrying every reconcile
+		if wait := r.quotaBackoffRemaining(agent); wait > 0 {
+			log.V(1).Info("Synthesis paused by quota backoff", "requeueAfter", wait)
+			return ctrl.Result{RequeueAfter: wait}, nil
+		}
+
+		err := r.reconcileCodeConfigMap(ctx, agent)
+		if deferredErr, ok := isSynthesisDeferred(err); ok {
+			// Synthesis waits for referenced tools so it sees their schemas, or for a free
+			// synthesis slot; code already synthesized keeps running, and the rest of the agent
+			// is reconciled meanwhile
+			log.Info("Deferring synthesis", "cause", deferredErr.cause, "requeueAfter", deferredErr.wait)
+			if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
+				log.Error(updateErr, "Failed to update status while synthesis is deferred")
+			}
+			if !deferredErr.synthesized {
+				return ctrl.Result{RequeueAfter: deferredErr.wait}, nil
+			}
+			deferredRequeue = deferredErr.wait
+			err = nil
+		}
+		backoff := r.observeSynthesisQuota(agent, err)
+		if err != nil {
 			log.Error(err, "Failed to synthesize/reconcile agent code")
 			span.RecordError(err)
 			span.SetStatus(codes.Error, "Synthesis failed")
-			SetCondition(&agent.Status.Conditions, "Synthesized", metav1.ConditionFalse, "SynthesisFailed", err.Error(), agent.Generation)
-			if updateErr := r.Status().Update(ctx, agent); updateErr != nil {
+			reason := synthesis.FailureReason(err)
+			if synthesis.IsCostEstimateExceeded(err) {
+				reason = synthesis.ReasonCostEstimateExceeded
+			} else if synthesis.IsCostDataUnavailable(err) {
+				reason = synthesis.ReasonCostDataUnavailable
+			} else if synthesis.IsMissingToolReference(err) {
+				reason = synthesis.ReasonReferencesMissingTool
+			} else if synthesis.IsPersonaConstraintViolation(err) {
+				reason = synthesis.ReasonPersonaConstraintViolation
+			} else if validation.IsLimitExceeded(err) {
+				reason = validation.ReasonValidationTimeout
+			}
+			SetCondition(&agent.Status.Conditions, "Synthesized", metav1.ConditionFalse, reason, err.Error(), agent.Generation)
+			setFailure(agent, classifySynthesisFailure(err), err.Error())
+			if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
 				log.Error(updateErr, "Failed to update status after synthesis failure")
 			}
+			r.Audit.Emit(ctx, auditControllerLanguageAgent, audit.ActionSynthesisFailed, agent, err.Error(),
+				map[string]string{"reason": reason})
 			reconcileErr = err
+			if backoff > 0 {
+				log.Info("Pausing synthesis after repeated quota exhaustion", "requeueAfter", backoff)
+				return ctrl.Result{RequeueAfter: backoff}, nil
+			}
 			return ctrl.Result{}, err
 		}
-		SetCondition(&agent.Status.Conditions, "Synthesized", metav1.ConditionTrue, "CodeGenerated", "Agent code synthesized successfully", agent.Generation)
+		if deferredRequeue == 0 {
+			SetCondition(&agent.Status.Conditions, "Synthesized", metav1.ConditionTrue, "CodeGenerated", "Agent code synthesized successfully", agent.Generation)
+		}
 	}
 
 	// Reconcile ConfigMap
@@ -222,7 +497,8 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "ConfigMap reconciliation failed")
 		SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "ConfigMapError", err.Error(), agent.Generation)
-		if updateErr := r.Status().Update(ctx, agent); updateErr != nil {
+		setFailure(agent, langopv1alpha1.FailureReasonUnknown, err.Error())
+		if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
 			log.Error(updateErr, "Failed to update status after ConfigMap error")
 		}
 		reconcileErr = err
@@ -235,7 +511,8 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "PVC reconciliation failed")
 		SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "PVCError", err.Error(), agent.Generation)
-		if updateErr := r.Status().Update(ctx, agent); updateErr != nil {
+		setFailure(agent, langopv1alpha1.FailureReasonUnknown, err.Error())
+		if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
 			log.Error(updateErr, "Failed to update status after PVC error")
 		}
 		reconcileErr = err
@@ -273,7 +550,8 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 			// For non-timeout errors, fail the reconciliation
 			span.SetStatus(codes.Error, "NetworkPolicy reconciliation failed")
 			SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "NetworkPolicyError", err.Error(), agent.Generation)
-			if updateErr := r.Status().Update(ctx, agent); updateErr != nil {
+			setFailure(agent, langopv1alpha1.FailureReasonNetwork, err.Error())
+			if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
 				log.Error(updateErr, "Failed to update status after NetworkPolicy error")
 			}
 			reconcileErr = err
@@ -286,7 +564,8 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	}
 
 	// Detect if NetworkPolicy enforcement is supported
-	if supported, cni := r.detectNetworkPolicySupport(ctx); !supported {
+	supported, cni := r.detectNetworkPolicySupport(ctx)
+	if !supported {
 		message := fmt.Sprintf("NetworkPolicy created but may not be enforced. CNI plugin '%s' does not support NetworkPolicy. Consider installing Cilium, Calico, Weave Net, or Antrea for network isolation.", cni)
 		SetCondition(&agent.Status.Conditions, "NetworkPolicyEnforced", metav1.ConditionFalse, "CNINotSupported", message, agent.Generation)
 		if r.Recorder != nil {
@@ -299,10 +578,18 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		log.V(1).Info("NetworkPolicy enforcement supported", "cni", cni)
 	}
 
+	// Enforce DNS egress rules by name where the CNI supports it. The NetworkPolicy above
+	// still allows the resolved IPs, so a failure here only degrades name-based enforcement.
+	if err := r.reconcileFQDNEgress(ctx, agent, cni); err != nil {
+		log.Error(err, "Failed to reconcile DNS egress policy")
+		SetCondition(&agent.Status.Conditions, fqdnEgressCondition, metav1.ConditionFalse, "FQDNEgressError", err.Error(), agent.Generation)
+	}
+
 	// Ensure agent has a UUID for webhook routing
 	if agent.Status.UUID == "" {
+		// Persisted right away so a concurrent reconcile can't assign a different UUID
 		agent.Status.UUID = uuid.New().String()
-		if err := r.Status().Update(ctx, agent); err != nil {
+		if err := statusBatch.flush(ctx, r.Client, agent); err != nil {
 			if errors.IsConflict(err) {
 				// Another reconciler updated first, requeue to get their UUID
 				log.V(1).Info("UUID assignment conflict, requeuing to get assigned UUID")
@@ -323,7 +610,8 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Service reconciliation failed")
 		SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "ServiceError", err.Error(), agent.Generation)
-		if updateErr := r.Status().Update(ctx, agent); updateErr != nil {
+		setFailure(agent, langopv1alpha1.FailureReasonUnknown, err.Error())
+		if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
 			log.Error(updateErr, "Failed to update status after Service error")
 		}
 		return ctrl.Result{}, err
@@ -338,28 +626,107 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		SetCondition(&agent.Status.Conditions, "WebhooksReady", metav1.ConditionTrue, "Configured", "Webhook routing configured", agent.Generation)
 	}
 
-	// Reconcile workload based on execution mode
+	// Gate workload creation until all declared dependencies are Ready
+	dependenciesReady, dependencyMsg, err := r.checkDependencies(ctx, agent)
+	if err != nil {
+		log.Error(err, "Failed to check agent dependencies")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Dependency check failed")
+		reconcileErr = err
+		return ctrl.Result{}, err
+	}
+	if !dependenciesReady {
+		log.Info("Waiting for agent dependencies", "reason", dependencyMsg)
+		SetCondition(&agent.Status.Conditions, "DependenciesReady", metav1.ConditionFalse, "DependenciesNotReady", dependencyMsg, agent.Generation)
+		SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "DependenciesNotReady", dependencyMsg, agent.Generation)
+		setFailure(agent, langopv1alpha1.FailureReasonDependency, dependencyMsg)
+		agent.Status.Phase = "Pending"
+		if err := r.updateStatus(ctx, agent); err != nil {
+			log.Error(err, "Failed to update status while waiting for dependencies")
+			reconcileErr = err
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: dependencyRequeueInterval}, nil
+	}
+	if len(agent.Spec.DependsOn) > 0 {
+		SetCondition(&agent.Status.Conditions, "DependenciesReady", metav1.ConditionTrue, "DependenciesReady", "All dependencies are ready", agent.Generation)
+	} else {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, "DependenciesReady")
+	}
+
+	// Don't create a workload that can't mount its code ConfigMap
+	if agentWorkload(agent) != "" {
+		codeReady, err := r.codeConfigMapExists(ctx, agent)
+		if err != nil {
+			log.Error(err, "Failed to check code ConfigMap")
+			span.RecordError(err)
+			reconcileErr = err
+			return ctrl.Result{}, err
+		}
+		if !codeReady {
+			log.Info("Waiting for synthesized code before creating workload")
+			SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "WaitingForCode",
+				"Waiting for synthesized code ConfigMap before creating workload", agent.Generation)
+			agent.Status.Phase = "Pending"
+			if err := r.updateStatus(ctx, agent); err != nil {
+				log.Error(err, "Failed to update status while waiting for code")
+				reconcileErr = err
+				return ctrl.Result{}, err
+			}
+			return ctrl.Result{RequeueAfter: codeRequeueInterval}, nil
+		}
+	}
+
+	// Reconcile workload based on execution mode, unless the workload type is forced
 	// If executionMode is empty, skip workload reconciliation until synthesis completes and detects the mode
-	switch agent.Spec.ExecutionMode {
-	case "autonomous", "interactive", "event-driven":
+	switch agentWorkload(agent) {
+	case WorkloadDeployment:
 		if err := r.reconcileDeployment(ctx, agent); err != nil {
 			log.Error(err, "Failed to reconcile Deployment")
 			span.RecordError(err)
 			span.SetStatus(codes.Error, "Deployment reconciliation failed")
 			SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "DeploymentError", err.Error(), agent.Generation)
-			if updateErr := r.Status().Update(ctx, agent); updateErr != nil {
+			setFailure(agent, langopv1alpha1.FailureReasonUnknown, err.Error())
+			if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
 				log.Error(updateErr, "Failed to update status after Deployment error")
 			}
 			reconcileErr = err
 			return ctrl.Result{}, err
 		}
-	case "scheduled":
+	case WorkloadCronJob:
+		minInterval, err := r.getMinScheduleInterval(ctx, agent)
+		if err != nil {
+			log.Error(err, "Failed to resolve cluster schedule policy")
+			span.RecordError(err)
+			reconcileErr = err
+			return ctrl.Result{}, err
+		}
+		if err := validateScheduleInterval(cronJobSchedule(agent), minInterval); err != nil {
+			// Leave any existing CronJob untouched until the schedule complies with the cluster policy
+			log.Info("Rejecting schedule that violates cluster policy", "reason", err.Error())
+			SetCondition(&agent.Status.Conditions, ScheduleTooFrequentCondition, metav1.ConditionTrue, "BelowMinScheduleInterval", err.Error(), agent.Generation)
+			SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "ScheduleTooFrequent", err.Error(), agent.Generation)
+			setFailure(agent, langopv1alpha1.FailureReasonValidation, err.Error())
+			agent.Status.Phase = "Failed"
+			if r.Recorder != nil {
+				r.Recorder.Eventf(agent, corev1.EventTypeWarning, "ScheduleTooFrequent", "%s", err.Error())
+			}
+			if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
+				log.Error(updateErr, "Failed to update status after schedule policy violation")
+				reconcileErr = updateErr
+				return ctrl.Result{}, updateErr
+			}
+			return ctrl.Result{}, nil
+		}
+		meta.RemoveStatusCondition(&agent.Status.Conditions, ScheduleTooFrequentCondition)
+
 		if err := r.reconcileCronJob(ctx, agent); err != nil {
 			log.Error(err, "Failed to reconcile CronJob")
 			span.RecordError(err)
 			span.SetStatus(codes.Error, "CronJob reconciliation failed")
 			SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, "CronJobError", err.Error(), agent.Generation)
-			if updateErr := r.Status().Update(ctx, agent); updateErr != nil {
+			setFailure(agent, langopv1alpha1.FailureReasonUnknown, err.Error())
+			if updateErr := r.updateStatus(ctx, agent); updateErr != nil {
 				log.Error(updateErr, "Failed to update status after CronJob error")
 			}
 			reconcileErr = err
@@ -370,6 +737,13 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		log.V(1).Info("ExecutionMode not set, skipping workload reconciliation until synthesis completes")
 	}
 
+	if err := r.reconcilePDB(ctx, agent); err != nil {
+		log.Error(err, "Failed to reconcile PodDisruptionBudget")
+		span.RecordError(err)
+		reconcileErr = err
+		return ctrl.Result{}, err
+	}
+
 	// Update status only if something changed
 	statusChanged := false
 	if agent.Status.Phase != "Running" {
@@ -379,9 +753,12 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	if SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionTrue, "ReconcileSuccess", "LanguageAgent is ready", agent.Generation) {
 		statusChanged = true
 	}
+	if clearFailure(agent) {
+		statusChanged = true
+	}
 
 	if statusChanged {
-		if err := r.Status().Update(ctx, agent); err != nil {
+		if err := r.updateStatus(ctx, agent); err != nil {
 			log.Error(err, "Failed to update LanguageAgent status")
 			span.RecordError(err)
 			span.SetStatus(codes.Error, "Failed to update status")
@@ -391,12 +768,18 @@ func (r *LanguageAgentReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	// Reconciliation successful
 	span.SetStatus(codes.Ok, "Reconciliation successful")
-	return ctrl.Result{}, nil
+	requeue := stabilityRequeue
+	if deferredRequeue > 0 && (requeue == 0 || deferredRequeue < requeue) {
+		requeue = deferredRequeue
+	}
+	if r.getRestartCoordinator().Waiting(req.NamespacedName) && (requeue == 0 || restartRequeueInterval < requeue) {
+		return ctrl.Result{RequeueAfter: restartRequeueInterval}, nil
+	}
+	return ctrl.Result{RequeueAfter: requeue}, nil
 }
 
 func (r *LanguageAgentReconciler) reconcileConfigMap(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
 	log := log.FromContext(ctx)
-	data := make(map[string]string)
 
 	// Fetch persona if referenced
 	persona, err := r.fetchPersona(ctx, agent)
@@ -404,6 +787,20 @@ func (r *LanguageAgentReconciler) reconcileConfigMap(ctx context.Context, agent
 		// Log warning but continue without persona
 		log.Error(err, "Failed to fetch persona, continuing without it")
 	}
+	r.checkPersonaResponseFormat(ctx, agent, persona)
+
+	data, err := agentConfigData(agent, persona)
+	if err != nil {
+		return err
+	}
+
+	configMapName := GenerateConfigMapName(agent.Name, "agent")
+	return CreateOrUpdateConfigMap(ctx, r.Client, r.Scheme, agent, configMapName, agent.Namespace, data)
+}
+
+// agentConfigData builds the contents of the agent config ConfigMap
+func agentConfigData(agent *langopv1alpha1.LanguageAgent, persona *langopv1alpha1.LanguagePersona) (map[string]string, error) {
+	data := make(map[string]string)
 
 	// Merge instructions with persona systemPrompt if persona is available
 	instructions := agent.Spec.Instructions
@@ -428,7 +825,7 @@ func (r *LanguageAgentReconciler) reconcileConfigMap(ctx context.Context, agent
 	// Add agent spec as JSON
 	specJSON, err := json.Marshal(agent.Spec)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	data["agent.json"] = string(specJSON)
 
@@ -436,7 +833,7 @@ func (r *LanguageAgentReconciler) reconcileConfigMap(ctx context.Context, agent
 	if persona != nil {
 		personaJSON, err := json.Marshal(persona.Spec)
 		if err != nil {
-			return err
+			return nil, err
 		}
 		data["persona.json"] = string(personaJSON)
 		data["persona_name"] = persona.Name
@@ -455,8 +852,7 @@ func (r *LanguageAgentReconciler) reconcileConfigMap(ctx context.Context, agent
 		data["instructions"] = instructions
 	}
 
-	configMapName := GenerateConfigMapName(agent.Name, "agent")
-	return CreateOrUpdateConfigMap(ctx, r.Client, r.Scheme, agent, configMapName, agent.Namespace, data)
+	return data, nil
 }
 
 // reconcileCodeConfigMap synthesizes agent DSL code and stores it in a ConfigMap
@@ -472,6 +868,11 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 			"consecutiveFailures", agent.Status.ConsecutiveFailures,
 			"selfHealingAttempts", agent.Status.SelfHealingAttempts)
 
+		// Self-healed code that fails again soon after deployment is reverted rather than healed again
+		if rolledBack, err := r.rollbackFailedSelfHealing(ctx, agent); err != nil || rolledBack {
+			return err
+		}
+
 		// Check if we've exceeded max self-healing attempts
 		if agent.Status.SelfHealingAttempts >= r.MaxSelfHealingAttempts {
 			log.Info("Max self-healing attempts reached, marking agent as failed")
@@ -480,7 +881,7 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 				fmt.Sprintf("Self-healing failed after %d attempts", r.MaxSelfHealingAttempts),
 				agent.Generation)
 			agent.Status.Phase = "Failed"
-			if err := r.Status().Update(ctx, agent); err != nil {
+			if err := r.updateStatus(ctx, agent); err != nil {
 				return err
 			}
 			if r.Recorder != nil {
@@ -512,6 +913,12 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 		}
 
 		agent.Status.SelfHealingAttempts++
+		r.Audit.Emit(ctx, auditControllerLanguageAgent, audit.ActionSelfHealingTriggered, agent,
+			"Self-healing synthesis triggered",
+			map[string]string{
+				"attempt":             strconv.Itoa(int(agent.Status.SelfHealingAttempts)),
+				"consecutiveFailures": strconv.Itoa(int(agent.Status.ConsecutiveFailures)),
+			})
 		return r.performSelfHealingSynthesis(ctx, agent)
 	}
 
@@ -519,7 +926,7 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 	// Check if we need to synthesize
 	// Smart change detection:
 	// 1. ConfigMap doesn't exist → full synthesis
-	// 2. Instructions changed → full synthesis
+	// 2. Instructions changed → regenerate the affected tasks, or full synthesis
 	// 3. Persona changed → re-distill only (update existing code's context)
 	// 4. Tools/models changed → env var update only (no synthesis needed)
 	existingCM := &corev1.ConfigMap{}
@@ -527,7 +934,11 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 
 	needsSynthesis := false
 	needsPersonaUpdate := false
+	codeDrift := false
+	// Tasks to regenerate when an instruction change only affects part of the code
+	var regeneratedTasks, changedSections []string
 
+	codeExists := err == nil
 	if errors.IsNotFound(err) {
 		needsSynthesis = true
 		log.Info("Code ConfigMap not found, will synthesize")
@@ -551,8 +962,18 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 			return nil
 		}
 
+		// Code edited outside the operator can't be trusted, so regenerate it unless drift is only reported
+		codeDrift = codeDrifted(existingCM)
+		if !r.correctsDrift() {
+			driftState := ""
+			if codeDrift {
+				driftState = hashString(existingCM.Data["agent.rb"])
+			}
+			r.warnDriftTransition(agent, "ConfigMap", codeConfigMapName, driftState)
+		}
+
 		// Compare current vs previous hashes for smart change detection
-		currentInstructionsHash := hashString(agent.Spec.Instructions)
+		currentInstructionsHash := hashString(r.synthesisInstructions(agent))
 		previousInstructionsHash := existingCM.Annotations["langop.io/instructions-hash"]
 
 		currentToolsHash := hashString(strings.Join(r.getToolNames(agent), ","))
@@ -565,12 +986,17 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 		currentPersonaHash := hashString(strings.Join(personaRefs, ","))
 		previousPersonaHash := existingCM.Annotations["langop.io/persona-hash"]
 
-		// Instructions changed → full re-synthesis
-		if currentInstructionsHash != previousInstructionsHash {
+		if codeDrift && r.correctsDrift() {
+			needsSynthesis = true
+			log.Info("Code ConfigMap was edited outside the operator, will re-synthesize")
+			// Instructions changed → full re-synthesis
+		} else if currentInstructionsHash != previousInstructionsHash {
 			needsSynthesis = true
+			regeneratedTasks, changedSections, _ = r.planTaskRegeneration(agent, existingCM)
 			log.Info("Instructions changed, will re-synthesize",
 				"previousHash", previousInstructionsHash,
-				"currentHash", currentInstructionsHash)
+				"currentHash", currentInstructionsHash,
+				"tasks", regeneratedTasks)
 			// Persona changed → re-distill without full synthesis
 		} else if currentPersonaHash != previousPersonaHash {
 			needsPersonaUpdate = true
@@ -586,8 +1012,40 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 		}
 	}
 
-	var dslCode string
+	// Wait for referenced tools so synthesis sees their schemas
 	if needsSynthesis {
+		wait, err := r.checkToolsReady(ctx, agent)
+		if err != nil {
+			return fmt.Errorf("failed to check tool readiness: %w", err)
+		}
+		if wait > 0 {
+			return &synthesisDeferredError{wait: wait, synthesized: codeExists, cause: "waiting for tools"}
+		}
+	}
+
+	var dslCode string
+	var cacheHit bool
+	// Partial regeneration splices into the existing code, so only full synthesis uses the cache
+	if needsSynthesis && len(regeneratedTasks) == 0 {
+		dslCode, cacheHit = r.cachedSynthesis(ctx, agent)
+	}
+	if cacheHit {
+		log.Info("Using cached synthesized code, skipping synthesis", "agent", agent.Name)
+		synthesis.RecordSynthesisCacheHit(agent.Namespace)
+		if r.Recorder != nil {
+			r.Recorder.Event(agent, corev1.EventTypeNormal, "SynthesisCacheHit", "Reused cached code synthesized from the same instructions, tools, models, and personas")
+		}
+		if agent.Status.SynthesisInfo == nil {
+			agent.Status.SynthesisInfo = &langopv1alpha1.SynthesisInfo{}
+		}
+		agent.Status.SynthesisInfo.CodeHash = hashString(dslCode)
+		agent.Status.SynthesisInfo.InstructionsHash = hashString(r.synthesisInstructions(agent))
+		agent.Status.SynthesisInfo.ValidationErrors = nil
+		meta.RemoveStatusCondition(&agent.Status.Conditions, langopv1alpha1.SelfHealingRolledBackCondition)
+		if err := r.updateStatus(ctx, agent); err != nil {
+			log.Error(err, "Failed to update synthesis info in status")
+		}
+	} else if needsSynthesis {
 		// Start synthesis span
 		ctx, span := agentTracer.Start(ctx, "agent.synthesize")
 		defer span.End()
@@ -620,6 +1078,9 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 		var distilledPersona string
 		if persona != nil {
 			distilledPersona, err = r.distillPersona(ctx, persona, agent)
+			if synthesis.IsNoSynthesisSlot(err) {
+				return deferOnBusySlots(err, codeExists)
+			}
 			if err != nil {
 				log.Error(err, "Failed to distill persona, continuing without it")
 				distilledPersona = ""
@@ -631,15 +1092,32 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 
 		// Build synthesis request
 		synthReq := synthesis.AgentSynthesisRequest{
-			Instructions: agent.Spec.Instructions,
+			Instructions: r.synthesisInstructions(agent),
 			Tools:        tools,       // Kept for backward compatibility
 			ToolSchemas:  toolSchemas, // Complete schemas for better synthesis
 			Models:       models,
 			PersonaText:  distilledPersona,
 			AgentName:    agent.Name,
 			Namespace:    agent.Namespace,
+			Examples:     r.synthesisExamples(ctx, agent, tools),
+
+			PersonaConstraints: r.personaConstraints(agent, persona),
 		}
 
+		// Wait for a synthesis slot before using up rate limit and quota, so synthesis deferred
+		// because every slot is busy costs nothing
+		if err := r.SynthesisSlots.Acquire(ctx, agent.Namespace, agent.Name); err != nil {
+			return deferOnBusySlots(fmt.Errorf("failed to acquire synthesis slot: %w", err), codeExists)
+		}
+		slotHeld := true
+		releaseSlot := func() {
+			if slotHeld {
+				slotHeld = false
+				r.SynthesisSlots.Release()
+			}
+		}
+		defer releaseSlot()
+
 		// Check rate limit before synthesis
 		if r.RateLimiter != nil {
 			if err := r.RateLimiter.CheckAndConsume(ctx, agent.Namespace); err != nil {
@@ -653,14 +1131,18 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 				span.RecordError(err)
 				span.SetStatus(codes.Error, "Rate limit exceeded")
 				// Return error to retry later
-				return fmt.Errorf("synthesis rate limit exceeded: %w", err)
+				return &synthesis.QuotaExceededError{Err: fmt.Errorf("synthesis rate limit exceeded: %w", err)}
 			}
 		}
 
 		// Check quota before synthesis
+		var reserved bool
 		if r.QuotaManager != nil {
-			// Check attempt quota
-			if err := r.QuotaManager.CheckAttemptQuota(ctx, agent.Namespace); err != nil {
+			defer r.recordSynthesisQuota(agent)
+
+			// Reserve an attempt so concurrent reconciles in the namespace can't overrun the quota,
+			// and the agent can't exceed its own spec.synthesisQuota
+			if err := r.QuotaManager.ReserveAgentAttempt(ctx, agent.Namespace, agent.Name, agentQuotaLimits(agent)); err != nil {
 				if r.Recorder != nil {
 					r.Recorder.Eventf(agent, corev1.EventTypeWarning, "QuotaExceeded", "Synthesis attempt quota exceeded: %v", err)
 				}
@@ -670,8 +1152,29 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 				// Record error in span
 				span.RecordError(err)
 				span.SetStatus(codes.Error, "Quota exceeded")
-				return fmt.Errorf("synthesis attempt quota exceeded: %w", err)
+				return &synthesis.QuotaExceededError{Err: fmt.Errorf("synthesis attempt quota exceeded: %w", err)}
 			}
+			// Synthesis completes the reservation once it ran; any earlier return releases it
+			reserved = true
+			defer func() {
+				if reserved {
+					r.QuotaManager.ReleaseAgentAttempt(agent.Namespace, agent.Name)
+				}
+			}()
+
+			// Refuse synthesis whose projected cost alone would exceed the remaining cost quota
+			if err := r.checkSynthesisCostEstimate(ctx, agent, synthReq); err != nil {
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "Cost estimate exceeds quota")
+				return err
+			}
+		}
+
+		// Refuse synthesis the cost quota can't account for when the agent requires cost data
+		if err := r.checkSynthesisPricing(ctx, agent); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Cost data unavailable")
+			return err
 		}
 
 		// Synthesize code
@@ -680,13 +1183,34 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 			r.Recorder.Event(agent, corev1.EventTypeNormal, "SynthesisStarted", "Starting code synthesis from natural language instructions")
 		}
 
-		// Create synthesizer from agent's model
-		synthesizer, synthesisModelName, err := r.createSynthesizer(ctx, agent)
+		// Synthesize with the agent's model, moving along the cluster's fallback chain on failure
+		chain, err := r.synthesisModelChain(ctx, agent)
 		if err != nil {
 			return fmt.Errorf("failed to create synthesizer: %w", err)
 		}
 
-		resp, err := synthesizer.SynthesizeAgent(ctx, synthReq)
+		resp, synthesisModel, err := r.synthesizeAlongChain(ctx, agent, chain, func(synthesizer synthesis.AgentSynthesizer) (*synthesis.AgentSynthesisResponse, error) {
+			if len(regeneratedTasks) > 0 {
+				resp, err := r.regenerateTasks(ctx, synthesizer, synthReq, existingCM.Data["agent.rb"], regeneratedTasks, changedSections)
+				if err == nil {
+					return resp, nil
+				}
+				log.Info("Task regeneration failed, falling back to full re-synthesis",
+					"tasks", regeneratedTasks,
+					"error", err.Error())
+				regeneratedTasks = nil
+			}
+			if candidates, ok := synthesizer.(synthesis.CandidateSynthesizer); ok && agent.Spec.SynthesisCandidates > 1 {
+				budget := r.candidateBudget(ctx, agent)
+				return r.streamSynthesis(ctx, agent, func() (<-chan synthesis.SynthesisProgress, error) {
+					return candidates.SynthesizeCandidatesStream(ctx, synthReq, int(agent.Spec.SynthesisCandidates), budget)
+				})
+			}
+			return r.streamSynthesis(ctx, agent, func() (<-chan synthesis.SynthesisProgress, error) {
+				return synthesizer.SynthesizeAgentStream(ctx, synthReq)
+			})
+		})
+		releaseSlot()
 
 		// Record synthesis attempt
 		if r.QuotaManager != nil {
@@ -697,11 +1221,12 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 			} else if resp.Error != "" {
 				errorMsg = resp.Error
 			}
-			r.QuotaManager.RecordAttempt(ctx, agent.Namespace, agent.Name, success, errorMsg)
+			r.QuotaManager.CompleteAgentAttempt(ctx, agent.Namespace, agent.Name, success, errorMsg)
+			reserved = false
 		}
 		if err != nil {
 			if r.Recorder != nil {
-				r.Recorder.Eventf(agent, corev1.EventTypeWarning, "SynthesisFailed", "Code synthesis failed: %v", err)
+				r.Recorder.Eventf(agent, corev1.EventTypeWarning, synthesis.FailureReason(err), "Code synthesis failed: %v", err)
 			}
 			// Record failure metrics
 			synthesis.RecordSynthesisRequest(agent.Namespace, "failed")
@@ -726,7 +1251,14 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 			return fmt.Errorf("synthesis validation failed: %s", resp.Error)
 		}
 
+		if err := r.checkReportedCost(ctx, agent, synthesisModel, synthReq, resp); err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Cost data unavailable")
+			return err
+		}
+
 		dslCode = resp.DSLCode
+		r.cacheSynthesis(ctx, agent, dslCode)
 		log.Info("Agent code synthesized successfully",
 			"agent", agent.Name,
 			"codeLength", len(dslCode),
@@ -741,6 +1273,10 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 
 		if r.Recorder != nil {
 			r.Recorder.Eventf(agent, corev1.EventTypeNormal, "SynthesisSucceeded", "Code synthesized successfully in %.2fs", resp.DurationSeconds)
+			if len(regeneratedTasks) > 0 {
+				r.Recorder.Eventf(agent, corev1.EventTypeNormal, "TasksRegenerated", "Regenerated tasks %s without re-synthesizing the rest of the agent",
+					strings.Join(regeneratedTasks, ", "))
+			}
 		}
 
 		// Record synthesis cost if available
@@ -758,7 +1294,12 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 
 			// Record metrics
 			synthesis.RecordSynthesisTokens(agent.Namespace, resp.Cost.InputTokens, resp.Cost.OutputTokens)
-			synthesis.RecordSynthesisCost(agent.Namespace, resp.Cost.TotalCost)
+			// The cost metric is in USD whatever the quota currency; costs that can't be converted are left out
+			if costUSD, converted := r.QuotaManager.ConvertCostTo(resp.Cost.TotalCost, resp.Cost.Currency, "USD"); converted {
+				synthesis.RecordSynthesisCost(agent.Namespace, costUSD)
+			} else {
+				log.Info("No exchange rate to USD, leaving synthesis cost out of the cost metric", "currency", resp.Cost.Currency)
+			}
 		}
 
 		// Record synthesis success metric
@@ -778,11 +1319,22 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 			agent.Status.SynthesisInfo = &langopv1alpha1.SynthesisInfo{}
 		}
 		agent.Status.SynthesisInfo.LastSynthesisTime = &now
-		agent.Status.SynthesisInfo.SynthesisModel = synthesisModelName
+		agent.Status.SynthesisInfo.SynthesisModel = synthesisModel.Spec.ModelName
+		agent.Status.SynthesisInfo.SynthesisProvider = resp.Provider
 		agent.Status.SynthesisInfo.SynthesisDuration = resp.DurationSeconds
 		agent.Status.SynthesisInfo.CodeHash = hashString(dslCode)
-		agent.Status.SynthesisInfo.InstructionsHash = hashString(agent.Spec.Instructions)
+		agent.Status.SynthesisInfo.InstructionsHash = hashString(r.synthesisInstructions(agent))
 		agent.Status.SynthesisInfo.ValidationErrors = resp.ValidationErrors
+		agent.Status.SynthesisInfo.Candidates = 0
+		agent.Status.SynthesisInfo.SelectionRationale = ""
+		if resp.Candidates != nil {
+			agent.Status.SynthesisInfo.Candidates = int32(resp.Candidates.Generated)
+			agent.Status.SynthesisInfo.SelectionRationale = resp.Candidates.Rationale
+		}
+		r.recordRedaction(agent, resp)
+		r.recordPromptTrim(agent, resp)
+		// New code replaces any code restored by a self-healing rollback, which needs no more attention
+		meta.RemoveStatusCondition(&agent.Status.Conditions, langopv1alpha1.SelfHealingRolledBackCondition)
 		if agent.Status.SynthesisInfo.SynthesisAttempts == 0 || needsSynthesis {
 			agent.Status.SynthesisInfo.SynthesisAttempts++
 		}
@@ -793,7 +1345,7 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 		}
 
 		// Update agent status
-		if err := r.Status().Update(ctx, agent); err != nil {
+		if err := r.updateStatus(ctx, agent); err != nil {
 			log.Error(err, "Failed to update synthesis info in status")
 		}
 	} else if needsPersonaUpdate {
@@ -828,11 +1380,13 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 
 	// Store all hashes for smart change detection
 	annotations := map[string]string{
-		"langop.io/instructions-hash": hashString(agent.Spec.Instructions),
+		codeHashAnnotation:            hashString(dslCode),
+		"langop.io/instructions-hash": hashString(r.synthesisInstructions(agent)),
 		"langop.io/tools-hash":        hashString(strings.Join(r.getToolNames(agent), ",")),
 		"langop.io/models-hash":       hashString(strings.Join(r.getModelNames(agent), ",")),
 		"langop.io/persona-hash":      hashString(strings.Join(r.getPersonaNames(agent), ",")),
 	}
+	addTaskSectionAnnotations(annotations, r.synthesisInstructions(agent), dslCode)
 
 	// Only update synthesized-at timestamp when we actually synthesized new code
 	if needsSynthesis || needsPersonaUpdate {
@@ -843,13 +1397,53 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 			annotations["langop.io/synthesized-at"] = existingTimestamp
 		}
 	}
+	// Annotations are merged into the existing ConfigMap, so new code explicitly clears the rollback mark
+	if needsSynthesis && existingCM.Annotations["langop.io/self-healing-rollback"] == "true" {
+		annotations["langop.io/self-healing-rollback"] = "false"
+	}
 
 	if err := CreateOrUpdateConfigMapWithAnnotations(ctx, r.Client, r.Scheme, agent, codeConfigMapName, agent.Namespace, data, annotations); err != nil {
 		return err
 	}
+	if codeDrift && r.correctsDrift() {
+		r.recordDrift(agent, "ConfigMap", codeConfigMapName, true)
+	}
+	if existingCM.ResourceVersion != "" && existingCM.Data["agent.rb"] != dslCode {
+		if err := r.deliverCode(ctx, agent, dslCode); err != nil {
+			log.Error(err, "Failed to restart agent pods after code change")
+		}
+	}
+	if needsSynthesis && !cacheHit {
+		r.Audit.Emit(ctx, auditControllerLanguageAgent, audit.ActionSynthesized, agent, "Agent code synthesized",
+			map[string]string{
+				"configMap":        codeConfigMapName,
+				"instructionsHash": annotations["langop.io/instructions-hash"],
+			})
+	}
+
+	r.recordToolUsage(ctx, agent, dslCode)
+
+	// Documentation is informational, so failing to generate it doesn't hold up the agent
+	if err := r.reconcileAgentDocs(ctx, agent, dslCode); err != nil {
+		log.Error(err, "Failed to reconcile agent documentation")
+	}
+
+	// Plans are previews, so failing to produce one doesn't hold up the agent either
+	if err := r.reconcileSynthesisPlan(ctx, agent, dslCode); err != nil {
+		log.Error(err, "Failed to reconcile synthesis plan")
+	}
+
+	// A forced workload type takes precedence over the mode detected in the code
+	if workload := forcedWorkload(agent); workload != "" {
+		log.V(1).Info("Workload type forced by annotation, skipping executionMode auto-detection",
+			"agent", agent.Name,
+			"workload", workload)
+		return nil
+	}
 
 	// Parse DSL to extract mode and schedule, then update spec if needed
 	detectedMode, detectedSchedule := parseDSLMode(dslCode)
+	previousMode := agent.Spec.ExecutionMode
 	specNeedsUpdate := false
 
 	// Check if executionMode needs to be updated
@@ -862,6 +1456,25 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 		specNeedsUpdate = true
 	}
 
+	// Don't adopt a synthesized schedule that runs more often than the cluster allows
+	if detectedMode == "scheduled" && detectedSchedule != "" && agent.Spec.Schedule != detectedSchedule {
+		minInterval, err := r.getMinScheduleInterval(ctx, agent)
+		if err != nil {
+			return err
+		}
+		if err := validateScheduleInterval(detectedSchedule, minInterval); err != nil {
+			log.Info("Ignoring auto-detected schedule that violates cluster policy",
+				"agent", agent.Name,
+				"detectedSchedule", detectedSchedule,
+				"reason", err.Error())
+			if r.Recorder != nil {
+				r.Recorder.Eventf(agent, corev1.EventTypeWarning, "ScheduleTooFrequent",
+					"Ignored synthesized schedule: %s", err.Error())
+			}
+			detectedSchedule = ""
+		}
+	}
+
 	// Check if schedule needs to be updated (only for scheduled mode)
 	if detectedMode == "scheduled" && detectedSchedule != "" && agent.Spec.Schedule != detectedSchedule {
 		log.Info("Auto-detected schedule from synthesized DSL",
@@ -872,12 +1485,30 @@ func (r *LanguageAgentReconciler) reconcileCodeConfigMap(ctx context.Context, ag
 		specNeedsUpdate = true
 	}
 
+	// The webhook only accepts a schedule together with scheduled mode, so a mode change must
+	// carry a consistent schedule or the update is rejected
+	if specNeedsUpdate && detectedMode == "scheduled" && agent.Spec.Schedule == "" {
+		log.Info("Ignoring auto-detected scheduled mode without a schedule", "agent", agent.Name)
+		agent.Spec.ExecutionMode = previousMode
+		if r.Recorder != nil {
+			r.Recorder.Event(agent, corev1.EventTypeWarning, "ScheduleMissing",
+				"Synthesized code declares scheduled mode without a usable schedule; set spec.schedule to run it as a CronJob")
+		}
+		return nil
+	}
+	if specNeedsUpdate && detectedMode != "scheduled" && agent.Spec.Schedule != "" {
+		agent.Spec.Schedule = ""
+	}
+
 	// Update the agent spec if changes were detected
 	if specNeedsUpdate {
+		// The update response carries the stored status; keep the status changes not yet written
+		status := agent.Status.DeepCopy()
 		if err := r.Update(ctx, agent); err != nil {
 			log.Error(err, "Failed to update agent spec with auto-detected mode and schedule")
 			return err
 		}
+		agent.Status = *status
 		log.Info("Agent spec updated with auto-detected execution mode and schedule",
 			"agent", agent.Name,
 			"executionMode", agent.Spec.ExecutionMode,
@@ -903,7 +1534,7 @@ func (r *LanguageAgentReconciler) distillPersona(ctx context.Context, persona *l
 
 	agentCtx := synthesis.AgentContext{
 		AgentName:    agent.Name,
-		Instructions: agent.Spec.Instructions,
+		Instructions: r.synthesisInstructions(agent),
 		Tools:        strings.Join(r.getToolNames(agent), ", "),
 	}
 
@@ -913,13 +1544,18 @@ func (r *LanguageAgentReconciler) distillPersona(ctx context.Context, persona *l
 		return "", fmt.Errorf("failed to create synthesizer for persona distillation: %w", err)
 	}
 
+	if err := r.SynthesisSlots.Acquire(ctx, agent.Namespace, agent.Name); err != nil {
+		return "", fmt.Errorf("failed to acquire synthesis slot: %w", err)
+	}
+	defer r.SynthesisSlots.Release()
+
 	return synthesizer.DistillPersona(ctx, personaInfo, agentCtx)
 }
 
 // getToolNames extracts tool names from agent's toolRefs
 func (r *LanguageAgentReconciler) getToolNames(agent *langopv1alpha1.LanguageAgent) []string {
 	var names []string
-	for _, ref := range agent.Spec.ToolRefs {
+	for _, ref := range agentToolRefs(agent) {
 		names = append(names, ref.Name)
 	}
 	return names
@@ -929,7 +1565,7 @@ func (r *LanguageAgentReconciler) getToolNames(agent *langopv1alpha1.LanguageAge
 func (r *LanguageAgentReconciler) getToolSchemas(ctx context.Context, agent *langopv1alpha1.LanguageAgent) []langopv1alpha1.ToolSchema {
 	var allSchemas []langopv1alpha1.ToolSchema
 
-	for _, ref := range agent.Spec.ToolRefs {
+	for _, ref := range agentToolRefs(agent) {
 		// Get the LanguageTool CR
 		tool := &langopv1alpha1.LanguageTool{}
 		err := r.Get(ctx, types.NamespacedName{
@@ -996,24 +1632,92 @@ func (r *LanguageAgentReconciler) getSynthesisModel(ctx context.Context, agent *
 	return model, nil
 }
 
-// createSynthesizer creates a synthesizer from the agent's model
+// createSynthesizer creates a synthesizer from the agent's model, or from the cluster's
+// synthesis fallback chain when the agent's model is unavailable
 func (r *LanguageAgentReconciler) createSynthesizer(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (synthesis.AgentSynthesizer, string, error) {
-	model, err := r.getSynthesisModel(ctx, agent)
+	model, fallback, err := r.resolveSynthesisModel(ctx, agent)
 	if err != nil {
 		return nil, "", err
 	}
+	r.recordSynthesisFallback(agent, fallback)
 
-	synth, err := synthesis.NewSynthesizerFromLanguageModel(ctx, r.Client, model, r.Log.WithName("synthesis"))
+	synth, err := r.newSynthesizer(ctx, agent, model)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to create synthesizer: %w", err)
 	}
-
 	return synth, model.Spec.ModelName, nil
 }
 
-// getPersonaNames extracts persona names from agent's personaRefs
+// newSynthesizer creates a synthesizer for the agent from the given model. The agent's
+// spec.synthesis overrides the model's generation parameters.
+func (r *LanguageAgentReconciler) newSynthesizer(ctx context.Context, agent *langopv1alpha1.LanguageAgent, model *langopv1alpha1.LanguageModel) (synthesis.AgentSynthesizer, error) {
+	params := synthesis.ResolveGenerationParameters(model, agent.Spec.Synthesis)
+	synth, err := synthesis.NewSynthesizerWithParameters(ctx, r.Client, model, modelRequestTimeout(agent), params, r.Log.WithName("synthesis"))
+	if err != nil {
+		return nil, err
+	}
+	recordGenerationParameters(agent, params)
+
+	if r.SynthesisRedactor != nil {
+		return &synthesis.RedactingSynthesizer{Synthesizer: synth, Redactor: r.SynthesisRedactor}, nil
+	}
+	return synth, nil
+}
+
+// recordGenerationParameters records in status the temperature and output token limit the agent
+// is synthesized with, leaving out those left to the provider
+func recordGenerationParameters(agent *langopv1alpha1.LanguageAgent, params synthesis.GenerationParameters) {
+	if agent.Status.SynthesisInfo == nil {
+		agent.Status.SynthesisInfo = &langopv1alpha1.SynthesisInfo{}
+	}
+	agent.Status.SynthesisInfo.Temperature = nil
+	if params.Temperature != nil {
+		temperature := *params.Temperature
+		agent.Status.SynthesisInfo.Temperature = &temperature
+	}
+	agent.Status.SynthesisInfo.MaxTokens = 0
+	if params.MaxTokens != nil {
+		agent.Status.SynthesisInfo.MaxTokens = *params.MaxTokens
+	}
+}
+
+// recordRedaction records in status how many values were redacted from the synthesis request
+// that produced resp, so it is visible that the synthesis model never saw them
+func (r *LanguageAgentReconciler) recordRedaction(agent *langopv1alpha1.LanguageAgent, resp *synthesis.AgentSynthesisResponse) {
+	agent.Status.SynthesisInfo.RedactedValues = int32(resp.Redactions)
+	if resp.Redactions > 0 && r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeNormal, "SynthesisInputRedacted",
+			"Redacted %d sensitive value(s) from the synthesis request", resp.Redactions)
+	}
+}
+
+// recordPromptTrim records in status which prompt sections were trimmed from the synthesis
+// request that produced resp to fit the synthesis model's context window
+func (r *LanguageAgentReconciler) recordPromptTrim(agent *langopv1alpha1.LanguageAgent, resp *synthesis.AgentSynthesisResponse) {
+	agent.Status.SynthesisInfo.PromptTrimmed = resp.PromptTrimmed
+	if len(resp.PromptTrimmed) > 0 && r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeNormal, "SynthesisPromptTrimmed",
+			"Trimmed the synthesis prompt to fit the model's context window: %s", strings.Join(resp.PromptTrimmed, ", "))
+	}
+}
+
+// modelRequestTimeout returns the agent's spec.modelRequestTimeout, or zero if it is unset or invalid
+func modelRequestTimeout(agent *langopv1alpha1.LanguageAgent) time.Duration {
+	if agent.Spec.ModelRequestTimeout == "" {
+		return 0
+	}
+	timeout, err := time.ParseDuration(agent.Spec.ModelRequestTimeout)
+	if err != nil || timeout < 0 {
+		return 0
+	}
+	return timeout
+}
+
+// getPersonaNames extracts persona names from agent's default personas and personaRefs
 func (r *LanguageAgentReconciler) getPersonaNames(agent *langopv1alpha1.LanguageAgent) []string {
 	var names []string
+	// Default personas compose first, so they change the hash like any other persona
+	names = append(names, agent.Status.DefaultPersonas...)
 	for _, ref := range agent.Spec.PersonaRefs {
 		names = append(names, ref.Name)
 	}
@@ -1155,6 +1859,17 @@ func (r *LanguageAgentReconciler) buildContainerSecurityContext() *corev1.Securi
 	}
 }
 
+// codeVolumeConfigMapName returns the ConfigMap whose code the agent's pods run: the versioned
+// ConfigMap of its pinned code version, or the latest synthesized code when it isn't pinned
+func codeVolumeConfigMapName(agent *langopv1alpha1.LanguageAgent) string {
+	if value := agent.Annotations[langopv1alpha1.PinnedCodeVersionAnnotation]; value != "" {
+		if version, err := langopv1alpha1.ParsePinnedCodeVersion(value); err == nil {
+			return fmt.Sprintf("%s-v%d", agent.Name, version)
+		}
+	}
+	return GenerateConfigMapName(agent.Name, "code")
+}
+
 // buildVolumes creates the volumes and volume mounts for agent pods
 func (r *LanguageAgentReconciler) buildVolumes(agent *langopv1alpha1.LanguageAgent) ([]corev1.Volume, []corev1.VolumeMount) {
 	volumes := []corev1.Volume{}
@@ -1204,8 +1919,8 @@ func (r *LanguageAgentReconciler) buildVolumes(agent *langopv1alpha1.LanguageAge
 	})
 
 	// Add code ConfigMap volume if agent has modelRefs and instructions (synthesis enabled)
-	if len(agent.Spec.ModelRefs) > 0 && agent.Spec.Instructions != "" {
-		codeConfigMapName := GenerateConfigMapName(agent.Name, "code")
+	if r.usesSynthesizedCode(agent) {
+		codeConfigMapName := codeVolumeConfigMapName(agent)
 		volumes = append(volumes, corev1.Volume{
 			Name: "agent-code",
 			VolumeSource: corev1.VolumeSource{
@@ -1243,6 +1958,7 @@ func (r *LanguageAgentReconciler) buildVolumes(agent *langopv1alpha1.LanguageAge
 			Name:      "workspace",
 			MountPath: mountPath,
 		})
+		volumes = append(volumes, workspaceSeedVolumes(agent)...)
 	}
 
 	return volumes, volumeMounts
@@ -1259,7 +1975,7 @@ func (r *LanguageAgentReconciler) reconcileDeployment(ctx context.Context, agent
 	}
 
 	// Resolve model URLs and names
-	modelURLs, modelNames, err := r.resolveModels(ctx, agent)
+	models, err := r.resolveModels(ctx, agent)
 	if err != nil {
 		return fmt.Errorf("failed to resolve models: %w", err)
 	}
@@ -1275,6 +1991,31 @@ func (r *LanguageAgentReconciler) reconcileDeployment(ctx context.Context, agent
 	if err != nil {
 		return fmt.Errorf("failed to resolve sidecar tools: %w", err)
 	}
+	if err := r.checkSidecarPorts(agent, sidecarContainers); err != nil {
+		return err
+	}
+
+	image, err := r.resolveAgentImage(ctx, agent)
+	if err != nil {
+		return err
+	}
+	pullPolicy, err := r.resolveImagePullPolicy(ctx, agent)
+	if err != nil {
+		return err
+	}
+	for i := range sidecarContainers {
+		sidecarContainers[i].ImagePullPolicy = pullPolicy
+	}
+	resourceDefaults, err := r.resolveResourceDefaults(ctx, agent)
+	if err != nil {
+		return err
+	}
+
+	// Roll the pods when the code or config they run changes; ConfigMap volumes alone don't
+	checksums, err := r.podTemplateChecksums(ctx, agent, persona)
+	if err != nil {
+		return err
+	}
 
 	// Determine target namespace and labels
 	targetNamespace := agent.Namespace
@@ -1298,11 +2039,23 @@ func (r *LanguageAgentReconciler) reconcileDeployment(ctx context.Context, agent
 		},
 	}
 
+	drifted := false
+	liveHash := ""
+	restartQueued := false
+	restarts := r.getRestartCoordinator()
+	agentKey := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
 	_, err = controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
 		if err := controllerutil.SetControllerReference(agent, deployment, r.Scheme); err != nil {
 			return err
 		}
 
+		// Fingerprint the live spec before it is overwritten to detect external edits
+		liveSpec := deployment.Spec.DeepCopy()
+		rolledOut := deploymentRolledOut(deployment)
+		appliedHash := deployment.Annotations[appliedHashAnnotation]
+		liveHash = deploymentFingerprint(liveSpec)
+		drifted = deployment.ResourceVersion != "" && appliedHash != "" && liveHash != appliedHash
+
 		replicas := int32(1)
 		if agent.Spec.Replicas != nil {
 			replicas = *agent.Spec.Replicas
@@ -1311,9 +2064,11 @@ func (r *LanguageAgentReconciler) reconcileDeployment(ctx context.Context, agent
 		// Build container list starting with the agent
 		containers := []corev1.Container{
 			{
-				Name:  "agent",
-				Image: agent.Spec.Image,
-				Env:   r.buildAgentEnv(ctx, agent, modelURLs, modelNames, toolURLs, persona),
+				Name:            "agent",
+				Image:           image,
+				ImagePullPolicy: pullPolicy,
+				Ports:           agentContainerPorts(agent),
+				Env:             r.buildAgentEnv(ctx, agent, models, toolURLs, persona),
 			},
 		}
 
@@ -1322,9 +2077,11 @@ func (r *LanguageAgentReconciler) reconcileDeployment(ctx context.Context, agent
 			Selector: &metav1.LabelSelector{
 				MatchLabels: labels,
 			},
+			Strategy: agentDeploymentStrategy(agent),
 			Template: corev1.PodTemplateSpec{
 				ObjectMeta: metav1.ObjectMeta{
-					Labels: labels,
+					Labels:      labels,
+					Annotations: checksums,
 				},
 				Spec: corev1.PodSpec{
 					ShareProcessNamespace: &[]bool{len(sidecarContainers) > 0}[0],
@@ -1338,8 +2095,18 @@ func (r *LanguageAgentReconciler) reconcileDeployment(ctx context.Context, agent
 		// Add container security context for agent container
 		deployment.Spec.Template.Spec.Containers[0].SecurityContext = r.buildContainerSecurityContext()
 
-		// Add resource requirements if specified
+		// Seed the workspace before the sidecar tools start
+		r.applyWorkspaceSeed(agent, &deployment.Spec.Template.Spec)
+
+		// Add resource requirements, filling in the cluster and operator defaults
 		deployment.Spec.Template.Spec.Containers[0].Resources = agent.Spec.Resources
+		applyResourceDefaults(&deployment.Spec.Template.Spec, resourceDefaults)
+
+		// Give slow-starting agents time to initialize before other probes apply
+		deployment.Spec.Template.Spec.Containers[0].StartupProbe = buildAgentStartupProbe(agent)
+
+		// Drain webhook traffic before the agent exits so rolling updates don't drop requests
+		applyAgentWebhookDraining(agent, &deployment.Spec.Template.Spec)
 
 		// Build and apply volumes and volume mounts
 		volumes, volumeMounts := r.buildVolumes(agent)
@@ -1350,10 +2117,161 @@ func (r *LanguageAgentReconciler) reconcileDeployment(ctx context.Context, agent
 			deployment.Spec.Template.Spec.Containers[0].VolumeMounts = volumeMounts
 		}
 
+		// Keep rollout restarts requested by kubectl or a failed warm reload
+		if restartedAt, ok := liveSpec.Template.Annotations[restartedAtAnnotation]; ok {
+			if deployment.Spec.Template.Annotations == nil {
+				deployment.Spec.Template.Annotations = make(map[string]string)
+			}
+			deployment.Spec.Template.Annotations[restartedAtAnnotation] = restartedAt
+		}
+
+		// Stagger rollouts: keep the running pod template until a restart slot is free
+		templateHeld := false
+		if deployment.ResourceVersion != "" {
+			if !podTemplateChanged(deployment, liveSpec, drifted && r.correctsDrift()) {
+				restarts.Cancel(agentKey)
+				if rolledOut {
+					restarts.Complete(agentKey)
+				}
+			} else if admitted, queued := restarts.Admit(agentKey); !admitted {
+				deployment.Spec.Template = liveSpec.Template
+				restartQueued = queued
+				templateHeld = true
+			}
+		}
+
+		desiredHash := deploymentFingerprint(&deployment.Spec)
+		if drifted && !r.correctsDrift() && desiredHash == appliedHash {
+			// Nothing the operator manages changed, so keep the external edit
+			deployment.Spec = *liveSpec
+			return nil
+		}
+
+		if deployment.Annotations == nil {
+			deployment.Annotations = make(map[string]string)
+		}
+		deployment.Annotations[appliedHashAnnotation] = desiredHash
+		if !templateHeld {
+			deployment.Annotations[podTemplateHashAnnotation] = podTemplateFingerprint(&deployment.Spec.Template)
+		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	if drifted {
+		log.Info("Deployment drifted from the operator's intended state", "deployment", deployment.Name, "policy", r.DriftPolicy)
+	}
+	if r.correctsDrift() {
+		if drifted {
+			r.recordDrift(agent, "Deployment", deployment.Name, true)
+		}
+	} else {
+		driftState := ""
+		if drifted {
+			driftState = liveHash
+		}
+		r.warnDriftTransition(agent, "Deployment", deployment.Name, driftState)
+	}
+	if restartQueued {
+		log.Info("Deferring Deployment rollout until a restart slot is free", "deployment", deployment.Name,
+			"maxConcurrentRestarts", r.MaxConcurrentAgentRestarts)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(agent, corev1.EventTypeNormal, "RestartDeferred",
+				"Deployment rollout queued: at most %d agents restart at once", r.MaxConcurrentAgentRestarts)
+		}
+	}
+	return nil
+}
+
+// usesSynthesizedCode reports whether the agent runs synthesized code mounted from its code ConfigMap
+func (r *LanguageAgentReconciler) usesSynthesizedCode(agent *langopv1alpha1.LanguageAgent) bool {
+	return len(agent.Spec.ModelRefs) > 0 && r.synthesisInstructions(agent) != ""
+}
+
+// synthesisInstructions returns the instructions code is synthesized from. When spec.instructions
+// is empty and the GoalAsInstructions feature gate is enabled, spec.goal is used instead.
+func (r *LanguageAgentReconciler) synthesisInstructions(agent *langopv1alpha1.LanguageAgent) string {
+	if agent.Spec.Instructions == "" && r.featureGateEnabled(agent, langopv1alpha1.FeatureGateGoalAsInstructions) {
+		return agent.Spec.Goal
+	}
+	return agent.Spec.Instructions
+}
+
+// codeConfigMapExists reports whether the code ConfigMap mounted by the agent's workload exists.
+// Agents that don't use synthesized code always report true.
+func (r *LanguageAgentReconciler) codeConfigMapExists(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (bool, error) {
+	if !r.usesSynthesizedCode(agent) {
+		return true, nil
+	}
+
+	codeConfigMap := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace}, codeConfigMap)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get code ConfigMap: %w", err)
+	}
+	return true, nil
+}
+
+// cronJobSchedule returns the schedule used for the agent's CronJob, defaulting to hourly
+func cronJobSchedule(agent *langopv1alpha1.LanguageAgent) string {
+	if agent.Spec.Schedule != "" {
+		return agent.Spec.Schedule
+	}
+	return "0 * * * *"
+}
+
+// checkDependencies reports whether every agent listed in spec.dependsOn exists and is Ready.
+// When not ready, the returned message names the first dependency being waited on.
+func (r *LanguageAgentReconciler) checkDependencies(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (bool, string, error) {
+	for _, dep := range agent.Spec.DependsOn {
+		namespace := dep.Namespace
+		if namespace == "" {
+			namespace = agent.Namespace
+		}
+
+		dependency := &langopv1alpha1.LanguageAgent{}
+		if err := r.Get(ctx, types.NamespacedName{Name: dep.Name, Namespace: namespace}, dependency); err != nil {
+			if errors.IsNotFound(err) {
+				return false, fmt.Sprintf("Dependency %s/%s not found", namespace, dep.Name), nil
+			}
+			return false, "", fmt.Errorf("failed to get dependency %s/%s: %w", namespace, dep.Name, err)
+		}
+
+		if !meta.IsStatusConditionTrue(dependency.Status.Conditions, "Ready") {
+			return false, fmt.Sprintf("Waiting for dependency %s/%s to become Ready", namespace, dep.Name), nil
+		}
+	}
+
+	return true, "", nil
+}
+
+// buildAgentStartupProbe returns the agent's configured startup probe. Interactive agents, the only
+// ones listening on the webhook port, default to a TCP probe on it that tolerates up to 5 minutes of
+// initialization; other agents get no default probe.
+func buildAgentStartupProbe(agent *langopv1alpha1.LanguageAgent) *corev1.Probe {
+	if agent.Spec.StartupProbe != nil {
+		return agent.Spec.StartupProbe.DeepCopy()
+	}
+	if agent.Spec.ExecutionMode != "interactive" {
+		return nil
+	}
+
+	return &corev1.Probe{
+		ProbeHandler: corev1.ProbeHandler{
+			TCPSocket: &corev1.TCPSocketAction{
+				Port: intstr.FromInt(agentWebhookPort),
+			},
+		},
+		PeriodSeconds:    10,
+		TimeoutSeconds:   1,
+		SuccessThreshold: 1,
+		FailureThreshold: 30,
+	}
 }
 
 func (r *LanguageAgentReconciler) reconcileCronJob(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
@@ -1367,7 +2285,7 @@ func (r *LanguageAgentReconciler) reconcileCronJob(ctx context.Context, agent *l
 	}
 
 	// Resolve model URLs and names
-	modelURLs, modelNames, err := r.resolveModels(ctx, agent)
+	models, err := r.resolveModels(ctx, agent)
 	if err != nil {
 		return fmt.Errorf("failed to resolve models: %w", err)
 	}
@@ -1383,6 +2301,25 @@ func (r *LanguageAgentReconciler) reconcileCronJob(ctx context.Context, agent *l
 	if err != nil {
 		return fmt.Errorf("failed to resolve sidecar tools: %w", err)
 	}
+	if err := r.checkSidecarPorts(agent, sidecarContainers); err != nil {
+		return err
+	}
+
+	image, err := r.resolveAgentImage(ctx, agent)
+	if err != nil {
+		return err
+	}
+	pullPolicy, err := r.resolveImagePullPolicy(ctx, agent)
+	if err != nil {
+		return err
+	}
+	for i := range sidecarContainers {
+		sidecarContainers[i].ImagePullPolicy = pullPolicy
+	}
+	resourceDefaults, err := r.resolveResourceDefaults(ctx, agent)
+	if err != nil {
+		return err
+	}
 
 	// Determine target namespace and labels
 	targetNamespace := agent.Namespace
@@ -1411,17 +2348,15 @@ func (r *LanguageAgentReconciler) reconcileCronJob(ctx context.Context, agent *l
 			return err
 		}
 
-		schedule := "0 * * * *" // Default: hourly
-		if agent.Spec.Schedule != "" {
-			schedule = agent.Spec.Schedule
-		}
+		schedule := cronJobSchedule(agent)
 
 		// Build container list starting with the agent
 		containers := []corev1.Container{
 			{
-				Name:  "agent",
-				Image: agent.Spec.Image,
-				Env:   r.buildAgentEnv(ctx, agent, modelURLs, modelNames, toolURLs, persona),
+				Name:            "agent",
+				Image:           image,
+				ImagePullPolicy: pullPolicy,
+				Env:             r.buildAgentEnv(ctx, agent, models, toolURLs, persona),
 			},
 		}
 
@@ -1448,8 +2383,12 @@ func (r *LanguageAgentReconciler) reconcileCronJob(ctx context.Context, agent *l
 		// Add container security context for agent container
 		cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].SecurityContext = r.buildContainerSecurityContext()
 
-		// Add resource requirements if specified
+		// Seed the workspace before the sidecar tools start
+		r.applyWorkspaceSeed(agent, &cronJob.Spec.JobTemplate.Spec.Template.Spec)
+
+		// Add resource requirements, filling in the cluster and operator defaults
 		cronJob.Spec.JobTemplate.Spec.Template.Spec.Containers[0].Resources = agent.Spec.Resources
+		applyResourceDefaults(&cronJob.Spec.JobTemplate.Spec.Template.Spec, resourceDefaults)
 
 		// Build and apply volumes and volume mounts
 		volumes, volumeMounts := r.buildVolumes(agent)
@@ -1462,8 +2401,12 @@ func (r *LanguageAgentReconciler) reconcileCronJob(ctx context.Context, agent *l
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	// Run once now if the agent was annotated with a new trigger
+	return r.reconcileManualRun(ctx, agent, cronJob)
 }
 
 func (r *LanguageAgentReconciler) reconcileNetworkPolicy(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
@@ -1473,6 +2416,12 @@ func (r *LanguageAgentReconciler) reconcileNetworkPolicy(ctx context.Context, ag
 	// This ensures agents can send traces to the collector
 	otelEndpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
 
+	// The cluster's default egress applies alongside the agent's own rules
+	egress, err := r.resolveEgress(ctx, agent)
+	if err != nil {
+		return err
+	}
+
 	// Build NetworkPolicy using helper from utils.go
 	networkPolicy := BuildEgressNetworkPolicy(
 		agent.Name,
@@ -1481,18 +2430,52 @@ func (r *LanguageAgentReconciler) reconcileNetworkPolicy(ctx context.Context, ag
 		"", // provider - not applicable for agents
 		"", // endpoint - not applicable for agents
 		otelEndpoint,
-		agent.Spec.Egress,
+		egress,
 	)
 
 	// Create or update the NetworkPolicy with owner reference and configured timeout/retries
 	return CreateOrUpdateNetworkPolicyWithTimeout(ctx, r.Client, r.Scheme, agent, networkPolicy, r.NetworkPolicyTimeout, r.NetworkPolicyRetries)
 }
 
-func (r *LanguageAgentReconciler) resolveModels(ctx context.Context, agent *langopv1alpha1.LanguageAgent) ([]string, []string, error) {
-	var modelURLs []string
-	var modelNames []string
+// resolvedModels holds the proxy URLs, model names, and roles of an agent's models, ordered so
+// the agent tries primary models first and fails over to fallback models last
+type resolvedModels struct {
+	URLs  []string
+	Names []string
+	// Roles is index-aligned with URLs
+	Roles []string
+}
 
-	for _, modelRef := range agent.Spec.ModelRefs {
+// modelRoleRank orders primary models (and models without a role) first, specialized models
+// next, and fallback models last
+func modelRoleRank(role string) int {
+	switch role {
+	case "", langopv1alpha1.ModelRolePrimary:
+		return 0
+	case langopv1alpha1.ModelRoleFallback:
+		return 2
+	default:
+		return 1
+	}
+}
+
+// orderedModelRefs returns the model references in failover order, keeping the declared order
+// within each role
+func orderedModelRefs(refs []langopv1alpha1.ModelReference) []langopv1alpha1.ModelReference {
+	ordered := append([]langopv1alpha1.ModelReference(nil), refs...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return modelRoleRank(ordered[i].Role) < modelRoleRank(ordered[j].Role)
+	})
+	return ordered
+}
+
+func (r *LanguageAgentReconciler) resolveModels(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (resolvedModels, error) {
+	var endpoints []modelEndpoint
+	// Models force-deleted while referenced are skipped so the agent degrades instead of failing.
+	// Any other missing model, such as a newly added or mistyped reference, still fails resolution.
+	var missing []string
+
+	for _, modelRef := range orderedModelRefs(agent.Spec.ModelRefs) {
 		// Determine namespace
 		namespace := modelRef.Namespace
 		if namespace == "" {
@@ -1502,7 +2485,12 @@ func (r *LanguageAgentReconciler) resolveModels(ctx context.Context, agent *lang
 		// Fetch the LanguageModel
 		model := &langopv1alpha1.LanguageModel{}
 		if err := r.Get(ctx, types.NamespacedName{Name: modelRef.Name, Namespace: namespace}, model); err != nil {
-			return nil, nil, fmt.Errorf("failed to get model %s/%s: %w", namespace, modelRef.Name, err)
+			if key := namespace + "/" + modelRef.Name; errors.IsNotFound(err) && slices.Contains(agent.Status.DeletedModels, key) {
+				log.FromContext(ctx).Info("Skipping deleted model", "model", modelRef.Name, "modelNamespace", namespace)
+				missing = append(missing, key)
+				continue
+			}
+			return resolvedModels{}, fmt.Errorf("failed to get model %s/%s: %w", namespace, modelRef.Name, err)
 		}
 
 		// Build LiteLLM proxy URL
@@ -1510,22 +2498,111 @@ func (r *LanguageAgentReconciler) resolveModels(ctx context.Context, agent *lang
 		// TODO: Once LanguageModel controller creates Service, get actual port from service
 		port := 8000 // Default LiteLLM port
 
-		serviceURL := fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", model.Name, namespace, port)
-		modelURLs = append(modelURLs, serviceURL)
+		role := modelRef.Role
+		if role == "" {
+			role = langopv1alpha1.ModelRolePrimary
+		}
+
+		endpoints = append(endpoints, modelEndpoint{
+			key:         namespace + "/" + model.Name,
+			url:         fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", model.Name, namespace, port),
+			name:        model.Spec.ModelName,
+			role:        role,
+			unreachable: synthesisModelUnreachable(model),
+		})
+	}
+
+	// Forget deleted models that were recreated or are no longer referenced
+	agent.Status.DeletedModels = missing
+	if len(missing) > 0 {
+		// Report which models the agent is running without rather than skipping them silently
+		message := fmt.Sprintf("Running without deleted LanguageModels %s; recreate them or remove the references", strings.Join(missing, ", "))
+		if SetCondition(&agent.Status.Conditions, "ModelDeleted", metav1.ConditionTrue, "ModelsMissing", message, agent.Generation) && r.Recorder != nil {
+			r.Recorder.Event(agent, corev1.EventTypeWarning, "ModelsMissing", message)
+		}
+	} else {
+		// Every referenced model exists again (recreated or references updated)
+		meta.RemoveStatusCondition(&agent.Status.Conditions, "ModelDeleted")
+	}
+
+	var models resolvedModels
+	for _, endpoint := range r.routeModelsByHealth(ctx, agent, endpoints) {
+		models.URLs = append(models.URLs, endpoint.url)
+		models.Roles = append(models.Roles, endpoint.role)
 
 		// Collect model name from spec
-		if model.Spec.ModelName != "" {
-			modelNames = append(modelNames, model.Spec.ModelName)
+		if endpoint.name != "" {
+			models.Names = append(models.Names, endpoint.name)
+		}
+	}
+
+	return models, nil
+}
+
+// resolveImagePullPolicy returns the agent's pull policy, falling back to its cluster's default.
+// An empty policy leaves the choice to Kubernetes, which pulls :latest images on every start.
+func (r *LanguageAgentReconciler) resolveImagePullPolicy(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (corev1.PullPolicy, error) {
+	if agent.Spec.ImagePullPolicy != "" {
+		return agent.Spec.ImagePullPolicy, nil
+	}
+	if agent.Spec.ClusterRef == "" {
+		return "", nil
+	}
+
+	cluster := &langopv1alpha1.LanguageCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.ClusterRef, Namespace: agent.Namespace}, cluster); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
 		}
+		return "", fmt.Errorf("failed to get cluster %s: %w", agent.Spec.ClusterRef, err)
 	}
+	return cluster.Spec.ImagePullPolicy, nil
+}
 
-	return modelURLs, modelNames, nil
+// resolveLogLevel returns the agent's log level, falling back to its cluster's default log level.
+// An empty level leaves the runtime's own default in place.
+func (r *LanguageAgentReconciler) resolveLogLevel(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (string, error) {
+	if agent.Spec.LogLevel != "" {
+		return agent.Spec.LogLevel, nil
+	}
+	if agent.Spec.ClusterRef == "" {
+		return "", nil
+	}
+
+	cluster := &langopv1alpha1.LanguageCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.ClusterRef, Namespace: agent.Namespace}, cluster); err != nil {
+		if errors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to get cluster %s: %w", agent.Spec.ClusterRef, err)
+	}
+	return cluster.Spec.DefaultLogLevel, nil
+}
+
+// resolveAgentImage returns the agent's image, falling back to its cluster's default agent image.
+// Agents using the default are rolled to a new image when the cluster's default changes.
+func (r *LanguageAgentReconciler) resolveAgentImage(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (string, error) {
+	if agent.Spec.Image != "" {
+		return agent.Spec.Image, nil
+	}
+	if agent.Spec.ClusterRef == "" {
+		return "", fmt.Errorf("spec.image is required for agents without a clusterRef")
+	}
+
+	cluster := &langopv1alpha1.LanguageCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.ClusterRef, Namespace: agent.Namespace}, cluster); err != nil {
+		return "", fmt.Errorf("failed to get cluster %s: %w", agent.Spec.ClusterRef, err)
+	}
+	if cluster.Spec.DefaultAgentImage == "" {
+		return "", fmt.Errorf("spec.image is not set and cluster %s has no defaultAgentImage", agent.Spec.ClusterRef)
+	}
+	return cluster.Spec.DefaultAgentImage, nil
 }
 
 func (r *LanguageAgentReconciler) resolveSidecarTools(ctx context.Context, agent *langopv1alpha1.LanguageAgent) ([]corev1.Container, error) {
 	var sidecarContainers []corev1.Container
 
-	for _, toolRef := range agent.Spec.ToolRefs {
+	for _, toolRef := range agentToolRefs(agent) {
 		// Determine namespace
 		namespace := toolRef.Namespace
 		if namespace == "" {
@@ -1606,7 +2683,7 @@ func (r *LanguageAgentReconciler) resolveSidecarTools(ctx context.Context, agent
 func (r *LanguageAgentReconciler) resolveTools(ctx context.Context, agent *langopv1alpha1.LanguageAgent) ([]string, error) {
 	var toolURLs []string
 
-	for _, toolRef := range agent.Spec.ToolRefs {
+	for _, toolRef := range agentToolRefs(agent) {
 		// Determine namespace
 		namespace := toolRef.Namespace
 		if namespace == "" {
@@ -1632,16 +2709,17 @@ func (r *LanguageAgentReconciler) resolveTools(ctx context.Context, agent *lango
 			continue
 		}
 
-		// Build MCP server URL (service mode)
+		// Build MCP server URL (service mode, or the shared server of a cluster mode tool, which
+		// runs in the tool's namespace whichever namespace the agent is in)
 		// Format: http://<service-name>.<namespace>.svc.cluster.local:<port>
-		serviceURL := fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", tool.Name, namespace, port)
+		serviceURL := fmt.Sprintf("http://%s.%s.svc.cluster.local:%d", tool.Name, tool.Namespace, port)
 		toolURLs = append(toolURLs, serviceURL)
 	}
 
 	return toolURLs, nil
 }
 
-func (r *LanguageAgentReconciler) buildAgentEnv(ctx context.Context, agent *langopv1alpha1.LanguageAgent, modelURLs []string, modelNames []string, toolURLs []string, persona *langopv1alpha1.LanguagePersona) []corev1.EnvVar {
+func (r *LanguageAgentReconciler) buildAgentEnv(ctx context.Context, agent *langopv1alpha1.LanguageAgent, models resolvedModels, toolURLs []string, persona *langopv1alpha1.LanguagePersona) []corev1.EnvVar {
 	env := []corev1.EnvVar{
 		{
 			Name:  "CONFIG_PATH",
@@ -1669,8 +2747,14 @@ func (r *LanguageAgentReconciler) buildAgentEnv(ctx context.Context, agent *lang
 	// Agents use the collector endpoint for sending telemetry data
 	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"); endpoint != "" {
 		// Ruby OpenTelemetry exporter uses HTTP (port 4318) not gRPC (port 4317)
-		// Replace :4317 with :4318 for Ruby agents
-		agentEndpoint := strings.Replace(endpoint, ":4317", ":4318", 1)
+		// Replace :4317 with :4318 unless the agent runtime exports over gRPC
+		agentEndpoint := endpoint
+		protocol := "http/protobuf"
+		if agent.Spec.Telemetry != nil && agent.Spec.Telemetry.Protocol == langopv1alpha1.TelemetryProtocolGRPC {
+			protocol = "grpc"
+		} else {
+			agentEndpoint = strings.Replace(endpoint, ":4317", ":4318", 1)
+		}
 
 		// Ensure http:// protocol is present (required by Ruby OTLP exporter)
 		if !strings.HasPrefix(agentEndpoint, "http://") && !strings.HasPrefix(agentEndpoint, "https://") {
@@ -1688,21 +2772,22 @@ func (r *LanguageAgentReconciler) buildAgentEnv(ctx context.Context, agent *lang
 		})
 		env = append(env, corev1.EnvVar{
 			Name:  "OTEL_EXPORTER_OTLP_PROTOCOL",
-			Value: "http/protobuf",
+			Value: protocol,
 		})
 		env = append(env, corev1.EnvVar{
 			Name:  "OTEL_LOGS_EXPORTER",
 			Value: "otlp",
 		})
 
-		// Inject additional OTEL variables from operator environment if present
-		if resourceAttrs := os.Getenv("OTEL_RESOURCE_ATTRIBUTES"); resourceAttrs != "" {
+		// Metrics share the endpoint and protocol of traces and logs
+		if agent.Spec.Telemetry != nil && agent.Spec.Telemetry.MetricsEnabled {
 			env = append(env, corev1.EnvVar{
-				Name:  "OTEL_RESOURCE_ATTRIBUTES",
-				Value: resourceAttrs,
+				Name:  "OTEL_METRICS_EXPORTER",
+				Value: "otlp",
 			})
 		}
 
+		// Inject additional OTEL variables from operator environment if present
 		if sampler := os.Getenv("OTEL_TRACES_SAMPLER"); sampler != "" {
 			env = append(env, corev1.EnvVar{
 				Name:  "OTEL_TRACES_SAMPLER",
@@ -1724,6 +2809,14 @@ func (r *LanguageAgentReconciler) buildAgentEnv(ctx context.Context, agent *lang
 		Value: fmt.Sprintf("language-operator-agent-%s", agent.Name),
 	})
 
+	// Tag agent telemetry with operator-managed and user-configured resource attributes
+	if resourceAttrs := buildAgentResourceAttributes(agent); resourceAttrs != "" {
+		env = append(env, corev1.EnvVar{
+			Name:  "OTEL_RESOURCE_ATTRIBUTES",
+			Value: resourceAttrs,
+		})
+	}
+
 	if agent.Spec.Goal != "" {
 		env = append(env, corev1.EnvVar{
 			Name:  "AGENT_GOAL",
@@ -1758,29 +2851,61 @@ func (r *LanguageAgentReconciler) buildAgentEnv(ctx context.Context, agent *lang
 		}
 	}
 
-	// Add LiteLLM model proxy URLs (comma-separated)
-	if len(modelURLs) > 0 {
+	// Add LiteLLM model proxy URLs (comma-separated, primary first)
+	if len(models.URLs) > 0 {
 		env = append(env, corev1.EnvVar{
 			Name:  "MODEL_ENDPOINTS",
-			Value: strings.Join(modelURLs, ","),
+			Value: strings.Join(models.URLs, ","),
+		})
+		// Roles of the endpoints, index-aligned, so the agent knows which to fail over to
+		env = append(env, corev1.EnvVar{
+			Name:  "MODEL_ROLES",
+			Value: strings.Join(models.Roles, ","),
 		})
 	}
 
 	// Add model names (comma-separated)
 	// This tells the agent which model to request from the proxy
-	if len(modelNames) > 0 {
+	if len(models.Names) > 0 {
 		env = append(env, corev1.EnvVar{
 			Name:  "LLM_MODEL",
-			Value: strings.Join(modelNames, ","),
+			Value: strings.Join(models.Names, ","),
+		})
+	}
+
+	// Add the model request timeout in seconds, as RubyLLM's request_timeout expects
+	if timeout := modelRequestTimeout(agent); timeout > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "MODEL_REQUEST_TIMEOUT",
+			Value: strconv.FormatFloat(timeout.Seconds(), 'f', -1, 64),
 		})
 	}
 
 	// Add dummy API key for local proxies (LiteLLM doesn't need auth)
 	// RubyLLM requires an API key to be set, so we provide a placeholder
-	if len(modelURLs) > 0 {
+	if len(models.URLs) > 0 {
+		env = append(env, corev1.EnvVar{
+			Name:  "OPENAI_API_KEY",
+			Value: "sk-dummy-key-for-local-proxy",
+		})
+	}
+
+	// Set the runtime log level; a changed level rolls the pods with the new env. Agents without
+	// spec.logLevel that set the level in spec.env keep it rather than the cluster default.
+	logLevel, err := r.resolveLogLevel(ctx, agent)
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to resolve agent log level, using the runtime default", "agent", agent.Name)
+	}
+	envSetsLogLevel := slices.ContainsFunc(agent.Spec.Env, func(e corev1.EnvVar) bool {
+		return langopv1alpha1.IsLogLevelAgentEnvVar(e.Name)
+	})
+	if logLevel != "" && (agent.Spec.LogLevel != "" || !envSetsLogLevel) {
 		env = append(env, corev1.EnvVar{
-			Name:  "OPENAI_API_KEY",
-			Value: "sk-dummy-key-for-local-proxy",
+			Name:  "LOG_LEVEL",
+			Value: logLevel,
+		}, corev1.EnvVar{
+			Name:  "AGENT_LOG_LEVEL",
+			Value: logLevel,
 		})
 	}
 
@@ -1791,6 +2916,9 @@ func (r *LanguageAgentReconciler) buildAgentEnv(ctx context.Context, agent *lang
 		Value: "1",
 	})
 
+	// Tell the runtime to fail a fraction of runs when chaos testing is permitted
+	env = append(env, r.chaosEnv(ctx, agent)...)
+
 	// Add MCP tool server URLs (comma-separated)
 	if len(toolURLs) > 0 {
 		env = append(env, corev1.EnvVar{
@@ -1799,20 +2927,157 @@ func (r *LanguageAgentReconciler) buildAgentEnv(ctx context.Context, agent *lang
 		})
 	}
 
-	// Add environment variables from spec
-	env = append(env, agent.Spec.Env...)
+	// Add environment variables from spec. The webhook rejects new reserved names, but agents that
+	// set them before they were reserved must not shadow the operator's values, so those are
+	// dropped here and reported on the agent.
+	var ignored []string
+	for _, e := range agent.Spec.Env {
+		if langopv1alpha1.IsReservedAgentEnvVar(e.Name) ||
+			(agent.Spec.LogLevel != "" && langopv1alpha1.IsLogLevelAgentEnvVar(e.Name)) {
+			log.FromContext(ctx).Info("Ignoring reserved environment variable in spec.env", "agent", agent.Name, "name", e.Name)
+			ignored = append(ignored, e.Name)
+			continue
+		}
+		env = append(env, e)
+	}
+	r.reportIgnoredAgentEnv(agent, ignored)
 
 	return env
 }
 
+// buildAgentResourceAttributes renders the OTEL_RESOURCE_ATTRIBUTES value for an agent, or ""
+// when neither the operator environment nor spec.telemetry.resourceAttributes sets any attribute.
+// Attributes inherited from the operator environment are overridden by spec.telemetry.resourceAttributes,
+// which are in turn overridden by the operator-managed agent identity attributes. Keys are sorted so
+// the value is stable across reconciles and does not trigger spurious workload updates.
+func buildAgentResourceAttributes(agent *langopv1alpha1.LanguageAgent) string {
+	attrs := make(map[string]string)
+
+	// Inherited values are already encoded
+	for _, pair := range strings.Split(os.Getenv("OTEL_RESOURCE_ATTRIBUTES"), ",") {
+		key, value, found := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !found || key == "" {
+			continue
+		}
+		attrs[key] = strings.TrimSpace(value)
+	}
+
+	if agent.Spec.Telemetry != nil {
+		for key, value := range agent.Spec.Telemetry.ResourceAttributes {
+			attrs[key] = encodeResourceAttributeValue(value)
+		}
+	}
+
+	if len(attrs) == 0 {
+		return ""
+	}
+
+	attrs["k8s.namespace.name"] = encodeResourceAttributeValue(agent.Namespace)
+	attrs["langop.agent.name"] = encodeResourceAttributeValue(agent.Name)
+	attrs["langop.agent.uid"] = encodeResourceAttributeValue(string(agent.UID))
+
+	keys := make([]string, 0, len(attrs))
+	for key := range attrs {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, key+"="+attrs[key])
+	}
+	return strings.Join(pairs, ",")
+}
+
+// encodeResourceAttributeValue percent-encodes the characters an OTEL_RESOURCE_ATTRIBUTES value
+// can't hold verbatim: the ',' and '=' separators, '%' and anything outside printable ASCII
+func encodeResourceAttributeValue(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if c <= ' ' || c >= 0x7f || strings.IndexByte(`",;=\%`, c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+			continue
+		}
+		b.WriteByte(c)
+	}
+	return b.String()
+}
+
+// personaConstraints returns the composed persona constraints synthesized code must respect,
+// or nil when the agent has no persona or PersonaConstraintValidation is disabled
+func (r *LanguageAgentReconciler) personaConstraints(agent *langopv1alpha1.LanguageAgent, persona *langopv1alpha1.LanguagePersona) *langopv1alpha1.PersonaConstraints {
+	if persona == nil || !r.featureGateEnabled(agent, langopv1alpha1.FeatureGatePersonaConstraintValidation) {
+		return nil
+	}
+	return persona.Spec.Constraints
+}
+
+// synthesisExamples returns the examples of the agent's synthesis example set most relevant to
+// its instructions and tools. When the set can't be loaded, synthesis proceeds without examples.
+func (r *LanguageAgentReconciler) synthesisExamples(ctx context.Context, agent *langopv1alpha1.LanguageAgent, tools []string) []synthesis.SynthesisExample {
+	if agent.Spec.SynthesisExampleSet == "" {
+		return nil
+	}
+	examples, err := r.ExampleLibrary.Load(ctx, agent.Spec.SynthesisExampleSet)
+	if err != nil {
+		r.Log.Info("Synthesizing without examples", "agent", agent.Name, "exampleSet", agent.Spec.SynthesisExampleSet, "reason", err.Error())
+		if r.Recorder != nil {
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "SynthesisExampleSetUnavailable", "Synthesizing without examples: %v", err)
+		}
+		return nil
+	}
+	return synthesis.SelectExamples(examples, r.synthesisInstructions(agent), tools, synthesis.MaxSynthesisExamples)
+}
+
+// checkSynthesisCostEstimate rejects a synthesis request whose estimated cost exceeds the
+// remaining daily cost quota of the namespace or the agent
+func (r *LanguageAgentReconciler) checkSynthesisCostEstimate(ctx context.Context, agent *langopv1alpha1.LanguageAgent, req synthesis.AgentSynthesisRequest) error {
+	log := log.FromContext(ctx)
+
+	model, _, err := r.resolveSynthesisModel(ctx, agent)
+	if err != nil {
+		return err
+	}
+	// The agent's spec.synthesis.maxTokens bounds the output as much as the model's own setting
+	estimate, err := r.QuotaManager.EstimateCost(req, model, synthesis.ResolveGenerationParameters(model, agent.Spec.Synthesis))
+	if err != nil {
+		return fmt.Errorf("failed to estimate synthesis cost: %w", err)
+	}
+	if !estimate.Converted {
+		log.Info("No exchange rate for the model's currency, skipping synthesis cost estimate check",
+			"agent", agent.Name,
+			"currency", estimate.Currency)
+		return nil
+	}
+
+	if err := r.QuotaManager.CheckAgentCostQuota(ctx, agent.Namespace, agent.Name, agentQuotaLimits(agent), estimate.Cost); err != nil {
+		if r.Recorder != nil {
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "SynthesisBudgetExceeded",
+				"Estimated synthesis cost %.4f %s (%d input tokens) exceeds the remaining daily quota",
+				estimate.Cost, estimate.Currency, estimate.InputTokens)
+		}
+		log.Info("Synthesis cost estimate exceeds quota",
+			"agent", agent.Name,
+			"namespace", agent.Namespace,
+			"estimatedCost", estimate.Cost,
+			"inputTokens", estimate.InputTokens,
+			"outputTokens", estimate.OutputTokens)
+		synthesis.RecordSynthesisQuotaExceeded(agent.Namespace, "cost")
+		return &synthesis.QuotaExceededError{Err: &synthesis.CostEstimateExceededError{Estimate: estimate, Err: err}}
+	}
+	return nil
+}
+
 func (r *LanguageAgentReconciler) fetchPersona(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (*langopv1alpha1.LanguagePersona, error) {
-	// Return nil if no personas are referenced
-	if len(agent.Spec.PersonaRefs) == 0 {
-		return nil, nil
+	// Default personas form the base layer that the agent's own personas override
+	personas, err := r.fetchDefaultPersonas(ctx, agent)
+	if err != nil {
+		return nil, err
 	}
 
-	// Fetch all personas
-	var personas []*langopv1alpha1.LanguagePersona
+	// Fetch all referenced personas
 	for _, ref := range agent.Spec.PersonaRefs {
 		// Determine namespace
 		namespace := ref.Namespace
@@ -1933,6 +3198,11 @@ func (r *LanguageAgentReconciler) composePersonas(personas []*langopv1alpha1.Lan
 	return composed
 }
 
+// strictCleanupTimeout bounds how long spec.strictCleanup blocks deletion after it was requested
+const strictCleanupTimeout = 10 * time.Minute
+
+// cleanupResources deletes the agent's routes, services, and ReferenceGrants. Failures only block
+// deletion when spec.strictCleanup is set.
 func (r *LanguageAgentReconciler) cleanupResources(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
 	log := log.FromContext(ctx)
 	log.Info("Starting explicit resource cleanup", "agent", agent.Name, "namespace", agent.Namespace)
@@ -1980,8 +3250,27 @@ func (r *LanguageAgentReconciler) cleanupResources(ctx context.Context, agent *l
 		log.Error(err, "Cleanup error details")
 	}
 
-	// Don't block agent deletion for cleanup failures - log and continue
-	return nil
+	if !agent.Spec.StrictCleanup {
+		// Don't block agent deletion for cleanup failures - log and continue
+		return nil
+	}
+
+	cleanupErr := kerrors.NewAggregate(cleanupErrors)
+	if agent.DeletionTimestamp != nil && time.Since(agent.DeletionTimestamp.Time) >= strictCleanupTimeout {
+		log.Info("Strict cleanup timed out, removing finalizer anyway", "agent", agent.Name, "timeout", strictCleanupTimeout)
+		if r.Recorder != nil {
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "CleanupAbandoned",
+				"Cleanup did not succeed within %s, deleting anyway; resources may be orphaned: %v", strictCleanupTimeout, cleanupErr)
+		}
+		return nil
+	}
+
+	// Strict cleanup keeps the finalizer until every resource is gone
+	if r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeWarning, "CleanupFailed",
+			"Waiting for cleanup to succeed before deleting agent: %v", cleanupErr)
+	}
+	return fmt.Errorf("strict cleanup failed: %w", cleanupErr)
 }
 
 // cleanupHTTPRoutes deletes HTTPRoutes owned by the agent and verifies deletion
@@ -2169,18 +3458,11 @@ func (r *LanguageAgentReconciler) reconcileService(ctx context.Context, agent *l
 			return err
 		}
 
-		// All agents expose webhook server on port 8080
+		// All agents expose webhook server on port 8080, plus any other ports spec.webhookRoutes targets
 		service.Spec = corev1.ServiceSpec{
 			Selector: labels,
-			Ports: []corev1.ServicePort{
-				{
-					Name:       "http",
-					Port:       80,
-					TargetPort: intstr.FromInt(8080),
-					Protocol:   corev1.ProtocolTCP,
-				},
-			},
-			Type: corev1.ServiceTypeClusterIP,
+			Ports:    agentServicePorts(agent),
+			Type:     corev1.ServiceTypeClusterIP,
 		}
 
 		return nil
@@ -2195,6 +3477,7 @@ func (r *LanguageAgentReconciler) reconcileWebhooks(ctx context.Context, agent *
 
 	// Get the cluster to check for domain configuration
 	var domain string
+	var fallbackToIngress bool
 	if agent.Spec.ClusterRef != "" {
 		cluster := &langopv1alpha1.LanguageCluster{}
 		if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.ClusterRef, Namespace: agent.Namespace}, cluster); err != nil {
@@ -2205,11 +3488,15 @@ func (r *LanguageAgentReconciler) reconcileWebhooks(ctx context.Context, agent *
 			return err
 		}
 		domain = cluster.Spec.Domain
+		if cluster.Spec.IngressConfig != nil {
+			fallbackToIngress = cluster.Spec.IngressConfig.FallbackToIngress
+		}
 	}
 
 	// Skip webhook reconciliation if no domain is configured
 	if domain == "" {
 		log.Info("No domain configured, skipping webhook reconciliation")
+		agent.Status.WebhookRoute = nil
 		return nil
 	}
 
@@ -2226,30 +3513,65 @@ func (r *LanguageAgentReconciler) reconcileWebhooks(ctx context.Context, agent *
 
 	var routeReady bool
 	var routeReadyMsg string
+	useIngress := !hasGateway
 
 	if hasGateway {
 		log.Info("Gateway API detected, creating HTTPRoute", "hostname", hostname)
 		if err := r.reconcileHTTPRoute(ctx, agent, hostname); err != nil {
-			// Set WebhookRouteCreated condition to false on failure
-			SetCondition(&agent.Status.Conditions, langopv1alpha1.WebhookRouteCreatedCondition, metav1.ConditionFalse, "HTTPRouteCreationFailed", err.Error(), agent.Generation)
-			return fmt.Errorf("failed to reconcile HTTPRoute: %w", err)
-		}
-
-		// Set WebhookRouteCreated condition to true on success
-		SetCondition(&agent.Status.Conditions, langopv1alpha1.WebhookRouteCreatedCondition, metav1.ConditionTrue, "HTTPRouteCreated", "HTTPRoute created successfully", agent.Generation)
+			if !fallbackToIngress {
+				// Set WebhookRouteCreated condition to false on failure
+				SetCondition(&agent.Status.Conditions, langopv1alpha1.WebhookRouteCreatedCondition, metav1.ConditionFalse, "HTTPRouteCreationFailed", err.Error(), agent.Generation)
+				return fmt.Errorf("failed to reconcile HTTPRoute: %w", err)
+			}
 
-		// Check if HTTPRoute is ready
-		ready, msg, err := r.checkHTTPRouteReadiness(ctx, agent.Name, agent.Namespace)
-		if err != nil {
-			log.Error(err, "Failed to check HTTPRoute readiness")
-			routeReady = false
-			routeReadyMsg = fmt.Sprintf("Failed to check readiness: %v", err)
+			// Gateway API is present but the route could not be created (e.g. Gateway
+			// misconfiguration), so serve the webhook through an Ingress instead
+			log.Error(err, "HTTPRoute creation failed, falling back to Ingress", "hostname", hostname)
+			r.fallBackToIngress(agent, "HTTPRouteCreationFailed", fmt.Sprintf("HTTPRoute creation failed: %v", err))
+			useIngress = true
 		} else {
-			routeReady = ready
-			routeReadyMsg = msg
+			// Set WebhookRouteCreated condition to true on success
+			SetCondition(&agent.Status.Conditions, langopv1alpha1.WebhookRouteCreatedCondition, metav1.ConditionTrue, "HTTPRouteCreated", "HTTPRoute created successfully", agent.Generation)
+
+			// Check if HTTPRoute is ready
+			ready, accepted, msg, err := r.checkHTTPRouteReadiness(ctx, agent.Name, agent.Namespace)
+			if err != nil {
+				log.Error(err, "Failed to check HTTPRoute readiness")
+				routeReady = false
+				routeReadyMsg = fmt.Sprintf("Failed to check readiness: %v", err)
+			} else {
+				routeReady = ready
+				routeReadyMsg = msg
+			}
+
+			switch {
+			case routeReady:
+				// Once the HTTPRoute serves the webhook, the Ingress from an earlier fallback is stale
+				if err := r.deleteFallbackIngress(ctx, agent); err != nil {
+					return err
+				}
+				meta.RemoveStatusCondition(&agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition)
+			case fallbackToIngress && err == nil && !accepted:
+				// The route exists but no Gateway accepted it (e.g. no matching listener), so it
+				// won't serve the webhook; the Ingress does until the route is ready
+				log.Info("HTTPRoute not accepted, falling back to Ingress", "hostname", hostname, "reason", msg)
+				r.fallBackToIngress(agent, "HTTPRouteNotAccepted", msg)
+				useIngress = true
+			case meta.IsStatusConditionTrue(agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition):
+				// The Ingress from an earlier fallback keeps serving until the route is ready
+				useIngress = true
+			}
 		}
 	} else {
-		log.Info("Gateway API not available, creating Ingress fallback", "hostname", hostname)
+		meta.RemoveStatusCondition(&agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition)
+	}
+
+	if useIngress {
+		// Ingress-served webhooks aren't attached to a Gateway listener
+		agent.Status.WebhookRoute = nil
+		if !hasGateway {
+			log.Info("Gateway API not available, creating Ingress fallback", "hostname", hostname)
+		}
 		if err := r.reconcileIngress(ctx, agent, hostname); err != nil {
 			// Set WebhookRouteCreated condition to false on failure
 			SetCondition(&agent.Status.Conditions, langopv1alpha1.WebhookRouteCreatedCondition, metav1.ConditionFalse, "IngressCreationFailed", err.Error(), agent.Generation)
@@ -2292,7 +3614,7 @@ func (r *LanguageAgentReconciler) reconcileWebhooks(ctx context.Context, agent *
 	}
 
 	// Update agent status with conditions and potentially webhook URLs
-	if err := r.Status().Update(ctx, agent); err != nil {
+	if err := r.updateStatus(ctx, agent); err != nil {
 		log.Error(err, "Failed to update agent status")
 		return err
 	}
@@ -2341,81 +3663,11 @@ func (r *LanguageAgentReconciler) detectNetworkPolicySupport(ctx context.Context
 
 // hasGatewayAPI checks if Gateway API CRDs are available in the cluster with caching
 func (r *LanguageAgentReconciler) hasGatewayAPI(ctx context.Context) (bool, error) {
-	// Quick read lock check for cached result
-	r.gatewayCache.mutex.RLock()
-	if time.Since(r.gatewayCache.lastCheck) < gatewayAPICacheTTL {
-		available := r.gatewayCache.available
-		r.gatewayCache.mutex.RUnlock()
-		return available, nil
-	}
-	r.gatewayCache.mutex.RUnlock()
-
-	// Cache is stale, acquire write lock and refresh
-	r.gatewayCache.mutex.Lock()
-	defer r.gatewayCache.mutex.Unlock()
-
-	// Check again in case another goroutine already refreshed
-	if time.Since(r.gatewayCache.lastCheck) < gatewayAPICacheTTL {
-		return r.gatewayCache.available, nil
-	}
-
-	// Perform expensive discovery
-	available, err := r.discoverGatewayAPI(ctx)
-	if err != nil {
-		// Don't update cache on error, return stale data if available
-		if !r.gatewayCache.lastCheck.IsZero() {
-			return r.gatewayCache.available, nil
-		}
-		return false, err
-	}
-
-	// Update cache with fresh result
-	r.gatewayCache.available = available
-	r.gatewayCache.lastCheck = time.Now()
-	return available, nil
-}
-
-// discoverGatewayAPI performs the actual API discovery without caching
-func (r *LanguageAgentReconciler) discoverGatewayAPI(ctx context.Context) (bool, error) {
-	// Create a discovery client from the existing client
-	cfg, err := ctrl.GetConfig()
-	if err != nil {
-		return false, err
-	}
-
-	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
-	if err != nil {
-		return false, err
-	}
-
-	// Check if HTTPRoute CRD exists (gateway.networking.k8s.io/v1)
-	gvr := schema.GroupVersionResource{
-		Group:    "gateway.networking.k8s.io",
-		Version:  "v1",
-		Resource: "httproutes",
-	}
-
-	_, apiResourcesList, err := discoveryClient.ServerGroupsAndResources()
-	if err != nil {
-		// Partial errors are acceptable - some API groups might be unavailable
-		if discovery.IsGroupDiscoveryFailedError(err) {
-			// Continue with partial results
-		} else {
-			return false, err
-		}
-	}
-
-	for _, apiResources := range apiResourcesList {
-		if apiResources.GroupVersion == gvr.Group+"/"+gvr.Version {
-			for _, resource := range apiResources.APIResources {
-				if resource.Name == gvr.Resource {
-					return true, nil
-				}
-			}
-		}
+	detector := r.GatewayAPI
+	if detector == nil {
+		detector = SharedGatewayAPIDetector()
 	}
-
-	return false, nil
+	return detector.Available(ctx)
 }
 
 // reconcileReferenceGrant creates or updates a Gateway API ReferenceGrant for cross-namespace access
@@ -2598,7 +3850,7 @@ func (r *LanguageAgentReconciler) reconcileHTTPRoute(ctx context.Context, agent
 	labels := GetCommonLabels(agent.Name, "LanguageAgent")
 
 	// Get cluster config for Gateway configuration and TLS settings
-	var gatewayName, gatewayNamespace string
+	var gatewayName, gatewayNamespace, sectionName string
 	var tlsEnabled bool
 	if agent.Spec.ClusterRef != "" {
 		cluster := &langopv1alpha1.LanguageCluster{}
@@ -2623,6 +3875,7 @@ func (r *LanguageAgentReconciler) reconcileHTTPRoute(ctx context.Context, agent
 					gatewayName = cluster.Spec.IngressConfig.GatewayClassName
 					gatewayNamespace = agent.Namespace
 				}
+				sectionName = cluster.Spec.IngressConfig.GatewaySectionName
 			}
 		}
 	}
@@ -2634,7 +3887,7 @@ func (r *LanguageAgentReconciler) reconcileHTTPRoute(ctx context.Context, agent
 	}
 
 	// Validate Gateway TLS configuration and determine protocol
-	_, err := r.validateGatewayTLS(ctx, gatewayName, gatewayNamespace, tlsEnabled)
+	protocol, err := r.validateGatewayTLS(ctx, gatewayName, gatewayNamespace, tlsEnabled)
 	if err != nil {
 		return fmt.Errorf("Gateway TLS validation failed: %w", err)
 	}
@@ -2656,32 +3909,18 @@ func (r *LanguageAgentReconciler) reconcileHTTPRoute(ctx context.Context, agent
 	httpRoute.SetLabels(labels)
 
 	// Build HTTPRoute spec
+	parentRef := map[string]interface{}{
+		"name":      gatewayName,
+		"namespace": gatewayNamespace,
+	}
+	if sectionName != "" {
+		parentRef["sectionName"] = sectionName
+	}
+	// Unstructured content must be JSON-compatible, so lists are []interface{}
 	spec := map[string]interface{}{
-		"parentRefs": []map[string]interface{}{
-			{
-				"name":      gatewayName,
-				"namespace": gatewayNamespace,
-			},
-		},
-		"hostnames": []string{hostname},
-		"rules": []map[string]interface{}{
-			{
-				"matches": []map[string]interface{}{
-					{
-						"path": map[string]interface{}{
-							"type":  "PathPrefix",
-							"value": "/",
-						},
-					},
-				},
-				"backendRefs": []map[string]interface{}{
-					{
-						"name": agent.Name,
-						"port": int64(80),
-					},
-				},
-			},
-		},
+		"parentRefs": []interface{}{parentRef},
+		"hostnames":  []interface{}{hostname},
+		"rules":      httpRouteRules(agent),
 	}
 
 	// Check if HTTPRoute already exists
@@ -2713,9 +3952,50 @@ func (r *LanguageAgentReconciler) reconcileHTTPRoute(ctx context.Context, agent
 		}
 	}
 
+	// Record which Gateway listener the route is attached to
+	route := &langopv1alpha1.WebhookRouteStatus{
+		GatewayName:      gatewayName,
+		GatewayNamespace: gatewayNamespace,
+		SectionName:      sectionName,
+		Protocol:         protocol,
+	}
+	if agent.Status.WebhookRoute == nil || *agent.Status.WebhookRoute != *route {
+		agent.Status.WebhookRoute = route
+		log.Info("Updated webhook route attachment in status", "gateway", gatewayNamespace+"/"+gatewayName, "sectionName", sectionName, "protocol", protocol)
+	}
+
+	return nil
+}
+
+// deleteFallbackIngress deletes the agent's Ingress left behind by an Ingress fallback
+func (r *LanguageAgentReconciler) deleteFallbackIngress(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
+	ingress := &networkingv1.Ingress{}
+	if err := r.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, ingress); err != nil {
+		if errors.IsNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to get fallback Ingress: %w", err)
+	}
+	// Only delete an Ingress this agent created
+	if !metav1.IsControlledBy(ingress, agent) {
+		return nil
+	}
+	if err := r.Delete(ctx, ingress); err != nil && !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to delete fallback Ingress: %w", err)
+	}
+	log.FromContext(ctx).Info("Deleted fallback Ingress now that the HTTPRoute is ready", "ingress", ingress.Name)
 	return nil
 }
 
+// fallBackToIngress records that an Ingress serves the agent's webhook in place of its HTTPRoute
+func (r *LanguageAgentReconciler) fallBackToIngress(agent *langopv1alpha1.LanguageAgent, reason, cause string) {
+	wasFallback := meta.IsStatusConditionTrue(agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition)
+	SetCondition(&agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition, metav1.ConditionTrue, reason, fmt.Sprintf("Using Ingress because %s", cause), agent.Generation)
+	if r.Recorder != nil && !wasFallback {
+		r.Recorder.Eventf(agent, corev1.EventTypeWarning, "WebhookRouteFallback", "Falling back to Ingress: %s", cause)
+	}
+}
+
 // reconcileIngress creates or updates an Ingress for the agent (fallback when Gateway API unavailable)
 func (r *LanguageAgentReconciler) reconcileIngress(ctx context.Context, agent *langopv1alpha1.LanguageAgent, hostname string) error {
 	labels := GetCommonLabels(agent.Name, "LanguageAgent")
@@ -2733,27 +4013,13 @@ func (r *LanguageAgentReconciler) reconcileIngress(ctx context.Context, agent *l
 			return err
 		}
 
-		pathType := networkingv1.PathTypePrefix
 		ingress.Spec = networkingv1.IngressSpec{
 			Rules: []networkingv1.IngressRule{
 				{
 					Host: hostname,
 					IngressRuleValue: networkingv1.IngressRuleValue{
 						HTTP: &networkingv1.HTTPIngressRuleValue{
-							Paths: []networkingv1.HTTPIngressPath{
-								{
-									Path:     "/",
-									PathType: &pathType,
-									Backend: networkingv1.IngressBackend{
-										Service: &networkingv1.IngressServiceBackend{
-											Name: agent.Name,
-											Port: networkingv1.ServiceBackendPort{
-												Number: 80,
-											},
-										},
-									},
-								},
-							},
+							Paths: ingressPaths(agent),
 						},
 					},
 				},
@@ -2820,6 +4086,9 @@ func (r *LanguageAgentReconciler) performSelfHealingSynthesis(ctx context.Contex
 	var distilledPersona string
 	if persona != nil {
 		distilledPersona, err = r.distillPersona(ctx, persona, agent)
+		if synthesis.IsNoSynthesisSlot(err) {
+			return r.deferSelfHealing(agent, err)
+		}
 		if err != nil {
 			log.Error(err, "Failed to distill persona, continuing without it")
 			distilledPersona = ""
@@ -2840,7 +4109,7 @@ func (r *LanguageAgentReconciler) performSelfHealingSynthesis(ctx context.Contex
 
 	// Build synthesis request with error context
 	synthReq := synthesis.AgentSynthesisRequest{
-		Instructions:      agent.Spec.Instructions,
+		Instructions:      r.synthesisInstructions(agent),
 		Tools:             r.getToolNames(agent), // Kept for backward compatibility
 		ToolSchemas:       toolSchemas,           // Complete schemas for better synthesis
 		Models:            r.getModelNames(agent),
@@ -2851,6 +4120,9 @@ func (r *LanguageAgentReconciler) performSelfHealingSynthesis(ctx context.Contex
 		IsRetry:           true,
 		AttemptNumber:     agent.Status.SelfHealingAttempts,
 		LastKnownGoodCode: lastKnownGoodCode,
+		Examples:          r.synthesisExamples(ctx, agent, r.getToolNames(agent)),
+
+		PersonaConstraints: r.personaConstraints(agent, persona),
 	}
 
 	// Build error context string for span attribute
@@ -2885,13 +4157,23 @@ func (r *LanguageAgentReconciler) performSelfHealingSynthesis(ctx context.Contex
 			"Starting self-healing code synthesis with error context")
 	}
 
-	// Create synthesizer from agent's model
-	synthesizer, synthesisModelName, err := r.createSynthesizer(ctx, agent)
+	if err := r.checkSynthesisPricing(ctx, agent); err != nil {
+		return fmt.Errorf("self-healing synthesis refused: %w", err)
+	}
+
+	// Synthesize with the agent's model, moving along the cluster's fallback chain on failure
+	chain, err := r.synthesisModelChain(ctx, agent)
 	if err != nil {
 		return fmt.Errorf("failed to create synthesizer for self-healing: %w", err)
 	}
 
-	resp, err := synthesizer.SynthesizeAgent(ctx, synthReq)
+	if err := r.SynthesisSlots.Acquire(ctx, agent.Namespace, agent.Name); err != nil {
+		return r.deferSelfHealing(agent, fmt.Errorf("failed to acquire synthesis slot: %w", err))
+	}
+	resp, synthesisModel, err := r.synthesizeAlongChain(ctx, agent, chain, func(synthesizer synthesis.AgentSynthesizer) (*synthesis.AgentSynthesisResponse, error) {
+		return synthesizer.SynthesizeAgent(ctx, synthReq)
+	})
+	r.SynthesisSlots.Release()
 	if err != nil {
 		span.RecordError(err)
 		span.SetStatus(codes.Error, "Self-healing synthesis failed")
@@ -2921,17 +4203,26 @@ func (r *LanguageAgentReconciler) performSelfHealingSynthesis(ctx context.Contex
 
 	// Store all hashes for smart change detection
 	annotations := map[string]string{
-		"langop.io/instructions-hash": hashString(agent.Spec.Instructions),
+		codeHashAnnotation:            hashString(resp.DSLCode),
+		"langop.io/instructions-hash": hashString(r.synthesisInstructions(agent)),
 		"langop.io/tools-hash":        hashString(strings.Join(r.getToolNames(agent), ",")),
 		"langop.io/models-hash":       hashString(strings.Join(r.getModelNames(agent), ",")),
 		"langop.io/persona-hash":      hashString(strings.Join(r.getPersonaNames(agent), ",")),
 		"langop.io/synthesized-at":    metav1.Now().Format("2006-01-02T15:04:05Z"),
 		"langop.io/self-healing":      "true",
+		// Healed code replaces any code restored by an earlier rollback
+		"langop.io/self-healing-rollback": "false",
 	}
+	addTaskSectionAnnotations(annotations, r.synthesisInstructions(agent), resp.DSLCode)
 
 	if err := CreateOrUpdateConfigMapWithAnnotations(ctx, r.Client, r.Scheme, agent, codeConfigMapName, agent.Namespace, data, annotations); err != nil {
 		return err
 	}
+	if err := r.deliverCode(ctx, agent, resp.DSLCode); err != nil {
+		log.Error(err, "Failed to restart agent pods after self-healing")
+	}
+	// Self-healing never reads the cache, but healed code replaces the cached code that failed
+	r.cacheSynthesis(ctx, agent, resp.DSLCode)
 
 	// Update synthesis info in status
 	now := metav1.Now()
@@ -2939,14 +4230,20 @@ func (r *LanguageAgentReconciler) performSelfHealingSynthesis(ctx context.Contex
 		agent.Status.SynthesisInfo = &langopv1alpha1.SynthesisInfo{}
 	}
 	agent.Status.SynthesisInfo.LastSynthesisTime = &now
-	agent.Status.SynthesisInfo.SynthesisModel = synthesisModelName
+	agent.Status.SynthesisInfo.SynthesisModel = synthesisModel.Spec.ModelName
+	agent.Status.SynthesisInfo.SynthesisProvider = resp.Provider
 	agent.Status.SynthesisInfo.SynthesisDuration = resp.DurationSeconds
 	agent.Status.SynthesisInfo.CodeHash = hashString(resp.DSLCode)
-	agent.Status.SynthesisInfo.InstructionsHash = hashString(agent.Spec.Instructions)
+	agent.Status.SynthesisInfo.InstructionsHash = hashString(r.synthesisInstructions(agent))
 	agent.Status.SynthesisInfo.ValidationErrors = resp.ValidationErrors
+	r.recordRedaction(agent, resp)
+	r.recordPromptTrim(agent, resp)
+	// Failures of the replaced code don't count against the self-healed code
+	agent.Status.ConsecutiveFailures = 0
+	meta.RemoveStatusCondition(&agent.Status.Conditions, langopv1alpha1.SelfHealingRolledBackCondition)
 
 	// Update agent status
-	if err := r.Status().Update(ctx, agent); err != nil {
+	if err := r.updateStatus(ctx, agent); err != nil {
 		log.Error(err, "Failed to update synthesis info in status")
 		return err
 	}
@@ -2972,6 +4269,123 @@ func (r *LanguageAgentReconciler) performSelfHealingSynthesis(ctx context.Contex
 	return nil
 }
 
+// promoteStableSelfHealedCode makes self-healed code the new last known good code once the agent
+// has run it without failures for the stability window, and resets the self-healing attempts.
+// While the window is still running it returns the time left in it.
+func (r *LanguageAgentReconciler) promoteStableSelfHealedCode(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (time.Duration, error) {
+	if agent.Status.SelfHealingAttempts == 0 || agent.Status.ConsecutiveFailures > 0 ||
+		agent.Status.SynthesisInfo == nil || agent.Status.SynthesisInfo.LastSynthesisTime == nil {
+		return 0, nil
+	}
+	window := r.SelfHealingStabilityWindow
+	if window <= 0 {
+		window = defaultSelfHealingStabilityWindow
+	}
+	stableFor := time.Since(agent.Status.SynthesisInfo.LastSynthesisTime.Time)
+	if stableFor < window {
+		return window - stableFor, nil
+	}
+
+	codeConfigMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace}, codeConfigMap); err != nil {
+		return 0, client.IgnoreNotFound(err)
+	}
+	code := codeConfigMap.Data["agent.rb"]
+	if codeConfigMap.Annotations["langop.io/self-healing"] != "true" || code == "" {
+		return 0, nil
+	}
+
+	attempts := agent.Status.SelfHealingAttempts
+	agent.Status.LastSuccessfulCode = code
+	agent.Status.SelfHealingAttempts = 0
+	if err := r.updateStatus(ctx, agent); err != nil {
+		return 0, fmt.Errorf("failed to promote self-healed code: %w", err)
+	}
+
+	r.Log.Info("Promoted stable self-healed code to last known good",
+		"agent", agent.Name,
+		"namespace", agent.Namespace,
+		"attempts", attempts,
+		"stableFor", stableFor.Round(time.Second))
+	if r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeNormal, "SelfHealedCodePromoted",
+			"Self-healed code ran without failures for %s and is now the last known good code", window)
+	}
+	return 0, nil
+}
+
+// rollbackFailedSelfHealing reverts self-healed code that failed within the rollback window to the
+// last known good code, and reports whether it did
+func (r *LanguageAgentReconciler) rollbackFailedSelfHealing(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (bool, error) {
+	if r.SelfHealingRollbackWindow <= 0 || agent.Status.ConsecutiveFailures == 0 || agent.Status.LastSuccessfulCode == "" ||
+		agent.Status.SynthesisInfo == nil || agent.Status.SynthesisInfo.LastSynthesisTime == nil {
+		return false, nil
+	}
+	failedAfter := time.Since(agent.Status.SynthesisInfo.LastSynthesisTime.Time)
+	if failedAfter > r.SelfHealingRollbackWindow {
+		return false, nil
+	}
+
+	codeConfigMapName := GenerateConfigMapName(agent.Name, "code")
+	codeConfigMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: codeConfigMapName, Namespace: agent.Namespace}, codeConfigMap); err != nil {
+		return false, client.IgnoreNotFound(err)
+	}
+	if codeConfigMap.Annotations["langop.io/self-healing"] != "true" ||
+		codeConfigMap.Data["agent.rb"] == agent.Status.LastSuccessfulCode {
+		return false, nil
+	}
+
+	code := agent.Status.LastSuccessfulCode
+	data := map[string]string{
+		"agent.rb": code,
+	}
+	annotations := map[string]string{
+		codeHashAnnotation:                hashString(code),
+		"langop.io/instructions-hash":     hashString(r.synthesisInstructions(agent)),
+		"langop.io/tools-hash":            hashString(strings.Join(r.getToolNames(agent), ",")),
+		"langop.io/models-hash":           hashString(strings.Join(r.getModelNames(agent), ",")),
+		"langop.io/persona-hash":          hashString(strings.Join(r.getPersonaNames(agent), ",")),
+		"langop.io/synthesized-at":        codeConfigMap.Annotations["langop.io/synthesized-at"],
+		"langop.io/self-healing-rollback": "true",
+		// Annotations are merged into the existing ConfigMap, so the self-healing mark is cleared explicitly
+		"langop.io/self-healing": "false",
+	}
+	addTaskSectionAnnotations(annotations, r.synthesisInstructions(agent), code)
+
+	if err := CreateOrUpdateConfigMapWithAnnotations(ctx, r.Client, r.Scheme, agent, codeConfigMapName, agent.Namespace, data, annotations); err != nil {
+		return false, fmt.Errorf("failed to roll back self-healed code: %w", err)
+	}
+	if err := r.deliverCode(ctx, agent, code); err != nil {
+		r.Log.Error(err, "Failed to restart agent pods after self-healing rollback", "agent", agent.Name)
+	}
+
+	failures := agent.Status.ConsecutiveFailures
+	agent.Status.SynthesisInfo.CodeHash = hashString(code)
+	agent.Status.SynthesisInfo.ValidationErrors = nil
+	// Failures of the self-healed code don't count against the restored code
+	agent.Status.ConsecutiveFailures = 0
+	SetCondition(&agent.Status.Conditions, langopv1alpha1.SelfHealingRolledBackCondition, metav1.ConditionTrue,
+		"RepeatFailure",
+		fmt.Sprintf("Self-healed code failed %d time(s) within %s of deployment and was rolled back to the last known good code; manual attention required",
+			failures, r.SelfHealingRollbackWindow),
+		agent.Generation)
+	if err := r.updateStatus(ctx, agent); err != nil {
+		return false, err
+	}
+
+	r.Log.Info("Rolled back failing self-healed code to last known good",
+		"agent", agent.Name,
+		"namespace", agent.Namespace,
+		"consecutiveFailures", failures,
+		"failedAfter", failedAfter.Round(time.Second))
+	if r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeWarning, "SelfHealingRolledBack",
+			"Self-healed code failed within %s of deployment, rolled back to the last known good code", r.SelfHealingRollbackWindow)
+	}
+	return true, nil
+}
+
 // shouldAttemptSelfHealing determines if self-healing should be triggered
 func (r *LanguageAgentReconciler) shouldAttemptSelfHealing(agent *langopv1alpha1.LanguageAgent) bool {
 	// Self-healing must be enabled
@@ -3089,10 +4503,10 @@ func (r *LanguageAgentReconciler) detectPodFailures(ctx context.Context, agent *
 
 				agent.Status.LastCrashLog = crashLog
 				agent.Status.ConsecutiveFailures++
-				agent.Status.FailureReason = "Runtime"
+				setFailure(agent, classifyPodFailure(&pod), runtimeError.ErrorMessage)
 
 				// Update status
-				if err := r.Status().Update(ctx, agent); err != nil {
+				if err := r.updateStatus(ctx, agent); err != nil {
 					log.Error(err, "Failed to update agent status with runtime error")
 					span.RecordError(err)
 					span.SetStatus(codes.Error, "Failed to update agent status")
@@ -3105,9 +4519,47 @@ func (r *LanguageAgentReconciler) detectPodFailures(ctx context.Context, agent *
 						"Pod %s failed: %s", pod.Name, runtimeError.ErrorMessage)
 				}
 			}
+		} else if unhealthySince, unhealthy := r.isPodUnhealthy(&pod); unhealthy && r.featureGateEnabled(agent, langopv1alpha1.FeatureGateUnhealthyPodDetection) {
+			// Pod hasn't crashed but has been failing readiness for longer than the threshold
+			runtimeError := r.buildUnhealthyError(&pod, unhealthySince, agent)
+
+			// Count each unready episode only once across reconciles
+			if !countUnhealthyEpisode(agent, pod.UID, unhealthySince) {
+				continue
+			}
+
+			podFailureCount++
+			log.Info("Pod health failure detected", "pod", pod.Name, "notReadySince", unhealthySince.Time)
+
+			if runtimeError.ErrorMessage != "" {
+				errorPatterns = append(errorPatterns, runtimeError.ErrorMessage)
+			}
+
+			// Append to runtime errors (keep last 10)
+			agent.Status.RuntimeErrors = append(agent.Status.RuntimeErrors, *runtimeError)
+			if len(agent.Status.RuntimeErrors) > 10 {
+				agent.Status.RuntimeErrors = agent.Status.RuntimeErrors[len(agent.Status.RuntimeErrors)-10:]
+			}
+
+			agent.Status.ConsecutiveFailures++
+			setFailure(agent, langopv1alpha1.FailureReasonUnhealthy, runtimeError.ErrorMessage)
+
+			if err := r.updateStatus(ctx, agent); err != nil {
+				log.Error(err, "Failed to update agent status with health failure")
+				span.RecordError(err)
+				span.SetStatus(codes.Error, "Failed to update agent status")
+				return err
+			}
+
+			if r.Recorder != nil {
+				r.Recorder.Eventf(agent, corev1.EventTypeWarning, "Unhealthy",
+					"Pod %s not ready: %s", pod.Name, runtimeError.ErrorMessage)
+			}
 		}
 	}
 
+	pruneUnhealthyEpisodes(agent, podList.Items)
+
 	// Add failure detection metrics to span
 	span.SetAttributes(
 		attribute.Int("agent.pod_failures", podFailureCount),
@@ -3152,6 +4604,91 @@ func (r *LanguageAgentReconciler) isPodFailed(pod *corev1.Pod) bool {
 	return false
 }
 
+// isPodUnhealthy checks if a running, non-crashed pod has been not ready for longer
+// than the configured threshold. Returns the time the pod became not ready.
+func (r *LanguageAgentReconciler) isPodUnhealthy(pod *corev1.Pod) (metav1.Time, bool) {
+	if r.UnhealthyThreshold <= 0 {
+		return metav1.Time{}, false
+	}
+
+	// Only running pods that are not being torn down can be unhealthy
+	if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil {
+		return metav1.Time{}, false
+	}
+
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type != corev1.PodReady {
+			continue
+		}
+		if condition.Status == corev1.ConditionTrue || condition.LastTransitionTime.IsZero() {
+			return metav1.Time{}, false
+		}
+		if time.Since(condition.LastTransitionTime.Time) < r.UnhealthyThreshold {
+			return metav1.Time{}, false
+		}
+		return condition.LastTransitionTime, true
+	}
+
+	return metav1.Time{}, false
+}
+
+// buildUnhealthyError builds a runtime error describing a pod that is running but not ready
+func (r *LanguageAgentReconciler) buildUnhealthyError(pod *corev1.Pod, since metav1.Time, agent *langopv1alpha1.LanguageAgent) *langopv1alpha1.RuntimeError {
+	message := fmt.Sprintf("Pod %s has not been ready since %s", pod.Name, since.UTC().Format(time.RFC3339))
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady && condition.Message != "" {
+			message = fmt.Sprintf("%s: %s", message, condition.Message)
+		}
+	}
+
+	return &langopv1alpha1.RuntimeError{
+		Timestamp:        since,
+		ErrorType:        "Unhealthy",
+		ErrorMessage:     message,
+		SynthesisAttempt: agent.Status.SelfHealingAttempts,
+	}
+}
+
+// countUnhealthyEpisode records the unready episode of a pod that has not been ready since the
+// given time. Returns false if the episode was already counted.
+func countUnhealthyEpisode(agent *langopv1alpha1.LanguageAgent, podUID types.UID, since metav1.Time) bool {
+	episode := langopv1alpha1.UnhealthyPodEpisode{PodUID: string(podUID), NotReadySince: since}
+	for i, counted := range agent.Status.CountedUnhealthyPods {
+		if counted.PodUID != episode.PodUID {
+			continue
+		}
+		if counted.NotReadySince.Equal(&since) {
+			return false
+		}
+		// The pod recovered and became unready again since its last counted episode
+		agent.Status.CountedUnhealthyPods[i] = episode
+		return true
+	}
+	agent.Status.CountedUnhealthyPods = append(agent.Status.CountedUnhealthyPods, episode)
+	return true
+}
+
+// pruneUnhealthyEpisodes forgets the counted unready episodes of pods that no longer exist
+func pruneUnhealthyEpisodes(agent *langopv1alpha1.LanguageAgent, pods []corev1.Pod) {
+	if len(agent.Status.CountedUnhealthyPods) == 0 {
+		return
+	}
+	current := make(map[string]bool, len(pods))
+	for _, pod := range pods {
+		current[string(pod.UID)] = true
+	}
+	kept := agent.Status.CountedUnhealthyPods[:0]
+	for _, counted := range agent.Status.CountedUnhealthyPods {
+		if current[counted.PodUID] {
+			kept = append(kept, counted)
+		}
+	}
+	if len(kept) == 0 {
+		kept = nil
+	}
+	agent.Status.CountedUnhealthyPods = kept
+}
+
 // extractPodErrorInfo extracts error details and logs from a failed pod
 func (r *LanguageAgentReconciler) extractPodErrorInfo(ctx context.Context, pod *corev1.Pod, agent *langopv1alpha1.LanguageAgent) (*langopv1alpha1.RuntimeError, string, error) {
 	runtimeError := &langopv1alpha1.RuntimeError{
@@ -3193,19 +4730,19 @@ func (r *LanguageAgentReconciler) extractPodErrorInfo(ctx context.Context, pod *
 }
 
 // validateImageRegistry validates that the agent's container image registry is in the whitelist
-func (r *LanguageAgentReconciler) validateImageRegistry(agent *langopv1alpha1.LanguageAgent) error {
+func (r *LanguageAgentReconciler) validateImageRegistry(image string) error {
 	// Skip validation if no whitelist configured
 	allowedRegistries := r.RegistryManager.GetRegistries()
 	if len(allowedRegistries) == 0 {
 		return nil
 	}
 
-	return validation.ValidateImageRegistry(agent.Spec.Image, allowedRegistries)
+	return validation.ValidateImageRegistry(image, allowedRegistries)
 }
 
-// checkHTTPRouteReadiness checks if an HTTPRoute is ready to serve traffic
-// Returns (isReady, statusMessage, error)
-func (r *LanguageAgentReconciler) checkHTTPRouteReadiness(ctx context.Context, name, namespace string) (bool, string, error) {
+// checkHTTPRouteReadiness checks if an HTTPRoute is ready to serve traffic, and whether any parent
+// Gateway has accepted it. Returns (isReady, isAccepted, statusMessage, error)
+func (r *LanguageAgentReconciler) checkHTTPRouteReadiness(ctx context.Context, name, namespace string) (bool, bool, string, error) {
 	// Get HTTPRoute using unstructured to avoid Gateway API dependency
 	httpRoute := &unstructured.Unstructured{}
 	httpRoute.SetGroupVersionKind(schema.GroupVersionKind{
@@ -3217,30 +4754,32 @@ func (r *LanguageAgentReconciler) checkHTTPRouteReadiness(ctx context.Context, n
 	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, httpRoute)
 	if err != nil {
 		if errors.IsNotFound(err) {
-			return false, "HTTPRoute not found", nil
+			return false, false, "HTTPRoute not found", nil
 		}
-		return false, "", fmt.Errorf("failed to get HTTPRoute: %w", err)
+		return false, false, "", fmt.Errorf("failed to get HTTPRoute: %w", err)
 	}
 
 	// Check status conditions
 	status, found, err := unstructured.NestedMap(httpRoute.Object, "status")
 	if err != nil {
-		return false, "", fmt.Errorf("failed to get HTTPRoute status: %w", err)
+		return false, false, "", fmt.Errorf("failed to get HTTPRoute status: %w", err)
 	}
 	if !found {
-		return false, "HTTPRoute status not available", nil
+		return false, false, "HTTPRoute status not available", nil
 	}
 
 	// Check parents (Gateway references)
 	parents, found, err := unstructured.NestedSlice(status, "parents")
 	if err != nil {
-		return false, "", fmt.Errorf("failed to get HTTPRoute parents status: %w", err)
+		return false, false, "", fmt.Errorf("failed to get HTTPRoute parents status: %w", err)
 	}
 	if !found || len(parents) == 0 {
-		return false, "HTTPRoute has no parent Gateway status", nil
+		return false, false, "HTTPRoute has no parent Gateway status", nil
 	}
 
 	// Check if any parent is ready (Accepted and Programmed)
+	var anyAccepted bool
+	var rejection string
 	for _, parentInterface := range parents {
 		parent, ok := parentInterface.(map[string]interface{})
 		if !ok {
@@ -3265,17 +4804,26 @@ func (r *LanguageAgentReconciler) checkHTTPRouteReadiness(ctx context.Context, n
 			if condType == "Accepted" && condStatus == "True" {
 				accepted = true
 			}
+			if condType == "Accepted" && condStatus == "False" && rejection == "" {
+				reason, _, _ := unstructured.NestedString(cond, "reason")
+				message, _, _ := unstructured.NestedString(cond, "message")
+				rejection = fmt.Sprintf("HTTPRoute rejected by parent Gateway: %s: %s", reason, message)
+			}
 			if condType == "Programmed" && condStatus == "True" {
 				programmed = true
 			}
 		}
 
 		if accepted && programmed {
-			return true, "HTTPRoute is ready and programmed", nil
+			return true, true, "HTTPRoute is ready and programmed", nil
 		}
+		anyAccepted = anyAccepted || accepted
 	}
 
-	return false, "HTTPRoute is not ready - waiting for Gateway to accept and program route", nil
+	if !anyAccepted && rejection != "" {
+		return false, false, rejection, nil
+	}
+	return false, anyAccepted, "HTTPRoute is not ready - waiting for Gateway to accept and program route", nil
 }
 
 // checkIngressReadiness checks if an Ingress is ready to serve traffic
@@ -3312,14 +4860,28 @@ func (r *LanguageAgentReconciler) SetupWithManager(mgr ctrl.Manager, concurrency
 		r.MaxSelfHealingAttempts = 5
 	}
 
+	var forOpts []builder.ForOption
+	if r.Priority != nil {
+		// Let the prioritizer see each agent request as it is enqueued
+		forOpts = append(forOpts, builder.WithPredicates(r.Priority.Predicate()))
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
-		For(&langopv1alpha1.LanguageAgent{}).
+		For(&langopv1alpha1.LanguageAgent{}, forOpts...).
 		Owns(&appsv1.Deployment{}).
 		Owns(&batchv1.CronJob{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&corev1.Service{}).
+		Owns(&policyv1.PodDisruptionBudget{}).
 		Owns(&networkingv1.NetworkPolicy{}).
 		Owns(&networkingv1.Ingress{}).
 		Owns(&corev1.Pod{}).
+		Watches(&langopv1alpha1.LanguageTool{}, handler.EnqueueRequestsFromMapFunc(r.agentsForTool)).
+		Watches(&langopv1alpha1.LanguagePersona{}, handler.EnqueueRequestsFromMapFunc(r.agentsForDefaultPersona)).
+		Watches(&langopv1alpha1.LanguageCluster{}, handler.EnqueueRequestsFromMapFunc(r.agentsForCluster),
+			builder.WithPredicates(clusterDefaultsChanged())).
+		Watches(&langopv1alpha1.LanguageModel{}, handler.EnqueueRequestsFromMapFunc(r.agentsForModel),
+			builder.WithPredicates(modelReachabilityChanged())).
+		Watches(&langopv1alpha1.LanguageModelGrant{}, handler.EnqueueRequestsFromMapFunc(r.agentsForModelGrant)).
 		Complete(r)
 }
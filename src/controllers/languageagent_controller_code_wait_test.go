@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLanguageAgentController_WaitsForCodeConfigMap(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{ModelName: "gpt-4"},
+	}
+
+	// The agent is in its self-healing backoff window, so synthesis is skipped
+	// and the code ConfigMap is not created during this reconcile
+	now := metav1.Now()
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "code-wait-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			Instructions:  "Summarize the news",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+		},
+		Status: langopv1alpha1.LanguageAgentStatus{
+			ConsecutiveFailures: 2,
+			SynthesisInfo: &langopv1alpha1.SynthesisInfo{
+				LastSynthesisTime: &now,
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(model, agent).
+		WithStatusSubresource(agent).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:                 fakeClient,
+		Scheme:                 scheme,
+		Log:                    logr.Discard(),
+		Recorder:               &record.FakeRecorder{},
+		RegistryManager:        &mockRegistryManager{},
+		SelfHealingEnabled:     true,
+		MaxSelfHealingAttempts: 5,
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if result.RequeueAfter != codeRequeueInterval {
+		t.Errorf("Expected requeue after %v, got %v", codeRequeueInterval, result.RequeueAfter)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, key, deployment); !errors.IsNotFound(err) {
+		t.Fatalf("Expected no Deployment before the code ConfigMap exists, got err=%v", err)
+	}
+
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+	if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != "WaitingForCode" {
+		t.Fatalf("Expected Ready=False with reason WaitingForCode, got %+v", ready)
+	}
+
+	// Once the code ConfigMap exists the workload is created
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace},
+		Data:       map[string]string{"agent.rb": "agent 'code-wait-agent' do\nend"},
+	}
+	if err := fakeClient.Create(ctx, codeConfigMap); err != nil {
+		t.Fatalf("Failed to create code ConfigMap: %v", err)
+	}
+
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if err := fakeClient.Get(ctx, key, deployment); err != nil {
+		t.Fatalf("Expected Deployment once the code ConfigMap exists, got error: %v", err)
+	}
+}
+
+func TestCodeConfigMapExists_WithoutSynthesis(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "plain-agent", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{Image: "ghcr.io/language-operator/agent:latest"},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme}
+
+	exists, err := reconciler.codeConfigMapExists(context.Background(), agent)
+	if err != nil {
+		t.Fatalf("codeConfigMapExists failed: %v", err)
+	}
+	if !exists {
+		t.Error("Expected agents without synthesized code never to wait for a code ConfigMap")
+	}
+}
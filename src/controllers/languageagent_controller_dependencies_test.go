@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newDependencyTestAgent(name string, dependsOn ...string) *langopv1alpha1.LanguageAgent {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+		},
+	}
+	for _, dep := range dependsOn {
+		agent.Spec.DependsOn = append(agent.Spec.DependsOn, langopv1alpha1.AgentReference{Name: dep})
+	}
+	return agent
+}
+
+func TestLanguageAgentController_WaitsForDependencies(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	provider := newDependencyTestAgent("shared-provider")
+	consumer := newDependencyTestAgent("consumer", "shared-provider")
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(provider, consumer).
+		WithStatusSubresource(provider, consumer).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        &record.FakeRecorder{},
+		RegistryManager: &mockRegistryManager{},
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	consumerKey := types.NamespacedName{Name: consumer.Name, Namespace: consumer.Namespace}
+
+	// The provider is not Ready yet, so the consumer's workload must not be created
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: consumerKey})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if result.RequeueAfter != dependencyRequeueInterval {
+		t.Errorf("Expected requeue after %v, got %v", dependencyRequeueInterval, result.RequeueAfter)
+	}
+
+	deployment := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, consumerKey, deployment); !errors.IsNotFound(err) {
+		t.Fatalf("Expected no Deployment while dependencies are not ready, got err=%v", err)
+	}
+
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, consumerKey, updated); err != nil {
+		t.Fatalf("Failed to get consumer: %v", err)
+	}
+	condition := meta.FindStatusCondition(updated.Status.Conditions, "DependenciesReady")
+	if condition == nil || condition.Status != metav1.ConditionFalse || condition.Reason != "DependenciesNotReady" {
+		t.Fatalf("Expected DependenciesReady=False with reason DependenciesNotReady, got %+v", condition)
+	}
+
+	// Once the provider becomes Ready the consumer proceeds
+	readyProvider := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: provider.Name, Namespace: provider.Namespace}, readyProvider); err != nil {
+		t.Fatalf("Failed to get provider: %v", err)
+	}
+	SetCondition(&readyProvider.Status.Conditions, "Ready", metav1.ConditionTrue, "ReconcileSuccess", "LanguageAgent is ready", readyProvider.Generation)
+	if err := fakeClient.Status().Update(ctx, readyProvider); err != nil {
+		t.Fatalf("Failed to mark provider ready: %v", err)
+	}
+
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: consumerKey}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if err := fakeClient.Get(ctx, consumerKey, deployment); err != nil {
+		t.Fatalf("Expected Deployment once dependencies are ready, got error: %v", err)
+	}
+
+	if err := fakeClient.Get(ctx, consumerKey, updated); err != nil {
+		t.Fatalf("Failed to get consumer: %v", err)
+	}
+	if !meta.IsStatusConditionTrue(updated.Status.Conditions, "DependenciesReady") {
+		t.Error("Expected DependenciesReady=True once dependencies are ready")
+	}
+}
+
+func TestLanguageAgentController_MissingDependency(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	consumer := newDependencyTestAgent("consumer", "does-not-exist")
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(consumer).
+		WithStatusSubresource(consumer).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme}
+
+	ready, msg, err := reconciler.checkDependencies(context.Background(), consumer)
+	if err != nil {
+		t.Fatalf("checkDependencies failed: %v", err)
+	}
+	if ready {
+		t.Error("Expected missing dependency to block readiness")
+	}
+	if msg != "Dependency default/does-not-exist not found" {
+		t.Errorf("Unexpected message: %q", msg)
+	}
+}
@@ -542,7 +542,7 @@ func TestLanguageAgentController_CheckHTTPRouteReadiness(t *testing.T) {
 			}
 
 			ctx := context.Background()
-			ready, message, err := reconciler.checkHTTPRouteReadiness(ctx, "test-agent", "default")
+			ready, _, message, err := reconciler.checkHTTPRouteReadiness(ctx, "test-agent", "default")
 			if err != nil {
 				t.Fatalf("checkHTTPRouteReadiness failed: %v", err)
 			}
@@ -0,0 +1,571 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newUnreadyPod creates a running agent pod whose readiness has been failing since the given time
+func newUnreadyPod(name string, agent *langopv1alpha1.LanguageAgent, notReadySince time.Time) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: agent.Namespace,
+			UID:       types.UID(name + "-uid"),
+			Labels:    GetCommonLabels(agent.Name, "LanguageAgent"),
+		},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodRunning,
+			Conditions: []corev1.PodCondition{
+				{
+					Type:               corev1.PodReady,
+					Status:             corev1.ConditionFalse,
+					Reason:             "ContainersNotReady",
+					Message:            "containers with unready status: [agent]",
+					LastTransitionTime: metav1.NewTime(notReadySince),
+				},
+			},
+			ContainerStatuses: []corev1.ContainerStatus{
+				{
+					Name:  "agent",
+					Ready: false,
+					State: corev1.ContainerState{
+						Running: &corev1.ContainerStateRunning{},
+					},
+				},
+			},
+		},
+	}
+}
+
+func newSelfHealingTestAgent() *langopv1alpha1.LanguageAgent {
+	return &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "unhealthy-agent",
+			Namespace: "default",
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+		},
+		Status: langopv1alpha1.LanguageAgentStatus{
+			SynthesisInfo: &langopv1alpha1.SynthesisInfo{},
+		},
+	}
+}
+
+func TestLanguageAgentController_DetectUnhealthyPod(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	agent := newSelfHealingTestAgent()
+	pod := newUnreadyPod("unhealthy-agent-abc", agent, time.Now().Add(-10*time.Minute))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent, pod).
+		WithStatusSubresource(agent).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:             fakeClient,
+		Scheme:             scheme,
+		Log:                logr.Discard(),
+		Recorder:           record.NewFakeRecorder(10),
+		SelfHealingEnabled: true,
+		UnhealthyThreshold: 5 * time.Minute,
+	}
+
+	ctx := context.Background()
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if err := reconciler.detectPodFailures(ctx, agent); err != nil {
+		t.Fatalf("detectPodFailures failed: %v", err)
+	}
+
+	if agent.Status.ConsecutiveFailures != 1 {
+		t.Errorf("Expected 1 consecutive failure, got %d", agent.Status.ConsecutiveFailures)
+	}
+	if agent.Status.FailureReason != langopv1alpha1.FailureReasonUnhealthy {
+		t.Errorf("Expected failure reason 'Unhealthy', got %q", agent.Status.FailureReason)
+	}
+	if len(agent.Status.RuntimeErrors) != 1 || agent.Status.RuntimeErrors[0].ErrorType != "Unhealthy" {
+		t.Fatalf("Expected one Unhealthy runtime error, got %+v", agent.Status.RuntimeErrors)
+	}
+
+	// The same unready episode must not be counted again on the next reconcile
+	if err := reconciler.detectPodFailures(ctx, agent); err != nil {
+		t.Fatalf("detectPodFailures failed: %v", err)
+	}
+	if agent.Status.ConsecutiveFailures != 1 {
+		t.Errorf("Expected unready episode to be counted once, got %d failures", agent.Status.ConsecutiveFailures)
+	}
+
+	// Nor once its runtime error dropped out of the recent errors or the condition message changed
+	agent.Status.RuntimeErrors = nil
+	pod.Status.Conditions[0].Message = "containers with unready status: [agent sidecar]"
+	if err := fakeClient.Status().Update(ctx, pod); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if err := reconciler.detectPodFailures(ctx, agent); err != nil {
+		t.Fatalf("detectPodFailures failed: %v", err)
+	}
+	if agent.Status.ConsecutiveFailures != 1 {
+		t.Errorf("Expected the same unready episode to be counted once, got %d failures", agent.Status.ConsecutiveFailures)
+	}
+
+	// A new unready episode of the same pod counts again
+	pod.Status.Conditions[0].LastTransitionTime = metav1.NewTime(time.Now().Add(-6 * time.Minute).Truncate(time.Second))
+	if err := fakeClient.Status().Update(ctx, pod); err != nil {
+		t.Fatalf("Failed to update pod: %v", err)
+	}
+	if err := reconciler.detectPodFailures(ctx, agent); err != nil {
+		t.Fatalf("detectPodFailures failed: %v", err)
+	}
+	if agent.Status.ConsecutiveFailures != 2 {
+		t.Errorf("Expected a new unready episode to be counted, got %d failures", agent.Status.ConsecutiveFailures)
+	}
+	if len(agent.Status.CountedUnhealthyPods) != 1 {
+		t.Errorf("Expected one counted episode per pod, got %+v", agent.Status.CountedUnhealthyPods)
+	}
+
+	// Episodes of pods that are gone are forgotten
+	if err := fakeClient.Delete(ctx, pod); err != nil {
+		t.Fatalf("Failed to delete pod: %v", err)
+	}
+	if err := reconciler.detectPodFailures(ctx, agent); err != nil {
+		t.Fatalf("detectPodFailures failed: %v", err)
+	}
+	if len(agent.Status.CountedUnhealthyPods) != 0 {
+		t.Errorf("Expected the deleted pod's episode to be forgotten, got %+v", agent.Status.CountedUnhealthyPods)
+	}
+}
+
+func TestLanguageAgentController_UnhealthyPodTriggersSelfHealing(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	agent := newSelfHealingTestAgent()
+	podA := newUnreadyPod("unhealthy-agent-a", agent, time.Now().Add(-10*time.Minute))
+	podB := newUnreadyPod("unhealthy-agent-b", agent, time.Now().Add(-8*time.Minute))
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent, podA, podB).
+		WithStatusSubresource(agent).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:                 fakeClient,
+		Scheme:                 scheme,
+		Log:                    logr.Discard(),
+		Recorder:               record.NewFakeRecorder(10),
+		SelfHealingEnabled:     true,
+		MaxSelfHealingAttempts: 5,
+		UnhealthyThreshold:     5 * time.Minute,
+	}
+
+	if reconciler.shouldAttemptSelfHealing(agent) {
+		t.Fatal("Expected no self-healing before failures were detected")
+	}
+
+	ctx := context.Background()
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if err := reconciler.detectPodFailures(ctx, agent); err != nil {
+		t.Fatalf("detectPodFailures failed: %v", err)
+	}
+
+	if agent.Status.ConsecutiveFailures != 2 {
+		t.Errorf("Expected 2 consecutive failures, got %d", agent.Status.ConsecutiveFailures)
+	}
+	if !reconciler.shouldAttemptSelfHealing(agent) {
+		t.Error("Expected persistently unready pods to trigger self-healing")
+	}
+}
+
+func TestLanguageAgentController_IsPodUnhealthy(t *testing.T) {
+	agent := newSelfHealingTestAgent()
+
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		pod       func() *corev1.Pod
+		expected  bool
+	}{
+		{
+			name:      "not ready beyond threshold",
+			threshold: 5 * time.Minute,
+			pod: func() *corev1.Pod {
+				return newUnreadyPod("p", agent, time.Now().Add(-10*time.Minute))
+			},
+			expected: true,
+		},
+		{
+			name:      "not ready within threshold",
+			threshold: 5 * time.Minute,
+			pod: func() *corev1.Pod {
+				return newUnreadyPod("p", agent, time.Now().Add(-1*time.Minute))
+			},
+			expected: false,
+		},
+		{
+			name:      "detection disabled",
+			threshold: 0,
+			pod: func() *corev1.Pod {
+				return newUnreadyPod("p", agent, time.Now().Add(-10*time.Minute))
+			},
+			expected: false,
+		},
+		{
+			name:      "ready pod",
+			threshold: 5 * time.Minute,
+			pod: func() *corev1.Pod {
+				pod := newUnreadyPod("p", agent, time.Now().Add(-10*time.Minute))
+				pod.Status.Conditions[0].Status = corev1.ConditionTrue
+				return pod
+			},
+			expected: false,
+		},
+		{
+			name:      "pending pod",
+			threshold: 5 * time.Minute,
+			pod: func() *corev1.Pod {
+				pod := newUnreadyPod("p", agent, time.Now().Add(-10*time.Minute))
+				pod.Status.Phase = corev1.PodPending
+				return pod
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := &LanguageAgentReconciler{UnhealthyThreshold: tt.threshold}
+			_, unhealthy := reconciler.isPodUnhealthy(tt.pod())
+			if unhealthy != tt.expected {
+				t.Errorf("Expected unhealthy=%v, got %v", tt.expected, unhealthy)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_PromoteStableSelfHealedCode(t *testing.T) {
+	tests := []struct {
+		name                string
+		healedAgo           time.Duration
+		consecutiveFailures int32
+		selfHealedCode      bool
+		expectPromoted      bool
+		expectRemaining     bool
+	}{
+		{name: "stable for the window", healedAgo: 15 * time.Minute, selfHealedCode: true, expectPromoted: true},
+		{name: "within the window", healedAgo: 5 * time.Minute, selfHealedCode: true, expectRemaining: true},
+		{name: "failing again", healedAgo: 15 * time.Minute, consecutiveFailures: 1, selfHealedCode: true},
+		{name: "code not from self-healing", healedAgo: 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			healedAt := metav1.NewTime(time.Now().Add(-tt.healedAgo))
+			agent := newSelfHealingTestAgent()
+			agent.Status.SelfHealingAttempts = 2
+			agent.Status.ConsecutiveFailures = tt.consecutiveFailures
+			agent.Status.LastSuccessfulCode = "old code"
+			agent.Status.SynthesisInfo.LastSynthesisTime = &healedAt
+
+			codeConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace},
+				Data:       map[string]string{"agent.rb": "healed code"},
+			}
+			if tt.selfHealedCode {
+				codeConfigMap.Annotations = map[string]string{"langop.io/self-healing": "true"}
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent, codeConfigMap).
+				WithStatusSubresource(agent).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &LanguageAgentReconciler{
+				Client:                     fakeClient,
+				Scheme:                     scheme,
+				Log:                        logr.Discard(),
+				Recorder:                   recorder,
+				SelfHealingEnabled:         true,
+				SelfHealingStabilityWindow: 10 * time.Minute,
+			}
+
+			ctx := context.Background()
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			remaining, err := reconciler.promoteStableSelfHealedCode(ctx, agent)
+			if err != nil {
+				t.Fatalf("promoteStableSelfHealedCode failed: %v", err)
+			}
+			// The agent is rechecked when the rest of the window has passed
+			if tt.expectRemaining != (remaining > 4*time.Minute && remaining <= 5*time.Minute) {
+				t.Errorf("Expected remaining window=%v, got %s", tt.expectRemaining, remaining)
+			}
+
+			updated := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, updated); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			if tt.expectPromoted {
+				if updated.Status.LastSuccessfulCode != "healed code" {
+					t.Errorf("Expected self-healed code to be promoted, got %q", updated.Status.LastSuccessfulCode)
+				}
+				if updated.Status.SelfHealingAttempts != 0 {
+					t.Errorf("Expected self-healing attempts to reset, got %d", updated.Status.SelfHealingAttempts)
+				}
+				if !hasEvent(drainEvents(recorder), "SelfHealedCodePromoted") {
+					t.Error("Expected SelfHealedCodePromoted event")
+				}
+				return
+			}
+			if updated.Status.LastSuccessfulCode != "old code" || updated.Status.SelfHealingAttempts != 2 {
+				t.Errorf("Expected no promotion, got lastSuccessfulCode=%q attempts=%d",
+					updated.Status.LastSuccessfulCode, updated.Status.SelfHealingAttempts)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_RollbackFailedSelfHealing(t *testing.T) {
+	tests := []struct {
+		name               string
+		healedAgo          time.Duration
+		rollbackWindow     time.Duration
+		lastSuccessfulCode string
+		selfHealedCode     bool
+		expectRollback     bool
+	}{
+		{name: "repeat failure within the window", healedAgo: 3 * time.Minute, rollbackWindow: 10 * time.Minute, lastSuccessfulCode: "good code", selfHealedCode: true, expectRollback: true},
+		{name: "repeat failure after the window", healedAgo: 15 * time.Minute, rollbackWindow: 10 * time.Minute, lastSuccessfulCode: "good code", selfHealedCode: true},
+		{name: "no last known good code", healedAgo: 3 * time.Minute, rollbackWindow: 10 * time.Minute, selfHealedCode: true},
+		{name: "code not from self-healing", healedAgo: 3 * time.Minute, rollbackWindow: 10 * time.Minute, lastSuccessfulCode: "good code"},
+		{name: "rollback disabled", healedAgo: 3 * time.Minute, lastSuccessfulCode: "good code", selfHealedCode: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			healedAt := metav1.NewTime(time.Now().Add(-tt.healedAgo))
+			agent := newSelfHealingTestAgent()
+			agent.Status.SelfHealingAttempts = 1
+			agent.Status.ConsecutiveFailures = 2
+			agent.Status.LastSuccessfulCode = tt.lastSuccessfulCode
+			agent.Status.SynthesisInfo.LastSynthesisTime = &healedAt
+
+			codeConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace},
+				Data:       map[string]string{"agent.rb": "healed code"},
+			}
+			if tt.selfHealedCode {
+				codeConfigMap.Annotations = map[string]string{"langop.io/self-healing": "true"}
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent, codeConfigMap).
+				WithStatusSubresource(agent).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &LanguageAgentReconciler{
+				Client:                    fakeClient,
+				Scheme:                    scheme,
+				Log:                       logr.Discard(),
+				Recorder:                  recorder,
+				SelfHealingEnabled:        true,
+				MaxSelfHealingAttempts:    5,
+				SelfHealingRollbackWindow: tt.rollbackWindow,
+			}
+
+			ctx := context.Background()
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			rolledBack, err := reconciler.rollbackFailedSelfHealing(ctx, agent)
+			if err != nil {
+				t.Fatalf("rollbackFailedSelfHealing failed: %v", err)
+			}
+			if rolledBack != tt.expectRollback {
+				t.Fatalf("Expected rolledBack=%v, got %v", tt.expectRollback, rolledBack)
+			}
+
+			updatedConfigMap := &corev1.ConfigMap{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: codeConfigMap.Name, Namespace: agent.Namespace}, updatedConfigMap); err != nil {
+				t.Fatalf("Failed to get code ConfigMap: %v", err)
+			}
+			updated := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, updated); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			rolledBackCondition := meta.FindStatusCondition(updated.Status.Conditions, langopv1alpha1.SelfHealingRolledBackCondition)
+
+			if !tt.expectRollback {
+				if updatedConfigMap.Data["agent.rb"] != "healed code" {
+					t.Errorf("Expected self-healed code to stay deployed, got %q", updatedConfigMap.Data["agent.rb"])
+				}
+				if rolledBackCondition != nil {
+					t.Errorf("Expected no %s condition", langopv1alpha1.SelfHealingRolledBackCondition)
+				}
+				return
+			}
+
+			if updatedConfigMap.Data["agent.rb"] != "good code" {
+				t.Errorf("Expected last known good code to be restored, got %q", updatedConfigMap.Data["agent.rb"])
+			}
+			if updatedConfigMap.Annotations["langop.io/self-healing"] == "true" {
+				t.Error("Expected restored code not to be marked as self-healed")
+			}
+			if updatedConfigMap.Annotations["langop.io/self-healing-rollback"] != "true" {
+				t.Error("Expected restored code to be marked as a rollback")
+			}
+			if updated.Status.ConsecutiveFailures != 0 {
+				t.Errorf("Expected consecutive failures to reset, got %d", updated.Status.ConsecutiveFailures)
+			}
+			if updated.Status.SynthesisInfo.CodeHash != hashString("good code") {
+				t.Error("Expected code hash to match the restored code")
+			}
+			if rolledBackCondition == nil || rolledBackCondition.Status != metav1.ConditionTrue {
+				t.Errorf("Expected %s condition to be true, got %+v", langopv1alpha1.SelfHealingRolledBackCondition, rolledBackCondition)
+			}
+			if !hasEvent(drainEvents(recorder), "SelfHealingRolledBack") {
+				t.Error("Expected SelfHealingRolledBack event")
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_RepeatFailureRollsBackInsteadOfHealing(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	healedAt := metav1.NewTime(time.Now().Add(-2 * time.Minute))
+	agent := newSelfHealingTestAgent()
+	agent.Status.SelfHealingAttempts = 1
+	agent.Status.ConsecutiveFailures = 2
+	agent.Status.LastSuccessfulCode = "good code"
+	agent.Status.SynthesisInfo.LastSynthesisTime = &healedAt
+
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        GenerateConfigMapName(agent.Name, "code"),
+			Namespace:   agent.Namespace,
+			Annotations: map[string]string{"langop.io/self-healing": "true"},
+		},
+		Data: map[string]string{"agent.rb": "healed code"},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent, codeConfigMap).
+		WithStatusSubresource(agent).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LanguageAgentReconciler{
+		Client:                    fakeClient,
+		Scheme:                    scheme,
+		Log:                       logr.Discard(),
+		Recorder:                  recorder,
+		SelfHealingEnabled:        true,
+		MaxSelfHealingAttempts:    5,
+		SelfHealingRollbackWindow: 10 * time.Minute,
+	}
+
+	ctx := context.Background()
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	// No synthesis model is configured, so attempting another self-healing synthesis would fail
+	if err := reconciler.reconcileCodeConfigMap(ctx, agent); err != nil {
+		t.Fatalf("Expected rollback instead of self-healing synthesis, got: %v", err)
+	}
+
+	events := drainEvents(recorder)
+	if hasEvent(events, "SelfHealingTriggered") {
+		t.Error("Expected no further self-healing synthesis after rollback")
+	}
+	if !hasEvent(events, "SelfHealingRolledBack") {
+		t.Error("Expected SelfHealingRolledBack event")
+	}
+	if agent.Status.SelfHealingAttempts != 1 {
+		t.Errorf("Expected self-healing attempts to be unchanged, got %d", agent.Status.SelfHealingAttempts)
+	}
+}
+
+func TestLanguageAgentController_SynthesisClearsSelfHealingRollback(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	agent := newSelfHealingTestAgent()
+	SetCondition(&agent.Status.Conditions, langopv1alpha1.SelfHealingRolledBackCondition, metav1.ConditionTrue,
+		"RepeatFailure", "Self-healed code was rolled back", agent.Generation)
+
+	// Code restored by a rollback, synthesized from instructions that have since changed
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GenerateConfigMapName(agent.Name, "code"),
+			Namespace: agent.Namespace,
+			Annotations: map[string]string{
+				codeHashAnnotation:                hashString("good code"),
+				"langop.io/instructions-hash":     hashString("previous instructions"),
+				"langop.io/self-healing-rollback": "true",
+			},
+		},
+		Data: map[string]string{"agent.rb": "good code"},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent, codeConfigMap).
+		WithStatusSubresource(agent).
+		Build()
+	reconciler := &LanguageAgentReconciler{
+		Client:         fakeClient,
+		Scheme:         scheme,
+		Log:            logr.Discard(),
+		Recorder:       record.NewFakeRecorder(10),
+		SynthesisCache: synthesis.NewMemoryCache(time.Hour, 10),
+	}
+
+	ctx := context.Background()
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if err := reconciler.SynthesisCache.Put(ctx, reconciler.synthesisCacheKey(agent), "new code"); err != nil {
+		t.Fatalf("Failed to populate cache: %v", err)
+	}
+	if err := reconciler.reconcileCodeConfigMap(ctx, agent); err != nil {
+		t.Fatalf("reconcileCodeConfigMap failed: %v", err)
+	}
+
+	updatedConfigMap := &corev1.ConfigMap{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: codeConfigMap.Name, Namespace: agent.Namespace}, updatedConfigMap); err != nil {
+		t.Fatalf("Failed to get code ConfigMap: %v", err)
+	}
+	if updatedConfigMap.Data["agent.rb"] != "new code" {
+		t.Fatalf("Expected the newly synthesized code, got %q", updatedConfigMap.Data["agent.rb"])
+	}
+	if updatedConfigMap.Annotations["langop.io/self-healing-rollback"] == "true" {
+		t.Error("Expected new code not to be marked as a rollback")
+	}
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if meta.FindStatusCondition(updated.Status.Conditions, langopv1alpha1.SelfHealingRolledBackCondition) != nil {
+		t.Errorf("Expected %s condition to be cleared", langopv1alpha1.SelfHealingRolledBackCondition)
+	}
+}
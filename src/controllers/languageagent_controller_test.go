@@ -2,20 +2,27 @@ package controllers
 
 import (
 	"context"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
 	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
 	appsv1 "k8s.io/api/apps/v1"
 	batchv1 "k8s.io/api/batch/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 )
 
 // mockRegistryManager implements RegistryManager for testing
@@ -147,6 +154,12 @@ func TestLanguageAgentController_DeploymentCreation(t *testing.T) {
 	if deployment.Spec.Template.Spec.Containers[0].Image != agent.Spec.Image {
 		t.Errorf("Expected image '%s', got '%s'", agent.Spec.Image, deployment.Spec.Template.Spec.Containers[0].Image)
 	}
+
+	// Verify the zero-downtime rolling update default
+	rollingUpdate := deployment.Spec.Strategy.RollingUpdate
+	if rollingUpdate == nil || rollingUpdate.MaxSurge.IntValue() != 1 || rollingUpdate.MaxUnavailable.IntValue() != 0 {
+		t.Errorf("Expected maxSurge=1 and maxUnavailable=0, got %+v", deployment.Spec.Strategy)
+	}
 }
 
 func TestLanguageAgentController_CronJobCreation(t *testing.T) {
@@ -612,6 +625,269 @@ func TestLanguageAgentController_ContainerSecurityContext(t *testing.T) {
 	}
 }
 
+func TestLanguageAgentController_StartupProbe(t *testing.T) {
+	tests := []struct {
+		name             string
+		executionMode    string
+		startupProbe     *corev1.Probe
+		expectProbe      bool
+		expectTCP        bool
+		expectThreshold  int32
+		minStartupWindow int32
+	}{
+		{
+			name:             "default startup probe tolerates slow installs",
+			executionMode:    "interactive",
+			expectProbe:      true,
+			expectTCP:        true,
+			expectThreshold:  30,
+			minStartupWindow: 300,
+		},
+		{
+			name:          "autonomous agents get no default startup probe",
+			executionMode: "autonomous",
+		},
+		{
+			name:          "event-driven agents get no default startup probe",
+			executionMode: "event-driven",
+		},
+		{
+			name:          "custom startup probe is applied",
+			executionMode: "autonomous",
+			startupProbe: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					Exec: &corev1.ExecAction{Command: []string{"test", "-f", "/tmp/ready"}},
+				},
+				PeriodSeconds:    5,
+				SuccessThreshold: 1,
+				FailureThreshold: 120,
+			},
+			expectProbe:      true,
+			expectThreshold:  120,
+			minStartupWindow: 600,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "test-startup-probe-agent",
+					Namespace: "default",
+				},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:         "ghcr.io/language-operator/agent:latest",
+					ExecutionMode: tt.executionMode,
+					StartupProbe:  tt.startupProbe,
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent).
+				WithStatusSubresource(agent).
+				Build()
+
+			reconciler := &LanguageAgentReconciler{
+				Client:          fakeClient,
+				Scheme:          scheme,
+				Log:             logr.Discard(),
+				Recorder:        &record.FakeRecorder{},
+				RegistryManager: &mockRegistryManager{},
+			}
+			reconciler.InitializeGatewayCache()
+
+			ctx := context.Background()
+			_, err := reconciler.Reconcile(ctx, ctrl.Request{
+				NamespacedName: types.NamespacedName{
+					Name:      agent.Name,
+					Namespace: agent.Namespace,
+				},
+			})
+			if err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			deployment := &appsv1.Deployment{}
+			err = fakeClient.Get(ctx, types.NamespacedName{
+				Name:      agent.Name,
+				Namespace: agent.Namespace,
+			}, deployment)
+			if err != nil {
+				t.Fatalf("Expected Deployment to exist, but got error: %v", err)
+			}
+
+			probe := deployment.Spec.Template.Spec.Containers[0].StartupProbe
+			if !tt.expectProbe {
+				// Only interactive agents listen on the webhook port the default probe checks
+				if probe != nil {
+					t.Errorf("Expected no startup probe, got %+v", probe)
+				}
+				return
+			}
+			if probe == nil {
+				t.Fatal("Expected agent container to have a startup probe")
+			}
+
+			if tt.expectTCP && (probe.TCPSocket == nil || probe.TCPSocket.Port.IntValue() != agentWebhookPort) {
+				t.Errorf("Expected default TCP startup probe on the webhook port, got %+v", probe.ProbeHandler)
+			}
+			if !tt.expectTCP && probe.Exec == nil {
+				t.Errorf("Expected custom exec startup probe, got %+v", probe.ProbeHandler)
+			}
+			if probe.FailureThreshold != tt.expectThreshold {
+				t.Errorf("Expected failureThreshold %d, got %d", tt.expectThreshold, probe.FailureThreshold)
+			}
+
+			// Kubernetes holds off liveness checks until the startup probe succeeds,
+			// so the startup window must be long enough for slow initialization
+			if window := probe.FailureThreshold * probe.PeriodSeconds; window < tt.minStartupWindow {
+				t.Errorf("Expected startup window of at least %ds, got %ds", tt.minStartupWindow, window)
+			}
+			if probe.SuccessThreshold != 1 {
+				t.Errorf("Expected successThreshold 1 for startup probe, got %d", probe.SuccessThreshold)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_ResourceAttributes(t *testing.T) {
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "deployment.environment=staging,cluster=shared")
+	scheme := testutil.SetupTestScheme(t)
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-resource-attributes-agent",
+			Namespace: "default",
+			UID:       "1234-abcd",
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			Telemetry: &langopv1alpha1.AgentTelemetrySpec{
+				ResourceAttributes: map[string]string{
+					"team":                   "payments",
+					"deployment.environment": "prod",
+					"langop.agent.name":      "spoofed",
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent).
+		WithStatusSubresource(agent).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        &record.FakeRecorder{},
+		RegistryManager: &mockRegistryManager{},
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+
+	resourceAttributes := func() string {
+		if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+			t.Fatalf("Reconcile failed: %v", err)
+		}
+		deployment := &appsv1.Deployment{}
+		if err := fakeClient.Get(ctx, key, deployment); err != nil {
+			t.Fatalf("Expected Deployment to exist, but got error: %v", err)
+		}
+		for _, env := range deployment.Spec.Template.Spec.Containers[0].Env {
+			if env.Name == "OTEL_RESOURCE_ATTRIBUTES" {
+				return env.Value
+			}
+		}
+		t.Fatal("Expected OTEL_RESOURCE_ATTRIBUTES env var on agent container")
+		return ""
+	}
+
+	// Spec attributes override the operator environment, and operator-managed attributes override both
+	expected := "cluster=shared,deployment.environment=prod,k8s.namespace.name=default," +
+		"langop.agent.name=test-resource-attributes-agent,langop.agent.uid=1234-abcd,team=payments"
+
+	first := resourceAttributes()
+	if first != expected {
+		t.Errorf("Expected OTEL_RESOURCE_ATTRIBUTES %q, got %q", expected, first)
+	}
+
+	for i := 0; i < 3; i++ {
+		if value := resourceAttributes(); value != first {
+			t.Errorf("Expected OTEL_RESOURCE_ATTRIBUTES to be stable across reconciles, got %q then %q", first, value)
+		}
+	}
+}
+
+func TestBuildAgentResourceAttributes(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviewer", Namespace: "default", UID: "1234-abcd"},
+	}
+
+	// Without inherited or configured attributes the variable is left unset
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "")
+	if value := buildAgentResourceAttributes(agent); value != "" {
+		t.Errorf("Expected no resource attributes, got %q", value)
+	}
+
+	// Configured values are percent-encoded; inherited ones are already encoded
+	t.Setenv("OTEL_RESOURCE_ATTRIBUTES", "owner=Platform%20Team")
+	agent.Spec.Telemetry = &langopv1alpha1.AgentTelemetrySpec{
+		ResourceAttributes: map[string]string{"cost.center": "eu-west, 50%", "tier": "gold=1"},
+	}
+	expected := "cost.center=eu-west%2C%2050%25,k8s.namespace.name=default,langop.agent.name=reviewer," +
+		"langop.agent.uid=1234-abcd,owner=Platform%20Team,tier=gold%3D1"
+	if value := buildAgentResourceAttributes(agent); value != expected {
+		t.Errorf("Expected %q, got %q", expected, value)
+	}
+}
+
+func TestLanguageAgentController_PinnedCodeVolume(t *testing.T) {
+	tests := []struct {
+		name      string
+		pin       string
+		configMap string
+	}{
+		{name: "not pinned", configMap: "pinned-agent-code"},
+		{name: "pinned", pin: "3", configMap: "pinned-agent-v3"},
+		{name: "pinned with prefix", pin: "v3", configMap: "pinned-agent-v3"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "pinned-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Instructions: "Summarize open issues",
+					ModelRefs:    []langopv1alpha1.ModelReference{{Name: "gpt-4o"}},
+				},
+			}
+			if tt.pin != "" {
+				agent.Annotations = map[string]string{langopv1alpha1.PinnedCodeVersionAnnotation: tt.pin}
+			}
+
+			volumes, _ := (&LanguageAgentReconciler{}).buildVolumes(agent)
+			var codeConfigMap string
+			for _, volume := range volumes {
+				if volume.Name == "agent-code" {
+					codeConfigMap = volume.ConfigMap.Name
+				}
+			}
+			if codeConfigMap != tt.configMap {
+				t.Errorf("Expected the code volume to mount %s, got %q", tt.configMap, codeConfigMap)
+			}
+		})
+	}
+}
+
 func TestLanguageAgentController_TmpfsVolumes(t *testing.T) {
 	scheme := testutil.SetupTestScheme(t)
 
@@ -1244,3 +1520,512 @@ func TestLanguageAgentController_CleanupMethods(t *testing.T) {
 		}
 	})
 }
+
+func TestLanguageAgentController_StrictCleanup(t *testing.T) {
+	tests := []struct {
+		name          string
+		strict        bool
+		deletedAgo    time.Duration
+		expectBlocked bool
+		expectedEvent string
+	}{
+		{name: "lenient cleanup proceeds on failure", strict: false, deletedAgo: time.Minute},
+		{name: "strict cleanup blocks until clean", strict: true, deletedAgo: time.Minute, expectBlocked: true, expectedEvent: "CleanupFailed"},
+		{name: "strict cleanup gives up after timeout", strict: true, deletedAgo: strictCleanupTimeout + time.Minute, expectedEvent: "CleanupAbandoned"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "test-agent",
+					Namespace:         "default",
+					DeletionTimestamp: &metav1.Time{Time: time.Now().Add(-tt.deletedAgo)},
+				},
+				Spec: langopv1alpha1.LanguageAgentSpec{StrictCleanup: tt.strict},
+			}
+
+			// Gateway namespaces are unreachable, so ReferenceGrant cleanup always fails
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithInterceptorFuncs(interceptor.Funcs{
+					List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+						if list.GetObjectKind().GroupVersionKind().Kind == "ReferenceGrantList" {
+							return fmt.Errorf("connection refused")
+						}
+						return c.List(ctx, list, opts...)
+					},
+				}).
+				Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &LanguageAgentReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Log:      logr.Discard(),
+				Recorder: recorder,
+			}
+
+			err := reconciler.cleanupResources(context.Background(), agent)
+			if blocked := err != nil; blocked != tt.expectBlocked {
+				t.Errorf("Expected blocked=%v, got error %v", tt.expectBlocked, err)
+			}
+
+			events := drainEvents(recorder)
+			if tt.expectedEvent != "" && !hasEvent(events, tt.expectedEvent) {
+				t.Errorf("Expected %s event, got %v", tt.expectedEvent, events)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_ImagePullPolicy(t *testing.T) {
+	tests := []struct {
+		name          string
+		agentPolicy   corev1.PullPolicy
+		clusterPolicy corev1.PullPolicy
+		expected      corev1.PullPolicy
+	}{
+		{name: "agent policy applies", agentPolicy: corev1.PullAlways, clusterPolicy: corev1.PullNever, expected: corev1.PullAlways},
+		{name: "cluster default applies", clusterPolicy: corev1.PullAlways, expected: corev1.PullAlways},
+		{name: "unset leaves Kubernetes default", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+
+			cluster := &langopv1alpha1.LanguageCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "pull-cluster", Namespace: "default"},
+				Spec:       langopv1alpha1.LanguageClusterSpec{ImagePullPolicy: tt.clusterPolicy},
+				Status:     langopv1alpha1.LanguageClusterStatus{Phase: "Ready"},
+			}
+			tool := &langopv1alpha1.LanguageTool{
+				ObjectMeta: metav1.ObjectMeta{Name: "sidecar-tool", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageToolSpec{
+					Image:          "ghcr.io/language-operator/tool:dev",
+					DeploymentMode: "sidecar",
+					Port:           3000,
+				},
+			}
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "pull-policy-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:           "ghcr.io/language-operator/agent:dev",
+					ImagePullPolicy: tt.agentPolicy,
+					ClusterRef:      "pull-cluster",
+					ExecutionMode:   "autonomous",
+					ToolRefs:        []langopv1alpha1.ToolReference{{Name: "sidecar-tool"}},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, tool, agent).
+				WithStatusSubresource(agent).
+				Build()
+
+			reconciler := &LanguageAgentReconciler{
+				Client:          fakeClient,
+				Scheme:          scheme,
+				Log:             logr.Discard(),
+				Recorder:        &record.FakeRecorder{},
+				RegistryManager: &mockRegistryManager{},
+			}
+			reconciler.InitializeGatewayCache()
+
+			ctx := context.Background()
+			key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			deployment := &appsv1.Deployment{}
+			if err := fakeClient.Get(ctx, key, deployment); err != nil {
+				t.Fatalf("Expected Deployment to exist, but got error: %v", err)
+			}
+
+			podSpec := deployment.Spec.Template.Spec
+			if got := podSpec.Containers[0].ImagePullPolicy; got != tt.expected {
+				t.Errorf("Expected agent container pull policy %q, got %q", tt.expected, got)
+			}
+			if len(podSpec.InitContainers) != 1 {
+				t.Fatalf("Expected 1 tool sidecar, got %d", len(podSpec.InitContainers))
+			}
+			if got := podSpec.InitContainers[0].ImagePullPolicy; got != tt.expected {
+				t.Errorf("Expected tool sidecar pull policy %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_SynthesisExamples(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	library := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "synthesis-examples", Namespace: "langop-system"},
+		Data: map[string]string{
+			"platform-team": `
+- name: github-triage
+  tags: [github]
+  instructions: Label new GitHub issues
+  code: agent "triage" do end
+- name: slack-digest
+  tags: [slack]
+  instructions: Post a digest to Slack
+  code: agent "digest" do end
+`,
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(library).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LanguageAgentReconciler{
+		Client:   fakeClient,
+		Scheme:   scheme,
+		Log:      logr.Discard(),
+		Recorder: recorder,
+		ExampleLibrary: &synthesis.ExampleLibrary{
+			Reader:    fakeClient,
+			Namespace: "langop-system",
+			Name:      "synthesis-examples",
+		},
+	}
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "digest", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Instructions:        "Post a summary of open incidents every morning",
+			SynthesisExampleSet: "platform-team",
+		},
+	}
+
+	examples := reconciler.synthesisExamples(context.Background(), agent, []string{"slack"})
+	if len(examples) != 2 || examples[0].Name != "slack-digest" {
+		t.Errorf("Expected the slack example to be selected first, got %+v", examples)
+	}
+
+	agent.Spec.SynthesisExampleSet = "unknown"
+	if examples := reconciler.synthesisExamples(context.Background(), agent, []string{"slack"}); examples != nil {
+		t.Errorf("Expected no examples for an unknown set, got %+v", examples)
+	}
+	if events := drainEvents(recorder); !hasEvent(events, "SynthesisExampleSetUnavailable") {
+		t.Errorf("Expected SynthesisExampleSetUnavailable event, got %v", events)
+	}
+
+	agent.Spec.SynthesisExampleSet = ""
+	if examples := reconciler.synthesisExamples(context.Background(), agent, nil); examples != nil {
+		t.Errorf("Expected no examples without an example set, got %+v", examples)
+	}
+}
+
+func TestLanguageAgentController_ModelRequestTimeout(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  string
+		expected string
+	}{
+		{name: "timeout in seconds", timeout: "90s", expected: "90"},
+		{name: "fractional seconds", timeout: "1500ms", expected: "1.5"},
+		{name: "unset", timeout: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "timeout-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:               "ghcr.io/language-operator/agent:latest",
+					ModelRequestTimeout: tt.timeout,
+				},
+			}
+			reconciler := &LanguageAgentReconciler{Log: logr.Discard()}
+
+			var value string
+			var found bool
+			for _, env := range reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil) {
+				if env.Name == "MODEL_REQUEST_TIMEOUT" {
+					value, found = env.Value, true
+				}
+			}
+			if found != (tt.expected != "") {
+				t.Fatalf("Expected MODEL_REQUEST_TIMEOUT set=%v, got set=%v", tt.expected != "", found)
+			}
+			if value != tt.expected {
+				t.Errorf("Expected MODEL_REQUEST_TIMEOUT %q, got %q", tt.expected, value)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_BuildAgentEnvTelemetryProtocol(t *testing.T) {
+	tests := []struct {
+		name             string
+		protocol         string
+		operatorEndpoint string
+		expectedEndpoint string
+		expectedProtocol string
+	}{
+		{name: "default rewrites grpc port", operatorEndpoint: "otel-collector:4317", expectedEndpoint: "http://otel-collector:4318", expectedProtocol: "http/protobuf"},
+		{name: "http rewrites grpc port", protocol: "http", operatorEndpoint: "http://otel-collector:4317", expectedEndpoint: "http://otel-collector:4318", expectedProtocol: "http/protobuf"},
+		{name: "http keeps https scheme", protocol: "http", operatorEndpoint: "https://otel-collector:4318", expectedEndpoint: "https://otel-collector:4318", expectedProtocol: "http/protobuf"},
+		{name: "grpc keeps grpc port", protocol: "grpc", operatorEndpoint: "otel-collector:4317", expectedEndpoint: "http://otel-collector:4317", expectedProtocol: "grpc"},
+		{name: "grpc keeps scheme", protocol: "grpc", operatorEndpoint: "https://otel-collector:4317", expectedEndpoint: "https://otel-collector:4317", expectedProtocol: "grpc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.operatorEndpoint)
+
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "otel-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image: "ghcr.io/language-operator/agent:latest",
+				},
+			}
+			if tt.protocol != "" {
+				agent.Spec.Telemetry = &langopv1alpha1.AgentTelemetrySpec{Protocol: tt.protocol}
+			}
+			reconciler := &LanguageAgentReconciler{Log: logr.Discard()}
+
+			envs := map[string]string{}
+			for _, env := range reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil) {
+				envs[env.Name] = env.Value
+			}
+			if got := envs["OTEL_EXPORTER_OTLP_ENDPOINT"]; got != tt.expectedEndpoint {
+				t.Errorf("Expected OTEL_EXPORTER_OTLP_ENDPOINT %q, got %q", tt.expectedEndpoint, got)
+			}
+			if got := envs["OTEL_EXPORTER_OTLP_PROTOCOL"]; got != tt.expectedProtocol {
+				t.Errorf("Expected OTEL_EXPORTER_OTLP_PROTOCOL %q, got %q", tt.expectedProtocol, got)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_BuildAgentEnvMetricsExporter(t *testing.T) {
+	tests := []struct {
+		name             string
+		telemetry        *langopv1alpha1.AgentTelemetrySpec
+		operatorEndpoint string
+		expectExporter   bool
+	}{
+		{name: "metrics disabled by default", operatorEndpoint: "otel-collector:4317"},
+		{name: "metrics explicitly disabled", telemetry: &langopv1alpha1.AgentTelemetrySpec{Protocol: "grpc"}, operatorEndpoint: "otel-collector:4317"},
+		{name: "metrics enabled", telemetry: &langopv1alpha1.AgentTelemetrySpec{MetricsEnabled: true}, operatorEndpoint: "otel-collector:4317", expectExporter: true},
+		{name: "metrics enabled without a collector", telemetry: &langopv1alpha1.AgentTelemetrySpec{MetricsEnabled: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.operatorEndpoint)
+
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "metrics-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:     "ghcr.io/language-operator/agent:latest",
+					Telemetry: tt.telemetry,
+				},
+			}
+			reconciler := &LanguageAgentReconciler{Log: logr.Discard()}
+
+			envs := map[string]string{}
+			for _, env := range reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil) {
+				envs[env.Name] = env.Value
+			}
+			exporter, ok := envs["OTEL_METRICS_EXPORTER"]
+			if ok != tt.expectExporter {
+				t.Fatalf("Expected OTEL_METRICS_EXPORTER set=%v, got %q (set=%v)", tt.expectExporter, exporter, ok)
+			}
+			if !tt.expectExporter {
+				return
+			}
+			if exporter != "otlp" {
+				t.Errorf("Expected OTEL_METRICS_EXPORTER otlp, got %q", exporter)
+			}
+			if envs["OTEL_EXPORTER_OTLP_ENDPOINT"] != "http://otel-collector:4318" || envs["OTEL_EXPORTER_OTLP_PROTOCOL"] != "http/protobuf" {
+				t.Errorf("Expected metrics to share the OTLP endpoint and protocol, got %q over %q",
+					envs["OTEL_EXPORTER_OTLP_ENDPOINT"], envs["OTEL_EXPORTER_OTLP_PROTOCOL"])
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_BuildAgentEnvReservedNames(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "env-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image: "ghcr.io/language-operator/agent:latest",
+			Env: []corev1.EnvVar{
+				{Name: "AGENT_NAME", Value: "impostor"},
+				{Name: "OTEL_SERVICE_NAME", Value: "custom"},
+				{Name: "SLACK_CHANNEL", Value: "#ops"},
+			},
+		},
+	}
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LanguageAgentReconciler{Log: logr.Discard(), Recorder: recorder}
+
+	counts := map[string]int{}
+	envs := map[string]string{}
+	for _, env := range reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil) {
+		counts[env.Name]++
+		envs[env.Name] = env.Value
+	}
+
+	if envs["AGENT_NAME"] != "env-agent" || counts["AGENT_NAME"] != 1 {
+		t.Errorf("Expected a single operator-managed AGENT_NAME=env-agent, got %q (%d entries)", envs["AGENT_NAME"], counts["AGENT_NAME"])
+	}
+	if envs["OTEL_SERVICE_NAME"] != "language-operator-agent-env-agent" || counts["OTEL_SERVICE_NAME"] != 1 {
+		t.Errorf("Expected a single operator-managed OTEL_SERVICE_NAME, got %q (%d entries)", envs["OTEL_SERVICE_NAME"], counts["OTEL_SERVICE_NAME"])
+	}
+	if envs["SLACK_CHANNEL"] != "#ops" {
+		t.Errorf("Expected user env SLACK_CHANNEL=#ops to be applied, got %q", envs["SLACK_CHANNEL"])
+	}
+
+	// The dropped names are reported once, and cleared when spec.env no longer sets them
+	condition := meta.FindStatusCondition(agent.Status.Conditions, ReservedEnvIgnoredCondition)
+	if condition == nil || !strings.Contains(condition.Message, "AGENT_NAME, OTEL_SERVICE_NAME") {
+		t.Errorf("Expected a %s condition naming the dropped variables, got %+v", ReservedEnvIgnoredCondition, condition)
+	}
+	reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil)
+	if events := drainEvents(recorder); len(events) != 1 || !hasEvent(events, "ReservedEnvIgnored") {
+		t.Errorf("Expected a single ReservedEnvIgnored event, got %v", events)
+	}
+
+	agent.Spec.Env = agent.Spec.Env[2:]
+	reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil)
+	if meta.FindStatusCondition(agent.Status.Conditions, ReservedEnvIgnoredCondition) != nil {
+		t.Errorf("Expected the %s condition to be removed", ReservedEnvIgnoredCondition)
+	}
+	if events := drainEvents(recorder); !hasEvent(events, "ReservedEnvResolved") {
+		t.Errorf("Expected a ReservedEnvResolved event, got %v", events)
+	}
+}
+
+func TestLanguageAgentController_BuildAgentEnvLogLevel(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	cluster := &langopv1alpha1.LanguageCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageClusterSpec{DefaultLogLevel: langopv1alpha1.LogLevelWarn},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(cluster).Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+	tests := []struct {
+		name       string
+		logLevel   string
+		clusterRef string
+		env        string
+		expected   string
+	}{
+		{name: "runtime default without cluster", expected: ""},
+		{name: "agent level", logLevel: "debug", expected: "debug"},
+		{name: "cluster default", clusterRef: "fleet", expected: "warn"},
+		{name: "agent level overrides cluster default", logLevel: "error", clusterRef: "fleet", expected: "error"},
+		{name: "missing cluster uses runtime default", clusterRef: "missing", expected: ""},
+		{name: "env level overrides cluster default", clusterRef: "fleet", env: "debug", expected: "debug"},
+		{name: "agent level overrides env level", logLevel: "error", env: "debug", expected: "error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "log-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:      "ghcr.io/language-operator/agent:latest",
+					LogLevel:   tt.logLevel,
+					ClusterRef: tt.clusterRef,
+				},
+			}
+			if tt.env != "" {
+				agent.Spec.Env = []corev1.EnvVar{{Name: "LOG_LEVEL", Value: tt.env}, {Name: "AGENT_LOG_LEVEL", Value: tt.env}}
+			}
+
+			envs := map[string]string{}
+			counts := map[string]int{}
+			for _, env := range reconciler.buildAgentEnv(context.Background(), agent, resolvedModels{}, nil, nil) {
+				envs[env.Name] = env.Value
+				counts[env.Name]++
+			}
+			for _, name := range []string{"LOG_LEVEL", "AGENT_LOG_LEVEL"} {
+				if counts[name] > 1 {
+					t.Errorf("Expected a single %s, got %d", name, counts[name])
+				}
+				value, set := envs[name]
+				if tt.expected == "" && set {
+					t.Errorf("Expected %s to be unset, got %q", name, value)
+				}
+				if tt.expected != "" && value != tt.expected {
+					t.Errorf("Expected %s=%q, got %q", name, tt.expected, value)
+				}
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_SynthesisCostEstimateExceedsQuota(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	inputCost, outputCost := 0.01, 0.03
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "expensive-model", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:  "openai",
+			ModelName: "gpt-4",
+			CostTracking: &langopv1alpha1.CostTrackingSpec{
+				Enabled:         true,
+				Currency:        "USD",
+				InputTokenCost:  &inputCost,
+				OutputTokenCost: &outputCost,
+			},
+		},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "budget-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:        "ghcr.io/language-operator/agent:latest",
+			Instructions: strings.Repeat("Summarize every open incident in detail. ", 200),
+			ModelRefs:    []langopv1alpha1.ModelReference{{Name: "expensive-model"}},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(model, agent).
+		WithStatusSubresource(agent).
+		Build()
+	recorder := record.NewFakeRecorder(20)
+	reconciler := &LanguageAgentReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		Log:             logr.Discard(),
+		Recorder:        recorder,
+		RegistryManager: &mockRegistryManager{},
+		// The estimate of about 0.25 USD for 8192 output tokens alone is over this quota
+		QuotaManager: synthesis.NewQuotaManager(0.1, 100, "USD", logr.Discard()),
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if !synthesis.IsCostEstimateExceeded(err) {
+		t.Fatalf("Expected a cost estimate error, got %v", err)
+	}
+
+	if events := drainEvents(recorder); !hasEvent(events, "SynthesisBudgetExceeded") || hasEvent(events, "SynthesisStarted") {
+		t.Errorf("Expected SynthesisBudgetExceeded before synthesis started, got %v", events)
+	}
+	if _, attempts, _ := reconciler.QuotaManager.GetNamespaceStats(agent.Namespace); attempts != 0 {
+		t.Errorf("Expected no synthesis attempt to be recorded, got %d", attempts)
+	}
+
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	synthesized := meta.FindStatusCondition(updated.Status.Conditions, "Synthesized")
+	if synthesized == nil || synthesized.Status != metav1.ConditionFalse || synthesized.Reason != synthesis.ReasonCostEstimateExceeded {
+		t.Errorf("Expected Synthesized=False with reason %s, got %+v", synthesis.ReasonCostEstimateExceeded, synthesized)
+	}
+	if updated.Status.FailureReason != langopv1alpha1.FailureReasonQuota {
+		t.Errorf("Expected failure reason %q, got %q", langopv1alpha1.FailureReasonQuota, updated.Status.FailureReason)
+	}
+}
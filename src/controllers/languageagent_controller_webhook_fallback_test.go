@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// newMisconfiguredGatewayObjects returns a cluster requiring TLS and a Gateway with only an HTTP listener,
+// so HTTPRoute creation fails TLS validation even though Gateway API is available
+func newMisconfiguredGatewayObjects(fallbackToIngress bool) (*langopv1alpha1.LanguageCluster, *langopv1alpha1.LanguageAgent, *unstructured.Unstructured) {
+	cluster := &langopv1alpha1.LanguageCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-namespace"},
+		Spec: langopv1alpha1.LanguageClusterSpec{
+			Domain: "example.com",
+			IngressConfig: &langopv1alpha1.IngressConfig{
+				GatewayName:       "http-only-gateway",
+				GatewayNamespace:  "gateway-system",
+				TLS:               &langopv1alpha1.IngressTLSConfig{Enabled: true},
+				FallbackToIngress: fallbackToIngress,
+			},
+		},
+	}
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "test-namespace"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{ClusterRef: "test-cluster"},
+		Status:     langopv1alpha1.LanguageAgentStatus{UUID: "test-uuid-123"},
+	}
+
+	gateway := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "Gateway",
+			"metadata": map[string]interface{}{
+				"name":      "http-only-gateway",
+				"namespace": "gateway-system",
+			},
+			"spec": map[string]interface{}{
+				"listeners": []interface{}{
+					map[string]interface{}{"name": "http", "protocol": "HTTP", "port": int64(80)},
+				},
+			},
+		},
+	}
+
+	return cluster, agent, gateway
+}
+
+func TestReconcileWebhooks_FallbackToIngressOnGatewayMisconfig(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	cluster, agent, gateway := newMisconfiguredGatewayObjects(true)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(cluster, agent, gateway).
+		WithStatusSubresource(agent).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+	reconciler.GatewayAPI = detectedGatewayAPI(true)
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent))
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+
+	// An Ingress must have been created in place of the HTTPRoute
+	ingress := &networkingv1.Ingress{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, ingress))
+
+	fallback := meta.FindStatusCondition(agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition)
+	require.NotNil(t, fallback)
+	assert.Equal(t, metav1.ConditionTrue, fallback.Status)
+	assert.Contains(t, fallback.Message, "Gateway TLS validation failed")
+
+	created := meta.FindStatusCondition(agent.Status.Conditions, langopv1alpha1.WebhookRouteCreatedCondition)
+	require.NotNil(t, created)
+	assert.Equal(t, metav1.ConditionTrue, created.Status)
+	assert.Equal(t, "IngressCreated", created.Reason)
+
+	select {
+	case event := <-recorder.Events:
+		assert.Contains(t, event, "WebhookRouteFallback")
+	default:
+		t.Error("Expected a WebhookRouteFallback event")
+	}
+}
+
+func TestReconcileWebhooks_NoFallbackFailsOnGatewayMisconfig(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	cluster, agent, gateway := newMisconfiguredGatewayObjects(false)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(cluster, agent, gateway).
+		WithStatusSubresource(agent).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	reconciler.GatewayAPI = detectedGatewayAPI(true)
+
+	err := reconciler.reconcileWebhooks(ctx, agent)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to reconcile HTTPRoute")
+
+	// No Ingress may be created when fallback is disabled
+	ingress := &networkingv1.Ingress{}
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, ingress)
+	assert.True(t, err != nil, "Expected no Ingress to be created")
+
+	assert.Nil(t, meta.FindStatusCondition(agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition))
+	created := meta.FindStatusCondition(agent.Status.Conditions, langopv1alpha1.WebhookRouteCreatedCondition)
+	require.NotNil(t, created)
+	assert.Equal(t, metav1.ConditionFalse, created.Status)
+	assert.Equal(t, "HTTPRouteCreationFailed", created.Reason)
+}
+
+func TestReconcileWebhooks_DeletesFallbackIngressOnceHTTPRouteReady(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	cluster, agent, gateway := newMisconfiguredGatewayObjects(true)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(cluster, agent, gateway).
+		WithStatusSubresource(agent).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	reconciler.GatewayAPI = detectedGatewayAPI(true)
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent))
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	require.NoError(t, fakeClient.Get(ctx, key, &networkingv1.Ingress{}))
+
+	// Fix the Gateway so the HTTPRoute can be created
+	require.NoError(t, unstructured.SetNestedSlice(gateway.Object, []interface{}{
+		map[string]interface{}{"name": "https", "protocol": "HTTPS", "port": int64(443)},
+	}, "spec", "listeners"))
+	require.NoError(t, fakeClient.Update(ctx, gateway))
+
+	// The Ingress keeps serving the webhook until the HTTPRoute is ready
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+	require.NoError(t, fakeClient.Get(ctx, key, &networkingv1.Ingress{}))
+
+	route := &unstructured.Unstructured{}
+	route.SetAPIVersion("gateway.networking.k8s.io/v1")
+	route.SetKind("HTTPRoute")
+	require.NoError(t, fakeClient.Get(ctx, key, route))
+	require.NoError(t, unstructured.SetNestedSlice(route.Object, []interface{}{
+		map[string]interface{}{
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Accepted", "status": "True"},
+				map[string]interface{}{"type": "Programmed", "status": "True"},
+			},
+		},
+	}, "status", "parents"))
+	require.NoError(t, fakeClient.Update(ctx, route))
+
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+	err := fakeClient.Get(ctx, key, &networkingv1.Ingress{})
+	assert.True(t, apierrors.IsNotFound(err), "Expected the fallback Ingress to be deleted, got %v", err)
+	assert.Nil(t, meta.FindStatusCondition(agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition))
+}
+
+func TestReconcileWebhooks_FallbackToIngressWhenHTTPRouteRejected(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	cluster, agent, gateway := newMisconfiguredGatewayObjects(true)
+	cluster.Spec.IngressConfig.TLS = nil
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(cluster, agent, gateway).
+		WithStatusSubresource(agent).
+		Build()
+
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+	reconciler.GatewayAPI = detectedGatewayAPI(true)
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	require.NoError(t, fakeClient.Get(ctx, key, agent))
+
+	// The HTTPRoute is created, but the Gateway rejects it
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+	route := &unstructured.Unstructured{}
+	route.SetAPIVersion("gateway.networking.k8s.io/v1")
+	route.SetKind("HTTPRoute")
+	require.NoError(t, fakeClient.Get(ctx, key, route))
+	setRouteParentConditions := func(conditions ...interface{}) {
+		require.NoError(t, fakeClient.Get(ctx, key, route))
+		require.NoError(t, unstructured.SetNestedSlice(route.Object, []interface{}{
+			map[string]interface{}{"conditions": conditions},
+		}, "status", "parents"))
+		require.NoError(t, fakeClient.Update(ctx, route))
+	}
+	setRouteParentConditions(map[string]interface{}{
+		"type": "Accepted", "status": "False", "reason": "NoMatchingListenerHostname", "message": "no matching listener",
+	})
+
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+	require.NoError(t, fakeClient.Get(ctx, key, &networkingv1.Ingress{}))
+	fallback := meta.FindStatusCondition(agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition)
+	require.NotNil(t, fallback)
+	assert.Equal(t, "HTTPRouteNotAccepted", fallback.Reason)
+	assert.Contains(t, fallback.Message, "no matching listener")
+
+	// While the route is accepted but not yet programmed, the Ingress keeps serving and the
+	// status keeps reporting the fallback
+	setRouteParentConditions(
+		map[string]interface{}{"type": "Accepted", "status": "True"},
+		map[string]interface{}{"type": "Programmed", "status": "False"},
+	)
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+	require.NoError(t, fakeClient.Get(ctx, key, &networkingv1.Ingress{}))
+	assert.True(t, meta.IsStatusConditionTrue(agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition))
+
+	// Once the route is ready, the Ingress and the fallback condition go together
+	setRouteParentConditions(
+		map[string]interface{}{"type": "Accepted", "status": "True"},
+		map[string]interface{}{"type": "Programmed", "status": "True"},
+	)
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+	err := fakeClient.Get(ctx, key, &networkingv1.Ingress{})
+	assert.True(t, apierrors.IsNotFound(err), "Expected the fallback Ingress to be deleted, got %v", err)
+	assert.Nil(t, meta.FindStatusCondition(agent.Status.Conditions, langopv1alpha1.WebhookRouteFallbackCondition))
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileHTTPRoute_RecordsRouteAttachment(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	cluster := &langopv1alpha1.LanguageCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "test-namespace"},
+		Spec: langopv1alpha1.LanguageClusterSpec{
+			Domain: "example.com",
+			IngressConfig: &langopv1alpha1.IngressConfig{
+				GatewayName:        "public-gateway",
+				GatewayNamespace:   "gateway-system",
+				GatewaySectionName: "websecure",
+				TLS:                &langopv1alpha1.IngressTLSConfig{Enabled: true},
+			},
+		},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "test-namespace"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{ClusterRef: "test-cluster"},
+		Status:     langopv1alpha1.LanguageAgentStatus{UUID: "test-uuid-123"},
+	}
+	gateway := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "Gateway",
+			"metadata": map[string]interface{}{
+				"name":      "public-gateway",
+				"namespace": "gateway-system",
+			},
+			"spec": map[string]interface{}{
+				"listeners": []interface{}{
+					map[string]interface{}{"name": "web", "protocol": "HTTP", "port": int64(80)},
+					map[string]interface{}{"name": "websecure", "protocol": "HTTPS", "port": int64(443)},
+				},
+			},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(cluster, agent, gateway).
+		WithStatusSubresource(agent).
+		Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	reconciler.GatewayAPI = detectedGatewayAPI(true)
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent))
+	require.NoError(t, reconciler.reconcileHTTPRoute(ctx, agent, "test-uuid-123.example.com"))
+
+	require.NotNil(t, agent.Status.WebhookRoute)
+	assert.Equal(t, langopv1alpha1.WebhookRouteStatus{
+		GatewayName:      "public-gateway",
+		GatewayNamespace: "gateway-system",
+		SectionName:      "websecure",
+		Protocol:         "https",
+	}, *agent.Status.WebhookRoute)
+
+	// The HTTPRoute targets the configured listener
+	httpRoute := &unstructured.Unstructured{}
+	httpRoute.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"})
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, httpRoute))
+	parentRefs, found, err := unstructured.NestedSlice(httpRoute.Object, "spec", "parentRefs")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, parentRefs, 1)
+	assert.Equal(t, "websecure", parentRefs[0].(map[string]interface{})["sectionName"])
+
+	// Moving the agent to a plain HTTP Gateway without a listener updates the attachment
+	cluster.Spec.IngressConfig = &langopv1alpha1.IngressConfig{GatewayName: "internal-gateway"}
+	require.NoError(t, fakeClient.Update(ctx, cluster))
+	require.NoError(t, reconciler.reconcileHTTPRoute(ctx, agent, "test-uuid-123.example.com"))
+
+	require.NotNil(t, agent.Status.WebhookRoute)
+	assert.Equal(t, langopv1alpha1.WebhookRouteStatus{
+		GatewayName:      "internal-gateway",
+		GatewayNamespace: "test-namespace",
+		Protocol:         "http",
+	}, *agent.Status.WebhookRoute)
+}
+
+func TestReconcileWebhooks_FallbackClearsRouteAttachment(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	cluster, agent, gateway := newMisconfiguredGatewayObjects(true)
+	agent.Status.WebhookRoute = &langopv1alpha1.WebhookRouteStatus{
+		GatewayName:      "http-only-gateway",
+		GatewayNamespace: "gateway-system",
+		Protocol:         "http",
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).
+		WithObjects(cluster, agent, gateway).
+		WithStatusSubresource(agent).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	reconciler.GatewayAPI = detectedGatewayAPI(true)
+
+	require.NoError(t, reconciler.reconcileWebhooks(ctx, agent))
+	assert.Nil(t, agent.Status.WebhookRoute, "Expected no Gateway attachment when webhooks are served through an Ingress")
+}
+
+func TestReconcileWebhooks_RoutesPathPrefixesToPorts(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "test-namespace"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			WebhookRoutes: []langopv1alpha1.WebhookRouteRule{
+				{PathPrefix: "/webhook"},
+				{PathPrefix: "/api/v1", Port: 9090},
+				{PathPrefix: "/metrics", Port: 9090},
+			},
+		},
+		Status: langopv1alpha1.LanguageAgentStatus{UUID: "test-uuid-123"},
+	}
+	gateway := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "gateway.networking.k8s.io/v1",
+			"kind":       "Gateway",
+			"metadata":   map[string]interface{}{"name": "default", "namespace": "default"},
+			"spec": map[string]interface{}{
+				"listeners": []interface{}{
+					map[string]interface{}{"name": "web", "protocol": "HTTP", "port": int64(80)},
+				},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent, gateway).Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+	reconciler.GatewayAPI = detectedGatewayAPI(true)
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+
+	// The Service exposes the webhook server and each other routed container port once
+	require.NoError(t, reconciler.reconcileService(ctx, agent))
+	service := &corev1.Service{}
+	require.NoError(t, fakeClient.Get(ctx, key, service))
+	require.Len(t, service.Spec.Ports, 2)
+	assert.Equal(t, int32(80), service.Spec.Ports[0].Port)
+	assert.Equal(t, int32(8080), service.Spec.Ports[0].TargetPort.IntVal)
+	assert.Equal(t, "route-9090", service.Spec.Ports[1].Name)
+	assert.Equal(t, int32(9090), service.Spec.Ports[1].Port)
+	assert.Equal(t, int32(9090), service.Spec.Ports[1].TargetPort.IntVal)
+
+	// The agent container declares the other routed port, so no sidecar tool can take it
+	containerPorts := agentContainerPorts(agent)
+	require.Len(t, containerPorts, 1)
+	assert.Equal(t, int32(9090), containerPorts[0].ContainerPort)
+
+	expected := []struct {
+		prefix string
+		port   int64
+	}{
+		{"/webhook", 80},
+		{"/api/v1", 9090},
+		{"/metrics", 9090},
+	}
+
+	// One HTTPRoute rule per route
+	require.NoError(t, reconciler.reconcileHTTPRoute(ctx, agent, "test-uuid-123.example.com"))
+	httpRoute := &unstructured.Unstructured{}
+	httpRoute.SetGroupVersionKind(schema.GroupVersionKind{Group: "gateway.networking.k8s.io", Version: "v1", Kind: "HTTPRoute"})
+	require.NoError(t, fakeClient.Get(ctx, key, httpRoute))
+	rules, found, err := unstructured.NestedSlice(httpRoute.Object, "spec", "rules")
+	require.NoError(t, err)
+	require.True(t, found)
+	require.Len(t, rules, len(expected))
+	for i, rule := range rules {
+		matches, _, _ := unstructured.NestedSlice(rule.(map[string]interface{}), "matches")
+		require.Len(t, matches, 1)
+		path, _, _ := unstructured.NestedString(matches[0].(map[string]interface{}), "path", "value")
+		assert.Equal(t, expected[i].prefix, path)
+		backendRefs, _, _ := unstructured.NestedSlice(rule.(map[string]interface{}), "backendRefs")
+		require.Len(t, backendRefs, 1)
+		port, _, _ := unstructured.NestedInt64(backendRefs[0].(map[string]interface{}), "port")
+		assert.Equal(t, expected[i].port, port)
+	}
+
+	// One Ingress path per route
+	require.NoError(t, reconciler.reconcileIngress(ctx, agent, "test-uuid-123.example.com"))
+	ingress := &networkingv1.Ingress{}
+	require.NoError(t, fakeClient.Get(ctx, key, ingress))
+	require.Len(t, ingress.Spec.Rules, 1)
+	paths := ingress.Spec.Rules[0].HTTP.Paths
+	require.Len(t, paths, len(expected))
+	for i, path := range paths {
+		assert.Equal(t, expected[i].prefix, path.Path)
+		assert.Equal(t, int32(expected[i].port), path.Backend.Service.Port.Number)
+	}
+
+	// Without routes, all paths go to the webhook server
+	agent.Spec.WebhookRoutes = nil
+	require.NoError(t, reconciler.reconcileIngress(ctx, agent, "test-uuid-123.example.com"))
+	require.NoError(t, fakeClient.Get(ctx, key, ingress))
+	require.Len(t, ingress.Spec.Rules[0].HTTP.Paths, 1)
+	assert.Equal(t, "/", ingress.Spec.Rules[0].HTTP.Paths[0].Path)
+	assert.Equal(t, int32(80), ingress.Spec.Rules[0].HTTP.Paths[0].Backend.Service.Port.Number)
+}
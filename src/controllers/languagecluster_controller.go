@@ -48,6 +48,8 @@ type LanguageClusterReconciler struct {
 //+kubebuilder:rbac:groups=langop.io,resources=languageclusters/finalizers,verbs=update
 //+kubebuilder:rbac:groups=langop.io,resources=languageagents,verbs=get;list;delete
 //+kubebuilder:rbac:groups=langop.io,resources=languagetools,verbs=get;list;delete
+//+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch
+//+kubebuilder:rbac:groups=batch,resources=cronjobs,verbs=get;list;watch
 
 // Reconcile is part of the main kubernetes reconciliation loop
 func (r *LanguageClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
@@ -121,6 +123,16 @@ func (r *LanguageClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		r.validateDNS(ctx, cluster)
 	}
 
+	// Track agents rolling to the cluster's default agent image
+	requeueAfter, err := r.updateImageUpgradeStatus(ctx, cluster)
+	if err != nil {
+		log.Error(err, "Failed to update agent image upgrade status")
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to update agent image upgrade status")
+		reconcileErr = err
+		return ctrl.Result{}, err
+	}
+
 	// LanguageCluster is now just a logical grouping - no namespace management
 	// Child resources reference the cluster and live in the same namespace
 	cluster.Status.Phase = "Ready"
@@ -136,7 +148,7 @@ func (r *LanguageClusterReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	}
 
 	span.SetStatus(codes.Ok, "Reconciliation successful")
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // cleanupDependentResources removes all resources that reference this cluster
@@ -21,6 +21,9 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/go-logr/logr"
 	"go.opentelemetry.io/otel"
@@ -29,9 +32,12 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -41,11 +47,48 @@ import (
 	"github.com/language-operator/language-operator/pkg/reconciler"
 )
 
+// Model deletion policies for LanguageModels still referenced by agents
+const (
+	// ModelDeletionPolicyBlock keeps a referenced model until no agent references it
+	ModelDeletionPolicyBlock = "block"
+	// ModelDeletionPolicyWarn deletes a referenced model and marks its dependent agents with a ModelDeleted condition
+	ModelDeletionPolicyWarn = "warn"
+)
+
+// ForceDeleteAnnotation lets a referenced LanguageModel be deleted under the block policy. Dependent
+// agents are marked with a ModelDeleted condition, as with the warn policy.
+const ForceDeleteAnnotation = "langop.io/force-delete"
+
+// modelDeletionRecheckInterval is how often a blocked deletion re-checks for dependent agents
+const modelDeletionRecheckInterval = 30 * time.Second
+
 // LanguageModelReconciler reconciles a LanguageModel object
 type LanguageModelReconciler struct {
 	client.Client
-	Scheme *runtime.Scheme
-	Log    logr.Logger
+	Scheme   *runtime.Scheme
+	Log      logr.Logger
+	Recorder record.EventRecorder
+	// DeletionPolicy controls deleting a model that agents still reference (block or warn, defaults to block)
+	DeletionPolicy string
+	// HealthChecker probes the provider endpoint of each model (nil disables health checks)
+	HealthChecker ModelHealthChecker
+	// HealthCheckInterval is how often a reachable model is checked. Failed checks back off
+	// exponentially from it. Zero disables health checks.
+	HealthCheckInterval time.Duration
+	// RateLimits observes each model's usage against its spec.rateLimits (nil disables tracking)
+	RateLimits *RateLimitTracker
+}
+
+// ParseModelDeletionPolicy validates a model deletion policy, defaulting to block when empty
+func ParseModelDeletionPolicy(policy string) (string, error) {
+	switch policy {
+	case "", ModelDeletionPolicyBlock:
+		return ModelDeletionPolicyBlock, nil
+	case ModelDeletionPolicyWarn:
+		return ModelDeletionPolicyWarn, nil
+	default:
+		return "", fmt.Errorf("unknown model deletion policy %q, expected %q or %q", policy, ModelDeletionPolicyBlock, ModelDeletionPolicyWarn)
+	}
 }
 
 // modelTracer is used by methods that haven't been refactored yet
@@ -54,6 +97,8 @@ var modelTracer = otel.Tracer("language-operator/model-controller")
 //+kubebuilder:rbac:groups=langop.io,resources=languagemodels,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=langop.io,resources=languagemodels/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=langop.io,resources=languagemodels/finalizers,verbs=update
+//+kubebuilder:rbac:groups=langop.io,resources=languageagents,verbs=get;list;watch
+//+kubebuilder:rbac:groups=langop.io,resources=languageagents/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
@@ -110,6 +155,9 @@ func (r *LanguageModelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Status as stored, so periodic health and rate limit checks only write it when it changed
+	persistedStatus := model.Status.DeepCopy()
+
 	// Reconcile the ConfigMap
 	if err := r.reconcileConfigMap(ctx, model); err != nil {
 		log.Error(err, "Failed to reconcile ConfigMap")
@@ -166,23 +214,38 @@ func (r *LanguageModelReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 		return ctrl.Result{}, err
 	}
 
+	// Check that the provider endpoint behind the proxy is reachable
+	nextHealthCheck := r.reconcileHealthCheck(ctx, model)
+
+	// Observe usage against the model's rate limits
+	nextRateLimitCheck := r.reconcileRateLimit(ctx, model)
+
 	// Update status
 	model.Status.ObservedGeneration = model.Generation
 	model.Status.Phase = "Ready"
+	if meta.IsStatusConditionFalse(model.Status.Conditions, ModelReachableCondition) {
+		model.Status.Phase = "Degraded"
+	}
 	// Status fields updated
 	SetCondition(&model.Status.Conditions, "Ready", metav1.ConditionTrue, "ReconcileSuccess", "Model proxy is ready", model.Generation)
 
-	if err := r.Status().Update(ctx, model); err != nil {
-		log.Error(err, "Failed to update status")
-		span.RecordError(err)
-		span.SetStatus(codes.Error, "Failed to update status")
-		reconcileErr = err
-		return ctrl.Result{}, err
+	if !equality.Semantic.DeepEqual(persistedStatus, &model.Status) {
+		if err := r.Status().Update(ctx, model); err != nil {
+			log.Error(err, "Failed to update status")
+			span.RecordError(err)
+			span.SetStatus(codes.Error, "Failed to update status")
+			reconcileErr = err
+			return ctrl.Result{}, err
+		}
 	}
 
 	log.Info("Successfully reconciled LanguageModel")
 	span.SetStatus(codes.Ok, "Reconciliation successful")
-	return ctrl.Result{}, nil
+	requeueAfter := nextHealthCheck
+	if nextRateLimitCheck > 0 && (requeueAfter == 0 || nextRateLimitCheck < requeueAfter) {
+		requeueAfter = nextRateLimitCheck
+	}
+	return ctrl.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // reconcileConfigMap creates or updates the ConfigMap for the model
@@ -478,6 +541,22 @@ func (r *LanguageModelReconciler) handleDeletion(ctx context.Context, model *lan
 	log := log.FromContext(ctx)
 
 	if controllerutil.ContainsFinalizer(model, FinalizerName) {
+		dependents, err := r.findDependentAgents(ctx, model)
+		if err != nil {
+			log.Error(err, "Failed to find dependent agents")
+			return ctrl.Result{}, err
+		}
+
+		if len(dependents) > 0 {
+			if r.DeletionPolicy != ModelDeletionPolicyWarn && model.Annotations[ForceDeleteAnnotation] != "true" {
+				return r.blockDeletion(ctx, model, dependents)
+			}
+			if err := r.markModelDeleted(ctx, model, dependents); err != nil {
+				log.Error(err, "Failed to mark dependent agents")
+				return ctrl.Result{}, err
+			}
+		}
+
 		// Delete the ConfigMap
 		configMapName := GenerateConfigMapName(model.Name, "model")
 		if err := DeleteConfigMap(ctx, r.Client, configMapName, model.Namespace); err != nil {
@@ -496,6 +575,83 @@ func (r *LanguageModelReconciler) handleDeletion(ctx context.Context, model *lan
 	return ctrl.Result{}, nil
 }
 
+// findDependentAgents returns the agents, in any namespace, that reference the model
+func (r *LanguageModelReconciler) findDependentAgents(ctx context.Context, model *langopv1alpha1.LanguageModel) ([]langopv1alpha1.LanguageAgent, error) {
+	agentList := &langopv1alpha1.LanguageAgentList{}
+	if err := r.List(ctx, agentList); err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	var dependents []langopv1alpha1.LanguageAgent
+	for _, agent := range agentList.Items {
+		if !agent.DeletionTimestamp.IsZero() {
+			continue
+		}
+		for _, ref := range agent.Spec.ModelRefs {
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = agent.Namespace
+			}
+			if ref.Name == model.Name && namespace == model.Namespace {
+				dependents = append(dependents, agent)
+				break
+			}
+		}
+	}
+	return dependents, nil
+}
+
+// blockDeletion keeps a referenced model in place and reports the agents that still depend on it
+func (r *LanguageModelReconciler) blockDeletion(ctx context.Context, model *langopv1alpha1.LanguageModel, dependents []langopv1alpha1.LanguageAgent) (ctrl.Result, error) {
+	log := log.FromContext(ctx)
+
+	names := make([]string, 0, len(dependents))
+	for _, agent := range dependents {
+		names = append(names, agent.Namespace+"/"+agent.Name)
+	}
+	message := fmt.Sprintf("Deletion blocked: model is referenced by agents %s. Remove the references or set the %s=true annotation to delete it anyway",
+		strings.Join(names, ", "), ForceDeleteAnnotation)
+
+	// Rechecks that find the same dependents leave the status and events alone
+	if SetCondition(&model.Status.Conditions, "DeletionBlocked", metav1.ConditionTrue, "DependentAgents", message, model.Generation) {
+		log.Info("Blocking deletion of referenced model", "dependents", names)
+		if err := r.Status().Update(ctx, model); err != nil {
+			log.Error(err, "Failed to update status")
+			return ctrl.Result{}, err
+		}
+		if r.Recorder != nil {
+			r.Recorder.Event(model, corev1.EventTypeWarning, "DeletionBlocked", message)
+		}
+	}
+
+	return ctrl.Result{RequeueAfter: modelDeletionRecheckInterval}, nil
+}
+
+// markModelDeleted sets a ModelDeleted condition on each dependent agent so it degrades gracefully
+// instead of failing to resolve the model on its next reconcile
+func (r *LanguageModelReconciler) markModelDeleted(ctx context.Context, model *langopv1alpha1.LanguageModel, dependents []langopv1alpha1.LanguageAgent) error {
+	log := log.FromContext(ctx)
+
+	for i := range dependents {
+		agent := &dependents[i]
+		if key := model.Namespace + "/" + model.Name; !slices.Contains(agent.Status.DeletedModels, key) {
+			agent.Status.DeletedModels = append(agent.Status.DeletedModels, key)
+		}
+		SetCondition(&agent.Status.Conditions, "ModelDeleted", metav1.ConditionTrue, "ModelDeleted",
+			fmt.Sprintf("LanguageModel %s/%s was deleted while this agent referenced it", model.Namespace, model.Name), agent.Generation)
+		if err := r.Status().Update(ctx, agent); err != nil {
+			return fmt.Errorf("failed to mark agent %s/%s: %w", agent.Namespace, agent.Name, err)
+		}
+		log.Info("Marked dependent agent of deleted model", "agent", agent.Name, "agentNamespace", agent.Namespace)
+	}
+
+	if r.Recorder != nil {
+		r.Recorder.Eventf(model, corev1.EventTypeWarning, "DependentsMarked",
+			"Deleted while referenced by %d agents; they were marked with a ModelDeleted condition", len(dependents))
+	}
+	return nil
+}
+
 // SetupWithManager sets up the controller with the Manager
 func (r *LanguageModelReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int) error {
 	return ctrl.NewControllerManagedBy(mgr).
@@ -2,7 +2,9 @@ package controllers
 
 import (
 	"context"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-logr/logr"
 	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
@@ -10,10 +12,15 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 )
 
@@ -254,6 +261,44 @@ func TestLanguageModelController_StatusUpdates(t *testing.T) {
 	}
 }
 
+func TestLanguageModelController_StatusWrittenOnChange(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:  "anthropic",
+			ModelName: "claude-3-5-sonnet-20241022",
+		},
+	}
+
+	statusUpdates := 0
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(model).
+		WithStatusSubresource(model).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				statusUpdates++
+				return c.SubResource(subResource).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	reconciler := &LanguageModelReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: model.Name, Namespace: model.Namespace}}
+
+	// The first reconcile adds the finalizer, the second marks the model ready, the rest change nothing
+	for i := 0; i < 4; i++ {
+		if _, err := reconciler.Reconcile(context.Background(), req); err != nil {
+			t.Fatalf("Reconcile %d failed: %v", i, err)
+		}
+	}
+	if statusUpdates != 1 {
+		t.Errorf("Expected a single status write, got %d", statusUpdates)
+	}
+}
+
 func TestLanguageModelController_APIKeySecretMount(t *testing.T) {
 	scheme := testutil.SetupTestScheme(t)
 
@@ -857,3 +902,253 @@ func TestLanguageModelController_Finalizer(t *testing.T) {
 		t.Error("Expected finalizer to be added after first reconcile")
 	}
 }
+
+func TestLanguageModelController_DeletionWithDependentAgents(t *testing.T) {
+	tests := []struct {
+		name        string
+		policy      string
+		annotations map[string]string
+		expectBlock bool
+	}{
+		{name: "blocked by default", expectBlock: true},
+		{name: "forced by annotation", annotations: map[string]string{ForceDeleteAnnotation: "true"}},
+		{name: "warn policy", policy: ModelDeletionPolicyWarn},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+
+			model := &langopv1alpha1.LanguageModel{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              "shared-model",
+					Namespace:         "default",
+					Annotations:       tt.annotations,
+					Finalizers:        []string{FinalizerName},
+					DeletionTimestamp: &metav1.Time{Time: time.Now()},
+				},
+				Spec: langopv1alpha1.LanguageModelSpec{
+					Provider:  "openai",
+					ModelName: "gpt-4",
+				},
+			}
+			// Agents in other namespaces reference the model with an explicit namespace
+			dependent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "dependent-agent", Namespace: "team-a"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					ModelRefs: []langopv1alpha1.ModelReference{{Name: "shared-model", Namespace: "default"}},
+				},
+			}
+			unrelated := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated-agent", Namespace: "team-a"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					ModelRefs: []langopv1alpha1.ModelReference{{Name: "shared-model"}},
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(model, dependent, unrelated).
+				WithStatusSubresource(model, dependent, unrelated).
+				Build()
+
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &LanguageModelReconciler{
+				Client:         fakeClient,
+				Scheme:         scheme,
+				Log:            logr.Discard(),
+				Recorder:       recorder,
+				DeletionPolicy: tt.policy,
+			}
+
+			ctx := context.Background()
+			key := types.NamespacedName{Name: model.Name, Namespace: model.Namespace}
+			result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+			if err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			updatedModel := &langopv1alpha1.LanguageModel{}
+			getErr := fakeClient.Get(ctx, key, updatedModel)
+
+			updatedAgent := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: dependent.Name, Namespace: dependent.Namespace}, updatedAgent); err != nil {
+				t.Fatalf("Failed to get dependent agent: %v", err)
+			}
+
+			if tt.expectBlock {
+				if getErr != nil {
+					t.Fatalf("Expected blocked model to remain, got %v", getErr)
+				}
+				if !controllerutil.ContainsFinalizer(updatedModel, FinalizerName) {
+					t.Error("Expected finalizer to be kept while agents reference the model")
+				}
+				condition := meta.FindStatusCondition(updatedModel.Status.Conditions, "DeletionBlocked")
+				if condition == nil || !strings.Contains(condition.Message, "team-a/dependent-agent") {
+					t.Fatalf("Expected DeletionBlocked condition listing the dependent agent, got %+v", condition)
+				}
+				if strings.Contains(condition.Message, "unrelated-agent") {
+					t.Errorf("Expected agents referencing another namespace's model not to be listed, got %q", condition.Message)
+				}
+				if result.RequeueAfter == 0 {
+					t.Error("Expected blocked deletion to be re-checked")
+				}
+				if meta.FindStatusCondition(updatedAgent.Status.Conditions, "ModelDeleted") != nil {
+					t.Error("Expected dependent agent not to be marked while deletion is blocked")
+				}
+
+				// Rechecking the same dependents doesn't rewrite the status or repeat the event
+				if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+					t.Fatalf("Reconcile failed: %v", err)
+				}
+				if events := drainEvents(recorder); len(events) != 1 || !hasEvent(events, "DeletionBlocked") {
+					t.Errorf("Expected a single DeletionBlocked event, got %v", events)
+				}
+				rechecked := &langopv1alpha1.LanguageModel{}
+				if err := fakeClient.Get(ctx, key, rechecked); err != nil {
+					t.Fatalf("Failed to get model: %v", err)
+				}
+				if rechecked.ResourceVersion != updatedModel.ResourceVersion {
+					t.Error("Expected an unchanged blocked deletion not to update the model")
+				}
+				return
+			}
+
+			if getErr == nil && controllerutil.ContainsFinalizer(updatedModel, FinalizerName) {
+				t.Error("Expected finalizer to be removed for forced deletion")
+			} else if getErr != nil && !errors.IsNotFound(getErr) {
+				t.Fatalf("Failed to get model: %v", getErr)
+			}
+			if !meta.IsStatusConditionTrue(updatedAgent.Status.Conditions, "ModelDeleted") {
+				t.Errorf("Expected dependent agent to have ModelDeleted condition, got %+v", updatedAgent.Status.Conditions)
+			}
+			if deleted := updatedAgent.Status.DeletedModels; len(deleted) != 1 || deleted[0] != model.Namespace+"/"+model.Name {
+				t.Errorf("Expected dependent agent to record the deleted model, got %v", deleted)
+			}
+
+			unrelatedAgent := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: unrelated.Name, Namespace: unrelated.Namespace}, unrelatedAgent); err != nil {
+				t.Fatalf("Failed to get unrelated agent: %v", err)
+			}
+			if meta.FindStatusCondition(unrelatedAgent.Status.Conditions, "ModelDeleted") != nil {
+				t.Error("Expected unrelated agent not to be marked")
+			}
+		})
+	}
+}
+
+func TestResolveModels_SkipsDeletedModel(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "degraded-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ModelRefs: []langopv1alpha1.ModelReference{{Name: "deleted-model"}},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+	ctx := context.Background()
+
+	if _, err := reconciler.resolveModels(ctx, agent); err == nil {
+		t.Fatal("Expected a missing model to fail resolution without a ModelDeleted condition")
+	}
+
+	SetCondition(&agent.Status.Conditions, "ModelDeleted", metav1.ConditionTrue, "ModelDeleted", "LanguageModel default/deleted-model was deleted", agent.Generation)
+	agent.Status.DeletedModels = []string{"default/deleted-model"}
+	models, err := reconciler.resolveModels(ctx, agent)
+	if err != nil {
+		t.Fatalf("Expected deleted model to be skipped, got %v", err)
+	}
+	if len(models.URLs) != 0 {
+		t.Errorf("Expected no model URLs, got %v", models.URLs)
+	}
+	condition := meta.FindStatusCondition(agent.Status.Conditions, "ModelDeleted")
+	if condition == nil || condition.Reason != "ModelsMissing" || !strings.Contains(condition.Message, "default/deleted-model") {
+		t.Errorf("Expected ModelDeleted condition naming the missing model, got %+v", condition)
+	}
+
+	// A newly added reference to a model that doesn't exist still fails resolution
+	agent.Spec.ModelRefs = append(agent.Spec.ModelRefs, langopv1alpha1.ModelReference{Name: "mistyped-model"})
+	if _, err := reconciler.resolveModels(ctx, agent); err == nil {
+		t.Error("Expected a missing model that wasn't deleted to fail resolution")
+	}
+
+	// Removing the deleted model's reference forgets it and clears the condition
+	agent.Spec.ModelRefs = nil
+	if _, err := reconciler.resolveModels(ctx, agent); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(agent.Status.DeletedModels) != 0 || meta.FindStatusCondition(agent.Status.Conditions, "ModelDeleted") != nil {
+		t.Errorf("Expected the deleted model to be forgotten, got %v and %+v", agent.Status.DeletedModels, agent.Status.Conditions)
+	}
+}
+
+func TestResolveModels_OrdersByRole(t *testing.T) {
+	tests := []struct {
+		name           string
+		refs           []langopv1alpha1.ModelReference
+		expectModels   string
+		expectRoles    string
+		expectEndpoint string
+	}{
+		{
+			name: "mixed roles",
+			refs: []langopv1alpha1.ModelReference{
+				{Name: "llama", Role: langopv1alpha1.ModelRoleFallback},
+				{Name: "summarizer", Role: "summarization"},
+				{Name: "gpt", Role: langopv1alpha1.ModelRolePrimary},
+				{Name: "mistral", Role: langopv1alpha1.ModelRoleFallback},
+			},
+			expectModels:   "gpt-4o,claude-3-haiku,llama3,mistral-large",
+			expectRoles:    "primary,summarization,fallback,fallback",
+			expectEndpoint: "http://gpt.default.svc.cluster.local:8000",
+		},
+		{
+			name:           "no roles keep the declared order",
+			refs:           []langopv1alpha1.ModelReference{{Name: "llama"}, {Name: "gpt"}},
+			expectModels:   "llama3,gpt-4o",
+			expectRoles:    "primary,primary",
+			expectEndpoint: "http://llama.default.svc.cluster.local:8000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			var objects []client.Object
+			for name, modelName := range map[string]string{"gpt": "gpt-4o", "summarizer": "claude-3-haiku", "llama": "llama3", "mistral": "mistral-large"} {
+				objects = append(objects, &langopv1alpha1.LanguageModel{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+					Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai-compatible", ModelName: modelName},
+				})
+			}
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "failover-agent", Namespace: "default"},
+				Spec:       langopv1alpha1.LanguageAgentSpec{ModelRefs: tt.refs},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+			reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+			models, err := reconciler.resolveModels(context.Background(), agent)
+			if err != nil {
+				t.Fatalf("resolveModels failed: %v", err)
+			}
+
+			env := map[string]string{}
+			for _, e := range reconciler.buildAgentEnv(context.Background(), agent, models, nil, nil) {
+				env[e.Name] = e.Value
+			}
+			if env["LLM_MODEL"] != tt.expectModels {
+				t.Errorf("Expected LLM_MODEL %q, got %q", tt.expectModels, env["LLM_MODEL"])
+			}
+			if env["MODEL_ROLES"] != tt.expectRoles {
+				t.Errorf("Expected MODEL_ROLES %q, got %q", tt.expectRoles, env["MODEL_ROLES"])
+			}
+			if first := strings.Split(env["MODEL_ENDPOINTS"], ",")[0]; first != tt.expectEndpoint {
+				t.Errorf("Expected first endpoint %q, got %q", tt.expectEndpoint, first)
+			}
+		})
+	}
+}
@@ -19,6 +19,8 @@ package controllers
 import (
 	"context"
 	"encoding/json"
+	"slices"
+	"strings"
 
 	"github.com/go-logr/logr"
 	"go.opentelemetry.io/otel/codes"
@@ -90,6 +92,23 @@ func (r *LanguagePersonaReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{Requeue: true}, nil
 	}
 
+	// Agents refuse personas that aren't Ready, so an invalid persona stays NotReady until it's fixed
+	if errs := persona.ValidateSpec(); len(errs) > 0 {
+		log.Info("Persona failed validation", "errors", errs)
+		recordPersonaValidation(persona, errs)
+		persona.Status.ObservedGeneration = persona.Generation
+		persona.Status.Phase = "NotReady"
+		SetCondition(&persona.Status.Conditions, "Ready", metav1.ConditionFalse, "ValidationFailed", strings.Join(errs, "; "), persona.Generation)
+		if err := r.Status().Update(ctx, persona); err != nil {
+			log.Error(err, "Failed to update status")
+			reconcileErr = err
+			return ctrl.Result{}, err
+		}
+		span.SetStatus(codes.Error, "Persona validation failed")
+		return ctrl.Result{}, nil
+	}
+	recordPersonaValidation(persona, nil)
+
 	// Reconcile the ConfigMap
 	if err := r.reconcileConfigMap(ctx, persona); err != nil {
 		log.Error(err, "Failed to reconcile ConfigMap")
@@ -123,6 +142,21 @@ func (r *LanguagePersonaReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 	return ctrl.Result{}, nil
 }
 
+// recordPersonaValidation records a validation result in the persona status. The validation time
+// only moves when the result changes, so reconciling an unchanged persona doesn't rewrite its status.
+func recordPersonaValidation(persona *langopv1alpha1.LanguagePersona, errs []string) {
+	valid := len(errs) == 0
+	if previous := persona.Status.ValidationResult; previous != nil && previous.Valid == valid && slices.Equal(previous.Errors, errs) {
+		return
+	}
+	now := metav1.Now()
+	persona.Status.ValidationResult = &langopv1alpha1.PersonaValidation{
+		Valid:          valid,
+		ValidationTime: &now,
+		Errors:         errs,
+	}
+}
+
 // reconcileConfigMap creates or updates the ConfigMap for the persona
 func (r *LanguagePersonaReconciler) reconcileConfigMap(ctx context.Context, persona *langopv1alpha1.LanguagePersona) error {
 	// Create ConfigMap data from persona spec
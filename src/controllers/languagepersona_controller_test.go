@@ -189,3 +189,78 @@ func TestLanguagePersonaController_NotFoundHandling(t *testing.T) {
 		t.Error("Expected no requeue for not found persona")
 	}
 }
+
+func TestLanguagePersonaController_ValidationGatesReady(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	negative := int32(-1)
+	persona := &langopv1alpha1.LanguagePersona{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       "invalid-persona",
+			Namespace:  "default",
+			Generation: 1,
+			Finalizers: []string{FinalizerName},
+		},
+		Spec: langopv1alpha1.LanguagePersonaSpec{
+			SystemPrompt:    "You are a helpful assistant.",
+			ToolPreferences: &langopv1alpha1.ToolPreferencesSpec{Strategy: "reckless"},
+			Constraints:     &langopv1alpha1.PersonaConstraints{MaxResponseTokens: &negative},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(persona).
+		WithStatusSubresource(persona).
+		Build()
+
+	reconciler := &LanguagePersonaReconciler{
+		Client: fakeClient,
+		Scheme: scheme,
+		Log:    logr.Discard(),
+	}
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: persona.Name, Namespace: persona.Namespace}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	updated := &langopv1alpha1.LanguagePersona{}
+	if err := fakeClient.Get(ctx, req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to fetch persona: %v", err)
+	}
+	if updated.Status.Phase != "NotReady" {
+		t.Errorf("Expected phase 'NotReady' for an invalid persona, got '%s'", updated.Status.Phase)
+	}
+	if updated.Status.ValidationResult == nil || updated.Status.ValidationResult.Valid {
+		t.Fatalf("Expected an invalid validation result, got %+v", updated.Status.ValidationResult)
+	}
+	if len(updated.Status.ValidationResult.Errors) != 2 {
+		t.Errorf("Expected 2 validation errors, got %v", updated.Status.ValidationResult.Errors)
+	}
+	cm := &corev1.ConfigMap{}
+	err := fakeClient.Get(ctx, types.NamespacedName{Name: GenerateConfigMapName(persona.Name, "persona"), Namespace: persona.Namespace}, cm)
+	if err == nil {
+		t.Error("Expected no ConfigMap for an invalid persona")
+	}
+
+	// Fixing the spec makes the persona Ready
+	updated.Spec.ToolPreferences.Strategy = langopv1alpha1.ToolStrategyBalanced
+	updated.Spec.Constraints.MaxResponseTokens = nil
+	if err := fakeClient.Update(ctx, updated); err != nil {
+		t.Fatalf("Failed to update persona: %v", err)
+	}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if err := fakeClient.Get(ctx, req.NamespacedName, updated); err != nil {
+		t.Fatalf("Failed to fetch persona: %v", err)
+	}
+	if updated.Status.Phase != "Ready" {
+		t.Errorf("Expected phase 'Ready' after fixing the spec, got '%s'", updated.Status.Phase)
+	}
+	if updated.Status.ValidationResult == nil || !updated.Status.ValidationResult.Valid {
+		t.Errorf("Expected a valid validation result, got %+v", updated.Status.ValidationResult)
+	}
+}
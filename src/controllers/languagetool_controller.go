@@ -25,6 +25,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
@@ -91,6 +92,7 @@ type MCPSchemaProperty struct {
 //+kubebuilder:rbac:groups=langop.io,resources=languagetools,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=langop.io,resources=languagetools/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=langop.io,resources=languagetools/finalizers,verbs=update
+//+kubebuilder:rbac:groups=langop.io,resources=languageagents,verbs=get;list;watch
 //+kubebuilder:rbac:groups=apps,resources=deployments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=services,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update;patch;delete
@@ -136,6 +138,19 @@ func (r *LanguageToolReconciler) Reconcile(ctx context.Context, req ctrl.Request
 	if !tool.DeletionTimestamp.IsZero() {
 		span.AddEvent("Deleting tool")
 		if controllerutil.ContainsFinalizer(tool, FinalizerName) {
+			// A shared server stays up while agents still reference it
+			if isSharedTool(tool) {
+				referencing, err := r.findReferencingAgents(ctx, tool)
+				if err != nil {
+					span.RecordError(err)
+					reconcileErr = err
+					return ctrl.Result{}, err
+				}
+				if len(referencing) > 0 {
+					return r.blockSharedToolDeletion(ctx, tool, referencing)
+				}
+			}
+
 			// Perform cleanup
 			if err := r.cleanupResources(ctx, tool); err != nil {
 				span.RecordError(err)
@@ -192,7 +207,31 @@ func (r *LanguageToolReconciler) Reconcile(ctx context.Context, req ctrl.Request
 
 	// Skip Deployment and Service for sidecar mode tools
 	// Sidecar tools are injected into agent pods directly
-	if tool.Spec.DeploymentMode != "sidecar" {
+	serveTool := tool.Spec.DeploymentMode != "sidecar"
+
+	// Shared cluster tools only run while agents reference them
+	if isSharedTool(tool) {
+		referencing, err := r.findReferencingAgents(ctx, tool)
+		if err != nil {
+			span.RecordError(err)
+			reconcileErr = err
+			return ctrl.Result{}, err
+		}
+		tool.Status.ReferencingAgents = referencing
+		if len(referencing) == 0 {
+			serveTool = false
+			if err := r.removeSharedServer(ctx, tool); err != nil {
+				log.Error(err, "Failed to remove unreferenced shared tool server")
+				span.RecordError(err)
+				reconcileErr = err
+				return ctrl.Result{}, err
+			}
+		}
+	} else {
+		tool.Status.ReferencingAgents = nil
+	}
+
+	if serveTool {
 		// Reconcile Deployment
 		if err := r.reconcileDeployment(ctx, tool); err != nil {
 			log.Error(err, "Failed to reconcile Deployment")
@@ -585,6 +624,17 @@ func (r *LanguageToolReconciler) updateToolStatus(ctx context.Context, tool *lan
 		return r.Status().Update(ctx, tool)
 	}
 
+	// Shared tools without referencing agents have no server running
+	if isSharedTool(tool) && len(tool.Status.ReferencingAgents) == 0 {
+		tool.Status.Phase = "Pending"
+		tool.Status.ReadyReplicas = 0
+		tool.Status.AvailableReplicas = 0
+		tool.Status.UpdatedReplicas = 0
+		tool.Status.UnavailableReplicas = 0
+		SetCondition(&tool.Status.Conditions, "Ready", metav1.ConditionFalse, "Unreferenced", "No agents reference this shared tool", tool.Generation)
+		return r.Status().Update(ctx, tool)
+	}
+
 	// For service mode tools, check deployment status
 	deployment := &appsv1.Deployment{}
 	err := r.Get(ctx, types.NamespacedName{Name: tool.Name, Namespace: tool.Namespace}, deployment)
@@ -677,11 +727,18 @@ func (r *LanguageToolReconciler) validateImageRegistry(tool *langopv1alpha1.Lang
 
 // SetupWithManager sets up the controller with the Manager.
 func (r *LanguageToolReconciler) SetupWithManager(mgr ctrl.Manager, concurrency int) error {
+	// Agent events find the shared tools they dropped without listing every tool
+	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &langopv1alpha1.LanguageTool{},
+		sharedToolReferencingAgentsField, sharedToolReferencingAgents); err != nil {
+		return err
+	}
+
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&langopv1alpha1.LanguageTool{}).
 		Owns(&appsv1.Deployment{}).
 		Owns(&corev1.Service{}).
 		Owns(&corev1.ConfigMap{}).
 		Owns(&networkingv1.NetworkPolicy{}).
+		Watches(&langopv1alpha1.LanguageAgent{}, handler.EnqueueRequestsFromMapFunc(r.sharedToolsForAgent)).
 		Complete(r)
 }
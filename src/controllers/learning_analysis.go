@@ -0,0 +1,230 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// LearningApprovedAnnotation lists the tasks (comma-separated) whose learned code a human has
+// approved for promotion when spec.learning.requireApproval is set
+const LearningApprovedAnnotation = "langop.io/learning-approved"
+
+// Review states of an exported learning analysis
+const (
+	learningAnalysisPendingApproval = "pending_approval"
+	learningAnalysisApproved        = "approved"
+	learningAnalysisPromoted        = "promoted"
+)
+
+// maxAnalysisSampleErrors limits the error messages kept as evidence for a task
+const maxAnalysisSampleErrors = 3
+
+// TraceEvidence summarizes the execution traces a pattern analysis was derived from
+type TraceEvidence struct {
+	TraceCount       int            `json:"traceCount"`
+	SuccessRate      float64        `json:"successRate"`
+	ToolCallPatterns map[string]int `json:"toolCallPatterns"`
+	FirstTrace       time.Time      `json:"firstTrace"`
+	LastTrace        time.Time      `json:"lastTrace"`
+	SampleErrors     []string       `json:"sampleErrors,omitempty"`
+}
+
+// LearningAnalysis is the reviewable record of why a task was, or is about to be, converted
+// to symbolic code
+type LearningAnalysis struct {
+	PatternAnalysis
+	Evidence        TraceEvidence `json:"evidence"`
+	Trigger         string        `json:"trigger"`
+	State           string        `json:"state"`
+	PromotedVersion int32         `json:"promotedVersion,omitempty"`
+	AnalyzedAt      time.Time     `json:"analyzedAt"`
+}
+
+// learningAnalysisEnabled reports whether the agent's learning analyses are exported for review
+func learningAnalysisEnabled(agent *langopv1alpha1.LanguageAgent) bool {
+	return agent.Spec.Learning != nil && (agent.Spec.Learning.ExportAnalysis || agent.Spec.Learning.RequireApproval)
+}
+
+// learningApprovalRequired reports whether learned code must be approved before promotion
+func learningApprovalRequired(agent *langopv1alpha1.LanguageAgent) bool {
+	return agent.Spec.Learning != nil && agent.Spec.Learning.RequireApproval
+}
+
+// isLearningApproved reports whether the task is listed in the approval annotation
+func isLearningApproved(agent *langopv1alpha1.LanguageAgent, taskName string) bool {
+	for _, approved := range strings.Split(agent.GetAnnotations()[LearningApprovedAnnotation], ",") {
+		if strings.TrimSpace(approved) == taskName {
+			return true
+		}
+	}
+	return false
+}
+
+// learningAnalysisConfigMapName returns the name of the ConfigMap holding the agent's analyses
+func learningAnalysisConfigMapName(agent *langopv1alpha1.LanguageAgent) string {
+	return fmt.Sprintf("%s-learning-analysis", agent.Name)
+}
+
+// buildLearningAnalysis re-runs pattern analysis for the trigger's task and records the trace
+// evidence behind it
+func (r *LearningReconciler) buildLearningAnalysis(ctx context.Context, agent *langopv1alpha1.LanguageAgent, trigger LearningEvent) (*LearningAnalysis, error) {
+	traces, err := r.getExecutionTraces(ctx, agent)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get execution traces: %w", err)
+	}
+	taskTraces := r.groupTracesByTask(traces)[trigger.TaskName]
+
+	analysis, err := r.analyzeTaskPatterns(trigger.TaskName, taskTraces)
+	if err != nil {
+		return nil, fmt.Errorf("failed to analyze task patterns: %w", err)
+	}
+
+	evidence := TraceEvidence{
+		TraceCount:       len(taskTraces),
+		ToolCallPatterns: r.analyzeToolCallPatterns(taskTraces),
+	}
+	if len(taskTraces) > 0 {
+		evidence.SuccessRate = 1 - r.calculateErrorRate(taskTraces)
+		evidence.FirstTrace = taskTraces[0].Timestamp
+		evidence.LastTrace = taskTraces[0].Timestamp
+	}
+	for _, trace := range taskTraces {
+		if trace.Timestamp.Before(evidence.FirstTrace) {
+			evidence.FirstTrace = trace.Timestamp
+		}
+		if trace.Timestamp.After(evidence.LastTrace) {
+			evidence.LastTrace = trace.Timestamp
+		}
+		if !trace.Success && trace.ErrorMessage != "" && len(evidence.SampleErrors) < maxAnalysisSampleErrors &&
+			!containsString(evidence.SampleErrors, trace.ErrorMessage) {
+			evidence.SampleErrors = append(evidence.SampleErrors, trace.ErrorMessage)
+		}
+	}
+	sort.Strings(evidence.SampleErrors)
+
+	return &LearningAnalysis{
+		PatternAnalysis: *analysis,
+		Evidence:        evidence,
+		Trigger:         trigger.EventType,
+		AnalyzedAt:      time.Now(),
+	}, nil
+}
+
+// writeLearningAnalysis stores the analysis under the task's key in the <agent>-learning-analysis
+// ConfigMap. The write is skipped when only the analysis time changed, so re-analyzing a task
+// awaiting approval on every reconcile doesn't churn the ConfigMap. The ConfigMap is an artifact
+// expiring ArtifactTTL after its last write, and kept while any analysis awaits approval; a later
+// analysis recreates it. It returns the state of the analysis previously stored for the task,
+// empty when there was none.
+func (r *LearningReconciler) writeLearningAnalysis(ctx context.Context, agent *langopv1alpha1.LanguageAgent, analysis *LearningAnalysis) (string, error) {
+	key := fmt.Sprintf("%s.json", analysis.TaskName)
+	data, err := json.MarshalIndent(analysis, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to serialize learning analysis: %w", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	err = r.Get(ctx, types.NamespacedName{Name: learningAnalysisConfigMapName(agent), Namespace: agent.Namespace}, configMap)
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      learningAnalysisConfigMapName(agent),
+				Namespace: agent.Namespace,
+				Labels: map[string]string{
+					"langop.io/agent":     agent.Name,
+					"langop.io/component": "learning-analysis",
+				},
+			},
+			Data: map[string]string{key: string(data)},
+		}
+		setArtifactTTL(configMap, r.ArtifactTTL, time.Now())
+		setArtifactPendingApproval(configMap, analysis.State == learningAnalysisPendingApproval)
+		if err := controllerutil.SetControllerReference(agent, configMap, r.Scheme); err != nil {
+			return "", fmt.Errorf("failed to set controller reference: %w", err)
+		}
+		if err := r.Create(ctx, configMap); err != nil {
+			return "", fmt.Errorf("failed to create learning analysis ConfigMap: %w", err)
+		}
+		return "", nil
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get learning analysis ConfigMap: %w", err)
+	}
+
+	previousState := learningAnalysisState(configMap.Data[key])
+	if unchangedLearningAnalysis(configMap.Data[key], analysis) {
+		return previousState, nil
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[key] = string(data)
+	setArtifactTTL(configMap, r.ArtifactTTL, time.Now())
+	setArtifactPendingApproval(configMap, learningAnalysisAwaitsApproval(configMap.Data))
+	if err := r.Update(ctx, configMap); err != nil {
+		return "", fmt.Errorf("failed to update learning analysis ConfigMap: %w", err)
+	}
+	return previousState, nil
+}
+
+// learningAnalysisState returns the state of a stored analysis, empty when it can't be read
+func learningAnalysisState(stored string) string {
+	var analysis LearningAnalysis
+	if stored == "" || json.Unmarshal([]byte(stored), &analysis) != nil {
+		return ""
+	}
+	return analysis.State
+}
+
+// learningAnalysisAwaitsApproval reports whether any stored analysis is pending approval
+func learningAnalysisAwaitsApproval(data map[string]string) bool {
+	for _, stored := range data {
+		if learningAnalysisState(stored) == learningAnalysisPendingApproval {
+			return true
+		}
+	}
+	return false
+}
+
+// unchangedLearningAnalysis reports whether the stored analysis differs from analysis only in
+// its analysis time
+func unchangedLearningAnalysis(stored string, analysis *LearningAnalysis) bool {
+	if stored == "" {
+		return false
+	}
+	var previous LearningAnalysis
+	if err := json.Unmarshal([]byte(stored), &previous); err != nil {
+		return false
+	}
+	previous.AnalyzedAt = analysis.AnalyzedAt
+	previousData, err := json.Marshal(previous)
+	if err != nil {
+		return false
+	}
+	currentData, err := json.Marshal(analysis)
+	if err != nil {
+		return false
+	}
+	return string(previousData) == string(currentData)
+}
+
+// containsString reports whether values contains value
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
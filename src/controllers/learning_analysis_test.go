@@ -0,0 +1,165 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+func newLearningAnalysisReconciler(t *testing.T, agent *langopv1alpha1.LanguageAgent) (*LearningReconciler, client.Client, *record.FakeRecorder) {
+	scheme := testutil.SetupTestScheme(t)
+
+	// Few enough traces that none are summarized away before analysis
+	var spans []telemetry.Span
+	for i := 0; i < 5; i++ {
+		start := time.Now().Add(-time.Duration(5-i) * time.Minute)
+		spans = append(spans, telemetry.Span{
+			SpanID:        fmt.Sprintf("span-%d", i),
+			TraceID:       fmt.Sprintf("trace-%d", i),
+			OperationName: "execute_task",
+			TaskName:      "fetch_user",
+			StartTime:     start,
+			EndTime:       start.Add(time.Second),
+			Duration:      time.Second,
+			Status:        true,
+			Attributes: map[string]string{
+				"task.inputs":  `{"user_id": 123}`,
+				"task.outputs": `{"user": {"name": "Alice"}}`,
+			},
+		})
+	}
+
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LearningReconciler{
+		Client:               fakeClient,
+		Scheme:               scheme,
+		Log:                  logr.Discard(),
+		Recorder:             recorder,
+		LearningInterval:     time.Minute,
+		PatternConfidenceMin: 0.7,
+		Synthesizer:          &MockSynthesizer{GeneratedCode: "mock learned code"},
+		ConfigMapManager:     &synthesis.ConfigMapManager{Client: fakeClient, Scheme: scheme, Log: logr.Discard()},
+		TelemetryAdapter:     &telemetry.MockAdapter{AvailableReturn: true, SpanResults: spans},
+	}
+	return reconciler, fakeClient, recorder
+}
+
+func newLearningAnalysisAgent(learning *langopv1alpha1.LearningSpec) *langopv1alpha1.LanguageAgent {
+	return &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default", UID: "test-uid"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Instructions: "test instructions",
+			Learning:     learning,
+		},
+	}
+}
+
+func newLearningAnalysisTrigger() LearningEvent {
+	return LearningEvent{
+		AgentName:  "test-agent",
+		Namespace:  "default",
+		TaskName:   "fetch_user",
+		EventType:  "traces_accumulated",
+		TraceCount: 5,
+		Confidence: 0.9,
+		Timestamp:  time.Now(),
+	}
+}
+
+func getLearningAnalysis(t *testing.T, c client.Client, taskName string) *LearningAnalysis {
+	t.Helper()
+	var configMap corev1.ConfigMap
+	require.NoError(t, c.Get(context.Background(), types.NamespacedName{Name: "test-agent-learning-analysis", Namespace: "default"}, &configMap))
+	data, ok := configMap.Data[taskName+".json"]
+	require.True(t, ok, "Expected an analysis entry for task %s", taskName)
+
+	var analysis LearningAnalysis
+	require.NoError(t, json.Unmarshal([]byte(data), &analysis))
+	return &analysis
+}
+
+func TestLearningReconciler_ExportsLearningAnalysis(t *testing.T) {
+	agent := newLearningAnalysisAgent(&langopv1alpha1.LearningSpec{ExportAnalysis: true})
+	reconciler, fakeClient, _ := newLearningAnalysisReconciler(t, agent)
+
+	learningStatus := map[string]*TaskLearningStatus{}
+	require.NoError(t, reconciler.processLearningTrigger(context.Background(), agent, newLearningAnalysisTrigger(), learningStatus))
+
+	analysis := getLearningAnalysis(t, fakeClient, "fetch_user")
+	assert.Equal(t, learningAnalysisPromoted, analysis.State)
+	assert.Equal(t, int32(2), analysis.PromotedVersion)
+	assert.Equal(t, "traces_accumulated", analysis.Trigger)
+	assert.NotEmpty(t, analysis.Explanation)
+	assert.NotEmpty(t, analysis.CommonPattern)
+	assert.Equal(t, 5, analysis.Evidence.TraceCount)
+	assert.Equal(t, 1.0, analysis.Evidence.SuccessRate)
+	assert.True(t, learningStatus["fetch_user"].IsSymbolic)
+}
+
+func TestLearningReconciler_NoAnalysisExportByDefault(t *testing.T) {
+	agent := newLearningAnalysisAgent(nil)
+	reconciler, fakeClient, _ := newLearningAnalysisReconciler(t, agent)
+
+	require.NoError(t, reconciler.processLearningTrigger(context.Background(), agent, newLearningAnalysisTrigger(), map[string]*TaskLearningStatus{}))
+
+	err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "test-agent-learning-analysis", Namespace: "default"}, &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err), "Expected no analysis ConfigMap, got %v", err)
+}
+
+func TestLearningReconciler_ApprovalGatesPromotion(t *testing.T) {
+	ctx := context.Background()
+	agent := newLearningAnalysisAgent(&langopv1alpha1.LearningSpec{RequireApproval: true})
+	reconciler, fakeClient, recorder := newLearningAnalysisReconciler(t, agent)
+	learningStatus := map[string]*TaskLearningStatus{}
+
+	require.NoError(t, reconciler.processLearningTrigger(ctx, agent, newLearningAnalysisTrigger(), learningStatus))
+
+	// The analysis is written for review but no learned version is created
+	assert.Equal(t, learningAnalysisPendingApproval, getLearningAnalysis(t, fakeClient, "fetch_user").State)
+	err := fakeClient.Get(ctx, types.NamespacedName{Name: "test-agent-v2", Namespace: "default"}, &corev1.ConfigMap{})
+	assert.True(t, errors.IsNotFound(err), "Expected promotion to wait for approval, got %v", err)
+	assert.False(t, learningStatus["fetch_user"].IsSymbolic)
+	assert.True(t, hasEvent(drainEvents(recorder), "LearningApprovalPending"))
+	analysisConfigMap := &corev1.ConfigMap{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-agent-learning-analysis", Namespace: "default"}, analysisConfigMap))
+	assert.Equal(t, "true", analysisConfigMap.Annotations[ArtifactPendingApprovalAnnotation], "Expected the analysis to be kept while it awaits approval")
+
+	// Re-analyzing a task that is still waiting doesn't announce it again
+	require.NoError(t, reconciler.processLearningTrigger(ctx, agent, newLearningAnalysisTrigger(), learningStatus))
+	assert.False(t, hasEvent(drainEvents(recorder), "LearningApprovalPending"))
+
+	// Approving a different task doesn't promote this one
+	agent.Annotations = map[string]string{LearningApprovedAnnotation: "other_task"}
+	require.NoError(t, reconciler.processLearningTrigger(ctx, agent, newLearningAnalysisTrigger(), learningStatus))
+	assert.False(t, learningStatus["fetch_user"].IsSymbolic)
+
+	agent.Annotations = map[string]string{LearningApprovedAnnotation: "other_task, fetch_user"}
+	require.NoError(t, reconciler.processLearningTrigger(ctx, agent, newLearningAnalysisTrigger(), learningStatus))
+
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-agent-v2", Namespace: "default"}, &corev1.ConfigMap{}))
+	assert.True(t, learningStatus["fetch_user"].IsSymbolic)
+	analysis := getLearningAnalysis(t, fakeClient, "fetch_user")
+	assert.Equal(t, learningAnalysisPromoted, analysis.State)
+	assert.Equal(t, int32(2), analysis.PromotedVersion)
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: "test-agent-learning-analysis", Namespace: "default"}, analysisConfigMap))
+	assert.NotContains(t, analysisConfigMap.Annotations, ArtifactPendingApprovalAnnotation)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -27,6 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/audit"
 	"github.com/language-operator/language-operator/pkg/learning"
 	"github.com/language-operator/language-operator/pkg/reconciler"
 	"github.com/language-operator/language-operator/pkg/synthesis"
@@ -48,6 +50,9 @@ type LearningReconciler struct {
 	LearningEnabled       bool
 	LearningThreshold     int32         // Number of execution traces before triggering learning
 	LearningInterval      time.Duration // Minimum interval between learning attempts
+	LearningTraceWindow   time.Duration // How far back execution traces are analyzed (0 = executionTraceWindowIntervals learning intervals)
+	MinTraceAge           time.Duration // Executions that completed more recently than this are not analyzed
+	HealthGateWindow      time.Duration // How long a promoted version's error rate is observed before it is kept (0 disables the gate)
 	MaxVersions           int32         // Maximum number of ConfigMap versions to keep
 	PatternConfidenceMin  float64       // Minimum confidence threshold for pattern detection
 
@@ -55,6 +60,26 @@ type LearningReconciler struct {
 	ErrorFailureThreshold       int32         // Number of consecutive failures before triggering re-synthesis (default: 3)
 	ErrorCooldownPeriod         time.Duration // Cooldown period between error-triggered re-synthesis attempts (default: 5m)
 	MaxErrorResynthesisAttempts int32         // Maximum number of error re-synthesis attempts per task (default: 3)
+
+	// MaxConcurrentLearningRollouts bounds learning-driven Deployment rollouts across all agents (0 = unlimited)
+	MaxConcurrentLearningRollouts int32
+
+	// Audit records learning conversions and rollbacks to the audit stream (nil disables auditing)
+	Audit *audit.Emitter
+
+	// Reloader delivers learned code to agents with spec.warmReload (nil uses HTTPAgentReloader)
+	Reloader AgentReloader
+
+	// ArtifactTTL is how long learning analysis ConfigMaps are kept after their last write before the ArtifactCleaner
+	// deletes them (0 keeps them for the agent's lifetime)
+	ArtifactTTL time.Duration
+
+	rolloutLimiter     *rolloutLimiter
+	rolloutLimiterOnce sync.Once
+
+	// pinSkips holds the pinned code version each skipped agent was last reported at, so
+	// LearningSkippedDueToPin is recorded once per pin rather than on every reconcile
+	pinSkips sync.Map
 }
 
 // LearningEvent represents a learning trigger event
@@ -98,6 +123,9 @@ type TaskLearningStatus struct {
 	LastExecutionTime    time.Time `json:"lastExecutionTime"`
 	SuccessRate          float64   `json:"successRate"`
 	LearningStatus       string    `json:"learningStatus"` // "learning", "ready_for_symbolic", "symbolic"
+
+	// HealthGate is set while a promoted learned version is checked against the error rate before promotion
+	HealthGate *LearningHealthGate `json:"healthGate,omitempty"`
 }
 
 // TaskTrace represents an execution trace for pattern detection
@@ -153,6 +181,9 @@ type AgentExecutionSummary struct {
 	Updated           time.Time                      `json:"updated"`
 }
 
+// auditControllerLearning identifies the learning controller in audit records
+const auditControllerLearning = "learning"
+
 // learningTracer is used by helper methods for detailed tracing
 var learningTracer = otel.Tracer("language-operator/learning")
 
@@ -179,7 +210,10 @@ func (r *LearningReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, err
 	}
 	if result == nil {
-		// Resource was deleted
+		// Resource was deleted; remove any versions that weren't garbage collected with it
+		if _, err := r.sweepOrphanedConfigMaps(ctx, req.Namespace); err != nil {
+			r.Log.Error(err, "Failed to sweep orphaned versioned ConfigMaps", "namespace", req.Namespace)
+		}
 		return ctrl.Result{}, nil
 	}
 
@@ -206,6 +240,13 @@ func (r *LearningReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, nil
 	}
 
+	pinnedVersion := agent.Annotations[langopv1alpha1.PinnedCodeVersionAnnotation]
+	if pinnedVersion != "" {
+		r.recordPinSkip(agent, pinnedVersion)
+	} else {
+		r.pinSkips.Delete(req.NamespacedName)
+	}
+
 	// Get learning status from ConfigMap
 	learningStatus, err := r.getLearningStatus(ctx, agent)
 	if err != nil {
@@ -214,6 +255,18 @@ func (r *LearningReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 		return ctrl.Result{}, reconcileErr
 	}
 
+	// Pinned agents ignore new versions, so don't analyze traces or create versions for them.
+	// Versions promoted before the pin still go through their health gates.
+	if pinnedVersion != "" {
+		log.V(1).Info("Agent is pinned to a code version, skipping learning", "pinnedVersion", pinnedVersion)
+		pinnedResult, err := r.checkPinnedHealthGates(ctx, agent, learningStatus)
+		reconcileErr = err
+		return pinnedResult, err
+	}
+
+	// Roll back promotions that increased errors before looking for new ones
+	healthGateDue := r.checkHealthGates(ctx, agent, learningStatus)
+
 	// Check for learning triggers (pattern-based and error-based)
 	learningTriggers, err := r.checkLearningTriggers(ctx, agent, learningStatus)
 	if err != nil {
@@ -292,6 +345,9 @@ func (r *LearningReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 	if requeue {
 		requeueAfter = time.Minute // Faster requeue after learning events
 	}
+	if healthGateDue > 0 && healthGateDue < requeueAfter {
+		requeueAfter = healthGateDue
+	}
 
 	log.V(1).Info("Learning reconciliation completed",
 		"triggers", len(learningTriggers),
@@ -304,13 +360,44 @@ func (r *LearningReconciler) Reconcile(ctx context.Context, req ctrl.Request) (c
 func (r *LearningReconciler) isLearningEnabled(agent *langopv1alpha1.LanguageAgent) bool {
 	// Check agent annotations for learning configuration
 	if annotations := agent.GetAnnotations(); annotations != nil {
-		if disabled, exists := annotations["langop.io/learning-disabled"]; exists && disabled == "true" {
+		if disabled, exists := annotations[langopv1alpha1.LearningDisabledAnnotation]; exists && disabled == "true" {
 			return false
 		}
 	}
 	return true
 }
 
+// checkPinnedHealthGates runs the pending health gates of a pinned agent, which learning otherwise
+// skips, and requeues the agent for the next gate due
+func (r *LearningReconciler) checkPinnedHealthGates(ctx context.Context, agent *langopv1alpha1.LanguageAgent, learningStatus map[string]*TaskLearningStatus) (ctrl.Result, error) {
+	pending := false
+	for _, taskStatus := range learningStatus {
+		pending = pending || taskStatus.HealthGate != nil
+	}
+	if !pending {
+		return ctrl.Result{}, nil
+	}
+
+	healthGateDue := r.checkHealthGates(ctx, agent, learningStatus)
+	if err := r.updateLearningStatus(ctx, agent, learningStatus); err != nil {
+		return ctrl.Result{}, fmt.Errorf("failed to update learning status: %w", err)
+	}
+	return ctrl.Result{RequeueAfter: healthGateDue}, nil
+}
+
+// recordPinSkip records a LearningSkippedDueToPin event the first time learning skips the agent
+// at the given pinned version
+func (r *LearningReconciler) recordPinSkip(agent *langopv1alpha1.LanguageAgent, version string) {
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if previous, loaded := r.pinSkips.Swap(key, version); loaded && previous == version {
+		return
+	}
+	if r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeNormal, "LearningSkippedDueToPin",
+			"Learning skipped: agent is pinned to code version %s", version)
+	}
+}
+
 // getLearningStatus retrieves the current learning status from the agent's ConfigMap
 func (r *LearningReconciler) getLearningStatus(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (map[string]*TaskLearningStatus, error) {
 	ctx, span := learningTracer.Start(ctx, "learning.get_status")
@@ -344,6 +431,9 @@ func (r *LearningReconciler) getLearningStatus(ctx context.Context, agent *lango
 				r.Log.Error(err, "Failed to parse learning status for task", "task", taskNameClean)
 				continue
 			}
+			if status.TaskName == "" {
+				status.TaskName = taskNameClean
+			}
 			learningStatus[taskNameClean] = status
 		}
 	}
@@ -352,23 +442,32 @@ func (r *LearningReconciler) getLearningStatus(ctx context.Context, agent *lango
 	return learningStatus, nil
 }
 
-// parseTaskLearningStatus parses learning status from JSON string
+// parseTaskLearningStatus parses learning status from JSON string. Entries still in the legacy
+// "key:value,..." format are parsed for one cycle and rewritten as JSON on the next save.
 func (r *LearningReconciler) parseTaskLearningStatus(data string) (*TaskLearningStatus, error) {
-	// Try JSON parsing first (new format)
+	if isLegacyTaskLearningStatus(data) {
+		r.Log.V(1).Info("Migrating legacy task learning status to JSON", "data", data)
+		return r.parseTaskLearningStatusLegacy(data)
+	}
+
 	var status TaskLearningStatus
-	if err := json.Unmarshal([]byte(data), &status); err == nil {
-		// Validate that version is non-negative
-		if status.CurrentVersion < 0 {
-			r.Log.Error(fmt.Errorf("invalid version in task status: %d", status.CurrentVersion),
-				"Invalid version number in task status", "task", status.TaskName, "version", status.CurrentVersion)
-			status.CurrentVersion = 0 // Reset to safe default
-		}
-		return &status, nil
+	if err := json.Unmarshal([]byte(data), &status); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal task learning status: %w", err)
+	}
+
+	// Validate that version is non-negative
+	if status.CurrentVersion < 0 {
+		r.Log.Error(fmt.Errorf("invalid version in task status: %d", status.CurrentVersion),
+			"Invalid version number in task status", "task", status.TaskName, "version", status.CurrentVersion)
+		status.CurrentVersion = 0 // Reset to safe default
 	}
+	return &status, nil
+}
 
-	// Fall back to legacy string parsing for backward compatibility
-	r.Log.V(1).Info("JSON parsing failed, attempting legacy string parsing", "data", data)
-	return r.parseTaskLearningStatusLegacy(data)
+// isLegacyTaskLearningStatus reports whether data uses the legacy comma-separated format
+func isLegacyTaskLearningStatus(data string) bool {
+	trimmed := strings.TrimSpace(data)
+	return !strings.HasPrefix(trimmed, "{") && strings.Contains(trimmed, ":")
 }
 
 // parseTaskLearningStatusLegacy parses learning status from legacy string format
@@ -385,6 +484,7 @@ func (r *LearningReconciler) parseTaskLearningStatusLegacy(data string) (*TaskLe
 	}
 
 	// Simple parsing for key:value pairs
+	recognized := false
 	parts := strings.Split(data, ",")
 	for _, part := range parts {
 		keyValue := strings.Split(part, ":")
@@ -429,9 +529,15 @@ func (r *LearningReconciler) parseTaskLearningStatusLegacy(data string) (*TaskLe
 			if v, err := fmt.Sscanf(value, "%d", &status.ErrorResynthesisAttempts); err == nil && v == 1 {
 				// parsed successfully
 			}
+		default:
+			continue
 		}
+		recognized = true
 	}
 
+	if !recognized {
+		return nil, fmt.Errorf("unrecognized task learning status format: %q", data)
+	}
 	return status, nil
 }
 
@@ -1002,6 +1108,13 @@ func (r *LearningReconciler) processLearningTrigger(ctx context.Context, agent *
 		learningStatus[trigger.TaskName] = taskStatus
 	}
 
+	// Don't promote another version until the last one passed its health gate
+	if taskStatus.HealthGate != nil {
+		log.V(1).Info("Learned version health gate pending, skipping",
+			"promoted_at", taskStatus.HealthGate.PromotedAt)
+		return nil
+	}
+
 	// Check learning cooldown
 	if time.Since(taskStatus.LastLearningAttempt) < r.LearningInterval {
 		log.V(1).Info("Learning cooldown active, skipping",
@@ -1023,6 +1136,39 @@ func (r *LearningReconciler) processLearningTrigger(ctx context.Context, agent *
 		return nil
 	}
 
+	// Export the analysis for review and hold promotion until a human approves the task
+	var analysis *LearningAnalysis
+	if learningAnalysisEnabled(agent) {
+		var err error
+		analysis, err = r.buildLearningAnalysis(ctx, agent, trigger)
+		if err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("failed to build learning analysis: %w", err)
+		}
+		approved := !learningApprovalRequired(agent) || isLearningApproved(agent, trigger.TaskName)
+		analysis.State = learningAnalysisApproved
+		if !approved {
+			analysis.State = learningAnalysisPendingApproval
+		}
+		previousState, err := r.writeLearningAnalysis(ctx, agent, analysis)
+		if err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("failed to export learning analysis: %w", err)
+		}
+		if !approved {
+			// Announce the task once when it starts waiting, not on every re-analysis
+			if previousState != learningAnalysisPendingApproval {
+				log.Info("Learned code awaiting approval", "annotation", LearningApprovedAnnotation)
+				if r.Recorder != nil {
+					r.Recorder.Event(agent, corev1.EventTypeNormal, "LearningApprovalPending",
+						fmt.Sprintf("Task %s is ready for symbolic conversion; review %s and add it to the %s annotation to promote it",
+							trigger.TaskName, learningAnalysisConfigMapName(agent), LearningApprovedAnnotation))
+				}
+			}
+			return nil
+		}
+	}
+
 	// Record learning attempt
 	taskStatus.LastLearningAttempt = time.Now()
 	taskStatus.LearningAttempts++
@@ -1108,10 +1254,14 @@ func (r *LearningReconciler) processLearningTrigger(ctx context.Context, agent *
 		}
 	}
 
-	// Update deployment
-	if err := r.updateDeployment(ctx, agent, trigger.TaskName, newVersion); err != nil {
-		span.RecordError(err)
-		return fmt.Errorf("failed to update deployment: %w", err)
+	// Agents with warm reload load the learned code in place; otherwise roll the deployment
+	if !r.warmReloadLearnedCode(ctx, agent, learnedCode) {
+		previousConfigMap := r.currentDeploymentConfigMap(ctx, agent)
+		if err := r.updateDeployment(ctx, agent, trigger.TaskName, newVersion); err != nil {
+			span.RecordError(err)
+			return fmt.Errorf("failed to update deployment: %w", err)
+		}
+		r.startHealthGate(agent, taskStatus, previousConfigMap)
 	}
 
 	// Update task status
@@ -1119,6 +1269,14 @@ func (r *LearningReconciler) processLearningTrigger(ctx context.Context, agent *
 	taskStatus.IsSymbolic = true
 	taskStatus.PatternConfidence = trigger.Confidence
 
+	if analysis != nil {
+		analysis.State = learningAnalysisPromoted
+		analysis.PromotedVersion = newVersion
+		if _, err := r.writeLearningAnalysis(ctx, agent, analysis); err != nil {
+			r.Log.Error(err, "Failed to record promotion in learning analysis", "task", trigger.TaskName)
+		}
+	}
+
 	// Calculate cost savings from the conversion
 	costSavings := 0.0
 	if r.MetricsCollector != nil {
@@ -1141,7 +1299,7 @@ func (r *LearningReconciler) processLearningTrigger(ctx context.Context, agent *
 	}
 
 	// Record learning event (legacy event recording)
-	r.recordLearningEvent(agent, trigger, newVersion)
+	r.recordLearningEvent(ctx, agent, trigger, newVersion)
 
 	span.SetAttributes(
 		attribute.Int("learning.new_version", int(newVersion)),
@@ -1433,6 +1591,14 @@ func (r *LearningReconciler) updateDeployment(ctx context.Context, agent *langop
 		return r.updateAlternativeWorkload(ctx, agent, taskName, version)
 	}
 
+	// Wait for a rollout slot so simultaneous learning triggers don't roll out every Deployment at once
+	limiter := r.getRolloutLimiter()
+	if err := limiter.Acquire(ctx); err != nil {
+		span.RecordError(err)
+		return fmt.Errorf("failed waiting for learning rollout slot: %w", err)
+	}
+	defer limiter.Release()
+
 	// Store original ConfigMap reference for rollback
 	originalConfigMap := r.extractConfigMapReference(deployment)
 
@@ -1458,6 +1624,13 @@ func (r *LearningReconciler) updateDeployment(ctx context.Context, agent *langop
 
 		r.Recorder.Event(agent, corev1.EventTypeWarning, "LearningRollback",
 			fmt.Sprintf("Rolled back deployment after failed learning update for task %s", taskName))
+		r.Audit.Emit(ctx, auditControllerLearning, audit.ActionLearningRollback, agent,
+			fmt.Sprintf("Rolled back deployment after failed learning update: %v", err),
+			map[string]string{
+				"task":              taskName,
+				"failedConfigMap":   newConfigMapName,
+				"restoredConfigMap": originalConfigMap,
+			})
 
 		return fmt.Errorf("deployment rollout failed, rolled back: %w", err)
 	}
@@ -1487,6 +1660,45 @@ func (r *LearningReconciler) updateDeployment(ctx context.Context, agent *langop
 	return nil
 }
 
+// warmReloadLearnedCode writes learned code to the agent's code ConfigMap and reloads it in the
+// running pods. It returns false when the agent doesn't use warm reload or the reload failed,
+// in which case the deployment is rolled to the new ConfigMap version instead.
+func (r *LearningReconciler) warmReloadLearnedCode(ctx context.Context, agent *langopv1alpha1.LanguageAgent, code string) bool {
+	if !agent.Spec.WarmReload {
+		return false
+	}
+
+	codeConfigMap := &corev1.ConfigMap{}
+	if err := r.Get(ctx, types.NamespacedName{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace}, codeConfigMap); err != nil {
+		r.Log.Error(err, "Failed to get code ConfigMap for warm reload", "agent", agent.Name)
+		return false
+	}
+
+	// Keep new pods on the learned code and the code hash current so it isn't reported as drift
+	if codeConfigMap.Data == nil {
+		codeConfigMap.Data = make(map[string]string)
+	}
+	codeConfigMap.Data["agent.rb"] = code
+	if codeConfigMap.Annotations == nil {
+		codeConfigMap.Annotations = make(map[string]string)
+	}
+	codeConfigMap.Annotations[codeHashAnnotation] = hashString(code)
+	if err := r.Update(ctx, codeConfigMap); err != nil {
+		r.Log.Error(err, "Failed to update code ConfigMap for warm reload", "agent", agent.Name)
+		return false
+	}
+
+	return warmReloadAgent(ctx, r.Client, r.Reloader, r.Recorder, agent, code)
+}
+
+// getRolloutLimiter returns the limiter shared by all learning-driven rollouts
+func (r *LearningReconciler) getRolloutLimiter() *rolloutLimiter {
+	r.rolloutLimiterOnce.Do(func() {
+		r.rolloutLimiter = newRolloutLimiter(r.MaxConcurrentLearningRollouts)
+	})
+	return r.rolloutLimiter
+}
+
 // findAgentDeployment finds the deployment associated with the agent
 func (r *LearningReconciler) findAgentDeployment(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (*appsv1.Deployment, error) {
 	ctx, span := learningTracer.Start(ctx, "learning.find_deployment")
@@ -2298,11 +2510,18 @@ func (r *LearningReconciler) serializeTaskLearningStatus(status *TaskLearningSta
 }
 
 // recordLearningEvent records a learning event for auditing and monitoring
-func (r *LearningReconciler) recordLearningEvent(agent *langopv1alpha1.LanguageAgent, trigger LearningEvent, newVersion int32) {
+func (r *LearningReconciler) recordLearningEvent(ctx context.Context, agent *langopv1alpha1.LanguageAgent, trigger LearningEvent, newVersion int32) {
 	message := fmt.Sprintf("Learned optimization for task %s (v%d) with confidence %.2f from %s",
 		trigger.TaskName, newVersion, trigger.Confidence, trigger.EventType)
 
 	r.Recorder.Event(agent, corev1.EventTypeNormal, "LearningSucceeded", message)
+	r.Audit.Emit(ctx, auditControllerLearning, audit.ActionLearningConverted, agent, message,
+		map[string]string{
+			"task":       trigger.TaskName,
+			"version":    fmt.Sprintf("%d", newVersion),
+			"trigger":    trigger.EventType,
+			"confidence": fmt.Sprintf("%.2f", trigger.Confidence),
+		})
 
 	r.Log.Info("Recorded learning event",
 		"agent", agent.Name,
@@ -2375,17 +2594,17 @@ func (r *LearningReconciler) getExecutionTraces(ctx context.Context, agent *lang
 		return []TaskTrace{}, nil
 	}
 
-	// Query spans from the last 24 hours for this agent
+	// Query this agent's spans over the trace window, leaving out executions that may still be running
+	now := time.Now()
 	timeRange := telemetry.TimeRange{
-		Start: time.Now().Add(-24 * time.Hour),
-		End:   time.Now(),
+		Start: now.Add(-r.learningTraceWindow(agent)),
+		End:   now.Add(-r.minTraceAge(agent)),
 	}
 
 	filter := telemetry.SpanFilter{
 		TimeRange: timeRange,
-		// Use semantic attributes like the original gem implementation
 		Attributes: map[string]string{
-			"service.name": fmt.Sprintf("language-operator-agent-%s", agent.Name),
+			"agent.name": agent.Name,
 		},
 		Limit: 1000, // Reasonable limit for pattern analysis
 	}
@@ -2405,7 +2624,7 @@ func (r *LearningReconciler) getExecutionTraces(ctx context.Context, agent *lang
 	}
 
 	// Convert telemetry spans to TaskTrace format
-	traces := r.convertSpansToTaskTraces(spans)
+	traces := filterTracesByTime(r.convertSpansToTaskTraces(spans), timeRange)
 
 	// Summarize traces to reduce data size for ConfigMap storage
 	summarizedTraces := r.summarizeTraces(traces)
@@ -2426,25 +2645,109 @@ func (r *LearningReconciler) getExecutionTraces(ctx context.Context, agent *lang
 	return summarizedTraces, nil
 }
 
-// convertSpansToTaskTraces converts telemetry spans to TaskTrace format
+// executionTraceWindowIntervals is the number of learning intervals of execution history
+// queried for pattern analysis (24h at the default 5m interval)
+const executionTraceWindowIntervals = 288
+
+// learningTraceWindow returns how far back execution traces are analyzed for an agent
+func (r *LearningReconciler) learningTraceWindow(agent *langopv1alpha1.LanguageAgent) time.Duration {
+	if agent.Spec.Learning != nil && agent.Spec.Learning.TraceWindow != "" {
+		if window, err := time.ParseDuration(agent.Spec.Learning.TraceWindow); err == nil && window > 0 {
+			return window
+		}
+	}
+	if r.LearningTraceWindow > 0 {
+		return r.LearningTraceWindow
+	}
+	if r.LearningInterval <= 0 {
+		return 24 * time.Hour
+	}
+	return r.LearningInterval * executionTraceWindowIntervals
+}
+
+// minTraceAge returns how long ago an execution must have completed to be analyzed for an agent
+func (r *LearningReconciler) minTraceAge(agent *langopv1alpha1.LanguageAgent) time.Duration {
+	if agent.Spec.Learning != nil && agent.Spec.Learning.MinTraceAge != "" {
+		if age, err := time.ParseDuration(agent.Spec.Learning.MinTraceAge); err == nil && age >= 0 {
+			return age
+		}
+	}
+	return r.MinTraceAge
+}
+
+// filterTracesByTime keeps the traces that started and completed within the time range.
+// Adapters apply the range to individual spans, so a task span can match while its execution
+// is still in flight or started before the window.
+func filterTracesByTime(traces []TaskTrace, timeRange telemetry.TimeRange) []TaskTrace {
+	filtered := traces[:0]
+	for _, trace := range traces {
+		if trace.Timestamp.Before(timeRange.Start) || trace.Timestamp.Add(trace.Duration).After(timeRange.End) {
+			continue
+		}
+		filtered = append(filtered, trace)
+	}
+	return filtered
+}
+
+// spanTaskName returns the task a span executed, preferring the task.name attribute
+func spanTaskName(span telemetry.Span) string {
+	if taskName := span.Attributes["task.name"]; taskName != "" {
+		return taskName
+	}
+	return span.TaskName
+}
+
+// isToolSpan reports whether a span records a tool invocation
+func isToolSpan(span telemetry.Span) bool {
+	return span.OperationName == "tool_call" || span.Attributes["gen_ai.operation.name"] == "execute_tool"
+}
+
+// isTaskSpan reports whether a span records a task execution
+func isTaskSpan(span telemetry.Span) bool {
+	if spanTaskName(span) == "" || isToolSpan(span) {
+		return false
+	}
+	return span.OperationName == "execute_task" || span.Attributes["task.name"] != ""
+}
+
+// convertSpansToTaskTraces converts telemetry spans to TaskTrace format, one trace per task
+// execution span with its tool calls reconstructed from descendant tool spans
 func (r *LearningReconciler) convertSpansToTaskTraces(spans []telemetry.Span) []TaskTrace {
 	var traces []TaskTrace
 
+	children := make(map[string][]telemetry.Span)
+	for _, span := range spans {
+		if span.ParentSpanID != "" {
+			children[span.ParentSpanID] = append(children[span.ParentSpanID], span)
+		}
+	}
+	for _, siblings := range children {
+		sort.SliceStable(siblings, func(i, j int) bool {
+			return siblings[i].StartTime.Before(siblings[j].StartTime)
+		})
+	}
+
 	for _, span := range spans {
 		// Only process spans that represent task executions
-		if span.OperationName != "execute_task" || span.TaskName == "" {
+		if !isTaskSpan(span) {
 			continue
 		}
 
 		// Extract task inputs/outputs from span attributes
 		inputs := r.parseJSONAttribute(span.Attributes["task.inputs"])
+		for name, value := range r.prefixedAttributes(span.Attributes, "task.input.") {
+			inputs[name] = value
+		}
 		outputs := r.parseJSONAttribute(span.Attributes["task.outputs"])
+		for name, value := range r.prefixedAttributes(span.Attributes, "task.output.") {
+			outputs[name] = value
+		}
 
-		// Extract tool calls from child spans (simplified)
 		toolCalls := r.extractToolCallsFromSpan(span)
+		toolCalls = append(toolCalls, r.extractToolCallsFromChildren(span.SpanID, children)...)
 
 		trace := TaskTrace{
-			TaskName:     span.TaskName,
+			TaskName:     spanTaskName(span),
 			Timestamp:    span.StartTime,
 			Inputs:       inputs,
 			Outputs:      outputs,
@@ -2460,6 +2763,61 @@ func (r *LearningReconciler) convertSpansToTaskTraces(spans []telemetry.Span) []
 	return traces
 }
 
+// prefixedAttributes collects span attributes under a prefix, keyed by the rest of the
+// attribute name. JSON values are decoded; anything else is kept as a string.
+func (r *LearningReconciler) prefixedAttributes(attributes map[string]string, prefix string) map[string]interface{} {
+	result := map[string]interface{}{}
+	for key, raw := range attributes {
+		name, found := strings.CutPrefix(key, prefix)
+		if !found || name == "" {
+			continue
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(raw), &value); err != nil {
+			value = raw
+		}
+		result[name] = value
+	}
+	return result
+}
+
+// extractToolCallsFromChildren reconstructs the tool calls made beneath a span, in start
+// order. Nested task executions are left to their own traces.
+func (r *LearningReconciler) extractToolCallsFromChildren(spanID string, children map[string][]telemetry.Span) []ToolCall {
+	if spanID == "" {
+		return nil
+	}
+
+	var toolCalls []ToolCall
+	for _, child := range children[spanID] {
+		if child.SpanID == spanID || isTaskSpan(child) {
+			continue
+		}
+		if isToolSpan(child) {
+			toolCall := ToolCall{
+				ToolName:   child.Attributes["gen_ai.tool.name"],
+				Method:     child.Attributes["tool.method"],
+				Parameters: r.parseJSONAttribute(child.Attributes["gen_ai.tool.call.arguments"]),
+				Result:     r.parseJSONAttribute(child.Attributes["gen_ai.tool.call.result"]),
+				Duration:   child.Duration,
+				Success:    child.Status,
+			}
+			if toolCall.ToolName == "" {
+				toolCall.ToolName = child.Attributes["tool.name"]
+			}
+			if toolCall.Method == "" {
+				toolCall.Method = "execute" // Default method for MCP tools
+			}
+			if toolCall.ToolName != "" {
+				toolCalls = append(toolCalls, toolCall)
+			}
+		}
+		toolCalls = append(toolCalls, r.extractToolCallsFromChildren(child.SpanID, children)...)
+	}
+
+	return toolCalls
+}
+
 // parseJSONAttribute safely parses JSON from span attributes
 func (r *LearningReconciler) parseJSONAttribute(jsonStr string) map[string]interface{} {
 	if jsonStr == "" {
@@ -2471,6 +2829,10 @@ func (r *LearningReconciler) parseJSONAttribute(jsonStr string) map[string]inter
 		r.Log.V(1).Info("Failed to parse JSON attribute", "json", jsonStr, "error", err)
 		return map[string]interface{}{}
 	}
+	// A JSON null leaves the map nil, and callers add prefixed attributes to it
+	if result == nil {
+		return map[string]interface{}{}
+	}
 
 	return result
 }
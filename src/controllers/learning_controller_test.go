@@ -3,6 +3,7 @@ package controllers
 import (
 	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -54,6 +55,14 @@ end`, req.AgentName)
 	}, nil
 }
 
+func (m *MockSynthesizer) SynthesizeAgentStream(ctx context.Context, req synthesis.AgentSynthesisRequest) (<-chan synthesis.SynthesisProgress, error) {
+	updates := make(chan synthesis.SynthesisProgress, 1)
+	resp, err := m.SynthesizeAgent(ctx, req)
+	updates <- synthesis.SynthesisProgress{Done: true, Response: resp, Err: err}
+	close(updates)
+	return updates, nil
+}
+
 func (m *MockSynthesizer) DistillPersona(ctx context.Context, persona synthesis.PersonaInfo, agentContext synthesis.AgentContext) (string, error) {
 	return "mock distilled persona", nil
 }
@@ -1339,6 +1348,111 @@ func TestTaskLearningStatus_SerializeParse(t *testing.T) {
 	assert.Equal(t, original.ErrorResynthesisAttempts, parsed.ErrorResynthesisAttempts)
 }
 
+func TestTaskLearningStatus_SerializeParseTimeFields(t *testing.T) {
+	reconciler := &LearningReconciler{Log: logr.Discard()}
+
+	now := time.Date(2025, 3, 14, 9, 26, 53, 589793000, time.UTC)
+	original := &TaskLearningStatus{
+		TaskName:            "test_task",
+		CurrentVersion:      4,
+		LastLearningAttempt: now.Add(-time.Hour),
+		LastTraceTimestamp:  now.Add(-time.Minute),
+		CommonPattern:       "database.query->cache.set",
+		LastFailureTime:     now.Add(-2 * time.Hour),
+		LastErrorMessage:    "connection refused",
+		FailurePattern:      "network",
+		LastSuccessTime:     now,
+		LastExecutionTime:   now,
+		SuccessRate:         0.75,
+		LearningStatus:      "ready_for_symbolic",
+	}
+
+	serialized, err := reconciler.serializeTaskLearningStatus(original)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(serialized, "{"), "Expected JSON serialization")
+
+	parsed, err := reconciler.parseTaskLearningStatus(serialized)
+	require.NoError(t, err)
+	assert.True(t, original.LastFailureTime.Equal(parsed.LastFailureTime))
+	assert.True(t, original.LastSuccessTime.Equal(parsed.LastSuccessTime))
+	assert.True(t, original.LastLearningAttempt.Equal(parsed.LastLearningAttempt))
+	assert.True(t, original.LastTraceTimestamp.Equal(parsed.LastTraceTimestamp))
+	assert.True(t, original.LastExecutionTime.Equal(parsed.LastExecutionTime))
+
+	// Compare the remaining fields with the times normalized
+	parsed.LastFailureTime = original.LastFailureTime
+	parsed.LastSuccessTime = original.LastSuccessTime
+	parsed.LastLearningAttempt = original.LastLearningAttempt
+	parsed.LastTraceTimestamp = original.LastTraceTimestamp
+	parsed.LastExecutionTime = original.LastExecutionTime
+	assert.Equal(t, original, parsed)
+}
+
+func TestLearningReconciler_parseTaskLearningStatus_Formats(t *testing.T) {
+	reconciler := &LearningReconciler{Log: logr.Discard()}
+
+	legacy, err := reconciler.parseTaskLearningStatus("task:test_task,traces:5,attempts:2,version:3,symbolic:true,confidence:0.85,failures:1,error_attempts:2")
+	require.NoError(t, err)
+	assert.Equal(t, &TaskLearningStatus{
+		TaskName:                 "test_task",
+		TraceCount:               5,
+		LearningAttempts:         2,
+		CurrentVersion:           3,
+		IsSymbolic:               true,
+		PatternConfidence:        0.85,
+		ConsecutiveFailures:      1,
+		ErrorResynthesisAttempts: 2,
+	}, legacy)
+
+	// Corrupt JSON must not be mistaken for a fresh legacy status at version 1
+	_, err = reconciler.parseTaskLearningStatus(`{"taskName": "test_task", "currentVersion": 3`)
+	assert.Error(t, err)
+
+	_, err = reconciler.parseTaskLearningStatus("unknown:value")
+	assert.Error(t, err)
+}
+
+func TestLearningReconciler_LegacyLearningStatusMigratesToJSON(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, langopv1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default", UID: "test-uid"},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent-learning-status", Namespace: "default"},
+		Data: map[string]string{
+			"fetch_user-status": "task:fetch_user,traces:12,attempts:2,version:4,symbolic:true,confidence:0.9,failures:0,error_attempts:1",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent, configMap).Build()
+	reconciler := &LearningReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+	ctx := context.Background()
+
+	// The legacy entry is read for this cycle...
+	learningStatus, err := reconciler.getLearningStatus(ctx, agent)
+	require.NoError(t, err)
+	require.Contains(t, learningStatus, "fetch_user")
+	assert.Equal(t, int32(4), learningStatus["fetch_user"].CurrentVersion)
+
+	// ...and rewritten as JSON without losing its version counter
+	failedAt := time.Date(2025, 3, 14, 9, 0, 0, 0, time.UTC)
+	learningStatus["fetch_user"].LastFailureTime = failedAt
+	require.NoError(t, reconciler.updateLearningStatus(ctx, agent, learningStatus))
+
+	updated := &corev1.ConfigMap{}
+	require.NoError(t, fakeClient.Get(ctx, types.NamespacedName{Name: configMap.Name, Namespace: configMap.Namespace}, updated))
+	assert.False(t, isLegacyTaskLearningStatus(updated.Data["fetch_user-status"]), "Expected the status to be rewritten as JSON")
+
+	migrated, err := reconciler.getLearningStatus(ctx, agent)
+	require.NoError(t, err)
+	require.Contains(t, migrated, "fetch_user")
+	assert.Equal(t, int32(4), migrated["fetch_user"].CurrentVersion)
+	assert.True(t, migrated["fetch_user"].IsSymbolic)
+	assert.True(t, failedAt.Equal(migrated["fetch_user"].LastFailureTime))
+}
+
 func TestLearningReconciler_parseTaskLearningStatus_InvalidVersions(t *testing.T) {
 	reconciler := &LearningReconciler{
 		Log: logr.Discard(),
@@ -1556,6 +1670,201 @@ func TestLearningReconciler_convertSpansToTaskTraces(t *testing.T) {
 	assert.Empty(t, trace.ToolCalls)
 }
 
+// recordingSpanAdapter wraps MockAdapter to capture the span filter it was queried with
+type recordingSpanAdapter struct {
+	*telemetry.MockAdapter
+	filter telemetry.SpanFilter
+}
+
+func (m *recordingSpanAdapter) QuerySpans(ctx context.Context, filter telemetry.SpanFilter) ([]telemetry.Span, error) {
+	m.filter = filter
+	return m.MockAdapter.QuerySpans(ctx, filter)
+}
+
+// newTaskExecutionSpans returns the spans of one fetch_user execution: a task span carrying
+// its inputs/outputs as prefixed attributes and a child span for its database lookup
+func newTaskExecutionSpans(traceID string, start time.Time) []telemetry.Span {
+	return []telemetry.Span{
+		{
+			SpanID:        traceID + "-task",
+			TraceID:       traceID,
+			OperationName: "agent.task",
+			StartTime:     start,
+			EndTime:       start.Add(2 * time.Second),
+			Duration:      2 * time.Second,
+			Status:        true,
+			Attributes: map[string]string{
+				"agent.name":          "test-agent",
+				"task.name":           "fetch_user",
+				"task.input.user_id":  "123",
+				"task.output.user":    `{"name": "Alice"}`,
+				"task.output.comment": "found",
+			},
+		},
+		{
+			SpanID:        traceID + "-tool",
+			TraceID:       traceID,
+			ParentSpanID:  traceID + "-task",
+			OperationName: "execute_tool database",
+			StartTime:     start.Add(100 * time.Millisecond),
+			EndTime:       start.Add(time.Second),
+			Duration:      900 * time.Millisecond,
+			Status:        true,
+			Attributes: map[string]string{
+				"agent.name":                 "test-agent",
+				"gen_ai.operation.name":      "execute_tool",
+				"gen_ai.tool.name":           "database",
+				"tool.method":                "query",
+				"gen_ai.tool.call.arguments": `{"user_id": 123}`,
+			},
+		},
+	}
+}
+
+func TestLearningReconciler_convertSpansToTaskTraces_childToolSpans(t *testing.T) {
+	reconciler := &LearningReconciler{
+		Log: ctrl.Log.WithName("test"),
+	}
+
+	start := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	traces := reconciler.convertSpansToTaskTraces(newTaskExecutionSpans("trace-1", start))
+
+	// The tool span becomes a tool call of the task, not a trace of its own
+	require.Len(t, traces, 1)
+	trace := traces[0]
+	assert.Equal(t, "fetch_user", trace.TaskName)
+	assert.Equal(t, start, trace.Timestamp)
+	assert.Equal(t, 2*time.Second, trace.Duration)
+	assert.True(t, trace.Success)
+	assert.Equal(t, map[string]interface{}{"user_id": float64(123)}, trace.Inputs)
+	assert.Equal(t, map[string]interface{}{"user": map[string]interface{}{"name": "Alice"}, "comment": "found"}, trace.Outputs)
+
+	require.Len(t, trace.ToolCalls, 1)
+	toolCall := trace.ToolCalls[0]
+	assert.Equal(t, "database", toolCall.ToolName)
+	assert.Equal(t, "query", toolCall.Method)
+	assert.Equal(t, map[string]interface{}{"user_id": float64(123)}, toolCall.Parameters)
+	assert.Equal(t, 900*time.Millisecond, toolCall.Duration)
+	assert.True(t, toolCall.Success)
+}
+
+func TestLearningReconciler_convertSpansToTaskTraces_nullInputs(t *testing.T) {
+	reconciler := &LearningReconciler{
+		Log: ctrl.Log.WithName("test"),
+	}
+
+	// A task recorded with null inputs still collects its prefixed input attributes
+	spans := newTaskExecutionSpans("trace-1", time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC))
+	spans[0].Attributes["task.inputs"] = "null"
+	spans[0].Attributes["task.outputs"] = "null"
+	traces := reconciler.convertSpansToTaskTraces(spans)
+
+	require.Len(t, traces, 1)
+	assert.Equal(t, map[string]interface{}{"user_id": float64(123)}, traces[0].Inputs)
+	assert.Equal(t, "found", traces[0].Outputs["comment"])
+}
+
+func TestLearningReconciler_getExecutionTraces_queriesAgentWindow(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+	}
+	adapter := &recordingSpanAdapter{MockAdapter: telemetry.NewMockAdapter()}
+	reconciler := &LearningReconciler{
+		Log:              ctrl.Log.WithName("test"),
+		TelemetryAdapter: adapter,
+		LearningInterval: 10 * time.Minute,
+	}
+
+	_, err := reconciler.getExecutionTraces(context.Background(), agent)
+	require.NoError(t, err)
+
+	assert.Equal(t, map[string]string{"agent.name": "test-agent"}, adapter.filter.Attributes)
+	window := adapter.filter.TimeRange.End.Sub(adapter.filter.TimeRange.Start)
+	assert.Equal(t, 10*time.Minute*executionTraceWindowIntervals, window)
+
+	reconciler.LearningTraceWindow = 6 * time.Hour
+	reconciler.MinTraceAge = time.Minute
+	_, err = reconciler.getExecutionTraces(context.Background(), agent)
+	require.NoError(t, err)
+	window = adapter.filter.TimeRange.End.Sub(adapter.filter.TimeRange.Start)
+	assert.Equal(t, 6*time.Hour-time.Minute, window)
+	assert.WithinDuration(t, time.Now().Add(-time.Minute), adapter.filter.TimeRange.End, 5*time.Second)
+}
+
+func TestLearningReconciler_getExecutionTraces_traceWindowAndMinAge(t *testing.T) {
+	now := time.Now()
+	adapter := telemetry.NewMockAdapter()
+	for _, age := range []time.Duration{5 * time.Hour, 2 * time.Hour, 30 * time.Minute, 10 * time.Second} {
+		adapter.SpanResults = append(adapter.SpanResults, newTaskExecutionSpans(fmt.Sprintf("trace-%s", age), now.Add(-age))...)
+	}
+
+	reconciler := &LearningReconciler{
+		Log:                 ctrl.Log.WithName("test"),
+		TelemetryAdapter:    adapter,
+		LearningTraceWindow: time.Hour,
+		MinTraceAge:         time.Minute,
+	}
+
+	startTimes := func(traces []TaskTrace) []time.Time {
+		var starts []time.Time
+		for _, trace := range traces {
+			starts = append(starts, trace.Timestamp)
+		}
+		return starts
+	}
+
+	// Only the execution inside the window that completed over a minute ago is analyzed
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+	}
+	traces, err := reconciler.getExecutionTraces(context.Background(), agent)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{now.Add(-30 * time.Minute)}, startTimes(traces))
+
+	// Per-agent overrides widen the window and admit the most recent execution
+	agent.Spec.Learning = &langopv1alpha1.LearningSpec{TraceWindow: "3h", MinTraceAge: "0s"}
+	traces, err = reconciler.getExecutionTraces(context.Background(), agent)
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{now.Add(-2 * time.Hour), now.Add(-30 * time.Minute), now.Add(-10 * time.Second)}, startTimes(traces))
+}
+
+func TestLearningReconciler_checkLearningTriggers_fromTelemetry(t *testing.T) {
+	ctx := context.Background()
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+	}
+
+	start := time.Now().Add(-time.Hour)
+	adapter := telemetry.NewMockAdapter()
+	adapter.SpanResults = append(newTaskExecutionSpans("trace-1", start), newTaskExecutionSpans("trace-2", start.Add(time.Minute))...)
+
+	reconciler := &LearningReconciler{
+		Log:                  ctrl.Log.WithName("test"),
+		TelemetryAdapter:     adapter,
+		LearningThreshold:    3,
+		LearningInterval:     5 * time.Minute,
+		PatternConfidenceMin: 0.8,
+	}
+
+	// Two executions are below the threshold
+	learningStatus := map[string]*TaskLearningStatus{}
+	triggers, err := reconciler.checkLearningTriggers(ctx, agent, learningStatus)
+	require.NoError(t, err)
+	assert.Empty(t, triggers)
+	require.Contains(t, learningStatus, "fetch_user")
+	assert.Equal(t, int32(2), learningStatus["fetch_user"].TraceCount)
+
+	// A third identical execution reaches the threshold
+	adapter.SpanResults = append(adapter.SpanResults, newTaskExecutionSpans("trace-3", start.Add(2*time.Minute))...)
+	triggers, err = reconciler.checkLearningTriggers(ctx, agent, learningStatus)
+	require.NoError(t, err)
+	require.Len(t, triggers, 1)
+	assert.Equal(t, "traces_accumulated", triggers[0].EventType)
+	assert.Equal(t, "fetch_user", triggers[0].TaskName)
+	assert.Equal(t, int32(3), triggers[0].TraceCount)
+	assert.GreaterOrEqual(t, triggers[0].Confidence, 0.8)
+}
+
 // TestProcessAgentExecution tests the ProcessAgentExecution method
 func TestProcessAgentExecution(t *testing.T) {
 	scheme := runtime.NewScheme()
@@ -1877,3 +2186,66 @@ func TestLearningReconciler_mapJobToAgent(t *testing.T) {
 		})
 	}
 }
+
+func TestLearningReconciler_SkipsPinnedAgents(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, langopv1alpha1.AddToScheme(scheme))
+	require.NoError(t, corev1.AddToScheme(scheme))
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-agent",
+			Namespace: "default",
+			Annotations: map[string]string{
+				langopv1alpha1.PinnedCodeVersionAnnotation: "3",
+			},
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Instructions: "test instructions",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).Build()
+	adapter := &recordingSpanAdapter{MockAdapter: &telemetry.MockAdapter{
+		AvailableReturn: true,
+		SpanResults:     newTaskExecutionSpans("trace-1", time.Now().Add(-time.Hour)),
+	}}
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LearningReconciler{
+		Client:               fakeClient,
+		Scheme:               scheme,
+		Log:                  logr.Discard(),
+		Recorder:             recorder,
+		Synthesizer:          &MockSynthesizer{ShouldFail: true},
+		TelemetryAdapter:     adapter,
+		LearningEnabled:      true,
+		LearningThreshold:    1,
+		LearningInterval:     5 * time.Minute,
+		PatternConfidenceMin: 0.1,
+	}
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}}
+
+	// Repeated reconciles of the same pin record a single event
+	for i := 0; i < 3; i++ {
+		result, err := reconciler.Reconcile(ctx, req)
+		require.NoError(t, err)
+		assert.Equal(t, ctrl.Result{}, result)
+	}
+	events := drainEvents(recorder)
+	assert.Len(t, events, 1)
+	assert.True(t, hasEvent(events, "LearningSkippedDueToPin"))
+
+	// No traces were analyzed and no learning state or versions were created
+	assert.Equal(t, telemetry.SpanFilter{}, adapter.filter)
+	configMaps := &corev1.ConfigMapList{}
+	require.NoError(t, fakeClient.List(ctx, configMaps, client.InNamespace(agent.Namespace)))
+	assert.Empty(t, configMaps.Items)
+
+	// Pinning a different version is reported again
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, agent))
+	agent.Annotations[langopv1alpha1.PinnedCodeVersionAnnotation] = "4"
+	require.NoError(t, fakeClient.Update(ctx, agent))
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.True(t, hasEvent(drainEvents(recorder), "LearningSkippedDueToPin"))
+}
@@ -0,0 +1,189 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/audit"
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+const (
+	// healthGateMinExecutions is how many executions of a task the health gate needs after a
+	// promotion before it judges the learned version; with fewer the version is kept
+	healthGateMinExecutions = 10
+
+	// healthGateErrorRateMargin is how far a task's error rate may rise above its pre-promotion
+	// baseline before the learned version is rolled back
+	healthGateErrorRateMargin = 0.05
+)
+
+// LearningHealthGate tracks a promoted learned version until its error rate has been compared
+// with the task's error rate before the promotion
+type LearningHealthGate struct {
+	PromotedAt        time.Time `json:"promotedAt"`
+	BaselineErrorRate float64   `json:"baselineErrorRate"`
+	PreviousVersion   int32     `json:"previousVersion"`
+	PreviousSymbolic  bool      `json:"previousSymbolic"`
+	PreviousConfigMap string    `json:"previousConfigMap"`
+}
+
+// healthGateWindow returns how long after a promotion the task's error rate is observed before
+// the promotion is kept (0 disables the health gate)
+func (r *LearningReconciler) healthGateWindow(agent *langopv1alpha1.LanguageAgent) time.Duration {
+	if agent.Spec.Learning != nil && agent.Spec.Learning.HealthGateWindow != "" {
+		if window, err := time.ParseDuration(agent.Spec.Learning.HealthGateWindow); err == nil && window >= 0 {
+			return window
+		}
+	}
+	return r.HealthGateWindow
+}
+
+// currentDeploymentConfigMap returns the code ConfigMap the agent's Deployment currently
+// mounts, or "" when the agent doesn't run as a Deployment
+func (r *LearningReconciler) currentDeploymentConfigMap(ctx context.Context, agent *langopv1alpha1.LanguageAgent) string {
+	deployment, err := r.findAgentDeployment(ctx, agent)
+	if err != nil || deployment == nil {
+		return ""
+	}
+	return r.extractConfigMapReference(deployment)
+}
+
+// startHealthGate records the pre-promotion baseline of a task whose Deployment was just rolled
+// to a learned version, so checkHealthGates can roll it back if errors increase
+func (r *LearningReconciler) startHealthGate(agent *langopv1alpha1.LanguageAgent, taskStatus *TaskLearningStatus, previousConfigMap string) {
+	if previousConfigMap == "" || r.healthGateWindow(agent) <= 0 {
+		return
+	}
+	taskStatus.HealthGate = &LearningHealthGate{
+		PromotedAt:        time.Now(),
+		BaselineErrorRate: taskStatus.ErrorRate,
+		PreviousVersion:   taskStatus.CurrentVersion,
+		PreviousSymbolic:  taskStatus.IsSymbolic,
+		PreviousConfigMap: previousConfigMap,
+	}
+}
+
+// checkHealthGates compares the error rate of each task promoted over the last health gate
+// window with its pre-promotion baseline, rolling the promotion back if errors increased. It
+// returns how long until the next pending gate is due, or 0 when none are pending.
+func (r *LearningReconciler) checkHealthGates(ctx context.Context, agent *langopv1alpha1.LanguageAgent, learningStatus map[string]*TaskLearningStatus) time.Duration {
+	log := r.Log.WithValues("agent", agent.Name, "namespace", agent.Namespace)
+	window := r.healthGateWindow(agent)
+
+	var nextDue time.Duration
+	for taskName, taskStatus := range learningStatus {
+		gate := taskStatus.HealthGate
+		if gate == nil {
+			continue
+		}
+		if window <= 0 {
+			taskStatus.HealthGate = nil
+			continue
+		}
+		if remaining := time.Until(gate.PromotedAt.Add(window)); remaining > 0 {
+			if nextDue == 0 || remaining < nextDue {
+				nextDue = remaining
+			}
+			continue
+		}
+
+		errorRate, executions, err := r.postPromotionErrorRate(ctx, agent, taskName, gate.PromotedAt, window)
+		if err != nil {
+			// Keep the gate pending and check again on the next reconcile
+			log.Error(err, "Failed to query post-promotion error rate", "task", taskName)
+			continue
+		}
+		taskStatus.HealthGate = nil
+
+		if executions < healthGateMinExecutions {
+			log.Info("Too few executions after learning promotion, keeping learned version",
+				"task", taskName, "version", taskStatus.CurrentVersion, "executions", executions)
+			continue
+		}
+		if errorRate <= gate.BaselineErrorRate+healthGateErrorRateMargin {
+			log.Info("Learned version passed health gate",
+				"task", taskName, "errorRate", errorRate, "baseline", gate.BaselineErrorRate)
+			if r.Recorder != nil {
+				r.Recorder.Event(agent, corev1.EventTypeNormal, "LearningHealthGatePassed",
+					fmt.Sprintf("Learned version v%d of task %s kept: error rate %.1f%% over %d executions (baseline %.1f%%)",
+						taskStatus.CurrentVersion, taskName, errorRate*100, executions, gate.BaselineErrorRate*100))
+			}
+			continue
+		}
+
+		if err := r.rollbackPromotion(ctx, agent, taskName, taskStatus, gate, errorRate); err != nil {
+			log.Error(err, "Failed to roll back learned version that failed health gate", "task", taskName)
+			// Retry the rollback on the next reconcile
+			taskStatus.HealthGate = gate
+		}
+	}
+	return nextDue
+}
+
+// postPromotionErrorRate returns the task's error rate over the window after promotion and the
+// number of executions it was measured over
+func (r *LearningReconciler) postPromotionErrorRate(ctx context.Context, agent *langopv1alpha1.LanguageAgent, taskName string, promotedAt time.Time, window time.Duration) (float64, int, error) {
+	if r.TelemetryAdapter == nil || !r.TelemetryAdapter.Available() {
+		return 0, 0, nil
+	}
+
+	timeRange := telemetry.TimeRange{Start: promotedAt, End: promotedAt.Add(window)}
+	spans, err := r.TelemetryAdapter.QuerySpans(ctx, telemetry.SpanFilter{
+		TimeRange:  timeRange,
+		Attributes: map[string]string{"agent.name": agent.Name},
+		Limit:      1000,
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var taskTraces []TaskTrace
+	for _, trace := range filterTracesByTime(r.convertSpansToTaskTraces(spans), timeRange) {
+		if trace.TaskName == taskName {
+			taskTraces = append(taskTraces, trace)
+		}
+	}
+	return r.calculateErrorRate(taskTraces), len(taskTraces), nil
+}
+
+// rollbackPromotion restores the Deployment and task status to the version before a learned
+// version that increased the task's error rate
+func (r *LearningReconciler) rollbackPromotion(ctx context.Context, agent *langopv1alpha1.LanguageAgent, taskName string, taskStatus *TaskLearningStatus, gate *LearningHealthGate, errorRate float64) error {
+	deployment, err := r.findAgentDeployment(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("failed to find agent deployment: %w", err)
+	}
+	if deployment == nil {
+		return fmt.Errorf("agent deployment not found")
+	}
+	if err := r.rollbackDeployment(ctx, deployment, gate.PreviousConfigMap); err != nil {
+		return err
+	}
+
+	failedVersion := taskStatus.CurrentVersion
+	taskStatus.CurrentVersion = gate.PreviousVersion
+	taskStatus.IsSymbolic = gate.PreviousSymbolic
+	taskStatus.LastLearningAttempt = time.Now()
+
+	r.Log.Info("Rolled back learned version that increased errors",
+		"agent", agent.Name, "task", taskName, "version", failedVersion,
+		"errorRate", errorRate, "baseline", gate.BaselineErrorRate)
+	if r.Recorder != nil {
+		r.Recorder.Event(agent, corev1.EventTypeWarning, "LearningHealthGateRollback",
+			fmt.Sprintf("Rolled back learned version v%d of task %s: error rate rose from %.1f%% to %.1f%% after promotion",
+				failedVersion, taskName, gate.BaselineErrorRate*100, errorRate*100))
+	}
+	r.Audit.Emit(ctx, auditControllerLearning, audit.ActionLearningRollback, agent,
+		fmt.Sprintf("Rolled back learned version v%d after its error rate rose from %.3f to %.3f", failedVersion, gate.BaselineErrorRate, errorRate),
+		map[string]string{
+			"task":              taskName,
+			"failedConfigMap":   fmt.Sprintf("%s-v%d", agent.Name, failedVersion),
+			"restoredConfigMap": gate.PreviousConfigMap,
+		})
+	return nil
+}
@@ -0,0 +1,221 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+// postPromotionSpans returns fetch_user executions after start, the first failures of which failed
+func postPromotionSpans(start time.Time, executions, failures int) []telemetry.Span {
+	var spans []telemetry.Span
+	for i := 0; i < executions; i++ {
+		execution := newTaskExecutionSpans(fmt.Sprintf("trace-%d", i), start.Add(time.Duration(i+1)*time.Minute))
+		if i < failures {
+			execution[0].Status = false
+			execution[0].ErrorMessage = "undefined method for nil"
+		}
+		spans = append(spans, execution...)
+	}
+	return spans
+}
+
+func newHealthGateTest(t *testing.T, spans []telemetry.Span) (*LearningReconciler, *record.FakeRecorder, *langopv1alpha1.LanguageAgent, *appsv1.Deployment) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, langopv1alpha1.AddToScheme(scheme))
+	require.NoError(t, appsv1.AddToScheme(scheme))
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-agent",
+			Namespace: "default",
+			Labels:    map[string]string{"app.kubernetes.io/name": "test-agent"},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Volumes: []corev1.Volume{{
+						Name: "agent-code",
+						VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+							LocalObjectReference: corev1.LocalObjectReference{Name: "test-agent-v2"},
+						}},
+					}},
+				},
+			},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent, deployment).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LearningReconciler{
+		Client:           fakeClient,
+		Scheme:           scheme,
+		Log:              logr.Discard(),
+		Recorder:         recorder,
+		TelemetryAdapter: &telemetry.MockAdapter{AvailableReturn: true, SpanResults: spans},
+		HealthGateWindow: 30 * time.Minute,
+	}
+	return reconciler, recorder, agent, deployment
+}
+
+// newPromotedTaskStatus returns the status of fetch_user just after v2 was promoted over v1
+func newPromotedTaskStatus(promotedAt time.Time, baseline float64) map[string]*TaskLearningStatus {
+	return map[string]*TaskLearningStatus{
+		"fetch_user": {
+			TaskName:       "fetch_user",
+			CurrentVersion: 2,
+			IsSymbolic:     true,
+			HealthGate: &LearningHealthGate{
+				PromotedAt:        promotedAt,
+				BaselineErrorRate: baseline,
+				PreviousVersion:   1,
+				PreviousConfigMap: "test-agent-v1",
+			},
+		},
+	}
+}
+
+func TestLearningReconciler_HealthGateRollsBackWorseVersion(t *testing.T) {
+	promotedAt := time.Now().Add(-time.Hour)
+	reconciler, recorder, agent, deployment := newHealthGateTest(t, postPromotionSpans(promotedAt, 10, 4))
+	learningStatus := newPromotedTaskStatus(promotedAt, 0.1)
+	ctx := context.Background()
+
+	due := reconciler.checkHealthGates(ctx, agent, learningStatus)
+	assert.Zero(t, due)
+
+	taskStatus := learningStatus["fetch_user"]
+	assert.Nil(t, taskStatus.HealthGate)
+	assert.Equal(t, int32(1), taskStatus.CurrentVersion)
+	assert.False(t, taskStatus.IsSymbolic)
+
+	updated := &appsv1.Deployment{}
+	require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, updated))
+	assert.Equal(t, "test-agent-v1", updated.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+	assert.True(t, hasEvent(drainEvents(recorder), "LearningHealthGateRollback"))
+}
+
+func TestLearningReconciler_HealthGateKeepsImprovedVersion(t *testing.T) {
+	promotedAt := time.Now().Add(-time.Hour)
+	reconciler, recorder, agent, deployment := newHealthGateTest(t, postPromotionSpans(promotedAt, 10, 0))
+	learningStatus := newPromotedTaskStatus(promotedAt, 0.1)
+	ctx := context.Background()
+
+	reconciler.checkHealthGates(ctx, agent, learningStatus)
+
+	taskStatus := learningStatus["fetch_user"]
+	assert.Nil(t, taskStatus.HealthGate)
+	assert.Equal(t, int32(2), taskStatus.CurrentVersion)
+
+	updated := &appsv1.Deployment{}
+	require.NoError(t, reconciler.Get(ctx, types.NamespacedName{Name: deployment.Name, Namespace: deployment.Namespace}, updated))
+	assert.Equal(t, "test-agent-v2", updated.Spec.Template.Spec.Volumes[0].ConfigMap.Name)
+	assert.True(t, hasEvent(drainEvents(recorder), "LearningHealthGatePassed"))
+}
+
+func TestLearningReconciler_HealthGateToleratesNoise(t *testing.T) {
+	tests := []struct {
+		name       string
+		executions int
+		failures   int
+	}{
+		{name: "too few executions", executions: 3, failures: 1},
+		{name: "within margin", executions: 20, failures: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			promotedAt := time.Now().Add(-time.Hour)
+			reconciler, _, agent, _ := newHealthGateTest(t, postPromotionSpans(promotedAt, tt.executions, tt.failures))
+			// The gate works without an event recorder
+			reconciler.Recorder = nil
+			learningStatus := newPromotedTaskStatus(promotedAt, 0.1)
+
+			reconciler.checkHealthGates(context.Background(), agent, learningStatus)
+
+			assert.Nil(t, learningStatus["fetch_user"].HealthGate)
+			assert.Equal(t, int32(2), learningStatus["fetch_user"].CurrentVersion)
+		})
+	}
+}
+
+func TestLearningReconciler_HealthGateWaitsForWindow(t *testing.T) {
+	promotedAt := time.Now().Add(-10 * time.Minute)
+	reconciler, recorder, agent, _ := newHealthGateTest(t, postPromotionSpans(promotedAt, 5, 5))
+	learningStatus := newPromotedTaskStatus(promotedAt, 0.1)
+
+	due := reconciler.checkHealthGates(context.Background(), agent, learningStatus)
+
+	assert.InDelta(t, (20 * time.Minute).Seconds(), due.Seconds(), 5)
+	assert.NotNil(t, learningStatus["fetch_user"].HealthGate)
+	assert.Equal(t, int32(2), learningStatus["fetch_user"].CurrentVersion)
+	assert.Empty(t, drainEvents(recorder))
+
+	// Another promotion waits for the pending gate
+	err := reconciler.processLearningTrigger(context.Background(), agent, LearningEvent{
+		TaskName:   "fetch_user",
+		EventType:  "traces_accumulated",
+		Confidence: 1,
+	}, learningStatus)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), learningStatus["fetch_user"].CurrentVersion)
+}
+
+func TestLearningReconciler_HealthGateRunsForPinnedAgent(t *testing.T) {
+	promotedAt := time.Now().Add(-time.Hour)
+	reconciler, recorder, agent, _ := newHealthGateTest(t, postPromotionSpans(promotedAt, 10, 4))
+	require.NoError(t, corev1.AddToScheme(reconciler.Scheme))
+	reconciler.LearningEnabled = true
+	ctx := context.Background()
+
+	// The agent was pinned after fetch_user v2 was promoted
+	agent.Annotations = map[string]string{langopv1alpha1.PinnedCodeVersionAnnotation: "2"}
+	require.NoError(t, reconciler.Update(ctx, agent))
+	require.NoError(t, reconciler.updateLearningStatus(ctx, agent, newPromotedTaskStatus(promotedAt, 0.1)))
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}})
+	require.NoError(t, err)
+
+	events := drainEvents(recorder)
+	assert.True(t, hasEvent(events, "LearningSkippedDueToPin"))
+	assert.True(t, hasEvent(events, "LearningHealthGateRollback"))
+	learningStatus, err := reconciler.getLearningStatus(ctx, agent)
+	require.NoError(t, err)
+	assert.Nil(t, learningStatus["fetch_user"].HealthGate)
+}
+
+func TestLearningReconciler_StartHealthGate(t *testing.T) {
+	reconciler := &LearningReconciler{HealthGateWindow: 30 * time.Minute}
+	agent := &langopv1alpha1.LanguageAgent{ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"}}
+	taskStatus := &TaskLearningStatus{TaskName: "fetch_user", CurrentVersion: 1, ErrorRate: 0.25}
+
+	reconciler.startHealthGate(agent, taskStatus, "test-agent-code")
+	require.NotNil(t, taskStatus.HealthGate)
+	assert.Equal(t, 0.25, taskStatus.HealthGate.BaselineErrorRate)
+	assert.Equal(t, int32(1), taskStatus.HealthGate.PreviousVersion)
+	assert.Equal(t, "test-agent-code", taskStatus.HealthGate.PreviousConfigMap)
+
+	// The agent can disable the gate
+	taskStatus.HealthGate = nil
+	agent.Spec.Learning = &langopv1alpha1.LearningSpec{HealthGateWindow: "0s"}
+	reconciler.startHealthGate(agent, taskStatus, "test-agent-code")
+	assert.Nil(t, taskStatus.HealthGate)
+}
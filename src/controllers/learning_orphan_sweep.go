@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// OrphanedConfigMapsDeleted counts versioned code ConfigMaps deleted because their agent no longer exists
+var OrphanedConfigMapsDeleted = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "langop_orphaned_configmaps_deleted_total",
+		Help: "Number of versioned agent code ConfigMaps deleted because their agent no longer exists",
+	},
+	[]string{"namespace"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(OrphanedConfigMapsDeleted)
+}
+
+// DefaultOrphanSweepInterval is how often versioned ConfigMaps of deleted agents are looked for
+const DefaultOrphanSweepInterval = time.Hour
+
+// OrphanSweeper deletes versioned code ConfigMaps whose agent no longer exists. It sweeps every
+// namespace when it starts and then every Interval, so versions orphaned before the operator
+// started, or by agents deleted while it was down, are cleaned up too.
+type OrphanSweeper struct {
+	Client   client.Client
+	Log      logr.Logger
+	Interval time.Duration
+}
+
+// Start runs a sweep every Interval until ctx is done. It implements manager.Runnable.
+func (s *OrphanSweeper) Start(ctx context.Context) error {
+	interval := s.Interval
+	if interval <= 0 {
+		interval = DefaultOrphanSweepInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.Sweep(ctx, ""); err != nil {
+			s.Log.Error(err, "Failed to sweep orphaned versioned ConfigMaps")
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// Sweep deletes the versioned code ConfigMaps in namespace, or in every namespace when empty,
+// whose agent no longer exists and returns how many were deleted. Versions are owned by their
+// agent and normally garbage collected with it; this catches versions that outlived it anyway,
+// such as ones created without an owner reference by earlier operator versions.
+func (s *OrphanSweeper) Sweep(ctx context.Context, namespace string) (int, error) {
+	configMaps := &corev1.ConfigMapList{}
+	if err := s.Client.List(ctx, configMaps, client.InNamespace(namespace),
+		client.MatchingLabels{"langop.io/component": synthesis.VersionedCodeComponent}); err != nil {
+		return 0, fmt.Errorf("failed to list versioned ConfigMaps: %w", err)
+	}
+
+	// Several versions share an agent, so each agent is looked up once
+	agentExists := map[types.NamespacedName]bool{}
+	deleted := 0
+	for i := range configMaps.Items {
+		configMap := &configMaps.Items[i]
+		agentName := configMap.Labels["langop.io/agent"]
+		if agentName == "" {
+			continue
+		}
+		key := types.NamespacedName{Name: agentName, Namespace: configMap.Namespace}
+		exists, checked := agentExists[key]
+		if !checked {
+			err := s.Client.Get(ctx, key, &langopv1alpha1.LanguageAgent{})
+			if err != nil && !errors.IsNotFound(err) {
+				return deleted, fmt.Errorf("failed to get agent %s: %w", key, err)
+			}
+			exists = err == nil
+			agentExists[key] = exists
+		}
+		if exists {
+			continue
+		}
+
+		if err := s.Client.Delete(ctx, configMap); err != nil && !errors.IsNotFound(err) {
+			s.Log.Error(err, "Failed to delete orphaned versioned ConfigMap", "configMap", configMap.Name, "namespace", configMap.Namespace)
+			continue
+		}
+		s.Log.Info("Deleted orphaned versioned ConfigMap", "configMap", configMap.Name, "namespace", configMap.Namespace, "agent", agentName)
+		OrphanedConfigMapsDeleted.WithLabelValues(configMap.Namespace).Inc()
+		deleted++
+	}
+	return deleted, nil
+}
+
+// sweepOrphanedConfigMaps deletes the versioned code ConfigMaps in namespace whose agent no longer
+// exists and returns how many were deleted
+func (r *LearningReconciler) sweepOrphanedConfigMaps(ctx context.Context, namespace string) (int, error) {
+	return (&OrphanSweeper{Client: r.Client, Log: r.Log}).Sweep(ctx, namespace)
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+func newVersionedConfigMap(namespace, agent, name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels: map[string]string{
+				"langop.io/agent":     agent,
+				"langop.io/component": synthesis.VersionedCodeComponent,
+			},
+		},
+		Data: map[string]string{"agent.rb": "agent \"" + agent + "\" do\nend"},
+	}
+}
+
+func newOrphanSweepReconciler(t *testing.T, objects ...client.Object) (*LearningReconciler, client.Client) {
+	scheme := testutil.SetupTestScheme(t)
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	return &LearningReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}, fakeClient
+}
+
+func TestLearningReconciler_SweepOrphanedConfigMaps(t *testing.T) {
+	live := &langopv1alpha1.LanguageAgent{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "sweep"}}
+	liveVersion := newVersionedConfigMap("sweep", "live", "live-v1")
+	orphanV1 := newVersionedConfigMap("sweep", "deleted", "deleted-v1")
+	orphanV2 := newVersionedConfigMap("sweep", "deleted", "deleted-v2")
+	otherNamespace := newVersionedConfigMap("elsewhere", "deleted", "deleted-v1")
+	unrelated := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{
+		Name:      "deleted-learning-status",
+		Namespace: "sweep",
+		Labels:    map[string]string{"langop.io/agent": "deleted", "langop.io/component": "learning-status"},
+	}}
+	reconciler, fakeClient := newOrphanSweepReconciler(t, live, liveVersion, orphanV1, orphanV2, otherNamespace, unrelated)
+	ctx := context.Background()
+	before := promtestutil.ToFloat64(OrphanedConfigMapsDeleted.WithLabelValues("sweep"))
+
+	deleted, err := reconciler.sweepOrphanedConfigMaps(ctx, "sweep")
+	if err != nil {
+		t.Fatalf("Sweep failed: %v", err)
+	}
+	if deleted != 2 {
+		t.Errorf("Expected 2 orphaned versions deleted, got %d", deleted)
+	}
+	if got := promtestutil.ToFloat64(OrphanedConfigMapsDeleted.WithLabelValues("sweep")) - before; got != 2 {
+		t.Errorf("Expected langop_orphaned_configmaps_deleted_total to grow by 2, got %v", got)
+	}
+
+	for _, cm := range []*corev1.ConfigMap{orphanV1, orphanV2} {
+		err := fakeClient.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, &corev1.ConfigMap{})
+		if !errors.IsNotFound(err) {
+			t.Errorf("Expected orphaned ConfigMap %s to be deleted, got %v", cm.Name, err)
+		}
+	}
+	for _, cm := range []*corev1.ConfigMap{liveVersion, otherNamespace, unrelated} {
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}, &corev1.ConfigMap{}); err != nil {
+			t.Errorf("Expected ConfigMap %s/%s to be preserved, got %v", cm.Namespace, cm.Name, err)
+		}
+	}
+}
+
+func TestLearningReconciler_DeletedAgentSweepsVersions(t *testing.T) {
+	orphan := newVersionedConfigMap("default", "deleted-agent", "deleted-agent-v3")
+	reconciler, fakeClient := newOrphanSweepReconciler(t, orphan)
+	ctx := context.Background()
+
+	_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "deleted-agent", Namespace: "default"}})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	err = fakeClient.Get(ctx, types.NamespacedName{Name: orphan.Name, Namespace: orphan.Namespace}, &corev1.ConfigMap{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected the deleted agent's versions to be swept, got %v", err)
+	}
+}
+
+func TestOrphanSweeper_SweepsAtStartup(t *testing.T) {
+	live := &langopv1alpha1.LanguageAgent{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: "team-a"}}
+	liveVersion := newVersionedConfigMap("team-a", "live", "live-v1")
+	orphanA := newVersionedConfigMap("team-a", "deleted", "deleted-v1")
+	orphanB := newVersionedConfigMap("team-b", "live", "live-v1")
+	_, fakeClient := newOrphanSweepReconciler(t, live, liveVersion, orphanA, orphanB)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	sweeper := &OrphanSweeper{Client: fakeClient, Log: logr.Discard()}
+	go func() { done <- sweeper.Start(ctx) }()
+
+	// Orphans in every namespace go in the first sweep, without any agent being deleted
+	deadline := time.Now().Add(5 * time.Second)
+	for _, cm := range []*corev1.ConfigMap{orphanA, orphanB} {
+		key := types.NamespacedName{Name: cm.Name, Namespace: cm.Namespace}
+		for !errors.IsNotFound(fakeClient.Get(ctx, key, &corev1.ConfigMap{})) {
+			if time.Now().After(deadline) {
+				t.Fatalf("Expected orphaned ConfigMap %s to be swept at startup", key)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	cancel()
+	if err := <-done; err != nil {
+		t.Errorf("Start returned an error: %v", err)
+	}
+
+	if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: liveVersion.Name, Namespace: liveVersion.Namespace}, &corev1.ConfigMap{}); err != nil {
+		t.Errorf("Expected the live agent's version to be preserved, got %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"context"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	learningRolloutQueued   = "queued"
+	learningRolloutInFlight = "in_flight"
+)
+
+// LearningRollouts tracks learning-driven Deployment rollouts waiting for or holding a rollout slot
+var LearningRollouts = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "langop_learning_rollouts",
+		Help: "Number of learning-driven Deployment rollouts by state (queued or in_flight)",
+	},
+	[]string{"state"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(LearningRollouts)
+}
+
+// rolloutLimiter bounds the number of learning-driven rollouts running at once across all agents,
+// so simultaneous learning triggers queue up instead of rolling out every Deployment together
+type rolloutLimiter struct {
+	slots chan struct{}
+}
+
+// newRolloutLimiter creates a limiter allowing maxConcurrent rollouts; zero or less means unlimited
+func newRolloutLimiter(maxConcurrent int32) *rolloutLimiter {
+	if maxConcurrent <= 0 {
+		return &rolloutLimiter{}
+	}
+	return &rolloutLimiter{slots: make(chan struct{}, maxConcurrent)}
+}
+
+// Acquire blocks until a rollout slot is free or ctx is done. Every successful Acquire must be paired with Release.
+func (l *rolloutLimiter) Acquire(ctx context.Context) error {
+	if l.slots != nil {
+		LearningRollouts.WithLabelValues(learningRolloutQueued).Inc()
+		defer LearningRollouts.WithLabelValues(learningRolloutQueued).Dec()
+
+		select {
+		case l.slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	LearningRollouts.WithLabelValues(learningRolloutInFlight).Inc()
+	return nil
+}
+
+// Release frees the slot held by a completed rollout
+func (l *rolloutLimiter) Release() {
+	LearningRollouts.WithLabelValues(learningRolloutInFlight).Dec()
+	if l.slots != nil {
+		<-l.slots
+	}
+}
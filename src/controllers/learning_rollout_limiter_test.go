@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestRolloutLimiter_BoundsConcurrentRollouts(t *testing.T) {
+	limiter := newRolloutLimiter(2)
+
+	var inFlight, maxInFlight, completed int32
+	var wg sync.WaitGroup
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := limiter.Acquire(context.Background()); err != nil {
+				t.Errorf("Acquire failed: %v", err)
+				return
+			}
+			defer limiter.Release()
+
+			current := atomic.AddInt32(&inFlight, 1)
+			for {
+				observed := atomic.LoadInt32(&maxInFlight)
+				if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+			atomic.AddInt32(&inFlight, -1)
+			atomic.AddInt32(&completed, 1)
+		}()
+	}
+	wg.Wait()
+
+	if maxInFlight > 2 {
+		t.Errorf("Expected at most 2 concurrent rollouts, observed %d", maxInFlight)
+	}
+	if completed != 6 {
+		t.Errorf("Expected all 6 rollouts to complete, got %d", completed)
+	}
+	if value := promtestutil.ToFloat64(LearningRollouts.WithLabelValues(learningRolloutInFlight)); value != 0 {
+		t.Errorf("Expected no in-flight rollouts after completion, got %v", value)
+	}
+	if value := promtestutil.ToFloat64(LearningRollouts.WithLabelValues(learningRolloutQueued)); value != 0 {
+		t.Errorf("Expected no queued rollouts after completion, got %v", value)
+	}
+}
+
+func TestRolloutLimiter_ReleasesOnCompletion(t *testing.T) {
+	limiter := newRolloutLimiter(1)
+
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	// A second rollout queues while the slot is held
+	acquired := make(chan error, 1)
+	go func() {
+		acquired <- limiter.Acquire(context.Background())
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Expected second rollout to wait for the slot")
+	case <-time.After(50 * time.Millisecond):
+	}
+	if value := promtestutil.ToFloat64(LearningRollouts.WithLabelValues(learningRolloutQueued)); value != 1 {
+		t.Errorf("Expected 1 queued rollout, got %v", value)
+	}
+
+	limiter.Release()
+
+	select {
+	case err := <-acquired:
+		if err != nil {
+			t.Fatalf("Expected queued rollout to acquire the released slot, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected queued rollout to proceed after release")
+	}
+	limiter.Release()
+}
+
+func TestRolloutLimiter_CancelledWhileQueued(t *testing.T) {
+	limiter := newRolloutLimiter(1)
+	if err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	defer limiter.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Acquire(ctx); err == nil {
+		t.Error("Expected Acquire to fail when the context expires while queued")
+	}
+}
+
+func TestRolloutLimiter_Unlimited(t *testing.T) {
+	limiter := newRolloutLimiter(0)
+	for i := 0; i < 10; i++ {
+		if err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("Acquire failed: %v", err)
+		}
+	}
+	for i := 0; i < 10; i++ {
+		limiter.Release()
+	}
+}
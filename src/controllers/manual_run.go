@@ -0,0 +1,131 @@
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// TriggerNowAnnotation on a scheduled LanguageAgent runs it once right away, outside its schedule.
+// The value is a timestamp, RFC 3339 or Unix seconds; each distinct second triggers a single run.
+const TriggerNowAnnotation = "langop.io/trigger-now"
+
+// manualRunTriggerAnnotation records on the agent's CronJob the last trigger-now value handled,
+// so a trigger doesn't run again once its Job has been cleaned up
+const manualRunTriggerAnnotation = "langop.io/manual-run-trigger"
+
+// manualRunJobTTL is how long a finished manual run's Job and pods are kept for inspection
+const manualRunJobTTL = 24 * time.Hour
+
+// parseTriggerTimestamp parses a trigger-now value as RFC 3339 or Unix seconds
+func parseTriggerTimestamp(value string) (time.Time, error) {
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	ts, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s must be an RFC 3339 timestamp or Unix seconds, got %q", TriggerNowAnnotation, value)
+	}
+	return ts, nil
+}
+
+// maxManualRunJobNameLength keeps manual run Job names usable as the job-name label of their pods
+const maxManualRunJobNameLength = 63
+
+// manualRunJobName names the Job of a manual run after the agent and the trigger's Unix time. Names
+// too long for a label are truncated and suffixed with a hash of the full name to stay unique.
+func manualRunJobName(agentName string, ts time.Time) string {
+	name := fmt.Sprintf("%s-manual-%d", agentName, ts.Unix())
+	if len(name) <= maxManualRunJobNameLength {
+		return name
+	}
+	suffix := fmt.Sprintf("%x", sha256.Sum256([]byte(name)))[:8]
+	prefix := strings.TrimRight(name[:maxManualRunJobNameLength-len(suffix)-1], "-.")
+	return prefix + "-" + suffix
+}
+
+// reconcileManualRun starts a one-shot Job from the CronJob's job template when the agent carries
+// a trigger-now value the CronJob hasn't seen yet
+func (r *LanguageAgentReconciler) reconcileManualRun(ctx context.Context, agent *langopv1alpha1.LanguageAgent, cronJob *batchv1.CronJob) error {
+	trigger := agent.Annotations[TriggerNowAnnotation]
+	if trigger == "" || cronJob.Annotations[manualRunTriggerAnnotation] == trigger {
+		return nil
+	}
+	log := log.FromContext(ctx)
+
+	ts, err := parseTriggerTimestamp(trigger)
+	if err != nil {
+		// Remember the bad value too, so it is reported once rather than on every reconcile
+		log.Info("Ignoring invalid manual run trigger", "trigger", trigger)
+		if r.Recorder != nil {
+			r.Recorder.Event(agent, corev1.EventTypeWarning, "InvalidManualTrigger", err.Error())
+		}
+		return r.recordManualRunTrigger(ctx, cronJob, trigger)
+	}
+
+	ttl := int32(manualRunJobTTL.Seconds())
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        manualRunJobName(agent.Name, ts),
+			Namespace:   cronJob.Namespace,
+			Labels:      cronJob.Labels,
+			Annotations: map[string]string{TriggerNowAnnotation: trigger},
+		},
+		Spec: *cronJob.Spec.JobTemplate.Spec.DeepCopy(),
+	}
+	job.Spec.TTLSecondsAfterFinished = &ttl
+	if err := controllerutil.SetControllerReference(agent, job, r.Scheme); err != nil {
+		return err
+	}
+
+	if err := r.Create(ctx, job); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create manual run job: %w", err)
+		}
+		existing := &batchv1.Job{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(job), existing); err != nil {
+			return fmt.Errorf("failed to get manual run job: %w", err)
+		}
+		// The Job of this trigger exists already if recording the trigger failed after creating it.
+		// Otherwise an earlier trigger for the same second, written differently, started it.
+		if previous := existing.Annotations[TriggerNowAnnotation]; previous != trigger {
+			log.Info("Ignoring duplicate manual run trigger", "job", job.Name, "trigger", trigger, "previousTrigger", previous)
+			if r.Recorder != nil {
+				r.Recorder.Eventf(agent, corev1.EventTypeWarning, "DuplicateManualTrigger",
+					"Manual run %s was already started by trigger %q for the same time", job.Name, previous)
+			}
+		}
+		return r.recordManualRunTrigger(ctx, cronJob, trigger)
+	}
+	log.Info("Triggered manual run", "job", job.Name, "trigger", trigger)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeNormal, "ManualRunTriggered", "Started manual run %s", job.Name)
+	}
+	return r.recordManualRunTrigger(ctx, cronJob, trigger)
+}
+
+// recordManualRunTrigger marks a trigger-now value as handled on the agent's CronJob
+func (r *LanguageAgentReconciler) recordManualRunTrigger(ctx context.Context, cronJob *batchv1.CronJob, trigger string) error {
+	patch := client.MergeFrom(cronJob.DeepCopy())
+	if cronJob.Annotations == nil {
+		cronJob.Annotations = make(map[string]string)
+	}
+	cronJob.Annotations[manualRunTriggerAnnotation] = trigger
+	if err := r.Patch(ctx, cronJob, patch); err != nil {
+		return fmt.Errorf("failed to record manual run trigger: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func listManualRunJobs(t *testing.T, c client.Client) []batchv1.Job {
+	t.Helper()
+	jobs := &batchv1.JobList{}
+	if err := c.List(context.Background(), jobs, client.InNamespace("default")); err != nil {
+		t.Fatalf("Failed to list jobs: %v", err)
+	}
+	return jobs.Items
+}
+
+func TestLanguageAgentController_ManualRun(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "nightly-report", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "scheduled",
+			Schedule:      "0 2 * * *",
+		},
+	}
+	reconciler, fakeClient := newForceWorkloadReconciler(t, agent)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	ctx := context.Background()
+
+	// Without a trigger only the CronJob is created
+	if err := reconciler.reconcileCronJob(ctx, agent); err != nil {
+		t.Fatalf("reconcileCronJob failed: %v", err)
+	}
+	if jobs := listManualRunJobs(t, fakeClient); len(jobs) != 0 {
+		t.Fatalf("Expected no manual run without a trigger, got %d jobs", len(jobs))
+	}
+
+	// A trigger runs once, however often the agent is reconciled
+	agent.Annotations = map[string]string{TriggerNowAnnotation: "2026-10-16T09:30:00Z"}
+	for i := 0; i < 3; i++ {
+		if err := reconciler.reconcileCronJob(ctx, agent); err != nil {
+			t.Fatalf("reconcileCronJob failed: %v", err)
+		}
+	}
+	jobs := listManualRunJobs(t, fakeClient)
+	if len(jobs) != 1 {
+		t.Fatalf("Expected one manual run, got %d jobs", len(jobs))
+	}
+	job := jobs[0]
+	expectedName := manualRunJobName(agent.Name, time.Date(2026, 10, 16, 9, 30, 0, 0, time.UTC))
+	if job.Name != expectedName {
+		t.Errorf("Expected job %s, got %s", expectedName, job.Name)
+	}
+	if job.Spec.TTLSecondsAfterFinished == nil || *job.Spec.TTLSecondsAfterFinished != int32(manualRunJobTTL.Seconds()) {
+		t.Errorf("Expected TTLSecondsAfterFinished %v, got %v", manualRunJobTTL.Seconds(), job.Spec.TTLSecondsAfterFinished)
+	}
+	if !metav1.IsControlledBy(&job, agent) {
+		t.Error("Expected the manual run to be owned by the agent")
+	}
+	if containers := job.Spec.Template.Spec.Containers; len(containers) != 1 || containers[0].Image != agent.Spec.Image {
+		t.Errorf("Expected the CronJob's agent container, got %+v", containers)
+	}
+	events := drainEvents(recorder)
+	if !hasEvent(events, "ManualRunTriggered") {
+		t.Errorf("Expected ManualRunTriggered event, got %v", events)
+	}
+
+	// Once its Job is cleaned up the same trigger doesn't run again
+	if err := fakeClient.Delete(ctx, &job); err != nil {
+		t.Fatalf("Failed to delete job: %v", err)
+	}
+	if err := reconciler.reconcileCronJob(ctx, agent); err != nil {
+		t.Fatalf("reconcileCronJob failed: %v", err)
+	}
+	if jobs := listManualRunJobs(t, fakeClient); len(jobs) != 0 {
+		t.Errorf("Expected the handled trigger not to run again, got %d jobs", len(jobs))
+	}
+
+	// A new trigger value starts another run
+	agent.Annotations[TriggerNowAnnotation] = "1792150200"
+	if err := reconciler.reconcileCronJob(ctx, agent); err != nil {
+		t.Fatalf("reconcileCronJob failed: %v", err)
+	}
+	jobs = listManualRunJobs(t, fakeClient)
+	if len(jobs) != 1 || jobs[0].Name != "nightly-report-manual-1792150200" {
+		t.Errorf("Expected a second manual run, got %v", jobs)
+	}
+	drainEvents(recorder)
+
+	// The same second written as RFC 3339 is reported as a duplicate rather than a new run
+	agent.Annotations[TriggerNowAnnotation] = time.Unix(1792150200, 0).UTC().Format(time.RFC3339)
+	if err := reconciler.reconcileCronJob(ctx, agent); err != nil {
+		t.Fatalf("reconcileCronJob failed: %v", err)
+	}
+	if jobs := listManualRunJobs(t, fakeClient); len(jobs) != 1 {
+		t.Errorf("Expected no further manual run, got %d jobs", len(jobs))
+	}
+	events = drainEvents(recorder)
+	if len(events) != 1 || !hasEvent(events, "DuplicateManualTrigger") {
+		t.Errorf("Expected a single DuplicateManualTrigger event, got %v", events)
+	}
+}
+
+func TestManualRunJobName(t *testing.T) {
+	ts := time.Unix(1792150200, 0)
+	long := strings.Repeat("a", 60)
+	name := manualRunJobName(long, ts)
+	if len(name) > maxManualRunJobNameLength {
+		t.Errorf("Expected at most %d characters, got %d (%s)", maxManualRunJobNameLength, len(name), name)
+	}
+	if other := manualRunJobName(long, ts.Add(time.Second)); other == name {
+		t.Errorf("Expected truncated names of distinct triggers to differ, both %s", name)
+	}
+	if name != manualRunJobName(long, ts) {
+		t.Error("Expected the truncated name to be stable")
+	}
+}
+
+func TestLanguageAgentController_ManualRunInvalidTrigger(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "nightly-report",
+			Namespace:   "default",
+			Annotations: map[string]string{TriggerNowAnnotation: "now"},
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "scheduled",
+			Schedule:      "0 2 * * *",
+		},
+	}
+	reconciler, fakeClient := newForceWorkloadReconciler(t, agent)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if err := reconciler.reconcileCronJob(ctx, agent); err != nil {
+			t.Fatalf("reconcileCronJob failed: %v", err)
+		}
+	}
+	if jobs := listManualRunJobs(t, fakeClient); len(jobs) != 0 {
+		t.Errorf("Expected no manual run for an invalid trigger, got %d jobs", len(jobs))
+	}
+	events := drainEvents(recorder)
+	if len(events) != 1 || !hasEvent(events, "InvalidManualTrigger") {
+		t.Errorf("Expected a single InvalidManualTrigger event, got %v", events)
+	}
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// CrossNamespaceDeniedCondition is set on agents referencing a model in another namespace that
+// no LanguageModelGrant in that namespace permits
+const CrossNamespaceDeniedCondition = "CrossNamespaceDenied"
+
+// deniedModelReference returns a message naming the first cross-namespace model reference of
+// the agent that isn't permitted by a LanguageModelGrant in the model's namespace, or "" when
+// every reference is allowed
+func (r *LanguageAgentReconciler) deniedModelReference(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (string, error) {
+	grants := map[string][]langopv1alpha1.LanguageModelGrant{}
+	for _, ref := range agent.Spec.ModelRefs {
+		if ref.Namespace == "" || ref.Namespace == agent.Namespace {
+			continue
+		}
+
+		namespaceGrants, listed := grants[ref.Namespace]
+		if !listed {
+			list := &langopv1alpha1.LanguageModelGrantList{}
+			if err := r.List(ctx, list, client.InNamespace(ref.Namespace)); err != nil {
+				return "", fmt.Errorf("failed to list model grants in namespace %s: %w", ref.Namespace, err)
+			}
+			namespaceGrants = list.Items
+			grants[ref.Namespace] = namespaceGrants
+		}
+
+		permitted := false
+		for _, grant := range namespaceGrants {
+			if grant.Spec.Permits(agent.Namespace, ref.Name) {
+				permitted = true
+				break
+			}
+		}
+		if !permitted {
+			return fmt.Sprintf("No LanguageModelGrant in namespace %s permits namespace %s to reference model %s",
+				ref.Namespace, agent.Namespace, ref.Name), nil
+		}
+	}
+	return "", nil
+}
+
+// enforceModelGrants fails agents with a cross-namespace model reference that isn't granted,
+// setting the CrossNamespaceDenied condition, and clears the condition once every reference is
+// granted
+func (r *LanguageAgentReconciler) enforceModelGrants(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
+	message, err := r.deniedModelReference(ctx, agent)
+	if err != nil {
+		return err
+	}
+	if message == "" {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, CrossNamespaceDeniedCondition)
+		return nil
+	}
+
+	if !meta.IsStatusConditionTrue(agent.Status.Conditions, CrossNamespaceDeniedCondition) && r.Recorder != nil {
+		r.Recorder.Event(agent, corev1.EventTypeWarning, CrossNamespaceDeniedCondition, message)
+	}
+	log.FromContext(ctx).Info("Cross-namespace model reference denied", "agent", agent.Name, "reason", message)
+	SetCondition(&agent.Status.Conditions, CrossNamespaceDeniedCondition, metav1.ConditionTrue, "GrantMissing", message, agent.Generation)
+	SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionFalse, CrossNamespaceDeniedCondition, message, agent.Generation)
+	setFailure(agent, langopv1alpha1.FailureReasonValidation, message)
+	return fmt.Errorf("cross-namespace model reference denied: %s", message)
+}
+
+// agentsForModelGrant maps a LanguageModelGrant event to the agents in the namespaces it names
+// that reference models in the grant's namespace, so granting or revoking access takes effect
+func (r *LanguageAgentReconciler) agentsForModelGrant(ctx context.Context, obj client.Object) []reconcile.Request {
+	grant, ok := obj.(*langopv1alpha1.LanguageModelGrant)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, from := range grant.Spec.From {
+		agents := &langopv1alpha1.LanguageAgentList{}
+		if err := r.List(ctx, agents, client.InNamespace(from.Namespace)); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to list agents for model grant", "grant", grant.Name, "namespace", from.Namespace)
+			continue
+		}
+		for _, agent := range agents.Items {
+			for _, ref := range agent.Spec.ModelRefs {
+				if ref.Namespace == grant.Namespace {
+					requests = append(requests, reconcile.Request{
+						NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace},
+					})
+					break
+				}
+			}
+		}
+	}
+	return requests
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+func newModelGrant(name, namespace string, from []string, to ...string) *langopv1alpha1.LanguageModelGrant {
+	grant := &langopv1alpha1.LanguageModelGrant{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace}}
+	for _, ns := range from {
+		grant.Spec.From = append(grant.Spec.From, langopv1alpha1.ModelGrantFrom{Namespace: ns})
+	}
+	for _, model := range to {
+		grant.Spec.To = append(grant.Spec.To, langopv1alpha1.ModelGrantTo{Name: model})
+	}
+	return grant
+}
+
+func TestLanguageAgentController_EnforceModelGrants(t *testing.T) {
+	tests := []struct {
+		name      string
+		refs      []langopv1alpha1.ModelReference
+		grants    []client.Object
+		expectErr bool
+	}{
+		{name: "same-namespace references need no grant",
+			refs: []langopv1alpha1.ModelReference{{Name: "local"}, {Name: "explicit", Namespace: "team-a"}}},
+		{name: "namespace-wide grant",
+			refs:   []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "shared-models"}},
+			grants: []client.Object{newModelGrant("team-a", "shared-models", []string{"team-b", "team-a"})}},
+		{name: "grant naming the model",
+			refs:   []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "shared-models"}},
+			grants: []client.Object{newModelGrant("gpt-only", "shared-models", []string{"team-a"}, "gpt")}},
+		{name: "no grant", expectErr: true,
+			refs: []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "shared-models"}}},
+		{name: "grant for another namespace", expectErr: true,
+			refs:   []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "shared-models"}},
+			grants: []client.Object{newModelGrant("team-b", "shared-models", []string{"team-b"})}},
+		{name: "grant for another model", expectErr: true,
+			refs:   []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "shared-models"}},
+			grants: []client.Object{newModelGrant("claude-only", "shared-models", []string{"team-a"}, "claude")}},
+		{name: "grant in the agent's namespace", expectErr: true,
+			refs:   []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "shared-models"}},
+			grants: []client.Object{newModelGrant("self-granted", "team-a", []string{"team-a"})}},
+		{name: "every cross-namespace reference needs a grant", expectErr: true,
+			refs:   []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "shared-models"}, {Name: "claude", Namespace: "other-models"}},
+			grants: []client.Object{newModelGrant("team-a", "shared-models", []string{"team-a"})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: "team-a"},
+				Spec:       langopv1alpha1.LanguageAgentSpec{ModelRefs: tt.refs},
+			}
+			reconciler, _ := newForceWorkloadReconciler(t, tt.grants...)
+
+			err := reconciler.enforceModelGrants(context.Background(), agent)
+			if tt.expectErr != (err != nil) {
+				t.Fatalf("Expected error %v, got %v", tt.expectErr, err)
+			}
+			denied := meta.IsStatusConditionTrue(agent.Status.Conditions, CrossNamespaceDeniedCondition)
+			if denied != tt.expectErr {
+				t.Errorf("Expected %s condition %v, got %v", CrossNamespaceDeniedCondition, tt.expectErr, denied)
+			}
+			if tt.expectErr && !meta.IsStatusConditionFalse(agent.Status.Conditions, "Ready") {
+				t.Error("Expected a denied agent to be not Ready")
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_ModelGrantLifecycle(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "consumer", Namespace: "team-a"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ModelRefs: []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "shared-models"}},
+		},
+	}
+	reconciler, fakeClient := newForceWorkloadReconciler(t, agent.DeepCopy())
+	ctx := context.Background()
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	// Denied until the model's namespace grants access, with one event per transition
+	for i := 0; i < 2; i++ {
+		if err := reconciler.enforceModelGrants(ctx, agent); err == nil {
+			t.Fatal("Expected the ungranted reference to be denied")
+		}
+	}
+	if events := drainEvents(recorder); len(events) != 1 || !hasEvent(events, CrossNamespaceDeniedCondition) {
+		t.Errorf("Expected one %s event, got %v", CrossNamespaceDeniedCondition, events)
+	}
+
+	grant := newModelGrant("team-a", "shared-models", []string{"team-a"})
+	if err := fakeClient.Create(ctx, grant); err != nil {
+		t.Fatalf("Failed to create grant: %v", err)
+	}
+	requests := reconciler.agentsForModelGrant(ctx, grant)
+	if len(requests) != 1 || requests[0].Name != "consumer" || requests[0].Namespace != "team-a" {
+		t.Errorf("Expected the grant to requeue the referencing agent, got %v", requests)
+	}
+
+	if err := reconciler.enforceModelGrants(ctx, agent); err != nil {
+		t.Fatalf("Expected the granted reference to be allowed, got %v", err)
+	}
+	if meta.FindStatusCondition(agent.Status.Conditions, CrossNamespaceDeniedCondition) != nil {
+		t.Errorf("Expected the %s condition to be cleared once granted", CrossNamespaceDeniedCondition)
+	}
+}
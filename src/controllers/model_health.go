@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ModelReachableCondition reports whether the provider endpoint behind a LanguageModel answers requests
+const ModelReachableCondition = "ModelReachable"
+
+const (
+	// modelHealthCheckTimeout bounds a single provider health check request
+	modelHealthCheckTimeout = 10 * time.Second
+	// maxModelHealthCheckInterval caps the health check backoff after repeated failures
+	maxModelHealthCheckInterval = 30 * time.Minute
+	// anthropicAPIVersion is the API version sent with Anthropic health checks
+	anthropicAPIVersion = "2023-06-01"
+	// azureAPIVersion is the API version sent with Azure OpenAI health checks
+	azureAPIVersion = "2024-10-21"
+)
+
+// Default provider API base URLs used when a LanguageModel has no endpoint
+const (
+	openAIDefaultBaseURL    = "https://api.openai.com/v1"
+	anthropicDefaultBaseURL = "https://api.anthropic.com/v1"
+)
+
+// ErrModelHealthCheckUnsupported is returned for providers without a lightweight health check request
+var ErrModelHealthCheckUnsupported = errors.New("provider does not support health checks")
+
+// ModelHealthChecker checks that the provider endpoint behind a LanguageModel is reachable
+type ModelHealthChecker interface {
+	Check(ctx context.Context, model *langopv1alpha1.LanguageModel, apiKey string) error
+}
+
+// HTTPModelHealthChecker lists the provider's models, a request that is cheap and authenticated
+type HTTPModelHealthChecker struct {
+	Client *http.Client
+}
+
+// Check implements ModelHealthChecker
+func (h *HTTPModelHealthChecker) Check(ctx context.Context, model *langopv1alpha1.LanguageModel, apiKey string) error {
+	req, err := newModelHealthCheckRequest(ctx, model, apiKey)
+	if err != nil {
+		return err
+	}
+
+	httpClient := h.Client
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: modelHealthCheckTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusUnauthorized, resp.StatusCode == http.StatusForbidden:
+		return fmt.Errorf("%s rejected the API key with status %d", req.URL.Host, resp.StatusCode)
+	default:
+		return fmt.Errorf("%s returned status %d", req.URL.Host, resp.StatusCode)
+	}
+}
+
+// newModelHealthCheckRequest builds the provider-appropriate model listing request
+func newModelHealthCheckRequest(ctx context.Context, model *langopv1alpha1.LanguageModel, apiKey string) (*http.Request, error) {
+	var url string
+	headers := map[string]string{}
+
+	switch model.Spec.Provider {
+	case "openai", "openai-compatible", "custom":
+		baseURL := model.Spec.Endpoint
+		if baseURL == "" {
+			if model.Spec.Provider != "openai" {
+				return nil, fmt.Errorf("provider %s requires an endpoint", model.Spec.Provider)
+			}
+			baseURL = openAIDefaultBaseURL
+		}
+		url = normalizeV1BaseURL(baseURL) + "/models"
+		if apiKey != "" {
+			headers["Authorization"] = "Bearer " + apiKey
+		}
+	case "anthropic":
+		baseURL := anthropicDefaultBaseURL
+		if model.Spec.Endpoint != "" {
+			baseURL = normalizeV1BaseURL(model.Spec.Endpoint)
+		}
+		url = baseURL + "/models"
+		headers["anthropic-version"] = anthropicAPIVersion
+		if apiKey != "" {
+			headers["x-api-key"] = apiKey
+		}
+	case "azure":
+		if model.Spec.Endpoint == "" {
+			return nil, fmt.Errorf("provider azure requires an endpoint")
+		}
+		url = strings.TrimSuffix(model.Spec.Endpoint, "/") + "/openai/models?api-version=" + azureAPIVersion
+		if apiKey != "" {
+			headers["api-key"] = apiKey
+		}
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrModelHealthCheckUnsupported, model.Spec.Provider)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	return req, nil
+}
+
+// normalizeV1BaseURL adds the /v1 suffix OpenAI-style APIs are served under, as synthesis does
+func normalizeV1BaseURL(endpoint string) string {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1") {
+		endpoint += "/v1"
+	}
+	return endpoint
+}
+
+// modelAPIKey reads the model's API key from its referenced secret
+func (r *LanguageModelReconciler) modelAPIKey(ctx context.Context, model *langopv1alpha1.LanguageModel) (string, error) {
+	if model.Spec.APIKeySecretRef == nil {
+		return "", nil
+	}
+	secretNamespace := model.Spec.APIKeySecretRef.Namespace
+	if secretNamespace == "" {
+		secretNamespace = model.Namespace
+	}
+	secretKey := model.Spec.APIKeySecretRef.Key
+	if secretKey == "" {
+		secretKey = "api-key"
+	}
+
+	secret := &corev1.Secret{}
+	if err := r.Get(ctx, types.NamespacedName{Name: model.Spec.APIKeySecretRef.Name, Namespace: secretNamespace}, secret); err != nil {
+		return "", fmt.Errorf("failed to get API key secret: %w", err)
+	}
+	apiKey := string(secret.Data[secretKey])
+	if apiKey == "" {
+		return "", fmt.Errorf("API key not found in secret %s/%s at key %s", secretNamespace, model.Spec.APIKeySecretRef.Name, secretKey)
+	}
+	return apiKey, nil
+}
+
+// healthCheckInterval returns how long to wait before the next health check, doubling the
+// configured interval for each consecutive failure up to maxModelHealthCheckInterval
+func (r *LanguageModelReconciler) healthCheckInterval(failures int32) time.Duration {
+	interval := r.HealthCheckInterval
+	for i := int32(0); i < failures && interval < maxModelHealthCheckInterval; i++ {
+		interval = min(interval*2, maxModelHealthCheckInterval)
+	}
+	return interval
+}
+
+// reconcileHealthCheck probes the model's provider endpoint when a check is due, records the
+// result in the ModelReachable condition, and returns how long until the next check
+// (zero when health checks are disabled or unsupported)
+func (r *LanguageModelReconciler) reconcileHealthCheck(ctx context.Context, model *langopv1alpha1.LanguageModel) time.Duration {
+	if r.HealthChecker == nil || r.HealthCheckInterval <= 0 {
+		return 0
+	}
+	log := log.FromContext(ctx)
+
+	// Copy the condition, SetCondition updates it in place and events compare against the old status
+	previous := meta.FindStatusCondition(model.Status.Conditions, ModelReachableCondition).DeepCopy()
+	if previous != nil && previous.Reason == "ProbeUnsupported" && previous.ObservedGeneration == model.Generation {
+		return 0
+	}
+
+	// Spec changes are checked right away, otherwise wait out the (backed off) interval
+	interval := r.healthCheckInterval(model.Status.HealthCheckFailures)
+	if previous != nil && previous.ObservedGeneration == model.Generation && model.Status.LastHealthCheck != nil {
+		if remaining := interval - time.Since(model.Status.LastHealthCheck.Time); remaining > 0 {
+			return remaining
+		}
+	}
+
+	apiKey, err := r.modelAPIKey(ctx, model)
+	if err == nil {
+		checkCtx, cancel := context.WithTimeout(ctx, modelHealthCheckTimeout)
+		err = r.HealthChecker.Check(checkCtx, model, apiKey)
+		cancel()
+	}
+
+	now := metav1.Now()
+	model.Status.LastHealthCheck = &now
+
+	if errors.Is(err, ErrModelHealthCheckUnsupported) {
+		model.Status.HealthCheckFailures = 0
+		SetCondition(&model.Status.Conditions, ModelReachableCondition, metav1.ConditionUnknown, "ProbeUnsupported",
+			fmt.Sprintf("Health checks are not supported for provider %s", model.Spec.Provider), model.Generation)
+		return 0
+	}
+
+	if err != nil {
+		model.Status.Healthy = false
+		model.Status.HealthCheckFailures++
+		SetCondition(&model.Status.Conditions, ModelReachableCondition, metav1.ConditionFalse, "HealthCheckFailed", err.Error(), model.Generation)
+
+		log.Info("Model health check failed", "failures", model.Status.HealthCheckFailures, "error", err.Error())
+		if r.Recorder != nil && (previous == nil || previous.Status != metav1.ConditionFalse) {
+			r.Recorder.Eventf(model, corev1.EventTypeWarning, "ModelUnreachable", "Model endpoint is unreachable: %v", err)
+		}
+		return r.healthCheckInterval(model.Status.HealthCheckFailures)
+	}
+
+	model.Status.Healthy = true
+	model.Status.HealthCheckFailures = 0
+	SetCondition(&model.Status.Conditions, ModelReachableCondition, metav1.ConditionTrue, "HealthCheckSucceeded",
+		"Model endpoint answered the health check", model.Generation)
+	if r.Recorder != nil && previous != nil && previous.Status == metav1.ConditionFalse {
+		r.Recorder.Event(model, corev1.EventTypeNormal, "ModelReachable", "Model endpoint is reachable again")
+	}
+	return r.HealthCheckInterval
+}
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// fakeModelHealthChecker returns a fixed result and records the API key it was called with
+type fakeModelHealthChecker struct {
+	err    error
+	calls  int
+	apiKey string
+}
+
+func (f *fakeModelHealthChecker) Check(_ context.Context, _ *langopv1alpha1.LanguageModel, apiKey string) error {
+	f.calls++
+	f.apiKey = apiKey
+	return f.err
+}
+
+func TestHTTPModelHealthChecker_ProviderRequests(t *testing.T) {
+	tests := []struct {
+		name         string
+		provider     string
+		endpointPath string
+		status       int
+		expectPath   string
+		expectHeader string
+		expectValue  string
+		expectErr    bool
+	}{
+		{name: "openai-compatible lists models", provider: "openai-compatible", status: http.StatusOK,
+			expectPath: "/v1/models", expectHeader: "Authorization", expectValue: "Bearer sk-test"},
+		{name: "endpoint with v1 suffix", provider: "custom", endpointPath: "/v1/", status: http.StatusOK,
+			expectPath: "/v1/models", expectHeader: "Authorization", expectValue: "Bearer sk-test"},
+		{name: "anthropic uses its key header", provider: "anthropic", status: http.StatusOK,
+			expectPath: "/v1/models", expectHeader: "x-api-key", expectValue: "sk-test"},
+		{name: "azure uses its key header", provider: "azure", status: http.StatusOK,
+			expectPath: "/openai/models", expectHeader: "api-key", expectValue: "sk-test"},
+		{name: "rejected API key", provider: "openai-compatible", status: http.StatusUnauthorized,
+			expectPath: "/v1/models", expectHeader: "Authorization", expectValue: "Bearer sk-test", expectErr: true},
+		{name: "server error", provider: "openai-compatible", status: http.StatusBadGateway,
+			expectPath: "/v1/models", expectHeader: "Authorization", expectValue: "Bearer sk-test", expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotPath, gotHeader string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				gotPath = req.URL.Path
+				gotHeader = req.Header.Get(tt.expectHeader)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			model := &langopv1alpha1.LanguageModel{
+				Spec: langopv1alpha1.LanguageModelSpec{Provider: tt.provider, ModelName: "test", Endpoint: server.URL + tt.endpointPath},
+			}
+			err := (&HTTPModelHealthChecker{Client: server.Client()}).Check(context.Background(), model, "sk-test")
+			if tt.expectErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.expectPath, gotPath)
+			assert.Equal(t, tt.expectValue, gotHeader)
+		})
+	}
+}
+
+func TestHTTPModelHealthChecker_UnsupportedProvider(t *testing.T) {
+	model := &langopv1alpha1.LanguageModel{Spec: langopv1alpha1.LanguageModelSpec{Provider: "bedrock", ModelName: "test"}}
+	err := (&HTTPModelHealthChecker{}).Check(context.Background(), model, "")
+	assert.ErrorIs(t, err, ErrModelHealthCheckUnsupported)
+}
+
+func TestLanguageModelController_HealthCheckBackoff(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default", Generation: 1},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:        "openai",
+			ModelName:       "gpt-4o",
+			APIKeySecretRef: &langopv1alpha1.SecretReference{Name: "openai-key"},
+		},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "openai-key", Namespace: "default"},
+		Data:       map[string][]byte{"api-key": []byte("sk-test")},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(model, secret).Build()
+	recorder := record.NewFakeRecorder(10)
+	checker := &fakeModelHealthChecker{}
+	reconciler := &LanguageModelReconciler{
+		Client:              fakeClient,
+		Scheme:              scheme,
+		Log:                 logr.Discard(),
+		Recorder:            recorder,
+		HealthChecker:       checker,
+		HealthCheckInterval: 2 * time.Minute,
+	}
+
+	// A reachable model is checked again after the interval
+	next := reconciler.reconcileHealthCheck(ctx, model)
+	assert.Equal(t, 2*time.Minute, next)
+	assert.Equal(t, "sk-test", checker.apiKey)
+	assert.True(t, model.Status.Healthy)
+	require.NotNil(t, model.Status.LastHealthCheck)
+	assert.True(t, meta.IsStatusConditionTrue(model.Status.Conditions, ModelReachableCondition))
+
+	// A check that isn't due yet is skipped
+	next = reconciler.reconcileHealthCheck(ctx, model)
+	assert.Equal(t, 1, checker.calls)
+	assert.LessOrEqual(t, next, 2*time.Minute)
+	assert.Greater(t, next, time.Duration(0))
+
+	// Failures back off and only the transition to unreachable is reported
+	checker.err = errors.New("connection refused")
+	for i, expected := range []time.Duration{4 * time.Minute, 8 * time.Minute, 16 * time.Minute, 30 * time.Minute, 30 * time.Minute} {
+		model.Status.LastHealthCheck = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+		next = reconciler.reconcileHealthCheck(ctx, model)
+		assert.Equal(t, expected, next, "failure %d", i+1)
+	}
+	assert.False(t, model.Status.Healthy)
+	assert.Equal(t, int32(5), model.Status.HealthCheckFailures)
+	assert.True(t, meta.IsStatusConditionFalse(model.Status.Conditions, ModelReachableCondition))
+	events := drainEvents(recorder)
+	unreachable := 0
+	for _, event := range events {
+		if strings.Contains(event, "ModelUnreachable") {
+			unreachable++
+		}
+	}
+	assert.Equal(t, 1, unreachable, "Expected a single ModelUnreachable event, got %v", events)
+
+	// Recovery resets the backoff
+	checker.err = nil
+	model.Status.LastHealthCheck = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+	next = reconciler.reconcileHealthCheck(ctx, model)
+	assert.Equal(t, 2*time.Minute, next)
+	assert.Zero(t, model.Status.HealthCheckFailures)
+	assert.True(t, hasEvent(drainEvents(recorder), "ModelReachable"))
+}
+
+func TestLanguageModelController_UnreachableModelIsDegraded(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:  "openai-compatible",
+			ModelName: "llama3",
+			Endpoint:  "http://llm.internal:1234",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(model).WithStatusSubresource(model).Build()
+	reconciler := &LanguageModelReconciler{
+		Client:              fakeClient,
+		Scheme:              scheme,
+		Log:                 logr.Discard(),
+		Recorder:            record.NewFakeRecorder(10),
+		HealthChecker:       &fakeModelHealthChecker{err: errors.New("connection refused")},
+		HealthCheckInterval: 2 * time.Minute,
+	}
+
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: model.Name, Namespace: model.Namespace}}
+	_, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	result, err := reconciler.Reconcile(ctx, req)
+	require.NoError(t, err)
+	assert.Equal(t, 4*time.Minute, result.RequeueAfter, "Expected the backed off interval after a failed check")
+
+	updated := &langopv1alpha1.LanguageModel{}
+	require.NoError(t, fakeClient.Get(ctx, req.NamespacedName, updated))
+	assert.Equal(t, "Degraded", updated.Status.Phase)
+	assert.False(t, updated.Status.Healthy)
+	reachable := meta.FindStatusCondition(updated.Status.Conditions, ModelReachableCondition)
+	require.NotNil(t, reachable)
+	assert.Equal(t, metav1.ConditionFalse, reachable.Status)
+	assert.Contains(t, reachable.Message, "connection refused")
+}
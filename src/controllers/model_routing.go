@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// AllModelsUnreachableCondition is set on agents whose referenced models all failed their last
+// provider health check
+const AllModelsUnreachableCondition = "AllModelsUnreachable"
+
+// modelEndpoint is a referenced model resolved to the endpoint the agent calls
+type modelEndpoint struct {
+	key         string
+	url         string
+	name        string
+	role        string
+	unreachable bool
+}
+
+// routeModelsByHealth drops the models whose last health check failed, keeping the failover
+// order of the rest. When every model is unreachable they are all kept, since an agent without
+// endpoints can't recover on its own, and the AllModelsUnreachable condition is set instead.
+func (r *LanguageAgentReconciler) routeModelsByHealth(ctx context.Context, agent *langopv1alpha1.LanguageAgent, endpoints []modelEndpoint) []modelEndpoint {
+	if !r.featureGateEnabled(agent, langopv1alpha1.FeatureGateModelHealthRouting) {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, AllModelsUnreachableCondition)
+		return endpoints
+	}
+
+	var reachable []modelEndpoint
+	var unreachable []string
+	for _, endpoint := range endpoints {
+		if endpoint.unreachable {
+			unreachable = append(unreachable, endpoint.key)
+			continue
+		}
+		reachable = append(reachable, endpoint)
+	}
+
+	if len(endpoints) > 0 && len(reachable) == 0 {
+		message := "All referenced models are unreachable, keeping them as endpoints: " + strings.Join(unreachable, ", ")
+		if !meta.IsStatusConditionTrue(agent.Status.Conditions, AllModelsUnreachableCondition) && r.Recorder != nil {
+			r.Recorder.Event(agent, corev1.EventTypeWarning, AllModelsUnreachableCondition, message)
+		}
+		log.FromContext(ctx).Info("All referenced models are unreachable", "agent", agent.Name, "models", unreachable)
+		SetCondition(&agent.Status.Conditions, AllModelsUnreachableCondition, metav1.ConditionTrue,
+			"HealthCheckFailed", message, agent.Generation)
+		return endpoints
+	}
+
+	meta.RemoveStatusCondition(&agent.Status.Conditions, AllModelsUnreachableCondition)
+	if len(unreachable) > 0 {
+		log.FromContext(ctx).Info("Excluding unreachable models from the agent's endpoints", "agent", agent.Name, "models", unreachable)
+	}
+	return reachable
+}
+
+// modelReachabilityChanged passes LanguageModel updates that flip the ModelReachable condition,
+// the only model changes that move agent endpoints
+func modelReachabilityChanged() predicate.Predicate {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldModel, okOld := e.ObjectOld.(*langopv1alpha1.LanguageModel)
+			newModel, okNew := e.ObjectNew.(*langopv1alpha1.LanguageModel)
+			if !okOld || !okNew {
+				return false
+			}
+			return synthesisModelUnreachable(oldModel) != synthesisModelUnreachable(newModel)
+		},
+		DeleteFunc:  func(e event.DeleteEvent) bool { return false },
+		GenericFunc: func(e event.GenericEvent) bool { return false },
+	}
+}
+
+// agentsForModel maps a LanguageModel event to the agents referencing the model
+func (r *LanguageAgentReconciler) agentsForModel(ctx context.Context, obj client.Object) []reconcile.Request {
+	agents := &langopv1alpha1.LanguageAgentList{}
+	if err := r.List(ctx, agents); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list agents for model", "model", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, agent := range agents.Items {
+		for _, ref := range agent.Spec.ModelRefs {
+			namespace := ref.Namespace
+			if namespace == "" {
+				namespace = agent.Namespace
+			}
+			if ref.Name == obj.GetName() && namespace == obj.GetNamespace() {
+				requests = append(requests, reconcile.Request{
+					NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace},
+				})
+				break
+			}
+		}
+	}
+	return requests
+}
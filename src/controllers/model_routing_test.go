@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// newHealthCheckedModel returns a model whose last health check had the given result
+func newHealthCheckedModel(name, modelName string, status metav1.ConditionStatus) *langopv1alpha1.LanguageModel {
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai-compatible", ModelName: modelName},
+	}
+	if status != "" {
+		SetCondition(&model.Status.Conditions, ModelReachableCondition, status, "HealthCheck", "", 0)
+	}
+	return model
+}
+
+func TestResolveModels_HealthBasedRouting(t *testing.T) {
+	routingEnabled := map[string]bool{langopv1alpha1.FeatureGateModelHealthRouting: true}
+	tests := []struct {
+		name              string
+		health            map[string]metav1.ConditionStatus
+		agentGates        map[string]bool
+		expectModels      string
+		expectRoles       string
+		expectUnreachable bool
+	}{
+		{
+			name:         "all reachable keep the failover order",
+			health:       map[string]metav1.ConditionStatus{"gpt": metav1.ConditionTrue, "llama": metav1.ConditionTrue},
+			agentGates:   routingEnabled,
+			expectModels: "gpt-4o,llama3",
+			expectRoles:  "primary,fallback",
+		},
+		{
+			name:         "unreachable primary is excluded",
+			health:       map[string]metav1.ConditionStatus{"gpt": metav1.ConditionFalse, "llama": metav1.ConditionTrue},
+			agentGates:   routingEnabled,
+			expectModels: "llama3",
+			expectRoles:  "fallback",
+		},
+		{
+			name:         "unchecked models are kept",
+			health:       map[string]metav1.ConditionStatus{"gpt": metav1.ConditionUnknown, "llama": metav1.ConditionFalse},
+			agentGates:   routingEnabled,
+			expectModels: "gpt-4o",
+			expectRoles:  "primary",
+		},
+		{
+			name:              "all unreachable are kept and surfaced",
+			health:            map[string]metav1.ConditionStatus{"gpt": metav1.ConditionFalse, "llama": metav1.ConditionFalse},
+			agentGates:        routingEnabled,
+			expectModels:      "gpt-4o,llama3",
+			expectRoles:       "primary,fallback",
+			expectUnreachable: true,
+		},
+		{
+			name:         "gate off by default keeps unreachable models",
+			health:       map[string]metav1.ConditionStatus{"gpt": metav1.ConditionFalse, "llama": metav1.ConditionTrue},
+			expectModels: "gpt-4o,llama3",
+			expectRoles:  "primary,fallback",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "routed-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					ModelRefs: []langopv1alpha1.ModelReference{
+						{Name: "llama", Role: langopv1alpha1.ModelRoleFallback},
+						{Name: "gpt", Role: langopv1alpha1.ModelRolePrimary},
+					},
+					FeatureGates: tt.agentGates,
+				},
+			}
+			reconciler, _ := newForceWorkloadReconciler(t,
+				newHealthCheckedModel("gpt", "gpt-4o", tt.health["gpt"]),
+				newHealthCheckedModel("llama", "llama3", tt.health["llama"]))
+
+			models, err := reconciler.resolveModels(context.Background(), agent)
+			if err != nil {
+				t.Fatalf("resolveModels failed: %v", err)
+			}
+			if got := strings.Join(models.Names, ","); got != tt.expectModels {
+				t.Errorf("Expected models %q, got %q", tt.expectModels, got)
+			}
+			if got := strings.Join(models.Roles, ","); got != tt.expectRoles {
+				t.Errorf("Expected roles %q, got %q", tt.expectRoles, got)
+			}
+			if len(models.URLs) != len(models.Roles) {
+				t.Errorf("Expected URLs aligned with roles, got %v and %v", models.URLs, models.Roles)
+			}
+
+			unreachable := meta.IsStatusConditionTrue(agent.Status.Conditions, AllModelsUnreachableCondition)
+			if unreachable != tt.expectUnreachable {
+				t.Errorf("Expected %s=%v, got conditions %+v", AllModelsUnreachableCondition, tt.expectUnreachable, agent.Status.Conditions)
+			}
+			events := drainEvents(reconciler.Recorder.(*record.FakeRecorder))
+			if hasEvent(events, AllModelsUnreachableCondition) != tt.expectUnreachable {
+				t.Errorf("Expected %s event=%v, got %v", AllModelsUnreachableCondition, tt.expectUnreachable, events)
+			}
+		})
+	}
+}
+
+func TestResolveModels_AllModelsUnreachableRecovers(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "routed-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ModelRefs:    []langopv1alpha1.ModelReference{{Name: "gpt"}},
+			FeatureGates: map[string]bool{langopv1alpha1.FeatureGateModelHealthRouting: true},
+		},
+	}
+	model := newHealthCheckedModel("gpt", "gpt-4o", metav1.ConditionFalse)
+	reconciler, fakeClient := newForceWorkloadReconciler(t, model)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := reconciler.resolveModels(ctx, agent); err != nil {
+			t.Fatalf("resolveModels failed: %v", err)
+		}
+	}
+	if !meta.IsStatusConditionTrue(agent.Status.Conditions, AllModelsUnreachableCondition) {
+		t.Fatalf("Expected %s condition, got %+v", AllModelsUnreachableCondition, agent.Status.Conditions)
+	}
+	if events := drainEvents(recorder); len(events) != 1 {
+		t.Errorf("Expected a single warning while the models stay unreachable, got %v", events)
+	}
+
+	SetCondition(&model.Status.Conditions, ModelReachableCondition, metav1.ConditionTrue, "HealthCheck", "", 0)
+	if err := fakeClient.Update(ctx, model); err != nil {
+		t.Fatalf("Failed to update model: %v", err)
+	}
+	models, err := reconciler.resolveModels(ctx, agent)
+	if err != nil {
+		t.Fatalf("resolveModels failed: %v", err)
+	}
+	if len(models.URLs) != 1 {
+		t.Errorf("Expected the recovered model to be routed, got %v", models.URLs)
+	}
+	if meta.FindStatusCondition(agent.Status.Conditions, AllModelsUnreachableCondition) != nil {
+		t.Errorf("Expected %s to be removed after recovery", AllModelsUnreachableCondition)
+	}
+}
+
+func TestAgentsForModel(t *testing.T) {
+	model := newHealthCheckedModel("gpt", "gpt-4o", metav1.ConditionTrue)
+	referencing := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "referencing", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{ModelRefs: []langopv1alpha1.ModelReference{{Name: "gpt"}}},
+	}
+	crossNamespace := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "cross-namespace", Namespace: "agents"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{ModelRefs: []langopv1alpha1.ModelReference{{Name: "gpt", Namespace: "default"}}},
+	}
+	otherNamespace := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "other-namespace", Namespace: "agents"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{ModelRefs: []langopv1alpha1.ModelReference{{Name: "gpt"}}},
+	}
+	reconciler, _ := newForceWorkloadReconciler(t, referencing, crossNamespace, otherNamespace)
+
+	requests := reconciler.agentsForModel(context.Background(), model)
+	var names []string
+	for _, req := range requests {
+		names = append(names, req.Name)
+	}
+	if strings.Join(names, ",") != "cross-namespace,referencing" && strings.Join(names, ",") != "referencing,cross-namespace" {
+		t.Errorf("Expected the referencing agents, got %v", names)
+	}
+}
+
+func TestModelReachabilityChanged(t *testing.T) {
+	reachable := newHealthCheckedModel("gpt", "gpt-4o", metav1.ConditionTrue)
+	unreachable := newHealthCheckedModel("gpt", "gpt-4o", metav1.ConditionFalse)
+	unchecked := newHealthCheckedModel("gpt", "gpt-4o", "")
+
+	tests := []struct {
+		name     string
+		old, new client.Object
+		expected bool
+	}{
+		{name: "became unreachable", old: reachable, new: unreachable, expected: true},
+		{name: "recovered", old: unreachable, new: reachable, expected: true},
+		{name: "still reachable", old: reachable, new: reachable},
+		{name: "first check succeeded", old: unchecked, new: reachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := modelReachabilityChanged().Update(event.UpdateEvent{ObjectOld: tt.old, ObjectNew: tt.new})
+			if got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
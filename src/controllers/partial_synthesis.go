@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+const (
+	// instructionSectionsAnnotation records the hashes of the instruction sections the code was synthesized from
+	instructionSectionsAnnotation = "langop.io/instruction-sections"
+	// taskSectionsAnnotation records which instruction sections each task of the code implements
+	taskSectionsAnnotation = "langop.io/task-sections"
+)
+
+// addTaskSectionAnnotations records the instruction sections behind code so a later instruction
+// change can be confined to the tasks it affects
+func addTaskSectionAnnotations(annotations map[string]string, instructions, code string) {
+	sections := synthesis.SplitInstructionSections(instructions)
+	hashes := make([]string, 0, len(sections))
+	for _, section := range sections {
+		hashes = append(hashes, synthesis.HashSection(section))
+	}
+
+	// Marshalling a map of string slices cannot fail
+	taskSections, _ := json.Marshal(synthesis.MapTasksToSections(code, sections))
+
+	annotations[instructionSectionsAnnotation] = strings.Join(hashes, ",")
+	annotations[taskSectionsAnnotation] = string(taskSections)
+}
+
+// planTaskRegeneration returns the tasks of the existing code affected by an instruction change
+// and the changed instruction sections, or ok=false when the agent must be fully re-synthesized
+func (r *LanguageAgentReconciler) planTaskRegeneration(agent *langopv1alpha1.LanguageAgent, existingCM *corev1.ConfigMap) (tasks []string, changed []string, ok bool) {
+	if !r.featureGateEnabled(agent, langopv1alpha1.FeatureGatePartialSynthesis) {
+		return nil, nil, false
+	}
+
+	previous := existingCM.Annotations[instructionSectionsAnnotation]
+	if previous == "" {
+		return nil, nil, false
+	}
+	var taskSections map[string][]string
+	if err := json.Unmarshal([]byte(existingCM.Annotations[taskSectionsAnnotation]), &taskSections); err != nil {
+		return nil, nil, false
+	}
+
+	return synthesis.PlanTaskRegeneration(existingCM.Data["agent.rb"], strings.Split(previous, ","),
+		taskSections, synthesis.SplitInstructionSections(r.synthesisInstructions(agent)))
+}
+
+// regenerateTasks regenerates only the given tasks of the existing code, preserving every other
+// task (including learned symbolic ones) as-is
+func (r *LanguageAgentReconciler) regenerateTasks(ctx context.Context, synthesizer synthesis.AgentSynthesizer, req synthesis.AgentSynthesisRequest, existingCode string, tasks, changed []string) (*synthesis.AgentSynthesisResponse, error) {
+	taskSynthesizer, ok := synthesizer.(synthesis.TaskSynthesizer)
+	if !ok {
+		return nil, fmt.Errorf("synthesizer does not support task regeneration")
+	}
+
+	return taskSynthesizer.SynthesizeTasks(ctx, synthesis.TaskSynthesisRequest{
+		AgentSynthesisRequest: req,
+		ExistingCode:          existingCode,
+		Tasks:                 tasks,
+		ChangedSections:       changed,
+	})
+}
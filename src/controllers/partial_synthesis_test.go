@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"strings"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestPlanTaskRegeneration(t *testing.T) {
+	code := `require 'language_operator'
+
+agent "news-digest" do
+  task :fetch_headlines do |inputs|
+    { headlines: inputs[:headlines] || [] }
+  end
+
+  task :summarize_headlines,
+    instructions: "summarize the headlines in three bullet points",
+    inputs: { headlines: 'array' },
+    outputs: { summary: 'string' }
+
+  main do |inputs|
+    news = execute_task(:fetch_headlines)
+    execute_task(:summarize_headlines, inputs: news)
+  end
+end`
+	instructions := "Fetch the latest technology headlines.\n\nSummarize the headlines in three bullet points."
+
+	annotations := map[string]string{}
+	addTaskSectionAnnotations(annotations, instructions, code)
+	existingCM := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Annotations: annotations},
+		Data:       map[string]string{"agent.rb": code},
+	}
+
+	tests := []struct {
+		name     string
+		gates    map[string]bool
+		expected []string
+	}{
+		{name: "regenerates only the changed task", expected: []string{"summarize_headlines"}},
+		{name: "gate disabled", gates: map[string]bool{langopv1alpha1.FeatureGatePartialSynthesis: false}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reconciler := &LanguageAgentReconciler{FeatureGates: tt.gates}
+			agent := &langopv1alpha1.LanguageAgent{
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Instructions: "Fetch the latest technology headlines.\n\nSummarize the headlines in five bullet points.",
+				},
+			}
+
+			tasks, _, ok := reconciler.planTaskRegeneration(agent, existingCM)
+			if ok != (len(tt.expected) > 0) {
+				t.Fatalf("Expected ok=%v, got %v", len(tt.expected) > 0, ok)
+			}
+			if strings.Join(tasks, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected tasks %v, got %v", tt.expected, tasks)
+			}
+		})
+	}
+}
+
+func TestPlanTaskRegeneration_WithoutSectionAnnotations(t *testing.T) {
+	// Code synthesized before sections were recorded is always fully re-synthesized
+	reconciler := &LanguageAgentReconciler{}
+	agent := &langopv1alpha1.LanguageAgent{
+		Spec: langopv1alpha1.LanguageAgentSpec{Instructions: "Summarize the news"},
+	}
+	existingCM := &corev1.ConfigMap{Data: map[string]string{"agent.rb": "agent 'a' do\nend"}}
+
+	if _, _, ok := reconciler.planTaskRegeneration(agent, existingCM); ok {
+		t.Error("Expected full re-synthesis without recorded instruction sections")
+	}
+}
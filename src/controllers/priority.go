@@ -0,0 +1,156 @@
+package controllers
+
+import (
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+)
+
+// PriorityLabel selects how urgently an agent is reconciled when the work queue is backed up.
+// It is read from the agent's labels, falling back to an annotation of the same name.
+const PriorityLabel = "langop.io/priority"
+
+// Reconcile priorities, from most to least urgent
+const (
+	PriorityHigh   = "high"
+	PriorityNormal = "normal"
+	PriorityLow    = "low"
+)
+
+const (
+	// priorityDeferInterval is how long a deferred reconcile waits before retrying
+	priorityDeferInterval = 500 * time.Millisecond
+	// priorityMaxDefer bounds how long a request can be deferred so low-priority agents aren't starved
+	priorityMaxDefer = 30 * time.Second
+)
+
+// priorityRank orders priorities so that a higher rank is reconciled first
+func priorityRank(priority string) int {
+	switch priority {
+	case PriorityHigh:
+		return 2
+	case PriorityLow:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// agentPriority returns the reconcile priority of an object, defaulting to normal
+func agentPriority(obj client.Object) string {
+	priority, ok := obj.GetLabels()[PriorityLabel]
+	if !ok {
+		priority = obj.GetAnnotations()[PriorityLabel]
+	}
+	switch priority {
+	case PriorityHigh, PriorityLow:
+		return priority
+	default:
+		return PriorityNormal
+	}
+}
+
+// ReconcilePrioritizer orders reconciles by agent priority. controller-runtime's work queue is
+// FIFO, so instead of reordering it the prioritizer tracks which requests are waiting and defers
+// a reconcile while a higher-priority request is still queued. Deferred requests go back on the
+// queue, letting high-priority agents take the worker slots during a backlog.
+type ReconcilePrioritizer struct {
+	mu sync.Mutex
+	// priorities remembers the last seen priority of each agent, so requests enqueued
+	// by owned resources inherit their agent's priority
+	priorities map[types.NamespacedName]int
+	// pending holds the rank of each request enqueued but not yet reconciled
+	pending map[types.NamespacedName]int
+	// deferredSince records when a request was first deferred
+	deferredSince map[types.NamespacedName]time.Time
+	now           func() time.Time
+}
+
+// NewReconcilePrioritizer creates an empty ReconcilePrioritizer
+func NewReconcilePrioritizer() *ReconcilePrioritizer {
+	return &ReconcilePrioritizer{
+		priorities:    make(map[types.NamespacedName]int),
+		pending:       make(map[types.NamespacedName]int),
+		deferredSince: make(map[types.NamespacedName]time.Time),
+		now:           time.Now,
+	}
+}
+
+// Observe records the priority of an agent
+func (p *ReconcilePrioritizer) Observe(obj client.Object) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.priorities[client.ObjectKeyFromObject(obj)] = priorityRank(agentPriority(obj))
+}
+
+// Forget drops all state for a deleted agent
+func (p *ReconcilePrioritizer) Forget(key types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	delete(p.priorities, key)
+	delete(p.pending, key)
+	delete(p.deferredSince, key)
+}
+
+// Enqueued marks a request as waiting in the work queue
+func (p *ReconcilePrioritizer) Enqueued(key types.NamespacedName) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	rank, ok := p.priorities[key]
+	if !ok {
+		rank = priorityRank(PriorityNormal)
+	}
+	p.pending[key] = rank
+}
+
+// Defer reports whether a request should step aside for a higher-priority one that is still
+// queued. When it returns false the request is considered started and leaves the pending set.
+func (p *ReconcilePrioritizer) Defer(key types.NamespacedName) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	rank, ok := p.pending[key]
+	if !ok {
+		rank, ok = p.priorities[key]
+		if !ok {
+			rank = priorityRank(PriorityNormal)
+		}
+	}
+
+	since, deferred := p.deferredSince[key]
+	if !deferred || p.now().Sub(since) < priorityMaxDefer {
+		for other, otherRank := range p.pending {
+			if other != key && otherRank > rank {
+				if !deferred {
+					p.deferredSince[key] = p.now()
+				}
+				p.pending[key] = rank
+				return true
+			}
+		}
+	}
+
+	delete(p.pending, key)
+	delete(p.deferredSince, key)
+	return false
+}
+
+// Predicate records the priority of each agent event and marks the request it enqueues as
+// pending. It never filters events.
+func (p *ReconcilePrioritizer) Predicate() predicate.Predicate {
+	enqueued := func(obj client.Object) bool {
+		p.Observe(obj)
+		p.Enqueued(client.ObjectKeyFromObject(obj))
+		return true
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return enqueued(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return enqueued(e.ObjectNew) },
+		DeleteFunc:  func(e event.DeleteEvent) bool { return enqueued(e.Object) },
+		GenericFunc: func(e event.GenericEvent) bool { return enqueued(e.Object) },
+	}
+}
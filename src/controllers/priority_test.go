@@ -0,0 +1,133 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/util/workqueue"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func newPriorityAgent(name string, labels, annotations map[string]string) *langopv1alpha1.LanguageAgent {
+	return &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels, Annotations: annotations},
+	}
+}
+
+func TestAgentPriority(t *testing.T) {
+	tests := []struct {
+		name        string
+		labels      map[string]string
+		annotations map[string]string
+		expected    string
+	}{
+		{name: "unlabeled", expected: PriorityNormal},
+		{name: "high label", labels: map[string]string{PriorityLabel: "high"}, expected: PriorityHigh},
+		{name: "low annotation", annotations: map[string]string{PriorityLabel: "low"}, expected: PriorityLow},
+		{name: "label wins over annotation", labels: map[string]string{PriorityLabel: "high"}, annotations: map[string]string{PriorityLabel: "low"}, expected: PriorityHigh},
+		{name: "unknown value", labels: map[string]string{PriorityLabel: "urgent"}, expected: PriorityNormal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := agentPriority(newPriorityAgent("agent", tt.labels, tt.annotations)); got != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+// TestReconcilePrioritizer_DequeuesHighPriorityFirst floods a FIFO work queue with low and normal
+// priority agents ahead of a high-priority one and checks the high-priority agent is reconciled first
+func TestReconcilePrioritizer_DequeuesHighPriorityFirst(t *testing.T) {
+	prioritizer := NewReconcilePrioritizer()
+	pred := prioritizer.Predicate()
+	queue := workqueue.New()
+	defer queue.ShutDown()
+
+	agents := []*langopv1alpha1.LanguageAgent{
+		newPriorityAgent("bulk-1", map[string]string{PriorityLabel: "low"}, nil),
+		newPriorityAgent("bulk-2", map[string]string{PriorityLabel: "low"}, nil),
+		newPriorityAgent("regular", nil, nil),
+		newPriorityAgent("bulk-3", map[string]string{PriorityLabel: "low"}, nil),
+		newPriorityAgent("critical", map[string]string{PriorityLabel: "high"}, nil),
+	}
+	for _, agent := range agents {
+		if pred.Create(event.CreateEvent{Object: agent}) {
+			queue.Add(types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace})
+		}
+	}
+
+	var order []string
+	for len(order) < len(agents) {
+		item, _ := queue.Get()
+		key := item.(types.NamespacedName)
+		if prioritizer.Defer(key) {
+			queue.Done(item)
+			queue.Add(item)
+			continue
+		}
+		order = append(order, key.Name)
+		queue.Done(item)
+	}
+
+	if order[0] != "critical" || order[1] != "regular" {
+		t.Errorf("Expected critical then regular to be reconciled first, got %v", order)
+	}
+}
+
+func TestReconcilePrioritizer_BoundsDeferral(t *testing.T) {
+	prioritizer := NewReconcilePrioritizer()
+	now := time.Now()
+	prioritizer.now = func() time.Time { return now }
+
+	high := newPriorityAgent("critical", map[string]string{PriorityLabel: "high"}, nil)
+	low := newPriorityAgent("bulk", map[string]string{PriorityLabel: "low"}, nil)
+	for _, agent := range []*langopv1alpha1.LanguageAgent{high, low} {
+		prioritizer.Predicate().Create(event.CreateEvent{Object: agent})
+	}
+
+	lowKey := types.NamespacedName{Name: low.Name, Namespace: low.Namespace}
+	if !prioritizer.Defer(lowKey) {
+		t.Fatal("Expected low-priority agent to be deferred while a high-priority one is queued")
+	}
+
+	now = now.Add(priorityMaxDefer)
+	if prioritizer.Defer(lowKey) {
+		t.Error("Expected low-priority agent to run once it has been deferred for the maximum time")
+	}
+}
+
+func TestLanguageAgentReconciler_DefersLowPriority(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	high := newPriorityAgent("critical", map[string]string{PriorityLabel: "high"}, nil)
+	low := newPriorityAgent("bulk", map[string]string{PriorityLabel: "low"}, nil)
+
+	prioritizer := NewReconcilePrioritizer()
+	for _, agent := range []*langopv1alpha1.LanguageAgent{high, low} {
+		prioritizer.Predicate().Create(event.CreateEvent{Object: agent})
+	}
+
+	reconciler := &LanguageAgentReconciler{
+		Client:   fake.NewClientBuilder().WithScheme(scheme).WithObjects(high, low).Build(),
+		Scheme:   scheme,
+		Log:      logr.Discard(),
+		Priority: prioritizer,
+	}
+
+	result, err := reconciler.Reconcile(context.Background(), ctrl.Request{NamespacedName: types.NamespacedName{Name: low.Name, Namespace: low.Namespace}})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if result.RequeueAfter != priorityDeferInterval {
+		t.Errorf("Expected low-priority reconcile to be requeued after %s, got %+v", priorityDeferInterval, result)
+	}
+}
@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// quotaBackoffCondition is True while an agent's synthesis is paused after repeated quota exhaustion
+const quotaBackoffCondition = "SynthesisQuotaBackoff"
+
+// defaultQuotaBackoffInterval is how long an agent in quota backoff waits before synthesis is retried
+const defaultQuotaBackoffInterval = 30 * time.Minute
+
+// quotaBackoffState tracks the consecutive quota-exhausted reconciles of one agent
+type quotaBackoffState struct {
+	exhaustions int
+	generation  int64
+	until       time.Time
+}
+
+// quotaBackoffTracker pauses synthesis of agents that keep hitting the synthesis rate limit or
+// quota. Without it such agents retry on every reconcile, each refused with another QuotaExceeded
+// event. Unlike synthesis failures, retrying can't succeed before the quota window resets, so after
+// threshold consecutive refusals the agent waits out interval instead.
+type quotaBackoffTracker struct {
+	mu        sync.Mutex
+	threshold int
+	interval  time.Duration
+	agents    map[types.NamespacedName]*quotaBackoffState
+	now       func() time.Time
+}
+
+// newQuotaBackoffTracker creates a tracker backing off after threshold consecutive refusals;
+// zero or less disables backoff
+func newQuotaBackoffTracker(threshold int, interval time.Duration) *quotaBackoffTracker {
+	if interval <= 0 {
+		interval = defaultQuotaBackoffInterval
+	}
+	return &quotaBackoffTracker{
+		threshold: threshold,
+		interval:  interval,
+		agents:    make(map[types.NamespacedName]*quotaBackoffState),
+		now:       time.Now,
+	}
+}
+
+// Remaining returns how long the agent's synthesis stays paused. A spec change ends the backoff
+// early, since the new spec may need less quota or none at all.
+func (t *quotaBackoffTracker) Remaining(key types.NamespacedName, generation int64) time.Duration {
+	if t == nil || t.threshold <= 0 {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.agents[key]
+	if !ok || state.until.IsZero() {
+		return 0
+	}
+	if state.generation != generation {
+		delete(t.agents, key)
+		return 0
+	}
+	remaining := state.until.Sub(t.now())
+	if remaining <= 0 {
+		// Retry once; another refusal backs off again right away
+		state.until = time.Time{}
+		state.exhaustions = t.threshold - 1
+		return 0
+	}
+	return remaining
+}
+
+// Exhausted records a reconcile refused by the synthesis rate limit or quota and returns how long
+// the agent backs off, or zero while it is still below the threshold
+func (t *quotaBackoffTracker) Exhausted(key types.NamespacedName, generation int64) time.Duration {
+	if t == nil || t.threshold <= 0 {
+		return 0
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	state, ok := t.agents[key]
+	if !ok || state.generation != generation {
+		state = &quotaBackoffState{generation: generation}
+		t.agents[key] = state
+	}
+	state.exhaustions++
+	if state.exhaustions < t.threshold {
+		return 0
+	}
+	state.until = t.now().Add(t.interval)
+	return t.interval
+}
+
+// Forget resets the agent's count, after a reconcile that wasn't refused or when it is deleted
+func (t *quotaBackoffTracker) Forget(key types.NamespacedName) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.agents, key)
+}
+
+func (r *LanguageAgentReconciler) getQuotaBackoff() *quotaBackoffTracker {
+	r.quotaBackoffOnce.Do(func() {
+		r.quotaBackoff = newQuotaBackoffTracker(r.QuotaBackoffThreshold, r.QuotaBackoffInterval)
+	})
+	return r.quotaBackoff
+}
+
+// quotaBackoffRemaining returns how long the agent's synthesis stays paused by quota backoff
+func (r *LanguageAgentReconciler) quotaBackoffRemaining(agent *langopv1alpha1.LanguageAgent) time.Duration {
+	return r.getQuotaBackoff().Remaining(types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent.Generation)
+}
+
+// observeSynthesisQuota records the outcome of a synthesis reconcile for quota backoff. When err
+// is the agent's threshold-th consecutive quota refusal, it sets the SynthesisQuotaBackoff
+// condition and returns how long to wait before retrying. The condition stays set through the
+// retry after the backoff, so an agent still refused backs off again without another event.
+func (r *LanguageAgentReconciler) observeSynthesisQuota(agent *langopv1alpha1.LanguageAgent, err error) time.Duration {
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+	if !synthesis.IsQuotaExceeded(err) {
+		r.getQuotaBackoff().Forget(key)
+		meta.RemoveStatusCondition(&agent.Status.Conditions, quotaBackoffCondition)
+		return 0
+	}
+
+	backoff := r.getQuotaBackoff().Exhausted(key, agent.Generation)
+	if backoff <= 0 {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, quotaBackoffCondition)
+		return 0
+	}
+	message := fmt.Sprintf("Synthesis was refused by the synthesis quota %d times in a row; retrying in %s or when the spec changes",
+		r.QuotaBackoffThreshold, backoff)
+	if SetCondition(&agent.Status.Conditions, quotaBackoffCondition, metav1.ConditionTrue, "QuotaExhausted", message, agent.Generation) && r.Recorder != nil {
+		r.Recorder.Event(agent, corev1.EventTypeWarning, "SynthesisQuotaBackoff", message)
+	}
+	return backoff
+}
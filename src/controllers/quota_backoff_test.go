@@ -0,0 +1,115 @@
+package controllers
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+func TestLanguageAgentController_QuotaBackoff(t *testing.T) {
+	reconciler, _ := newForceWorkloadReconciler(t)
+	reconciler.QuotaBackoffThreshold = 3
+	reconciler.QuotaBackoffInterval = time.Hour
+	now := time.Now()
+	reconciler.getQuotaBackoff().now = func() time.Time { return now }
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", Generation: 1},
+	}
+	quotaErr := &synthesis.QuotaExceededError{Err: errors.New("daily attempt quota exceeded")}
+
+	// Refusals below the threshold keep retrying as before
+	for i := 0; i < 2; i++ {
+		if backoff := reconciler.observeSynthesisQuota(agent, quotaErr); backoff != 0 {
+			t.Fatalf("Expected no backoff after %d refusals, got %s", i+1, backoff)
+		}
+	}
+	if meta.FindStatusCondition(agent.Status.Conditions, quotaBackoffCondition) != nil {
+		t.Error("Expected no SynthesisQuotaBackoff condition below the threshold")
+	}
+
+	if backoff := reconciler.observeSynthesisQuota(agent, quotaErr); backoff != time.Hour {
+		t.Fatalf("Expected a 1h backoff after 3 refusals, got %s", backoff)
+	}
+	if !meta.IsStatusConditionTrue(agent.Status.Conditions, quotaBackoffCondition) {
+		t.Errorf("Expected SynthesisQuotaBackoff=True, got %v", agent.Status.Conditions)
+	}
+	if events := drainEvents(recorder); !hasEvent(events, "SynthesisQuotaBackoff") {
+		t.Errorf("Expected a SynthesisQuotaBackoff event, got %v", events)
+	}
+
+	now = now.Add(20 * time.Minute)
+	if wait := reconciler.quotaBackoffRemaining(agent); wait != 40*time.Minute {
+		t.Errorf("Expected synthesis to stay paused for 40m, got %s", wait)
+	}
+
+	// Once the backoff is over, a single refusal backs off again without another event
+	now = now.Add(time.Hour)
+	if wait := reconciler.quotaBackoffRemaining(agent); wait != 0 {
+		t.Fatalf("Expected the backoff to be over, got %s", wait)
+	}
+	if backoff := reconciler.observeSynthesisQuota(agent, quotaErr); backoff != time.Hour {
+		t.Errorf("Expected a still exhausted quota to back off again, got %s", backoff)
+	}
+	if events := drainEvents(recorder); len(events) > 0 {
+		t.Errorf("Expected no repeated event, got %v", events)
+	}
+
+	// The quota window reset: synthesis succeeds and the backoff is cleared
+	now = now.Add(2 * time.Hour)
+	if wait := reconciler.quotaBackoffRemaining(agent); wait != 0 {
+		t.Fatalf("Expected the backoff to be over, got %s", wait)
+	}
+	if backoff := reconciler.observeSynthesisQuota(agent, nil); backoff != 0 {
+		t.Errorf("Expected no backoff after a successful synthesis, got %s", backoff)
+	}
+	if meta.FindStatusCondition(agent.Status.Conditions, quotaBackoffCondition) != nil {
+		t.Error("Expected the SynthesisQuotaBackoff condition to be cleared")
+	}
+	if backoff := reconciler.observeSynthesisQuota(agent, quotaErr); backoff != 0 {
+		t.Errorf("Expected the refusal count to start over, got backoff %s", backoff)
+	}
+}
+
+func TestLanguageAgentController_QuotaBackoffEndsOnSpecChange(t *testing.T) {
+	reconciler, _ := newForceWorkloadReconciler(t)
+	reconciler.QuotaBackoffThreshold = 1
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", Generation: 1},
+	}
+	quotaErr := &synthesis.QuotaExceededError{Err: errors.New("rate limit exceeded")}
+
+	if backoff := reconciler.observeSynthesisQuota(agent, quotaErr); backoff != defaultQuotaBackoffInterval {
+		t.Fatalf("Expected the default backoff interval, got %s", backoff)
+	}
+	if wait := reconciler.quotaBackoffRemaining(agent); wait <= 0 {
+		t.Fatal("Expected synthesis to be paused")
+	}
+
+	agent.Generation = 2
+	if wait := reconciler.quotaBackoffRemaining(agent); wait != 0 {
+		t.Errorf("Expected a spec change to end the backoff, got %s", wait)
+	}
+}
+
+func TestLanguageAgentController_QuotaBackoffDisabled(t *testing.T) {
+	reconciler, _ := newForceWorkloadReconciler(t)
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default", Generation: 1},
+	}
+	quotaErr := &synthesis.QuotaExceededError{Err: errors.New("rate limit exceeded")}
+
+	for i := 0; i < 10; i++ {
+		if backoff := reconciler.observeSynthesisQuota(agent, quotaErr); backoff != 0 {
+			t.Fatalf("Expected no backoff without a threshold, got %s", backoff)
+		}
+	}
+}
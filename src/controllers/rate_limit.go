@@ -0,0 +1,258 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+// RateLimitedCondition reports whether a LanguageModel has used up one of its spec.rateLimits,
+// so agents referencing it can surface the degradation
+const RateLimitedCondition = "RateLimited"
+
+// UnlimitedRateLimit is reported by GetRemainingRateLimit for a dimension without a limit
+const UnlimitedRateLimit int64 = -1
+
+const (
+	// rateLimitWindow is the window rate limits are expressed over
+	rateLimitWindow = time.Minute
+
+	// modelRequestsMetric and modelTokensMetric are the counters model proxies report to telemetry
+	modelRequestsMetric = "langop_model_requests_total"
+	modelTokensMetric   = "langop_model_tokens_total"
+
+	// Names of the token buckets tracking the request and token limits
+	requestsBucket = "requests"
+	tokensBucket   = "tokens"
+)
+
+// TokenBucket holds up to Capacity tokens and refills at RefillPerSecond. A per-minute rate
+// limit is a bucket whose capacity is the limit, refilled over one minute.
+type TokenBucket struct {
+	Name            string  `json:"name"`
+	Capacity        int64   `json:"capacity"`
+	RefillPerSecond float64 `json:"refillPerSecond"`
+}
+
+// rateLimitBuckets converts a model's per-minute request and token limits into token buckets
+func rateLimitBuckets(limits *langopv1alpha1.RateLimitSpec) []TokenBucket {
+	if limits == nil {
+		return nil
+	}
+	var buckets []TokenBucket
+	for _, limit := range []struct {
+		name  string
+		value *int32
+	}{
+		{requestsBucket, limits.RequestsPerMinute},
+		{tokensBucket, limits.TokensPerMinute},
+	} {
+		if limit.value == nil || *limit.value <= 0 {
+			continue
+		}
+		buckets = append(buckets, TokenBucket{
+			Name:            limit.name,
+			Capacity:        int64(*limit.value),
+			RefillPerSecond: float64(*limit.value) / rateLimitWindow.Seconds(),
+		})
+	}
+	return buckets
+}
+
+// Remaining returns the tokens left in the bucket after it drained used tokens over the last
+// window and then refilled for elapsed
+func (b TokenBucket) Remaining(used int64, elapsed time.Duration) int64 {
+	level := float64(b.Capacity - used)
+	if level < 0 {
+		level = 0
+	}
+	if elapsed > 0 {
+		level += b.RefillPerSecond * elapsed.Seconds()
+	}
+	return min(int64(level), b.Capacity)
+}
+
+// RefillDuration returns how long the bucket takes to fill up from remaining tokens
+func (b TokenBucket) RefillDuration(remaining int64) time.Duration {
+	if remaining >= b.Capacity || b.RefillPerSecond <= 0 {
+		return 0
+	}
+	seconds := float64(b.Capacity-remaining) / b.RefillPerSecond
+	return time.Duration(math.Ceil(seconds)) * time.Second
+}
+
+// usage returns the bucket's share of a usage observation
+func (b TokenBucket) usage(usage langopv1alpha1.RateLimitUsage) int64 {
+	if b.Name == tokensBucket {
+		return usage.Tokens
+	}
+	return usage.Requests
+}
+
+// RateLimitTracker observes how much of its rate limits each LanguageModel has used from the
+// request and token counters model proxies report to telemetry
+type RateLimitTracker struct {
+	Telemetry telemetry.TelemetryAdapter
+
+	now func() time.Time
+}
+
+// NewRateLimitTracker creates a tracker reading usage from adapter
+func NewRateLimitTracker(adapter telemetry.TelemetryAdapter) *RateLimitTracker {
+	return &RateLimitTracker{Telemetry: adapter, now: time.Now}
+}
+
+// observeUsage returns the requests and tokens the model served over the rate limit window ending at now
+func (t *RateLimitTracker) observeUsage(ctx context.Context, model *langopv1alpha1.LanguageModel, now time.Time) (langopv1alpha1.RateLimitUsage, error) {
+	var usage langopv1alpha1.RateLimitUsage
+	for _, counter := range []struct {
+		metric string
+		value  *int64
+	}{
+		{modelRequestsMetric, &usage.Requests},
+		{modelTokensMetric, &usage.Tokens},
+	} {
+		points, err := t.Telemetry.QueryMetrics(ctx, telemetry.MetricFilter{
+			MetricName:  counter.metric,
+			Labels:      map[string]string{"model": model.Name, "namespace": model.Namespace},
+			TimeRange:   telemetry.TimeRange{Start: now.Add(-rateLimitWindow), End: now},
+			Aggregation: "sum",
+		})
+		if err != nil {
+			return usage, fmt.Errorf("failed to query %s: %w", counter.metric, err)
+		}
+		*counter.value = counterIncrease(points)
+	}
+	return usage, nil
+}
+
+// counterIncrease returns how much a counter grew across points ordered newest first. A counter
+// that went down was reset, so its newest value is the whole increase.
+func counterIncrease(points []telemetry.MetricPoint) int64 {
+	if len(points) == 0 {
+		return 0
+	}
+	newest, oldest := points[0].Value, points[len(points)-1].Value
+	if len(points) == 1 || newest < oldest {
+		return int64(newest)
+	}
+	return int64(newest - oldest)
+}
+
+// stillIdle reports whether the model saw no usage now and at its previous observation. Full
+// buckets don't depend on when they were observed, so the previous observation stays valid.
+func stillIdle(previous *langopv1alpha1.RateLimitStatus, usage langopv1alpha1.RateLimitUsage) bool {
+	return usage == (langopv1alpha1.RateLimitUsage{}) && previous != nil && previous.ObservedTime != nil &&
+		previous.CurrentUsage == usage
+}
+
+// Observe records the model's usage over the last minute in status.rateLimit and updates the
+// RateLimited condition. Models without rate limits have both cleared. An idle model keeps its
+// previous idle observation, so its status isn't rewritten every minute.
+func (t *RateLimitTracker) Observe(ctx context.Context, model *langopv1alpha1.LanguageModel) error {
+	buckets := rateLimitBuckets(model.Spec.RateLimits)
+	if len(buckets) == 0 {
+		model.Status.RateLimit = nil
+		meta.RemoveStatusCondition(&model.Status.Conditions, RateLimitedCondition)
+		return nil
+	}
+	if t.Telemetry == nil || !t.Telemetry.Available() {
+		SetCondition(&model.Status.Conditions, RateLimitedCondition, metav1.ConditionUnknown, "TelemetryUnavailable",
+			"Rate limit usage can't be observed without a telemetry backend", model.Generation)
+		return nil
+	}
+
+	now := t.now()
+	usage, err := t.observeUsage(ctx, model, now)
+	if err != nil {
+		SetCondition(&model.Status.Conditions, RateLimitedCondition, metav1.ConditionUnknown, "UsageQueryFailed", err.Error(), model.Generation)
+		return err
+	}
+
+	var reset time.Duration
+	var exhausted *TokenBucket
+	for i, bucket := range buckets {
+		remaining := bucket.Remaining(bucket.usage(usage), 0)
+		reset = max(reset, bucket.RefillDuration(remaining))
+		if remaining == 0 && exhausted == nil {
+			exhausted = &buckets[i]
+		}
+	}
+	if !stillIdle(model.Status.RateLimit, usage) {
+		observed := metav1.NewTime(now)
+		resetTime := metav1.NewTime(now.Add(reset))
+		model.Status.RateLimit = &langopv1alpha1.RateLimitStatus{
+			CurrentUsage: usage,
+			ObservedTime: &observed,
+			ResetTime:    &resetTime,
+		}
+	}
+
+	if exhausted != nil {
+		SetCondition(&model.Status.Conditions, RateLimitedCondition, metav1.ConditionTrue, "RateLimitExceeded",
+			fmt.Sprintf("Used %d of %d %s in the last minute", exhausted.usage(usage), exhausted.Capacity, exhausted.Name), model.Generation)
+	} else {
+		SetCondition(&model.Status.Conditions, RateLimitedCondition, metav1.ConditionFalse, "WithinLimits",
+			"Usage in the last minute is within the model's rate limits", model.Generation)
+	}
+	return nil
+}
+
+// GetRemainingRateLimit returns how many requests and tokens the model can serve right now,
+// based on its last observed usage and the buckets' refill since. Dimensions without a limit
+// report UnlimitedRateLimit; a model whose usage was never observed has full buckets.
+func (t *RateLimitTracker) GetRemainingRateLimit(model *langopv1alpha1.LanguageModel) (requests, tokens int64) {
+	requests, tokens = UnlimitedRateLimit, UnlimitedRateLimit
+
+	var usage langopv1alpha1.RateLimitUsage
+	var elapsed time.Duration
+	if status := model.Status.RateLimit; status != nil && status.ObservedTime != nil {
+		usage = status.CurrentUsage
+		elapsed = t.now().Sub(status.ObservedTime.Time)
+	}
+	for _, bucket := range rateLimitBuckets(model.Spec.RateLimits) {
+		remaining := bucket.Remaining(bucket.usage(usage), elapsed)
+		if bucket.Name == tokensBucket {
+			tokens = remaining
+		} else {
+			requests = remaining
+		}
+	}
+	return requests, tokens
+}
+
+// reconcileRateLimit observes the model's rate limit usage and returns how long until it is
+// observed again (zero when rate limits aren't tracked)
+func (r *LanguageModelReconciler) reconcileRateLimit(ctx context.Context, model *langopv1alpha1.LanguageModel) time.Duration {
+	if r.RateLimits == nil {
+		return 0
+	}
+
+	wasLimited := meta.IsStatusConditionTrue(model.Status.Conditions, RateLimitedCondition)
+	if err := r.RateLimits.Observe(ctx, model); err != nil {
+		log.FromContext(ctx).Info("Failed to observe model rate limit usage", "error", err.Error())
+	}
+	if len(rateLimitBuckets(model.Spec.RateLimits)) == 0 {
+		return 0
+	}
+
+	if r.Recorder != nil {
+		switch {
+		case !wasLimited && meta.IsStatusConditionTrue(model.Status.Conditions, RateLimitedCondition):
+			condition := meta.FindStatusCondition(model.Status.Conditions, RateLimitedCondition)
+			r.Recorder.Eventf(model, corev1.EventTypeWarning, "RateLimited", "Model is rate limited: %s", condition.Message)
+		case wasLimited && meta.IsStatusConditionFalse(model.Status.Conditions, RateLimitedCondition):
+			r.Recorder.Event(model, corev1.EventTypeNormal, "RateLimitRecovered", "Model usage is back within its rate limits")
+		}
+	}
+	return rateLimitWindow
+}
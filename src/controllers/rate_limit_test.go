@@ -0,0 +1,179 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+// counterTelemetry serves the counter points configured for each metric name
+type counterTelemetry struct {
+	telemetry.MockAdapter
+	counters map[string][]telemetry.MetricPoint
+}
+
+func (c *counterTelemetry) QueryMetrics(_ context.Context, filter telemetry.MetricFilter) ([]telemetry.MetricPoint, error) {
+	return c.counters[filter.MetricName], nil
+}
+
+// counterPoints returns counter points ordered newest first
+func counterPoints(values ...float64) []telemetry.MetricPoint {
+	points := make([]telemetry.MetricPoint, len(values))
+	for i, value := range values {
+		points[i] = telemetry.MetricPoint{Value: value}
+	}
+	return points
+}
+
+func int32Ptr(v int32) *int32 { return &v }
+
+func TestTokenBucket(t *testing.T) {
+	buckets := rateLimitBuckets(&langopv1alpha1.RateLimitSpec{
+		RequestsPerMinute: int32Ptr(60),
+		TokensPerMinute:   int32Ptr(12000),
+	})
+	require.Len(t, buckets, 2)
+	requests, tokens := buckets[0], buckets[1]
+	assert.Equal(t, TokenBucket{Name: "requests", Capacity: 60, RefillPerSecond: 1}, requests)
+	assert.Equal(t, TokenBucket{Name: "tokens", Capacity: 12000, RefillPerSecond: 200}, tokens)
+
+	tests := []struct {
+		name            string
+		used            int64
+		elapsed         time.Duration
+		expectRemaining int64
+		expectRefill    time.Duration
+	}{
+		{name: "unused bucket is full", used: 0, expectRemaining: 60, expectRefill: 0},
+		{name: "partially drained", used: 45, expectRemaining: 15, expectRefill: 45 * time.Second},
+		{name: "exhausted", used: 60, expectRemaining: 0, expectRefill: time.Minute},
+		{name: "overdrawn bucket doesn't go negative", used: 90, expectRemaining: 0, expectRefill: time.Minute},
+		{name: "refills over time", used: 60, elapsed: 20 * time.Second, expectRemaining: 20, expectRefill: 40 * time.Second},
+		{name: "refill is capped at capacity", used: 10, elapsed: time.Hour, expectRemaining: 60, expectRefill: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining := requests.Remaining(tt.used, tt.elapsed)
+			assert.Equal(t, tt.expectRemaining, remaining)
+			assert.Equal(t, tt.expectRefill, requests.RefillDuration(remaining))
+		})
+	}
+
+	assert.Empty(t, rateLimitBuckets(nil))
+	assert.Empty(t, rateLimitBuckets(&langopv1alpha1.RateLimitSpec{ConcurrentRequests: int32Ptr(4)}))
+}
+
+func TestCounterIncrease(t *testing.T) {
+	assert.Equal(t, int64(0), counterIncrease(nil))
+	assert.Equal(t, int64(7), counterIncrease(counterPoints(7)))
+	assert.Equal(t, int64(30), counterIncrease(counterPoints(130, 115, 100)))
+	// The counter was reset within the window
+	assert.Equal(t, int64(12), counterIncrease(counterPoints(12, 400)))
+}
+
+func TestLanguageModelController_RateLimitTransitions(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpt-4", Namespace: "default", Generation: 1},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:   "openai",
+			ModelName:  "gpt-4",
+			RateLimits: &langopv1alpha1.RateLimitSpec{RequestsPerMinute: int32Ptr(100), TokensPerMinute: int32Ptr(12000)},
+		},
+	}
+	adapter := &counterTelemetry{MockAdapter: telemetry.MockAdapter{AvailableReturn: true}}
+	tracker := NewRateLimitTracker(adapter)
+	tracker.now = func() time.Time { return now }
+	recorder := record.NewFakeRecorder(10)
+	r := &LanguageModelReconciler{Recorder: recorder, RateLimits: tracker}
+	ctx := context.Background()
+
+	// Within limits
+	adapter.counters = map[string][]telemetry.MetricPoint{
+		modelRequestsMetric: counterPoints(540, 500),
+		modelTokensMetric:   counterPoints(9000, 5000),
+	}
+	assert.Equal(t, time.Minute, r.reconcileRateLimit(ctx, model))
+	assert.True(t, meta.IsStatusConditionFalse(model.Status.Conditions, RateLimitedCondition))
+	require.NotNil(t, model.Status.RateLimit)
+	assert.Equal(t, langopv1alpha1.RateLimitUsage{Requests: 40, Tokens: 4000}, model.Status.RateLimit.CurrentUsage)
+	// The requests bucket refills in 24s, the tokens bucket in 20s
+	assert.Equal(t, now.Add(24*time.Second), model.Status.RateLimit.ResetTime.Time)
+	assert.Empty(t, drainEvents(recorder))
+
+	requests, tokens := tracker.GetRemainingRateLimit(model)
+	assert.Equal(t, int64(60), requests)
+	assert.Equal(t, int64(8000), tokens)
+
+	// Token limit used up
+	adapter.counters[modelTokensMetric] = counterPoints(17000, 5000)
+	r.reconcileRateLimit(ctx, model)
+	condition := meta.FindStatusCondition(model.Status.Conditions, RateLimitedCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionTrue, condition.Status)
+	assert.Equal(t, "RateLimitExceeded", condition.Reason)
+	assert.Contains(t, condition.Message, "12000 of 12000 tokens")
+	events := drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.True(t, strings.Contains(events[0], "RateLimited"))
+
+	// The buckets refill as time passes after the observation
+	tracker.now = func() time.Time { return now.Add(30 * time.Second) }
+	requests, tokens = tracker.GetRemainingRateLimit(model)
+	assert.Equal(t, int64(100), requests)
+	assert.Equal(t, int64(6000), tokens)
+
+	// Still limited: no repeated event
+	r.reconcileRateLimit(ctx, model)
+	assert.Empty(t, drainEvents(recorder))
+
+	// Back within limits
+	adapter.counters[modelTokensMetric] = counterPoints(100)
+	r.reconcileRateLimit(ctx, model)
+	assert.True(t, meta.IsStatusConditionFalse(model.Status.Conditions, RateLimitedCondition))
+	events = drainEvents(recorder)
+	require.Len(t, events, 1)
+	assert.Contains(t, events[0], "RateLimitRecovered")
+
+	// An idle model keeps its first idle observation rather than rewriting status every minute
+	adapter.counters = map[string][]telemetry.MetricPoint{
+		modelRequestsMetric: counterPoints(540, 540),
+		modelTokensMetric:   counterPoints(17000, 17000),
+	}
+	tracker.now = func() time.Time { return now.Add(time.Minute) }
+	r.reconcileRateLimit(ctx, model)
+	idleStatus := model.Status.RateLimit.DeepCopy()
+	assert.Equal(t, langopv1alpha1.RateLimitUsage{}, idleStatus.CurrentUsage)
+	tracker.now = func() time.Time { return now.Add(2 * time.Minute) }
+	r.reconcileRateLimit(ctx, model)
+	assert.Equal(t, idleStatus, model.Status.RateLimit)
+	requests, tokens = tracker.GetRemainingRateLimit(model)
+	assert.Equal(t, int64(100), requests)
+	assert.Equal(t, int64(12000), tokens)
+
+	// Telemetry outage leaves the usage unknown
+	adapter.AvailableReturn = false
+	r.reconcileRateLimit(ctx, model)
+	condition = meta.FindStatusCondition(model.Status.Conditions, RateLimitedCondition)
+	require.NotNil(t, condition)
+	assert.Equal(t, metav1.ConditionUnknown, condition.Status)
+
+	// Removing the limits clears the rate limit state
+	model.Spec.RateLimits = nil
+	assert.Equal(t, time.Duration(0), r.reconcileRateLimit(ctx, model))
+	assert.Nil(t, model.Status.RateLimit)
+	assert.Nil(t, meta.FindStatusCondition(model.Status.Conditions, RateLimitedCondition))
+	requests, tokens = tracker.GetRemainingRateLimit(model)
+	assert.Equal(t, UnlimitedRateLimit, requests)
+	assert.Equal(t, UnlimitedRateLimit, tokens)
+}
@@ -0,0 +1,34 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ReservedEnvIgnoredCondition is set on agents whose spec.env sets variables the operator manages.
+// The webhook rejects new reserved names, but agents admitted before a name was reserved keep it.
+const ReservedEnvIgnoredCondition = "ReservedEnvIgnored"
+
+// reportIgnoredAgentEnv records which spec.env variables were dropped in favour of the operator's
+// values. An event is only emitted when the ignored names change, and when none are left.
+func (r *LanguageAgentReconciler) reportIgnoredAgentEnv(agent *langopv1alpha1.LanguageAgent, ignored []string) {
+	if len(ignored) > 0 {
+		message := fmt.Sprintf("spec.env sets %s, managed by the operator; the operator's values are used instead. Remove them from spec.env",
+			strings.Join(ignored, ", "))
+		changed := SetCondition(&agent.Status.Conditions, ReservedEnvIgnoredCondition, metav1.ConditionTrue, "ReservedName",
+			message, agent.Generation)
+		if changed && r.Recorder != nil {
+			r.Recorder.Event(agent, corev1.EventTypeWarning, "ReservedEnvIgnored", message)
+		}
+		return
+	}
+	if meta.RemoveStatusCondition(&agent.Status.Conditions, ReservedEnvIgnoredCondition) && r.Recorder != nil {
+		r.Recorder.Event(agent, corev1.EventTypeNormal, "ReservedEnvResolved", "spec.env no longer sets variables managed by the operator")
+	}
+}
@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/types"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ParseDefaultResources builds the operator-level default requests and limits of agent
+// containers from the --default-agent-* flags. Empty quantities are left unset.
+func ParseDefaultResources(cpuRequest, cpuLimit, memoryRequest, memoryLimit string) (corev1.ResourceRequirements, error) {
+	var defaults corev1.ResourceRequirements
+	for _, quantity := range []struct {
+		flag  string
+		value string
+		list  *corev1.ResourceList
+		name  corev1.ResourceName
+	}{
+		{"default-agent-cpu-request", cpuRequest, &defaults.Requests, corev1.ResourceCPU},
+		{"default-agent-cpu-limit", cpuLimit, &defaults.Limits, corev1.ResourceCPU},
+		{"default-agent-memory-request", memoryRequest, &defaults.Requests, corev1.ResourceMemory},
+		{"default-agent-memory-limit", memoryLimit, &defaults.Limits, corev1.ResourceMemory},
+	} {
+		if quantity.value == "" {
+			continue
+		}
+		parsed, err := resource.ParseQuantity(quantity.value)
+		if err != nil {
+			return defaults, fmt.Errorf("invalid --%s %q: %w", quantity.flag, quantity.value, err)
+		}
+		if *quantity.list == nil {
+			*quantity.list = corev1.ResourceList{}
+		}
+		(*quantity.list)[quantity.name] = parsed
+	}
+	return defaults, nil
+}
+
+// mergeResourceRequirements fills each request and limit the container doesn't set from the
+// first defaults layer that sets it. Defaults never produce a request above a limit: a defaulted
+// limit below the container's own request is skipped, and a defaulted request is capped at the limit.
+func mergeResourceRequirements(own corev1.ResourceRequirements, defaults ...corev1.ResourceRequirements) corev1.ResourceRequirements {
+	merged := *own.DeepCopy()
+	fill := func(list *corev1.ResourceList, name corev1.ResourceName, quantity resource.Quantity) {
+		if _, ok := (*list)[name]; ok {
+			return
+		}
+		if *list == nil {
+			*list = corev1.ResourceList{}
+		}
+		(*list)[name] = quantity.DeepCopy()
+	}
+
+	for _, layer := range defaults {
+		for name, limit := range layer.Limits {
+			if request, ok := own.Requests[name]; ok && limit.Cmp(request) < 0 {
+				continue
+			}
+			fill(&merged.Limits, name, limit)
+		}
+	}
+	for _, layer := range defaults {
+		for name, request := range layer.Requests {
+			if limit, ok := merged.Limits[name]; ok && request.Cmp(limit) > 0 {
+				request = limit
+			}
+			fill(&merged.Requests, name, request)
+		}
+	}
+	return merged
+}
+
+// resolveResourceDefaults returns the default requests and limits for the agent's containers:
+// the referenced cluster's spec.defaults.resources, then the operator's defaults
+func (r *LanguageAgentReconciler) resolveResourceDefaults(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (corev1.ResourceRequirements, error) {
+	if agent.Spec.ClusterRef == "" {
+		return r.DefaultResources, nil
+	}
+
+	cluster := &langopv1alpha1.LanguageCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.ClusterRef, Namespace: agent.Namespace}, cluster); err != nil {
+		if errors.IsNotFound(err) {
+			return r.DefaultResources, nil
+		}
+		return corev1.ResourceRequirements{}, fmt.Errorf("failed to get cluster %s: %w", agent.Spec.ClusterRef, err)
+	}
+	if cluster.Spec.Defaults == nil || cluster.Spec.Defaults.Resources == nil {
+		return r.DefaultResources, nil
+	}
+	return mergeResourceRequirements(*cluster.Spec.Defaults.Resources, r.DefaultResources), nil
+}
+
+// applyResourceDefaults fills the requests and limits the agent container and sidecar tool
+// containers don't set from defaults
+func applyResourceDefaults(podSpec *corev1.PodSpec, defaults corev1.ResourceRequirements) {
+	for i := range podSpec.Containers {
+		podSpec.Containers[i].Resources = mergeResourceRequirements(podSpec.Containers[i].Resources, defaults)
+	}
+	for i := range podSpec.InitContainers {
+		podSpec.InitContainers[i].Resources = mergeResourceRequirements(podSpec.InitContainers[i].Resources, defaults)
+	}
+}
@@ -0,0 +1,201 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// resources builds requirements from cpu and memory quantities; empty quantities are left unset
+func resources(cpuRequest, memoryRequest, cpuLimit, memoryLimit string) corev1.ResourceRequirements {
+	var requirements corev1.ResourceRequirements
+	set := func(list *corev1.ResourceList, name corev1.ResourceName, value string) {
+		if value == "" {
+			return
+		}
+		if *list == nil {
+			*list = corev1.ResourceList{}
+		}
+		(*list)[name] = resource.MustParse(value)
+	}
+	set(&requirements.Requests, corev1.ResourceCPU, cpuRequest)
+	set(&requirements.Requests, corev1.ResourceMemory, memoryRequest)
+	set(&requirements.Limits, corev1.ResourceCPU, cpuLimit)
+	set(&requirements.Limits, corev1.ResourceMemory, memoryLimit)
+	return requirements
+}
+
+// resourceSummary renders requirements for comparison
+func resourceSummary(requirements corev1.ResourceRequirements) string {
+	summary := func(list corev1.ResourceList, name corev1.ResourceName) string {
+		if quantity, ok := list[name]; ok {
+			return quantity.String()
+		}
+		return "-"
+	}
+	return "requests cpu=" + summary(requirements.Requests, corev1.ResourceCPU) +
+		" memory=" + summary(requirements.Requests, corev1.ResourceMemory) +
+		" limits cpu=" + summary(requirements.Limits, corev1.ResourceCPU) +
+		" memory=" + summary(requirements.Limits, corev1.ResourceMemory)
+}
+
+func TestParseDefaultResources(t *testing.T) {
+	defaults, err := ParseDefaultResources("100m", "", "256Mi", "1Gi")
+	if err != nil {
+		t.Fatalf("ParseDefaultResources failed: %v", err)
+	}
+	if got, expected := resourceSummary(defaults), resourceSummary(resources("100m", "256Mi", "", "1Gi")); got != expected {
+		t.Errorf("Expected %s, got %s", expected, got)
+	}
+
+	if _, err := ParseDefaultResources("lots", "", "", ""); err == nil {
+		t.Error("Expected an error for an invalid quantity")
+	}
+}
+
+func TestMergeResourceRequirements(t *testing.T) {
+	tests := []struct {
+		name     string
+		own      corev1.ResourceRequirements
+		defaults []corev1.ResourceRequirements
+		expected corev1.ResourceRequirements
+	}{
+		{
+			name:     "no defaults keeps the container's own resources",
+			own:      resources("200m", "", "", ""),
+			expected: resources("200m", "", "", ""),
+		},
+		{
+			name:     "unset values are filled from defaults",
+			own:      resources("", "", "", "2Gi"),
+			defaults: []corev1.ResourceRequirements{resources("100m", "256Mi", "", "1Gi")},
+			expected: resources("100m", "256Mi", "", "2Gi"),
+		},
+		{
+			name: "earlier layers take precedence",
+			defaults: []corev1.ResourceRequirements{
+				resources("", "512Mi", "", ""),
+				resources("100m", "256Mi", "1", "1Gi"),
+			},
+			expected: resources("100m", "512Mi", "1", "1Gi"),
+		},
+		{
+			name:     "defaulted request is capped at the container's limit",
+			own:      resources("", "", "", "128Mi"),
+			defaults: []corev1.ResourceRequirements{resources("", "256Mi", "", "")},
+			expected: resources("", "128Mi", "", "128Mi"),
+		},
+		{
+			name:     "defaulted limit below the container's request is skipped",
+			own:      resources("", "2Gi", "", ""),
+			defaults: []corev1.ResourceRequirements{resources("", "", "", "1Gi")},
+			expected: resources("", "2Gi", "", ""),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := mergeResourceRequirements(tt.own, tt.defaults...)
+			if got, expected := resourceSummary(merged), resourceSummary(tt.expected); got != expected {
+				t.Errorf("Expected %s, got %s", expected, got)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_ResourceDefaultsPrecedence(t *testing.T) {
+	tests := []struct {
+		name             string
+		operatorDefaults corev1.ResourceRequirements
+		clusterDefaults  *corev1.ResourceRequirements
+		agentResources   corev1.ResourceRequirements
+		toolResources    corev1.ResourceRequirements
+		expectAgent      corev1.ResourceRequirements
+		expectTool       corev1.ResourceRequirements
+	}{
+		{
+			name: "no defaults leaves containers unbounded",
+		},
+		{
+			name:             "operator flags apply when nothing else is set",
+			operatorDefaults: resources("100m", "256Mi", "", "1Gi"),
+			expectAgent:      resources("100m", "256Mi", "", "1Gi"),
+			expectTool:       resources("100m", "256Mi", "", "1Gi"),
+		},
+		{
+			name:             "cluster defaults override operator flags",
+			operatorDefaults: resources("100m", "256Mi", "", "1Gi"),
+			clusterDefaults:  &corev1.ResourceRequirements{Limits: resources("", "", "", "512Mi").Limits},
+			expectAgent:      resources("100m", "256Mi", "", "512Mi"),
+			expectTool:       resources("100m", "256Mi", "", "512Mi"),
+		},
+		{
+			name:             "agent and tool specs override cluster defaults",
+			operatorDefaults: resources("100m", "256Mi", "", "1Gi"),
+			clusterDefaults:  &corev1.ResourceRequirements{Limits: resources("", "", "", "512Mi").Limits},
+			agentResources:   resources("", "", "", "4Gi"),
+			toolResources:    resources("50m", "", "", ""),
+			expectAgent:      resources("100m", "256Mi", "", "4Gi"),
+			expectTool:       resources("50m", "256Mi", "", "512Mi"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &langopv1alpha1.LanguageCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "default"},
+				Status:     langopv1alpha1.LanguageClusterStatus{Phase: "Ready"},
+			}
+			if tt.clusterDefaults != nil {
+				cluster.Spec.Defaults = &langopv1alpha1.ClusterDefaults{Resources: tt.clusterDefaults}
+			}
+			tool := &langopv1alpha1.LanguageTool{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-search", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageToolSpec{
+					Image:          "ghcr.io/language-operator/web-tool:latest",
+					DeploymentMode: "sidecar",
+					Port:           3000,
+					Resources:      tt.toolResources,
+				},
+			}
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "bounded-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:         "ghcr.io/language-operator/agent:latest",
+					ExecutionMode: "autonomous",
+					ClusterRef:    "platform",
+					ToolRefs:      []langopv1alpha1.ToolReference{{Name: "web-search"}},
+					Resources:     tt.agentResources,
+				},
+			}
+			reconciler, fakeClient := newForceWorkloadReconciler(t, cluster, tool, agent)
+			reconciler.DefaultResources = tt.operatorDefaults
+
+			ctx := context.Background()
+			if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+				t.Fatalf("reconcileDeployment failed: %v", err)
+			}
+			deployment := &appsv1.Deployment{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, deployment); err != nil {
+				t.Fatalf("Failed to get Deployment: %v", err)
+			}
+
+			podSpec := deployment.Spec.Template.Spec
+			if got, expected := resourceSummary(podSpec.Containers[0].Resources), resourceSummary(tt.expectAgent); got != expected {
+				t.Errorf("Expected agent container %s, got %s", expected, got)
+			}
+			if len(podSpec.InitContainers) != 1 {
+				t.Fatalf("Expected 1 sidecar tool container, got %d", len(podSpec.InitContainers))
+			}
+			if got, expected := resourceSummary(podSpec.InitContainers[0].Resources), resourceSummary(tt.expectTool); got != expected {
+				t.Errorf("Expected sidecar tool container %s, got %s", expected, got)
+			}
+		})
+	}
+}
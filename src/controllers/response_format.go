@@ -0,0 +1,61 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// responseFormatCondition reports whether the agent's models can honor its persona's response format
+const responseFormatCondition = "ResponseFormatSupported"
+
+// checkPersonaResponseFormat warns when the agent's composed persona asks for JSON or structured
+// responses but one of the agent's models can't be constrained to emit JSON, so its output may not
+// match the format at runtime. Models whose capabilities are unknown are given the benefit of the doubt.
+func (r *LanguageAgentReconciler) checkPersonaResponseFormat(ctx context.Context, agent *langopv1alpha1.LanguageAgent, persona *langopv1alpha1.LanguagePersona) {
+	if persona == nil || !langopv1alpha1.IsStructuredResponseFormat(persona.Spec.ResponseFormat) {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, responseFormatCondition)
+		return
+	}
+
+	var unsupported []string
+	for _, ref := range agent.Spec.ModelRefs {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = agent.Namespace
+		}
+		model := &langopv1alpha1.LanguageModel{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, model); err != nil {
+			// Missing models are reported where the agent's models are resolved
+			log.FromContext(ctx).V(1).Info("Skipping response format check of model", "model", ref.Name, "namespace", namespace, "error", err.Error())
+			continue
+		}
+		capabilities, known := langopv1alpha1.ModelCapabilities(model)
+		if !known {
+			continue
+		}
+		if missing := langopv1alpha1.MissingModelCapabilities(capabilities, []string{langopv1alpha1.ModelCapabilityJSONMode}); len(missing) > 0 {
+			unsupported = append(unsupported, fmt.Sprintf("%q (LanguageModel %s/%s)", model.Spec.ModelName, namespace, ref.Name))
+		}
+	}
+
+	if len(unsupported) == 0 {
+		SetCondition(&agent.Status.Conditions, responseFormatCondition, metav1.ConditionTrue, "ModelsSupportFormat",
+			fmt.Sprintf("All models support %s responses", persona.Spec.ResponseFormat.Type), agent.Generation)
+		return
+	}
+
+	message := fmt.Sprintf("Persona %s requests %s responses but model %s does not support %s; responses may not match spec.responseFormat",
+		persona.Name, persona.Spec.ResponseFormat.Type, strings.Join(unsupported, ", "), langopv1alpha1.ModelCapabilityJSONMode)
+	if SetCondition(&agent.Status.Conditions, responseFormatCondition, metav1.ConditionFalse, "ResponseFormatUnsupported", message, agent.Generation) && r.Recorder != nil {
+		r.Recorder.Event(agent, corev1.EventTypeWarning, "ResponseFormatUnsupported", message)
+	}
+}
@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+func TestLanguageAgentController_PersonaResponseFormat(t *testing.T) {
+	jsonModel := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "gpt", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai", ModelName: "gpt-4o"},
+	}
+	textModel := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "legacy", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai", ModelName: "gpt-4"},
+	}
+	unknownModel := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "local", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai-compatible", ModelName: "my-finetune"},
+	}
+
+	tests := []struct {
+		name       string
+		formatType string
+		model      string
+		expected   metav1.ConditionStatus
+	}{
+		{name: "text format isn't checked", formatType: "markdown", model: "legacy"},
+		{name: "json format on a json-mode model", formatType: "json", model: "gpt", expected: metav1.ConditionTrue},
+		{name: "structured format on a model without json mode", formatType: "structured", model: "legacy", expected: metav1.ConditionFalse},
+		{name: "json format on a model with unknown capabilities", formatType: "json", model: "local", expected: metav1.ConditionTrue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent", Namespace: "default"},
+				Spec:       langopv1alpha1.LanguageAgentSpec{ModelRefs: []langopv1alpha1.ModelReference{{Name: tt.model}}},
+			}
+			persona := &langopv1alpha1.LanguagePersona{
+				ObjectMeta: metav1.ObjectMeta{Name: "analyst", Namespace: "default"},
+				Spec:       langopv1alpha1.LanguagePersonaSpec{ResponseFormat: &langopv1alpha1.ResponseFormatSpec{Type: tt.formatType}},
+			}
+			reconciler, _ := newForceWorkloadReconciler(t, jsonModel.DeepCopy(), textModel.DeepCopy(), unknownModel.DeepCopy())
+
+			reconciler.checkPersonaResponseFormat(context.Background(), agent, persona)
+
+			condition := meta.FindStatusCondition(agent.Status.Conditions, responseFormatCondition)
+			events := drainEvents(reconciler.Recorder.(*record.FakeRecorder))
+			if tt.expected == "" {
+				if condition != nil {
+					t.Errorf("Expected no %s condition, got %v", responseFormatCondition, condition)
+				}
+				return
+			}
+			if condition == nil || condition.Status != tt.expected {
+				t.Fatalf("Expected %s condition %s, got %v", responseFormatCondition, tt.expected, condition)
+			}
+			if warned := hasEvent(events, "ResponseFormatUnsupported"); warned != (tt.expected == metav1.ConditionFalse) {
+				t.Errorf("Expected ResponseFormatUnsupported event %v, got %v", tt.expected == metav1.ConditionFalse, events)
+			}
+
+			// The warning is emitted once, not on every reconcile
+			reconciler.checkPersonaResponseFormat(context.Background(), agent, persona)
+			if events := drainEvents(reconciler.Recorder.(*record.FakeRecorder)); len(events) > 0 {
+				t.Errorf("Expected no events on an unchanged reconcile, got %v", events)
+			}
+		})
+	}
+}
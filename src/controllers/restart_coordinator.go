@@ -0,0 +1,200 @@
+package controllers
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	agentRestartPending  = "pending"
+	agentRestartInFlight = "in_flight"
+
+	// restartRequeueInterval is how often an agent waiting for a restart slot retries
+	restartRequeueInterval = 10 * time.Second
+	// restartSlotTimeout frees the slot of a rollout that never completes, e.g. a crash-looping agent
+	restartSlotTimeout = 10 * time.Minute
+
+	// podTemplateHashAnnotation records on a Deployment the fingerprint of the pod template the
+	// operator last applied
+	podTemplateHashAnnotation = "langop.io/pod-template-hash"
+)
+
+// AgentRestarts tracks agent Deployment rollouts waiting for or holding a restart slot
+var AgentRestarts = prometheus.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "langop_agent_restarts",
+		Help: "Number of agent Deployment rollouts by state (pending or in_flight)",
+	},
+	[]string{"state"},
+)
+
+func init() {
+	metrics.Registry.MustRegister(AgentRestarts)
+}
+
+// restartCoordinator staggers agent Deployment rollouts. When a shared change (a rotated model
+// secret, a new default image) changes the pod template of many agents at once, only
+// maxConcurrent of them roll out together; the rest wait in FIFO order for a slot, which is
+// freed once the Deployment has finished rolling out.
+type restartCoordinator struct {
+	mu            sync.Mutex
+	maxConcurrent int
+	inFlight      map[types.NamespacedName]time.Time
+	pending       []types.NamespacedName
+	now           func() time.Time
+}
+
+// newRestartCoordinator creates a coordinator allowing maxConcurrent rollouts; zero or less means unlimited
+func newRestartCoordinator(maxConcurrent int32) *restartCoordinator {
+	return &restartCoordinator{
+		maxConcurrent: int(maxConcurrent),
+		inFlight:      make(map[types.NamespacedName]time.Time),
+		now:           time.Now,
+	}
+}
+
+// Admit reports whether the agent may roll out its Deployment now. Agents that must wait are
+// queued; queued reports whether this call added the agent to the queue.
+func (c *restartCoordinator) Admit(key types.NamespacedName) (admitted, queued bool) {
+	if c == nil || c.maxConcurrent <= 0 {
+		return true, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	defer c.updateMetrics()
+
+	if _, ok := c.inFlight[key]; ok {
+		return true, false
+	}
+	c.expireStale()
+
+	position := c.position(key)
+	if position < 0 {
+		c.pending = append(c.pending, key)
+		position = len(c.pending) - 1
+		queued = true
+	}
+	if position < c.maxConcurrent-len(c.inFlight) {
+		c.pending = append(c.pending[:position], c.pending[position+1:]...)
+		c.inFlight[key] = c.now()
+		return true, false
+	}
+	return false, queued
+}
+
+// Waiting reports whether the agent is queued for a restart slot
+func (c *restartCoordinator) Waiting(key types.NamespacedName) bool {
+	if c == nil {
+		return false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.position(key) >= 0
+}
+
+// Complete frees the agent's slot once its rollout has finished
+func (c *restartCoordinator) Complete(key types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.inFlight, key)
+	c.updateMetrics()
+}
+
+// Cancel removes an agent from the queue when its Deployment no longer needs a rollout
+func (c *restartCoordinator) Cancel(key types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if position := c.position(key); position >= 0 {
+		c.pending = append(c.pending[:position], c.pending[position+1:]...)
+	}
+	c.updateMetrics()
+}
+
+// Forget drops all state for a deleted agent
+func (c *restartCoordinator) Forget(key types.NamespacedName) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	delete(c.inFlight, key)
+	c.mu.Unlock()
+	c.Cancel(key)
+}
+
+// position returns the agent's index in the queue, or -1. Must be called with c.mu held.
+func (c *restartCoordinator) position(key types.NamespacedName) int {
+	for i, pending := range c.pending {
+		if pending == key {
+			return i
+		}
+	}
+	return -1
+}
+
+// expireStale frees slots held longer than restartSlotTimeout. Must be called with c.mu held.
+func (c *restartCoordinator) expireStale() {
+	for key, since := range c.inFlight {
+		if c.now().Sub(since) >= restartSlotTimeout {
+			delete(c.inFlight, key)
+		}
+	}
+}
+
+// updateMetrics publishes the queue and slot counts. Must be called with c.mu held.
+func (c *restartCoordinator) updateMetrics() {
+	AgentRestarts.WithLabelValues(agentRestartPending).Set(float64(len(c.pending)))
+	AgentRestarts.WithLabelValues(agentRestartInFlight).Set(float64(len(c.inFlight)))
+}
+
+// deploymentRolledOut reports whether every replica of the Deployment runs its current template
+func deploymentRolledOut(deployment *appsv1.Deployment) bool {
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+	return deployment.Status.ObservedGeneration >= deployment.Generation &&
+		deployment.Status.UpdatedReplicas == replicas &&
+		deployment.Status.AvailableReplicas == replicas
+}
+
+// podTemplateFingerprint fingerprints a whole pod template, any change to which restarts the pods.
+// The live template carries server-side defaults, so only templates the operator built are compared.
+func podTemplateFingerprint(template *corev1.PodTemplateSpec) string {
+	// Marshalling a pod template cannot fail; map keys are sorted
+	data, _ := json.Marshal(template)
+	return hashString(string(data))
+}
+
+// podTemplateChanged reports whether applying the desired spec of a Deployment restarts its pods:
+// its pod template differs from the one last applied, or drift is about to be corrected.
+// Deployments applied before the template fingerprint was recorded compare against the live spec.
+func podTemplateChanged(deployment *appsv1.Deployment, liveSpec *appsv1.DeploymentSpec, correctingDrift bool) bool {
+	if correctingDrift {
+		return true
+	}
+	if applied, ok := deployment.Annotations[podTemplateHashAnnotation]; ok {
+		return applied != podTemplateFingerprint(&deployment.Spec.Template)
+	}
+	return restartFingerprint(liveSpec) != restartFingerprint(&deployment.Spec)
+}
+
+// restartFingerprint fingerprints the parts of a Deployment spec whose change restarts its pods
+// that can be compared between a live and a desired spec
+func restartFingerprint(spec *appsv1.DeploymentSpec) string {
+	template := spec.DeepCopy()
+	template.Replicas = nil
+	return hashString(deploymentFingerprint(template) + checksumFingerprint(&spec.Template))
+}
@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestartCoordinator_AdmitsInOrder(t *testing.T) {
+	coordinator := newRestartCoordinator(2)
+	keys := make([]types.NamespacedName, 5)
+	for i := range keys {
+		keys[i] = types.NamespacedName{Name: fmt.Sprintf("agent-%d", i), Namespace: "default"}
+	}
+
+	var admitted []string
+	for _, key := range keys {
+		if ok, _ := coordinator.Admit(key); ok {
+			admitted = append(admitted, key.Name)
+		}
+	}
+	if len(admitted) != 2 || admitted[0] != "agent-0" || admitted[1] != "agent-1" {
+		t.Fatalf("Expected only agent-0 and agent-1 to restart at once, got %v", admitted)
+	}
+
+	// A later agent can't jump ahead of the queue when a slot frees up
+	coordinator.Complete(keys[0])
+	if ok, _ := coordinator.Admit(keys[3]); ok {
+		t.Error("Expected agent-3 to wait behind agent-2")
+	}
+	if ok, _ := coordinator.Admit(keys[2]); !ok {
+		t.Error("Expected agent-2 to take the freed slot")
+	}
+	if !coordinator.Waiting(keys[3]) || !coordinator.Waiting(keys[4]) {
+		t.Error("Expected agent-3 and agent-4 to remain queued")
+	}
+}
+
+func TestRestartCoordinator_ExpiresStuckRollouts(t *testing.T) {
+	coordinator := newRestartCoordinator(1)
+	now := time.Now()
+	coordinator.now = func() time.Time { return now }
+
+	first := types.NamespacedName{Name: "crashlooping", Namespace: "default"}
+	second := types.NamespacedName{Name: "healthy", Namespace: "default"}
+	coordinator.Admit(first)
+	if ok, queued := coordinator.Admit(second); ok || !queued {
+		t.Fatalf("Expected second agent to be queued, got admitted=%v queued=%v", ok, queued)
+	}
+
+	now = now.Add(restartSlotTimeout)
+	if ok, _ := coordinator.Admit(second); !ok {
+		t.Error("Expected a rollout that never completes to give up its slot")
+	}
+}
+
+func TestRestartCoordinator_Unlimited(t *testing.T) {
+	coordinator := newRestartCoordinator(0)
+	for i := 0; i < 10; i++ {
+		if ok, _ := coordinator.Admit(types.NamespacedName{Name: fmt.Sprintf("agent-%d", i), Namespace: "default"}); !ok {
+			t.Fatalf("Expected unlimited restarts to admit every agent")
+		}
+	}
+}
+
+// TestLanguageAgentController_StaggersFleetRestarts changes the image of several agents at once and
+// checks their Deployments roll out one at a time instead of simultaneously
+func TestLanguageAgentController_StaggersFleetRestarts(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	var agents []*langopv1alpha1.LanguageAgent
+	for i := 0; i < 3; i++ {
+		agents = append(agents, &langopv1alpha1.LanguageAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("fleet-%d", i), Namespace: "default"},
+			Spec: langopv1alpha1.LanguageAgentSpec{
+				Image:         "ghcr.io/language-operator/agent:v1",
+				ExecutionMode: "autonomous",
+			},
+		})
+	}
+
+	builder := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&appsv1.Deployment{})
+	for _, agent := range agents {
+		builder = builder.WithObjects(agent).WithStatusSubresource(agent)
+	}
+	fakeClient := builder.Build()
+
+	recorder := record.NewFakeRecorder(100)
+	reconciler := &LanguageAgentReconciler{
+		Client:                     fakeClient,
+		Scheme:                     scheme,
+		Log:                        logr.Discard(),
+		Recorder:                   recorder,
+		RegistryManager:            &mockRegistryManager{},
+		MaxConcurrentAgentRestarts: 1,
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	reconcileAgent := func(name string) ctrl.Result {
+		t.Helper()
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: "default"}})
+		if err != nil {
+			t.Fatalf("Reconcile of %s failed: %v", name, err)
+		}
+		return result
+	}
+	deploymentImage := func(name string) string {
+		t.Helper()
+		deployment := &appsv1.Deployment{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: "default"}, deployment); err != nil {
+			t.Fatalf("Failed to get Deployment %s: %v", name, err)
+		}
+		return deployment.Spec.Template.Spec.Containers[0].Image
+	}
+
+	// Creating Deployments is not throttled
+	for _, agent := range agents {
+		reconcileAgent(agent.Name)
+	}
+
+	// A shared change updates the image of every agent at once
+	for _, agent := range agents {
+		current := &langopv1alpha1.LanguageAgent{}
+		if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, current); err != nil {
+			t.Fatalf("Failed to get agent: %v", err)
+		}
+		current.Spec.Image = "ghcr.io/language-operator/agent:v2"
+		if err := fakeClient.Update(ctx, current); err != nil {
+			t.Fatalf("Failed to update agent: %v", err)
+		}
+	}
+	drainEvents(recorder)
+
+	results := make([]ctrl.Result, len(agents))
+	for i, agent := range agents {
+		results[i] = reconcileAgent(agent.Name)
+	}
+
+	if image := deploymentImage("fleet-0"); image != "ghcr.io/language-operator/agent:v2" {
+		t.Errorf("Expected fleet-0 to roll out first, got image %s", image)
+	}
+	for i := 1; i < len(agents); i++ {
+		if image := deploymentImage(agents[i].Name); image != "ghcr.io/language-operator/agent:v1" {
+			t.Errorf("Expected %s to wait for a restart slot, got image %s", agents[i].Name, image)
+		}
+		if results[i].RequeueAfter != restartRequeueInterval {
+			t.Errorf("Expected %s to be requeued while waiting, got %+v", agents[i].Name, results[i])
+		}
+	}
+	if events := drainEvents(recorder); !hasEvent(events, "RestartDeferred") {
+		t.Errorf("Expected RestartDeferred events, got %v", events)
+	}
+
+	// Once fleet-0 has rolled out, the next agent takes its slot
+	deployment := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "fleet-0", Namespace: "default"}, deployment); err != nil {
+		t.Fatalf("Failed to get Deployment: %v", err)
+	}
+	deployment.Status = appsv1.DeploymentStatus{ObservedGeneration: deployment.Generation, Replicas: 1, UpdatedReplicas: 1, AvailableReplicas: 1}
+	if err := fakeClient.Status().Update(ctx, deployment); err != nil {
+		t.Fatalf("Failed to update Deployment status: %v", err)
+	}
+	reconcileAgent("fleet-0")
+	reconcileAgent("fleet-1")
+	reconcileAgent("fleet-2")
+
+	if image := deploymentImage("fleet-1"); image != "ghcr.io/language-operator/agent:v2" {
+		t.Errorf("Expected fleet-1 to roll out after fleet-0 finished, got image %s", image)
+	}
+	if image := deploymentImage("fleet-2"); image != "ghcr.io/language-operator/agent:v1" {
+		t.Errorf("Expected fleet-2 to keep waiting, got image %s", image)
+	}
+}
+
+func TestPodTemplateChanged(t *testing.T) {
+	template := corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{Name: "agent", Image: "ghcr.io/language-operator/agent:v1"}}},
+	}
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{podTemplateHashAnnotation: podTemplateFingerprint(&template)}},
+		Spec:       appsv1.DeploymentSpec{Template: *template.DeepCopy()},
+	}
+	liveSpec := deployment.Spec.DeepCopy()
+
+	if podTemplateChanged(deployment, liveSpec, false) {
+		t.Error("Expected the applied template to be unchanged")
+	}
+	if !podTemplateChanged(deployment, liveSpec, true) {
+		t.Error("Expected correcting drift to restart the pods")
+	}
+
+	// Volumes and other pod settings restart the pods as much as the containers do
+	deployment.Spec.Template.Spec.Volumes = []corev1.Volume{{Name: "workspace"}}
+	if !podTemplateChanged(deployment, liveSpec, false) {
+		t.Error("Expected a new volume to change the pod template")
+	}
+
+	// Without a recorded fingerprint only the container settings of the live spec are compared
+	delete(deployment.Annotations, podTemplateHashAnnotation)
+	if podTemplateChanged(deployment, liveSpec, false) {
+		t.Error("Expected the live spec fallback to ignore volumes")
+	}
+	deployment.Spec.Template.Spec.Containers[0].Image = "ghcr.io/language-operator/agent:v2"
+	if !podTemplateChanged(deployment, liveSpec, false) {
+		t.Error("Expected the live spec fallback to detect a new image")
+	}
+}
@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/robfig/cron/v3"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+const (
+	// ScheduleTooFrequentCondition is set on agents whose schedule runs more often than the cluster allows
+	ScheduleTooFrequentCondition = "ScheduleTooFrequent"
+
+	// scheduleIntervalHorizon bounds how far ahead schedule activations are inspected
+	scheduleIntervalHorizon = 366 * 24 * time.Hour
+
+	// scheduleIntervalMaxActivations bounds the number of activations inspected for very frequent schedules
+	scheduleIntervalMaxActivations = 10000
+)
+
+// scheduleReferenceTime anchors interval checks so the result does not depend on when it runs
+var scheduleReferenceTime = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// validateScheduleInterval returns an error if any two consecutive runs of the cron schedule
+// are closer together than minInterval. A zero minInterval allows any schedule.
+func validateScheduleInterval(schedule string, minInterval time.Duration) error {
+	if minInterval <= 0 {
+		return nil
+	}
+
+	sched, err := cron.ParseStandard(schedule)
+	if err != nil {
+		return fmt.Errorf("invalid cron expression %q: %w", schedule, err)
+	}
+
+	horizon := scheduleReferenceTime.Add(scheduleIntervalHorizon)
+	previous := sched.Next(scheduleReferenceTime)
+	for i := 0; i < scheduleIntervalMaxActivations && !previous.IsZero() && previous.Before(horizon); i++ {
+		next := sched.Next(previous)
+		if next.IsZero() {
+			break
+		}
+		if gap := next.Sub(previous); gap < minInterval {
+			return fmt.Errorf("schedule %q runs every %v, more often than the cluster minimum of %v", schedule, gap, minInterval)
+		}
+		previous = next
+	}
+
+	return nil
+}
+
+// getMinScheduleInterval returns the minimum schedule interval enforced by the agent's cluster, or zero if none
+func (r *LanguageAgentReconciler) getMinScheduleInterval(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (time.Duration, error) {
+	if agent.Spec.ClusterRef == "" {
+		return 0, nil
+	}
+
+	cluster := &langopv1alpha1.LanguageCluster{}
+	if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.ClusterRef, Namespace: agent.Namespace}, cluster); err != nil {
+		if errors.IsNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to get cluster %s: %w", agent.Spec.ClusterRef, err)
+	}
+
+	if cluster.Spec.MinScheduleInterval == nil {
+		return 0, nil
+	}
+	return cluster.Spec.MinScheduleInterval.Duration, nil
+}
@@ -0,0 +1,125 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestValidateScheduleInterval(t *testing.T) {
+	tests := []struct {
+		name        string
+		schedule    string
+		minInterval time.Duration
+		expectErr   bool
+	}{
+		{name: "no policy allows every minute", schedule: "* * * * *", minInterval: 0},
+		{name: "every minute is too frequent", schedule: "* * * * *", minInterval: 15 * time.Minute, expectErr: true},
+		{name: "every 15 minutes complies", schedule: "*/15 * * * *", minInterval: 15 * time.Minute},
+		{name: "uneven gaps are checked", schedule: "0,5 * * * *", minInterval: 15 * time.Minute, expectErr: true},
+		{name: "weekday schedule complies", schedule: "0 9 * * 1-5", minInterval: time.Hour},
+		{name: "descriptor complies", schedule: "@hourly", minInterval: time.Hour},
+		{name: "every descriptor is too frequent", schedule: "@every 30s", minInterval: time.Minute, expectErr: true},
+		{name: "invalid expression", schedule: "not a schedule", minInterval: time.Minute, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateScheduleInterval(tt.schedule, tt.minInterval)
+			if (err != nil) != tt.expectErr {
+				t.Errorf("validateScheduleInterval(%q, %v) error = %v, expectErr %v", tt.schedule, tt.minInterval, err, tt.expectErr)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_MinScheduleInterval(t *testing.T) {
+	tests := []struct {
+		name          string
+		schedule      string
+		expectCronJob bool
+	}{
+		{name: "over-frequent schedule is rejected", schedule: "* * * * *", expectCronJob: false},
+		{name: "compliant schedule passes", schedule: "*/30 * * * *", expectCronJob: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+
+			cluster := &langopv1alpha1.LanguageCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "policy-cluster", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageClusterSpec{
+					MinScheduleInterval: &metav1.Duration{Duration: 15 * time.Minute},
+				},
+				Status: langopv1alpha1.LanguageClusterStatus{Phase: "Ready"},
+			}
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "scheduled-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:         "ghcr.io/language-operator/agent:latest",
+					ClusterRef:    "policy-cluster",
+					ExecutionMode: "scheduled",
+					Schedule:      tt.schedule,
+				},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(cluster, agent).
+				WithStatusSubresource(agent).
+				Build()
+
+			reconciler := &LanguageAgentReconciler{
+				Client:          fakeClient,
+				Scheme:          scheme,
+				Log:             logr.Discard(),
+				Recorder:        &record.FakeRecorder{},
+				RegistryManager: &mockRegistryManager{},
+			}
+			reconciler.InitializeGatewayCache()
+
+			ctx := context.Background()
+			key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+
+			cronJob := &batchv1.CronJob{}
+			err := fakeClient.Get(ctx, key, cronJob)
+			if tt.expectCronJob && err != nil {
+				t.Fatalf("Expected CronJob for compliant schedule, got error: %v", err)
+			}
+			if !tt.expectCronJob && !errors.IsNotFound(err) {
+				t.Fatalf("Expected no CronJob for over-frequent schedule, got err=%v", err)
+			}
+
+			updated := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, key, updated); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			tooFrequent := meta.IsStatusConditionTrue(updated.Status.Conditions, ScheduleTooFrequentCondition)
+			if tooFrequent == tt.expectCronJob {
+				t.Errorf("Expected %s condition set=%v, got conditions %+v", ScheduleTooFrequentCondition, !tt.expectCronJob, updated.Status.Conditions)
+			}
+			if !tt.expectCronJob {
+				ready := meta.FindStatusCondition(updated.Status.Conditions, "Ready")
+				if ready == nil || ready.Status != metav1.ConditionFalse || ready.Reason != "ScheduleTooFrequent" {
+					t.Errorf("Expected Ready=False with reason ScheduleTooFrequent, got %+v", ready)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// toolDeploymentModeCluster runs a single tool server in the tool's cluster namespace, shared by
+// agents in every namespace and kept up only while at least one of them references it
+const toolDeploymentModeCluster = "cluster"
+
+// sharedToolDeletionRecheckInterval is how often a blocked deletion re-checks for referencing agents
+const sharedToolDeletionRecheckInterval = 30 * time.Second
+
+// sharedToolReferencingAgentsField indexes shared tools by the namespace/name keys of the agents
+// that referenced them at their last reconcile
+const sharedToolReferencingAgentsField = "status.referencingAgents"
+
+// sharedToolReferencingAgents extracts the sharedToolReferencingAgentsField index values of a tool
+func sharedToolReferencingAgents(obj client.Object) []string {
+	tool, ok := obj.(*langopv1alpha1.LanguageTool)
+	if !ok || !isSharedTool(tool) {
+		return nil
+	}
+	return tool.Status.ReferencingAgents
+}
+
+// isSharedTool reports whether the tool runs as a reference-counted cluster-wide server
+func isSharedTool(tool *langopv1alpha1.LanguageTool) bool {
+	return tool.Spec.DeploymentMode == toolDeploymentModeCluster
+}
+
+// agentReferencesTool reports whether any of the agent's tool references, from spec.toolRefs or
+// its tool selector, resolve to the tool
+func agentReferencesTool(agent *langopv1alpha1.LanguageAgent, tool *langopv1alpha1.LanguageTool) bool {
+	for _, ref := range agentToolRefs(agent) {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = agent.Namespace
+		}
+		if ref.Name == tool.Name && namespace == tool.Namespace {
+			return true
+		}
+	}
+	return false
+}
+
+// findReferencingAgents returns the agents, in any namespace, that reference the tool, as sorted
+// namespace/name keys. Agents being deleted no longer count.
+func (r *LanguageToolReconciler) findReferencingAgents(ctx context.Context, tool *langopv1alpha1.LanguageTool) ([]string, error) {
+	agentList := &langopv1alpha1.LanguageAgentList{}
+	if err := r.List(ctx, agentList); err != nil {
+		return nil, fmt.Errorf("failed to list agents: %w", err)
+	}
+
+	var referencing []string
+	for i := range agentList.Items {
+		agent := &agentList.Items[i]
+		if agent.DeletionTimestamp.IsZero() && agentReferencesTool(agent, tool) {
+			referencing = append(referencing, agent.Namespace+"/"+agent.Name)
+		}
+	}
+	sort.Strings(referencing)
+	return referencing, nil
+}
+
+// removeSharedServer deletes the shared Deployment and Service of a tool no agent references anymore
+func (r *LanguageToolReconciler) removeSharedServer(ctx context.Context, tool *langopv1alpha1.LanguageTool) error {
+	key := types.NamespacedName{Name: tool.Name, Namespace: tool.Namespace}
+	for _, obj := range []client.Object{&appsv1.Deployment{}, &corev1.Service{}} {
+		if err := r.Get(ctx, key, obj); err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return err
+		}
+		if !metav1.IsControlledBy(obj, tool) {
+			continue
+		}
+		if err := r.Delete(ctx, obj); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete shared tool server: %w", err)
+		}
+		log.FromContext(ctx).Info("Removed unreferenced shared tool server", "kind", fmt.Sprintf("%T", obj), "name", tool.Name)
+	}
+	return nil
+}
+
+// blockSharedToolDeletion keeps a shared tool in place while agents still reference it
+func (r *LanguageToolReconciler) blockSharedToolDeletion(ctx context.Context, tool *langopv1alpha1.LanguageTool, referencing []string) (ctrl.Result, error) {
+	message := fmt.Sprintf("Deletion blocked: shared tool is referenced by agents %s", strings.Join(referencing, ", "))
+	log.FromContext(ctx).Info("Blocking deletion of referenced shared tool", "referencingAgents", referencing)
+
+	tool.Status.ReferencingAgents = referencing
+	SetCondition(&tool.Status.Conditions, "DeletionBlocked", metav1.ConditionTrue, "ReferencingAgents", message, tool.Generation)
+	if err := r.Status().Update(ctx, tool); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: sharedToolDeletionRecheckInterval}, nil
+}
+
+// sharedToolsForAgent maps a LanguageAgent event to the shared tools it references, or referenced
+// at their last reconcile, so reference counts follow agents adding and dropping tools
+func (r *LanguageToolReconciler) sharedToolsForAgent(ctx context.Context, obj client.Object) []reconcile.Request {
+	agent, ok := obj.(*langopv1alpha1.LanguageAgent)
+	if !ok {
+		return nil
+	}
+
+	var requests []reconcile.Request
+	seen := map[types.NamespacedName]bool{}
+	enqueue := func(key types.NamespacedName) {
+		if !seen[key] {
+			seen[key] = true
+			requests = append(requests, reconcile.Request{NamespacedName: key})
+		}
+	}
+
+	for _, ref := range agentToolRefs(agent) {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = agent.Namespace
+		}
+		key := types.NamespacedName{Name: ref.Name, Namespace: namespace}
+		tool := &langopv1alpha1.LanguageTool{}
+		if err := r.Get(ctx, key, tool); err != nil {
+			if !errors.IsNotFound(err) {
+				log.FromContext(ctx).Error(err, "Failed to get tool for agent", "agent", agent.Name, "tool", key)
+			}
+			continue
+		}
+		if isSharedTool(tool) {
+			enqueue(key)
+		}
+	}
+
+	tools := &langopv1alpha1.LanguageToolList{}
+	if err := r.List(ctx, tools, client.MatchingFields{sharedToolReferencingAgentsField: agent.Namespace + "/" + agent.Name}); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list tools referenced by agent", "agent", agent.Name)
+		return requests
+	}
+	for _, tool := range tools.Items {
+		enqueue(types.NamespacedName{Name: tool.Name, Namespace: tool.Namespace})
+	}
+	return requests
+}
@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func newSharedTool() *langopv1alpha1.LanguageTool {
+	return &langopv1alpha1.LanguageTool{
+		ObjectMeta: metav1.ObjectMeta{Name: "search", Namespace: "tools"},
+		Spec: langopv1alpha1.LanguageToolSpec{
+			Type:           "mcp",
+			Image:          "test:latest",
+			DeploymentMode: toolDeploymentModeCluster,
+			Port:           8080,
+		},
+	}
+}
+
+func newSharedToolAgent(name, namespace string, tools ...string) *langopv1alpha1.LanguageAgent {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+	}
+	for _, tool := range tools {
+		agent.Spec.ToolRefs = append(agent.Spec.ToolRefs, langopv1alpha1.ToolReference{Name: tool, Namespace: "tools"})
+	}
+	return agent
+}
+
+func newSharedToolReconciler(t *testing.T, objects ...client.Object) (*LanguageToolReconciler, client.Client) {
+	scheme := testutil.SetupTestScheme(t)
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objects...).
+		WithStatusSubresource(&langopv1alpha1.LanguageTool{}).
+		WithIndex(&langopv1alpha1.LanguageTool{}, sharedToolReferencingAgentsField, sharedToolReferencingAgents).
+		Build()
+	return &LanguageToolReconciler{
+		Client:          fakeClient,
+		Scheme:          scheme,
+		RegistryManager: &mockRegistryManager{},
+	}, fakeClient
+}
+
+func reconcileSharedTool(t *testing.T, r *LanguageToolReconciler, c client.Client) (*langopv1alpha1.LanguageTool, ctrl.Result) {
+	t.Helper()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "search", Namespace: "tools"}
+	result, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	tool := &langopv1alpha1.LanguageTool{}
+	if err := c.Get(ctx, key, tool); err != nil {
+		if errors.IsNotFound(err) {
+			return nil, result
+		}
+		t.Fatalf("Failed to get tool: %v", err)
+	}
+	return tool, result
+}
+
+func sharedServerExists(t *testing.T, c client.Client) bool {
+	t.Helper()
+	ctx := context.Background()
+	key := types.NamespacedName{Name: "search", Namespace: "tools"}
+	deploymentErr := c.Get(ctx, key, &appsv1.Deployment{})
+	serviceErr := c.Get(ctx, key, &corev1.Service{})
+	for _, err := range []error{deploymentErr, serviceErr} {
+		if err != nil && !errors.IsNotFound(err) {
+			t.Fatalf("Failed to get shared server: %v", err)
+		}
+	}
+	if (deploymentErr == nil) != (serviceErr == nil) {
+		t.Fatalf("Expected the Deployment and Service to exist together, got %v and %v", deploymentErr, serviceErr)
+	}
+	return deploymentErr == nil
+}
+
+func TestLanguageToolController_SharedToolReferenceCounting(t *testing.T) {
+	ctx := context.Background()
+	reconciler, fakeClient := newSharedToolReconciler(t, newSharedTool(),
+		newSharedToolAgent("unrelated", "team-a", "other-tool"))
+
+	// Without referencing agents no server runs
+	tool, _ := reconcileSharedTool(t, reconciler, fakeClient)
+	if len(tool.Status.ReferencingAgents) != 0 {
+		t.Errorf("Expected no referencing agents, got %v", tool.Status.ReferencingAgents)
+	}
+	if sharedServerExists(t, fakeClient) {
+		t.Error("Expected no shared server without referencing agents")
+	}
+	if tool.Status.Phase != "Pending" {
+		t.Errorf("Expected phase Pending, got %s", tool.Status.Phase)
+	}
+
+	// Agents from any namespace add a reference
+	triage := newSharedToolAgent("triage", "team-a", "search")
+	digest := newSharedToolAgent("digest", "team-b", "search")
+	for _, agent := range []*langopv1alpha1.LanguageAgent{triage, digest} {
+		if err := fakeClient.Create(ctx, agent); err != nil {
+			t.Fatalf("Failed to create agent: %v", err)
+		}
+	}
+	tool, _ = reconcileSharedTool(t, reconciler, fakeClient)
+	expected := []string{"team-a/triage", "team-b/digest"}
+	if !reflect.DeepEqual(tool.Status.ReferencingAgents, expected) {
+		t.Errorf("Expected referencing agents %v, got %v", expected, tool.Status.ReferencingAgents)
+	}
+	if !sharedServerExists(t, fakeClient) {
+		t.Fatal("Expected a shared server once agents reference the tool")
+	}
+
+	// Dropping one reference keeps the server for the remaining agent
+	triage.Spec.ToolRefs = nil
+	if err := fakeClient.Update(ctx, triage); err != nil {
+		t.Fatalf("Failed to update agent: %v", err)
+	}
+	tool, _ = reconcileSharedTool(t, reconciler, fakeClient)
+	if !reflect.DeepEqual(tool.Status.ReferencingAgents, []string{"team-b/digest"}) {
+		t.Errorf("Expected only team-b/digest to reference the tool, got %v", tool.Status.ReferencingAgents)
+	}
+	if !sharedServerExists(t, fakeClient) {
+		t.Error("Expected the shared server to stay while an agent references it")
+	}
+
+	// The last reference going away removes the server
+	if err := fakeClient.Delete(ctx, digest); err != nil {
+		t.Fatalf("Failed to delete agent: %v", err)
+	}
+	tool, _ = reconcileSharedTool(t, reconciler, fakeClient)
+	if len(tool.Status.ReferencingAgents) != 0 {
+		t.Errorf("Expected no referencing agents, got %v", tool.Status.ReferencingAgents)
+	}
+	if sharedServerExists(t, fakeClient) {
+		t.Error("Expected the shared server to be removed when no agent references it")
+	}
+	if condition := meta.FindStatusCondition(tool.Status.Conditions, "Ready"); condition == nil || condition.Reason != "Unreferenced" {
+		t.Errorf("Expected Ready condition with reason Unreferenced, got %v", condition)
+	}
+}
+
+func TestLanguageToolController_SharedToolDeletionBlocked(t *testing.T) {
+	ctx := context.Background()
+	agent := newSharedToolAgent("triage", "team-a", "search")
+	reconciler, fakeClient := newSharedToolReconciler(t, newSharedTool(), agent)
+
+	tool, _ := reconcileSharedTool(t, reconciler, fakeClient)
+	if err := fakeClient.Delete(ctx, tool); err != nil {
+		t.Fatalf("Failed to delete tool: %v", err)
+	}
+
+	tool, result := reconcileSharedTool(t, reconciler, fakeClient)
+	if tool == nil {
+		t.Fatal("Expected deletion to be blocked while an agent references the tool")
+	}
+	if result.RequeueAfter != sharedToolDeletionRecheckInterval {
+		t.Errorf("Expected a recheck after %s, got %s", sharedToolDeletionRecheckInterval, result.RequeueAfter)
+	}
+	if !meta.IsStatusConditionTrue(tool.Status.Conditions, "DeletionBlocked") {
+		t.Error("Expected DeletionBlocked condition")
+	}
+	if !sharedServerExists(t, fakeClient) {
+		t.Error("Expected the shared server to keep running while deletion is blocked")
+	}
+
+	if err := fakeClient.Delete(ctx, agent); err != nil {
+		t.Fatalf("Failed to delete agent: %v", err)
+	}
+	if tool, _ = reconcileSharedTool(t, reconciler, fakeClient); tool != nil {
+		t.Error("Expected the tool to be deleted once no agent references it")
+	}
+}
+
+func TestLanguageToolController_SharedToolsForAgent(t *testing.T) {
+	referenced := newSharedTool()
+	dropped := newSharedTool()
+	dropped.Name = "fetch"
+	dropped.Status.ReferencingAgents = []string{"team-a/triage"}
+	service := newSharedTool()
+	service.Name = "service-tool"
+	service.Spec.DeploymentMode = "service"
+	reconciler, _ := newSharedToolReconciler(t, referenced, dropped, service)
+
+	agent := newSharedToolAgent("triage", "team-a", "search", "service-tool")
+	requests := reconciler.sharedToolsForAgent(context.Background(), agent)
+	var names []string
+	for _, request := range requests {
+		names = append(names, request.Name)
+	}
+	sort.Strings(names)
+	if !reflect.DeepEqual(names, []string{"fetch", "search"}) {
+		t.Errorf("Expected the referenced and previously referenced shared tools, got %v", names)
+	}
+}
+
+func TestLanguageAgentController_ResolveSharedTool(t *testing.T) {
+	agent := newSharedToolAgent("triage", "team-a", "search")
+	reconciler, _ := newForceWorkloadReconciler(t, newSharedTool(), agent)
+
+	urls, err := reconciler.resolveTools(context.Background(), agent)
+	if err != nil {
+		t.Fatalf("resolveTools failed: %v", err)
+	}
+	expected := []string{"http://search.tools.svc.cluster.local:8080"}
+	if !reflect.DeepEqual(urls, expected) {
+		t.Errorf("Expected shared tool URL %v, got %v", expected, urls)
+	}
+}
+
+func TestLanguageAgentController_SharedToolReadyAcrossNamespaces(t *testing.T) {
+	ctx := context.Background()
+	agent := newSharedToolAgent("triage", "team-a", "search")
+	toolReconciler, toolClient := newSharedToolReconciler(t, newSharedTool(), agent)
+
+	// The agent's events reach the shared tool in the tools namespace
+	requests := toolReconciler.sharedToolsForAgent(ctx, agent)
+	expected := []reconcile.Request{{NamespacedName: types.NamespacedName{Name: "search", Namespace: "tools"}}}
+	if !reflect.DeepEqual(requests, expected) {
+		t.Fatalf("Expected agent events to enqueue %v, got %v", expected, requests)
+	}
+
+	tool, _ := reconcileSharedTool(t, toolReconciler, toolClient)
+	if !reflect.DeepEqual(tool.Status.ReferencingAgents, []string{"team-a/triage"}) {
+		t.Fatalf("Expected the agent in team-a to be counted, got %v", tool.Status.ReferencingAgents)
+	}
+	if !sharedServerExists(t, toolClient) {
+		t.Fatal("Expected the shared server to start for the agent in team-a")
+	}
+
+	// The agent waits on the shared tool itself, not on a tool missing from its own namespace
+	tool.ResourceVersion = ""
+	agentReconciler, _ := newForceWorkloadReconciler(t, tool, agent)
+	unready, err := agentReconciler.unreadyTools(ctx, agent)
+	if err != nil {
+		t.Fatalf("unreadyTools failed: %v", err)
+	}
+	if !reflect.DeepEqual(unready, []string{"search (phase Updating)"}) {
+		t.Errorf("Expected the agent to wait for the updating shared tool, got %v", unready)
+	}
+
+	tool.Status.Phase = "Running"
+	tool.Status.ToolSchemas = []langopv1alpha1.ToolSchema{{Name: "search"}}
+	agentReconciler, _ = newForceWorkloadReconciler(t, tool, agent)
+	if unready, err = agentReconciler.unreadyTools(ctx, agent); err != nil || len(unready) != 0 {
+		t.Errorf("Expected the running shared tool to release the agent, got %v (err %v)", unready, err)
+	}
+}
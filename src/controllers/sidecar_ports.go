@@ -0,0 +1,60 @@
+package controllers
+
+import (
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// PortCollisionCondition is set on agents whose sidecar tools listen on a port already taken in the pod
+const PortCollisionCondition = "PortCollision"
+
+// validateSidecarPorts returns an error naming the containers that would bind the same port:
+// a sidecar on the agent's webhook port or on a port spec.webhookRoutes sends to the agent
+// container, or two sidecars on one port
+func validateSidecarPorts(sidecars []corev1.Container, routes []langopv1alpha1.WebhookRouteRule) error {
+	owners := map[int32]string{agentWebhookPort: "agent webhook server"}
+	for _, route := range routes {
+		if _, taken := owners[route.Port]; !taken && route.Port != 0 {
+			owners[route.Port] = fmt.Sprintf("agent webhook route %s", route.PathPrefix)
+		}
+	}
+	var conflicts []string
+	for _, container := range sidecars {
+		for _, port := range container.Ports {
+			if owner, taken := owners[port.ContainerPort]; taken {
+				conflicts = append(conflicts, fmt.Sprintf("%s and %s both use port %d", container.Name, owner, port.ContainerPort))
+				continue
+			}
+			owners[port.ContainerPort] = container.Name
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("sidecar port collision: %s", strings.Join(conflicts, "; "))
+	}
+	return nil
+}
+
+// checkSidecarPorts records a PortCollision condition when the agent's sidecar tools can't all bind
+// their ports, so the workload isn't rolled out to crash-loop on bind. The collision is retried on
+// every reconcile, so an event is only emitted when it first appears or changes, and when it's resolved.
+func (r *LanguageAgentReconciler) checkSidecarPorts(agent *langopv1alpha1.LanguageAgent, sidecars []corev1.Container) error {
+	if err := validateSidecarPorts(sidecars, agent.Spec.WebhookRoutes); err != nil {
+		changed := SetCondition(&agent.Status.Conditions, PortCollisionCondition, metav1.ConditionTrue, "SidecarPortConflict",
+			err.Error()+". Set a distinct spec.port on the conflicting LanguageTools, or route spec.webhookRoutes to another port of the agent container",
+			agent.Generation)
+		if changed && r.Recorder != nil {
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "PortCollision", "%s", err.Error())
+		}
+		return err
+	}
+	if meta.RemoveStatusCondition(&agent.Status.Conditions, PortCollisionCondition) && r.Recorder != nil {
+		r.Recorder.Event(agent, corev1.EventTypeNormal, "PortCollisionResolved", "Sidecar tools no longer share a port")
+	}
+	return nil
+}
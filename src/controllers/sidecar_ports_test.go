@@ -0,0 +1,137 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func sidecarOnPort(name string, port int32) corev1.Container {
+	return corev1.Container{
+		Name:  "tool-" + name,
+		Ports: []corev1.ContainerPort{{Name: "mcp", ContainerPort: port, Protocol: corev1.ProtocolTCP}},
+	}
+}
+
+func TestValidateSidecarPorts(t *testing.T) {
+	tests := []struct {
+		name        string
+		sidecars    []corev1.Container
+		routes      []langopv1alpha1.WebhookRouteRule
+		expectError string
+	}{
+		{name: "no sidecars"},
+		{name: "distinct ports", sidecars: []corev1.Container{sidecarOnPort("search", 3000), sidecarOnPort("github", 3001)}},
+		{
+			name:        "sidecar on the agent webhook port",
+			sidecars:    []corev1.Container{sidecarOnPort("search", 8080)},
+			expectError: "tool-search and agent webhook server both use port 8080",
+		},
+		{
+			name:     "webhook route to the agent container",
+			sidecars: []corev1.Container{sidecarOnPort("search", 3000)},
+			routes:   []langopv1alpha1.WebhookRouteRule{{PathPrefix: "/webhook"}, {PathPrefix: "/api", Port: 9090}},
+		},
+		{
+			name:        "webhook route to a sidecar port",
+			sidecars:    []corev1.Container{sidecarOnPort("search", 3000)},
+			routes:      []langopv1alpha1.WebhookRouteRule{{PathPrefix: "/search", Port: 3000}},
+			expectError: "tool-search and agent webhook route /search both use port 3000",
+		},
+		{
+			name:        "two sidecars on one port",
+			sidecars:    []corev1.Container{sidecarOnPort("search", 3000), sidecarOnPort("github", 3000)},
+			expectError: "tool-github and tool-search both use port 3000",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateSidecarPorts(tt.sidecars, tt.routes)
+			if tt.expectError == "" {
+				if err != nil {
+					t.Errorf("Expected no collision, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+				t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+			}
+		})
+	}
+}
+
+func TestReconcileDeployment_SidecarPortCollision(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	ctx := context.Background()
+
+	// Both tools leave spec.port unset and default to the agent's webhook port
+	tool := &langopv1alpha1.LanguageTool{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-search", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageToolSpec{Image: "ghcr.io/language-operator/web-tool:latest", DeploymentMode: "sidecar"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "colliding-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			ToolRefs:      []langopv1alpha1.ToolReference{{Name: "web-search"}},
+		},
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tool, agent).Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard(), Recorder: recorder}
+
+	err := reconciler.reconcileDeployment(ctx, agent)
+	if err == nil || !strings.Contains(err.Error(), "port 8080") {
+		t.Fatalf("Expected a port collision error naming port 8080, got %v", err)
+	}
+	collision := meta.FindStatusCondition(agent.Status.Conditions, PortCollisionCondition)
+	if collision == nil || collision.Status != metav1.ConditionTrue {
+		t.Fatalf("Expected %s condition, got %+v", PortCollisionCondition, collision)
+	}
+	if !strings.Contains(collision.Message, "tool-web-search") {
+		t.Errorf("Expected the condition to name the conflicting sidecar, got %q", collision.Message)
+	}
+	if !hasEvent(drainEvents(recorder), "PortCollision") {
+		t.Error("Expected PortCollision event")
+	}
+
+	// Retrying the same collision doesn't repeat the event
+	if err := reconciler.reconcileDeployment(ctx, agent); err == nil {
+		t.Fatal("Expected the port collision to persist")
+	}
+	if events := drainEvents(recorder); hasEvent(events, "PortCollision") {
+		t.Errorf("Expected no repeated PortCollision event, got %v", events)
+	}
+	deployment := &appsv1.Deployment{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, deployment); err == nil {
+		t.Error("Expected no Deployment to be created for colliding ports")
+	}
+
+	// Moving the tool to its own port clears the condition
+	tool.Spec.Port = 3000
+	if err := fakeClient.Update(ctx, tool); err != nil {
+		t.Fatalf("Failed to update tool: %v", err)
+	}
+	if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+		t.Fatalf("reconcileDeployment failed: %v", err)
+	}
+	if meta.FindStatusCondition(agent.Status.Conditions, PortCollisionCondition) != nil {
+		t.Error("Expected the PortCollision condition to be cleared")
+	}
+	if !hasEvent(drainEvents(recorder), "PortCollisionResolved") {
+		t.Error("Expected PortCollisionResolved event")
+	}
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// agentStatusBatch tracks the agent status last written to the API server, so the status
+// changes made while reconciling reach it in one write instead of one write per step
+type agentStatusBatch struct {
+	persisted *langopv1alpha1.LanguageAgentStatus
+}
+
+// newAgentStatusBatch starts a batch from the status the agent was read with
+func newAgentStatusBatch(agent *langopv1alpha1.LanguageAgent) *agentStatusBatch {
+	return &agentStatusBatch{persisted: agent.Status.DeepCopy()}
+}
+
+// pending reports whether the agent status has changes that haven't been written
+func (b *agentStatusBatch) pending(agent *langopv1alpha1.LanguageAgent) bool {
+	return !equality.Semantic.DeepEqual(b.persisted, &agent.Status)
+}
+
+// flush writes the agent status if it has pending changes
+func (b *agentStatusBatch) flush(ctx context.Context, c client.Client, agent *langopv1alpha1.LanguageAgent) error {
+	if !b.pending(agent) {
+		return nil
+	}
+	if err := c.Status().Update(ctx, agent); err != nil {
+		return err
+	}
+	b.persisted = agent.Status.DeepCopy()
+	return nil
+}
+
+// updateStatus writes the agent status during a reconcile step. With BatchStatusUpdates the
+// write is left to the single flush at the end of Reconcile.
+func (r *LanguageAgentReconciler) updateStatus(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
+	if r.BatchStatusUpdates {
+		return nil
+	}
+	return r.Status().Update(ctx, agent)
+}
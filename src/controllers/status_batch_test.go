@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestLanguageAgentController_BatchStatusUpdates(t *testing.T) {
+	tests := []struct {
+		name           string
+		batch          bool
+		expectedWrites int
+	}{
+		// One write per unhealthy pod, one for the UUID, and one for the final phase
+		{name: "unbatched writes per step", batch: false, expectedWrites: 4},
+		// The UUID write carries the pod failures, the final write carries the phase
+		{name: "batched writes once per persisted value", batch: true, expectedWrites: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			agent := newSelfHealingTestAgent()
+			podA := newUnreadyPod("unhealthy-agent-a", agent, time.Now().Add(-10*time.Minute))
+			podB := newUnreadyPod("unhealthy-agent-b", agent, time.Now().Add(-8*time.Minute))
+
+			statusWrites := 0
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent, podA, podB).
+				WithStatusSubresource(agent).
+				WithInterceptorFuncs(interceptor.Funcs{
+					SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						statusWrites++
+						return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+					},
+				}).
+				Build()
+
+			reconciler := &LanguageAgentReconciler{
+				Client:                 fakeClient,
+				Scheme:                 scheme,
+				Log:                    logr.Discard(),
+				Recorder:               record.NewFakeRecorder(20),
+				RegistryManager:        &mockRegistryManager{},
+				SelfHealingEnabled:     true,
+				MaxSelfHealingAttempts: 5,
+				UnhealthyThreshold:     5 * time.Minute,
+				BatchStatusUpdates:     tt.batch,
+			}
+			reconciler.InitializeGatewayCache()
+
+			ctx := context.Background()
+			key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+			if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+				t.Fatalf("Reconcile failed: %v", err)
+			}
+			if statusWrites != tt.expectedWrites {
+				t.Errorf("Expected %d status writes, got %d", tt.expectedWrites, statusWrites)
+			}
+
+			// Every accumulated change must have reached the API server
+			updated := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, key, updated); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			if updated.Status.UUID == "" {
+				t.Error("Expected the UUID to be persisted")
+			}
+			if updated.Status.ConsecutiveFailures != 2 {
+				t.Errorf("Expected 2 persisted consecutive failures, got %d", updated.Status.ConsecutiveFailures)
+			}
+			if updated.Status.Phase != "Running" {
+				t.Errorf("Expected persisted phase Running, got %q", updated.Status.Phase)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_BatchStatusUpdates_NoChangeNoWrite(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	agent := newSelfHealingTestAgent()
+
+	statusWrites := 0
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent).
+		WithStatusSubresource(agent).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+				statusWrites++
+				return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+			},
+		}).
+		Build()
+
+	reconciler := &LanguageAgentReconciler{
+		Client:             fakeClient,
+		Scheme:             scheme,
+		Log:                logr.Discard(),
+		Recorder:           record.NewFakeRecorder(20),
+		RegistryManager:    &mockRegistryManager{},
+		BatchStatusUpdates: true,
+	}
+	reconciler.InitializeGatewayCache()
+
+	ctx := context.Background()
+	req := ctrl.Request{NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}}
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+
+	// A steady-state reconcile has nothing to write
+	statusWrites = 0
+	if _, err := reconciler.Reconcile(ctx, req); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if statusWrites != 0 {
+		t.Errorf("Expected no status writes for an unchanged agent, got %d", statusWrites)
+	}
+}
+
+func TestAgentStatusBatch_Flush(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	agent := newSelfHealingTestAgent()
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).WithStatusSubresource(agent).Build()
+
+	ctx := context.Background()
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	batch := newAgentStatusBatch(agent)
+	if batch.pending(agent) {
+		t.Fatal("Expected no pending changes for a freshly read agent")
+	}
+
+	agent.Status.Phase = "Running"
+	SetCondition(&agent.Status.Conditions, "Ready", metav1.ConditionTrue, "ReconcileSuccess", "LanguageAgent is ready", agent.Generation)
+	if !batch.pending(agent) {
+		t.Fatal("Expected pending changes after modifying the status")
+	}
+	if err := batch.flush(ctx, fakeClient, agent); err != nil {
+		t.Fatalf("flush failed: %v", err)
+	}
+	if batch.pending(agent) {
+		t.Error("Expected no pending changes after flushing")
+	}
+}
@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// synthesisCacheKey keys the synthesis cache by the agent and the hashes that drive change
+// detection, so cached code is only reused for the inputs it was synthesized from
+func (r *LanguageAgentReconciler) synthesisCacheKey(agent *langopv1alpha1.LanguageAgent) string {
+	return synthesis.CacheKey(agent.Namespace, agent.Name,
+		hashString(r.synthesisInstructions(agent)),
+		hashString(strings.Join(r.getToolNames(agent), ",")),
+		hashString(strings.Join(r.getModelNames(agent), ",")),
+		hashString(strings.Join(r.getPersonaNames(agent), ",")))
+}
+
+// cachedSynthesis returns previously synthesized code for the agent's current inputs. Cache
+// errors are logged and treated as misses so synthesis can still proceed.
+func (r *LanguageAgentReconciler) cachedSynthesis(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (string, bool) {
+	if r.SynthesisCache == nil {
+		return "", false
+	}
+	code, ok, err := r.SynthesisCache.Get(ctx, r.synthesisCacheKey(agent))
+	if err != nil {
+		log.FromContext(ctx).Error(err, "Failed to read synthesis cache, synthesizing instead")
+		return "", false
+	}
+	return code, ok && code != ""
+}
+
+// cacheSynthesis stores synthesized code for the agent's current inputs
+func (r *LanguageAgentReconciler) cacheSynthesis(ctx context.Context, agent *langopv1alpha1.LanguageAgent, code string) {
+	if r.SynthesisCache == nil {
+		return
+	}
+	if err := r.SynthesisCache.Put(ctx, r.synthesisCacheKey(agent), code); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to store synthesized code in the synthesis cache")
+	}
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestLanguageAgentController_SynthesisCacheHit(t *testing.T) {
+	ctx := context.Background()
+	scheme := testutil.SetupTestScheme(t)
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "daily-report", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Instructions:  "Summarize open issues every morning",
+			ExecutionMode: "autonomous",
+		},
+	}
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent).
+		WithStatusSubresource(agent).
+		Build()
+	recorder := record.NewFakeRecorder(10)
+	reconciler := &LanguageAgentReconciler{
+		Client:         fakeClient,
+		Scheme:         scheme,
+		Log:            logr.Discard(),
+		Recorder:       recorder,
+		SynthesisCache: synthesis.NewMemoryCache(time.Hour, 10),
+	}
+
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	cachedCode := "agent \"daily-report\" do\n  mode :autonomous\nend\n"
+	if err := reconciler.SynthesisCache.Put(ctx, reconciler.synthesisCacheKey(agent), cachedCode); err != nil {
+		t.Fatalf("Failed to populate cache: %v", err)
+	}
+
+	// No synthesis model is configured, so anything but a cache hit would fail
+	if err := reconciler.reconcileCodeConfigMap(ctx, agent); err != nil {
+		t.Fatalf("Expected cached code to be used, got: %v", err)
+	}
+
+	codeConfigMap := &corev1.ConfigMap{}
+	key := types.NamespacedName{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace}
+	if err := fakeClient.Get(ctx, key, codeConfigMap); err != nil {
+		t.Fatalf("Expected code ConfigMap to be created: %v", err)
+	}
+	if codeConfigMap.Data["agent.rb"] != cachedCode {
+		t.Errorf("Expected cached code in ConfigMap, got %q", codeConfigMap.Data["agent.rb"])
+	}
+	if !hasEvent(drainEvents(recorder), "SynthesisCacheHit") {
+		t.Error("Expected SynthesisCacheHit event")
+	}
+	if agent.Status.SynthesisInfo == nil || agent.Status.SynthesisInfo.CodeHash != hashString(cachedCode) {
+		t.Errorf("Expected code hash of the cached code in status, got %+v", agent.Status.SynthesisInfo)
+	}
+}
+
+func TestLanguageAgentController_SynthesisCacheKeyChangesWithInputs(t *testing.T) {
+	reconciler := &LanguageAgentReconciler{}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "daily-report", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{Instructions: "Summarize open issues every morning"},
+	}
+	original := reconciler.synthesisCacheKey(agent)
+
+	agent.Spec.Instructions = "Summarize closed issues every evening"
+	if reconciler.synthesisCacheKey(agent) == original {
+		t.Error("Expected changed instructions to change the cache key")
+	}
+
+	agent.Spec.Instructions = "Summarize open issues every morning"
+	agent.Namespace = "other"
+	if reconciler.synthesisCacheKey(agent) == original {
+		t.Error("Expected agents in other namespaces to have their own cache key")
+	}
+}
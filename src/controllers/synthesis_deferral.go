@@ -0,0 +1,55 @@
+package controllers
+
+import (
+	"fmt"
+	"time"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// synthesisSlotRetryDelay is how long an agent waits to retry synthesis after every synthesis slot
+// stayed busy
+const synthesisSlotRetryDelay = 15 * time.Second
+
+// synthesisDeferredError defers synthesis to a later reconcile, e.g. until the agent's tools are
+// ready or a synthesis slot is free
+type synthesisDeferredError struct {
+	// wait is how long to wait before trying again
+	wait time.Duration
+	// synthesized is whether the agent already has code to run while it waits
+	synthesized bool
+	// cause says what synthesis is waiting for
+	cause string
+}
+
+func (e *synthesisDeferredError) Error() string {
+	return fmt.Sprintf("deferring synthesis %s: %s", e.wait, e.cause)
+}
+
+// isSynthesisDeferred reports whether err defers synthesis to a later reconcile
+func isSynthesisDeferred(err error) (*synthesisDeferredError, bool) {
+	deferredErr, ok := err.(*synthesisDeferredError)
+	return deferredErr, ok
+}
+
+// deferOnBusySlots turns a synthesis request that found no free synthesis slot into a deferral, so
+// the reconcile worker is released and synthesis is retried later. Other errors are returned as is.
+func deferOnBusySlots(err error, synthesized bool) error {
+	if !synthesis.IsNoSynthesisSlot(err) {
+		return err
+	}
+	return &synthesisDeferredError{wait: synthesisSlotRetryDelay, synthesized: synthesized, cause: "no synthesis slot free"}
+}
+
+// deferSelfHealing defers a self-healing synthesis that found no free synthesis slot, giving back
+// the attempt it was counted as. The agent keeps running its current code meanwhile.
+func (r *LanguageAgentReconciler) deferSelfHealing(agent *langopv1alpha1.LanguageAgent, err error) error {
+	if !synthesis.IsNoSynthesisSlot(err) {
+		return err
+	}
+	if agent.Status.SelfHealingAttempts > 0 {
+		agent.Status.SelfHealingAttempts--
+	}
+	return deferOnBusySlots(err, true)
+}
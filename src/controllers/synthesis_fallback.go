@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// synthesisModelUnreachable reports whether the model's last provider health check failed
+func synthesisModelUnreachable(model *langopv1alpha1.LanguageModel) bool {
+	return meta.IsStatusConditionFalse(model.Status.Conditions, ModelReachableCondition)
+}
+
+// synthesisModelChoice is a model synthesis can use, with the namespace/name of the cluster
+// fallback model it is, or empty for the agent's own synthesis model
+type synthesisModelChoice struct {
+	model    *langopv1alpha1.LanguageModel
+	fallback string
+}
+
+// synthesisModelChain returns the models to synthesize with, in the order to try them. The agent's
+// own synthesis model comes first unless it is missing or unreachable, followed by the reachable
+// models in the referenced cluster's spec.synthesisFallbackModels. If none of them is usable the
+// agent's own model, or its error, is returned.
+func (r *LanguageAgentReconciler) synthesisModelChain(ctx context.Context, agent *langopv1alpha1.LanguageAgent) ([]synthesisModelChoice, error) {
+	var chain []synthesisModelChoice
+	model, modelErr := r.getSynthesisModel(ctx, agent)
+	if modelErr == nil && !synthesisModelUnreachable(model) {
+		chain = append(chain, synthesisModelChoice{model: model})
+	}
+
+	if agent.Spec.ClusterRef != "" {
+		cluster := &langopv1alpha1.LanguageCluster{}
+		if err := r.Get(ctx, types.NamespacedName{Name: agent.Spec.ClusterRef, Namespace: agent.Namespace}, cluster); err != nil {
+			if !errors.IsNotFound(err) {
+				log.FromContext(ctx).V(1).Info("Failed to get cluster for synthesis fallback", "cluster", agent.Spec.ClusterRef, "error", err.Error())
+			}
+		} else {
+			for _, ref := range cluster.Spec.SynthesisFallbackModels {
+				namespace := ref.Namespace
+				if namespace == "" {
+					namespace = agent.Namespace
+				}
+				fallback := &langopv1alpha1.LanguageModel{}
+				if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, fallback); err != nil {
+					log.FromContext(ctx).V(1).Info("Skipping synthesis fallback model", "model", ref.Name, "namespace", namespace, "error", err.Error())
+					continue
+				}
+				if synthesisModelUnreachable(fallback) {
+					continue
+				}
+				chain = append(chain, synthesisModelChoice{model: fallback, fallback: fmt.Sprintf("%s/%s", namespace, ref.Name)})
+			}
+		}
+	}
+
+	if len(chain) == 0 {
+		if modelErr != nil {
+			return nil, modelErr
+		}
+		chain = append(chain, synthesisModelChoice{model: model})
+	}
+	return chain, nil
+}
+
+// resolveSynthesisModel returns the first model of the agent's synthesis model chain and, when it
+// is a cluster fallback model, its namespace/name
+func (r *LanguageAgentReconciler) resolveSynthesisModel(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (*langopv1alpha1.LanguageModel, string, error) {
+	chain, err := r.synthesisModelChain(ctx, agent)
+	if err != nil {
+		return nil, "", err
+	}
+	return chain[0].model, chain[0].fallback, nil
+}
+
+// synthesizeAlongChain runs synthesize with a synthesizer for each model of the chain in turn,
+// moving on to the next model when the synthesizer can't be created or the call fails. A
+// response that fails validation is returned as is, since another model was reached and answered.
+// Returns the response and error of the last model tried, and that model.
+func (r *LanguageAgentReconciler) synthesizeAlongChain(ctx context.Context, agent *langopv1alpha1.LanguageAgent, chain []synthesisModelChoice,
+	synthesize func(synthesis.AgentSynthesizer) (*synthesis.AgentSynthesisResponse, error)) (*synthesis.AgentSynthesisResponse, *langopv1alpha1.LanguageModel, error) {
+	var lastErr error
+	for i, choice := range chain {
+		last := i == len(chain)-1
+		synthesizer, err := r.newSynthesizer(ctx, agent, choice.model)
+		if err != nil {
+			lastErr = fmt.Errorf("failed to create synthesizer: %w", err)
+			if last {
+				break
+			}
+			log.FromContext(ctx).Info("Skipping synthesis model", "model", choice.model.Name, "error", err.Error())
+			continue
+		}
+		r.recordSynthesisFallback(agent, choice.fallback)
+
+		resp, err := synthesize(synthesizer)
+		if err == nil || last || ctx.Err() != nil {
+			return resp, choice.model, err
+		}
+		lastErr = err
+		log.FromContext(ctx).Info("Synthesis failed, trying the next model in the fallback chain",
+			"model", choice.model.Name, "next", chain[i+1].fallback, "error", err.Error())
+		if r.Recorder != nil {
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "SynthesisModelFailed",
+				"Synthesis with model %s failed, retrying with cluster fallback model %s: %v", choice.model.Name, chain[i+1].fallback, err)
+		}
+	}
+	return nil, nil, lastErr
+}
+
+// recordSynthesisFallback records in status which cluster fallback model synthesis used, emitting
+// an event when the agent switches to a fallback
+func (r *LanguageAgentReconciler) recordSynthesisFallback(agent *langopv1alpha1.LanguageAgent, fallback string) {
+	if agent.Status.SynthesisInfo == nil {
+		if fallback == "" {
+			return
+		}
+		agent.Status.SynthesisInfo = &langopv1alpha1.SynthesisInfo{}
+	}
+	previous := agent.Status.SynthesisInfo.SynthesisFallbackModel
+	agent.Status.SynthesisInfo.SynthesisFallbackModel = fallback
+	if fallback == "" || fallback == previous || r.Recorder == nil {
+		return
+	}
+	r.Recorder.Eventf(agent, corev1.EventTypeWarning, "SynthesisModelFallback",
+		"Agent synthesis model is unavailable, synthesizing with cluster fallback model %s", fallback)
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// synthesisFallbackModel returns a LanguageModel whose last health check reported the given status
+func synthesisFallbackModel(name, namespace, modelName string, reachable metav1.ConditionStatus) *langopv1alpha1.LanguageModel {
+	return &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai", ModelName: modelName},
+		Status: langopv1alpha1.LanguageModelStatus{
+			Conditions: []metav1.Condition{{Type: ModelReachableCondition, Status: reachable, Reason: "HealthCheck"}},
+		},
+	}
+}
+
+func TestLanguageAgentController_SynthesisFallback(t *testing.T) {
+	tests := []struct {
+		name             string
+		primary          *langopv1alpha1.LanguageModel
+		fallbacks        []langopv1alpha1.ModelReference
+		expectModel      string
+		expectFallback   string
+		expectErr        bool
+		expectEventCount int
+	}{
+		{
+			name:        "reachable primary is used",
+			primary:     synthesisFallbackModel("primary", "default", "gpt-4o", metav1.ConditionTrue),
+			fallbacks:   []langopv1alpha1.ModelReference{{Name: "backup"}},
+			expectModel: "gpt-4o",
+		},
+		{
+			name:             "unreachable primary falls back to the first reachable cluster model",
+			primary:          synthesisFallbackModel("primary", "default", "gpt-4o", metav1.ConditionFalse),
+			fallbacks:        []langopv1alpha1.ModelReference{{Name: "down"}, {Name: "missing"}, {Name: "backup"}},
+			expectModel:      "claude-sonnet",
+			expectFallback:   "default/backup",
+			expectEventCount: 1,
+		},
+		{
+			name:             "missing primary falls back",
+			fallbacks:        []langopv1alpha1.ModelReference{{Name: "shared", Namespace: "models"}},
+			expectModel:      "llama-3",
+			expectFallback:   "models/shared",
+			expectEventCount: 1,
+		},
+		{
+			name:        "unreachable primary is kept without a usable fallback",
+			primary:     synthesisFallbackModel("primary", "default", "gpt-4o", metav1.ConditionFalse),
+			fallbacks:   []langopv1alpha1.ModelReference{{Name: "down"}},
+			expectModel: "gpt-4o",
+		},
+		{
+			name:      "missing primary without a usable fallback fails",
+			fallbacks: []langopv1alpha1.ModelReference{{Name: "missing"}},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cluster := &langopv1alpha1.LanguageCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "default"},
+				Spec:       langopv1alpha1.LanguageClusterSpec{SynthesisFallbackModels: tt.fallbacks},
+			}
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "reviewer", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					ClusterRef: "platform",
+					ModelRefs:  []langopv1alpha1.ModelReference{{Name: "primary"}},
+				},
+			}
+			objects := []client.Object{cluster, agent,
+				synthesisFallbackModel("down", "default", "gpt-4o-mini", metav1.ConditionFalse),
+				synthesisFallbackModel("backup", "default", "claude-sonnet", metav1.ConditionTrue),
+				synthesisFallbackModel("shared", "models", "llama-3", metav1.ConditionUnknown),
+			}
+			if tt.primary != nil {
+				objects = append(objects, tt.primary)
+			}
+			reconciler, _ := newForceWorkloadReconciler(t, objects...)
+			recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+			ctx := context.Background()
+			_, modelName, err := reconciler.createSynthesizer(ctx, agent)
+			if tt.expectErr {
+				if err == nil {
+					t.Fatalf("Expected an error, got model %q", modelName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("createSynthesizer failed: %v", err)
+			}
+			if modelName != tt.expectModel {
+				t.Errorf("Expected synthesis model %q, got %q", tt.expectModel, modelName)
+			}
+			fallback := ""
+			if agent.Status.SynthesisInfo != nil {
+				fallback = agent.Status.SynthesisInfo.SynthesisFallbackModel
+			}
+			if fallback != tt.expectFallback {
+				t.Errorf("Expected fallback model %q, got %q", tt.expectFallback, fallback)
+			}
+
+			events := drainEvents(recorder)
+			if len(events) != tt.expectEventCount {
+				t.Fatalf("Expected %d events, got %v", tt.expectEventCount, events)
+			}
+			if tt.expectEventCount > 0 && !strings.Contains(events[0], "SynthesisModelFallback") {
+				t.Errorf("Expected a SynthesisModelFallback event, got %q", events[0])
+			}
+
+			// Staying on the same fallback doesn't repeat the event
+			if _, _, err := reconciler.createSynthesizer(ctx, agent); err != nil {
+				t.Fatalf("createSynthesizer failed: %v", err)
+			}
+			if events := drainEvents(recorder); len(events) != 0 {
+				t.Errorf("Expected no events for an unchanged fallback, got %v", events)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_SynthesisFallbackCleared(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviewer", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ModelRefs: []langopv1alpha1.ModelReference{{Name: "primary"}},
+		},
+		Status: langopv1alpha1.LanguageAgentStatus{
+			SynthesisInfo: &langopv1alpha1.SynthesisInfo{SynthesisFallbackModel: "default/backup"},
+		},
+	}
+	primary := synthesisFallbackModel("primary", "default", "gpt-4o", metav1.ConditionTrue)
+	reconciler, _ := newForceWorkloadReconciler(t, agent, primary)
+
+	if _, _, err := reconciler.createSynthesizer(context.Background(), agent); err != nil {
+		t.Fatalf("createSynthesizer failed: %v", err)
+	}
+	if got := agent.Status.SynthesisInfo.SynthesisFallbackModel; got != "" {
+		t.Errorf("Expected the fallback model to be cleared once the primary recovers, got %q", got)
+	}
+}
+
+func TestLanguageAgentController_SynthesisFallbackRetry(t *testing.T) {
+	cluster := &langopv1alpha1.LanguageCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "platform", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageClusterSpec{
+			SynthesisFallbackModels: []langopv1alpha1.ModelReference{{Name: "down"}, {Name: "backup"}, {Name: "shared", Namespace: "models"}},
+		},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviewer", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ClusterRef: "platform",
+			ModelRefs:  []langopv1alpha1.ModelReference{{Name: "primary"}},
+		},
+	}
+	reconciler, _ := newForceWorkloadReconciler(t, cluster, agent,
+		synthesisFallbackModel("primary", "default", "gpt-4o", metav1.ConditionTrue),
+		synthesisFallbackModel("down", "default", "gpt-4o-mini", metav1.ConditionFalse),
+		synthesisFallbackModel("backup", "default", "claude-sonnet", metav1.ConditionTrue),
+		synthesisFallbackModel("shared", "models", "llama-3", metav1.ConditionUnknown),
+	)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	ctx := context.Background()
+
+	chain, err := reconciler.synthesisModelChain(ctx, agent)
+	if err != nil {
+		t.Fatalf("synthesisModelChain failed: %v", err)
+	}
+	if len(chain) != 3 {
+		t.Fatalf("Expected the primary and two reachable fallbacks, got %d models", len(chain))
+	}
+
+	// A failed call moves on to the next model; a response, even an invalid one, ends the chain
+	calls := 0
+	resp, model, err := reconciler.synthesizeAlongChain(ctx, agent, chain, func(synthesis.AgentSynthesizer) (*synthesis.AgentSynthesisResponse, error) {
+		calls++
+		if calls == 1 {
+			return nil, fmt.Errorf("provider unavailable")
+		}
+		return &synthesis.AgentSynthesisResponse{Error: "invalid DSL"}, nil
+	})
+	if err != nil || resp == nil {
+		t.Fatalf("Expected the fallback model's response, got %v", err)
+	}
+	if calls != 2 || model.Spec.ModelName != "claude-sonnet" {
+		t.Errorf("Expected synthesis to stop at the first fallback, got %d calls with %q", calls, model.Spec.ModelName)
+	}
+	if got := agent.Status.SynthesisInfo.SynthesisFallbackModel; got != "default/backup" {
+		t.Errorf("Expected fallback model default/backup, got %q", got)
+	}
+	events := drainEvents(recorder)
+	if !hasEvent(events, "SynthesisModelFailed") || !hasEvent(events, "SynthesisModelFallback") {
+		t.Errorf("Expected SynthesisModelFailed and SynthesisModelFallback events, got %v", events)
+	}
+
+	// When every model fails the last error is returned
+	calls = 0
+	_, _, err = reconciler.synthesizeAlongChain(ctx, agent, chain, func(synthesis.AgentSynthesizer) (*synthesis.AgentSynthesisResponse, error) {
+		calls++
+		return nil, fmt.Errorf("failure %d", calls)
+	})
+	if calls != 3 || err == nil || err.Error() != "failure 3" {
+		t.Errorf("Expected all three models to be tried and the last error returned, got %d calls and %v", calls, err)
+	}
+}
@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// SynthesisPlanAnnotation on a LanguageAgent synthesizes its code once without applying it and
+// writes a diff against the code it runs to the <agent>-plan ConfigMap. Each distinct value
+// plans once, so setting it to a timestamp asks for a fresh plan.
+const SynthesisPlanAnnotation = "langop.io/synthesis-plan"
+
+// synthesisPlanTriggerAnnotation records on the plan ConfigMap the plan annotation value it answers
+const synthesisPlanTriggerAnnotation = "langop.io/synthesis-plan-trigger"
+
+// Keys of the agent's plan ConfigMap
+const (
+	// synthesisPlanDiffKey holds the unified diff from the current to the planned agent.rb
+	synthesisPlanDiffKey = "agent.diff"
+	// synthesisPlanSummaryKey holds a one-line summary of the change, including mode and schedule changes
+	synthesisPlanSummaryKey = "summary"
+	// synthesisPlanJSONKey holds the plan as JSON: {old, new, diff, mode_changed}
+	synthesisPlanJSONKey = "plan.json"
+)
+
+// reconcileSynthesisPlan plans the agent's code when its plan annotation carries a value the
+// plan ConfigMap doesn't answer yet. code is the code the agent currently runs.
+func (r *LanguageAgentReconciler) reconcileSynthesisPlan(ctx context.Context, agent *langopv1alpha1.LanguageAgent, code string) error {
+	trigger := agent.Annotations[SynthesisPlanAnnotation]
+	if trigger == "" {
+		return nil
+	}
+
+	existing := &corev1.ConfigMap{}
+	err := r.Get(ctx, types.NamespacedName{Name: GenerateConfigMapName(agent.Name, "plan"), Namespace: agent.Namespace}, existing)
+	if err != nil && !errors.IsNotFound(err) {
+		return err
+	}
+	if err == nil && existing.Annotations[synthesisPlanTriggerAnnotation] == trigger {
+		return nil
+	}
+
+	synthesizer, _, err := r.createSynthesizer(ctx, agent)
+	if err != nil {
+		return fmt.Errorf("failed to create synthesizer: %w", err)
+	}
+	return r.writeSynthesisPlan(ctx, agent, synthesizer, code, trigger)
+}
+
+// writeSynthesisPlan synthesizes the agent's code without applying it and stores its diff against
+// code in the agent's plan ConfigMap. A failed plan is recorded too, so the paid model call isn't
+// repeated until the plan annotation changes.
+func (r *LanguageAgentReconciler) writeSynthesisPlan(ctx context.Context, agent *langopv1alpha1.LanguageAgent, synthesizer synthesis.AgentSynthesizer, code, trigger string) error {
+	log := log.FromContext(ctx)
+	planConfigMapName := GenerateConfigMapName(agent.Name, "plan")
+	annotations := map[string]string{
+		synthesisPlanTriggerAnnotation: trigger,
+		"langop.io/synthesized-at":     metav1.Now().Format("2006-01-02T15:04:05Z"),
+	}
+
+	planned, err := r.synthesizePlannedCode(ctx, agent, synthesizer)
+	if synthesis.IsNoSynthesisSlot(err) {
+		// Not a failure: the next reconcile plans once a slot is free
+		log.V(1).Info("No synthesis slot free for the plan, retrying later", "agent", agent.Name)
+		return nil
+	}
+	if err != nil {
+		if r.Recorder != nil {
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "SynthesisPlanFailed", "Synthesis plan failed: %v", err)
+		}
+		data := map[string]string{synthesisPlanSummaryKey: fmt.Sprintf("Synthesis failed: %v", err)}
+		if writeErr := CreateOrUpdateConfigMapWithAnnotations(ctx, r.Client, r.Scheme, agent, planConfigMapName, agent.Namespace, data, annotations); writeErr != nil {
+			return writeErr
+		}
+		return fmt.Errorf("synthesis plan failed: %w", err)
+	}
+
+	plan := planAgentCode(code, planned)
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return fmt.Errorf("failed to marshal synthesis plan: %w", err)
+	}
+	summary := summarizeCodePlan(plan)
+	data := map[string]string{
+		synthesisPlanDiffKey:    plan.Diff,
+		synthesisPlanSummaryKey: summary,
+		synthesisPlanJSONKey:    string(planJSON),
+	}
+	if err := CreateOrUpdateConfigMapWithAnnotations(ctx, r.Client, r.Scheme, agent, planConfigMapName, agent.Namespace, data, annotations); err != nil {
+		return err
+	}
+
+	log.Info("Agent code planned", "agent", agent.Name, "configMap", planConfigMapName, "changed", plan.Changed(), "modeChanged", plan.ModeChanged)
+	if r.Recorder != nil {
+		r.Recorder.Eventf(agent, corev1.EventTypeNormal, "SynthesisPlanned", "%s; see ConfigMap %s", summary, planConfigMapName)
+	}
+	return nil
+}
+
+// planAgentCode compares the planned code with the current code, detecting mode and schedule
+// changes the way the controller does when it applies code
+func planAgentCode(current, planned string) synthesis.CodePlan {
+	currentMode, currentSchedule := parseDSLMode(current)
+	plannedMode, plannedSchedule := parseDSLMode(planned)
+	return synthesis.CodePlan{
+		Old:         current,
+		New:         planned,
+		Diff:        synthesis.DiffCode(current, planned),
+		ModeChanged: current != "" && (currentMode != plannedMode || currentSchedule != plannedSchedule),
+	}
+}
+
+// summarizeCodePlan describes a plan in one line
+func summarizeCodePlan(plan synthesis.CodePlan) string {
+	switch {
+	case plan.Old == "":
+		mode, schedule := parseDSLMode(plan.New)
+		return fmt.Sprintf("No code synthesized yet; planned code runs %s", describeDSLMode(mode, schedule))
+	case !plan.Changed():
+		return "Planned code matches the current code"
+	case plan.ModeChanged:
+		oldMode, oldSchedule := parseDSLMode(plan.Old)
+		newMode, newSchedule := parseDSLMode(plan.New)
+		return fmt.Sprintf("Planned code differs from the current code and changes the mode from %s to %s",
+			describeDSLMode(oldMode, oldSchedule), describeDSLMode(newMode, newSchedule))
+	default:
+		return "Planned code differs from the current code"
+	}
+}
+
+// describeDSLMode names a detected mode, with its schedule when it has one
+func describeDSLMode(mode, schedule string) string {
+	if schedule == "" {
+		return mode
+	}
+	return fmt.Sprintf("%s (%q)", mode, schedule)
+}
+
+// synthesizePlannedCode synthesizes the agent's code from its current spec, metered like code
+// synthesis: it consumes the synthesis rate limit and an attempt of the synthesis quota, and its
+// cost is charged to the agent
+func (r *LanguageAgentReconciler) synthesizePlannedCode(ctx context.Context, agent *langopv1alpha1.LanguageAgent, synthesizer synthesis.AgentSynthesizer) (string, error) {
+	log := log.FromContext(ctx)
+	tools := r.getToolNames(agent)
+	persona, err := r.fetchPersona(ctx, agent)
+	if err != nil {
+		log.Error(err, "Failed to fetch persona, planning without it")
+	}
+	req := synthesis.AgentSynthesisRequest{
+		Instructions: r.synthesisInstructions(agent),
+		Tools:        tools,
+		ToolSchemas:  r.getToolSchemas(ctx, agent),
+		Models:       r.getModelNames(agent),
+		AgentName:    agent.Name,
+		Namespace:    agent.Namespace,
+		Examples:     r.synthesisExamples(ctx, agent, tools),
+
+		PersonaConstraints: r.personaConstraints(agent, persona),
+	}
+	if persona != nil {
+		req.PersonaText, err = r.distillPersona(ctx, persona, agent)
+		if synthesis.IsNoSynthesisSlot(err) {
+			return "", err
+		}
+		if err != nil {
+			log.Error(err, "Failed to distill persona, planning without it")
+		}
+	}
+
+	if r.RateLimiter != nil {
+		if err := r.RateLimiter.CheckAndConsume(ctx, agent.Namespace); err != nil {
+			synthesis.RecordSynthesisRateLimitExceeded(agent.Namespace)
+			return "", &synthesis.QuotaExceededError{Err: fmt.Errorf("synthesis rate limit exceeded: %w", err)}
+		}
+	}
+
+	if r.QuotaManager != nil {
+		defer r.recordSynthesisQuota(agent)
+		if err := r.QuotaManager.ReserveAgentAttempt(ctx, agent.Namespace, agent.Name, agentQuotaLimits(agent)); err != nil {
+			synthesis.RecordSynthesisQuotaExceeded(agent.Namespace, "attempts")
+			return "", &synthesis.QuotaExceededError{Err: fmt.Errorf("synthesis attempt quota exceeded: %w", err)}
+		}
+		if err := r.checkSynthesisCostEstimate(ctx, agent, req); err != nil {
+			r.QuotaManager.ReleaseAgentAttempt(agent.Namespace, agent.Name)
+			return "", err
+		}
+	}
+
+	if err := r.SynthesisSlots.Acquire(ctx, agent.Namespace, agent.Name); err != nil {
+		if r.QuotaManager != nil {
+			r.QuotaManager.ReleaseAgentAttempt(agent.Namespace, agent.Name)
+		}
+		return "", fmt.Errorf("failed to acquire synthesis slot: %w", err)
+	}
+	resp, err := synthesizer.SynthesizeAgent(ctx, req)
+	r.SynthesisSlots.Release()
+	if err == nil && resp.Error != "" {
+		err = fmt.Errorf("%s", resp.Error)
+	}
+
+	if r.QuotaManager != nil {
+		errorMsg := ""
+		if err != nil {
+			errorMsg = err.Error()
+		}
+		r.QuotaManager.CompleteAgentAttempt(ctx, agent.Namespace, agent.Name, err == nil, errorMsg)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// Code the synthesizer reported no cost for is priced with the model's configured pricing
+	if resp.Cost == nil {
+		model, _, err := r.resolveSynthesisModel(ctx, agent)
+		if err != nil {
+			log.V(1).Info("Failed to resolve the synthesis model to price the plan with", "agent", agent.Name, "error", err.Error())
+		}
+		if resp.Cost, err = r.pricedCost(ctx, agent, model, req, resp.DSLCode); err != nil {
+			return "", err
+		}
+	}
+	if r.QuotaManager != nil && resp.Cost != nil {
+		if err := r.QuotaManager.RecordCost(ctx, agent.Namespace, agent.Name, resp.Cost); err != nil {
+			log.Error(err, "Failed to record synthesis plan cost")
+		}
+	}
+	return resp.DSLCode, nil
+}
@@ -0,0 +1,116 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+func newPlannedAgent(trigger string) *langopv1alpha1.LanguageAgent {
+	return &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "planned",
+			Namespace:   "default",
+			UID:         "planned-uid",
+			Annotations: map[string]string{SynthesisPlanAnnotation: trigger},
+		},
+		Spec: langopv1alpha1.LanguageAgentSpec{Instructions: "Summarize the news every morning"},
+	}
+}
+
+func getPlanConfigMap(t *testing.T, reconciler *LanguageAgentReconciler) *corev1.ConfigMap {
+	t.Helper()
+	plan := &corev1.ConfigMap{}
+	if err := reconciler.Get(context.Background(), types.NamespacedName{Name: "planned-plan", Namespace: "default"}, plan); err != nil {
+		t.Fatalf("Expected the plan ConfigMap to exist: %v", err)
+	}
+	return plan
+}
+
+func TestLanguageAgentController_WriteSynthesisPlan(t *testing.T) {
+	agent := newPlannedAgent("2026-10-16T09:30:00Z")
+	reconciler, _ := newForceWorkloadReconciler(t)
+	current := "agent \"planned\" do\n  mode :autonomous\nend\n"
+	planned := "agent \"planned\" do\n  schedule \"0 8 * * *\"\nend\n"
+
+	if err := reconciler.writeSynthesisPlan(context.Background(), agent, &MockSynthesizer{GeneratedCode: planned}, current, "2026-10-16T09:30:00Z"); err != nil {
+		t.Fatalf("writeSynthesisPlan failed: %v", err)
+	}
+
+	plan := getPlanConfigMap(t, reconciler)
+	if plan.Annotations[synthesisPlanTriggerAnnotation] != "2026-10-16T09:30:00Z" {
+		t.Errorf("Expected the plan to record its trigger, got %v", plan.Annotations)
+	}
+	if !strings.Contains(plan.Data[synthesisPlanDiffKey], "+  schedule \"0 8 * * *\"") {
+		t.Errorf("Expected the diff of the planned code, got %q", plan.Data[synthesisPlanDiffKey])
+	}
+	if summary := plan.Data[synthesisPlanSummaryKey]; !strings.Contains(summary, `from autonomous to scheduled ("0 8 * * *")`) {
+		t.Errorf("Expected the summary to describe the mode change, got %q", summary)
+	}
+	var decoded synthesis.CodePlan
+	if err := json.Unmarshal([]byte(plan.Data[synthesisPlanJSONKey]), &decoded); err != nil {
+		t.Fatalf("Failed to parse plan JSON: %v", err)
+	}
+	if decoded.Old != current || decoded.New != planned || !decoded.ModeChanged || decoded.Diff != plan.Data[synthesisPlanDiffKey] {
+		t.Errorf("Unexpected plan JSON: %+v", decoded)
+	}
+	if len(plan.OwnerReferences) != 1 || plan.OwnerReferences[0].UID != agent.UID {
+		t.Errorf("Expected the plan ConfigMap to be owned by the agent, got %+v", plan.OwnerReferences)
+	}
+	if events := drainEvents(reconciler.Recorder.(*record.FakeRecorder)); !hasEvent(events, "SynthesisPlanned") {
+		t.Errorf("Expected a SynthesisPlanned event, got %v", events)
+	}
+
+	// Unchanged code is reported as such
+	if err := reconciler.writeSynthesisPlan(context.Background(), agent, &MockSynthesizer{GeneratedCode: current}, current, "again"); err != nil {
+		t.Fatalf("writeSynthesisPlan failed: %v", err)
+	}
+	plan = getPlanConfigMap(t, reconciler)
+	if plan.Data[synthesisPlanDiffKey] != "" || plan.Data[synthesisPlanSummaryKey] != "Planned code matches the current code" {
+		t.Errorf("Expected an empty plan, got %v", plan.Data)
+	}
+}
+
+func TestLanguageAgentController_WriteSynthesisPlanFailure(t *testing.T) {
+	agent := newPlannedAgent("1")
+	reconciler, _ := newForceWorkloadReconciler(t)
+
+	if err := reconciler.writeSynthesisPlan(context.Background(), agent, &MockSynthesizer{ShouldFail: true}, "agent \"planned\" do\nend\n", "1"); err == nil {
+		t.Fatal("Expected a failed plan to return an error")
+	}
+
+	// The failure answers the trigger so it isn't retried until the annotation changes
+	plan := getPlanConfigMap(t, reconciler)
+	if plan.Annotations[synthesisPlanTriggerAnnotation] != "1" || !strings.Contains(plan.Data[synthesisPlanSummaryKey], "Synthesis failed") {
+		t.Errorf("Expected the failure to be recorded, got %v %v", plan.Annotations, plan.Data)
+	}
+	if events := drainEvents(reconciler.Recorder.(*record.FakeRecorder)); !hasEvent(events, "SynthesisPlanFailed") {
+		t.Errorf("Expected a SynthesisPlanFailed event, got %v", events)
+	}
+	if err := reconciler.reconcileSynthesisPlan(context.Background(), agent, ""); err != nil {
+		t.Errorf("Expected an answered trigger to be skipped, got %v", err)
+	}
+}
+
+func TestLanguageAgentController_ReconcileSynthesisPlanWithoutTrigger(t *testing.T) {
+	agent := newPlannedAgent("")
+	reconciler, fakeClient := newForceWorkloadReconciler(t)
+
+	if err := reconciler.reconcileSynthesisPlan(context.Background(), agent, "agent \"planned\" do\nend\n"); err != nil {
+		t.Fatalf("reconcileSynthesisPlan failed: %v", err)
+	}
+	err := fakeClient.Get(context.Background(), types.NamespacedName{Name: "planned-plan", Namespace: "default"}, &corev1.ConfigMap{})
+	if !errors.IsNotFound(err) {
+		t.Errorf("Expected no plan without the plan annotation, got err=%v", err)
+	}
+}
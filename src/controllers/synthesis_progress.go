@@ -0,0 +1,85 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// synthesisProgressInterval is the minimum time between SynthesisProgress events within a phase
+const synthesisProgressInterval = 15 * time.Second
+
+// streamSynthesis synthesizes the agent's code, surfacing progress as SynthesisProgress events
+// and status.synthesisInfo.phase so long syntheses show up in kubectl describe. Phase changes
+// are recorded as they happen; token counts are recorded at most every synthesisProgressInterval.
+// stream starts the synthesis and returns its progress updates.
+func (r *LanguageAgentReconciler) streamSynthesis(ctx context.Context, agent *langopv1alpha1.LanguageAgent, stream func() (<-chan synthesis.SynthesisProgress, error)) (*synthesis.AgentSynthesisResponse, error) {
+	updates, err := stream()
+	if err != nil {
+		return nil, err
+	}
+	if agent.Status.SynthesisInfo == nil {
+		agent.Status.SynthesisInfo = &langopv1alpha1.SynthesisInfo{}
+	}
+	// The phase only describes a running synthesis, so clear it however synthesis ends
+	defer func() {
+		if agent.Status.SynthesisInfo.Phase == "" {
+			return
+		}
+		if err := r.patchSynthesisPhase(ctx, agent, ""); err != nil {
+			log.FromContext(ctx).Error(err, "Failed to clear synthesis phase in status")
+		}
+	}()
+
+	var lastEvent time.Time
+	for update := range updates {
+		if update.Done {
+			return update.Response, update.Err
+		}
+
+		if update.Phase != agent.Status.SynthesisInfo.Phase {
+			if err := r.patchSynthesisPhase(ctx, agent, update.Phase); err != nil {
+				log.FromContext(ctx).Error(err, "Failed to update synthesis phase in status")
+			}
+		} else if time.Since(lastEvent) < synthesisProgressInterval {
+			continue
+		}
+		lastEvent = time.Now()
+
+		if r.Recorder != nil {
+			message := fmt.Sprintf("Synthesis phase %s", update.Phase)
+			if update.OutputTokens > 0 {
+				message = fmt.Sprintf("%s, ~%d tokens generated", message, update.OutputTokens)
+			}
+			r.Recorder.Event(agent, corev1.EventTypeNormal, "SynthesisProgress", message)
+		}
+	}
+	// The stream only ends without a final update when ctx is done
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("synthesis ended without a result")
+}
+
+// patchSynthesisPhase writes the synthesis phase straight to the agent's status. The phase is only
+// useful while synthesis runs, so it bypasses batched status updates. Only the phase is patched,
+// from a copy, so status changes the reconcile hasn't written yet stay pending rather than being
+// overwritten by the stored status.
+func (r *LanguageAgentReconciler) patchSynthesisPhase(ctx context.Context, agent *langopv1alpha1.LanguageAgent, phase string) error {
+	patched := agent.DeepCopy()
+	patch := client.MergeFrom(patched.DeepCopy())
+	patched.Status.SynthesisInfo.Phase = phase
+	if err := r.Status().Patch(ctx, patched, patch); err != nil {
+		return err
+	}
+	agent.Status.SynthesisInfo.Phase = phase
+	agent.ResourceVersion = patched.ResourceVersion
+	return nil
+}
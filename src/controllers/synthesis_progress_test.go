@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// progressSynthesizer streams a fixed sequence of progress updates
+type progressSynthesizer struct {
+	MockSynthesizer
+	updates []synthesis.SynthesisProgress
+}
+
+func (s *progressSynthesizer) SynthesizeAgentStream(ctx context.Context, req synthesis.AgentSynthesisRequest) (<-chan synthesis.SynthesisProgress, error) {
+	updates := make(chan synthesis.SynthesisProgress, len(s.updates))
+	for _, update := range s.updates {
+		updates <- update
+	}
+	close(updates)
+	return updates, nil
+}
+
+func TestLanguageAgentController_StreamSynthesisProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		final       synthesis.SynthesisProgress
+		expectError bool
+	}{
+		{
+			name:  "synthesis succeeds",
+			final: synthesis.SynthesisProgress{Done: true, Response: &synthesis.AgentSynthesisResponse{DSLCode: "agent \"streamed\" do\nend"}},
+		},
+		{
+			name:        "synthesis fails",
+			final:       synthesis.SynthesisProgress{Done: true, Response: &synthesis.AgentSynthesisResponse{Error: "lint failed"}, Err: errors.New("lint failed")},
+			expectError: true,
+		},
+		{
+			name:        "stream ends without a result",
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			scheme := testutil.SetupTestScheme(t)
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "streamed", Namespace: "default"},
+				Spec:       langopv1alpha1.LanguageAgentSpec{Instructions: "Summarize open issues"},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent).WithStatusSubresource(agent).Build()
+			recorder := record.NewFakeRecorder(20)
+			reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard(), Recorder: recorder}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+
+			updates := []synthesis.SynthesisProgress{
+				{Phase: synthesis.SynthesisPhaseValidating},
+				{Phase: synthesis.SynthesisPhaseGenerating},
+				{Phase: synthesis.SynthesisPhaseGenerating, OutputTokens: 40},
+				{Phase: synthesis.SynthesisPhaseGenerating, OutputTokens: 80},
+				{Phase: synthesis.SynthesisPhaseValidatingOutput, OutputTokens: 120},
+			}
+			if tt.final.Done {
+				updates = append(updates, tt.final)
+			}
+			synthesizer := &progressSynthesizer{updates: updates}
+
+			resp, err := reconciler.streamSynthesis(ctx, agent, func() (<-chan synthesis.SynthesisProgress, error) {
+				return synthesizer.SynthesizeAgentStream(ctx, synthesis.AgentSynthesisRequest{AgentName: agent.Name})
+			})
+			if (err != nil) != tt.expectError {
+				t.Fatalf("Expected error=%v, got %v", tt.expectError, err)
+			}
+			if !tt.expectError && resp.DSLCode != tt.final.Response.DSLCode {
+				t.Errorf("Expected the final response, got %+v", resp)
+			}
+
+			// Each phase change is recorded; updates within a phase are throttled
+			var progressEvents []string
+			for _, event := range drainEvents(recorder) {
+				if strings.Contains(event, " SynthesisProgress ") {
+					progressEvents = append(progressEvents, event)
+				}
+			}
+			if len(progressEvents) != 3 {
+				t.Fatalf("Expected one SynthesisProgress event per phase, got %v", progressEvents)
+			}
+			if !strings.Contains(progressEvents[2], "validating-output, ~120 tokens generated") {
+				t.Errorf("Expected the token count in the progress event, got %q", progressEvents[2])
+			}
+
+			// The phase is cleared once synthesis ends
+			stored := &langopv1alpha1.LanguageAgent{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, stored); err != nil {
+				t.Fatalf("Failed to get agent: %v", err)
+			}
+			if stored.Status.SynthesisInfo == nil || stored.Status.SynthesisInfo.Phase != "" {
+				t.Errorf("Expected the synthesis phase to be cleared, got %+v", stored.Status.SynthesisInfo)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_StreamSynthesisPhaseWithBatchedStatus(t *testing.T) {
+	ctx := context.Background()
+	scheme := testutil.SetupTestScheme(t)
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "streamed", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageAgentSpec{Instructions: "Summarize open issues"},
+	}
+	var storedPhases []string
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(agent).
+		WithStatusSubresource(agent).
+		WithInterceptorFuncs(interceptor.Funcs{
+			SubResourcePatch: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+				if err := c.SubResource(subResourceName).Patch(ctx, obj, patch, opts...); err != nil {
+					return err
+				}
+				storedPhases = append(storedPhases, obj.(*langopv1alpha1.LanguageAgent).Status.SynthesisInfo.Phase)
+				return nil
+			},
+		}).
+		Build()
+	reconciler := &LanguageAgentReconciler{
+		Client:             fakeClient,
+		Scheme:             scheme,
+		Log:                logr.Discard(),
+		Recorder:           record.NewFakeRecorder(20),
+		BatchStatusUpdates: true,
+	}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, agent); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	// A status change made earlier in the reconcile, not yet written
+	agent.Status.Phase = "Synthesizing"
+
+	synthesizer := &progressSynthesizer{updates: []synthesis.SynthesisProgress{
+		{Phase: synthesis.SynthesisPhaseValidating},
+		{Phase: synthesis.SynthesisPhaseGenerating},
+		{Done: true, Response: &synthesis.AgentSynthesisResponse{DSLCode: "agent \"streamed\" do\nend"}},
+	}}
+	if _, err := reconciler.streamSynthesis(ctx, agent, func() (<-chan synthesis.SynthesisProgress, error) {
+		return synthesizer.SynthesizeAgentStream(ctx, synthesis.AgentSynthesisRequest{AgentName: agent.Name})
+	}); err != nil {
+		t.Fatalf("streamSynthesis failed: %v", err)
+	}
+
+	// Each phase reaches the API server while synthesis runs, even though status updates are batched
+	expected := []string{synthesis.SynthesisPhaseValidating, synthesis.SynthesisPhaseGenerating, ""}
+	if strings.Join(storedPhases, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected stored phases %q, got %q", expected, storedPhases)
+	}
+	if agent.Status.Phase != "Synthesizing" {
+		t.Errorf("Expected unwritten status changes to be kept, got phase %q", agent.Status.Phase)
+	}
+	if err := reconciler.Status().Update(ctx, agent); err != nil {
+		t.Errorf("Expected the batched status write not to conflict with the phase patches, got %v", err)
+	}
+}
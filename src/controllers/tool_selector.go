@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// resolveToolSelector records the LanguageTools matched by spec.toolSelector in
+// status.selectedTools. Tools already listed in spec.toolRefs are not repeated.
+func (r *LanguageAgentReconciler) resolveToolSelector(ctx context.Context, agent *langopv1alpha1.LanguageAgent) error {
+	if agent.Spec.ToolSelector == nil {
+		agent.Status.SelectedTools = nil
+		return nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(agent.Spec.ToolSelector)
+	if err != nil {
+		return fmt.Errorf("invalid spec.toolSelector: %w", err)
+	}
+
+	tools := &langopv1alpha1.LanguageToolList{}
+	if err := r.List(ctx, tools, client.InNamespace(agent.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+		return fmt.Errorf("failed to list tools matching spec.toolSelector: %w", err)
+	}
+
+	explicit := make(map[string]bool, len(agent.Spec.ToolRefs))
+	for _, ref := range agent.Spec.ToolRefs {
+		if ref.Namespace == "" || ref.Namespace == agent.Namespace {
+			explicit[ref.Name] = true
+		}
+	}
+
+	var selected []string
+	for _, tool := range tools.Items {
+		if !explicit[tool.Name] {
+			selected = append(selected, tool.Name)
+		}
+	}
+	sort.Strings(selected)
+
+	agent.Status.SelectedTools = selected
+	return nil
+}
+
+// agentToolRefs returns the agent's explicit toolRefs followed by the tools
+// selected by spec.toolSelector
+func agentToolRefs(agent *langopv1alpha1.LanguageAgent) []langopv1alpha1.ToolReference {
+	if len(agent.Status.SelectedTools) == 0 || agent.Spec.ToolSelector == nil {
+		return agent.Spec.ToolRefs
+	}
+
+	refs := make([]langopv1alpha1.ToolReference, 0, len(agent.Spec.ToolRefs)+len(agent.Status.SelectedTools))
+	refs = append(refs, agent.Spec.ToolRefs...)
+	for _, name := range agent.Status.SelectedTools {
+		refs = append(refs, langopv1alpha1.ToolReference{Name: name, Enabled: true})
+	}
+	return refs
+}
+
+// agentsForTool maps a LanguageTool event to the agents in its namespace that
+// select it, or selected it at their last reconcile
+func (r *LanguageAgentReconciler) agentsForTool(ctx context.Context, obj client.Object) []reconcile.Request {
+	agents := &langopv1alpha1.LanguageAgentList{}
+	if err := r.List(ctx, agents, client.InNamespace(obj.GetNamespace())); err != nil {
+		log.FromContext(ctx).Error(err, "Failed to list agents for tool", "tool", obj.GetName())
+		return nil
+	}
+
+	var requests []reconcile.Request
+	for _, agent := range agents.Items {
+		if agent.Spec.ToolSelector == nil {
+			continue
+		}
+		if !toolSelectedBy(&agent, obj) {
+			continue
+		}
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace},
+		})
+	}
+	return requests
+}
+
+// toolSelectedBy reports whether the agent's selector matches the tool, or the
+// tool was selected previously and may have been deleted or relabeled
+func toolSelectedBy(agent *langopv1alpha1.LanguageAgent, tool client.Object) bool {
+	for _, name := range agent.Status.SelectedTools {
+		if name == tool.GetName() {
+			return true
+		}
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(agent.Spec.ToolSelector)
+	if err != nil {
+		return false
+	}
+	return selector.Matches(labels.Set(tool.GetLabels()))
+}
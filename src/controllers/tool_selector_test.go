@@ -0,0 +1,169 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newSelectorTestTool(name string, labels map[string]string) *langopv1alpha1.LanguageTool {
+	return &langopv1alpha1.LanguageTool{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", Labels: labels},
+		Spec:       langopv1alpha1.LanguageToolSpec{Image: "ghcr.io/language-operator/tool:dev"},
+	}
+}
+
+func toolRefNames(refs []langopv1alpha1.ToolReference) string {
+	var names []string
+	for _, ref := range refs {
+		names = append(names, ref.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestResolveToolSelector(t *testing.T) {
+	ctx := context.Background()
+	scheme := testutil.SetupTestScheme(t)
+
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "selector-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ToolRefs: []langopv1alpha1.ToolReference{{Name: "web-search"}, {Name: "postgres"}},
+			ToolSelector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{"langop.io/team": "data"},
+			},
+		},
+	}
+	otherNamespace := newSelectorTestTool("s3", map[string]string{"langop.io/team": "data"})
+	otherNamespace.Namespace = "other"
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(
+			agent,
+			newSelectorTestTool("postgres", map[string]string{"langop.io/team": "data"}),
+			newSelectorTestTool("bigquery", map[string]string{"langop.io/team": "data"}),
+			newSelectorTestTool("slack", map[string]string{"langop.io/team": "comms"}),
+			otherNamespace,
+		).
+		Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+	if err := reconciler.resolveToolSelector(ctx, agent); err != nil {
+		t.Fatalf("resolveToolSelector failed: %v", err)
+	}
+	if got := toolRefNames(agentToolRefs(agent)); got != "web-search,postgres,bigquery" {
+		t.Errorf("Expected explicit refs followed by selected tools, got %s", got)
+	}
+
+	// Newly labeled tools are picked up and deleted ones dropped on the next resolve
+	if err := fakeClient.Create(ctx, newSelectorTestTool("snowflake", map[string]string{"langop.io/team": "data"})); err != nil {
+		t.Fatalf("Failed to create tool: %v", err)
+	}
+	if err := fakeClient.Delete(ctx, newSelectorTestTool("bigquery", nil)); err != nil {
+		t.Fatalf("Failed to delete tool: %v", err)
+	}
+	if err := reconciler.resolveToolSelector(ctx, agent); err != nil {
+		t.Fatalf("resolveToolSelector failed: %v", err)
+	}
+	if got := strings.Join(agent.Status.SelectedTools, ","); got != "snowflake" {
+		t.Errorf("Expected selected tools to follow tool changes, got %s", got)
+	}
+
+	// Removing the selector drops every selected tool
+	agent.Spec.ToolSelector = nil
+	if err := reconciler.resolveToolSelector(ctx, agent); err != nil {
+		t.Fatalf("resolveToolSelector failed: %v", err)
+	}
+	if got := toolRefNames(agentToolRefs(agent)); got != "web-search,postgres" {
+		t.Errorf("Expected only explicit refs without a selector, got %s", got)
+	}
+}
+
+func TestResolveToolSelector_InvalidSelector(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+	reconciler := &LanguageAgentReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme).Build(),
+		Scheme: scheme,
+		Log:    logr.Discard(),
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "selector-agent", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ToolSelector: &metav1.LabelSelector{
+				MatchExpressions: []metav1.LabelSelectorRequirement{{Key: "langop.io/team", Operator: "Near"}},
+			},
+		},
+	}
+
+	if err := reconciler.resolveToolSelector(context.Background(), agent); err == nil {
+		t.Error("Expected an error for an invalid selector")
+	}
+}
+
+func TestAgentsForTool(t *testing.T) {
+	scheme := testutil.SetupTestScheme(t)
+
+	selecting := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "selecting", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ToolSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"langop.io/team": "data"}},
+		},
+	}
+	previouslySelecting := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "previously-selecting", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ToolSelector: &metav1.LabelSelector{MatchLabels: map[string]string{"langop.io/team": "ml"}},
+		},
+		Status: langopv1alpha1.LanguageAgentStatus{SelectedTools: []string{"postgres"}},
+	}
+	explicitOnly := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "explicit-only", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ToolRefs: []langopv1alpha1.ToolReference{{Name: "postgres"}},
+		},
+	}
+
+	fakeClient := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(selecting, previouslySelecting, explicitOnly).
+		Build()
+	reconciler := &LanguageAgentReconciler{Client: fakeClient, Scheme: scheme, Log: logr.Discard()}
+
+	tests := []struct {
+		name     string
+		tool     *langopv1alpha1.LanguageTool
+		expected string
+	}{
+		{name: "matching labels", tool: newSelectorTestTool("bigquery", map[string]string{"langop.io/team": "data"}), expected: "selecting"},
+		{name: "relabeled tool still reaches agents that selected it", tool: newSelectorTestTool("postgres", map[string]string{"langop.io/team": "data"}), expected: "selecting,previously-selecting"},
+		{name: "unmatched tool", tool: newSelectorTestTool("slack", map[string]string{"langop.io/team": "comms"})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var names []string
+			for _, req := range reconciler.agentsForTool(context.Background(), tt.tool) {
+				names = append(names, req.Name)
+			}
+			expected := strings.Split(tt.expected, ",")
+			if tt.expected == "" {
+				expected = nil
+			}
+			if len(names) != len(expected) {
+				t.Fatalf("Expected agents %v, got %v", expected, names)
+			}
+			for _, name := range expected {
+				if !strings.Contains(","+strings.Join(names, ",")+",", ","+name+",") {
+					t.Errorf("Expected agent %s to be enqueued, got %v", name, names)
+				}
+			}
+		})
+	}
+}
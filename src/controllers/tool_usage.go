@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/pkg/synthesis"
+)
+
+// toolUsageManifest compares the tools called by agent code against the tools granted to the agent
+type toolUsageManifest struct {
+	used      []langopv1alpha1.UsedTool
+	unused    []string
+	ungranted []string
+}
+
+// buildToolUsageManifest resolves each tool call in the code to a granted LanguageTool. A call names
+// either the LanguageTool itself, optionally followed by a tool function, or a tool function that
+// one of the granted LanguageTools exposes. schemaOwners maps tool function names to their LanguageTool.
+func buildToolUsageManifest(code string, granted []string, schemaOwners map[string]string) toolUsageManifest {
+	grantedSet := make(map[string]struct{}, len(granted))
+	for _, name := range granted {
+		grantedSet[name] = struct{}{}
+	}
+
+	methods := make(map[string]map[string]struct{})
+	ungranted := make(map[string]struct{})
+	use := func(tool, method string) {
+		if methods[tool] == nil {
+			methods[tool] = make(map[string]struct{})
+		}
+		if method != "" {
+			methods[tool][method] = struct{}{}
+		}
+	}
+	for _, call := range synthesis.ToolCalls(code) {
+		if _, ok := grantedSet[call.Name]; ok {
+			use(call.Name, call.Method)
+		} else if owner, ok := schemaOwners[call.Name]; ok {
+			use(owner, call.Name)
+		} else {
+			ungranted[call.Name] = struct{}{}
+		}
+	}
+
+	var manifest toolUsageManifest
+	for tool, toolMethods := range methods {
+		usedTool := langopv1alpha1.UsedTool{Name: tool}
+		for method := range toolMethods {
+			usedTool.Methods = append(usedTool.Methods, method)
+		}
+		sort.Strings(usedTool.Methods)
+		manifest.used = append(manifest.used, usedTool)
+	}
+	sort.Slice(manifest.used, func(i, j int) bool { return manifest.used[i].Name < manifest.used[j].Name })
+
+	for name := range grantedSet {
+		if _, ok := methods[name]; !ok {
+			manifest.unused = append(manifest.unused, name)
+		}
+	}
+	sort.Strings(manifest.unused)
+
+	for name := range ungranted {
+		manifest.ungranted = append(manifest.ungranted, name)
+	}
+	sort.Strings(manifest.ungranted)
+
+	return manifest
+}
+
+// recordToolUsage records which granted tools the agent code calls, for least-privilege auditing.
+// Warning events are emitted when the set of unused grants or ungranted calls changes.
+func (r *LanguageAgentReconciler) recordToolUsage(ctx context.Context, agent *langopv1alpha1.LanguageAgent, code string) {
+	if code == "" {
+		agent.Status.UsedTools = nil
+		agent.Status.UnusedToolGrants = nil
+		agent.Status.UngrantedToolCalls = nil
+		return
+	}
+
+	var granted []string
+	schemaOwners := make(map[string]string)
+	for _, ref := range agentToolRefs(agent) {
+		// A disabled ref doesn't grant the tool
+		if !ref.Enabled {
+			continue
+		}
+		granted = append(granted, ref.Name)
+
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = agent.Namespace
+		}
+		tool := &langopv1alpha1.LanguageTool{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, tool); err != nil {
+			log.FromContext(ctx).V(1).Info("Failed to get LanguageTool for usage manifest", "tool", ref.Name, "error", err.Error())
+			continue
+		}
+		for _, schema := range tool.Status.ToolSchemas {
+			if _, ok := schemaOwners[schema.Name]; !ok {
+				schemaOwners[schema.Name] = ref.Name
+			}
+		}
+	}
+
+	manifest := buildToolUsageManifest(code, granted, schemaOwners)
+	unusedChanged := !equality.Semantic.DeepEqual(agent.Status.UnusedToolGrants, manifest.unused)
+	ungrantedChanged := !equality.Semantic.DeepEqual(agent.Status.UngrantedToolCalls, manifest.ungranted)
+	agent.Status.UsedTools = manifest.used
+	agent.Status.UnusedToolGrants = manifest.unused
+	agent.Status.UngrantedToolCalls = manifest.ungranted
+
+	if r.Recorder == nil {
+		return
+	}
+	if unusedChanged && len(manifest.unused) > 0 {
+		r.Recorder.Eventf(agent, corev1.EventTypeWarning, "UnusedToolGrant",
+			"Agent code never calls granted tools %s; consider removing them from spec.toolRefs", strings.Join(manifest.unused, ", "))
+	}
+	if ungrantedChanged && len(manifest.ungranted) > 0 {
+		r.Recorder.Eventf(agent, corev1.EventTypeWarning, "UngrantedToolCall",
+			"Agent code calls tools no granted LanguageTool provides: %s", strings.Join(manifest.ungranted, ", "))
+	}
+}
@@ -0,0 +1,94 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const toolUsageCode = `agent "reviewer" do
+  task :review do |inputs|
+    diff = execute_tool('github', 'get_pr_diff', pr_number: inputs[:pr_number])
+    files = execute_tool('github', 'list_files', pr_number: inputs[:pr_number])
+    content = execute_tool('read_file', { path: inputs[:file_path] })
+    execute_tool('slack', 'post_message', text: diff)
+  end
+end`
+
+func TestBuildToolUsageManifest(t *testing.T) {
+	manifest := buildToolUsageManifest(toolUsageCode,
+		[]string{"github", "workspace", "web-search", "email"},
+		map[string]string{"read_file": "workspace", "write_file": "workspace", "search": "web-search"})
+
+	expectedUsed := []langopv1alpha1.UsedTool{
+		{Name: "github", Methods: []string{"get_pr_diff", "list_files"}},
+		{Name: "workspace", Methods: []string{"read_file"}},
+	}
+	if fmt.Sprint(manifest.used) != fmt.Sprint(expectedUsed) {
+		t.Errorf("Expected used tools %v, got %v", expectedUsed, manifest.used)
+	}
+	if expected := []string{"email", "web-search"}; fmt.Sprint(manifest.unused) != fmt.Sprint(expected) {
+		t.Errorf("Expected unused grants %v, got %v", expected, manifest.unused)
+	}
+	if expected := []string{"slack"}; fmt.Sprint(manifest.ungranted) != fmt.Sprint(expected) {
+		t.Errorf("Expected ungranted calls %v, got %v", expected, manifest.ungranted)
+	}
+}
+
+func TestLanguageAgentController_RecordToolUsage(t *testing.T) {
+	workspace := &langopv1alpha1.LanguageTool{
+		ObjectMeta: metav1.ObjectMeta{Name: "workspace", Namespace: "shared-tools"},
+		Status: langopv1alpha1.LanguageToolStatus{
+			ToolSchemas: []langopv1alpha1.ToolSchema{{Name: "read_file"}, {Name: "write_file"}},
+		},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "reviewer", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ToolRefs: []langopv1alpha1.ToolReference{
+				{Name: "github", Enabled: true},
+				{Name: "workspace", Namespace: "shared-tools", Enabled: true},
+				{Name: "email", Enabled: true},
+				// A disabled ref doesn't grant slack
+				{Name: "slack", Enabled: false},
+			},
+		},
+	}
+	reconciler, _ := newForceWorkloadReconciler(t, agent, workspace)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+
+	ctx := context.Background()
+	reconciler.recordToolUsage(ctx, agent, toolUsageCode)
+
+	if len(agent.Status.UsedTools) != 2 || agent.Status.UsedTools[1].Name != "workspace" {
+		t.Errorf("Expected github and workspace to be used, got %v", agent.Status.UsedTools)
+	}
+	if fmt.Sprint(agent.Status.UnusedToolGrants) != "[email]" {
+		t.Errorf("Expected email to be an unused grant, got %v", agent.Status.UnusedToolGrants)
+	}
+	if fmt.Sprint(agent.Status.UngrantedToolCalls) != "[slack]" {
+		t.Errorf("Expected slack to be an ungranted call, got %v", agent.Status.UngrantedToolCalls)
+	}
+
+	events := drainEvents(recorder)
+	if len(events) != 2 {
+		t.Fatalf("Expected 2 events, got %v", events)
+	}
+	if !strings.Contains(events[0], "UnusedToolGrant") || !strings.Contains(events[0], "email") {
+		t.Errorf("Expected an UnusedToolGrant event for email, got %q", events[0])
+	}
+	if !strings.Contains(events[1], "UngrantedToolCall") || !strings.Contains(events[1], "slack") {
+		t.Errorf("Expected an UngrantedToolCall event for slack, got %q", events[1])
+	}
+
+	// An unchanged manifest doesn't repeat the warnings
+	reconciler.recordToolUsage(ctx, agent, toolUsageCode)
+	if events := drainEvents(recorder); len(events) != 0 {
+		t.Errorf("Expected no events for an unchanged manifest, got %v", events)
+	}
+}
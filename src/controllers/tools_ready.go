@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ToolsReadyCondition reports whether every LanguageTool an agent references is ready and has
+// published its tool schemas, so synthesis sees the agent's full toolset
+const ToolsReadyCondition = "ToolsReady"
+
+const (
+	// minToolsReadyBackoff and maxToolsReadyBackoff bound how long an agent waits between
+	// checks of its tools
+	minToolsReadyBackoff = 5 * time.Second
+	maxToolsReadyBackoff = 2 * time.Minute
+
+	// maxToolsReadyWait is how long synthesis waits for the agent's tools before proceeding with
+	// whichever are ready, so a tool that never publishes its schemas doesn't block the agent
+	maxToolsReadyWait = 15 * time.Minute
+)
+
+// toolNotReadyReason returns why a tool isn't ready for synthesis, or "" when it is. Only
+// service-mode MCP tools publish schemas in their status; sidecar and OpenAPI tools are ready
+// once running.
+func toolNotReadyReason(tool *langopv1alpha1.LanguageTool) string {
+	if tool.Status.Phase != "Running" {
+		phase := tool.Status.Phase
+		if phase == "" {
+			phase = "Pending"
+		}
+		return fmt.Sprintf("phase %s", phase)
+	}
+	publishesSchemas := tool.Spec.DeploymentMode != "sidecar" && (tool.Spec.Type == "" || tool.Spec.Type == "mcp")
+	if publishesSchemas && len(tool.Status.ToolSchemas) == 0 {
+		return "no tool schemas published"
+	}
+	return ""
+}
+
+// unreadyTools returns the agent's tools that aren't ready for synthesis, each described as
+// "<name> (<reason>)"
+func (r *LanguageAgentReconciler) unreadyTools(ctx context.Context, agent *langopv1alpha1.LanguageAgent) ([]string, error) {
+	var unready []string
+	for _, ref := range agentToolRefs(agent) {
+		namespace := ref.Namespace
+		if namespace == "" {
+			namespace = agent.Namespace
+		}
+		tool := &langopv1alpha1.LanguageTool{}
+		if err := r.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, tool); err != nil {
+			if errors.IsNotFound(err) {
+				unready = append(unready, fmt.Sprintf("%s (not found)", ref.Name))
+				continue
+			}
+			return nil, fmt.Errorf("failed to get tool %s: %w", ref.Name, err)
+		}
+		if reason := toolNotReadyReason(tool); reason != "" {
+			unready = append(unready, fmt.Sprintf("%s (%s)", ref.Name, reason))
+		}
+	}
+	return unready, nil
+}
+
+// toolsWaited returns how long the agent has been waiting for its tools, or zero when it isn't
+func toolsWaited(agent *langopv1alpha1.LanguageAgent, now time.Time) time.Duration {
+	condition := meta.FindStatusCondition(agent.Status.Conditions, ToolsReadyCondition)
+	if condition == nil || condition.Status != metav1.ConditionFalse {
+		return 0
+	}
+	return now.Sub(condition.LastTransitionTime.Time)
+}
+
+// toolsReadyBackoff returns how long to wait before checking the agent's tools again: the time
+// already spent waiting, between minToolsReadyBackoff and maxToolsReadyBackoff
+func toolsReadyBackoff(agent *langopv1alpha1.LanguageAgent, now time.Time) time.Duration {
+	return min(max(toolsWaited(agent, now), minToolsReadyBackoff), maxToolsReadyBackoff)
+}
+
+// checkToolsReady updates the ToolsReady condition and returns how long to wait before
+// synthesizing, or zero to proceed. Agents annotated with langop.io/allow-partial-tools=true
+// proceed with whichever tools are ready, as do agents that waited maxToolsReadyWait.
+func (r *LanguageAgentReconciler) checkToolsReady(ctx context.Context, agent *langopv1alpha1.LanguageAgent) (time.Duration, error) {
+	if len(agentToolRefs(agent)) == 0 {
+		meta.RemoveStatusCondition(&agent.Status.Conditions, ToolsReadyCondition)
+		return 0, nil
+	}
+
+	unready, err := r.unreadyTools(ctx, agent)
+	if err != nil {
+		return 0, err
+	}
+	if len(unready) == 0 {
+		SetCondition(&agent.Status.Conditions, ToolsReadyCondition, metav1.ConditionTrue, "AllToolsReady",
+			"All referenced tools are ready", agent.Generation)
+		return 0, nil
+	}
+
+	now := time.Now()
+	delay := toolsReadyBackoff(agent, now)
+	waitExpired := toolsWaited(agent, now) >= maxToolsReadyWait
+	message := fmt.Sprintf("Waiting for tools: %s", strings.Join(unready, ", "))
+	allowPartial := agent.Annotations[langopv1alpha1.AllowPartialToolsAnnotation] == "true"
+	if allowPartial || waitExpired {
+		message = fmt.Sprintf("Proceeding without tools: %s", strings.Join(unready, ", "))
+	}
+
+	wasWaiting := meta.IsStatusConditionFalse(agent.Status.Conditions, ToolsReadyCondition)
+	changed := SetCondition(&agent.Status.Conditions, ToolsReadyCondition, metav1.ConditionFalse, "ToolsNotReady", message, agent.Generation)
+	if r.Recorder != nil {
+		if !wasWaiting {
+			r.Recorder.Event(agent, corev1.EventTypeWarning, "ToolsNotReady", message)
+		} else if waitExpired && !allowPartial && changed {
+			r.Recorder.Eventf(agent, corev1.EventTypeWarning, "ToolsWaitExpired",
+				"Tools not ready after %s; %s", maxToolsReadyWait, message)
+		}
+	}
+
+	if allowPartial {
+		log.FromContext(ctx).Info("Synthesizing with partial tools", "annotation", langopv1alpha1.AllowPartialToolsAnnotation, "unready", unready)
+		return 0, nil
+	}
+	if waitExpired {
+		log.FromContext(ctx).Info("Synthesizing with partial tools after waiting for them", "waited", maxToolsReadyWait, "unready", unready)
+		return 0, nil
+	}
+	return delay, nil
+}
@@ -0,0 +1,364 @@
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+func TestToolNotReadyReason(t *testing.T) {
+	schemas := []langopv1alpha1.ToolSchema{{Name: "search"}}
+	tests := []struct {
+		name     string
+		spec     langopv1alpha1.LanguageToolSpec
+		status   langopv1alpha1.LanguageToolStatus
+		expected string
+	}{
+		{name: "pending tool", status: langopv1alpha1.LanguageToolStatus{}, expected: "phase Pending"},
+		{name: "failed tool", status: langopv1alpha1.LanguageToolStatus{Phase: "Failed"}, expected: "phase Failed"},
+		{name: "running MCP service without schemas", spec: langopv1alpha1.LanguageToolSpec{Type: "mcp"},
+			status: langopv1alpha1.LanguageToolStatus{Phase: "Running"}, expected: "no tool schemas published"},
+		{name: "running MCP service with schemas", spec: langopv1alpha1.LanguageToolSpec{Type: "mcp"},
+			status: langopv1alpha1.LanguageToolStatus{Phase: "Running", ToolSchemas: schemas}},
+		{name: "running sidecar doesn't publish schemas", spec: langopv1alpha1.LanguageToolSpec{Type: "mcp", DeploymentMode: "sidecar"},
+			status: langopv1alpha1.LanguageToolStatus{Phase: "Running"}},
+		{name: "running OpenAPI tool doesn't publish schemas", spec: langopv1alpha1.LanguageToolSpec{Type: "openapi"},
+			status: langopv1alpha1.LanguageToolStatus{Phase: "Running"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &langopv1alpha1.LanguageTool{Spec: tt.spec, Status: tt.status}
+			if got := toolNotReadyReason(tool); got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestToolsReadyBackoff(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	waitingSince := func(elapsed time.Duration) *langopv1alpha1.LanguageAgent {
+		agent := &langopv1alpha1.LanguageAgent{}
+		agent.Status.Conditions = []metav1.Condition{{
+			Type:               ToolsReadyCondition,
+			Status:             metav1.ConditionFalse,
+			LastTransitionTime: metav1.NewTime(now.Add(-elapsed)),
+		}}
+		return agent
+	}
+
+	if got := toolsReadyBackoff(&langopv1alpha1.LanguageAgent{}, now); got != minToolsReadyBackoff {
+		t.Errorf("Expected %v before waiting, got %v", minToolsReadyBackoff, got)
+	}
+	if got := toolsReadyBackoff(waitingSince(time.Second), now); got != minToolsReadyBackoff {
+		t.Errorf("Expected %v after a short wait, got %v", minToolsReadyBackoff, got)
+	}
+	if got := toolsReadyBackoff(waitingSince(40*time.Second), now); got != 40*time.Second {
+		t.Errorf("Expected 40s, got %v", got)
+	}
+	if got := toolsReadyBackoff(waitingSince(time.Hour), now); got != maxToolsReadyBackoff {
+		t.Errorf("Expected %v after a long wait, got %v", maxToolsReadyBackoff, got)
+	}
+}
+
+func TestLanguageAgentController_ToolsReadyGate(t *testing.T) {
+	newTool := func(name, phase string, schemas ...langopv1alpha1.ToolSchema) *langopv1alpha1.LanguageTool {
+		return &langopv1alpha1.LanguageTool{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       langopv1alpha1.LanguageToolSpec{Image: "ghcr.io/language-operator/" + name + ":latest", Type: "mcp"},
+			Status:     langopv1alpha1.LanguageToolStatus{Phase: phase, ToolSchemas: schemas},
+		}
+	}
+	newAgent := func(annotations map[string]string) *langopv1alpha1.LanguageAgent {
+		return &langopv1alpha1.LanguageAgent{
+			ObjectMeta: metav1.ObjectMeta{Name: "researcher", Namespace: "default", Annotations: annotations},
+			Spec: langopv1alpha1.LanguageAgentSpec{
+				ToolRefs: []langopv1alpha1.ToolReference{{Name: "web-search"}, {Name: "web-fetch"}},
+			},
+		}
+	}
+
+	tests := []struct {
+		name          string
+		annotations   map[string]string
+		tools         []*langopv1alpha1.LanguageTool
+		expectWait    bool
+		expectStatus  metav1.ConditionStatus
+		expectMessage string
+		expectEvent   bool
+	}{
+		{
+			name: "waits for a tool without schemas",
+			tools: []*langopv1alpha1.LanguageTool{
+				newTool("web-search", "Running", langopv1alpha1.ToolSchema{Name: "search"}),
+				newTool("web-fetch", "Running"),
+			},
+			expectWait:    true,
+			expectStatus:  metav1.ConditionFalse,
+			expectMessage: "Waiting for tools: web-fetch (no tool schemas published)",
+			expectEvent:   true,
+		},
+		{
+			name: "waits for a missing tool",
+			tools: []*langopv1alpha1.LanguageTool{
+				newTool("web-search", "Running", langopv1alpha1.ToolSchema{Name: "search"}),
+			},
+			expectWait:    true,
+			expectStatus:  metav1.ConditionFalse,
+			expectMessage: "web-fetch (not found)",
+			expectEvent:   true,
+		},
+		{
+			name:        "allow-partial-tools proceeds",
+			annotations: map[string]string{langopv1alpha1.AllowPartialToolsAnnotation: "true"},
+			tools: []*langopv1alpha1.LanguageTool{
+				newTool("web-search", "Running", langopv1alpha1.ToolSchema{Name: "search"}),
+				newTool("web-fetch", "Pending"),
+			},
+			expectStatus:  metav1.ConditionFalse,
+			expectMessage: "Proceeding without tools: web-fetch (phase Pending)",
+			expectEvent:   true,
+		},
+		{
+			name: "proceeds when all tools are ready",
+			tools: []*langopv1alpha1.LanguageTool{
+				newTool("web-search", "Running", langopv1alpha1.ToolSchema{Name: "search"}),
+				newTool("web-fetch", "Running", langopv1alpha1.ToolSchema{Name: "fetch"}),
+			},
+			expectStatus:  metav1.ConditionTrue,
+			expectMessage: "All referenced tools are ready",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agent := newAgent(tt.annotations)
+			reconciler, _ := newForceWorkloadReconciler(t)
+			for _, tool := range tt.tools {
+				if err := reconciler.Create(context.Background(), tool); err != nil {
+					t.Fatalf("Failed to create tool: %v", err)
+				}
+			}
+
+			wait, err := reconciler.checkToolsReady(context.Background(), agent)
+			if err != nil {
+				t.Fatalf("checkToolsReady failed: %v", err)
+			}
+			if tt.expectWait && wait < minToolsReadyBackoff {
+				t.Errorf("Expected a requeue of at least %v, got %v", minToolsReadyBackoff, wait)
+			}
+			if !tt.expectWait && wait != 0 {
+				t.Errorf("Expected to proceed, got a requeue after %v", wait)
+			}
+
+			condition := meta.FindStatusCondition(agent.Status.Conditions, ToolsReadyCondition)
+			if condition == nil {
+				t.Fatalf("Expected a %s condition", ToolsReadyCondition)
+			}
+			if condition.Status != tt.expectStatus {
+				t.Errorf("Expected %s=%s, got %s", ToolsReadyCondition, tt.expectStatus, condition.Status)
+			}
+			if !strings.Contains(condition.Message, tt.expectMessage) {
+				t.Errorf("Expected message containing %q, got %q", tt.expectMessage, condition.Message)
+			}
+			if got := hasEvent(drainEvents(reconciler.Recorder.(*record.FakeRecorder)), "ToolsNotReady"); got != tt.expectEvent {
+				t.Errorf("Expected ToolsNotReady event=%v, got %v", tt.expectEvent, got)
+			}
+
+			// Still waiting: no repeated event
+			if _, err := reconciler.checkToolsReady(context.Background(), agent); err != nil {
+				t.Fatalf("checkToolsReady failed: %v", err)
+			}
+			if events := drainEvents(reconciler.Recorder.(*record.FakeRecorder)); len(events) != 0 {
+				t.Errorf("Expected no repeated events, got %v", events)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_ToolsReadyWithoutTools(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "default"},
+	}
+	SetCondition(&agent.Status.Conditions, ToolsReadyCondition, metav1.ConditionFalse, "ToolsNotReady", "stale", 0)
+
+	reconciler, _ := newForceWorkloadReconciler(t)
+	wait, err := reconciler.checkToolsReady(context.Background(), agent)
+	if err != nil {
+		t.Fatalf("checkToolsReady failed: %v", err)
+	}
+	if wait != 0 {
+		t.Errorf("Expected to proceed, got a requeue after %v", wait)
+	}
+	if meta.FindStatusCondition(agent.Status.Conditions, ToolsReadyCondition) != nil {
+		t.Errorf("Expected the %s condition to be removed", ToolsReadyCondition)
+	}
+}
+
+func TestLanguageAgentController_ToolsReadyOtherNamespace(t *testing.T) {
+	tool := &langopv1alpha1.LanguageTool{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-search", Namespace: "shared-tools"},
+		Spec:       langopv1alpha1.LanguageToolSpec{Image: "ghcr.io/language-operator/web-search:latest", Type: "mcp"},
+		Status: langopv1alpha1.LanguageToolStatus{
+			Phase:       "Running",
+			ToolSchemas: []langopv1alpha1.ToolSchema{{Name: "search"}},
+		},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "researcher", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ToolRefs: []langopv1alpha1.ToolReference{{Name: "web-search", Namespace: "shared-tools"}},
+		},
+	}
+
+	reconciler, _ := newForceWorkloadReconciler(t, tool)
+	wait, err := reconciler.checkToolsReady(context.Background(), agent)
+	if err != nil {
+		t.Fatalf("checkToolsReady failed: %v", err)
+	}
+	if wait != 0 {
+		t.Errorf("Expected the tool in the referenced namespace to be ready, got a requeue after %v", wait)
+	}
+	if !meta.IsStatusConditionTrue(agent.Status.Conditions, ToolsReadyCondition) {
+		t.Errorf("Expected %s=True, got %+v", ToolsReadyCondition, agent.Status.Conditions)
+	}
+}
+
+func TestLanguageAgentController_ToolsReadyWaitExpires(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "researcher", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			ToolRefs: []langopv1alpha1.ToolReference{{Name: "web-search"}},
+		},
+	}
+	agent.Status.Conditions = []metav1.Condition{{
+		Type:               ToolsReadyCondition,
+		Status:             metav1.ConditionFalse,
+		Reason:             "ToolsNotReady",
+		Message:            "Waiting for tools: web-search (not found)",
+		LastTransitionTime: metav1.NewTime(time.Now().Add(-maxToolsReadyWait)),
+	}}
+
+	reconciler, _ := newForceWorkloadReconciler(t)
+	recorder := reconciler.Recorder.(*record.FakeRecorder)
+	for i := 0; i < 2; i++ {
+		wait, err := reconciler.checkToolsReady(context.Background(), agent)
+		if err != nil {
+			t.Fatalf("checkToolsReady failed: %v", err)
+		}
+		if wait != 0 {
+			t.Errorf("Expected to proceed after waiting %v, got a requeue after %v", maxToolsReadyWait, wait)
+		}
+	}
+
+	condition := meta.FindStatusCondition(agent.Status.Conditions, ToolsReadyCondition)
+	if condition == nil || !strings.HasPrefix(condition.Message, "Proceeding without tools: web-search") {
+		t.Errorf("Expected the condition to say synthesis proceeds without the tool, got %+v", condition)
+	}
+	if events := drainEvents(recorder); len(events) != 1 || !hasEvent(events, "ToolsWaitExpired") {
+		t.Errorf("Expected a single ToolsWaitExpired event, got %v", events)
+	}
+}
+
+func TestLanguageAgentController_ToolsReadyGatesOnlySynthesis(t *testing.T) {
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-model", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageModelSpec{Provider: "openai", ModelName: "gpt-4"},
+	}
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "researcher", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "autonomous",
+			Instructions:  "Research the news",
+			ModelRefs:     []langopv1alpha1.ModelReference{{Name: "test-model"}},
+			ToolRefs:      []langopv1alpha1.ToolReference{{Name: "web-search"}},
+		},
+	}
+
+	tool := &langopv1alpha1.LanguageTool{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-search", Namespace: "default"},
+		Spec:       langopv1alpha1.LanguageToolSpec{Image: "ghcr.io/language-operator/web-search:latest", Type: "mcp"},
+		Status:     langopv1alpha1.LanguageToolStatus{Phase: "Pending"},
+	}
+
+	reconciler, fakeClient := newForceWorkloadReconciler(t, model, tool, agent)
+	annotations := map[string]string{
+		"langop.io/instructions-hash": hashString(reconciler.synthesisInstructions(agent)),
+		"langop.io/tools-hash":        hashString(strings.Join(reconciler.getToolNames(agent), ",")),
+		"langop.io/models-hash":       hashString(strings.Join(reconciler.getModelNames(agent), ",")),
+		"langop.io/persona-hash":      hashString(strings.Join(reconciler.getPersonaNames(agent), ",")),
+	}
+	codeConfigMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        GenerateConfigMapName(agent.Name, "code"),
+			Namespace:   agent.Namespace,
+			Annotations: annotations,
+		},
+		Data: map[string]string{"agent.rb": "agent \"researcher\" do\n  mode :autonomous\nend\n"},
+	}
+	ctx := context.Background()
+	if err := fakeClient.Create(ctx, codeConfigMap); err != nil {
+		t.Fatalf("Failed to create code ConfigMap: %v", err)
+	}
+	key := types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}
+
+	// Already synthesized: a pending tool doesn't hold up the rest of the agent
+	if _, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key}); err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if err := fakeClient.Get(ctx, key, &appsv1.Deployment{}); err != nil {
+		t.Errorf("Expected a Deployment for the synthesized agent, got error: %v", err)
+	}
+	updated := &langopv1alpha1.LanguageAgent{}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if meta.FindStatusCondition(updated.Status.Conditions, ToolsReadyCondition) != nil {
+		t.Errorf("Expected tools to be checked only before synthesis, got %+v", updated.Status.Conditions)
+	}
+
+	// Changed instructions wait for the tool, while the existing code keeps running
+	updated.Spec.Instructions = "Research the weather"
+	if err := fakeClient.Update(ctx, updated); err != nil {
+		t.Fatalf("Failed to update agent: %v", err)
+	}
+	if err := fakeClient.Delete(ctx, &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace}}); err != nil {
+		t.Fatalf("Failed to delete Deployment: %v", err)
+	}
+	result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	if err != nil {
+		t.Fatalf("Reconcile failed: %v", err)
+	}
+	if result.RequeueAfter < minToolsReadyBackoff {
+		t.Errorf("Expected a requeue of at least %v while waiting for tools, got %v", minToolsReadyBackoff, result.RequeueAfter)
+	}
+	if err := fakeClient.Get(ctx, key, &appsv1.Deployment{}); err != nil {
+		t.Errorf("Expected the Deployment to be reconciled while waiting for tools, got error: %v", err)
+	}
+	if err := fakeClient.Get(ctx, key, updated); err != nil {
+		t.Fatalf("Failed to get agent: %v", err)
+	}
+	if !meta.IsStatusConditionFalse(updated.Status.Conditions, ToolsReadyCondition) {
+		t.Errorf("Expected %s=False while waiting for the tool, got %+v", ToolsReadyCondition, updated.Status.Conditions)
+	}
+	code := &corev1.ConfigMap{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: codeConfigMap.Name, Namespace: agent.Namespace}, code); err != nil {
+		t.Fatalf("Failed to get code ConfigMap: %v", err)
+	}
+	if code.Annotations["langop.io/instructions-hash"] != annotations["langop.io/instructions-hash"] {
+		t.Errorf("Expected the code not to be re-synthesized while waiting for tools")
+	}
+}
@@ -0,0 +1,178 @@
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+const (
+	// warmReloadPath is the endpoint of the agent webhook server that loads new code in place
+	warmReloadPath = "/reload"
+	// agentWebhookPort is the port every agent's webhook server listens on
+	agentWebhookPort = 8080
+	// warmReloadTimeout bounds a single reload request
+	warmReloadTimeout = 10 * time.Second
+	// restartedAtAnnotation on a pod template rolls the Deployment, as kubectl rollout restart does
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+)
+
+// ErrWarmReloadUnsupported is returned when the agent runtime does not serve the reload endpoint
+var ErrWarmReloadUnsupported = errors.New("agent runtime does not support warm reload")
+
+// AgentReloader asks a running agent pod to load new code without restarting. The pod only gets
+// the hash of the new code and loads it from its mounted code ConfigMap once that matches.
+type AgentReloader interface {
+	Reload(ctx context.Context, pod *corev1.Pod, codeHash string) error
+}
+
+// HTTPAgentReloader signals the reload endpoint of the agent's webhook server. The request
+// carries no code, since the webhook port may be reachable from outside the cluster.
+type HTTPAgentReloader struct {
+	Client *http.Client
+	// Port overrides the agent webhook port (default 8080)
+	Port int
+}
+
+// Reload implements AgentReloader
+func (h *HTTPAgentReloader) Reload(ctx context.Context, pod *corev1.Pod, codeHash string) error {
+	if pod.Status.PodIP == "" {
+		return fmt.Errorf("pod %s has no IP", pod.Name)
+	}
+
+	body, err := json.Marshal(map[string]string{"codeHash": codeHash})
+	if err != nil {
+		return err
+	}
+
+	port := h.Port
+	if port == 0 {
+		port = agentWebhookPort
+	}
+	url := fmt.Sprintf("http://%s:%d%s", pod.Status.PodIP, port, warmReloadPath)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := h.Client
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: warmReloadTimeout}
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reload request to pod %s failed: %w", pod.Name, err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusMethodNotAllowed, resp.StatusCode == http.StatusNotImplemented:
+		return fmt.Errorf("pod %s: %w", pod.Name, ErrWarmReloadUnsupported)
+	default:
+		return fmt.Errorf("pod %s rejected reload with status %d", pod.Name, resp.StatusCode)
+	}
+}
+
+// listRunningAgentPods returns the agent's pods that are running and reachable
+func listRunningAgentPods(ctx context.Context, c client.Client, agent *langopv1alpha1.LanguageAgent) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	labels := GetCommonLabels(agent.Name, "LanguageAgent")
+	if err := c.List(ctx, podList, client.InNamespace(agent.Namespace), client.MatchingLabels(labels)); err != nil {
+		return nil, err
+	}
+
+	var running []corev1.Pod
+	for _, pod := range podList.Items {
+		if pod.Status.Phase == corev1.PodRunning && pod.DeletionTimestamp == nil {
+			running = append(running, pod)
+		}
+	}
+	return running, nil
+}
+
+// rolloutRestartAgent restarts the agent's Deployment the way kubectl rollout restart does, so its
+// pods are replaced with the current code under the Deployment's update strategy
+func rolloutRestartAgent(ctx context.Context, c client.Client, agent *langopv1alpha1.LanguageAgent) error {
+	deployment := &appsv1.Deployment{}
+	if err := c.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, deployment); err != nil {
+		if apierrors.IsNotFound(err) {
+			// Scheduled agents have no Deployment; each run starts from the current code
+			return nil
+		}
+		return fmt.Errorf("failed to get deployment: %w", err)
+	}
+
+	patch := client.MergeFrom(deployment.DeepCopy())
+	if deployment.Spec.Template.Annotations == nil {
+		deployment.Spec.Template.Annotations = make(map[string]string)
+	}
+	deployment.Spec.Template.Annotations[restartedAtAnnotation] = time.Now().UTC().Format(time.RFC3339)
+	if err := c.Patch(ctx, deployment, patch); err != nil {
+		return fmt.Errorf("failed to restart deployment %s: %w", deployment.Name, err)
+	}
+	return nil
+}
+
+// warmReloadAgent asks every running pod of an agent with spec.warmReload to load code in place.
+// It returns false when the agent doesn't use warm reload, or when any pod could not reload,
+// in which case the caller must fall back to restarting the Deployment.
+func warmReloadAgent(ctx context.Context, c client.Client, reloader AgentReloader, recorder record.EventRecorder, agent *langopv1alpha1.LanguageAgent, code string) bool {
+	if !agent.Spec.WarmReload {
+		return false
+	}
+	logger := log.FromContext(ctx)
+	if reloader == nil {
+		reloader = &HTTPAgentReloader{}
+	}
+
+	codeHash := hashString(code)
+	pods, err := listRunningAgentPods(ctx, c, agent)
+	if err == nil {
+		for i := range pods {
+			if err = reloader.Reload(ctx, &pods[i], codeHash); err != nil {
+				break
+			}
+		}
+	}
+	if err != nil {
+		logger.Info("Warm reload failed, restarting the agent", "agent", agent.Name, "error", err.Error())
+		if recorder != nil {
+			recorder.Eventf(agent, corev1.EventTypeWarning, "WarmReloadFailed", "Warm reload failed, restarting the agent: %v", err)
+		}
+		return false
+	}
+
+	if recorder != nil && len(pods) > 0 {
+		recorder.Eventf(agent, corev1.EventTypeNormal, "CodeReloaded", "Reloaded new code in %d running pod(s) without restart", len(pods))
+	}
+	return true
+}
+
+// deliverCode makes the agent's running pods pick up newly written code: agents with
+// spec.warmReload reload it in place, falling back to a rollout restart when that fails
+func (r *LanguageAgentReconciler) deliverCode(ctx context.Context, agent *langopv1alpha1.LanguageAgent, code string) error {
+	if !agent.Spec.WarmReload {
+		return nil
+	}
+	if warmReloadAgent(ctx, r.Client, r.Reloader, r.Recorder, agent, code) {
+		return nil
+	}
+	return rolloutRestartAgent(ctx, r.Client, agent)
+}
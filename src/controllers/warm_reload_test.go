@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"github.com/language-operator/language-operator/controllers/testutil"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// mockAgentReloader records reloaded pods and fails with err when set
+type mockAgentReloader struct {
+	err      error
+	reloaded []string
+	codeHash string
+}
+
+func (m *mockAgentReloader) Reload(ctx context.Context, pod *corev1.Pod, codeHash string) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.reloaded = append(m.reloaded, pod.Name)
+	m.codeHash = codeHash
+	return nil
+}
+
+func newRunningAgentPod(name string, agent *langopv1alpha1.LanguageAgent) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: agent.Namespace,
+			Labels:    GetCommonLabels(agent.Name, "LanguageAgent"),
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning, PodIP: "10.0.0.1"},
+	}
+}
+
+func TestDeliverCode(t *testing.T) {
+	tests := []struct {
+		name            string
+		warmReload      bool
+		reloadErr       error
+		expectReloaded  bool
+		expectRestarted bool
+		expectedEvent   string
+	}{
+		{name: "warm reload signals the agent", warmReload: true, expectReloaded: true, expectedEvent: "CodeReloaded"},
+		{name: "unsupported reload falls back to restart", warmReload: true, reloadErr: ErrWarmReloadUnsupported, expectRestarted: true, expectedEvent: "WarmReloadFailed"},
+		{name: "failed reload falls back to restart", warmReload: true, reloadErr: errors.New("connection refused"), expectRestarted: true, expectedEvent: "WarmReloadFailed"},
+		{name: "warm reload disabled", warmReload: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			agent := newSelfHealingTestAgent()
+			agent.Spec.WarmReload = tt.warmReload
+			pod := newRunningAgentPod("unhealthy-agent-abc", agent)
+			deployment := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: agent.Name, Namespace: agent.Namespace}}
+
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(agent, pod, deployment).Build()
+			recorder := record.NewFakeRecorder(10)
+			reloader := &mockAgentReloader{err: tt.reloadErr}
+			reconciler := &LanguageAgentReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Log:      logr.Discard(),
+				Recorder: recorder,
+				Reloader: reloader,
+			}
+
+			if err := reconciler.deliverCode(context.Background(), agent, "agent 'new' do\nend"); err != nil {
+				t.Fatalf("deliverCode failed: %v", err)
+			}
+
+			if reloaded := len(reloader.reloaded) == 1 && reloader.codeHash == hashString("agent 'new' do\nend"); reloaded != tt.expectReloaded {
+				t.Errorf("Expected reloaded=%v, got pods %v", tt.expectReloaded, reloader.reloaded)
+			}
+
+			updated := &appsv1.Deployment{}
+			if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, updated); err != nil {
+				t.Fatalf("Failed to get deployment: %v", err)
+			}
+			if _, restarted := updated.Spec.Template.Annotations[restartedAtAnnotation]; restarted != tt.expectRestarted {
+				t.Errorf("Expected restarted=%v, got template annotations %v", tt.expectRestarted, updated.Spec.Template.Annotations)
+			}
+			if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: pod.Name, Namespace: pod.Namespace}, &corev1.Pod{}); err != nil {
+				t.Errorf("Expected the pod to be left to the rollout, got %v", err)
+			}
+
+			events := drainEvents(recorder)
+			if tt.expectedEvent != "" && !hasEvent(events, tt.expectedEvent) {
+				t.Errorf("Expected %s event, got %v", tt.expectedEvent, events)
+			}
+		})
+	}
+}
+
+func TestHTTPAgentReloader(t *testing.T) {
+	tests := []struct {
+		name              string
+		status            int
+		expectErr         bool
+		expectUnsupported bool
+	}{
+		{name: "reloaded", status: http.StatusOK},
+		{name: "runtime without reload endpoint", status: http.StatusNotFound, expectErr: true, expectUnsupported: true},
+		{name: "reload rejected", status: http.StatusUnprocessableEntity, expectErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var path, method string
+			var body map[string]string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				path, method = r.URL.Path, r.Method
+				_ = json.NewDecoder(r.Body).Decode(&body)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			host, portStr, _ := net.SplitHostPort(server.Listener.Addr().String())
+			port, _ := strconv.Atoi(portStr)
+			pod := &corev1.Pod{
+				ObjectMeta: metav1.ObjectMeta{Name: "agent-abc"},
+				Status:     corev1.PodStatus{PodIP: host},
+			}
+
+			err := (&HTTPAgentReloader{Port: port}).Reload(context.Background(), pod, hashString("agent 'new' do\nend"))
+			if (err != nil) != tt.expectErr {
+				t.Fatalf("Expected error=%v, got %v", tt.expectErr, err)
+			}
+			if errors.Is(err, ErrWarmReloadUnsupported) != tt.expectUnsupported {
+				t.Errorf("Expected unsupported=%v, got %v", tt.expectUnsupported, err)
+			}
+			if path != warmReloadPath || method != http.MethodPost {
+				t.Errorf("Expected POST %s, got %s %s", warmReloadPath, method, path)
+			}
+			if len(body) != 1 || body["codeHash"] != hashString("agent 'new' do\nend") {
+				t.Errorf("Expected only the code hash in the reload signal, got %v", body)
+			}
+		})
+	}
+}
+
+func TestLearningReconciler_WarmReloadLearnedCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		reloadErr error
+		expected  bool
+	}{
+		{name: "reloaded in place", expected: true},
+		{name: "reload failure rolls the deployment", reloadErr: errors.New("connection refused"), expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			scheme := testutil.SetupTestScheme(t)
+			agent := newSelfHealingTestAgent()
+			agent.Spec.WarmReload = true
+			codeConfigMap := &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: GenerateConfigMapName(agent.Name, "code"), Namespace: agent.Namespace},
+				Data:       map[string]string{"agent.rb": "agent 'old' do\nend"},
+			}
+
+			fakeClient := fake.NewClientBuilder().
+				WithScheme(scheme).
+				WithObjects(agent, codeConfigMap, newRunningAgentPod("unhealthy-agent-abc", agent)).
+				Build()
+			reconciler := &LearningReconciler{
+				Client:   fakeClient,
+				Scheme:   scheme,
+				Log:      logr.Discard(),
+				Recorder: record.NewFakeRecorder(10),
+				Reloader: &mockAgentReloader{err: tt.reloadErr},
+			}
+
+			if got := reconciler.warmReloadLearnedCode(context.Background(), agent, "agent 'learned' do\nend"); got != tt.expected {
+				t.Errorf("Expected %v, got %v", tt.expected, got)
+			}
+
+			updated := &corev1.ConfigMap{}
+			if err := fakeClient.Get(context.Background(), types.NamespacedName{Name: codeConfigMap.Name, Namespace: agent.Namespace}, updated); err != nil {
+				t.Fatalf("Failed to get code ConfigMap: %v", err)
+			}
+			if updated.Data["agent.rb"] != "agent 'learned' do\nend" || updated.Annotations[codeHashAnnotation] != hashString("agent 'learned' do\nend") {
+				t.Errorf("Expected learned code and hash in the code ConfigMap, got %v %v", updated.Data, updated.Annotations)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// agentServicePort is the agent Service port that fronts the webhook server on agentWebhookPort
+const agentServicePort int32 = 80
+
+// webhookBackend routes requests under PathPrefix to ServicePort of the agent Service
+type webhookBackend struct {
+	PathPrefix  string
+	ServicePort int32
+}
+
+// webhookServicePort returns the agent Service port that forwards to a container port. The
+// webhook server is behind port 80; other route ports are exposed on their own number.
+func webhookServicePort(containerPort int32) int32 {
+	if containerPort == 0 || containerPort == agentWebhookPort {
+		return agentServicePort
+	}
+	return containerPort
+}
+
+// webhookBackends returns the agent's spec.webhookRoutes as Service backends, or a single
+// catch-all route to the webhook server when none are set
+func webhookBackends(agent *langopv1alpha1.LanguageAgent) []webhookBackend {
+	if len(agent.Spec.WebhookRoutes) == 0 {
+		return []webhookBackend{{PathPrefix: "/", ServicePort: agentServicePort}}
+	}
+	backends := make([]webhookBackend, 0, len(agent.Spec.WebhookRoutes))
+	for _, route := range agent.Spec.WebhookRoutes {
+		backends = append(backends, webhookBackend{PathPrefix: route.PathPrefix, ServicePort: webhookServicePort(route.Port)})
+	}
+	return backends
+}
+
+// agentContainerPorts declares the ports spec.webhookRoutes sends to the agent container besides
+// its webhook server, so they can't be taken by a sidecar tool
+func agentContainerPorts(agent *langopv1alpha1.LanguageAgent) []corev1.ContainerPort {
+	var ports []corev1.ContainerPort
+	seen := map[int32]bool{agentWebhookPort: true}
+	for _, route := range agent.Spec.WebhookRoutes {
+		if route.Port == 0 || seen[route.Port] {
+			continue
+		}
+		seen[route.Port] = true
+		ports = append(ports, corev1.ContainerPort{
+			Name:          fmt.Sprintf("route-%d", route.Port),
+			ContainerPort: route.Port,
+			Protocol:      corev1.ProtocolTCP,
+		})
+	}
+	return ports
+}
+
+// agentServicePorts returns the agent Service ports: the webhook server, plus one port for each
+// other container port targeted by spec.webhookRoutes
+func agentServicePorts(agent *langopv1alpha1.LanguageAgent) []corev1.ServicePort {
+	ports := []corev1.ServicePort{{
+		Name:       "http",
+		Port:       agentServicePort,
+		TargetPort: intstr.FromInt32(agentWebhookPort),
+		Protocol:   corev1.ProtocolTCP,
+	}}
+	seen := map[int32]bool{agentServicePort: true}
+	for _, route := range agent.Spec.WebhookRoutes {
+		servicePort := webhookServicePort(route.Port)
+		if seen[servicePort] {
+			continue
+		}
+		seen[servicePort] = true
+		ports = append(ports, corev1.ServicePort{
+			Name:       fmt.Sprintf("route-%d", route.Port),
+			Port:       servicePort,
+			TargetPort: intstr.FromInt32(route.Port),
+			Protocol:   corev1.ProtocolTCP,
+		})
+	}
+	return ports
+}
+
+// httpRouteRules renders the agent's webhook routes as HTTPRoute rules
+func httpRouteRules(agent *langopv1alpha1.LanguageAgent) []interface{} {
+	var rules []interface{}
+	for _, backend := range webhookBackends(agent) {
+		rules = append(rules, map[string]interface{}{
+			"matches": []interface{}{
+				map[string]interface{}{
+					"path": map[string]interface{}{
+						"type":  "PathPrefix",
+						"value": backend.PathPrefix,
+					},
+				},
+			},
+			"backendRefs": []interface{}{
+				map[string]interface{}{
+					"name": agent.Name,
+					"port": int64(backend.ServicePort),
+				},
+			},
+		})
+	}
+	return rules
+}
+
+// ingressPaths renders the agent's webhook routes as Ingress paths
+func ingressPaths(agent *langopv1alpha1.LanguageAgent) []networkingv1.HTTPIngressPath {
+	pathType := networkingv1.PathTypePrefix
+	var paths []networkingv1.HTTPIngressPath
+	for _, backend := range webhookBackends(agent) {
+		paths = append(paths, networkingv1.HTTPIngressPath{
+			Path:     backend.PathPrefix,
+			PathType: &pathType,
+			Backend: networkingv1.IngressBackend{
+				Service: &networkingv1.IngressServiceBackend{
+					Name: agent.Name,
+					Port: networkingv1.ServiceBackendPort{
+						Number: backend.ServicePort,
+					},
+				},
+			},
+		})
+	}
+	return paths
+}
@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+const (
+	// DefaultWorkspaceSeedImage is the seed init container image when spec.workspace.seed.image is unset
+	DefaultWorkspaceSeedImage = "alpine/git:2.45.2"
+
+	// WorkspaceSeedContainerName is the name of the init container that seeds the workspace
+	WorkspaceSeedContainerName = "workspace-seed"
+
+	// gitSecretCredentialHelper answers Git's credential prompts from the seed Secret's keys
+	gitSecretCredentialHelper = `!f() { echo "username=$GIT_USERNAME"; echo "password=$GIT_PASSWORD"; }; f`
+
+	// workspaceSeedVolume holds the ConfigMap a workspace is seeded from
+	workspaceSeedVolume    = "workspace-seed"
+	workspaceSeedMountPath = "/etc/langop/seed"
+)
+
+// workspaceSeedPrelude skips seeding a workspace that was already seeded, so restarts keep the
+// agent's changes to it
+const workspaceSeedPrelude = `set -e
+if [ -e "$WORKSPACE_DIR/.langop-seeded" ]; then
+  echo "Workspace already seeded"
+  exit 0
+fi
+`
+
+// workspaceSeedGitScript clones the repository next to the workspace contents and copies it in,
+// since a workspace volume may already hold entries such as lost+found
+const workspaceSeedGitScript = workspaceSeedPrelude + `set --
+if [ -n "$GIT_REF" ]; then
+  set -- --branch "$GIT_REF"
+fi
+rm -rf "$WORKSPACE_DIR/.langop-seed"
+git clone --depth 1 "$@" "$GIT_URL" "$WORKSPACE_DIR/.langop-seed"
+cp -a "$WORKSPACE_DIR/.langop-seed/." "$WORKSPACE_DIR/"
+rm -rf "$WORKSPACE_DIR/.langop-seed"
+touch "$WORKSPACE_DIR/.langop-seeded"
+`
+
+// workspaceSeedConfigMapScript copies the ConfigMap's keys, skipping the volume's hidden
+// ..data entries. An empty ConfigMap leaves the glob unexpanded, so a missing file is skipped.
+const workspaceSeedConfigMapScript = workspaceSeedPrelude + `for f in ` + workspaceSeedMountPath + `/*; do
+  [ -e "$f" ] || continue
+  cp -L "$f" "$WORKSPACE_DIR/"
+done
+touch "$WORKSPACE_DIR/.langop-seeded"
+`
+
+// workspaceSeed returns the agent's workspace seed, or nil when the workspace isn't seeded
+func workspaceSeed(agent *langopv1alpha1.LanguageAgent) *langopv1alpha1.WorkspaceSeed {
+	if agent.Spec.Workspace == nil || !agent.Spec.Workspace.Enabled {
+		return nil
+	}
+	return agent.Spec.Workspace.Seed
+}
+
+// workspaceMountPath returns where the agent's workspace is mounted
+func workspaceMountPath(agent *langopv1alpha1.LanguageAgent) string {
+	if agent.Spec.Workspace != nil && agent.Spec.Workspace.MountPath != "" {
+		return agent.Spec.Workspace.MountPath
+	}
+	return "/workspace"
+}
+
+// workspaceSeedVolumes returns the volumes the seed init container needs besides the workspace
+func workspaceSeedVolumes(agent *langopv1alpha1.LanguageAgent) []corev1.Volume {
+	seed := workspaceSeed(agent)
+	if seed == nil || seed.ConfigMapRef == nil {
+		return nil
+	}
+	return []corev1.Volume{{
+		Name: workspaceSeedVolume,
+		VolumeSource: corev1.VolumeSource{
+			ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: *seed.ConfigMapRef},
+		},
+	}}
+}
+
+// buildWorkspaceSeedContainer returns the init container that seeds the agent's workspace, or
+// nil when no seed is configured. It only writes to the workspace mount, so it runs with the
+// agent's read-only root filesystem.
+func (r *LanguageAgentReconciler) buildWorkspaceSeedContainer(agent *langopv1alpha1.LanguageAgent) *corev1.Container {
+	seed := workspaceSeed(agent)
+	if seed == nil || (seed.GitRepo == nil && seed.ConfigMapRef == nil) {
+		return nil
+	}
+
+	image := seed.Image
+	if image == "" {
+		image = DefaultWorkspaceSeedImage
+	}
+	container := &corev1.Container{
+		Name:            WorkspaceSeedContainerName,
+		Image:           image,
+		Env:             []corev1.EnvVar{{Name: "WORKSPACE_DIR", Value: workspaceMountPath(agent)}},
+		VolumeMounts:    []corev1.VolumeMount{{Name: "workspace", MountPath: workspaceMountPath(agent)}},
+		SecurityContext: r.buildContainerSecurityContext(),
+	}
+
+	if git := seed.GitRepo; git != nil {
+		container.Command = []string{"/bin/sh", "-c", workspaceSeedGitScript}
+		container.Env = append(container.Env,
+			corev1.EnvVar{Name: "GIT_URL", Value: git.URL},
+			corev1.EnvVar{Name: "GIT_REF", Value: git.Ref},
+			corev1.EnvVar{Name: "GIT_TERMINAL_PROMPT", Value: "0"},
+		)
+		if git.SecretRef != nil {
+			container.Env = append(container.Env,
+				corev1.EnvVar{Name: "GIT_CONFIG_COUNT", Value: "1"},
+				corev1.EnvVar{Name: "GIT_CONFIG_KEY_0", Value: "credential.helper"},
+				corev1.EnvVar{Name: "GIT_CONFIG_VALUE_0", Value: gitSecretCredentialHelper},
+			)
+			for _, credential := range []struct{ env, key string }{
+				{"GIT_USERNAME", "username"},
+				{"GIT_PASSWORD", "password"},
+			} {
+				container.Env = append(container.Env, corev1.EnvVar{
+					Name: credential.env,
+					ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: *git.SecretRef, Key: credential.key},
+					},
+				})
+			}
+		}
+		return container
+	}
+
+	container.Command = []string{"/bin/sh", "-c", workspaceSeedConfigMapScript}
+	container.VolumeMounts = append(container.VolumeMounts, corev1.VolumeMount{
+		Name:      workspaceSeedVolume,
+		MountPath: workspaceSeedMountPath,
+		ReadOnly:  true,
+	})
+	return container
+}
+
+// applyWorkspaceSeed runs the workspace seed init container first, before the sidecar tools
+// (which are also init containers) start and mount the workspace. Process namespace sharing
+// stays keyed to the sidecars, since the seed container exits before the agent starts.
+func (r *LanguageAgentReconciler) applyWorkspaceSeed(agent *langopv1alpha1.LanguageAgent, podSpec *corev1.PodSpec) {
+	seedContainer := r.buildWorkspaceSeedContainer(agent)
+	if seedContainer == nil {
+		return
+	}
+	podSpec.InitContainers = append([]corev1.Container{*seedContainer}, podSpec.InitContainers...)
+}
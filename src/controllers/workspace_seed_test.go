@@ -0,0 +1,256 @@
+package controllers
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// containerEnv returns the value of a container's environment variable
+func containerEnv(container corev1.Container, name string) (corev1.EnvVar, bool) {
+	for _, env := range container.Env {
+		if env.Name == name {
+			return env, true
+		}
+	}
+	return corev1.EnvVar{}, false
+}
+
+func TestLanguageAgentController_WorkspaceSeedDeployment(t *testing.T) {
+	tests := []struct {
+		name        string
+		workspace   *langopv1alpha1.WorkspaceSpec
+		expectSeed  bool
+		expectInits int
+	}{
+		{
+			name:        "no workspace",
+			expectInits: 1,
+		},
+		{
+			name:        "workspace without seed",
+			workspace:   &langopv1alpha1.WorkspaceSpec{Enabled: true, Size: "1Gi"},
+			expectInits: 1,
+		},
+		{
+			name: "disabled workspace isn't seeded",
+			workspace: &langopv1alpha1.WorkspaceSpec{Size: "1Gi", Seed: &langopv1alpha1.WorkspaceSeed{
+				GitRepo: &langopv1alpha1.GitRepoSeed{URL: "https://github.com/example/runbooks.git"},
+			}},
+			expectInits: 1,
+		},
+		{
+			name: "git seed",
+			workspace: &langopv1alpha1.WorkspaceSpec{Enabled: true, Size: "1Gi", Seed: &langopv1alpha1.WorkspaceSeed{
+				GitRepo: &langopv1alpha1.GitRepoSeed{
+					URL:       "https://github.com/example/runbooks.git",
+					Ref:       "v1.2.0",
+					SecretRef: &corev1.LocalObjectReference{Name: "git-credentials"},
+				},
+			}},
+			expectSeed:  true,
+			expectInits: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tool := &langopv1alpha1.LanguageTool{
+				ObjectMeta: metav1.ObjectMeta{Name: "web-search", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageToolSpec{
+					Image:          "ghcr.io/language-operator/web-tool:latest",
+					DeploymentMode: "sidecar",
+					Port:           3000,
+				},
+			}
+			agent := &langopv1alpha1.LanguageAgent{
+				ObjectMeta: metav1.ObjectMeta{Name: "seeded-agent", Namespace: "default"},
+				Spec: langopv1alpha1.LanguageAgentSpec{
+					Image:         "ghcr.io/language-operator/agent:latest",
+					ExecutionMode: "autonomous",
+					ToolRefs:      []langopv1alpha1.ToolReference{{Name: "web-search"}},
+					Workspace:     tt.workspace,
+				},
+			}
+			reconciler, fakeClient := newForceWorkloadReconciler(t, tool, agent)
+
+			ctx := context.Background()
+			if err := reconciler.reconcileDeployment(ctx, agent); err != nil {
+				t.Fatalf("reconcileDeployment failed: %v", err)
+			}
+			deployment := &appsv1.Deployment{}
+			if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, deployment); err != nil {
+				t.Fatalf("Failed to get Deployment: %v", err)
+			}
+
+			podSpec := deployment.Spec.Template.Spec
+			if len(podSpec.InitContainers) != tt.expectInits {
+				t.Fatalf("Expected %d init containers, got %d", tt.expectInits, len(podSpec.InitContainers))
+			}
+			// The sidecar tool alone decides process namespace sharing
+			if podSpec.ShareProcessNamespace == nil || !*podSpec.ShareProcessNamespace {
+				t.Errorf("Expected the process namespace to be shared with the sidecar tool")
+			}
+			if !tt.expectSeed {
+				for _, container := range podSpec.InitContainers {
+					if container.Name == WorkspaceSeedContainerName {
+						t.Errorf("Expected no workspace seed container")
+					}
+				}
+				return
+			}
+
+			// The seed runs before the sidecar tool
+			seed := podSpec.InitContainers[0]
+			if seed.Name != WorkspaceSeedContainerName {
+				t.Fatalf("Expected the first init container to be %s, got %s", WorkspaceSeedContainerName, seed.Name)
+			}
+			if podSpec.InitContainers[1].Name != "tool-web-search" {
+				t.Errorf("Expected the sidecar tool after the seed container, got %s", podSpec.InitContainers[1].Name)
+			}
+			if seed.RestartPolicy != nil {
+				t.Errorf("Expected the seed container to run to completion, got restart policy %s", *seed.RestartPolicy)
+			}
+			if seed.Image != DefaultWorkspaceSeedImage {
+				t.Errorf("Expected image %s, got %s", DefaultWorkspaceSeedImage, seed.Image)
+			}
+			if seed.SecurityContext == nil || seed.SecurityContext.ReadOnlyRootFilesystem == nil || !*seed.SecurityContext.ReadOnlyRootFilesystem {
+				t.Errorf("Expected the seed container to keep a read-only root filesystem")
+			}
+			if len(seed.VolumeMounts) != 1 || seed.VolumeMounts[0].Name != "workspace" || seed.VolumeMounts[0].MountPath != "/workspace" {
+				t.Errorf("Expected the seed container to mount only the workspace, got %+v", seed.VolumeMounts)
+			}
+			if env, _ := containerEnv(seed, "GIT_URL"); env.Value != "https://github.com/example/runbooks.git" {
+				t.Errorf("Expected GIT_URL from the seed, got %q", env.Value)
+			}
+			if env, _ := containerEnv(seed, "GIT_REF"); env.Value != "v1.2.0" {
+				t.Errorf("Expected GIT_REF v1.2.0, got %q", env.Value)
+			}
+			password, ok := containerEnv(seed, "GIT_PASSWORD")
+			if !ok || password.ValueFrom == nil || password.ValueFrom.SecretKeyRef == nil ||
+				password.ValueFrom.SecretKeyRef.Name != "git-credentials" || password.ValueFrom.SecretKeyRef.Key != "password" {
+				t.Errorf("Expected GIT_PASSWORD from the git-credentials Secret, got %+v", password)
+			}
+		})
+	}
+}
+
+func TestLanguageAgentController_WorkspaceSeedCronJobFromConfigMap(t *testing.T) {
+	agent := &langopv1alpha1.LanguageAgent{
+		ObjectMeta: metav1.ObjectMeta{Name: "seeded-report", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageAgentSpec{
+			Image:         "ghcr.io/language-operator/agent:latest",
+			ExecutionMode: "scheduled",
+			Schedule:      "0 9 * * *",
+			Workspace: &langopv1alpha1.WorkspaceSpec{
+				Enabled:   true,
+				Size:      "1Gi",
+				MountPath: "/data",
+				Seed: &langopv1alpha1.WorkspaceSeed{
+					ConfigMapRef: &corev1.LocalObjectReference{Name: "report-templates"},
+					Image:        "busybox:1.36",
+				},
+			},
+		},
+	}
+	reconciler, fakeClient := newForceWorkloadReconciler(t, agent)
+
+	ctx := context.Background()
+	if err := reconciler.reconcileCronJob(ctx, agent); err != nil {
+		t.Fatalf("reconcileCronJob failed: %v", err)
+	}
+	cronJob := &batchv1.CronJob{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: agent.Name, Namespace: agent.Namespace}, cronJob); err != nil {
+		t.Fatalf("Failed to get CronJob: %v", err)
+	}
+
+	podSpec := cronJob.Spec.JobTemplate.Spec.Template.Spec
+	if len(podSpec.InitContainers) != 1 || podSpec.InitContainers[0].Name != WorkspaceSeedContainerName {
+		t.Fatalf("Expected only the workspace seed init container, got %+v", podSpec.InitContainers)
+	}
+	seed := podSpec.InitContainers[0]
+	if seed.Image != "busybox:1.36" {
+		t.Errorf("Expected the seed image override, got %s", seed.Image)
+	}
+	if env, _ := containerEnv(seed, "WORKSPACE_DIR"); env.Value != "/data" {
+		t.Errorf("Expected WORKSPACE_DIR /data, got %q", env.Value)
+	}
+	if _, ok := containerEnv(seed, "GIT_URL"); ok {
+		t.Errorf("Expected no Git settings for a ConfigMap seed")
+	}
+
+	var seedVolume *corev1.Volume
+	for i := range podSpec.Volumes {
+		if podSpec.Volumes[i].Name == workspaceSeedVolume {
+			seedVolume = &podSpec.Volumes[i]
+		}
+	}
+	if seedVolume == nil || seedVolume.ConfigMap == nil || seedVolume.ConfigMap.Name != "report-templates" {
+		t.Fatalf("Expected a volume for the report-templates ConfigMap, got %+v", podSpec.Volumes)
+	}
+	for _, mount := range podSpec.Containers[0].VolumeMounts {
+		if mount.Name == workspaceSeedVolume {
+			t.Errorf("Expected the seed ConfigMap to be mounted only by the seed container")
+		}
+	}
+}
+
+func TestWorkspaceSeedConfigMapScript(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	tests := []struct {
+		name        string
+		files       map[string]string
+		expectFiles []string
+	}{
+		{name: "empty ConfigMap", expectFiles: []string{".langop-seeded"}},
+		{
+			name:        "ConfigMap keys",
+			files:       map[string]string{"README.md": "# notes", "plan.txt": "step 1"},
+			expectFiles: []string{".langop-seeded", "README.md", "plan.txt"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seedDir := t.TempDir()
+			workspaceDir := t.TempDir()
+			for name, content := range tt.files {
+				if err := os.WriteFile(filepath.Join(seedDir, name), []byte(content), 0o644); err != nil {
+					t.Fatalf("Failed to write seed file: %v", err)
+				}
+			}
+
+			script := strings.ReplaceAll(workspaceSeedConfigMapScript, workspaceSeedMountPath, seedDir)
+			cmd := exec.Command("sh", "-c", script)
+			cmd.Env = append(os.Environ(), "WORKSPACE_DIR="+workspaceDir)
+			if output, err := cmd.CombinedOutput(); err != nil {
+				t.Fatalf("Seed script failed: %v: %s", err, output)
+			}
+
+			entries, err := os.ReadDir(workspaceDir)
+			if err != nil {
+				t.Fatalf("Failed to read workspace: %v", err)
+			}
+			var names []string
+			for _, entry := range entries {
+				names = append(names, entry.Name())
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expectFiles, ",") {
+				t.Errorf("Expected workspace files %v, got %v", tt.expectFiles, names)
+			}
+		})
+	}
+}
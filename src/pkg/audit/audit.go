@@ -0,0 +1,227 @@
+package audit
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// SchemaVersion identifies the audit record schema. It changes only when fields are removed or change meaning.
+const SchemaVersion = "audit.langop.io/v1"
+
+// Actions recorded in the audit stream
+const (
+	ActionSynthesized          = "Synthesized"
+	ActionSynthesisFailed      = "SynthesisFailed"
+	ActionSelfHealingTriggered = "SelfHealingTriggered"
+	ActionLearningConverted    = "LearningConverted"
+	ActionLearningRollback     = "LearningRollback"
+)
+
+const (
+	// DefaultQueueSize bounds the records waiting to be written. Records emitted while the queue
+	// is full are dropped.
+	DefaultQueueSize = 1000
+
+	// maxWriteAttempts is how often a record is written before it is dropped
+	maxWriteAttempts = 5
+	// defaultRetryDelay is the delay before retrying a failed write, doubled for each further retry
+	defaultRetryDelay = 200 * time.Millisecond
+	// drainTimeout bounds writing the records still queued when the emitter stops
+	drainTimeout = 10 * time.Second
+)
+
+// RecordsDropped counts audit records that never reached the sink
+var RecordsDropped = prometheus.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "audit_records_dropped_total",
+		Help: "Total number of audit records dropped by reason",
+	},
+	[]string{"reason"}, // reason: queue_full or write_failed
+)
+
+func init() {
+	metrics.Registry.MustRegister(RecordsDropped)
+}
+
+// Record is a single operator-driven change in the audit stream
+type Record struct {
+	SchemaVersion string            `json:"schemaVersion"`
+	Sequence      int64             `json:"sequence"`
+	Timestamp     time.Time         `json:"timestamp"`
+	Controller    string            `json:"controller"`
+	Action        string            `json:"action"`
+	Kind          string            `json:"kind"`
+	Namespace     string            `json:"namespace"`
+	Name          string            `json:"name"`
+	UID           string            `json:"uid,omitempty"`
+	Generation    int64             `json:"generation,omitempty"`
+	Message       string            `json:"message,omitempty"`
+	Details       map[string]string `json:"details,omitempty"`
+	PreviousHash  string            `json:"previousHash,omitempty"`
+	Hash          string            `json:"hash"`
+}
+
+// Seal links the record to the previous record in the stream and computes its hash.
+// Altering, removing, or reordering sealed records breaks the chain, which Verify detects.
+func (r *Record) Seal(previousHash string) {
+	r.PreviousHash = previousHash
+	r.Hash = r.computeHash()
+}
+
+// computeHash hashes every field of the record except the hash itself
+func (r Record) computeHash() string {
+	r.Hash = ""
+	// Marshalling a struct of strings, ints, and a string map cannot fail
+	data, _ := json.Marshal(r)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Verify checks that records form an unbroken hash chain in sequence order
+func Verify(records []Record) error {
+	for i, record := range records {
+		if record.Hash != record.computeHash() {
+			return fmt.Errorf("record %d has been modified", record.Sequence)
+		}
+		if i == 0 {
+			continue
+		}
+		previous := records[i-1]
+		if record.Sequence != previous.Sequence+1 {
+			return fmt.Errorf("record %d does not follow record %d", record.Sequence, previous.Sequence)
+		}
+		if record.PreviousHash != previous.Hash {
+			return fmt.Errorf("record %d is not linked to record %d", record.Sequence, previous.Sequence)
+		}
+	}
+	return nil
+}
+
+// Sink persists audit records. Implementations assign the sequence number and seal the record.
+type Sink interface {
+	Write(ctx context.Context, record Record) error
+}
+
+// Emitter builds audit records and queues them for writing to a sink, so a slow or failing sink
+// never blocks reconciliation. Records are written in order by Start, which must be running.
+// A nil Emitter discards records, so controllers can emit unconditionally whether or not
+// auditing is configured.
+type Emitter struct {
+	sink       Sink
+	log        logr.Logger
+	now        func() time.Time
+	queue      chan Record
+	retryDelay time.Duration
+}
+
+// NewEmitter creates an emitter writing to the given sink
+func NewEmitter(sink Sink, log logr.Logger) *Emitter {
+	return &Emitter{
+		sink:       sink,
+		log:        log,
+		now:        time.Now,
+		queue:      make(chan Record, DefaultQueueSize),
+		retryDelay: defaultRetryDelay,
+	}
+}
+
+// Emit queues a record of an action taken by controller on obj. When the queue is full the
+// record is dropped and counted rather than waiting for the sink.
+func (e *Emitter) Emit(ctx context.Context, controller, action string, obj client.Object, message string, details map[string]string) {
+	if e == nil {
+		return
+	}
+
+	// Objects read through a client usually have an empty TypeMeta, so fall back to the Go type name
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if kind == "" {
+		kind = reflect.Indirect(reflect.ValueOf(obj)).Type().Name()
+	}
+
+	record := Record{
+		SchemaVersion: SchemaVersion,
+		Timestamp:     e.now().UTC(),
+		Controller:    controller,
+		Action:        action,
+		Kind:          kind,
+		Namespace:     obj.GetNamespace(),
+		Name:          obj.GetName(),
+		UID:           string(obj.GetUID()),
+		Generation:    obj.GetGeneration(),
+		Message:       message,
+		Details:       details,
+	}
+
+	select {
+	case e.queue <- record:
+	default:
+		RecordsDropped.WithLabelValues("queue_full").Inc()
+		e.log.Info("Dropped audit record because the queue is full",
+			"controller", controller,
+			"action", action,
+			"namespace", record.Namespace,
+			"name", record.Name)
+	}
+}
+
+// Start writes queued records to the sink until ctx is done, then writes the records still
+// queued. Writes, including retries in progress, outlive ctx by up to drainTimeout. It
+// implements manager.Runnable.
+func (e *Emitter) Start(ctx context.Context) error {
+	writeCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancel()
+	stop := context.AfterFunc(ctx, func() { time.AfterFunc(drainTimeout, cancel) })
+	defer stop()
+
+	for {
+		select {
+		case record := <-e.queue:
+			e.write(writeCtx, record)
+		case <-ctx.Done():
+			for {
+				select {
+				case record := <-e.queue:
+					e.write(writeCtx, record)
+				default:
+					return nil
+				}
+			}
+		}
+	}
+}
+
+// write writes a record to the sink, retrying failed writes with exponential backoff. A record
+// that can't be written within maxWriteAttempts is dropped and counted.
+func (e *Emitter) write(ctx context.Context, record Record) {
+	delay := e.retryDelay
+	err := e.sink.Write(ctx, record)
+	for attempt := 1; err != nil && attempt < maxWriteAttempts && ctx.Err() == nil; attempt++ {
+		select {
+		case <-ctx.Done():
+			continue
+		case <-time.After(delay):
+		}
+		delay *= 2
+		err = e.sink.Write(ctx, record)
+	}
+	if err == nil {
+		return
+	}
+
+	RecordsDropped.WithLabelValues("write_failed").Inc()
+	e.log.Error(err, "Failed to write audit record",
+		"controller", record.Controller,
+		"action", record.Action,
+		"namespace", record.Namespace,
+		"name", record.Name)
+}
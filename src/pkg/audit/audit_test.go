@@ -0,0 +1,245 @@
+package audit
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/logr"
+	promtestutil "github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func newTestObject(name string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: types.UID("uid-" + name), Generation: 3},
+	}
+}
+
+func sealedChain(n int) []Record {
+	var records []Record
+	previousHash := ""
+	for i := 1; i <= n; i++ {
+		record := Record{
+			SchemaVersion: SchemaVersion,
+			Sequence:      int64(i),
+			Timestamp:     time.Date(2024, 1, 1, 0, i, 0, 0, time.UTC),
+			Controller:    "languageagent",
+			Action:        ActionSynthesized,
+			Name:          "agent",
+		}
+		record.Seal(previousHash)
+		previousHash = record.Hash
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestVerify_DetectsTampering(t *testing.T) {
+	tests := []struct {
+		name    string
+		tamper  func(records []Record) []Record
+		errMsg  string
+		wantErr bool
+	}{
+		{name: "intact chain", tamper: func(r []Record) []Record { return r }},
+		{
+			name:    "modified record",
+			tamper:  func(r []Record) []Record { r[1].Action = ActionLearningRollback; return r },
+			errMsg:  "record 2 has been modified",
+			wantErr: true,
+		},
+		{
+			name:    "removed record",
+			tamper:  func(r []Record) []Record { return append(r[:1], r[2:]...) },
+			errMsg:  "record 3 does not follow record 1",
+			wantErr: true,
+		},
+		{
+			name: "resealed record",
+			tamper: func(r []Record) []Record {
+				r[1].Message = "rewritten"
+				r[1].Seal(r[1].PreviousHash)
+				return r
+			},
+			errMsg:  "record 3 is not linked to record 2",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.tamper(sealedChain(3)))
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Expected intact chain to verify, got %v", err)
+			}
+		})
+	}
+}
+
+// runEmitter starts the emitter and returns a function stopping it once its queue is written
+func runEmitter(t *testing.T, emitter *Emitter) func() {
+	t.Helper()
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- emitter.Start(ctx) }()
+	return func() {
+		cancel()
+		if err := <-done; err != nil {
+			t.Errorf("Start failed: %v", err)
+		}
+	}
+}
+
+// flakySink fails its first writes before delegating to sink
+type flakySink struct {
+	sink     Sink
+	failures int
+	attempts int
+}
+
+func (s *flakySink) Write(ctx context.Context, record Record) error {
+	s.attempts++
+	if s.attempts <= s.failures {
+		return errors.New("sink unavailable")
+	}
+	return s.sink.Write(ctx, record)
+}
+
+func TestEmitter_RetriesFailedWrites(t *testing.T) {
+	logSink := NewLogSink(logr.Discard())
+	sink := &flakySink{sink: logSink, failures: 2}
+	emitter := NewEmitter(sink, logr.Discard())
+	emitter.retryDelay = time.Millisecond
+
+	stop := runEmitter(t, emitter)
+	emitter.Emit(context.Background(), "languageagent", ActionSynthesized, newTestObject("agent"), "", nil)
+	stop()
+
+	if sink.attempts != 3 || logSink.sequence != 1 {
+		t.Errorf("Expected the record to be written on the third attempt, got %d attempts and sequence %d", sink.attempts, logSink.sequence)
+	}
+
+	// A record failing every attempt is dropped and counted
+	dropped := promtestutil.ToFloat64(RecordsDropped.WithLabelValues("write_failed"))
+	sink.attempts, sink.failures = 0, maxWriteAttempts
+	stop = runEmitter(t, emitter)
+	emitter.Emit(context.Background(), "languageagent", ActionSynthesized, newTestObject("agent"), "", nil)
+	stop()
+
+	if sink.attempts != maxWriteAttempts || logSink.sequence != 1 {
+		t.Errorf("Expected %d attempts without a write, got %d attempts and sequence %d", maxWriteAttempts, sink.attempts, logSink.sequence)
+	}
+	if got := promtestutil.ToFloat64(RecordsDropped.WithLabelValues("write_failed")); got != dropped+1 {
+		t.Errorf("Expected the dropped record to be counted, got %v", got-dropped)
+	}
+}
+
+func TestEmitter_DropsRecordsWhenQueueIsFull(t *testing.T) {
+	sink := NewLogSink(logr.Discard())
+	emitter := NewEmitter(sink, logr.Discard())
+	emitter.queue = make(chan Record, 1)
+
+	// Nothing drains the queue, so Emit must not block once it is full
+	dropped := promtestutil.ToFloat64(RecordsDropped.WithLabelValues("queue_full"))
+	emitter.Emit(context.Background(), "languageagent", ActionSynthesized, newTestObject("agent"), "queued", nil)
+	emitter.Emit(context.Background(), "languageagent", ActionSynthesized, newTestObject("agent"), "dropped", nil)
+
+	if got := promtestutil.ToFloat64(RecordsDropped.WithLabelValues("queue_full")); got != dropped+1 {
+		t.Errorf("Expected one dropped record, got %v", got-dropped)
+	}
+
+	// The queued record is still written once the emitter runs
+	runEmitter(t, emitter)()
+	if sink.sequence != 1 {
+		t.Errorf("Expected the queued record to be written, got sequence %d", sink.sequence)
+	}
+}
+
+func TestEmitter_NilIsNoop(t *testing.T) {
+	var emitter *Emitter
+	emitter.Emit(context.Background(), "languageagent", ActionSynthesized, newTestObject("agent"), "", nil)
+}
+
+func TestLogSink_ChainsRecords(t *testing.T) {
+	sink := NewLogSink(logr.Discard())
+	emitter := NewEmitter(sink, logr.Discard())
+
+	ctx := context.Background()
+	stop := runEmitter(t, emitter)
+	emitter.Emit(ctx, "languageagent", ActionSynthesized, newTestObject("agent"), "first", nil)
+	emitter.Emit(ctx, "learning", ActionLearningConverted, newTestObject("agent"), "second", nil)
+	stop()
+
+	if sink.sequence != 2 {
+		t.Errorf("Expected sequence 2, got %d", sink.sequence)
+	}
+	if sink.lastHash == "" {
+		t.Error("Expected the sink to track the last record hash")
+	}
+}
+
+func TestConfigMapSink_AppendsAndResumes(t *testing.T) {
+	fakeClient := fake.NewClientBuilder().Build()
+	ctx := context.Background()
+
+	sink := NewConfigMapSink(fakeClient, fakeClient, "langop-system", "langop-audit")
+	sink.recordsPerSegment = 2
+	emitter := NewEmitter(sink, logr.Discard())
+
+	stop := runEmitter(t, emitter)
+	emitter.Emit(ctx, "languageagent", ActionSynthesized, newTestObject("agent"), "synthesized", map[string]string{"reason": "initial"})
+	emitter.Emit(ctx, "languageagent", ActionSelfHealingTriggered, newTestObject("agent"), "crash loop", nil)
+	emitter.Emit(ctx, "learning", ActionLearningConverted, newTestObject("agent"), "converted", nil)
+	stop()
+
+	// A new sink simulates an operator restart and must continue the same chain
+	resumed := NewConfigMapSink(fakeClient, fakeClient, "langop-system", "langop-audit")
+	resumed.recordsPerSegment = 2
+	resumedEmitter := NewEmitter(resumed, logr.Discard())
+	stop = runEmitter(t, resumedEmitter)
+	resumedEmitter.Emit(ctx, "learning", ActionLearningRollback, newTestObject("agent"), "rollback", nil)
+	stop()
+
+	records, err := resumed.ReadAll(ctx)
+	if err != nil {
+		t.Fatalf("ReadAll failed: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("Expected 4 records, got %d", len(records))
+	}
+	if err := Verify(records); err != nil {
+		t.Errorf("Expected a verifiable chain across segments and restarts, got %v", err)
+	}
+
+	first := records[0]
+	if first.Kind != "ConfigMap" || first.Namespace != "default" || first.UID != "uid-agent" || first.Generation != 3 {
+		t.Errorf("Unexpected object identity in record: %+v", first)
+	}
+	if first.Details["reason"] != "initial" {
+		t.Errorf("Expected details to be recorded, got %v", first.Details)
+	}
+	if records[3].Action != ActionLearningRollback {
+		t.Errorf("Expected last record to be %s, got %s", ActionLearningRollback, records[3].Action)
+	}
+
+	segments := &corev1.ConfigMapList{}
+	if err := fakeClient.List(ctx, segments, client.InNamespace("langop-system"), client.MatchingLabels{StreamLabel: "langop-audit"}); err != nil {
+		t.Fatalf("Failed to list segments: %v", err)
+	}
+	if len(segments.Items) != 2 {
+		t.Errorf("Expected records to roll over into 2 segments, got %d", len(segments.Items))
+	}
+}
@@ -0,0 +1,254 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// StreamLabel identifies the ConfigMap segments belonging to an audit stream
+	StreamLabel = "langop.io/audit-stream"
+	// SegmentLabel holds the segment number of an audit ConfigMap
+	SegmentLabel = "langop.io/audit-segment"
+
+	// DefaultRecordsPerSegment keeps each audit ConfigMap well under the 1MiB object size limit
+	DefaultRecordsPerSegment = 500
+)
+
+// LogSink writes sealed audit records to a logger as single-line JSON, for collection
+// by a log pipeline (e.g. an OpenTelemetry Collector filelog receiver). The hash chain
+// restarts when the operator restarts.
+type LogSink struct {
+	log      logr.Logger
+	mu       sync.Mutex
+	sequence int64
+	lastHash string
+}
+
+// NewLogSink creates a sink writing audit records to log
+func NewLogSink(log logr.Logger) *LogSink {
+	return &LogSink{log: log}
+}
+
+// Write implements Sink
+func (s *LogSink) Write(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record.Sequence = s.sequence + 1
+	record.Seal(s.lastHash)
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	s.log.Info("Audit record", "action", record.Action, "audit", string(data))
+	s.sequence = record.Sequence
+	s.lastHash = record.Hash
+	return nil
+}
+
+// ConfigMapSink appends audit records to a series of ConfigMap segments named <name>-<segment>.
+// Records are keyed by zero-padded sequence number and never rewritten, and the hash chain
+// continues across segments and operator restarts.
+type ConfigMapSink struct {
+	client            client.Client
+	reader            client.Reader
+	namespace         string
+	name              string
+	recordsPerSegment int
+
+	mu             sync.Mutex
+	loaded         bool
+	segment        int
+	segmentRecords int
+	sequence       int64
+	lastHash       string
+}
+
+// NewConfigMapSink creates a sink appending to ConfigMaps in namespace. Reads go through reader
+// so the stream can be resumed even when the manager's cache doesn't cover namespace.
+func NewConfigMapSink(c client.Client, reader client.Reader, namespace, name string) *ConfigMapSink {
+	return &ConfigMapSink{
+		client:            c,
+		reader:            reader,
+		namespace:         namespace,
+		name:              name,
+		recordsPerSegment: DefaultRecordsPerSegment,
+	}
+}
+
+// Write implements Sink
+func (s *ConfigMapSink) Write(ctx context.Context, record Record) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		if err := s.load(ctx); err != nil {
+			return err
+		}
+	}
+
+	segment := s.segment
+	if s.segmentRecords >= s.recordsPerSegment {
+		segment++
+	}
+
+	record.Sequence = s.sequence + 1
+	record.Seal(s.lastHash)
+
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	if err := s.appendToSegment(ctx, segment, sequenceKey(record.Sequence), string(data)); err != nil {
+		// Resume from the persisted stream on the next write in case another writer appended
+		s.loaded = false
+		return err
+	}
+
+	if segment != s.segment {
+		s.segment = segment
+		s.segmentRecords = 0
+	}
+	s.segmentRecords++
+	s.sequence = record.Sequence
+	s.lastHash = record.Hash
+	return nil
+}
+
+// ReadAll returns every record in the stream in sequence order
+func (s *ConfigMapSink) ReadAll(ctx context.Context) ([]Record, error) {
+	segments, err := s.listSegments(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []Record
+	for _, segment := range segments {
+		for _, key := range sortedKeys(segment.Data) {
+			var record Record
+			if err := json.Unmarshal([]byte(segment.Data[key]), &record); err != nil {
+				return nil, fmt.Errorf("failed to parse audit record %s in %s: %w", key, segment.Name, err)
+			}
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// load resumes the stream from the newest existing segment
+func (s *ConfigMapSink) load(ctx context.Context) error {
+	segments, err := s.listSegments(ctx)
+	if err != nil {
+		return err
+	}
+
+	if len(segments) > 0 {
+		latest := segments[len(segments)-1]
+		s.segment = segmentNumber(latest)
+		s.segmentRecords = len(latest.Data)
+
+		if keys := sortedKeys(latest.Data); len(keys) > 0 {
+			var last Record
+			if err := json.Unmarshal([]byte(latest.Data[keys[len(keys)-1]]), &last); err != nil {
+				return fmt.Errorf("failed to parse last audit record in %s: %w", latest.Name, err)
+			}
+			s.sequence = last.Sequence
+			s.lastHash = last.Hash
+		}
+	}
+
+	s.loaded = true
+	return nil
+}
+
+// listSegments returns the stream's ConfigMaps ordered by segment number
+func (s *ConfigMapSink) listSegments(ctx context.Context) ([]corev1.ConfigMap, error) {
+	list := &corev1.ConfigMapList{}
+	if err := s.reader.List(ctx, list, client.InNamespace(s.namespace), client.MatchingLabels{StreamLabel: s.name}); err != nil {
+		return nil, fmt.Errorf("failed to list audit ConfigMaps: %w", err)
+	}
+
+	segments := list.Items
+	sort.Slice(segments, func(i, j int) bool {
+		return segmentNumber(segments[i]) < segmentNumber(segments[j])
+	})
+	return segments, nil
+}
+
+// appendToSegment adds a record to the segment ConfigMap, creating the segment if needed
+func (s *ConfigMapSink) appendToSegment(ctx context.Context, segment int, key, value string) error {
+	name := fmt.Sprintf("%s-%06d", s.name, segment)
+
+	configMap := &corev1.ConfigMap{}
+	err := s.reader.Get(ctx, types.NamespacedName{Name: name, Namespace: s.namespace}, configMap)
+	if errors.IsNotFound(err) {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: s.namespace,
+				Labels: map[string]string{
+					StreamLabel:  s.name,
+					SegmentLabel: strconv.Itoa(segment),
+				},
+			},
+			Data: map[string]string{key: value},
+		}
+		if err := s.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create audit ConfigMap %s: %w", name, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get audit ConfigMap %s: %w", name, err)
+	}
+
+	if _, exists := configMap.Data[key]; exists {
+		return fmt.Errorf("audit record %s already exists in %s", key, name)
+	}
+	if configMap.Data == nil {
+		configMap.Data = make(map[string]string)
+	}
+	configMap.Data[key] = value
+
+	// The resourceVersion from the Get makes this fail rather than overwrite a concurrent append
+	if err := s.client.Update(ctx, configMap); err != nil {
+		return fmt.Errorf("failed to append to audit ConfigMap %s: %w", name, err)
+	}
+	return nil
+}
+
+// sequenceKey formats a sequence number so that keys sort in sequence order
+func sequenceKey(sequence int64) string {
+	return fmt.Sprintf("%012d", sequence)
+}
+
+// segmentNumber returns the segment number recorded on an audit ConfigMap
+func segmentNumber(configMap corev1.ConfigMap) int {
+	segment, _ := strconv.Atoi(configMap.Labels[SegmentLabel])
+	return segment
+}
+
+// sortedKeys returns the keys of data in ascending order
+func sortedKeys(data map[string]string) []string {
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
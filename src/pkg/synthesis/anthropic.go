@@ -0,0 +1,222 @@
+package synthesis
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// Synthesis provider APIs reported in AgentSynthesisResponse.Provider
+const (
+	// ProviderAnthropic is the native Anthropic Messages API
+	ProviderAnthropic = "anthropic"
+	// ProviderOpenAI is the OpenAI chat completions API, also spoken by OpenAI-compatible servers
+	// such as Ollama and LM Studio
+	ProviderOpenAI = "openai"
+)
+
+const (
+	// defaultAnthropicEndpoint is the Anthropic API used when the LanguageModel sets no endpoint
+	defaultAnthropicEndpoint = "https://api.anthropic.com"
+
+	// anthropicVersion is the Messages API version requested
+	anthropicVersion = "2023-06-01"
+
+	// maxAnthropicTemperature is the highest temperature the Messages API accepts
+	maxAnthropicTemperature = 1.0
+
+	// maxAnthropicResponseSize caps the Messages API response bodies read
+	maxAnthropicResponseSize = 10 * 1024 * 1024
+)
+
+// synthesisProvider returns the provider API a LanguageModel is synthesized with. Anthropic
+// models use the Messages API on the Anthropic API itself; a custom endpoint keeps the
+// OpenAI-compatible API unless the model opts in with AnthropicMessagesAPIAnnotation.
+func synthesisProvider(model *langopv1alpha1.LanguageModel) string {
+	if model.Spec.Provider != ProviderAnthropic {
+		return ProviderOpenAI
+	}
+	if model.Annotations[langopv1alpha1.AnthropicMessagesAPIAnnotation] == "true" {
+		return ProviderAnthropic
+	}
+	if model.Spec.Endpoint == "" {
+		return ProviderAnthropic
+	}
+	if endpoint, err := url.Parse(model.Spec.Endpoint); err == nil && endpoint.Host == "api.anthropic.com" {
+		return ProviderAnthropic
+	}
+	return ProviderOpenAI
+}
+
+// AnthropicChatModel is a ChatModel calling the Anthropic Messages API. System messages are
+// sent as the request's system prompt rather than as conversation turns, and temperatures
+// above the API's maximum of 1 are clamped to it.
+type AnthropicChatModel struct {
+	// Endpoint is the API base URL, without the /v1 suffix
+	Endpoint    string
+	APIKey      string
+	Model       string
+	MaxTokens   int
+	Temperature *float32
+	HTTPClient  *http.Client
+}
+
+// NewAnthropicChatModel creates an Anthropic chat model. An empty endpoint uses the Anthropic
+// API; zero timeout leaves requests unbounded.
+func NewAnthropicChatModel(endpoint, apiKey, modelName string, maxTokens int, temperature *float32, timeout time.Duration) *AnthropicChatModel {
+	if endpoint == "" {
+		endpoint = defaultAnthropicEndpoint
+	}
+	return &AnthropicChatModel{
+		Endpoint:    strings.TrimSuffix(strings.TrimSuffix(endpoint, "/"), "/v1"),
+		APIKey:      apiKey,
+		Model:       modelName,
+		MaxTokens:   maxTokens,
+		Temperature: temperature,
+		HTTPClient:  &http.Client{Timeout: timeout},
+	}
+}
+
+type anthropicMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type anthropicRequest struct {
+	Model       string             `json:"model"`
+	MaxTokens   int                `json:"max_tokens"`
+	System      string             `json:"system,omitempty"`
+	Messages    []anthropicMessage `json:"messages"`
+	Temperature *float32           `json:"temperature,omitempty"`
+}
+
+type anthropicResponse struct {
+	Content []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"content"`
+	StopReason string `json:"stop_reason"`
+	Usage      struct {
+		InputTokens  int `json:"input_tokens"`
+		OutputTokens int `json:"output_tokens"`
+	} `json:"usage"`
+}
+
+// AnthropicAPIError is an error response of the Anthropic API
+type AnthropicAPIError struct {
+	StatusCode int
+	Type       string
+	Message    string
+}
+
+// Error implements the error interface. The format matches OpenAI-compatible client errors so
+// the status code classifies the error the same way.
+func (e *AnthropicAPIError) Error() string {
+	return fmt.Sprintf("anthropic error, status code: %d, type: %s, message: %s", e.StatusCode, e.Type, e.Message)
+}
+
+// Generate implements ChatModel
+func (m *AnthropicChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	req := anthropicRequest{
+		Model:       m.Model,
+		MaxTokens:   m.MaxTokens,
+		Temperature: m.Temperature,
+	}
+	if req.MaxTokens <= 0 {
+		// max_tokens is required by the Messages API
+		req.MaxTokens = defaultSynthesisOutputTokens
+	}
+	if req.Temperature != nil && *req.Temperature > maxAnthropicTemperature {
+		temperature := float32(maxAnthropicTemperature)
+		req.Temperature = &temperature
+	}
+	var system []string
+	for _, message := range input {
+		switch message.Role {
+		case schema.System:
+			system = append(system, message.Content)
+		case schema.Assistant:
+			req.Messages = append(req.Messages, anthropicMessage{Role: "assistant", Content: message.Content})
+		default:
+			req.Messages = append(req.Messages, anthropicMessage{Role: "user", Content: message.Content})
+		}
+	}
+	req.System = strings.Join(system, "\n\n")
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode anthropic request: %w", err)
+	}
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, m.Endpoint+"/v1/messages", bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create anthropic request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("x-api-key", m.APIKey)
+	httpReq.Header.Set("anthropic-version", anthropicVersion)
+
+	client := m.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	httpResp, err := client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer httpResp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(httpResp.Body, maxAnthropicResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read anthropic response: %w", err)
+	}
+	if len(respBody) > maxAnthropicResponseSize {
+		return nil, fmt.Errorf("anthropic response exceeds maximum size of %d bytes", maxAnthropicResponseSize)
+	}
+	if httpResp.StatusCode != http.StatusOK {
+		apiErr := &AnthropicAPIError{StatusCode: httpResp.StatusCode, Message: strings.TrimSpace(string(respBody))}
+		var errResp struct {
+			Error struct {
+				Type    string `json:"type"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if json.Unmarshal(respBody, &errResp) == nil && errResp.Error.Message != "" {
+			apiErr.Type, apiErr.Message = errResp.Error.Type, errResp.Error.Message
+		}
+		return nil, apiErr
+	}
+
+	var resp anthropicResponse
+	if err := json.Unmarshal(respBody, &resp); err != nil {
+		return nil, fmt.Errorf("failed to decode anthropic response: %w", err)
+	}
+	var content strings.Builder
+	for _, block := range resp.Content {
+		if block.Type == "text" {
+			content.WriteString(block.Text)
+		}
+	}
+	return &schema.Message{
+		Role:    schema.Assistant,
+		Content: content.String(),
+		ResponseMeta: &schema.ResponseMeta{
+			FinishReason: resp.StopReason,
+			Usage: &schema.TokenUsage{
+				PromptTokens:     resp.Usage.InputTokens,
+				CompletionTokens: resp.Usage.OutputTokens,
+				TotalTokens:      resp.Usage.InputTokens + resp.Usage.OutputTokens,
+			},
+		},
+	}, nil
+}
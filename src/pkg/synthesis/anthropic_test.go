@@ -0,0 +1,293 @@
+package synthesis
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// recordedRequest is a request captured by a fake provider
+type recordedRequest struct {
+	path    string
+	headers http.Header
+	body    map[string]interface{}
+}
+
+// newFakeProvider serves response with status to every request and records the last request
+func newFakeProvider(t *testing.T, status int, response string) (*httptest.Server, *recordedRequest) {
+	recorded := &recordedRequest{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		assert.NoError(t, err)
+		recorded.path = r.URL.Path
+		recorded.headers = r.Header.Clone()
+		assert.NoError(t, json.Unmarshal(body, &recorded.body))
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(response))
+	}))
+	t.Cleanup(server.Close)
+	return server, recorded
+}
+
+func newProviderModel(provider, endpoint string) *langopv1alpha1.LanguageModel {
+	maxTokens := int32(2048)
+	return &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "synthesis-model", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:      provider,
+			ModelName:     "test-model",
+			Endpoint:      endpoint,
+			Configuration: &langopv1alpha1.ProviderConfiguration{MaxTokens: &maxTokens},
+		},
+	}
+}
+
+func TestNewSynthesizerFromLanguageModel_AnthropicNative(t *testing.T) {
+	server, recorded := newFakeProvider(t, http.StatusOK, `{
+		"content": [{"type": "text", "text": "agent \"researcher\" do"}, {"type": "text", "text": "\nend"}],
+		"stop_reason": "end_turn",
+		"usage": {"input_tokens": 12, "output_tokens": 5}
+	}`)
+
+	model := newProviderModel("anthropic", server.URL+"/v1")
+	model.Annotations = map[string]string{langopv1alpha1.AnthropicMessagesAPIAnnotation: "true"}
+	synth, err := NewSynthesizerFromLanguageModel(context.Background(), nil, model, time.Minute, logr.Discard())
+	require.NoError(t, err)
+	assert.Equal(t, ProviderAnthropic, synth.provider)
+
+	message, err := synth.chatModel.Generate(context.Background(), []*schema.Message{
+		schema.SystemMessage("You write agent DSL."),
+		schema.UserMessage("Build a researcher"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "agent \"researcher\" do\nend", message.Content)
+	require.NotNil(t, message.ResponseMeta)
+	assert.Equal(t, 12, message.ResponseMeta.Usage.PromptTokens)
+	assert.Equal(t, 5, message.ResponseMeta.Usage.CompletionTokens)
+
+	// The Messages API carries the system prompt separately from the conversation
+	assert.Equal(t, "/v1/messages", recorded.path)
+	assert.Equal(t, anthropicVersion, recorded.headers.Get("anthropic-version"))
+	assert.Equal(t, "You write agent DSL.", recorded.body["system"])
+	assert.Equal(t, float64(2048), recorded.body["max_tokens"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"role": "user", "content": "Build a researcher"}}, recorded.body["messages"])
+}
+
+func TestSynthesisProvider(t *testing.T) {
+	tests := []struct {
+		name        string
+		provider    string
+		endpoint    string
+		annotations map[string]string
+		expected    string
+	}{
+		{name: "anthropic API", provider: "anthropic", expected: ProviderAnthropic},
+		{name: "explicit anthropic endpoint", provider: "anthropic", endpoint: "https://api.anthropic.com/v1", expected: ProviderAnthropic},
+		{name: "proxy endpoint keeps chat completions", provider: "anthropic", endpoint: "https://llm-proxy.internal/v1", expected: ProviderOpenAI},
+		{
+			name:        "proxy endpoint opted in",
+			provider:    "anthropic",
+			endpoint:    "https://llm-proxy.internal",
+			annotations: map[string]string{langopv1alpha1.AnthropicMessagesAPIAnnotation: "true"},
+			expected:    ProviderAnthropic,
+		},
+		{name: "openai", provider: "openai", expected: ProviderOpenAI},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := newProviderModel(tt.provider, tt.endpoint)
+			model.Annotations = tt.annotations
+			assert.Equal(t, tt.expected, synthesisProvider(model))
+		})
+	}
+}
+
+func TestAnthropicChatModel_ClampsTemperature(t *testing.T) {
+	server, recorded := newFakeProvider(t, http.StatusOK, `{"content": [{"type": "text", "text": "ok"}]}`)
+
+	temperature := float32(1.5)
+	chatModel := NewAnthropicChatModel(server.URL, "sk-ant", "test-model", 0, &temperature, time.Minute)
+	_, err := chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("ping")})
+	require.NoError(t, err)
+	assert.Equal(t, float64(1), recorded.body["temperature"])
+}
+
+func TestAnthropicChatModel_ResponseSizeLimit(t *testing.T) {
+	oversized := `{"content": [{"type": "text", "text": "` + strings.Repeat("a", maxAnthropicResponseSize) + `"}]}`
+	server, _ := newFakeProvider(t, http.StatusOK, oversized)
+
+	chatModel := NewAnthropicChatModel(server.URL, "sk-ant", "test-model", 0, nil, time.Minute)
+	_, err := chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("ping")})
+	assert.ErrorContains(t, err, "exceeds maximum size")
+}
+
+func TestAnthropicChatModel_Errors(t *testing.T) {
+	server, _ := newFakeProvider(t, http.StatusUnauthorized,
+		`{"type": "error", "error": {"type": "authentication_error", "message": "invalid x-api-key"}}`)
+
+	chatModel := NewAnthropicChatModel(server.URL, "sk-ant-wrong", "test-model", 0, nil, time.Minute)
+	_, err := chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("ping")})
+	var apiErr *AnthropicAPIError
+	require.ErrorAs(t, err, &apiErr)
+	assert.Equal(t, http.StatusUnauthorized, apiErr.StatusCode)
+	assert.Equal(t, "authentication_error", apiErr.Type)
+	assert.Equal(t, ReasonSynthesisAuthError, FailureReason(classifyModelError(err)))
+}
+
+func TestNewSynthesizerFromLanguageModel_OpenAICompatible(t *testing.T) {
+	for _, provider := range []string{"openai", "openai-compatible"} {
+		t.Run(provider, func(t *testing.T) {
+			server, recorded := newFakeProvider(t, http.StatusOK, `{
+				"id": "chatcmpl-1",
+				"object": "chat.completion",
+				"created": 1700000000,
+				"model": "test-model",
+				"choices": [{"index": 0, "message": {"role": "assistant", "content": "agent \"researcher\" do\nend"}, "finish_reason": "stop"}],
+				"usage": {"prompt_tokens": 12, "completion_tokens": 5, "total_tokens": 17}
+			}`)
+
+			synth, err := NewSynthesizerFromLanguageModel(context.Background(), nil, newProviderModel(provider, server.URL), time.Minute, logr.Discard())
+			require.NoError(t, err)
+			assert.Equal(t, ProviderOpenAI, synth.provider)
+
+			message, err := synth.chatModel.Generate(context.Background(), []*schema.Message{
+				schema.SystemMessage("You write agent DSL."),
+				schema.UserMessage("Build a researcher"),
+			})
+			require.NoError(t, err)
+			assert.Equal(t, "agent \"researcher\" do\nend", message.Content)
+
+			// Chat completions carry the system prompt as a message
+			assert.Equal(t, "/v1/chat/completions", recorded.path)
+			messages, ok := recorded.body["messages"].([]interface{})
+			require.True(t, ok)
+			require.Len(t, messages, 2)
+			assert.Equal(t, "system", messages[0].(map[string]interface{})["role"])
+			assert.NotContains(t, recorded.body, "system")
+		})
+	}
+}
+
+func TestResolveGenerationParameters(t *testing.T) {
+	float := func(v float64) *float64 { return &v }
+	tokens := func(v int32) *int32 { return &v }
+
+	configured := newProviderModel("openai", "")
+	configured.Spec.Configuration.Temperature = float(0.7)
+	unconfigured := newProviderModel("openai", "")
+	unconfigured.Spec.Configuration = nil
+	tokensOnly := newProviderModel("openai", "")
+	anthropic := newProviderModel("anthropic", "")
+	anthropic.Spec.Configuration.Temperature = float(0.7)
+
+	tests := []struct {
+		name      string
+		model     *langopv1alpha1.LanguageModel
+		overrides *langopv1alpha1.AgentSynthesisSettings
+		expected  GenerationParameters
+	}{
+		{name: "synthesis defaults without a model configuration", model: unconfigured,
+			expected: GenerationParameters{Temperature: float(0.3), MaxTokens: tokens(8192)}},
+		{name: "model configuration", model: configured,
+			expected: GenerationParameters{Temperature: float(0.7), MaxTokens: tokens(2048)}},
+		{name: "unset configuration left to the provider", model: tokensOnly,
+			expected: GenerationParameters{MaxTokens: tokens(2048)}},
+		{name: "empty overrides keep the model configuration", model: configured, overrides: &langopv1alpha1.AgentSynthesisSettings{},
+			expected: GenerationParameters{Temperature: float(0.7), MaxTokens: tokens(2048)}},
+		{name: "agent overrides", model: configured,
+			overrides: &langopv1alpha1.AgentSynthesisSettings{Temperature: float(0), MaxTokens: tokens(32000)},
+			expected:  GenerationParameters{Temperature: float(0), MaxTokens: tokens(32000)}},
+		{name: "temperature capped at the provider's limit", model: anthropic,
+			overrides: &langopv1alpha1.AgentSynthesisSettings{Temperature: float(1.5)},
+			expected:  GenerationParameters{Temperature: float(1), MaxTokens: tokens(2048)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, ResolveGenerationParameters(tt.model, tt.overrides))
+		})
+	}
+}
+
+func TestNewSynthesizerWithParameters_UnsetParametersNotSent(t *testing.T) {
+	server, recorded := newFakeProvider(t, http.StatusOK, `{
+		"id": "chatcmpl-1",
+		"object": "chat.completion",
+		"created": 1700000000,
+		"model": "o3-mini",
+		"choices": [{"index": 0, "message": {"role": "assistant", "content": "agent \"researcher\" do\nend"}, "finish_reason": "stop"}],
+		"usage": {"prompt_tokens": 12, "completion_tokens": 5, "total_tokens": 17}
+	}`)
+
+	// Reasoning models reject temperature and max_tokens, so a model configuration without them
+	// must not send them
+	model := newProviderModel("openai", server.URL)
+	model.Spec.Configuration = &langopv1alpha1.ProviderConfiguration{}
+	synth, err := NewSynthesizerFromLanguageModel(context.Background(), nil, model, time.Minute, logr.Discard())
+	require.NoError(t, err)
+	assert.Equal(t, int64(defaultSynthesisOutputTokens), synth.outputTokens)
+
+	_, err = synth.chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("Build a researcher")})
+	require.NoError(t, err)
+	assert.NotContains(t, recorded.body, "temperature")
+	assert.NotContains(t, recorded.body, "max_tokens")
+}
+
+func TestNewSynthesizerWithParameters_OverridesReachProvider(t *testing.T) {
+	temperature, maxTokens := 0.75, int32(32000)
+	params := GenerationParameters{Temperature: &temperature, MaxTokens: &maxTokens}
+
+	t.Run("anthropic", func(t *testing.T) {
+		server, recorded := newFakeProvider(t, http.StatusOK, `{
+			"content": [{"type": "text", "text": "agent \"researcher\" do\nend"}],
+			"stop_reason": "end_turn",
+			"usage": {"input_tokens": 12, "output_tokens": 5}
+		}`)
+
+		model := newProviderModel("anthropic", server.URL+"/v1")
+		model.Annotations = map[string]string{langopv1alpha1.AnthropicMessagesAPIAnnotation: "true"}
+		synth, err := NewSynthesizerWithParameters(context.Background(), nil, model, time.Minute, params, logr.Discard())
+		require.NoError(t, err)
+		assert.Equal(t, int64(32000), synth.outputTokens)
+
+		_, err = synth.chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("Build a researcher")})
+		require.NoError(t, err)
+		assert.Equal(t, float64(32000), recorded.body["max_tokens"])
+		assert.Equal(t, 0.75, recorded.body["temperature"])
+	})
+
+	t.Run("openai", func(t *testing.T) {
+		server, recorded := newFakeProvider(t, http.StatusOK, `{
+			"id": "chatcmpl-1",
+			"object": "chat.completion",
+			"created": 1700000000,
+			"model": "test-model",
+			"choices": [{"index": 0, "message": {"role": "assistant", "content": "agent \"researcher\" do\nend"}, "finish_reason": "stop"}],
+			"usage": {"prompt_tokens": 12, "completion_tokens": 5, "total_tokens": 17}
+		}`)
+
+		synth, err := NewSynthesizerWithParameters(context.Background(), nil, newProviderModel("openai", server.URL), time.Minute, params, logr.Discard())
+		require.NoError(t, err)
+
+		_, err = synth.chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("Build a researcher")})
+		require.NoError(t, err)
+		assert.Equal(t, float64(32000), recorded.body["max_tokens"])
+		assert.Equal(t, 0.75, recorded.body["temperature"])
+	})
+}
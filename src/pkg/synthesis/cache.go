@@ -0,0 +1,268 @@
+package synthesis
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// DefaultCacheTTL is how long cached code is reused when no TTL is configured
+	DefaultCacheTTL = 7 * 24 * time.Hour
+
+	// DefaultCacheMaxEntries caps the cached code entries when no limit is configured
+	DefaultCacheMaxEntries = 200
+
+	// maxCacheConfigMapBytes keeps the cache ConfigMap under the 1MiB object size limit
+	maxCacheConfigMapBytes = 900 * 1024
+)
+
+// cacheEntry is a cached synthesis result
+type cacheEntry struct {
+	Code     string    `json:"code"`
+	StoredAt time.Time `json:"storedAt"`
+	// UsedAt is when the entry was last read
+	UsedAt time.Time `json:"usedAt,omitempty"`
+}
+
+// size returns the number of bytes the entry takes up in the cache ConfigMap. JSON escaping
+// makes this larger than the code itself.
+func (e *cacheEntry) size(key string) int {
+	value, err := json.Marshal(e)
+	if err != nil {
+		return len(key) + len(e.Code)
+	}
+	return len(key) + len(value)
+}
+
+// lastUsed returns when the entry was last stored or read, which orders eviction
+func (e *cacheEntry) lastUsed() time.Time {
+	if e.UsedAt.After(e.StoredAt) {
+		return e.UsedAt
+	}
+	return e.StoredAt
+}
+
+// SynthesisCache stores synthesized code keyed by a hash of everything that shaped it, so
+// unchanged agents don't re-run synthesis after an operator restart or a deleted code ConfigMap.
+// Entries expire after TTL, and the least recently used entries are evicted beyond MaxEntries.
+// The cache lives in a ConfigMap when one is configured and in memory otherwise.
+type SynthesisCache struct {
+	TTL        time.Duration
+	MaxEntries int
+
+	client    client.Client
+	reader    client.Reader
+	namespace string
+	name      string
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	// reads holds when ConfigMap entries were last read. Reads aren't written back on their own;
+	// they are saved with the next Put, so a cache hit never costs an API write.
+	reads map[string]time.Time
+	now   func() time.Time
+}
+
+// NewMemoryCache creates a cache that only lives as long as the operator process
+func NewMemoryCache(ttl time.Duration, maxEntries int) *SynthesisCache {
+	return &SynthesisCache{
+		TTL:        ttl,
+		MaxEntries: maxEntries,
+		entries:    map[string]*cacheEntry{},
+		now:        time.Now,
+	}
+}
+
+// NewConfigMapCache creates a cache persisted in the named ConfigMap. Reads go through reader
+// so the cache works even when the manager's cache doesn't cover namespace.
+func NewConfigMapCache(c client.Client, reader client.Reader, namespace, name string, ttl time.Duration, maxEntries int) *SynthesisCache {
+	return &SynthesisCache{
+		TTL:        ttl,
+		MaxEntries: maxEntries,
+		client:     c,
+		reader:     reader,
+		namespace:  namespace,
+		name:       name,
+		reads:      map[string]time.Time{},
+		now:        time.Now,
+	}
+}
+
+// CacheKey derives a cache key from the hashes of the synthesis inputs. The key is a valid
+// ConfigMap data key.
+func CacheKey(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns the cached code for key. Expired entries are misses.
+func (c *SynthesisCache) Get(ctx context.Context, key string) (string, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, _, err := c.load(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	entry, ok := entries[key]
+	if !ok || c.expired(entry) {
+		return "", false, nil
+	}
+	entry.UsedAt = c.now()
+	if c.client != nil {
+		c.reads[key] = entry.UsedAt
+	}
+	return entry.Code, true, nil
+}
+
+// Put stores code under key, dropping expired entries and evicting the least recently used
+// entries beyond the size limits
+func (c *SynthesisCache) Put(ctx context.Context, key, code string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, configMap, err := c.load(ctx)
+	if err != nil {
+		return err
+	}
+	now := c.now()
+	entry := &cacheEntry{Code: code, StoredAt: now, UsedAt: now}
+	if c.client != nil && entry.size(key) > maxCacheConfigMapBytes {
+		// Too large to ever fit the ConfigMap; leave the other entries alone
+		return nil
+	}
+
+	for k, usedAt := range c.reads {
+		if existing, ok := entries[k]; ok && usedAt.After(existing.UsedAt) {
+			existing.UsedAt = usedAt
+		}
+	}
+	entries[key] = entry
+	for k, entry := range entries {
+		if c.expired(entry) {
+			delete(entries, k)
+		}
+	}
+	c.evict(entries)
+
+	if c.client == nil {
+		return nil
+	}
+	if err := c.save(ctx, configMap, entries); err != nil {
+		return err
+	}
+	clear(c.reads)
+	return nil
+}
+
+// expired reports whether the entry is older than the TTL
+func (c *SynthesisCache) expired(entry *cacheEntry) bool {
+	ttl := c.TTL
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+	return c.now().Sub(entry.StoredAt) > ttl
+}
+
+// evict removes the least recently used entries until the cache is within MaxEntries and, for
+// ConfigMap caches, the ConfigMap size limit
+func (c *SynthesisCache) evict(entries map[string]*cacheEntry) {
+	maxEntries := c.MaxEntries
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheMaxEntries
+	}
+
+	keys := make([]string, 0, len(entries))
+	sizes := make(map[string]int, len(entries))
+	size := 0
+	for key, entry := range entries {
+		keys = append(keys, key)
+		sizes[key] = entry.size(key)
+		size += sizes[key]
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return entries[keys[i]].lastUsed().Before(entries[keys[j]].lastUsed())
+	})
+
+	for _, key := range keys {
+		if len(entries) <= maxEntries && (c.client == nil || size <= maxCacheConfigMapBytes) {
+			return
+		}
+		size -= sizes[key]
+		delete(entries, key)
+	}
+}
+
+// load returns the cache entries, reading them from the ConfigMap when the cache is persisted.
+// The ConfigMap is nil when it doesn't exist yet.
+func (c *SynthesisCache) load(ctx context.Context) (map[string]*cacheEntry, *corev1.ConfigMap, error) {
+	if c.client == nil {
+		return c.entries, nil, nil
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := c.reader.Get(ctx, types.NamespacedName{Name: c.name, Namespace: c.namespace}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return map[string]*cacheEntry{}, nil, nil
+		}
+		return nil, nil, fmt.Errorf("failed to get synthesis cache: %w", err)
+	}
+
+	entries := make(map[string]*cacheEntry, len(configMap.Data))
+	for key, value := range configMap.Data {
+		entry := &cacheEntry{}
+		// Entries that can't be decoded are dropped on the next write
+		if err := json.Unmarshal([]byte(value), entry); err == nil {
+			entries[key] = entry
+		}
+	}
+	return entries, configMap, nil
+}
+
+// save writes the entries to the cache ConfigMap, creating it if needed. An existing ConfigMap is
+// patched, so only the entries that were added, removed, or read are sent.
+func (c *SynthesisCache) save(ctx context.Context, configMap *corev1.ConfigMap, entries map[string]*cacheEntry) error {
+	data := make(map[string]string, len(entries))
+	for key, entry := range entries {
+		value, err := json.Marshal(entry)
+		if err != nil {
+			return fmt.Errorf("failed to marshal synthesis cache entry: %w", err)
+		}
+		data[key] = string(value)
+	}
+
+	if configMap == nil {
+		configMap = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      c.name,
+				Namespace: c.namespace,
+				Labels:    map[string]string{"app.kubernetes.io/managed-by": "language-operator"},
+			},
+			Data: data,
+		}
+		if err := c.client.Create(ctx, configMap); err != nil {
+			return fmt.Errorf("failed to create synthesis cache: %w", err)
+		}
+		return nil
+	}
+
+	patch := client.MergeFrom(configMap.DeepCopy())
+	configMap.Data = data
+	if err := c.client.Patch(ctx, configMap, patch); err != nil {
+		return fmt.Errorf("failed to update synthesis cache: %w", err)
+	}
+	return nil
+}
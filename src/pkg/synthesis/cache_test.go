@@ -0,0 +1,174 @@
+package synthesis
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestSynthesisCache_MemoryTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cache := NewMemoryCache(time.Hour, 10)
+	cache.now = func() time.Time { return now }
+
+	if err := cache.Put(ctx, "agent", "code"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if code, ok, _ := cache.Get(ctx, "agent"); !ok || code != "code" {
+		t.Fatalf("Expected a cache hit, got %q, %v", code, ok)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if _, ok, _ := cache.Get(ctx, "agent"); ok {
+		t.Error("Expected an expired entry to be a miss")
+	}
+}
+
+func TestSynthesisCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	cache := NewMemoryCache(time.Hour, 2)
+	cache.now = func() time.Time { return now }
+
+	for _, key := range []string{"first", "second"} {
+		now = now.Add(time.Second)
+		if err := cache.Put(ctx, key, key+" code"); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	// Reading the oldest entry makes "second" the least recently used
+	now = now.Add(time.Second)
+	if _, ok, _ := cache.Get(ctx, "first"); !ok {
+		t.Fatal("Expected a cache hit")
+	}
+	now = now.Add(time.Second)
+	if err := cache.Put(ctx, "third", "third code"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	for key, expected := range map[string]bool{"first": true, "second": false, "third": true} {
+		if _, ok, _ := cache.Get(ctx, key); ok != expected {
+			t.Errorf("Expected %s cached=%v, got %v", key, expected, ok)
+		}
+	}
+}
+
+func TestSynthesisCache_ConfigMapPersistence(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	cache := NewConfigMapCache(fakeClient, fakeClient, "langop-system", "langop-synthesis-cache", time.Hour, 10)
+	key := CacheKey("default", "reporter", "instructions-hash", "tools-hash", "models-hash", "persona-hash")
+	if err := cache.Put(ctx, key, "agent \"reporter\" do\nend"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := fakeClient.Get(ctx, types.NamespacedName{Name: "langop-synthesis-cache", Namespace: "langop-system"}, configMap); err != nil {
+		t.Fatalf("Expected the cache ConfigMap to be created: %v", err)
+	}
+	if !strings.Contains(configMap.Data[key], "reporter") {
+		t.Errorf("Expected the entry to be stored under its key, got %v", configMap.Data)
+	}
+
+	// A new cache, like after an operator restart, reads the persisted entries
+	restarted := NewConfigMapCache(fakeClient, fakeClient, "langop-system", "langop-synthesis-cache", time.Hour, 10)
+	if code, ok, err := restarted.Get(ctx, key); err != nil || !ok || code != "agent \"reporter\" do\nend" {
+		t.Errorf("Expected the persisted entry after a restart, got %q, %v, %v", code, ok, err)
+	}
+	if _, ok, _ := restarted.Get(ctx, CacheKey("default", "reporter", "changed-instructions-hash", "tools-hash", "models-hash", "persona-hash")); ok {
+		t.Error("Expected changed inputs to miss the cache")
+	}
+}
+
+func TestSynthesisCache_ConfigMapSizeLimit(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	cache := NewConfigMapCache(fakeClient, fakeClient, "langop-system", "langop-synthesis-cache", time.Hour, 10)
+
+	large := strings.Repeat("x", maxCacheConfigMapBytes/2)
+	for _, key := range []string{"first", "second", "third"} {
+		if err := cache.Put(ctx, key, large); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	if _, ok, _ := cache.Get(ctx, "first"); ok {
+		t.Error("Expected the oldest entry to be evicted to fit the ConfigMap")
+	}
+	if _, ok, _ := cache.Get(ctx, "third"); !ok {
+		t.Error("Expected the newest entry to be cached")
+	}
+}
+
+func TestSynthesisCache_ConfigMapEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	now := time.Now()
+	newCache := func() *SynthesisCache {
+		cache := NewConfigMapCache(fakeClient, fakeClient, "langop-system", "langop-synthesis-cache", time.Hour, 2)
+		cache.now = func() time.Time { return now }
+		return cache
+	}
+
+	cache := newCache()
+	for _, key := range []string{"first", "second"} {
+		now = now.Add(time.Second)
+		if err := cache.Put(ctx, key, key+" code"); err != nil {
+			t.Fatalf("Put failed: %v", err)
+		}
+	}
+	// The read is saved with the next write, so it orders eviction even after a restart
+	now = now.Add(time.Second)
+	if _, ok, _ := cache.Get(ctx, "first"); !ok {
+		t.Fatal("Expected a cache hit")
+	}
+	now = now.Add(time.Second)
+	if err := cache.Put(ctx, "third", "third code"); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+
+	restarted := newCache()
+	for key, expected := range map[string]bool{"first": true, "second": false, "third": true} {
+		if _, ok, _ := restarted.Get(ctx, key); ok != expected {
+			t.Errorf("Expected %s cached=%v, got %v", key, expected, ok)
+		}
+	}
+}
+
+func TestSynthesisCache_ConfigMapMeasuresEncodedEntries(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	if err := corev1.AddToScheme(scheme); err != nil {
+		t.Fatalf("Failed to add scheme: %v", err)
+	}
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	cache := NewConfigMapCache(fakeClient, fakeClient, "langop-system", "langop-synthesis-cache", time.Hour, 10)
+
+	// Fits the ConfigMap as raw code, but not once every quote is escaped
+	quoted := strings.Repeat(`"`, maxCacheConfigMapBytes*2/3)
+	if err := cache.Put(ctx, "quoted", quoted); err != nil {
+		t.Fatalf("Put failed: %v", err)
+	}
+	if _, ok, _ := cache.Get(ctx, "quoted"); ok {
+		t.Error("Expected an entry too large once encoded not to be cached")
+	}
+}
@@ -0,0 +1,173 @@
+package synthesis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// CandidateSelection records how the deployed implementation was chosen among several candidates
+type CandidateSelection struct {
+	// Generated is the number of candidates synthesized
+	Generated int
+	// Valid is the number of candidates that passed validation
+	Valid int
+	// Rationale explains why the winner was selected
+	Rationale string
+}
+
+// CandidateSynthesizer synthesizes several candidate implementations and returns the best one
+type CandidateSynthesizer interface {
+	SynthesizeCandidates(ctx context.Context, req AgentSynthesisRequest, count int, budget CandidateBudget) (*AgentSynthesisResponse, error)
+	// SynthesizeCandidatesStream synthesizes candidates like SynthesizeCandidates while reporting
+	// the progress of each candidate as SynthesizeAgentStream does
+	SynthesizeCandidatesStream(ctx context.Context, req AgentSynthesisRequest, count int, budget CandidateBudget) (<-chan SynthesisProgress, error)
+}
+
+// CandidateBudget meters the candidates synthesized after the first, which the caller has
+// already accounted for. Allow is asked before each further candidate with the number generated
+// and the cost spent so far, and reports whether it may be synthesized; Done is called with the
+// outcome of every candidate Allow admitted.
+type CandidateBudget interface {
+	Allow(generated int, spent *SynthesisCost) bool
+	Done(success bool, errorMsg string)
+}
+
+// SynthesizeCandidates synthesizes up to count candidate implementations, discards those that fail
+// validation, and returns the best remaining one. Candidates are generated one at a time and
+// generation stops early once budget (when set) refuses another candidate.
+// The returned response carries the combined cost and duration of all candidates.
+func (s *Synthesizer) SynthesizeCandidates(ctx context.Context, req AgentSynthesisRequest, count int, budget CandidateBudget) (*AgentSynthesisResponse, error) {
+	return s.synthesizeCandidates(ctx, req, count, budget, nil)
+}
+
+// SynthesizeCandidatesStream implements CandidateSynthesizer
+func (s *Synthesizer) SynthesizeCandidatesStream(ctx context.Context, req AgentSynthesisRequest, count int, budget CandidateBudget) (<-chan SynthesisProgress, error) {
+	return streamProgress(ctx, func(progress func(SynthesisProgress)) (*AgentSynthesisResponse, error) {
+		return s.synthesizeCandidates(ctx, req, count, budget, progress)
+	})
+}
+
+// synthesizeCandidates implements SynthesizeCandidates, reporting each candidate's progress
+// when progress is set
+func (s *Synthesizer) synthesizeCandidates(ctx context.Context, req AgentSynthesisRequest, count int, budget CandidateBudget, progress func(SynthesisProgress)) (*AgentSynthesisResponse, error) {
+	if count < 1 {
+		count = 1
+	}
+
+	var valid []*AgentSynthesisResponse
+	var lastResp *AgentSynthesisResponse
+	var lastErr error
+	var spent *SynthesisCost
+	var duration float64
+	generated := 0
+
+	for generated < count {
+		metered := generated > 0 && budget != nil
+		if metered && !budget.Allow(generated, spent) {
+			s.log.Info("Candidate synthesis stopped at budget",
+				"agent", req.AgentName,
+				"generated", generated,
+				"requested", count)
+			break
+		}
+
+		resp, err := s.synthesizeAgent(ctx, req, progress)
+		generated++
+		if resp != nil {
+			duration += resp.DurationSeconds
+			spent = addCost(spent, resp.Cost)
+		}
+		failed := err != nil || resp == nil || resp.Error != ""
+		if metered {
+			errorMsg := ""
+			if failed {
+				errorMsg = candidateError(resp, err)
+			}
+			budget.Done(!failed, errorMsg)
+		}
+		if failed {
+			s.log.Info("Discarding invalid synthesis candidate",
+				"agent", req.AgentName,
+				"candidate", generated,
+				"error", candidateError(resp, err))
+			lastResp, lastErr = resp, err
+			continue
+		}
+		valid = append(valid, resp)
+	}
+
+	selection := &CandidateSelection{Generated: generated, Valid: len(valid)}
+	if len(valid) == 0 {
+		selection.Rationale = fmt.Sprintf("none of %d candidates passed validation", generated)
+		if lastResp == nil {
+			lastResp = &AgentSynthesisResponse{}
+		}
+		lastResp.Cost = spent
+		lastResp.DurationSeconds = duration
+		lastResp.Candidates = selection
+		return lastResp, lastErr
+	}
+
+	winner := valid[0]
+	for _, candidate := range valid[1:] {
+		if codeLines(candidate.DSLCode) < codeLines(winner.DSLCode) {
+			winner = candidate
+		}
+	}
+	selection.Rationale = fmt.Sprintf("%d of %d candidates passed validation; selected the shortest valid implementation (%d lines)",
+		len(valid), generated, codeLines(winner.DSLCode))
+
+	s.log.Info("Selected synthesis candidate",
+		"agent", req.AgentName,
+		"generated", generated,
+		"valid", len(valid),
+		"lines", codeLines(winner.DSLCode))
+
+	winner.Cost = spent
+	winner.DurationSeconds = duration
+	winner.Candidates = selection
+	return winner, nil
+}
+
+// codeLines counts the non-blank, non-comment lines of DSL code
+func codeLines(code string) int {
+	count := 0
+	for _, line := range strings.Split(code, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && !strings.HasPrefix(trimmed, "#") {
+			count++
+		}
+	}
+	return count
+}
+
+// addCost sums two synthesis costs, either of which may be nil
+func addCost(total, cost *SynthesisCost) *SynthesisCost {
+	if cost == nil {
+		return total
+	}
+	if total == nil {
+		sum := *cost
+		return &sum
+	}
+	total.InputTokens += cost.InputTokens
+	total.OutputTokens += cost.OutputTokens
+	total.TotalTokens += cost.TotalTokens
+	total.InputCost += cost.InputCost
+	total.OutputCost += cost.OutputCost
+	total.TotalCost += cost.TotalCost
+	total.Timestamp = cost.Timestamp
+	return total
+}
+
+// candidateError describes why a candidate was discarded
+func candidateError(resp *AgentSynthesisResponse, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	if resp != nil {
+		return resp.Error
+	}
+	return "no response"
+}
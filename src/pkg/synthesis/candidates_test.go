@@ -0,0 +1,186 @@
+package synthesis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/go-logr/logr"
+)
+
+// sequenceChatModel returns its responses in order, repeating the last one
+type sequenceChatModel struct {
+	responses []string
+	calls     int
+}
+
+func (m *sequenceChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	response := m.responses[min(m.calls, len(m.responses)-1)]
+	m.calls++
+	return &schema.Message{Role: schema.Assistant, Content: response}, nil
+}
+
+const (
+	verboseCandidate = `require 'language_operator'
+
+agent "researcher" do
+  task :gather do |inputs|
+    news = execute_tool('web', 'search', query: inputs[:topic])
+    extra = execute_tool('web', 'search', query: "#{inputs[:topic]} news")
+    { results: news + extra }
+  end
+
+  main do |inputs|
+    execute_task(:gather, inputs: inputs)
+  end
+end`
+
+	missingToolCandidate = `require 'language_operator'
+
+agent "researcher" do
+  task :gather do |inputs|
+    { results: execute_tool('scraper', 'fetch', query: inputs[:topic]) }
+  end
+
+  main do |inputs|
+    execute_task(:gather, inputs: inputs)
+  end
+end`
+
+	conciseCandidate = `require 'language_operator'
+
+agent "researcher" do
+  task :gather do |inputs|
+    { results: execute_tool('web', 'search', query: inputs[:topic]) }
+  end
+
+  main do |inputs|
+    execute_task(:gather, inputs: inputs)
+  end
+end`
+)
+
+func newCandidateRequest() AgentSynthesisRequest {
+	return AgentSynthesisRequest{
+		Instructions: "Search the web for the topic",
+		Tools:        []string{"web"},
+		AgentName:    "researcher",
+		Namespace:    "default",
+	}
+}
+
+func TestSynthesizer_SynthesizeCandidates_SelectsBestValid(t *testing.T) {
+	chatModel := &sequenceChatModel{responses: []string{verboseCandidate, missingToolCandidate, conciseCandidate}}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	resp, err := synthesizer.SynthesizeCandidates(context.Background(), newCandidateRequest(), 3, nil)
+	if err != nil {
+		t.Fatalf("Expected a valid candidate to be selected, got %v", err)
+	}
+
+	if chatModel.calls != 3 {
+		t.Errorf("Expected 3 candidates to be synthesized, got %d", chatModel.calls)
+	}
+	if resp.DSLCode != conciseCandidate {
+		t.Errorf("Expected the shortest valid candidate to win, got:\n%s", resp.DSLCode)
+	}
+	if resp.Candidates == nil || resp.Candidates.Generated != 3 || resp.Candidates.Valid != 2 {
+		t.Errorf("Expected 3 generated and 2 valid candidates, got %+v", resp.Candidates)
+	}
+	if resp.Candidates != nil && resp.Candidates.Rationale == "" {
+		t.Error("Expected a selection rationale")
+	}
+}
+
+func TestSynthesizer_SynthesizeCandidates_NoValidCandidate(t *testing.T) {
+	chatModel := &sequenceChatModel{responses: []string{missingToolCandidate}}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	resp, err := synthesizer.SynthesizeCandidates(context.Background(), newCandidateRequest(), 2, nil)
+	if !IsMissingToolReference(err) {
+		t.Fatalf("Expected the last candidate's validation error, got %v", err)
+	}
+	if resp.Candidates == nil || resp.Candidates.Generated != 2 || resp.Candidates.Valid != 0 {
+		t.Errorf("Expected 2 generated and no valid candidates, got %+v", resp.Candidates)
+	}
+}
+
+// recordingBudget admits up to allow further candidates and records their outcomes
+type recordingBudget struct {
+	allow   int
+	asked   []int
+	results []bool
+}
+
+func (b *recordingBudget) Allow(generated int, spent *SynthesisCost) bool {
+	b.asked = append(b.asked, generated)
+	if b.allow == 0 {
+		return false
+	}
+	b.allow--
+	return true
+}
+
+func (b *recordingBudget) Done(success bool, errorMsg string) {
+	b.results = append(b.results, success)
+}
+
+func TestSynthesizer_SynthesizeCandidates_StopsAtBudget(t *testing.T) {
+	chatModel := &sequenceChatModel{responses: []string{verboseCandidate, conciseCandidate}}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	budget := &recordingBudget{}
+	resp, err := synthesizer.SynthesizeCandidates(context.Background(), newCandidateRequest(), 3, budget)
+	if err != nil {
+		t.Fatalf("Expected the first candidate to be used, got %v", err)
+	}
+	if chatModel.calls != 1 || resp.DSLCode != verboseCandidate {
+		t.Errorf("Expected generation to stop after the first candidate, got %d calls", chatModel.calls)
+	}
+	if len(budget.asked) != 1 || len(budget.results) != 0 {
+		t.Errorf("Expected one refused request and no metered candidates, got asked=%v results=%v", budget.asked, budget.results)
+	}
+}
+
+func TestSynthesizer_SynthesizeCandidates_MetersEachFurtherCandidate(t *testing.T) {
+	chatModel := &sequenceChatModel{responses: []string{verboseCandidate, missingToolCandidate, conciseCandidate}}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	budget := &recordingBudget{allow: 2}
+	if _, err := synthesizer.SynthesizeCandidates(context.Background(), newCandidateRequest(), 3, budget); err != nil {
+		t.Fatalf("Expected a valid candidate to be selected, got %v", err)
+	}
+	// The first candidate is the caller's own attempt; each further one is asked for and settled
+	if len(budget.asked) != 2 || budget.asked[0] != 1 || budget.asked[1] != 2 {
+		t.Errorf("Expected the budget to be asked before candidates 2 and 3, got %v", budget.asked)
+	}
+	if len(budget.results) != 2 || budget.results[0] || !budget.results[1] {
+		t.Errorf("Expected a failed then a successful metered candidate, got %v", budget.results)
+	}
+}
+
+func TestSynthesizer_SynthesizeCandidatesStream(t *testing.T) {
+	chatModel := &sequenceChatModel{responses: []string{verboseCandidate, conciseCandidate}}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	updates, err := synthesizer.SynthesizeCandidatesStream(context.Background(), newCandidateRequest(), 2, nil)
+	if err != nil {
+		t.Fatalf("SynthesizeCandidatesStream failed: %v", err)
+	}
+	generating := 0
+	var final SynthesisProgress
+	for update := range updates {
+		if update.Done {
+			final = update
+		} else if update.Phase == SynthesisPhaseGenerating {
+			generating++
+		}
+	}
+	if generating != 2 {
+		t.Errorf("Expected a generating update per candidate, got %d", generating)
+	}
+	if final.Err != nil || final.Response == nil || final.Response.DSLCode != conciseCandidate {
+		t.Errorf("Expected the selected candidate in the final update, got %+v", final)
+	}
+}
@@ -37,6 +37,8 @@ const (
 	CompressionThreshold = 800 * 1024
 	// CompressionPrefix indicates compressed data in ConfigMap
 	CompressionPrefix = "gzip:"
+	// VersionedCodeComponent is the langop.io/component label of versioned agent code ConfigMaps
+	VersionedCodeComponent = "agent-code"
 )
 
 // ConfigMapSizeError represents a ConfigMap size limit error
@@ -177,7 +179,7 @@ func (cm *ConfigMapManager) CreateVersionedConfigMap(ctx context.Context, agent
 		"langop.io/agent":          agent.Name,
 		"langop.io/version":        fmt.Sprintf("%d", options.Version),
 		"langop.io/synthesis-type": options.SynthesisType,
-		"langop.io/component":      "agent-code",
+		"langop.io/component":      VersionedCodeComponent,
 	}
 
 	// Add optional learned task information
@@ -304,7 +306,7 @@ func (cm *ConfigMapManager) GetVersionedConfigMaps(ctx context.Context, agent *l
 	configMapList := &corev1.ConfigMapList{}
 	labelSelector := labels.SelectorFromSet(map[string]string{
 		"langop.io/agent":     agent.Name,
-		"langop.io/component": "agent-code",
+		"langop.io/component": VersionedCodeComponent,
 	})
 
 	listOpts := []client.ListOption{
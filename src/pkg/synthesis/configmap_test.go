@@ -48,6 +48,12 @@ func TestConfigMapManager_CreateVersionedConfigMap(t *testing.T) {
 				assert.Equal(t, "agent 'test' do\nend", cm.Data["agent.rb"])
 				assert.Contains(t, cm.Annotations, "langop.io/created-at")
 				assert.Equal(t, "manual", cm.Annotations["langop.io/learned-from"])
+				// The agent owns every version, so deleting the agent garbage collects them
+				owner := metav1.GetControllerOf(cm)
+				require.NotNil(t, owner)
+				assert.Equal(t, "LanguageAgent", owner.Kind)
+				assert.Equal(t, "test-agent", owner.Name)
+				assert.Equal(t, types.UID("test-uid"), owner.UID)
 			},
 		},
 		{
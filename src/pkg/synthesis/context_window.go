@@ -0,0 +1,168 @@
+package synthesis
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// knownContextWindows holds the context windows in tokens of well-known models, keyed by model
+// name prefix. The longest matching prefix wins.
+var knownContextWindows = map[string]int64{
+	"gpt-3.5-turbo": 16385,
+	"gpt-4":         8192,
+	"gpt-4-turbo":   128000,
+	"gpt-4o":        128000,
+	"gpt-4.1":       1047576,
+	"o1":            200000,
+	"o3":            200000,
+	"o4-mini":       200000,
+	"claude":        200000,
+	"gemini-1.5":    1048576,
+	"gemini-2":      1048576,
+}
+
+// maxTrimmedCrashLogBytes is how much of the crash log's tail a trimmed error context keeps
+const maxTrimmedCrashLogBytes = 2000
+
+// ModelContextWindow returns the model's context window in tokens: spec.configuration.contextWindow
+// if set, otherwise the known window of its model name. Zero means the window is unknown.
+func ModelContextWindow(model *langopv1alpha1.LanguageModel) int64 {
+	if model.Spec.Configuration != nil && model.Spec.Configuration.ContextWindow != nil {
+		return int64(*model.Spec.Configuration.ContextWindow)
+	}
+
+	prefixes := make([]string, 0, len(knownContextWindows))
+	for prefix := range knownContextWindows {
+		prefixes = append(prefixes, prefix)
+	}
+	sort.Slice(prefixes, func(i, j int) bool { return len(prefixes[i]) > len(prefixes[j]) })
+
+	name := strings.ToLower(model.Spec.ModelName)
+	// Provider-qualified names such as "anthropic/claude-3-5-sonnet" match on the model part
+	name = name[strings.LastIndex(name, "/")+1:]
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(name, prefix) {
+			return knownContextWindows[prefix]
+		}
+	}
+	return 0
+}
+
+// PromptTooLargeError is returned when a synthesis prompt doesn't fit the model's context window
+// even with every trimmable section trimmed
+type PromptTooLargeError struct {
+	PromptTokens int64
+	Budget       int64
+}
+
+// Error implements the error interface
+func (e *PromptTooLargeError) Error() string {
+	return fmt.Sprintf("synthesis prompt of ~%d tokens exceeds the model's prompt budget of %d tokens even after trimming; shorten spec.instructions or the persona",
+		e.PromptTokens, e.Budget)
+}
+
+// promptTrimStep trims one section of a synthesis request. It returns false when the section
+// has nothing left to trim.
+type promptTrimStep struct {
+	section string
+	trim    func(req *AgentSynthesisRequest) bool
+}
+
+// promptTrimSteps are applied in order, least important section first, until the prompt fits.
+// Instructions, persona, and model names are never trimmed.
+var promptTrimSteps = []promptTrimStep{
+	{section: "examples", trim: func(req *AgentSynthesisRequest) bool {
+		if len(req.Examples) == 0 {
+			return false
+		}
+		req.Examples = req.Examples[:len(req.Examples)-1]
+		return true
+	}},
+	{section: "tool schemas", trim: func(req *AgentSynthesisRequest) bool {
+		// Summarize to each tool's name and description
+		summarized := false
+		schemas := make([]langopv1alpha1.ToolSchema, len(req.ToolSchemas))
+		for i, schema := range req.ToolSchemas {
+			summarized = summarized || schema.InputSchema != nil || schema.OutputSchema != nil
+			schemas[i] = langopv1alpha1.ToolSchema{Name: schema.Name, Description: schema.Description}
+		}
+		req.ToolSchemas = schemas
+		return summarized
+	}},
+	{section: "error context", trim: func(req *AgentSynthesisRequest) bool {
+		// Keep the most recent runtime error without its stack trace and the tail of the crash log
+		if req.ErrorContext == nil {
+			return false
+		}
+		errorContext := *req.ErrorContext
+		trimmed := false
+		if n := len(errorContext.RuntimeErrors); n > 1 || (n == 1 && len(errorContext.RuntimeErrors[0].StackTrace) > 0) {
+			latest := errorContext.RuntimeErrors[n-1]
+			latest.StackTrace = nil
+			errorContext.RuntimeErrors = []RuntimeError{latest}
+			trimmed = true
+		}
+		if len(errorContext.LastCrashLog) > maxTrimmedCrashLogBytes {
+			errorContext.LastCrashLog = errorContext.LastCrashLog[len(errorContext.LastCrashLog)-maxTrimmedCrashLogBytes:]
+			trimmed = true
+		}
+		req.ErrorContext = &errorContext
+		return trimmed
+	}},
+	{section: "last known good code", trim: func(req *AgentSynthesisRequest) bool {
+		if req.LastKnownGoodCode == "" {
+			return false
+		}
+		req.LastKnownGoodCode = ""
+		return true
+	}},
+}
+
+// trimPromptToFit trims the least important sections of req until countTokens reports the
+// prompt fits budget. It returns the trimmed request and a description of each trimmed section.
+func trimPromptToFit(req AgentSynthesisRequest, budget int64, countTokens func(AgentSynthesisRequest) int64) (AgentSynthesisRequest, []string, error) {
+	tokens := countTokens(req)
+	if tokens <= budget {
+		return req, nil, nil
+	}
+
+	examples := len(req.Examples)
+	var trimmed []string
+	for _, step := range promptTrimSteps {
+		stepTrimmed := false
+		for tokens > budget && step.trim(&req) {
+			stepTrimmed = true
+			tokens = countTokens(req)
+		}
+		if stepTrimmed {
+			section := step.section
+			if step.section == "examples" {
+				section = fmt.Sprintf("examples (kept %d of %d)", len(req.Examples), examples)
+			}
+			trimmed = append(trimmed, section)
+		}
+		if tokens <= budget {
+			return req, trimmed, nil
+		}
+	}
+	return req, trimmed, &PromptTooLargeError{PromptTokens: tokens, Budget: budget}
+}
+
+// fitContextWindow trims req so its prompt leaves room for the response in the model's context
+// window. Synthesizers without a known context window send the request as-is.
+func (s *Synthesizer) fitContextWindow(req AgentSynthesisRequest) (AgentSynthesisRequest, []string, error) {
+	if s.contextWindow <= 0 {
+		return req, nil, nil
+	}
+	tokenizer := s.tokenizer
+	if tokenizer == nil {
+		tokenizer = defaultTokenizer("")
+	}
+	budget := s.contextWindow - s.outputTokens
+	return trimPromptToFit(req, budget, func(req AgentSynthesisRequest) int64 {
+		return tokenizer.CountTokens(s.buildSynthesisPrompt(req))
+	})
+}
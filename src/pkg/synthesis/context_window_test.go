@@ -0,0 +1,148 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+func TestModelContextWindow(t *testing.T) {
+	window := int32(32000)
+	tests := []struct {
+		name          string
+		modelName     string
+		configuration *langopv1alpha1.ProviderConfiguration
+		expected      int64
+	}{
+		{name: "known model", modelName: "gpt-4", expected: 8192},
+		{name: "longest prefix wins", modelName: "gpt-4o-mini", expected: 128000},
+		{name: "provider-qualified name", modelName: "anthropic/claude-3-5-sonnet", expected: 200000},
+		{name: "unknown model", modelName: "llama3:8b", expected: 0},
+		{name: "configured window overrides the known window", modelName: "gpt-4o",
+			configuration: &langopv1alpha1.ProviderConfiguration{ContextWindow: &window}, expected: 32000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			model := &langopv1alpha1.LanguageModel{Spec: langopv1alpha1.LanguageModelSpec{
+				ModelName:     tt.modelName,
+				Configuration: tt.configuration,
+			}}
+			assert.Equal(t, tt.expected, ModelContextWindow(model))
+		})
+	}
+}
+
+// newOversizedRequest returns a synthesis request with every trimmable section populated
+func newOversizedRequest() AgentSynthesisRequest {
+	example := func(name string) SynthesisExample {
+		return SynthesisExample{Name: name, Instructions: "Summarize " + name, Code: strings.Repeat("# step\n", 400)}
+	}
+	return AgentSynthesisRequest{
+		AgentName:    "summarizer",
+		Instructions: "Summarize the team's open pull requests every morning",
+		PersonaText:  "You are concise.",
+		Models:       []string{"gpt-4o"},
+		ToolSchemas: []langopv1alpha1.ToolSchema{{
+			Name:        "github_search",
+			Description: "Searches GitHub",
+			InputSchema: &langopv1alpha1.ToolSchemaDefinition{
+				Type: "object",
+				Properties: map[string]langopv1alpha1.ToolProperty{
+					"query": {Type: "string", Description: strings.Repeat("search query syntax ", 200)},
+				},
+			},
+		}},
+		Examples: []SynthesisExample{example("issues"), example("releases"), example("alerts")},
+		ErrorContext: &ErrorContext{
+			RuntimeErrors: []RuntimeError{
+				{ErrorType: "NameError", ErrorMessage: "undefined tool", StackTrace: []string{strings.Repeat("frame ", 300)}},
+				{ErrorType: "Timeout", ErrorMessage: "tool timed out", StackTrace: []string{strings.Repeat("frame ", 300)}},
+			},
+			LastCrashLog: strings.Repeat("log line\n", 1000),
+		},
+		LastKnownGoodCode: strings.Repeat("agent \"summarizer\" do\nend\n", 200),
+	}
+}
+
+func TestFitContextWindow(t *testing.T) {
+	synthesizer := &Synthesizer{log: logr.Discard(), outputTokens: 1000, tokenizer: CharRatioTokenizer{CharsPerToken: 4}}
+	count := func(req AgentSynthesisRequest) int64 {
+		return synthesizer.tokenizer.CountTokens(synthesizer.buildSynthesisPrompt(req))
+	}
+	full := newOversizedRequest()
+
+	oneExample := newOversizedRequest()
+	oneExample.Examples = oneExample.Examples[:1]
+
+	summarizedSchemas := newOversizedRequest()
+	summarizedSchemas.Examples = nil
+	summarizedSchemas.ToolSchemas = []langopv1alpha1.ToolSchema{{Name: "github_search", Description: "Searches GitHub"}}
+
+	minimal := newOversizedRequest()
+	minimal.Examples = nil
+	minimal.ToolSchemas = summarizedSchemas.ToolSchemas
+	minimal.ErrorContext.RuntimeErrors = []RuntimeError{{ErrorType: "Timeout", ErrorMessage: "tool timed out"}}
+	minimal.ErrorContext.LastCrashLog = minimal.ErrorContext.LastCrashLog[len(minimal.ErrorContext.LastCrashLog)-maxTrimmedCrashLogBytes:]
+	minimal.LastKnownGoodCode = ""
+
+	tests := []struct {
+		name          string
+		budget        int64
+		expectTrimmed []string
+	}{
+		{name: "prompt within budget is untouched", budget: count(full)},
+		{name: "examples are dropped first", budget: count(oneExample),
+			expectTrimmed: []string{"examples (kept 1 of 3)"}},
+		{name: "tool schemas are summarized after examples", budget: count(summarizedSchemas),
+			expectTrimmed: []string{"examples (kept 0 of 3)", "tool schemas"}},
+		{name: "everything trimmable is trimmed in priority order", budget: count(minimal),
+			expectTrimmed: []string{"examples (kept 0 of 3)", "tool schemas", "error context", "last known good code"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			synthesizer.contextWindow = tt.budget + synthesizer.outputTokens
+			req, trimmed, err := synthesizer.fitContextWindow(newOversizedRequest())
+			require.NoError(t, err)
+			assert.Equal(t, tt.expectTrimmed, trimmed)
+			assert.LessOrEqual(t, count(req), tt.budget)
+			// Instructions and persona are never trimmed
+			assert.Equal(t, full.Instructions, req.Instructions)
+			assert.Equal(t, full.PersonaText, req.PersonaText)
+		})
+	}
+
+	// Sections keep what the budget allows: the summarized schemas keep their names
+	synthesizer.contextWindow = count(summarizedSchemas) + synthesizer.outputTokens
+	req, _, err := synthesizer.fitContextWindow(newOversizedRequest())
+	require.NoError(t, err)
+	require.Len(t, req.ToolSchemas, 1)
+	assert.Equal(t, "github_search", req.ToolSchemas[0].Name)
+	assert.Nil(t, req.ToolSchemas[0].InputSchema)
+	assert.Len(t, req.ErrorContext.RuntimeErrors, 2)
+	assert.NotEmpty(t, req.LastKnownGoodCode)
+
+	// Without a known context window nothing is trimmed
+	synthesizer.contextWindow = 0
+	req, trimmed, err := synthesizer.fitContextWindow(full)
+	require.NoError(t, err)
+	assert.Empty(t, trimmed)
+	assert.Len(t, req.Examples, 3)
+}
+
+func TestSynthesizeAgent_PromptTooLarge(t *testing.T) {
+	synthesizer := &Synthesizer{log: logr.Discard(), contextWindow: 2000, outputTokens: 1000}
+
+	resp, err := synthesizer.SynthesizeAgent(context.Background(), newOversizedRequest())
+	var tooLarge *PromptTooLargeError
+	require.True(t, errors.As(err, &tooLarge), "expected a PromptTooLargeError, got %v", err)
+	assert.Equal(t, int64(1000), tooLarge.Budget)
+	require.NotNil(t, resp)
+	assert.Equal(t, []string{"examples (kept 0 of 3)", "tool schemas", "error context", "last known good code"}, resp.PromptTrimmed)
+}
@@ -0,0 +1,29 @@
+package synthesis
+
+import (
+	"errors"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ReasonCostDataUnavailable is the condition reason used when an agent requires cost data but
+// its synthesis model has no pricing configured or reported no cost
+const ReasonCostDataUnavailable = "CostDataUnavailable"
+
+// ErrCostDataUnavailable means synthesis cost can't be tracked against the cost quota
+var ErrCostDataUnavailable = errors.New("synthesis cost data unavailable")
+
+// IsCostDataUnavailable reports whether err was caused by missing synthesis cost data
+func IsCostDataUnavailable(err error) bool {
+	return errors.Is(err, ErrCostDataUnavailable)
+}
+
+// HasPricing reports whether the model has token pricing configured, so synthesis with it is
+// charged against the cost quota. Pricing explicitly set to zero counts: it acknowledges the
+// model is free.
+func HasPricing(model *langopv1alpha1.LanguageModel) bool {
+	if model == nil || model.Spec.CostTracking == nil || !model.Spec.CostTracking.Enabled {
+		return false
+	}
+	return model.Spec.CostTracking.InputTokenCost != nil || model.Spec.CostTracking.OutputTokenCost != nil
+}
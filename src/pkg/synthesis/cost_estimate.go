@@ -0,0 +1,185 @@
+package synthesis
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ReasonCostEstimateExceeded is the condition reason used when synthesis is refused because its
+// estimated cost exceeds the namespace's remaining daily cost quota
+const ReasonCostEstimateExceeded = "CostEstimateExceeded"
+
+// defaultSynthesisOutputTokens is the expected output size when the model doesn't set maxTokens,
+// matching the synthesizer's default
+const defaultSynthesisOutputTokens = 8192
+
+// Tokenizer estimates how many tokens a model's tokenizer produces for a text
+type Tokenizer interface {
+	CountTokens(text string) int64
+}
+
+// CharRatioTokenizer estimates tokens from the average number of characters per token, with a
+// 10% safety margin
+type CharRatioTokenizer struct {
+	CharsPerToken float64
+}
+
+// CountTokens implements Tokenizer
+func (t CharRatioTokenizer) CountTokens(text string) int64 {
+	if t.CharsPerToken <= 0 {
+		return EstimateTokens(text)
+	}
+	return int64(math.Ceil(float64(len(text)) / t.CharsPerToken * 1.1))
+}
+
+// defaultTokenizers holds the built-in tokenizer heuristics by LanguageModel provider.
+// Claude's tokenizer produces noticeably more tokens per character than OpenAI's.
+var defaultTokenizers = map[string]Tokenizer{
+	"openai":    CharRatioTokenizer{CharsPerToken: 4.0},
+	"anthropic": CharRatioTokenizer{CharsPerToken: 3.5},
+}
+
+// CostEstimate is the projected cost of a synthesis request
+type CostEstimate struct {
+	InputTokens  int64
+	OutputTokens int64
+	// Cost is the projected cost in the quota currency
+	Cost     float64
+	Currency string
+	// Converted is false when the model's currency couldn't be converted into the quota
+	// currency, in which case Cost is in the model's currency
+	Converted bool
+}
+
+// CostEstimateExceededError is returned when the estimated cost of a synthesis exceeds the
+// namespace's remaining daily cost quota
+type CostEstimateExceededError struct {
+	Estimate *CostEstimate
+	Err      error
+}
+
+// Error implements the error interface
+func (e *CostEstimateExceededError) Error() string {
+	return fmt.Sprintf("estimated synthesis cost %.4f %s (%d input, %d output tokens) exceeds the remaining quota: %v",
+		e.Estimate.Cost, e.Estimate.Currency, e.Estimate.InputTokens, e.Estimate.OutputTokens, e.Err)
+}
+
+// Unwrap returns the underlying quota error
+func (e *CostEstimateExceededError) Unwrap() error {
+	return e.Err
+}
+
+// IsCostEstimateExceeded reports whether err was caused by a synthesis cost estimate over quota
+func IsCostEstimateExceeded(err error) bool {
+	var estimateErr *CostEstimateExceededError
+	return errors.As(err, &estimateErr)
+}
+
+// SetTokenizer overrides the tokenizer used to estimate synthesis costs for a provider
+func (qm *QuotaManager) SetTokenizer(provider string, tokenizer Tokenizer) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+
+	if qm.tokenizers == nil {
+		qm.tokenizers = make(map[string]Tokenizer)
+	}
+	qm.tokenizers[provider] = tokenizer
+}
+
+// tokenizerFor returns the tokenizer for a provider, falling back to the OpenAI heuristic
+// Must be called with qm.mu held
+func (qm *QuotaManager) tokenizerFor(provider string) Tokenizer {
+	if tokenizer, ok := qm.tokenizers[provider]; ok {
+		return tokenizer
+	}
+	return defaultTokenizer(provider)
+}
+
+// defaultTokenizer returns the built-in tokenizer heuristic for a provider, falling back to the
+// OpenAI heuristic
+func defaultTokenizer(provider string) Tokenizer {
+	if tokenizer, ok := defaultTokenizers[provider]; ok {
+		return tokenizer
+	}
+	return defaultTokenizers["openai"]
+}
+
+// EstimateCost projects the cost of a synthesis request generated with params before the model
+// is called. Input tokens are estimated from the instructions, persona, examples, and serialized
+// tool schemas with the provider's tokenizer; output tokens are assumed to reach params' maxTokens.
+func (qm *QuotaManager) EstimateCost(req AgentSynthesisRequest, model *langopv1alpha1.LanguageModel, params GenerationParameters) (*CostEstimate, error) {
+	if model == nil {
+		return nil, fmt.Errorf("no model to estimate synthesis cost for")
+	}
+
+	payload, err := synthesisPayload(req)
+	if err != nil {
+		return nil, err
+	}
+	outputTokens := params.OutputTokens()
+
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+
+	inputTokens := qm.tokenizerFor(model.Spec.Provider).CountTokens(payload)
+	projected := NewCostTracker(model).CalculateCost(inputTokens, outputTokens, model.Spec.ModelName)
+	cost, converted := qm.convertCost(projected.TotalCost, projected.Currency)
+	currency := qm.currency
+	if !converted {
+		currency = projected.Currency
+	}
+
+	return &CostEstimate{
+		InputTokens:  inputTokens,
+		OutputTokens: outputTokens,
+		Cost:         cost,
+		Currency:     currency,
+		Converted:    converted,
+	}, nil
+}
+
+// PricedCost computes the cost of a completed synthesis from the model's configured pricing, for
+// synthesizers that report no cost of their own. Input tokens are estimated from the request as
+// in EstimateCost and output tokens from the generated output.
+func (qm *QuotaManager) PricedCost(req AgentSynthesisRequest, output string, model *langopv1alpha1.LanguageModel) (*SynthesisCost, error) {
+	if model == nil {
+		return nil, fmt.Errorf("no model to price synthesis with")
+	}
+
+	payload, err := synthesisPayload(req)
+	if err != nil {
+		return nil, err
+	}
+
+	qm.mu.RLock()
+	tokenizer := qm.tokenizerFor(model.Spec.Provider)
+	qm.mu.RUnlock()
+
+	return NewCostTracker(model).CalculateCost(tokenizer.CountTokens(payload), tokenizer.CountTokens(output), model.Spec.ModelName), nil
+}
+
+// synthesisPayload concatenates the parts of a synthesis request that are sent to the model:
+// instructions, persona, serialized tool schemas, examples, and the last known good code
+func synthesisPayload(req AgentSynthesisRequest) (string, error) {
+	var payload strings.Builder
+	payload.WriteString(req.Instructions)
+	payload.WriteString(req.PersonaText)
+	if len(req.ToolSchemas) > 0 {
+		schemas, err := json.Marshal(req.ToolSchemas)
+		if err != nil {
+			return "", fmt.Errorf("failed to serialize tool schemas: %w", err)
+		}
+		payload.Write(schemas)
+	}
+	for _, example := range req.Examples {
+		payload.WriteString(example.Instructions)
+		payload.WriteString(example.Code)
+	}
+	payload.WriteString(req.LastKnownGoodCode)
+	return payload.String(), nil
+}
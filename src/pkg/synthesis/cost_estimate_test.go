@@ -0,0 +1,105 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+func newCostEstimateModel(provider string, maxTokens int32) *langopv1alpha1.LanguageModel {
+	inputCost, outputCost := 1.0, 2.0
+	return &langopv1alpha1.LanguageModel{
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:      provider,
+			ModelName:     "test-model",
+			Configuration: &langopv1alpha1.ProviderConfiguration{MaxTokens: &maxTokens},
+			CostTracking: &langopv1alpha1.CostTrackingSpec{
+				Enabled:         true,
+				Currency:        "USD",
+				InputTokenCost:  &inputCost,
+				OutputTokenCost: &outputCost,
+			},
+		},
+	}
+}
+
+func TestCharRatioTokenizer(t *testing.T) {
+	text := strings.Repeat("x", 4000)
+	assert.Equal(t, int64(1100), CharRatioTokenizer{CharsPerToken: 4}.CountTokens(text))
+	assert.Equal(t, EstimateTokens(text), CharRatioTokenizer{}.CountTokens(text), "Expected the default heuristic without a ratio")
+}
+
+func TestQuotaManager_EstimateCost(t *testing.T) {
+	qm := NewQuotaManager(10.0, 100, "USD", testr.New(t))
+	req := AgentSynthesisRequest{
+		Instructions: strings.Repeat("x", 4000),
+		ToolSchemas:  []langopv1alpha1.ToolSchema{{Name: "search", Description: strings.Repeat("y", 400)}},
+	}
+	maxTokens := int32(1000)
+	params := GenerationParameters{MaxTokens: &maxTokens}
+
+	openaiEstimate, err := qm.EstimateCost(req, newCostEstimateModel("openai", 1000), params)
+	require.NoError(t, err)
+	assert.True(t, openaiEstimate.Converted)
+	assert.Equal(t, "USD", openaiEstimate.Currency)
+	assert.Greater(t, openaiEstimate.InputTokens, int64(1100), "Expected tool schemas to count towards input tokens")
+	assert.Equal(t, int64(1000), openaiEstimate.OutputTokens)
+	assert.InDelta(t, float64(openaiEstimate.InputTokens)/1000+2.0, openaiEstimate.Cost, 1e-9)
+
+	anthropicEstimate, err := qm.EstimateCost(req, newCostEstimateModel("anthropic", 1000), params)
+	require.NoError(t, err)
+	assert.Greater(t, anthropicEstimate.InputTokens, openaiEstimate.InputTokens, "Expected the anthropic heuristic to count more tokens")
+
+	qm.SetTokenizer("anthropic", CharRatioTokenizer{CharsPerToken: 100})
+	tunedEstimate, err := qm.EstimateCost(req, newCostEstimateModel("anthropic", 1000), params)
+	require.NoError(t, err)
+	assert.Less(t, tunedEstimate.InputTokens, openaiEstimate.InputTokens, "Expected the configured tokenizer to be used")
+
+	_, err = qm.EstimateCost(req, nil, params)
+	assert.Error(t, err)
+
+	// The agent's maxTokens override bounds the output, not the model's configuration
+	model := newCostEstimateModel("openai", 1000)
+	overrideTokens := int32(32000)
+	overridden, err := qm.EstimateCost(req, model, ResolveGenerationParameters(model, &langopv1alpha1.AgentSynthesisSettings{MaxTokens: &overrideTokens}))
+	require.NoError(t, err)
+	assert.Equal(t, int64(32000), overridden.OutputTokens)
+}
+
+func TestQuotaManager_PricedCost(t *testing.T) {
+	qm := NewQuotaManager(10.0, 100, "USD", testr.New(t))
+	req := AgentSynthesisRequest{Instructions: strings.Repeat("x", 4000)}
+
+	cost, err := qm.PricedCost(req, strings.Repeat("y", 400), newCostEstimateModel("openai", 8192))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1100), cost.InputTokens)
+	assert.Less(t, cost.OutputTokens, int64(200), "Expected output tokens from the generated output, not maxTokens")
+	assert.InDelta(t, 1.1+float64(cost.OutputTokens)*2/1000, cost.TotalCost, 1e-9)
+	assert.Equal(t, "USD", cost.Currency)
+
+	_, err = qm.PricedCost(req, "", nil)
+	assert.Error(t, err)
+}
+
+func TestQuotaManager_EstimateCostAgainstQuota(t *testing.T) {
+	qm := NewQuotaManager(1.0, 100, "USD", testr.New(t))
+	estimate, err := qm.EstimateCost(AgentSynthesisRequest{Instructions: "Summarize the news"}, newCostEstimateModel("openai", 8192), GenerationParameters{})
+	require.NoError(t, err)
+
+	// 8192 output tokens at 2.0 per 1K tokens is well over the 1.0 daily quota
+	quotaErr := qm.CheckCostQuota(context.Background(), "default", estimate.Cost)
+	require.Error(t, quotaErr)
+
+	err = &QuotaExceededError{Err: &CostEstimateExceededError{Estimate: estimate, Err: quotaErr}}
+	assert.True(t, IsQuotaExceeded(err))
+	assert.True(t, IsCostEstimateExceeded(err))
+	assert.True(t, errors.Is(err, quotaErr))
+	assert.False(t, IsCostEstimateExceeded(quotaErr))
+}
@@ -78,9 +78,14 @@ func (ct *CostTracker) CalculateCost(inputTokens, outputTokens int64, modelName
 // EstimateTokens estimates token count from text (approximate: 1 token ~= 4 chars)
 // This is a rough estimate for cost prediction before API calls
 func EstimateTokens(text string) int64 {
+	return estimateTokensFromLength(len(text))
+}
+
+// estimateTokensFromLength estimates the token count of text with the given length in bytes
+func estimateTokensFromLength(length int) int64 {
 	// Average: 1 token = 4 characters for English text
 	// Add 10% buffer for safety
-	estimated := int64(math.Ceil(float64(len(text)) / 4.0 * 1.1))
+	estimated := int64(math.Ceil(float64(length) / 4.0 * 1.1))
 	return estimated
 }
 
@@ -0,0 +1,85 @@
+package synthesis
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ExchangeRateProvider supplies exchange rates for normalizing synthesis costs
+type ExchangeRateProvider interface {
+	// Rate returns the number of units of currency "to" worth one unit of currency "from".
+	// The boolean is false when no rate is known for the pair.
+	Rate(from, to string) (float64, bool)
+}
+
+// StaticExchangeRates is an ExchangeRateProvider backed by a fixed rate table.
+// Each rate is the number of units of that currency worth one unit of the base currency.
+type StaticExchangeRates struct {
+	base  string
+	rates map[string]float64
+}
+
+// NewStaticExchangeRates creates a static rate table relative to the base currency
+func NewStaticExchangeRates(base string, rates map[string]float64) *StaticExchangeRates {
+	normalized := make(map[string]float64, len(rates)+1)
+	for currency, rate := range rates {
+		normalized[normalizeCurrency(currency)] = rate
+	}
+	normalized[normalizeCurrency(base)] = 1.0
+
+	return &StaticExchangeRates{
+		base:  normalizeCurrency(base),
+		rates: normalized,
+	}
+}
+
+// Rate implements ExchangeRateProvider by converting through the base currency
+func (s *StaticExchangeRates) Rate(from, to string) (float64, bool) {
+	fromRate, ok := s.rates[normalizeCurrency(from)]
+	if !ok || fromRate <= 0 {
+		return 0, false
+	}
+	toRate, ok := s.rates[normalizeCurrency(to)]
+	if !ok || toRate <= 0 {
+		return 0, false
+	}
+	return toRate / fromRate, true
+}
+
+// ParseExchangeRates parses a comma-separated list of CURRENCY=RATE pairs (e.g. "EUR=0.92,GBP=0.79")
+func ParseExchangeRates(s string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+
+		currency, value, found := strings.Cut(pair, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid exchange rate %q, expected CURRENCY=RATE", pair)
+		}
+
+		currency = strings.TrimSpace(currency)
+		if currency == "" {
+			return nil, fmt.Errorf("invalid exchange rate %q, currency is empty", pair)
+		}
+
+		rate, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exchange rate for %s: %w", currency, err)
+		}
+		if rate <= 0 {
+			return nil, fmt.Errorf("invalid exchange rate for %s: must be positive, got %v", currency, rate)
+		}
+
+		rates[normalizeCurrency(currency)] = rate
+	}
+	return rates, nil
+}
+
+// normalizeCurrency returns the canonical form of a currency code
+func normalizeCurrency(currency string) string {
+	return strings.ToUpper(strings.TrimSpace(currency))
+}
@@ -0,0 +1,155 @@
+package synthesis
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+)
+
+func floatEquals(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestStaticExchangeRates(t *testing.T) {
+	rates := NewStaticExchangeRates("USD", map[string]float64{"EUR": 0.8, "gbp": 0.5})
+
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		expected float64
+		found    bool
+	}{
+		{name: "base to currency", from: "USD", to: "EUR", expected: 0.8, found: true},
+		{name: "currency to base", from: "EUR", to: "USD", expected: 1.25, found: true},
+		{name: "cross rate via base", from: "EUR", to: "GBP", expected: 0.625, found: true},
+		{name: "case insensitive", from: "gbp", to: "usd", expected: 2.0, found: true},
+		{name: "missing rate", from: "JPY", to: "USD", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rate, found := rates.Rate(tt.from, tt.to)
+			if found != tt.found {
+				t.Fatalf("Expected found=%v, got %v", tt.found, found)
+			}
+			if found && !floatEquals(rate, tt.expected) {
+				t.Errorf("Expected rate %f, got %f", tt.expected, rate)
+			}
+		})
+	}
+}
+
+func TestParseExchangeRates(t *testing.T) {
+	rates, err := ParseExchangeRates("EUR=0.92, gbp=0.79,")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(rates) != 2 || rates["EUR"] != 0.92 || rates["GBP"] != 0.79 {
+		t.Errorf("Unexpected parsed rates: %v", rates)
+	}
+
+	for _, invalid := range []string{"EUR", "=0.9", "EUR=abc", "EUR=0", "EUR=-1"} {
+		if _, err := ParseExchangeRates(invalid); err == nil {
+			t.Errorf("Expected error parsing %q", invalid)
+		}
+	}
+}
+
+func TestQuotaManagerRecordCostConvertsCurrency(t *testing.T) {
+	qm := NewQuotaManager(10.0, 100, "USD", testr.New(t))
+	qm.SetExchangeRateProvider(NewStaticExchangeRates("USD", map[string]float64{"EUR": 0.8}))
+	namespace := "currency-namespace"
+
+	// 4 EUR at 0.8 EUR per USD is 5 USD
+	cost := &SynthesisCost{TotalCost: 4.0, Currency: "EUR"}
+	if err := qm.RecordCost(context.Background(), namespace, "test-agent", cost); err != nil {
+		t.Fatalf("RecordCost failed: %v", err)
+	}
+
+	dailyCost, _, _ := qm.GetNamespaceStats(namespace)
+	if !floatEquals(dailyCost, 5.0) {
+		t.Errorf("Expected daily cost of 5.0 USD, got %f", dailyCost)
+	}
+
+	history := qm.GetCostHistory(namespace)
+	if len(history) != 1 {
+		t.Fatalf("Expected 1 cost entry, got %d", len(history))
+	}
+	entry := history[0]
+	if entry.Unconverted {
+		t.Error("Expected entry to be converted")
+	}
+	if entry.Currency != "USD" || entry.OriginalCurrency != "EUR" || entry.OriginalCost != 4.0 {
+		t.Errorf("Unexpected cost entry: %+v", entry)
+	}
+
+	// The converted amount must count toward enforcement
+	if err := qm.CheckCostQuota(context.Background(), namespace, 6.0); err == nil {
+		t.Error("Expected converted cost to count toward the quota")
+	}
+}
+
+func TestQuotaManagerRecordCostMissingRate(t *testing.T) {
+	qm := NewQuotaManager(10.0, 100, "USD", testr.New(t))
+	qm.SetExchangeRateProvider(NewStaticExchangeRates("USD", map[string]float64{"EUR": 0.8}))
+	namespace := "missing-rate-namespace"
+
+	cost := &SynthesisCost{TotalCost: 300.0, Currency: "JPY"}
+	if err := qm.RecordCost(context.Background(), namespace, "test-agent", cost); err != nil {
+		t.Fatalf("RecordCost failed: %v", err)
+	}
+
+	// Without a rate the raw cost is recorded and flagged
+	dailyCost, _, _ := qm.GetNamespaceStats(namespace)
+	if dailyCost != 300.0 {
+		t.Errorf("Expected raw cost 300 to be recorded, got %f", dailyCost)
+	}
+
+	history := qm.GetCostHistory(namespace)
+	if len(history) != 1 || !history[0].Unconverted {
+		t.Fatalf("Expected one unconverted cost entry, got %+v", history)
+	}
+	if history[0].OriginalCurrency != "JPY" {
+		t.Errorf("Expected original currency JPY, got %s", history[0].OriginalCurrency)
+	}
+}
+
+func TestQuotaManagerConvertCost(t *testing.T) {
+	qm := NewQuotaManager(10.0, 100, "USD", testr.New(t))
+
+	// Same currency and unspecified currency need no rate
+	if converted, ok := qm.ConvertCost(2.0, "usd"); !ok || converted != 2.0 {
+		t.Errorf("Expected same-currency cost to pass through, got %f (ok=%v)", converted, ok)
+	}
+	if converted, ok := qm.ConvertCost(2.0, ""); !ok || converted != 2.0 {
+		t.Errorf("Expected cost without currency to pass through, got %f (ok=%v)", converted, ok)
+	}
+
+	// No provider configured
+	if converted, ok := qm.ConvertCost(2.0, "EUR"); ok || converted != 2.0 {
+		t.Errorf("Expected raw cost without a provider, got %f (ok=%v)", converted, ok)
+	}
+}
+
+func TestQuotaManagerConvertCostTo(t *testing.T) {
+	qm := NewQuotaManager(10.0, 100, "EUR", testr.New(t))
+
+	// A cost without a currency is in the quota currency, which needs a rate to reach USD
+	if converted, ok := qm.ConvertCostTo(2.0, "", "USD"); ok || converted != 2.0 {
+		t.Errorf("Expected raw cost without a provider, got %f (ok=%v)", converted, ok)
+	}
+	if converted, ok := qm.ConvertCostTo(2.0, "usd", "USD"); !ok || converted != 2.0 {
+		t.Errorf("Expected same-currency cost to pass through, got %f (ok=%v)", converted, ok)
+	}
+
+	qm.SetExchangeRateProvider(NewStaticExchangeRates("USD", map[string]float64{"EUR": 0.8}))
+	if converted, ok := qm.ConvertCostTo(2.0, "", "USD"); !ok || !floatEquals(converted, 2.5) {
+		t.Errorf("Expected 2 EUR to convert to 2.5 USD, got %f (ok=%v)", converted, ok)
+	}
+	if _, ok := qm.ConvertCostTo(2.0, "JPY", "USD"); ok {
+		t.Error("Expected no conversion without a rate")
+	}
+}
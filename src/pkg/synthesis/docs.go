@@ -0,0 +1,111 @@
+package synthesis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/cloudwego/eino/schema"
+)
+
+// DocsSynthesizer is implemented by synthesizers that can describe synthesized agent code in
+// human-readable documentation
+type DocsSynthesizer interface {
+	SynthesizeDocs(ctx context.Context, req DocsSynthesisRequest) (*DocsSynthesisResponse, error)
+}
+
+// DocsSynthesisRequest asks for markdown documentation of synthesized agent code
+type DocsSynthesisRequest struct {
+	AgentSynthesisRequest
+
+	// Code is the agent code being documented
+	Code string
+}
+
+// DocsSynthesisResponse is the documentation of synthesized agent code
+type DocsSynthesisResponse struct {
+	// Docs is the markdown documentation
+	Docs string
+	// Cost is the cost of the model call, when cost tracking is configured
+	Cost *SynthesisCost
+}
+
+// SynthesizeDocs generates a markdown summary of the agent's behavior, tasks, tools, and
+// triggers from its synthesized code
+func (s *Synthesizer) SynthesizeDocs(ctx context.Context, req DocsSynthesisRequest) (*DocsSynthesisResponse, error) {
+	ctx, span := tracer.Start(ctx, "synthesis.agent.docs")
+	defer span.End()
+
+	s.log.Info("Synthesizing agent documentation",
+		"agent", req.AgentName,
+		"namespace", req.Namespace,
+		"codeLength", len(req.Code))
+
+	prompt := s.buildDocsPrompt(req)
+	response, err := s.chatModel.Generate(ctx, []*schema.Message{
+		{
+			Role:    schema.User,
+			Content: prompt,
+		},
+	})
+	if err != nil {
+		err = classifyModelError(err)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	// The call is paid for even when its output is unusable
+	result := &DocsSynthesisResponse{Docs: extractMarkdown(response.Content)}
+	if s.costTracker != nil {
+		result.Cost = s.costTracker.CalculateCost(EstimateTokens(prompt), EstimateTokens(response.Content), s.modelName)
+	}
+	if result.Docs == "" {
+		return result, fmt.Errorf("model returned empty documentation")
+	}
+
+	s.log.Info("Agent documentation synthesized",
+		"agent", req.AgentName,
+		"length", len(result.Docs))
+
+	return result, nil
+}
+
+// buildDocsPrompt creates the prompt asking for documentation of the request's code
+func (s *Synthesizer) buildDocsPrompt(req DocsSynthesisRequest) string {
+	return fmt.Sprintf(`Document the following AI agent for the people who operate it.
+
+**Agent Name:** %s
+
+**Instructions the agent was synthesized from:**
+%s
+
+**Available Tools:**
+%s
+
+**Agent Code (Ruby DSL):**
+`+"```ruby\n%s\n```"+`
+
+Write a concise markdown document with these sections:
+## Overview - what the agent does, in two or three sentences
+## Tasks - each task the code defines and what it does
+## Tools - the tools the code actually uses and what it uses them for
+## Triggers - when the agent runs: its schedule, webhooks, or whether it runs continuously
+
+Describe only what the code does; don't invent behavior it doesn't implement.
+Output ONLY the markdown document, nothing else.`,
+		req.AgentName,
+		req.Instructions,
+		s.buildToolsList(req.AgentSynthesisRequest),
+		req.Code)
+}
+
+// extractMarkdown removes a markdown code fence the model may have wrapped the document in
+func extractMarkdown(content string) string {
+	content = strings.TrimSpace(content)
+	for _, fence := range []string{"```markdown", "```md", "```"} {
+		if strings.HasPrefix(content, fence) && strings.HasSuffix(content, "```") && len(content) > len(fence)+3 {
+			return strings.TrimSpace(content[len(fence) : len(content)-3])
+		}
+	}
+	return content
+}
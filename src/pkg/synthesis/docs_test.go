@@ -0,0 +1,70 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const documentedCode = `agent "researcher" do
+  schedule "0 9 * * *"
+  task :search do |inputs|
+    execute_tool('web', 'search', query: inputs[:topic])
+  end
+end`
+
+func TestSynthesizer_SynthesizeDocs(t *testing.T) {
+	chatModel := &mockChatModel{response: "```markdown\n## Overview\nSearches the web every morning.\n```"}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	docs, err := synthesizer.SynthesizeDocs(context.Background(), DocsSynthesisRequest{
+		AgentSynthesisRequest: newCandidateRequest(),
+		Code:                  documentedCode,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "## Overview\nSearches the web every morning.", docs.Docs)
+	assert.Nil(t, docs.Cost)
+
+	// The prompt carries the code, instructions, and tools being documented
+	assert.Contains(t, chatModel.prompt, documentedCode)
+	assert.Contains(t, chatModel.prompt, "Search the web for the topic")
+	assert.Contains(t, chatModel.prompt, "  - web\n")
+	assert.Contains(t, chatModel.prompt, "## Triggers")
+}
+
+func TestSynthesizer_SynthesizeDocs_Cost(t *testing.T) {
+	synthesizer := &Synthesizer{chatModel: &mockChatModel{response: "## Overview\nSearches the web."}, log: logr.Discard()}
+	synthesizer.SetCostTracker(NewCostTracker(newCostEstimateModel("openai", 1024)), "test-model")
+
+	docs, err := synthesizer.SynthesizeDocs(context.Background(), DocsSynthesisRequest{
+		AgentSynthesisRequest: newCandidateRequest(),
+		Code:                  documentedCode,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, docs.Cost)
+	assert.Positive(t, docs.Cost.InputTokens)
+	assert.Positive(t, docs.Cost.TotalCost)
+	assert.Equal(t, "USD", docs.Cost.Currency)
+}
+
+func TestSynthesizer_SynthesizeDocs_Errors(t *testing.T) {
+	req := DocsSynthesisRequest{AgentSynthesisRequest: newCandidateRequest(), Code: documentedCode}
+
+	synthesizer := &Synthesizer{chatModel: &mockChatModel{response: "  \n"}, log: logr.Discard()}
+	_, err := synthesizer.SynthesizeDocs(context.Background(), req)
+	assert.ErrorContains(t, err, "empty documentation")
+
+	synthesizer = &Synthesizer{chatModel: &failingChatModel{err: errors.New("error, status code: 429, status: 429 Too Many Requests")}, log: logr.Discard()}
+	_, err = synthesizer.SynthesizeDocs(context.Background(), req)
+	assert.ErrorIs(t, err, ErrRateLimited)
+}
+
+func TestExtractMarkdown(t *testing.T) {
+	assert.Equal(t, "## Overview", extractMarkdown("```md\n## Overview\n```"))
+	assert.Equal(t, "## Overview", extractMarkdown("```\n## Overview\n```"))
+	assert.Equal(t, "## Overview\n```ruby\nend\n```\nDone", extractMarkdown("\n## Overview\n```ruby\nend\n```\nDone\n"))
+}
@@ -0,0 +1,96 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Sentinel errors classifying why a synthesis failed. Errors returned by SynthesizeAgent wrap
+// one of these when the cause is known; check them with errors.Is.
+var (
+	// ErrLLMTimeout means the model didn't respond within the request timeout
+	ErrLLMTimeout = errors.New("synthesis model request timed out")
+
+	// ErrRateLimited means the model provider rejected the request for exceeding a rate limit
+	ErrRateLimited = errors.New("synthesis model provider rate limited the request")
+
+	// ErrProviderAuth means the model provider rejected the request's credentials
+	ErrProviderAuth = errors.New("synthesis model provider rejected the credentials")
+
+	// ErrValidationFailed means the model responded but the generated code failed validation
+	ErrValidationFailed = errors.New("synthesized code failed validation")
+)
+
+// Event and condition reasons for synthesis failures classified by the sentinels above
+const (
+	ReasonSynthesisFailed           = "SynthesisFailed"
+	ReasonSynthesisTimeout          = "SynthesisTimeout"
+	ReasonSynthesisRateLimited      = "SynthesisRateLimited"
+	ReasonSynthesisAuthError        = "SynthesisAuthError"
+	ReasonSynthesisValidationFailed = "SynthesisValidationFailed"
+)
+
+// FailureReason returns the stable event and condition reason for a synthesis error,
+// ReasonSynthesisFailed when its cause isn't classified
+func FailureReason(err error) string {
+	switch {
+	case errors.Is(err, ErrLLMTimeout):
+		return ReasonSynthesisTimeout
+	case errors.Is(err, ErrRateLimited):
+		return ReasonSynthesisRateLimited
+	case errors.Is(err, ErrProviderAuth):
+		return ReasonSynthesisAuthError
+	case errors.Is(err, ErrValidationFailed):
+		return ReasonSynthesisValidationFailed
+	default:
+		return ReasonSynthesisFailed
+	}
+}
+
+// providerStatusCode matches the HTTP status code in OpenAI-compatible client errors, e.g.
+// "error, status code: 429, status: 429 Too Many Requests, message: ..."
+var providerStatusCode = regexp.MustCompile(`status code: (\d{3})`)
+
+// classifyModelError wraps an error from a model request in the sentinel matching its cause.
+// Errors of unknown cause are returned unchanged.
+func classifyModelError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+		return fmt.Errorf("%w: %w", ErrLLMTimeout, err)
+	}
+
+	message := strings.ToLower(err.Error())
+	if match := providerStatusCode.FindStringSubmatch(message); match != nil {
+		switch code, _ := strconv.Atoi(match[1]); code {
+		case 401, 403:
+			return fmt.Errorf("%w: %w", ErrProviderAuth, err)
+		case 408, 504:
+			return fmt.Errorf("%w: %w", ErrLLMTimeout, err)
+		case 429:
+			return fmt.Errorf("%w: %w", ErrRateLimited, err)
+		}
+	}
+	switch {
+	case strings.Contains(message, "invalid api key"), strings.Contains(message, "incorrect api key"),
+		strings.Contains(message, "unauthorized"):
+		return fmt.Errorf("%w: %w", ErrProviderAuth, err)
+	case strings.Contains(message, "rate limit"):
+		return fmt.Errorf("%w: %w", ErrRateLimited, err)
+	}
+	return err
+}
+
+// validationFailed wraps an error about the generated code in ErrValidationFailed, keeping the
+// original error available to errors.As
+func validationFailed(err error) error {
+	return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+}
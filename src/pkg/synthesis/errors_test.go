@@ -0,0 +1,105 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingChatModel fails every request with err
+type failingChatModel struct {
+	err error
+}
+
+func (m *failingChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return nil, m.err
+}
+
+func TestFailureReason(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+		expected string
+	}{
+		{
+			name:     "request deadline",
+			err:      fmt.Errorf("Post \"https://api.openai.com/v1/chat/completions\": %w", context.DeadlineExceeded),
+			sentinel: ErrLLMTimeout,
+			expected: ReasonSynthesisTimeout,
+		},
+		{
+			name:     "gateway timeout",
+			err:      errors.New("error, status code: 504, status: 504 Gateway Timeout, message: upstream timed out"),
+			sentinel: ErrLLMTimeout,
+			expected: ReasonSynthesisTimeout,
+		},
+		{
+			name:     "provider rate limit",
+			err:      errors.New("error, status code: 429, status: 429 Too Many Requests, message: Rate limit reached for gpt-4o"),
+			sentinel: ErrRateLimited,
+			expected: ReasonSynthesisRateLimited,
+		},
+		{
+			name:     "invalid api key",
+			err:      errors.New("error, status code: 401, status: 401 Unauthorized, message: Incorrect API key provided"),
+			sentinel: ErrProviderAuth,
+			expected: ReasonSynthesisAuthError,
+		},
+		{
+			name:     "forbidden",
+			err:      errors.New("error, status code: 403, status: 403 Forbidden, message: project access denied"),
+			sentinel: ErrProviderAuth,
+			expected: ReasonSynthesisAuthError,
+		},
+		{
+			name:     "auth error without a status code",
+			err:      errors.New("invalid api key"),
+			sentinel: ErrProviderAuth,
+			expected: ReasonSynthesisAuthError,
+		},
+		{
+			name:     "unclassified provider error",
+			err:      errors.New("error, status code: 500, status: 500 Internal Server Error, message: oops"),
+			expected: ReasonSynthesisFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			classified := classifyModelError(tt.err)
+			if tt.sentinel != nil {
+				assert.ErrorIs(t, classified, tt.sentinel)
+			}
+			// The original error stays in the chain and message
+			assert.ErrorIs(t, classified, tt.err)
+			assert.Contains(t, classified.Error(), tt.err.Error())
+			// The reason survives further wrapping by callers
+			assert.Equal(t, tt.expected, FailureReason(fmt.Errorf("synthesis failed: %w", classified)))
+		})
+	}
+
+	validationErr := validationFailed(&MissingToolReferenceError{Tools: []string{"scraper"}})
+	assert.Equal(t, ReasonSynthesisValidationFailed, FailureReason(validationErr))
+	assert.True(t, IsMissingToolReference(validationErr))
+	assert.Equal(t, ReasonSynthesisFailed, FailureReason(nil))
+}
+
+func TestSynthesizeAgent_ClassifiesModelErrors(t *testing.T) {
+	chatModel := &failingChatModel{err: errors.New("error, status code: 401, status: 401 Unauthorized, message: Incorrect API key provided")}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	resp, err := synthesizer.SynthesizeAgent(context.Background(), newCandidateRequest())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrProviderAuth)
+	assert.Equal(t, ReasonSynthesisAuthError, FailureReason(err))
+	require.NotNil(t, resp)
+	assert.Contains(t, resp.Error, "Incorrect API key provided")
+}
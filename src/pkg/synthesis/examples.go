@@ -0,0 +1,124 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// MaxSynthesisExamples caps the few-shot examples included in a synthesis prompt
+	MaxSynthesisExamples = 3
+
+	// maxExampleBytes caps the combined size of the examples so they fit the model context
+	maxExampleBytes = 12000
+)
+
+// ErrExampleSetNotFound is returned when the example library has no set with the requested name
+var ErrExampleSetNotFound = errors.New("synthesis example set not found")
+
+// SynthesisExample is a curated instruction to code pair used as a few-shot synthesis example
+type SynthesisExample struct {
+	Name         string   `json:"name"`
+	Tags         []string `json:"tags,omitempty"`
+	Instructions string   `json:"instructions"`
+	Code         string   `json:"code"`
+}
+
+// ExampleLibrary reads example sets from a shared ConfigMap. Each key of the ConfigMap names
+// an example set and holds a YAML list of examples.
+type ExampleLibrary struct {
+	Reader    client.Reader
+	Namespace string
+	Name      string
+}
+
+// Load returns the examples of the named set
+func (l *ExampleLibrary) Load(ctx context.Context, set string) ([]SynthesisExample, error) {
+	if l == nil {
+		return nil, fmt.Errorf("%w: no example library configured", ErrExampleSetNotFound)
+	}
+
+	configMap := &corev1.ConfigMap{}
+	if err := l.Reader.Get(ctx, types.NamespacedName{Name: l.Name, Namespace: l.Namespace}, configMap); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: example library %s/%s does not exist", ErrExampleSetNotFound, l.Namespace, l.Name)
+		}
+		return nil, fmt.Errorf("failed to get example library: %w", err)
+	}
+
+	data, ok := configMap.Data[set]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q is not in example library %s/%s", ErrExampleSetNotFound, set, l.Namespace, l.Name)
+	}
+	return ParseExampleSet(data)
+}
+
+// ParseExampleSet parses a YAML list of examples, skipping entries without code
+func ParseExampleSet(data string) ([]SynthesisExample, error) {
+	var examples []SynthesisExample
+	if err := yaml.Unmarshal([]byte(data), &examples); err != nil {
+		return nil, fmt.Errorf("failed to parse example set: %w", err)
+	}
+
+	valid := examples[:0]
+	for _, example := range examples {
+		if strings.TrimSpace(example.Code) != "" {
+			valid = append(valid, example)
+		}
+	}
+	return valid, nil
+}
+
+// SelectExamples picks up to limit examples most relevant to the agent. An example scores one
+// point for each of its tags that appears in the instructions or names one of the tools;
+// ties keep library order. Examples that would push the combined size over maxExampleBytes
+// are skipped.
+func SelectExamples(examples []SynthesisExample, instructions string, tools []string, limit int) []SynthesisExample {
+	instructions = strings.ToLower(instructions)
+	toolSet := make(map[string]bool, len(tools))
+	for _, tool := range tools {
+		toolSet[strings.ToLower(tool)] = true
+	}
+
+	scores := make([]int, len(examples))
+	for i, example := range examples {
+		for _, tag := range example.Tags {
+			tag = strings.ToLower(strings.TrimSpace(tag))
+			if tag != "" && (toolSet[tag] || strings.Contains(instructions, tag)) {
+				scores[i]++
+			}
+		}
+	}
+
+	order := make([]int, len(examples))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		return scores[order[a]] > scores[order[b]]
+	})
+
+	var selected []SynthesisExample
+	size := 0
+	for _, i := range order {
+		if len(selected) >= limit {
+			break
+		}
+		exampleSize := len(examples[i].Instructions) + len(examples[i].Code)
+		if size+exampleSize > maxExampleBytes {
+			continue
+		}
+		size += exampleSize
+		selected = append(selected, examples[i])
+	}
+	return selected
+}
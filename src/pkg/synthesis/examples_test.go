@@ -0,0 +1,108 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const exampleSetYAML = `
+- name: github-triage
+  tags: [github, triage]
+  instructions: Label new GitHub issues
+  code: |
+    agent "triage" do
+    end
+- name: slack-digest
+  tags: [slack, digest]
+  instructions: Post a daily digest to Slack
+  code: |
+    agent "digest" do
+    end
+- name: draft
+  instructions: An example without code
+`
+
+func TestParseExampleSet(t *testing.T) {
+	examples, err := ParseExampleSet(exampleSetYAML)
+	require.NoError(t, err)
+	require.Len(t, examples, 2, "Examples without code should be skipped")
+	assert.Equal(t, "github-triage", examples[0].Name)
+	assert.Equal(t, []string{"github", "triage"}, examples[0].Tags)
+
+	_, err = ParseExampleSet("not: [a list")
+	assert.Error(t, err)
+}
+
+func TestSelectExamples(t *testing.T) {
+	examples, err := ParseExampleSet(exampleSetYAML)
+	require.NoError(t, err)
+
+	selected := SelectExamples(examples, "Send a digest of yesterday's alerts", []string{"slack"}, 1)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "slack-digest", selected[0].Name, "Expected the example with the most matching tags")
+
+	selected = SelectExamples(examples, "Summarize the news", nil, MaxSynthesisExamples)
+	require.Len(t, selected, 2, "Unmatched examples still fill the remaining slots")
+	assert.Equal(t, "github-triage", selected[0].Name, "Expected ties to keep library order")
+
+	large := []SynthesisExample{
+		{Name: "huge", Code: strings.Repeat("x", maxExampleBytes+1)},
+		{Name: "small", Code: "agent \"small\" do\nend"},
+	}
+	selected = SelectExamples(large, "", nil, MaxSynthesisExamples)
+	require.Len(t, selected, 1)
+	assert.Equal(t, "small", selected[0].Name, "Expected examples over the size cap to be skipped")
+}
+
+func TestExampleLibrary_Load(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	library := &ExampleLibrary{
+		Reader: fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "synthesis-examples", Namespace: "langop-system"},
+			Data:       map[string]string{"platform-team": exampleSetYAML},
+		}).Build(),
+		Namespace: "langop-system",
+		Name:      "synthesis-examples",
+	}
+
+	examples, err := library.Load(context.Background(), "platform-team")
+	require.NoError(t, err)
+	assert.Len(t, examples, 2)
+
+	_, err = library.Load(context.Background(), "unknown")
+	assert.True(t, errors.Is(err, ErrExampleSetNotFound), "Expected ErrExampleSetNotFound, got %v", err)
+
+	var unconfigured *ExampleLibrary
+	_, err = unconfigured.Load(context.Background(), "platform-team")
+	assert.True(t, errors.Is(err, ErrExampleSetNotFound), "Expected ErrExampleSetNotFound, got %v", err)
+}
+
+func TestSynthesizer_BuildSynthesisPrompt_IncludesExamples(t *testing.T) {
+	synthesizer := &Synthesizer{log: logr.Discard()}
+	req := AgentSynthesisRequest{
+		Instructions: "Label new issues",
+		AgentName:    "triage",
+		Examples: []SynthesisExample{
+			{Name: "github-triage", Instructions: "Label new GitHub issues", Code: `agent "house-style" do`},
+		},
+	}
+
+	prompt := synthesizer.buildSynthesisPrompt(req)
+	assert.Contains(t, prompt, "House Examples")
+	assert.Contains(t, prompt, "github-triage")
+	assert.Contains(t, prompt, `agent "house-style" do`)
+
+	req.Examples = nil
+	assert.NotContains(t, synthesizer.buildSynthesisPrompt(req), "House Examples")
+}
@@ -61,6 +61,24 @@ var (
 		[]string{"namespace", "status"},
 	)
 
+	// SynthesisCostUnconverted tracks costs recorded without currency conversion
+	SynthesisCostUnconverted = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "synthesis_cost_unconverted_total",
+			Help: "Total number of synthesis costs recorded without conversion due to a missing exchange rate by namespace and currency",
+		},
+		[]string{"namespace", "currency"},
+	)
+
+	// SynthesisCacheHits tracks synthesis requests served from the synthesis cache
+	SynthesisCacheHits = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "synthesis_cache_hit_total",
+			Help: "Total number of synthesis requests served from the synthesis cache instead of the LLM by namespace",
+		},
+		[]string{"namespace"},
+	)
+
 	// NamespaceQuotaRemaining tracks remaining quota per namespace
 	NamespaceQuotaRemaining = prometheus.NewGaugeVec(
 		prometheus.GaugeOpts{
@@ -70,6 +88,26 @@ var (
 		[]string{"namespace", "type"}, // type: cost or attempts
 	)
 
+	// SynthesisRateLimitTokensAvailable tracks the synthesis rate limiter's fill level per namespace.
+	// Registered with the manager's metrics registry in main.go and pushed by RateLimiter on every check.
+	SynthesisRateLimitTokensAvailable = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "langop_synthesis_ratelimit_tokens_available",
+			Help: "Synthesis requests the rate limiter would currently allow by namespace",
+		},
+		[]string{"namespace"},
+	)
+
+	// SynthesisQuotaRemainingCost tracks the daily synthesis cost budget left per namespace.
+	// Registered with the manager's metrics registry in main.go and pushed by QuotaManager on every check.
+	SynthesisQuotaRemainingCost = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "langop_synthesis_quota_remaining_cost",
+			Help: "Remaining daily synthesis cost quota in the quota currency by namespace",
+		},
+		[]string{"namespace"},
+	)
+
 	// Learning-specific metrics for tracking organic function evolution
 
 	// LearningTasksTotal tracks total number of tasks that have been learned
@@ -164,6 +202,8 @@ func init() {
 		SynthesisRateLimitExceeded,
 		SynthesisQuotaExceeded,
 		SynthesisDuration,
+		SynthesisCostUnconverted,
+		SynthesisCacheHits,
 		NamespaceQuotaRemaining,
 		// Learning metrics
 		LearningTasksTotal,
@@ -194,6 +234,16 @@ func RecordSynthesisCost(namespace string, cost float64) {
 	SynthesisCostUSD.WithLabelValues(namespace).Add(cost)
 }
 
+// RecordSynthesisCostUnconverted records a cost that could not be converted to the quota currency
+func RecordSynthesisCostUnconverted(namespace, currency string) {
+	SynthesisCostUnconverted.WithLabelValues(namespace, currency).Inc()
+}
+
+// RecordSynthesisCacheHit records synthesis served from the synthesis cache
+func RecordSynthesisCacheHit(namespace string) {
+	SynthesisCacheHits.WithLabelValues(namespace).Inc()
+}
+
 // RecordSynthesisRateLimitExceeded records rate limit violation
 func RecordSynthesisRateLimitExceeded(namespace string) {
 	SynthesisRateLimitExceeded.WithLabelValues(namespace).Inc()
@@ -214,6 +264,12 @@ func UpdateNamespaceQuotaRemaining(namespace, quotaType string, remaining float6
 	NamespaceQuotaRemaining.WithLabelValues(namespace, quotaType).Set(remaining)
 }
 
+// QuotaMetrics returns the collectors the RateLimiter and QuotaManager push to, for registering
+// with the manager's metrics registry
+func QuotaMetrics() []prometheus.Collector {
+	return []prometheus.Collector{SynthesisRateLimitTokensAvailable, SynthesisQuotaRemainingCost}
+}
+
 // Learning metric recording functions
 
 // RecordLearningTask records when a task has been successfully learned
@@ -0,0 +1,89 @@
+package synthesis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/testr"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scrapeGauge gathers the registry and returns the named gauge's value for the namespace
+func scrapeGauge(t *testing.T, registry *prometheus.Registry, name, namespace string) (float64, bool) {
+	t.Helper()
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	for _, family := range families {
+		if family.GetName() != name {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			for _, label := range metric.GetLabel() {
+				if label.GetName() == "namespace" && label.GetValue() == namespace {
+					return metric.GetGauge().GetValue(), true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+func TestRateLimiterPublishesTokensAvailable(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "langop_synthesis_ratelimit_tokens_available",
+	}, []string{"namespace"})
+	registry.MustRegister(gauge)
+
+	rl := NewRateLimiter(3, testr.New(t))
+	rl.SetTokensAvailableGauge(gauge)
+	ctx := context.Background()
+
+	require.NoError(t, rl.CheckAndConsume(ctx, "team-a"))
+	value, found := scrapeGauge(t, registry, "langop_synthesis_ratelimit_tokens_available", "team-a")
+	require.True(t, found)
+	assert.InDelta(t, 2, value, 0.01)
+
+	require.NoError(t, rl.CheckAndConsume(ctx, "team-a"))
+	require.NoError(t, rl.CheckAndConsume(ctx, "team-a"))
+	assert.Error(t, rl.CheckAndConsume(ctx, "team-a"))
+	value, _ = scrapeGauge(t, registry, "langop_synthesis_ratelimit_tokens_available", "team-a")
+	assert.InDelta(t, 0, value, 0.01, "a refused check still publishes the fill level")
+
+	_, found = scrapeGauge(t, registry, "langop_synthesis_ratelimit_tokens_available", "team-b")
+	assert.False(t, found, "namespaces that never checked aren't published")
+}
+
+func TestQuotaManagerPublishesRemainingCost(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	gauge := prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "langop_synthesis_quota_remaining_cost",
+	}, []string{"namespace"})
+	registry.MustRegister(gauge)
+
+	qm := NewQuotaManager(10.0, 100, "USD", testr.New(t))
+	qm.SetRemainingCostGauge(gauge)
+	ctx := context.Background()
+
+	require.NoError(t, qm.CheckCostQuota(ctx, "team-a", 1.0))
+	value, found := scrapeGauge(t, registry, "langop_synthesis_quota_remaining_cost", "team-a")
+	require.True(t, found)
+	assert.Equal(t, 10.0, value)
+
+	require.NoError(t, qm.RecordCost(ctx, "team-a", "agent", &SynthesisCost{TotalCost: 2.5, Currency: "USD"}))
+	value, _ = scrapeGauge(t, registry, "langop_synthesis_quota_remaining_cost", "team-a")
+	assert.Equal(t, 7.5, value)
+
+	require.NoError(t, qm.RecordCost(ctx, "team-a", "agent", &SynthesisCost{TotalCost: 9.0, Currency: "USD"}))
+	assert.Error(t, qm.CheckCostQuota(ctx, "team-a", 0.5))
+	value, _ = scrapeGauge(t, registry, "langop_synthesis_quota_remaining_cost", "team-a")
+	assert.Equal(t, 0.0, value, "an overspent namespace has nothing remaining")
+
+	require.NoError(t, qm.ReserveAttempt(ctx, "team-b"))
+	value, found = scrapeGauge(t, registry, "langop_synthesis_quota_remaining_cost", "team-b")
+	require.True(t, found)
+	assert.Equal(t, 10.0, value)
+}
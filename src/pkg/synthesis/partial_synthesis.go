@@ -0,0 +1,468 @@
+package synthesis
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	_ "embed"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/go-logr/logr"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+)
+
+//go:embed task_regeneration.tmpl
+var taskRegenerationTemplate string
+
+// TaskSynthesizer is implemented by synthesizers that can regenerate individual tasks of
+// existing agent code instead of the whole agent
+type TaskSynthesizer interface {
+	SynthesizeTasks(ctx context.Context, req TaskSynthesisRequest) (*AgentSynthesisResponse, error)
+}
+
+// TaskSynthesisRequest asks for specific tasks of existing agent code to be regenerated
+type TaskSynthesisRequest struct {
+	AgentSynthesisRequest
+
+	// ExistingCode is the agent code the regenerated tasks are spliced into
+	ExistingCode string
+	// Tasks names the tasks to regenerate; all other code is kept as-is
+	Tasks []string
+	// ChangedSections holds the instruction sections that changed since ExistingCode was synthesized
+	ChangedSections []string
+}
+
+var (
+	// taskStartRegex matches the first line of a task definition: task :name or task(:name
+	taskStartRegex = regexp.MustCompile(`^(\s*)task\s*\(?\s*:(\w+)`)
+	// parenthesizedTaskRegex matches the opening of the task(:name form
+	parenthesizedTaskRegex = regexp.MustCompile(`task\s*\(\s*:`)
+	// blockOpenRegex matches a line that opens a do block
+	blockOpenRegex = regexp.MustCompile(`\bdo(\s*\|[^|]*\|)?\s*$`)
+	// stringLiteralRegex matches single and double quoted string literals
+	stringLiteralRegex = regexp.MustCompile(`"(\\.|[^"\\])*"|'(\\.|[^'\\])*'`)
+	// wordRegex matches the words used to relate tasks to instruction sections
+	wordRegex = regexp.MustCompile(`[a-z0-9]+`)
+)
+
+// TaskSpan locates a task definition in agent code by line
+type TaskSpan struct {
+	Name string
+	// Start is the index of the task's first line
+	Start int
+	// End is the index of the line after the task's last line
+	End int
+	// Indent is the indentation of the task's first line
+	Indent string
+}
+
+// FindTaskSpans locates the neural and symbolic task definitions in agent DSL code. Tasks whose
+// do block is never closed are skipped.
+func FindTaskSpans(code string) []TaskSpan {
+	lines := strings.Split(code, "\n")
+	var spans []TaskSpan
+
+	for i := 0; i < len(lines); i++ {
+		match := taskStartRegex.FindStringSubmatch(lines[i])
+		if match == nil {
+			continue
+		}
+		indent := match[1]
+
+		// The task header continues while brackets are open or lines end with a comma
+		end := i
+		depth := 0
+		for end < len(lines) {
+			stripped := stringLiteralRegex.ReplaceAllString(lines[end], `""`)
+			depth += strings.Count(stripped, "(") + strings.Count(stripped, "{") + strings.Count(stripped, "[")
+			depth -= strings.Count(stripped, ")") + strings.Count(stripped, "}") + strings.Count(stripped, "]")
+			end++
+			if depth <= 0 && !strings.HasSuffix(strings.TrimSpace(stripped), ",") {
+				break
+			}
+		}
+
+		// Symbolic tasks run until the end matching the task's indentation
+		if blockOpenRegex.MatchString(lines[end-1]) {
+			closed := false
+			for end < len(lines) {
+				line := lines[end]
+				end++
+				if strings.TrimSpace(line) == "end" && leadingWhitespace(line) == indent {
+					closed = true
+					break
+				}
+			}
+			if !closed {
+				continue
+			}
+		}
+
+		spans = append(spans, TaskSpan{Name: match[2], Start: i, End: end, Indent: indent})
+		i = end - 1
+	}
+
+	return spans
+}
+
+// SpliceTasks replaces the named tasks in code with their definitions from generated, keeping every
+// other line of code unchanged. Regenerated tasks must keep their original inputs and outputs so
+// callers in the main block stay valid.
+func SpliceTasks(code, generated string, tasks []string) (string, error) {
+	lines := strings.Split(code, "\n")
+	generatedLines := strings.Split(generated, "\n")
+
+	existing := make(map[string]TaskSpan)
+	for _, span := range FindTaskSpans(code) {
+		existing[span.Name] = span
+	}
+	replacements := make(map[string]TaskSpan)
+	for _, span := range FindTaskSpans(generated) {
+		replacements[span.Name] = span
+	}
+
+	var spans []TaskSpan
+	for _, task := range tasks {
+		original, ok := existing[task]
+		if !ok {
+			return "", fmt.Errorf("task %s not found in existing code", task)
+		}
+		replacement, ok := replacements[task]
+		if !ok {
+			return "", fmt.Errorf("task %s not found in regenerated code", task)
+		}
+
+		originalDef := parseTaskDefinition(strings.Join(lines[original.Start:original.End], "\n"))
+		replacementDef := parseTaskDefinition(strings.Join(generatedLines[replacement.Start:replacement.End], "\n"))
+		if !reflect.DeepEqual(originalDef.Inputs, replacementDef.Inputs) || !reflect.DeepEqual(originalDef.Outputs, replacementDef.Outputs) {
+			return "", fmt.Errorf("regenerated task %s changed its inputs or outputs", task)
+		}
+		spans = append(spans, original)
+	}
+
+	// Replace from the bottom up so earlier line indexes stay valid
+	sort.Slice(spans, func(i, j int) bool { return spans[i].Start > spans[j].Start })
+	for _, original := range spans {
+		replacement := replacements[original.Name]
+		var block []string
+		for _, line := range generatedLines[replacement.Start:replacement.End] {
+			if strings.TrimSpace(line) == "" {
+				block = append(block, "")
+				continue
+			}
+			block = append(block, original.Indent+strings.TrimPrefix(line, replacement.Indent))
+		}
+
+		spliced := append([]string{}, lines[:original.Start]...)
+		spliced = append(spliced, block...)
+		lines = append(spliced, lines[original.End:]...)
+	}
+
+	return strings.Join(lines, "\n"), nil
+}
+
+// SynthesizeTasks regenerates the requested tasks and splices them into the existing code. The
+// returned DSLCode is the complete agent, validated the same way as a full synthesis.
+func (s *Synthesizer) SynthesizeTasks(ctx context.Context, req TaskSynthesisRequest) (*AgentSynthesisResponse, error) {
+	ctx, span := tracer.Start(ctx, "synthesis.tasks.generate")
+	defer span.End()
+
+	span.SetAttributes(
+		attribute.String("synthesis.agent_name", req.AgentName),
+		attribute.String("synthesis.namespace", req.Namespace),
+		attribute.StringSlice("synthesis.tasks", req.Tasks),
+	)
+
+	startTime := time.Now()
+	s.log.Info("Regenerating agent tasks",
+		"agent", req.AgentName,
+		"namespace", req.Namespace,
+		"tasks", req.Tasks)
+
+	prompt := s.buildTaskRegenerationPrompt(req)
+	responseMsg, err := s.chatModel.Generate(ctx, []*schema.Message{{Role: schema.User, Content: prompt}})
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "LLM call failed")
+		return &AgentSynthesisResponse{
+			Error:           err.Error(),
+			DurationSeconds: time.Since(startTime).Seconds(),
+		}, err
+	}
+
+	var synthesisCost *SynthesisCost
+	if s.costTracker != nil {
+		synthesisCost = s.costTracker.CalculateCost(EstimateTokens(prompt), EstimateTokens(responseMsg.Content), s.modelName)
+	}
+
+	dslCode, err := SpliceTasks(req.ExistingCode, extractCodeFromMarkdown(responseMsg.Content), req.Tasks)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Failed to splice regenerated tasks")
+		return &AgentSynthesisResponse{
+			Error:            err.Error(),
+			DurationSeconds:  time.Since(startTime).Seconds(),
+			ValidationErrors: []string{err.Error()},
+			Cost:             synthesisCost,
+		}, fmt.Errorf("failed to splice regenerated tasks: %w", err)
+	}
+
+	if validationErrors, err := s.validateSplicedCode(ctx, dslCode, req.AgentSynthesisRequest); err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Validation failed")
+		return &AgentSynthesisResponse{
+			DSLCode:          dslCode,
+			Error:            fmt.Sprintf("Validation failed: %v", err),
+			DurationSeconds:  time.Since(startTime).Seconds(),
+			ValidationErrors: validationErrors,
+			Cost:             synthesisCost,
+		}, err
+	}
+
+	duration := time.Since(startTime).Seconds()
+	span.SetAttributes(
+		attribute.Int("synthesis.code_length", len(dslCode)),
+		attribute.Float64("synthesis.duration_seconds", duration),
+	)
+	span.SetStatus(codes.Ok, "Task regeneration successful")
+
+	s.log.Info("Agent tasks regenerated successfully",
+		"agent", req.AgentName,
+		"tasks", req.Tasks,
+		"duration", duration)
+
+	return &AgentSynthesisResponse{
+		DSLCode:         dslCode,
+		DurationSeconds: duration,
+		Cost:            synthesisCost,
+		Provider:        s.provider,
+	}, nil
+}
+
+// validateSplicedCode runs the schema, DSL, and tool reference checks of a full synthesis
+func (s *Synthesizer) validateSplicedCode(ctx context.Context, code string, req AgentSynthesisRequest) ([]string, error) {
+	violations, err := ValidateGeneratedCodeAgainstSchema(ctx, code)
+	if err != nil {
+		return []string{err.Error()}, fmt.Errorf("schema validation execution failed: %w", err)
+	}
+	if len(violations) > 0 {
+		var validationErrors []string
+		for _, violation := range violations {
+			validationErrors = append(validationErrors, fmt.Sprintf("Line %d: %s (%s)", violation.Location, violation.Message, violation.Type))
+		}
+		return validationErrors, fmt.Errorf("schema validation failed with %d violations", len(violations))
+	}
+
+	if err := s.validateDSL(ctx, code); err != nil {
+		return []string{err.Error()}, err
+	}
+
+	toolSchemaNames := make([]string, 0, len(req.ToolSchemas))
+	for _, toolSchema := range req.ToolSchemas {
+		toolSchemaNames = append(toolSchemaNames, toolSchema.Name)
+	}
+	if err := LintToolReferences(code, req.Tools, toolSchemaNames); err != nil {
+		return []string{err.Error()}, err
+	}
+	if err := LintPersonaConstraints(code, req.PersonaConstraints); err != nil {
+		return []string{err.Error()}, err
+	}
+	return nil, nil
+}
+
+// buildTaskRegenerationPrompt creates the prompt for regenerating individual tasks
+func (s *Synthesizer) buildTaskRegenerationPrompt(req TaskSynthesisRequest) string {
+	toolsList := s.buildToolsList(req.AgentSynthesisRequest)
+
+	tmpl, err := template.New("task_regeneration").Parse(taskRegenerationTemplate)
+	if err != nil {
+		s.log.Error(err, "Failed to parse task regeneration template")
+		return s.buildTaskRegenerationPromptFallback(req, toolsList)
+	}
+
+	data := map[string]interface{}{
+		"Instructions":    req.Instructions,
+		"ChangedSections": req.ChangedSections,
+		"Tasks":           req.Tasks,
+		"ExistingCode":    req.ExistingCode,
+		"ToolsList":       toolsList,
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		s.log.Error(err, "Failed to execute task regeneration template")
+		return s.buildTaskRegenerationPromptFallback(req, toolsList)
+	}
+
+	return buf.String()
+}
+
+// buildTaskRegenerationPromptFallback provides a fallback when template loading fails
+func (s *Synthesizer) buildTaskRegenerationPromptFallback(req TaskSynthesisRequest, toolsList string) string {
+	return fmt.Sprintf(`Regenerate ONLY these tasks of the agent code below so they implement the updated instructions: %s
+
+Keep each task's name, inputs, and outputs unchanged. Output only the regenerated task definitions in a ruby code block.
+
+Updated instructions:
+%s
+
+Current agent code:
+%s
+
+Available tools:
+%s`,
+		strings.Join(req.Tasks, ", "),
+		req.Instructions,
+		req.ExistingCode,
+		toolsList)
+}
+
+// SplitInstructionSections splits instructions into the blank-line separated sections that
+// partial synthesis tracks independently
+func SplitInstructionSections(instructions string) []string {
+	var sections []string
+	var current []string
+	for _, line := range strings.Split(instructions, "\n") {
+		if strings.TrimSpace(line) == "" {
+			if len(current) > 0 {
+				sections = append(sections, strings.Join(current, "\n"))
+				current = nil
+			}
+			continue
+		}
+		current = append(current, strings.TrimSpace(line))
+	}
+	if len(current) > 0 {
+		sections = append(sections, strings.Join(current, "\n"))
+	}
+	return sections
+}
+
+// HashSection returns the short hash used to track an instruction section
+func HashSection(section string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(section)))[:16]
+}
+
+// MapTasksToSections relates each task in code to the instruction sections it implements, by the
+// words the task's name and instructions share with each section. Returns task name to the hashes
+// of its best matching sections; tasks that share no words with any section are omitted.
+func MapTasksToSections(code string, sections []string) map[string][]string {
+	lines := strings.Split(code, "\n")
+
+	sectionWords := make([]map[string]bool, len(sections))
+	for i, section := range sections {
+		sectionWords[i] = significantWords(section)
+	}
+
+	mapping := make(map[string][]string)
+	for _, span := range FindTaskSpans(code) {
+		taskWords := significantWords(strings.ReplaceAll(span.Name, "_", " "))
+		for word := range significantWords(parseTaskDefinition(strings.Join(lines[span.Start:span.End], "\n")).Instructions) {
+			taskWords[word] = true
+		}
+
+		best := 0
+		var matches []string
+		for i, words := range sectionWords {
+			score := 0
+			for word := range taskWords {
+				if words[word] {
+					score++
+				}
+			}
+			switch {
+			case score == 0 || score < best:
+			case score > best:
+				best = score
+				matches = []string{HashSection(sections[i])}
+			default:
+				// Ties are ambiguous, so the task depends on every tied section
+				matches = append(matches, HashSection(sections[i]))
+			}
+		}
+		if len(matches) > 0 {
+			mapping[span.Name] = matches
+		}
+	}
+	return mapping
+}
+
+// PlanTaskRegeneration decides which tasks must be regenerated after instructions change.
+// previousSections holds the section hashes the code was synthesized from and taskSections the
+// mapping recorded by MapTasksToSections. It returns the tasks to regenerate and the changed
+// sections, or ok=false when the change can't be confined to a subset of tasks and the whole
+// agent must be re-synthesized.
+func PlanTaskRegeneration(code string, previousSections []string, taskSections map[string][]string, currentSections []string) (tasks []string, changed []string, ok bool) {
+	// Added or removed sections may need new tasks or a new main block
+	if len(previousSections) == 0 || len(previousSections) != len(currentSections) {
+		return nil, nil, false
+	}
+
+	changedHashes := make(map[string]bool)
+	for i, section := range currentSections {
+		if HashSection(section) == previousSections[i] {
+			continue
+		}
+		// Schedule changes live outside tasks
+		if detectTemporalIntent(section) != Continuous {
+			return nil, nil, false
+		}
+		changedHashes[previousSections[i]] = true
+		changed = append(changed, section)
+	}
+	if len(changed) == 0 {
+		return nil, nil, false
+	}
+
+	covered := make(map[string]bool)
+	for _, span := range FindTaskSpans(code) {
+		for _, hash := range taskSections[span.Name] {
+			if changedHashes[hash] {
+				tasks = append(tasks, span.Name)
+				covered[hash] = true
+				break
+			}
+		}
+	}
+
+	// A changed section with no task is implemented elsewhere (or not at all), and regenerating
+	// every task saves nothing over a full synthesis
+	if len(covered) != len(changedHashes) || len(tasks) == len(FindTaskSpans(code)) {
+		return nil, nil, false
+	}
+	return tasks, changed, true
+}
+
+// parseTaskDefinition parses a task block located by FindTaskSpans, accepting both the task :name
+// and task(:name forms
+func parseTaskDefinition(block string) *TaskDefinition {
+	block = parenthesizedTaskRegex.ReplaceAllString(block, "task :")
+	if def := NewTaskValidator(logr.Discard()).parseTaskBlock(block, nil); def != nil {
+		return def
+	}
+	return &TaskDefinition{}
+}
+
+// significantWords returns the lowercase words of text that are long enough to carry meaning
+func significantWords(text string) map[string]bool {
+	words := make(map[string]bool)
+	for _, word := range wordRegex.FindAllString(strings.ToLower(text), -1) {
+		if len(word) > 3 {
+			words[word] = true
+		}
+	}
+	return words
+}
+
+// leadingWhitespace returns the indentation of a line
+func leadingWhitespace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
@@ -0,0 +1,214 @@
+package synthesis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/go-logr/logr"
+)
+
+const newsDigestCode = `require 'language_operator'
+
+agent "news-digest" do
+  description "Summarize technology news"
+
+  task :fetch_headlines do |inputs|
+    { headlines: inputs[:headlines] || [] }
+  end
+
+  task :summarize_headlines,
+    instructions: "summarize the headlines in three bullet points",
+    inputs: { headlines: 'array' },
+    outputs: { summary: 'string' }
+
+  main do |inputs|
+    news = execute_task(:fetch_headlines)
+    digest = execute_task(:summarize_headlines, inputs: news)
+    digest
+  end
+end`
+
+const newsDigestInstructions = "Fetch the latest technology headlines.\n\nSummarize the headlines in three bullet points."
+
+// mockChatModel returns a fixed response and records the prompt it was given
+type mockChatModel struct {
+	response string
+	prompt   string
+}
+
+func (m *mockChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	m.prompt = input[len(input)-1].Content
+	return &schema.Message{Role: schema.Assistant, Content: m.response}, nil
+}
+
+// sectionHashes returns the hashes of the sections of instructions
+func sectionHashes(instructions string) []string {
+	var hashes []string
+	for _, section := range SplitInstructionSections(instructions) {
+		hashes = append(hashes, HashSection(section))
+	}
+	return hashes
+}
+
+func TestFindTaskSpans(t *testing.T) {
+	spans := FindTaskSpans(newsDigestCode)
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 tasks, got %+v", spans)
+	}
+
+	lines := strings.Split(newsDigestCode, "\n")
+	if spans[0].Name != "fetch_headlines" || strings.TrimSpace(lines[spans[0].End-1]) != "end" {
+		t.Errorf("Expected symbolic task to span through its end, got %+v", spans[0])
+	}
+	if spans[1].Name != "summarize_headlines" || !strings.Contains(lines[spans[1].End-1], "outputs:") {
+		t.Errorf("Expected neural task to span through its outputs, got %+v", spans[1])
+	}
+	if spans[1].Indent != "  " {
+		t.Errorf("Expected task indent of two spaces, got %q", spans[1].Indent)
+	}
+}
+
+func TestSpliceTasks(t *testing.T) {
+	generated := `task :summarize_headlines,
+  instructions: "summarize the headlines in five bullet points",
+  inputs: { headlines: 'array' },
+  outputs: { summary: 'string' }`
+
+	spliced, err := SpliceTasks(newsDigestCode, generated, []string{"summarize_headlines"})
+	if err != nil {
+		t.Fatalf("SpliceTasks failed: %v", err)
+	}
+
+	if !strings.Contains(spliced, `    instructions: "summarize the headlines in five bullet points",`) {
+		t.Errorf("Expected regenerated task at the original indentation, got:\n%s", spliced)
+	}
+	if !strings.Contains(spliced, "task :fetch_headlines do |inputs|\n    { headlines: inputs[:headlines] || [] }\n  end") {
+		t.Errorf("Expected untouched task to be preserved, got:\n%s", spliced)
+	}
+	if strings.Contains(spliced, "three bullet points") {
+		t.Errorf("Expected old task definition to be replaced, got:\n%s", spliced)
+	}
+}
+
+func TestSpliceTasks_Errors(t *testing.T) {
+	tests := []struct {
+		name      string
+		generated string
+		errMsg    string
+	}{
+		{
+			name:      "task missing from response",
+			generated: "task :other_task,\n  instructions: \"do something\",\n  outputs: { summary: 'string' }",
+			errMsg:    "not found in regenerated code",
+		},
+		{
+			name:      "changed outputs",
+			generated: "task :summarize_headlines,\n  instructions: \"summarize\",\n  inputs: { headlines: 'array' },\n  outputs: { bullets: 'array' }",
+			errMsg:    "changed its inputs or outputs",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := SpliceTasks(newsDigestCode, tt.generated, []string{"summarize_headlines"})
+			if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("Expected error containing %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
+
+func TestPlanTaskRegeneration(t *testing.T) {
+	previous := sectionHashes(newsDigestInstructions)
+	taskSections := MapTasksToSections(newsDigestCode, SplitInstructionSections(newsDigestInstructions))
+
+	tests := []struct {
+		name         string
+		instructions string
+		expectedOK   bool
+		expected     []string
+	}{
+		{
+			name:         "single task section changed",
+			instructions: "Fetch the latest technology headlines.\n\nSummarize the headlines in five bullet points.",
+			expectedOK:   true,
+			expected:     []string{"summarize_headlines"},
+		},
+		{
+			name:         "section added",
+			instructions: newsDigestInstructions + "\n\nPost the summary to the team channel.",
+			expectedOK:   false,
+		},
+		{
+			name:         "schedule change",
+			instructions: "Fetch the latest technology headlines every hour.\n\nSummarize the headlines in three bullet points.",
+			expectedOK:   false,
+		},
+		{
+			name:         "unchanged",
+			instructions: newsDigestInstructions,
+			expectedOK:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, changed, ok := PlanTaskRegeneration(newsDigestCode, previous, taskSections, SplitInstructionSections(tt.instructions))
+			if ok != tt.expectedOK {
+				t.Fatalf("Expected ok=%v, got %v (tasks %v)", tt.expectedOK, ok, tasks)
+			}
+			if !ok {
+				return
+			}
+			if strings.Join(tasks, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Expected tasks %v, got %v", tt.expected, tasks)
+			}
+			if len(changed) != 1 {
+				t.Errorf("Expected one changed section, got %v", changed)
+			}
+		})
+	}
+}
+
+func TestPlanTaskRegeneration_AllTasksAffected(t *testing.T) {
+	// A single section maps to every task, so regenerating them all is a full synthesis
+	instructions := "Fetch the latest technology headlines and summarize the headlines in three bullet points."
+	taskSections := MapTasksToSections(newsDigestCode, SplitInstructionSections(instructions))
+
+	changedInstructions := "Fetch the latest technology headlines and summarize the headlines in five bullet points."
+	if _, _, ok := PlanTaskRegeneration(newsDigestCode, sectionHashes(instructions), taskSections, SplitInstructionSections(changedInstructions)); ok {
+		t.Error("Expected a change affecting every task to require full synthesis")
+	}
+}
+
+func TestSynthesizer_SynthesizeTasks(t *testing.T) {
+	chatModel := &mockChatModel{response: "```ruby\ntask :summarize_headlines,\n  instructions: \"summarize the headlines in five bullet points\",\n  inputs: { headlines: 'array' },\n  outputs: { summary: 'string' }\n```"}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	resp, err := synthesizer.SynthesizeTasks(context.Background(), TaskSynthesisRequest{
+		AgentSynthesisRequest: AgentSynthesisRequest{
+			Instructions: "Fetch the latest technology headlines.\n\nSummarize the headlines in five bullet points.",
+			AgentName:    "news-digest",
+			Namespace:    "default",
+		},
+		ExistingCode:    newsDigestCode,
+		Tasks:           []string{"summarize_headlines"},
+		ChangedSections: []string{"Summarize the headlines in five bullet points."},
+	})
+	if err != nil {
+		t.Fatalf("SynthesizeTasks failed: %v", err)
+	}
+
+	if !strings.Contains(chatModel.prompt, "- summarize_headlines") || strings.Contains(chatModel.prompt, "- fetch_headlines") {
+		t.Errorf("Expected prompt to request only the changed task, got:\n%s", chatModel.prompt)
+	}
+	if !strings.Contains(resp.DSLCode, "five bullet points") {
+		t.Errorf("Expected regenerated task in code, got:\n%s", resp.DSLCode)
+	}
+	if !strings.Contains(resp.DSLCode, "task :fetch_headlines do |inputs|") || !strings.Contains(resp.DSLCode, "main do |inputs|") {
+		t.Errorf("Expected the rest of the agent to be preserved, got:\n%s", resp.DSLCode)
+	}
+}
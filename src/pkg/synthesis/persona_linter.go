@@ -0,0 +1,106 @@
+package synthesis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+// ReasonPersonaConstraintViolation is the condition reason used when synthesized code violates its persona's constraints
+const ReasonPersonaConstraintViolation = "PersonaConstraintViolation"
+
+// negationWindow is how many words before a blocked topic are searched for a negation
+const negationWindow = 5
+
+// negationWords mark a blocked topic as being refused rather than discussed, e.g. "never discuss politics"
+var negationWords = map[string]bool{
+	"no": true, "not": true, "never": true, "avoid": true, "avoids": true, "refuse": true,
+	"refuses": true, "without": true, "don't": true, "dont": true, "except": true,
+}
+
+// PersonaConstraintError is returned when synthesized code violates the constraints of the agent's persona
+type PersonaConstraintError struct {
+	Violations []string
+}
+
+// Error implements the error interface
+func (e *PersonaConstraintError) Error() string {
+	return fmt.Sprintf("synthesized code violates persona constraints: %s", strings.Join(e.Violations, "; "))
+}
+
+// IsPersonaConstraintViolation reports whether err was caused by synthesized code violating persona constraints
+func IsPersonaConstraintViolation(err error) bool {
+	var constraintErr *PersonaConstraintError
+	return errors.As(err, &constraintErr)
+}
+
+// LintPersonaConstraints checks DSL code against the composed persona constraints. No task may make
+// more than MaxToolCalls execute_tool calls, and the code may not mention a blocked topic outside of
+// comments unless the mention refuses it (e.g. "never discuss politics").
+func LintPersonaConstraints(code string, constraints *langopv1alpha1.PersonaConstraints) error {
+	if constraints == nil {
+		return nil
+	}
+
+	var violations []string
+	lines := strings.Split(code, "\n")
+
+	if constraints.MaxToolCalls != nil {
+		for _, span := range FindTaskSpans(code) {
+			body := strings.Join(lines[span.Start:span.End], "\n")
+			if calls := len(executeToolPattern.FindAllStringIndex(body, -1)); calls > int(*constraints.MaxToolCalls) {
+				violations = append(violations, fmt.Sprintf("task %s makes %d tool calls but the persona allows at most %d",
+					span.Name, calls, *constraints.MaxToolCalls))
+			}
+		}
+	}
+
+	for _, topic := range constraints.BlockedTopics {
+		topic = strings.TrimSpace(topic)
+		if topic == "" {
+			continue
+		}
+		if line := findBlockedTopic(lines, topic); line > 0 {
+			violations = append(violations, fmt.Sprintf("line %d references blocked topic %q", line, topic))
+		}
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	return &PersonaConstraintError{Violations: violations}
+}
+
+// findBlockedTopic returns the 1-based line of the first non-refusing mention of topic, or 0
+func findBlockedTopic(lines []string, topic string) int {
+	pattern := regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(topic) + `\b`)
+
+	for i, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+		for _, loc := range pattern.FindAllStringIndex(line, -1) {
+			if !refusesTopic(line[:loc[0]]) {
+				return i + 1
+			}
+		}
+	}
+	return 0
+}
+
+// refusesTopic reports whether the text leading up to a topic mention negates it
+func refusesTopic(prefix string) bool {
+	words := strings.Fields(strings.ToLower(prefix))
+	if len(words) > negationWindow {
+		words = words[len(words)-negationWindow:]
+	}
+	for _, word := range words {
+		if negationWords[strings.Trim(word, `"'.,:;!?(`)] {
+			return true
+		}
+	}
+	return false
+}
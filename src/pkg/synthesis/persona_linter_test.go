@@ -0,0 +1,124 @@
+package synthesis
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-logr/logr"
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+	"k8s.io/utils/ptr"
+)
+
+const threeToolCallsCode = `require 'language_operator'
+
+agent "researcher" do
+  task :gather do |inputs|
+    news = execute_tool('web', 'search', query: inputs[:topic])
+    papers = execute_tool('arxiv', 'search', query: inputs[:topic])
+    posts = execute_tool('reddit', 'search', query: inputs[:topic])
+    { results: news + papers + posts }
+  end
+
+  task :summarize,
+    instructions: "summarize the results and never mention politics",
+    inputs: { results: 'array' },
+    outputs: { summary: 'string' }
+
+  main do |inputs|
+    results = execute_task(:gather, inputs: inputs)
+    execute_task(:summarize, inputs: results)
+  end
+end`
+
+func TestLintPersonaConstraints(t *testing.T) {
+	tests := []struct {
+		name        string
+		code        string
+		constraints *langopv1alpha1.PersonaConstraints
+		expected    []string
+	}{
+		{
+			name:        "no constraints",
+			code:        threeToolCallsCode,
+			constraints: nil,
+		},
+		{
+			name:        "task within maxToolCalls",
+			code:        threeToolCallsCode,
+			constraints: &langopv1alpha1.PersonaConstraints{MaxToolCalls: ptr.To[int32](3)},
+		},
+		{
+			name:        "task exceeding maxToolCalls",
+			code:        threeToolCallsCode,
+			constraints: &langopv1alpha1.PersonaConstraints{MaxToolCalls: ptr.To[int32](2)},
+			expected:    []string{"task gather makes 3 tool calls but the persona allows at most 2"},
+		},
+		{
+			name:        "refused blocked topic",
+			code:        threeToolCallsCode,
+			constraints: &langopv1alpha1.PersonaConstraints{BlockedTopics: []string{"politics"}},
+		},
+		{
+			name: "referenced blocked topic",
+			code: `agent "pundit" do
+  # politics is out of scope
+  task :opine,
+    instructions: "write a hot take on Politics",
+    outputs: { take: 'string' }
+end`,
+			constraints: &langopv1alpha1.PersonaConstraints{BlockedTopics: []string{"politics"}},
+			expected:    []string{`line 4 references blocked topic "politics"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LintPersonaConstraints(tt.code, tt.constraints)
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Errorf("Expected no violations, got %v", err)
+				}
+				return
+			}
+
+			if !IsPersonaConstraintViolation(err) {
+				t.Fatalf("Expected a persona constraint violation, got %v", err)
+			}
+			violations := err.(*PersonaConstraintError).Violations
+			if strings.Join(violations, "\n") != strings.Join(tt.expected, "\n") {
+				t.Errorf("Expected violations %v, got %v", tt.expected, violations)
+			}
+		})
+	}
+}
+
+func TestIsPersonaConstraintViolation_Wrapped(t *testing.T) {
+	err := fmt.Errorf("synthesis failed: %w", &PersonaConstraintError{Violations: []string{"task a makes 4 tool calls"}})
+	if !IsPersonaConstraintViolation(err) {
+		t.Error("Expected wrapped error to be detected")
+	}
+}
+
+func TestSynthesizer_SynthesizeTasks_RejectsPersonaViolation(t *testing.T) {
+	chatModel := &mockChatModel{response: "```ruby\ntask :gather do |inputs|\n  a = execute_tool('web', 'search', query: inputs[:topic])\n  b = execute_tool('arxiv', 'search', query: inputs[:topic])\n  c = execute_tool('reddit', 'search', query: inputs[:topic])\n  d = execute_tool('hn', 'search', query: inputs[:topic])\n  { results: a + b + c + d }\nend\n```"}
+	synthesizer := &Synthesizer{chatModel: chatModel, log: logr.Discard()}
+
+	_, err := synthesizer.SynthesizeTasks(context.Background(), TaskSynthesisRequest{
+		AgentSynthesisRequest: AgentSynthesisRequest{
+			Instructions:       "Gather results from every source.\n\nSummarize the results.",
+			Tools:              []string{"web", "arxiv", "reddit", "hn"},
+			AgentName:          "researcher",
+			Namespace:          "default",
+			PersonaConstraints: &langopv1alpha1.PersonaConstraints{MaxToolCalls: ptr.To[int32](3)},
+		},
+		ExistingCode:    threeToolCallsCode,
+		Tasks:           []string{"gather"},
+		ChangedSections: []string{"Gather results from every source."},
+	})
+
+	if !IsPersonaConstraintViolation(err) {
+		t.Errorf("Expected code exceeding maxToolCalls to be rejected, got %v", err)
+	}
+}
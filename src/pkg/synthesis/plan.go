@@ -0,0 +1,35 @@
+package synthesis
+
+import (
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// CodePlan compares freshly synthesized code with the code an agent currently runs, so the
+// change synthesis would make can be reviewed before it is applied
+type CodePlan struct {
+	Old         string `json:"old"`
+	New         string `json:"new"`
+	Diff        string `json:"diff"`
+	ModeChanged bool   `json:"mode_changed"`
+}
+
+// Changed reports whether the planned code differs from the current code
+func (p CodePlan) Changed() bool {
+	return p.Diff != ""
+}
+
+// DiffCode returns a unified diff of agent.rb from oldCode to newCode, empty when they are equal
+func DiffCode(oldCode, newCode string) string {
+	if oldCode == newCode {
+		return ""
+	}
+	// Writing to the in-memory buffer cannot fail
+	diff, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(oldCode),
+		B:        difflib.SplitLines(newCode),
+		FromFile: "current/agent.rb",
+		ToFile:   "planned/agent.rb",
+		Context:  3,
+	})
+	return diff
+}
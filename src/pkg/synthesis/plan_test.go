@@ -0,0 +1,25 @@
+package synthesis
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDiffCode(t *testing.T) {
+	current := "agent \"news\" do\n  mode :autonomous\nend\n"
+	planned := "agent \"news\" do\n  schedule \"0 8 * * *\"\nend\n"
+
+	if diff := DiffCode(current, current); diff != "" {
+		t.Errorf("Expected no diff for unchanged code, got %q", diff)
+	}
+
+	diff := DiffCode(current, planned)
+	for _, line := range []string{"--- current/agent.rb", "+++ planned/agent.rb", "-  mode :autonomous", "+  schedule \"0 8 * * *\""} {
+		if !strings.Contains(diff, line+"\n") {
+			t.Errorf("Expected the diff to contain %q, got:\n%s", line, diff)
+		}
+	}
+	if !(CodePlan{Diff: diff}).Changed() {
+		t.Error("Expected a plan with a diff to be changed")
+	}
+}
@@ -2,13 +2,37 @@ package synthesis
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
+// QuotaExceededError is returned when synthesis is refused by a rate limit or quota
+type QuotaExceededError struct {
+	Err error
+}
+
+// Error implements the error interface
+func (e *QuotaExceededError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying limit error
+func (e *QuotaExceededError) Unwrap() error {
+	return e.Err
+}
+
+// IsQuotaExceeded reports whether err was caused by an exhausted synthesis rate limit or quota
+func IsQuotaExceeded(err error) bool {
+	var quotaErr *QuotaExceededError
+	return errors.As(err, &quotaErr)
+}
+
 // QuotaManager tracks synthesis quotas and costs per namespace
 type QuotaManager struct {
 	mu sync.RWMutex
@@ -16,11 +40,19 @@ type QuotaManager struct {
 	// Per-namespace tracking
 	namespaceQuotas map[string]*NamespaceQuota
 
+	// Per-agent tracking, keyed by namespace/name, for agents with their own synthesis quota
+	agentQuotas map[string]*NamespaceQuota
+
 	// Global limits
 	maxCostPerNamespacePerDay float64
 	maxAttemptsPerDay         int
 	currency                  string
+	exchangeRates             ExchangeRateProvider
+	tokenizers                map[string]Tokenizer
 	log                       logr.Logger
+
+	// remainingCost receives each namespace's remaining daily cost after every check
+	remainingCost *prometheus.GaugeVec
 }
 
 // NamespaceQuota tracks quota usage for a single namespace
@@ -37,6 +69,10 @@ type NamespaceQuota struct {
 	attemptsResetAt time.Time
 	attemptHistory  []AttemptEntry
 
+	// pendingAttempts counts attempts reserved by ReserveAttempt that are still running,
+	// so concurrent reconciles can't all pass the attempt check before any is recorded
+	pendingAttempts int
+
 	mu sync.RWMutex
 }
 
@@ -46,6 +82,23 @@ type CostEntry struct {
 	Cost      float64
 	AgentName string
 	Currency  string
+
+	// OriginalCost and OriginalCurrency hold the cost as reported by the model
+	OriginalCost     float64
+	OriginalCurrency string
+	// Unconverted is set when no exchange rate was available and Cost is the raw reported cost
+	Unconverted bool
+
+	// InputTokens and OutputTokens are the token usage the cost was reported for
+	InputTokens  int64
+	OutputTokens int64
+}
+
+// AgentQuota holds the daily limits an agent sets for itself. A zero limit leaves that
+// dimension bounded by the namespace quota alone.
+type AgentQuota struct {
+	MaxCostPerDay     float64
+	MaxAttemptsPerDay int
 }
 
 // AttemptEntry represents a single synthesis attempt
@@ -60,13 +113,103 @@ type AttemptEntry struct {
 func NewQuotaManager(maxCostPerDay float64, maxAttemptsPerDay int, currency string, log logr.Logger) *QuotaManager {
 	return &QuotaManager{
 		namespaceQuotas:           make(map[string]*NamespaceQuota),
+		agentQuotas:               make(map[string]*NamespaceQuota),
 		maxCostPerNamespacePerDay: maxCostPerDay,
 		maxAttemptsPerDay:         maxAttemptsPerDay,
 		currency:                  currency,
 		log:                       log,
+		remainingCost:             SynthesisQuotaRemainingCost,
 	}
 }
 
+// SetRemainingCostGauge replaces the gauge receiving each namespace's remaining daily cost.
+// A nil gauge stops publishing.
+func (qm *QuotaManager) SetRemainingCostGauge(gauge *prometheus.GaugeVec) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+
+	qm.remainingCost = gauge
+}
+
+// publishRemainingCost pushes the namespace's remaining daily cost to the gauge. Callers must
+// hold qm.mu and the quota's lock.
+func (qm *QuotaManager) publishRemainingCost(quota *NamespaceQuota) {
+	if qm.remainingCost == nil {
+		return
+	}
+	remaining := qm.maxCostPerNamespacePerDay - quota.dailyCost
+	if remaining < 0 {
+		remaining = 0
+	}
+	qm.remainingCost.WithLabelValues(quota.Namespace).Set(remaining)
+}
+
+// Limits returns the daily per-namespace cost and attempt limits and the quota currency
+func (qm *QuotaManager) Limits() (maxCostPerDay float64, maxAttemptsPerDay int, currency string) {
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+	return qm.maxCostPerNamespacePerDay, qm.maxAttemptsPerDay, qm.currency
+}
+
+// SetExchangeRateProvider configures the rates used to convert reported costs into the quota currency
+func (qm *QuotaManager) SetExchangeRateProvider(provider ExchangeRateProvider) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+
+	qm.exchangeRates = provider
+}
+
+// ConvertCost converts an amount in the given currency into the quota currency.
+// If no exchange rate is available the raw amount is returned with converted=false.
+func (qm *QuotaManager) ConvertCost(amount float64, currency string) (float64, bool) {
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+
+	return qm.convertCost(amount, currency)
+}
+
+// ConvertCostTo converts an amount in the given currency into the target currency. Costs without
+// a currency are taken to be in the quota currency. If no exchange rate is available the raw
+// amount is returned with converted=false.
+func (qm *QuotaManager) ConvertCostTo(amount float64, currency, target string) (float64, bool) {
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+
+	if currency == "" {
+		currency = qm.currency
+	}
+	return qm.convertCostTo(amount, currency, target)
+}
+
+// convertCost converts an amount into the quota currency
+// Must be called with qm.mu held
+func (qm *QuotaManager) convertCost(amount float64, currency string) (float64, bool) {
+	// Costs without a currency are assumed to already be in the quota currency
+	if currency == "" {
+		return amount, true
+	}
+	return qm.convertCostTo(amount, currency, qm.currency)
+}
+
+// convertCostTo converts an amount between currencies
+// Must be called with qm.mu held
+func (qm *QuotaManager) convertCostTo(amount float64, currency, target string) (float64, bool) {
+	if normalizeCurrency(currency) == normalizeCurrency(target) {
+		return amount, true
+	}
+
+	if qm.exchangeRates == nil {
+		return amount, false
+	}
+
+	rate, ok := qm.exchangeRates.Rate(currency, target)
+	if !ok {
+		return amount, false
+	}
+
+	return amount * rate, true
+}
+
 // NewNamespaceQuota creates a new namespace quota tracker
 func NewNamespaceQuota(namespace string) *NamespaceQuota {
 	now := time.Now()
@@ -98,6 +241,7 @@ func (qm *QuotaManager) CheckCostQuota(ctx context.Context, namespace string, es
 
 	// Reset daily counters if needed
 	quota.resetIfNeeded()
+	qm.publishRemainingCost(quota)
 
 	// Check if adding this cost would exceed quota
 	projectedCost := quota.dailyCost + estimatedCost
@@ -140,9 +284,10 @@ func (qm *QuotaManager) CheckAttemptQuota(ctx context.Context, namespace string)
 
 	// Reset daily counters if needed
 	quota.resetIfNeeded()
+	qm.publishRemainingCost(quota)
 
-	// Check if we've hit the attempt limit
-	if quota.dailyAttempts >= qm.maxAttemptsPerDay {
+	// Check if we've hit the attempt limit, counting attempts reserved by ReserveAttempt
+	if quota.dailyAttempts+quota.pendingAttempts >= qm.maxAttemptsPerDay {
 		qm.log.Info("Attempt quota exceeded",
 			"namespace", namespace,
 			"attempts", quota.dailyAttempts,
@@ -158,6 +303,65 @@ func (qm *QuotaManager) CheckAttemptQuota(ctx context.Context, namespace string)
 	return nil
 }
 
+// ReserveAttempt checks the attempt quota and, if an attempt is allowed, reserves it until
+// ReleaseAttempt is called. Unlike CheckAttemptQuota, in-flight reservations count against the
+// quota, so concurrent callers can't exceed it between the check and RecordAttempt.
+func (qm *QuotaManager) ReserveAttempt(ctx context.Context, namespace string) error {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+
+	// Get or create quota tracker for namespace
+	quota, exists := qm.namespaceQuotas[namespace]
+	if !exists {
+		quota = NewNamespaceQuota(namespace)
+		qm.namespaceQuotas[namespace] = quota
+	}
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	// Reset daily counters if needed
+	quota.resetIfNeeded()
+	qm.publishRemainingCost(quota)
+
+	if quota.dailyAttempts+quota.pendingAttempts >= qm.maxAttemptsPerDay {
+		qm.log.Info("Attempt quota exceeded",
+			"namespace", namespace,
+			"attempts", quota.dailyAttempts,
+			"pending", quota.pendingAttempts,
+			"limit", qm.maxAttemptsPerDay)
+
+		return fmt.Errorf("synthesis attempt quota exceeded for namespace %s: %d attempts today and %d in progress, limit is %d (resets at %s)",
+			namespace,
+			quota.dailyAttempts,
+			quota.pendingAttempts,
+			qm.maxAttemptsPerDay,
+			quota.attemptsResetAt.Format(time.RFC3339))
+	}
+
+	quota.pendingAttempts++
+	return nil
+}
+
+// ReleaseAttempt releases an attempt reserved by ReserveAttempt. Callers record the outcome
+// with RecordAttempt before releasing it.
+func (qm *QuotaManager) ReleaseAttempt(namespace string) {
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+
+	quota, exists := qm.namespaceQuotas[namespace]
+	if !exists {
+		return
+	}
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	if quota.pendingAttempts > 0 {
+		quota.pendingAttempts--
+	}
+}
+
 // RecordCost records an actual synthesis cost
 func (qm *QuotaManager) RecordCost(ctx context.Context, namespace, agentName string, cost *SynthesisCost) error {
 	if cost == nil {
@@ -180,20 +384,41 @@ func (qm *QuotaManager) RecordCost(ctx context.Context, namespace, agentName str
 	// Reset daily counters if needed
 	quota.resetIfNeeded()
 
+	// Normalize the cost into the quota currency before enforcement
+	normalizedCost, converted := qm.convertCost(cost.TotalCost, cost.Currency)
+	if !converted {
+		qm.log.Info("No exchange rate available, recording unconverted synthesis cost",
+			"namespace", namespace,
+			"agent", agentName,
+			"cost", cost.TotalCost,
+			"currency", cost.Currency,
+			"quotaCurrency", qm.currency)
+		RecordSynthesisCostUnconverted(namespace, normalizeCurrency(cost.Currency))
+	}
+
 	// Record the cost
-	quota.dailyCost += cost.TotalCost
+	quota.dailyCost += normalizedCost
+	qm.recordAgentUsage(namespace, agentName, normalizedCost, 0)
+	qm.publishRemainingCost(quota)
 	quota.costHistory = append(quota.costHistory, CostEntry{
-		Timestamp: time.Now(),
-		Cost:      cost.TotalCost,
-		AgentName: agentName,
-		Currency:  cost.Currency,
+		Timestamp:        time.Now(),
+		Cost:             normalizedCost,
+		AgentName:        agentName,
+		Currency:         qm.currency,
+		OriginalCost:     cost.TotalCost,
+		OriginalCurrency: cost.Currency,
+		Unconverted:      !converted,
+		InputTokens:      cost.InputTokens,
+		OutputTokens:     cost.OutputTokens,
 	})
 
 	qm.log.Info("Synthesis cost recorded",
 		"namespace", namespace,
 		"agent", agentName,
-		"cost", cost.TotalCost,
-		"currency", cost.Currency,
+		"cost", normalizedCost,
+		"currency", qm.currency,
+		"originalCost", cost.TotalCost,
+		"originalCurrency", cost.Currency,
 		"dailyTotal", quota.dailyCost,
 		"limit", qm.maxCostPerNamespacePerDay)
 
@@ -205,6 +430,23 @@ func (qm *QuotaManager) RecordAttempt(ctx context.Context, namespace, agentName
 	qm.mu.Lock()
 	defer qm.mu.Unlock()
 
+	qm.recordAttempt(namespace, agentName, success, errorMsg, false)
+}
+
+// CompleteAgentAttempt records the outcome of an attempt reserved by ReserveAgentAttempt and
+// releases its reservation in one step, so the attempt is never counted both as recorded and as
+// in progress
+func (qm *QuotaManager) CompleteAgentAttempt(ctx context.Context, namespace, agentName string, success bool, errorMsg string) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+
+	qm.recordAttempt(namespace, agentName, success, errorMsg, true)
+}
+
+// recordAttempt counts an attempt against the namespace and agent, releasing its reservation
+// when reserved is set
+// Must be called with qm.mu locked
+func (qm *QuotaManager) recordAttempt(namespace, agentName string, success bool, errorMsg string, reserved bool) {
 	// Get or create quota tracker
 	quota, exists := qm.namespaceQuotas[namespace]
 	if !exists {
@@ -220,6 +462,10 @@ func (qm *QuotaManager) RecordAttempt(ctx context.Context, namespace, agentName
 
 	// Record the attempt
 	quota.dailyAttempts++
+	if reserved && quota.pendingAttempts > 0 {
+		quota.pendingAttempts--
+	}
+	qm.recordAgentAttempt(namespace, agentName, reserved)
 	quota.attemptHistory = append(quota.attemptHistory, AttemptEntry{
 		Timestamp: time.Now(),
 		AgentName: agentName,
@@ -357,10 +603,285 @@ func filterAttemptHistory(history []AttemptEntry, cutoff time.Time) []AttemptEnt
 	return filtered
 }
 
+// GetCostHistory returns a copy of the recorded cost entries for a namespace
+func (qm *QuotaManager) GetCostHistory(namespace string) []CostEntry {
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+
+	quota, exists := qm.namespaceQuotas[namespace]
+	if !exists {
+		return nil
+	}
+
+	quota.mu.RLock()
+	defer quota.mu.RUnlock()
+
+	history := make([]CostEntry, len(quota.costHistory))
+	copy(history, quota.costHistory)
+	return history
+}
+
+// AgentUsage summarizes an agent's recorded synthesis usage over a period
+type AgentUsage struct {
+	// Cost is the total cost in the quota currency
+	Cost     float64
+	Currency string
+
+	InputTokens  int64
+	OutputTokens int64
+
+	Attempts           int
+	SuccessfulAttempts int
+	FailedAttempts     int
+}
+
+// GetAgentUsage sums the cost and attempts recorded for an agent from since up to, but not
+// including, until. Only the last 7 days of history are kept, so older periods come back short.
+func (qm *QuotaManager) GetAgentUsage(namespace, agentName string, since, until time.Time) AgentUsage {
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+
+	usage := AgentUsage{Currency: qm.currency}
+	quota, exists := qm.namespaceQuotas[namespace]
+	if !exists {
+		return usage
+	}
+
+	quota.mu.RLock()
+	defer quota.mu.RUnlock()
+
+	inPeriod := func(timestamp time.Time) bool {
+		return !timestamp.Before(since) && timestamp.Before(until)
+	}
+	for _, entry := range quota.costHistory {
+		if entry.AgentName != agentName || !inPeriod(entry.Timestamp) {
+			continue
+		}
+		usage.Cost += entry.Cost
+		usage.InputTokens += entry.InputTokens
+		usage.OutputTokens += entry.OutputTokens
+	}
+	for _, entry := range quota.attemptHistory {
+		if entry.AgentName != agentName || !inPeriod(entry.Timestamp) {
+			continue
+		}
+		usage.Attempts++
+		if entry.Success {
+			usage.SuccessfulAttempts++
+		} else {
+			usage.FailedAttempts++
+		}
+	}
+	return usage
+}
+
 // Reset clears all quota state (useful for testing)
 func (qm *QuotaManager) Reset() {
 	qm.mu.Lock()
 	defer qm.mu.Unlock()
 
 	qm.namespaceQuotas = make(map[string]*NamespaceQuota)
+	qm.agentQuotas = make(map[string]*NamespaceQuota)
+}
+
+// ForgetAgent drops the daily usage tracked for an agent, once the agent is deleted. Its
+// synthesis stays counted against the namespace quota.
+func (qm *QuotaManager) ForgetAgent(namespace, agentName string) {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+
+	delete(qm.agentQuotas, agentQuotaKey(namespace, agentName))
+}
+
+// agentQuotaKey returns the agentQuotas key of an agent
+func agentQuotaKey(namespace, agentName string) string {
+	return namespace + "/" + agentName
+}
+
+// getAgentQuota returns the usage tracker of an agent, creating it if needed
+// Must be called with qm.mu locked
+func (qm *QuotaManager) getAgentQuota(namespace, agentName string) *NamespaceQuota {
+	key := agentQuotaKey(namespace, agentName)
+	quota, exists := qm.agentQuotas[key]
+	if !exists {
+		quota = NewNamespaceQuota(namespace)
+		qm.agentQuotas[key] = quota
+	}
+	return quota
+}
+
+// recordAgentUsage adds cost and attempts to an agent's daily usage. Only counters are kept per
+// agent; the namespace tracker holds the history.
+// Must be called with qm.mu locked
+func (qm *QuotaManager) recordAgentUsage(namespace, agentName string, cost float64, attempts int) {
+	if agentName == "" {
+		return
+	}
+	quota := qm.getAgentQuota(namespace, agentName)
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	quota.resetIfNeeded()
+	quota.dailyCost += cost
+	quota.dailyAttempts += attempts
+}
+
+// recordAgentAttempt adds an attempt to an agent's daily usage, releasing its reservation when
+// reserved is set
+// Must be called with qm.mu locked
+func (qm *QuotaManager) recordAgentAttempt(namespace, agentName string, reserved bool) {
+	if agentName == "" {
+		return
+	}
+	quota := qm.getAgentQuota(namespace, agentName)
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	quota.resetIfNeeded()
+	quota.dailyAttempts++
+	if reserved && quota.pendingAttempts > 0 {
+		quota.pendingAttempts--
+	}
+}
+
+// ReserveAgentAttempt reserves an attempt against both the namespace quota and the agent's own
+// attempt limit, so the stricter of the two applies. A successful reservation is completed with
+// CompleteAgentAttempt once the attempt ran, or released with ReleaseAgentAttempt if it didn't.
+func (qm *QuotaManager) ReserveAgentAttempt(ctx context.Context, namespace, agentName string, limits AgentQuota) error {
+	if err := qm.ReserveAttempt(ctx, namespace); err != nil {
+		return err
+	}
+	if err := qm.reserveAgentAttempt(namespace, agentName, limits); err != nil {
+		// Give back the namespace reservation taken above
+		qm.ReleaseAttempt(namespace)
+		return err
+	}
+	return nil
+}
+
+// reserveAgentAttempt reserves an attempt against the agent's own attempt limit
+func (qm *QuotaManager) reserveAgentAttempt(namespace, agentName string, limits AgentQuota) error {
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+
+	quota := qm.getAgentQuota(namespace, agentName)
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	// Reset daily counters if needed
+	quota.resetIfNeeded()
+
+	if limits.MaxAttemptsPerDay > 0 && quota.dailyAttempts+quota.pendingAttempts >= limits.MaxAttemptsPerDay {
+		qm.log.Info("Agent attempt quota exceeded",
+			"namespace", namespace,
+			"agent", agentName,
+			"attempts", quota.dailyAttempts,
+			"pending", quota.pendingAttempts,
+			"limit", limits.MaxAttemptsPerDay)
+
+		return fmt.Errorf("synthesis attempt quota exceeded for agent %s/%s: %d attempts today and %d in progress, limit is %d (resets at %s)",
+			namespace,
+			agentName,
+			quota.dailyAttempts,
+			quota.pendingAttempts,
+			limits.MaxAttemptsPerDay,
+			quota.attemptsResetAt.Format(time.RFC3339))
+	}
+
+	quota.pendingAttempts++
+	return nil
+}
+
+// ReleaseAgentAttempt releases an attempt reserved by ReserveAgentAttempt without recording it
+func (qm *QuotaManager) ReleaseAgentAttempt(namespace, agentName string) {
+	qm.ReleaseAttempt(namespace)
+
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+
+	quota, exists := qm.agentQuotas[agentQuotaKey(namespace, agentName)]
+	if !exists {
+		return
+	}
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	if quota.pendingAttempts > 0 {
+		quota.pendingAttempts--
+	}
+}
+
+// CheckAgentCostQuota checks if synthesis would exceed either the namespace cost quota or the
+// agent's own daily cost limit
+func (qm *QuotaManager) CheckAgentCostQuota(ctx context.Context, namespace, agentName string, limits AgentQuota, estimatedCost float64) error {
+	if err := qm.CheckCostQuota(ctx, namespace, estimatedCost); err != nil {
+		return err
+	}
+	if limits.MaxCostPerDay <= 0 {
+		return nil
+	}
+
+	qm.mu.Lock()
+	defer qm.mu.Unlock()
+
+	quota := qm.getAgentQuota(namespace, agentName)
+
+	quota.mu.Lock()
+	defer quota.mu.Unlock()
+
+	// Reset daily counters if needed
+	quota.resetIfNeeded()
+
+	projectedCost := quota.dailyCost + estimatedCost
+	if projectedCost > limits.MaxCostPerDay {
+		qm.log.Info("Agent cost quota would be exceeded",
+			"namespace", namespace,
+			"agent", agentName,
+			"currentCost", quota.dailyCost,
+			"estimatedCost", estimatedCost,
+			"projectedCost", projectedCost,
+			"limit", limits.MaxCostPerDay,
+			"currency", qm.currency)
+
+		return fmt.Errorf("synthesis cost quota exceeded for agent %s/%s: current %.4f + estimated %.4f = %.4f > limit %.4f %s (resets at %s)",
+			namespace,
+			agentName,
+			quota.dailyCost,
+			estimatedCost,
+			projectedCost,
+			limits.MaxCostPerDay,
+			qm.currency,
+			quota.dailyResetAt.Format(time.RFC3339))
+	}
+
+	return nil
+}
+
+// GetRemainingAgentQuota returns the budget an agent has left: the lesser of the namespace's
+// remaining quota and what the agent's own limits leave
+func (qm *QuotaManager) GetRemainingAgentQuota(namespace, agentName string, limits AgentQuota) (remainingCost float64, remainingAttempts int) {
+	remainingCost, remainingAttempts = qm.GetRemainingQuota(namespace)
+
+	qm.mu.RLock()
+	defer qm.mu.RUnlock()
+
+	var dailyCost float64
+	var dailyAttempts int
+	if quota, exists := qm.agentQuotas[agentQuotaKey(namespace, agentName)]; exists {
+		dailyCost, dailyAttempts = qm.getQuotaValuesAfterReset(quota)
+	}
+
+	// The package's min helper shadows the builtin and only takes ints
+	if limits.MaxCostPerDay > 0 {
+		remainingCost = math.Min(remainingCost, math.Max(limits.MaxCostPerDay-dailyCost, 0))
+	}
+	if limits.MaxAttemptsPerDay > 0 {
+		remainingAttempts = min(remainingAttempts, max(limits.MaxAttemptsPerDay-dailyAttempts, 0))
+	}
+
+	return remainingCost, remainingAttempts
 }
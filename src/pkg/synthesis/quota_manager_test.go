@@ -201,6 +201,118 @@ func TestMixedReadWriteOperations(t *testing.T) {
 	}
 }
 
+// TestConcurrentAccountingIsExact hammers RecordCost, RecordAttempt and CheckAttemptQuota from many
+// goroutines and checks that no cost or attempt is lost or double counted
+func TestConcurrentAccountingIsExact(t *testing.T) {
+	const (
+		numGoroutines = 40
+		numOperations = 50
+		namespace     = "test-namespace"
+		// A power of two keeps the float sum exact regardless of ordering
+		costPerSynthesis = 0.25
+	)
+	total := numGoroutines * numOperations
+	qm := NewQuotaManager(float64(total), total, "USD", testr.New(t))
+
+	var wg sync.WaitGroup
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < numOperations; j++ {
+				// The check may race with other recordings but must never refuse within the limit
+				if err := qm.CheckAttemptQuota(context.Background(), namespace); err != nil {
+					t.Errorf("CheckAttemptQuota refused an attempt within the limit: %v", err)
+				}
+				qm.RecordAttempt(context.Background(), namespace, "test-agent", true, "")
+				if err := qm.RecordCost(context.Background(), namespace, "test-agent", &SynthesisCost{TotalCost: costPerSynthesis, Currency: "USD"}); err != nil {
+					t.Errorf("RecordCost failed: %v", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	dailyCost, dailyAttempts, exists := qm.GetNamespaceStats(namespace)
+	if !exists {
+		t.Fatal("Quota should exist after operations")
+	}
+	if dailyAttempts != total {
+		t.Errorf("Expected %d attempts, got %d", total, dailyAttempts)
+	}
+	if expected := float64(total) * costPerSynthesis; dailyCost != expected {
+		t.Errorf("Expected daily cost %f, got %f", expected, dailyCost)
+	}
+	if history := qm.GetCostHistory(namespace); len(history) != total {
+		t.Errorf("Expected %d cost entries, got %d", total, len(history))
+	}
+
+	if err := qm.CheckAttemptQuota(context.Background(), namespace); err == nil {
+		t.Error("Expected the attempt quota to be exhausted")
+	}
+}
+
+// TestReserveAttemptNeverExceedsQuota checks that concurrent reservations admit exactly as many
+// attempts as the quota allows, even before any of them is recorded
+func TestReserveAttemptNeverExceedsQuota(t *testing.T) {
+	const (
+		limit         = 10
+		numGoroutines = 50
+		namespace     = "test-namespace"
+	)
+	qm := NewQuotaManager(100.0, limit, "USD", testr.New(t))
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		admitted int
+	)
+	for i := 0; i < numGoroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := qm.ReserveAttempt(context.Background(), namespace); err != nil {
+				return
+			}
+			mu.Lock()
+			admitted++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+
+	if admitted != limit {
+		t.Fatalf("Expected %d reservations to be admitted, got %d", limit, admitted)
+	}
+	if err := qm.CheckAttemptQuota(context.Background(), namespace); err == nil {
+		t.Error("Expected in-flight reservations to count against the attempt quota")
+	}
+
+	// Recording and releasing every reservation keeps the quota exhausted for the day
+	for i := 0; i < admitted; i++ {
+		qm.RecordAttempt(context.Background(), namespace, "test-agent", true, "")
+		qm.ReleaseAttempt(namespace)
+	}
+	if _, attempts, _ := qm.GetNamespaceStats(namespace); attempts != limit {
+		t.Errorf("Expected %d recorded attempts, got %d", limit, attempts)
+	}
+	if err := qm.ReserveAttempt(context.Background(), namespace); err == nil {
+		t.Error("Expected the attempt quota to be exhausted after recording")
+	}
+
+	// Releasing a reservation that was never recorded frees its slot
+	qm.Reset()
+	for i := 0; i < limit; i++ {
+		if err := qm.ReserveAttempt(context.Background(), namespace); err != nil {
+			t.Fatalf("ReserveAttempt() error = %v", err)
+		}
+	}
+	qm.ReleaseAttempt(namespace)
+	if err := qm.ReserveAttempt(context.Background(), namespace); err != nil {
+		t.Errorf("Expected a released reservation to free its slot: %v", err)
+	}
+}
+
 // Helper function for floating point comparison
 func abs(x float64) float64 {
 	if x < 0 {
@@ -254,6 +366,147 @@ func TestQuotaManagerBasicFunctionality(t *testing.T) {
 	}
 }
 
+// TestAgentQuotaEnforcedIndependently tests that an agent's own quota is enforced while its
+// namespace still has headroom, and that the stricter of the two wins
+func TestAgentQuotaEnforcedIndependently(t *testing.T) {
+	qm := NewQuotaManager(10.0, 10, "USD", testr.New(t))
+	ctx := context.Background()
+	namespace := "test-namespace"
+	limits := AgentQuota{MaxCostPerDay: 1.0, MaxAttemptsPerDay: 2}
+
+	// The agent uses up its own attempts
+	for i := 0; i < limits.MaxAttemptsPerDay; i++ {
+		if err := qm.ReserveAgentAttempt(ctx, namespace, "limited-agent", limits); err != nil {
+			t.Fatalf("ReserveAgentAttempt() error = %v", err)
+		}
+		qm.RecordAttempt(ctx, namespace, "limited-agent", true, "")
+		qm.ReleaseAgentAttempt(namespace, "limited-agent")
+	}
+	if err := qm.ReserveAgentAttempt(ctx, namespace, "limited-agent", limits); err == nil {
+		t.Error("Expected the agent attempt quota to be exhausted")
+	}
+	// The refused reservation doesn't hold on to a namespace attempt
+	if _, remainingAttempts := qm.GetRemainingQuota(namespace); remainingAttempts != 8 {
+		t.Errorf("Expected 8 remaining namespace attempts, got %d", remainingAttempts)
+	}
+	// Other agents in the namespace are unaffected
+	if err := qm.ReserveAgentAttempt(ctx, namespace, "other-agent", AgentQuota{}); err != nil {
+		t.Errorf("Expected another agent to have namespace headroom: %v", err)
+	}
+	qm.ReleaseAgentAttempt(namespace, "other-agent")
+
+	// The agent's cost limit is stricter than the namespace's remaining budget
+	qm.RecordCost(ctx, namespace, "limited-agent", &SynthesisCost{TotalCost: 0.8, Currency: "USD"})
+	if err := qm.CheckAgentCostQuota(ctx, namespace, "limited-agent", limits, 0.5); err == nil {
+		t.Error("Expected the agent cost quota to reject the estimate")
+	}
+	if err := qm.CheckCostQuota(ctx, namespace, 0.5); err != nil {
+		t.Errorf("Expected the namespace to have headroom: %v", err)
+	}
+	remainingCost, remainingAttempts := qm.GetRemainingAgentQuota(namespace, "limited-agent", limits)
+	if abs(remainingCost-0.2) > 1e-9 || remainingAttempts != 0 {
+		t.Errorf("Expected 0.2 remaining cost and 0 attempts for the agent, got cost=%f, attempts=%d", remainingCost, remainingAttempts)
+	}
+
+	// The namespace quota is stricter than a generous agent limit
+	qm.RecordCost(ctx, namespace, "other-agent", &SynthesisCost{TotalCost: 9.0, Currency: "USD"})
+	generous := AgentQuota{MaxCostPerDay: 100.0, MaxAttemptsPerDay: 100}
+	if err := qm.CheckAgentCostQuota(ctx, namespace, "other-agent", generous, 0.5); err == nil {
+		t.Error("Expected the namespace cost quota to reject the estimate")
+	}
+	remainingCost, remainingAttempts = qm.GetRemainingAgentQuota(namespace, "other-agent", generous)
+	if abs(remainingCost-0.2) > 1e-9 || remainingAttempts != 8 {
+		t.Errorf("Expected the namespace's 0.2 remaining cost and 8 attempts, got cost=%f, attempts=%d", remainingCost, remainingAttempts)
+	}
+}
+
+// TestForgetAgent tests that a deleted agent's usage is dropped while the namespace keeps counting it
+func TestForgetAgent(t *testing.T) {
+	qm := NewQuotaManager(10.0, 10, "USD", testr.New(t))
+	ctx := context.Background()
+	limits := AgentQuota{MaxAttemptsPerDay: 1}
+
+	if err := qm.ReserveAgentAttempt(ctx, "default", "reviewer", limits); err != nil {
+		t.Fatalf("ReserveAgentAttempt() error = %v", err)
+	}
+	qm.CompleteAgentAttempt(ctx, "default", "reviewer", true, "")
+
+	qm.ForgetAgent("default", "reviewer")
+	qm.mu.RLock()
+	tracked := len(qm.agentQuotas)
+	qm.mu.RUnlock()
+	if tracked != 0 {
+		t.Errorf("Expected no agent usage to be tracked, got %d agents", tracked)
+	}
+	if _, remainingAttempts := qm.GetRemainingQuota("default"); remainingAttempts != 9 {
+		t.Errorf("Expected the namespace to keep counting the attempt, got %d remaining", remainingAttempts)
+	}
+}
+
+// TestCompleteAgentAttemptCountsOnce tests that a completed reservation counts as exactly one
+// attempt, for the namespace and for the agent
+func TestCompleteAgentAttemptCountsOnce(t *testing.T) {
+	qm := NewQuotaManager(10.0, 3, "USD", testr.New(t))
+	ctx := context.Background()
+	namespace := "test-namespace"
+	limits := AgentQuota{MaxAttemptsPerDay: 2}
+
+	for i := 0; i < limits.MaxAttemptsPerDay; i++ {
+		if err := qm.ReserveAgentAttempt(ctx, namespace, "limited-agent", limits); err != nil {
+			t.Fatalf("ReserveAgentAttempt() error = %v", err)
+		}
+		qm.CompleteAgentAttempt(ctx, namespace, "limited-agent", i == 0, "")
+	}
+	if _, attempts, _ := qm.GetNamespaceStats(namespace); attempts != 2 {
+		t.Errorf("Expected 2 recorded namespace attempts, got %d", attempts)
+	}
+	if _, remainingAttempts := qm.GetRemainingAgentQuota(namespace, "limited-agent", limits); remainingAttempts != 0 {
+		t.Errorf("Expected no remaining agent attempts, got %d", remainingAttempts)
+	}
+	usage := qm.GetAgentUsage(namespace, "limited-agent", time.Now().Add(-time.Hour), time.Now().Add(time.Hour))
+	if usage.Attempts != 2 || usage.SuccessfulAttempts != 1 || usage.FailedAttempts != 1 {
+		t.Errorf("Expected 2 attempts, 1 successful, got %+v", usage)
+	}
+
+	// Nothing is left in progress: the namespace's last attempt is still available
+	if err := qm.ReserveAgentAttempt(ctx, namespace, "other-agent", AgentQuota{}); err != nil {
+		t.Errorf("Expected completed attempts to release their reservations: %v", err)
+	}
+}
+
+func TestGetAgentUsage(t *testing.T) {
+	qm := NewQuotaManager(10.0, 10, "USD", testr.New(t))
+	ctx := context.Background()
+	namespace := "test-namespace"
+	since := time.Now()
+
+	qm.RecordCost(ctx, namespace, "agent", &SynthesisCost{TotalCost: 0.25, InputTokens: 1000, OutputTokens: 200, Currency: "USD"})
+	qm.RecordCost(ctx, namespace, "agent", &SynthesisCost{TotalCost: 0.5, InputTokens: 2000, OutputTokens: 400, Currency: "USD"})
+	qm.RecordCost(ctx, namespace, "other-agent", &SynthesisCost{TotalCost: 3.0, InputTokens: 9000, Currency: "USD"})
+	qm.RecordAttempt(ctx, namespace, "agent", true, "")
+	qm.RecordAttempt(ctx, namespace, "agent", false, "validation failed")
+	qm.RecordAttempt(ctx, namespace, "other-agent", true, "")
+
+	usage := qm.GetAgentUsage(namespace, "agent", since, time.Now().Add(time.Second))
+	if abs(usage.Cost-0.75) > 1e-9 || usage.Currency != "USD" {
+		t.Errorf("Expected a cost of 0.75 USD, got %f %s", usage.Cost, usage.Currency)
+	}
+	if usage.InputTokens != 3000 || usage.OutputTokens != 600 {
+		t.Errorf("Expected 3000 input and 600 output tokens, got %d and %d", usage.InputTokens, usage.OutputTokens)
+	}
+	if usage.Attempts != 2 || usage.SuccessfulAttempts != 1 || usage.FailedAttempts != 1 {
+		t.Errorf("Expected 2 attempts with 1 success and 1 failure, got %+v", usage)
+	}
+
+	// Usage outside the period isn't counted
+	if usage := qm.GetAgentUsage(namespace, "agent", since.Add(-time.Hour), since); usage.Cost != 0 || usage.Attempts != 0 {
+		t.Errorf("Expected no usage before the period, got %+v", usage)
+	}
+	if usage := qm.GetAgentUsage("other-namespace", "agent", since, time.Now().Add(time.Second)); usage.Attempts != 0 || usage.Currency != "USD" {
+		t.Errorf("Expected no usage in an unknown namespace, got %+v", usage)
+	}
+}
+
 // Benchmarks to ensure the race condition fix doesn't significantly impact performance
 
 // BenchmarkGetRemainingQuota measures performance of the main read operation
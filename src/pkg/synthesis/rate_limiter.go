@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus"
 )
 
 // RateLimiter implements token bucket algorithm for synthesis rate limiting
@@ -19,6 +20,9 @@ type RateLimiter struct {
 	// Global configuration
 	maxSynthesisPerNamespacePerHour int
 	log                             logr.Logger
+
+	// tokensAvailable receives each namespace's fill level after every check
+	tokensAvailable *prometheus.GaugeVec
 }
 
 // TokenBucket represents a token bucket for rate limiting
@@ -36,9 +40,24 @@ func NewRateLimiter(maxPerHour int, log logr.Logger) *RateLimiter {
 		namespaceTokens:                 make(map[string]*TokenBucket),
 		maxSynthesisPerNamespacePerHour: maxPerHour,
 		log:                             log,
+		tokensAvailable:                 SynthesisRateLimitTokensAvailable,
 	}
 }
 
+// SetTokensAvailableGauge replaces the gauge receiving each namespace's available tokens.
+// A nil gauge stops publishing.
+func (rl *RateLimiter) SetTokensAvailableGauge(gauge *prometheus.GaugeVec) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+
+	rl.tokensAvailable = gauge
+}
+
+// MaxPerHour returns the number of synthesis requests allowed per namespace per hour
+func (rl *RateLimiter) MaxPerHour() int {
+	return rl.maxSynthesisPerNamespacePerHour
+}
+
 // NewTokenBucket creates a new token bucket with given capacity and refill rate
 func NewTokenBucket(capacity, refillRate float64) *TokenBucket {
 	return &TokenBucket{
@@ -63,8 +82,12 @@ func (rl *RateLimiter) CheckAndConsume(ctx context.Context, namespace string) er
 		rl.namespaceTokens[namespace] = bucket
 	}
 
-	// Try to consume a token
-	if err := bucket.Consume(1.0); err != nil {
+	// Try to consume a token, publishing the fill level whatever the outcome
+	err := bucket.Consume(1.0)
+	if rl.tokensAvailable != nil {
+		rl.tokensAvailable.WithLabelValues(namespace).Set(bucket.tokens)
+	}
+	if err != nil {
 		rl.log.Info("Synthesis rate limit exceeded",
 			"namespace", namespace,
 			"limit", rl.maxSynthesisPerNamespacePerHour,
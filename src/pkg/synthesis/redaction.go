@@ -0,0 +1,293 @@
+package synthesis
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Redactor replaces strings matching configured patterns with placeholders before synthesis
+// input leaves the cluster, so sensitive values (internal URLs, account identifiers) are never
+// sent verbatim to an external LLM
+type Redactor struct {
+	patterns []*regexp.Regexp
+}
+
+// NewRedactor compiles the redaction patterns. Empty patterns are ignored.
+func NewRedactor(patterns []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, pattern := range patterns {
+		if strings.TrimSpace(pattern) == "" {
+			continue
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %q: %w", pattern, err)
+		}
+		r.patterns = append(r.patterns, re)
+	}
+	return r, nil
+}
+
+// Patterns returns the number of configured redaction patterns
+func (r *Redactor) Patterns() int {
+	return len(r.patterns)
+}
+
+// redactionPlaceholderPattern matches the placeholders allocated by a Redaction
+var redactionPlaceholderPattern = regexp.MustCompile(`^__REDACTED_[0-9]+__`)
+
+// Redaction maps the placeholders of one synthesis back to the values they replaced. The same
+// value always gets the same placeholder, so references in the generated code stay consistent.
+type Redaction struct {
+	redactor     *Redactor
+	placeholders map[string]string // original value -> placeholder
+	originals    map[string]string // placeholder -> original value
+}
+
+// NewRedaction starts a redaction for a single synthesis request
+func (r *Redactor) NewRedaction() *Redaction {
+	return &Redaction{
+		redactor:     r,
+		placeholders: map[string]string{},
+		originals:    map[string]string{},
+	}
+}
+
+// Redact replaces every match of the configured patterns in text with its placeholder
+func (d *Redaction) Redact(text string) string {
+	for _, re := range d.redactor.patterns {
+		text = re.ReplaceAllStringFunc(text, d.placeholder)
+	}
+	return text
+}
+
+// placeholder returns the placeholder for value, allocating one on first use
+func (d *Redaction) placeholder(value string) string {
+	if placeholder, ok := d.placeholders[value]; ok {
+		return placeholder
+	}
+	placeholder := fmt.Sprintf("__REDACTED_%d__", len(d.placeholders)+1)
+	d.placeholders[value] = placeholder
+	d.originals[placeholder] = value
+	return placeholder
+}
+
+// Restore puts the original values back in place of their placeholders
+func (d *Redaction) Restore(text string) string {
+	if len(d.originals) == 0 {
+		return text
+	}
+	pairs := make([]string, 0, len(d.originals)*2)
+	for placeholder, original := range d.originals {
+		pairs = append(pairs, placeholder, original)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Count returns the number of distinct values redacted
+func (d *Redaction) Count() int {
+	return len(d.originals)
+}
+
+// redactRequest redacts the free-form text of a synthesis request. Tool schemas and model names
+// come from cluster resources rather than instructions and are sent as-is.
+func (d *Redaction) redactRequest(req AgentSynthesisRequest) AgentSynthesisRequest {
+	req.Instructions = d.Redact(req.Instructions)
+	req.PersonaText = d.Redact(req.PersonaText)
+	req.LastKnownGoodCode = d.Redact(req.LastKnownGoodCode)
+	if req.ErrorContext != nil {
+		errorContext := *req.ErrorContext
+		errorContext.LastCrashLog = d.Redact(errorContext.LastCrashLog)
+		errorContext.ValidationErrors = make([]string, len(req.ErrorContext.ValidationErrors))
+		for i, validationErr := range req.ErrorContext.ValidationErrors {
+			errorContext.ValidationErrors[i] = d.Redact(validationErr)
+		}
+		req.ErrorContext = &errorContext
+	}
+	return req
+}
+
+// restoreResponse restores the original values in the generated code and records the redaction
+func (d *Redaction) restoreResponse(resp *AgentSynthesisResponse) {
+	if resp == nil {
+		return
+	}
+	resp.DSLCode = d.restoreCode(resp.DSLCode)
+	resp.Redactions = d.Count()
+}
+
+// restoreCode restores the original values in generated Ruby code, escaping each value for the
+// string literal its placeholder appears in. The code was validated with the placeholders in place,
+// so a quote, interpolation, or newline in a restored value must not change what the code does.
+// A placeholder outside a string literal is restored as a double-quoted string.
+func (d *Redaction) restoreCode(code string) string {
+	if len(d.originals) == 0 {
+		return code
+	}
+	var restored strings.Builder
+	var quote byte // quote of the string literal being scanned, 0 outside string literals
+	comment := false
+	for i := 0; i < len(code); {
+		if strings.HasPrefix(code[i:], "__REDACTED_") {
+			placeholder := redactionPlaceholderPattern.FindString(code[i:])
+			if original, ok := d.originals[placeholder]; ok {
+				restored.WriteString(rubyEscape(original, quote))
+				i += len(placeholder)
+				continue
+			}
+		}
+		c := code[i]
+		switch {
+		case quote != 0 && c == '\\' && i+1 < len(code):
+			restored.WriteString(code[i : i+2])
+			i += 2
+			continue
+		case quote != 0 && c == quote:
+			quote = 0
+		case comment && c == '\n':
+			comment = false
+		case quote == 0 && !comment && c == '#':
+			comment = true
+		case quote == 0 && !comment && (c == '"' || c == '\''):
+			quote = c
+		}
+		restored.WriteByte(c)
+		i++
+	}
+	return restored.String()
+}
+
+// rubyEscape escapes value for a Ruby string literal delimited by quote, or returns it as a
+// double-quoted string literal when quote is 0
+func rubyEscape(value string, quote byte) string {
+	switch quote {
+	case '\'':
+		return strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value)
+	case '"':
+		return strings.NewReplacer(`\`, `\\`, `"`, `\"`, `#`, `\#`, "\n", `\n`, "\r", `\r`).Replace(value)
+	default:
+		return `"` + rubyEscape(value, '"') + `"`
+	}
+}
+
+// RedactingSynthesizer redacts synthesis requests before passing them to the wrapped
+// synthesizer and restores the redacted values in the code it returns
+type RedactingSynthesizer struct {
+	Synthesizer AgentSynthesizer
+	Redactor    *Redactor
+}
+
+// SynthesizeAgent implements AgentSynthesizer
+func (s *RedactingSynthesizer) SynthesizeAgent(ctx context.Context, req AgentSynthesisRequest) (*AgentSynthesisResponse, error) {
+	redaction := s.Redactor.NewRedaction()
+	resp, err := s.Synthesizer.SynthesizeAgent(ctx, redaction.redactRequest(req))
+	redaction.restoreResponse(resp)
+	return resp, err
+}
+
+// SynthesizeAgentStream implements AgentSynthesizer, restoring the redacted values in the
+// response of the final update
+func (s *RedactingSynthesizer) SynthesizeAgentStream(ctx context.Context, req AgentSynthesisRequest) (<-chan SynthesisProgress, error) {
+	redaction := s.Redactor.NewRedaction()
+	inner, err := s.Synthesizer.SynthesizeAgentStream(ctx, redaction.redactRequest(req))
+	if err != nil {
+		return nil, err
+	}
+	return redaction.restoreStream(ctx, inner), nil
+}
+
+// restoreStream forwards the updates of inner, restoring the redacted values in the response
+// of the final update
+func (d *Redaction) restoreStream(ctx context.Context, inner <-chan SynthesisProgress) <-chan SynthesisProgress {
+	updates := make(chan SynthesisProgress, progressBufferSize)
+	go func() {
+		defer close(updates)
+		for update := range inner {
+			if update.Done {
+				d.restoreResponse(update.Response)
+				sendFinalProgress(ctx, updates, update.Response, update.Err)
+				continue
+			}
+			select {
+			case updates <- update:
+			default:
+			}
+		}
+	}()
+	return updates
+}
+
+// SynthesizeCandidates implements CandidateSynthesizer when the wrapped synthesizer does
+func (s *RedactingSynthesizer) SynthesizeCandidates(ctx context.Context, req AgentSynthesisRequest, count int, budget CandidateBudget) (*AgentSynthesisResponse, error) {
+	candidates, ok := s.Synthesizer.(CandidateSynthesizer)
+	if !ok {
+		return s.SynthesizeAgent(ctx, req)
+	}
+	redaction := s.Redactor.NewRedaction()
+	resp, err := candidates.SynthesizeCandidates(ctx, redaction.redactRequest(req), count, budget)
+	redaction.restoreResponse(resp)
+	return resp, err
+}
+
+// SynthesizeCandidatesStream implements CandidateSynthesizer when the wrapped synthesizer does
+func (s *RedactingSynthesizer) SynthesizeCandidatesStream(ctx context.Context, req AgentSynthesisRequest, count int, budget CandidateBudget) (<-chan SynthesisProgress, error) {
+	candidates, ok := s.Synthesizer.(CandidateSynthesizer)
+	if !ok {
+		return s.SynthesizeAgentStream(ctx, req)
+	}
+	redaction := s.Redactor.NewRedaction()
+	inner, err := candidates.SynthesizeCandidatesStream(ctx, redaction.redactRequest(req), count, budget)
+	if err != nil {
+		return nil, err
+	}
+	return redaction.restoreStream(ctx, inner), nil
+}
+
+// SynthesizeTasks implements TaskSynthesizer when the wrapped synthesizer does
+func (s *RedactingSynthesizer) SynthesizeTasks(ctx context.Context, req TaskSynthesisRequest) (*AgentSynthesisResponse, error) {
+	tasks, ok := s.Synthesizer.(TaskSynthesizer)
+	if !ok {
+		return nil, fmt.Errorf("synthesizer does not support task regeneration")
+	}
+	redaction := s.Redactor.NewRedaction()
+	req.AgentSynthesisRequest = redaction.redactRequest(req.AgentSynthesisRequest)
+	req.ExistingCode = redaction.Redact(req.ExistingCode)
+	changed := make([]string, len(req.ChangedSections))
+	for i, section := range req.ChangedSections {
+		changed[i] = redaction.Redact(section)
+	}
+	req.ChangedSections = changed
+	resp, err := tasks.SynthesizeTasks(ctx, req)
+	redaction.restoreResponse(resp)
+	return resp, err
+}
+
+// DistillPersona implements AgentSynthesizer. Persona text is redacted when it is used for
+// synthesis, so the distilled persona's placeholders are restored rather than kept.
+func (s *RedactingSynthesizer) DistillPersona(ctx context.Context, persona PersonaInfo, agentContext AgentContext) (string, error) {
+	redaction := s.Redactor.NewRedaction()
+	persona.Description = redaction.Redact(persona.Description)
+	persona.SystemPrompt = redaction.Redact(persona.SystemPrompt)
+	agentContext.Instructions = redaction.Redact(agentContext.Instructions)
+	distilled, err := s.Synthesizer.DistillPersona(ctx, persona, agentContext)
+	return redaction.Restore(distilled), err
+}
+
+// SynthesizeDocs implements DocsSynthesizer when the wrapped synthesizer does, restoring the
+// redacted values in the returned documentation
+func (s *RedactingSynthesizer) SynthesizeDocs(ctx context.Context, req DocsSynthesisRequest) (*DocsSynthesisResponse, error) {
+	docs, ok := s.Synthesizer.(DocsSynthesizer)
+	if !ok {
+		return nil, fmt.Errorf("synthesizer does not support documentation")
+	}
+	redaction := s.Redactor.NewRedaction()
+	req.AgentSynthesisRequest = redaction.redactRequest(req.AgentSynthesisRequest)
+	req.Code = redaction.Redact(req.Code)
+	generated, err := docs.SynthesizeDocs(ctx, req)
+	if generated != nil {
+		generated.Docs = redaction.Restore(generated.Docs)
+	}
+	return generated, err
+}
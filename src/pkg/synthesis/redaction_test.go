@@ -0,0 +1,286 @@
+package synthesis
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// recordingSynthesizer records the requests it receives and echoes every redaction placeholder
+// of the instructions into the generated code, like a model referencing the values it was given
+type recordingSynthesizer struct {
+	agentReq *AgentSynthesisRequest
+	taskReq  *TaskSynthesisRequest
+	docsReq  *DocsSynthesisRequest
+}
+
+var placeholderPattern = regexp.MustCompile(`__REDACTED_\d+__`)
+
+func (s *recordingSynthesizer) code(instructions string) string {
+	var calls []string
+	for _, placeholder := range placeholderPattern.FindAllString(instructions, -1) {
+		calls = append(calls, "    execute_tool('http', 'get', url: '"+placeholder+"')")
+	}
+	return "agent \"reporter\" do\n  main do |inputs|\n" + strings.Join(calls, "\n") + "\n  end\nend"
+}
+
+func (s *recordingSynthesizer) SynthesizeAgent(_ context.Context, req AgentSynthesisRequest) (*AgentSynthesisResponse, error) {
+	s.agentReq = &req
+	return &AgentSynthesisResponse{DSLCode: s.code(req.Instructions)}, nil
+}
+
+func (s *recordingSynthesizer) SynthesizeAgentStream(ctx context.Context, req AgentSynthesisRequest) (<-chan SynthesisProgress, error) {
+	updates := make(chan SynthesisProgress, 2)
+	resp, err := s.SynthesizeAgent(ctx, req)
+	updates <- SynthesisProgress{Phase: SynthesisPhaseGenerating, OutputTokens: EstimateTokens(resp.DSLCode)}
+	updates <- SynthesisProgress{Done: true, Response: resp, Err: err}
+	close(updates)
+	return updates, nil
+}
+
+func (s *recordingSynthesizer) SynthesizeTasks(_ context.Context, req TaskSynthesisRequest) (*AgentSynthesisResponse, error) {
+	s.taskReq = &req
+	return &AgentSynthesisResponse{DSLCode: s.code(req.Instructions)}, nil
+}
+
+func (s *recordingSynthesizer) DistillPersona(_ context.Context, persona PersonaInfo, _ AgentContext) (string, error) {
+	return "Answer questions about " + placeholderPattern.FindString(persona.SystemPrompt), nil
+}
+
+func newTestRedactor(t *testing.T) *Redactor {
+	redactor, err := NewRedactor([]string{`https://[a-z0-9.-]+\.internal\.acme\.corp[^\s'"]*`, `ACCT-\d{6}`, ""})
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+	return redactor
+}
+
+func TestNewRedactor_InvalidPattern(t *testing.T) {
+	if _, err := NewRedactor([]string{`ACCT-(\d+`}); err == nil {
+		t.Error("Expected an error for an invalid pattern")
+	}
+}
+
+func TestRedaction_ConsistentPlaceholders(t *testing.T) {
+	redaction := newTestRedactor(t).NewRedaction()
+
+	redacted := redaction.Redact("Bill ACCT-123456, then ACCT-654321, then ACCT-123456 again")
+	if redacted != "Bill __REDACTED_1__, then __REDACTED_2__, then __REDACTED_1__ again" {
+		t.Errorf("Unexpected redaction %q", redacted)
+	}
+	if redaction.Count() != 2 {
+		t.Errorf("Expected 2 redacted values, got %d", redaction.Count())
+	}
+	if restored := redaction.Restore(redacted); restored != "Bill ACCT-123456, then ACCT-654321, then ACCT-123456 again" {
+		t.Errorf("Unexpected restore %q", restored)
+	}
+}
+
+func TestRedaction_RestoreCodeEscapesValues(t *testing.T) {
+	redactor, err := NewRedactor([]string{`SECRET\[[^\]]*\]`})
+	if err != nil {
+		t.Fatalf("NewRedactor failed: %v", err)
+	}
+	tests := []struct {
+		name     string
+		value    string
+		code     string
+		expected string
+	}{
+		{
+			name:     "double-quoted string",
+			value:    `SECRET["#{system('id')}"]`,
+			code:     `url "%s"`,
+			expected: `url "SECRET[\"\#{system('id')}\"]"`,
+		},
+		{
+			name:     "single-quoted string",
+			value:    `SECRET[it's\]`,
+			code:     `url '%s'`,
+			expected: `url 'SECRET[it\'s\\]'`,
+		},
+		{
+			name:     "newline in a double-quoted string",
+			value:    "SECRET[a\nb]",
+			code:     `url "%s"`,
+			expected: `url "SECRET[a\nb]"`,
+		},
+		{
+			name:     "outside a string",
+			value:    `SECRET[x"]`,
+			code:     `url %s`,
+			expected: `url "SECRET[x\"]"`,
+		},
+		{
+			name:     "after an escaped quote",
+			value:    `SECRET['"]`,
+			code:     `say "a \"quote\" then %s"`,
+			expected: `say "a \"quote\" then SECRET['\"]"`,
+		},
+		{
+			name:     "quote in a comment",
+			value:    `SECRET[']`,
+			code:     "# it's\nurl '%s'",
+			expected: "# it's\nurl 'SECRET[\\']'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redaction := redactor.NewRedaction()
+			placeholder := redaction.Redact(tt.value)
+			restored := redaction.restoreCode(strings.Replace(tt.code, "%s", placeholder, 1))
+			if restored != tt.expected {
+				t.Errorf("Expected %s, got %s", tt.expected, restored)
+			}
+		})
+	}
+}
+
+func TestRedactingSynthesizer_SynthesizeAgent(t *testing.T) {
+	inner := &recordingSynthesizer{}
+	synthesizer := &RedactingSynthesizer{Synthesizer: inner, Redactor: newTestRedactor(t)}
+
+	req := AgentSynthesisRequest{
+		Instructions:      "Fetch https://billing.internal.acme.corp/v2/usage for account ACCT-004217 every hour",
+		PersonaText:       "You support account ACCT-004217",
+		LastKnownGoodCode: "execute_tool('http', 'get', url: 'https://billing.internal.acme.corp/v2/usage')",
+		ErrorContext:      &ErrorContext{ValidationErrors: []string{"unreachable: https://billing.internal.acme.corp/v2/usage"}},
+		AgentName:         "reporter",
+	}
+	resp, err := synthesizer.SynthesizeAgent(context.Background(), req)
+	if err != nil {
+		t.Fatalf("SynthesizeAgent failed: %v", err)
+	}
+
+	sent := inner.agentReq
+	for _, text := range []string{sent.Instructions, sent.PersonaText, sent.LastKnownGoodCode, sent.ErrorContext.ValidationErrors[0]} {
+		if strings.Contains(text, "internal.acme.corp") || strings.Contains(text, "ACCT-004217") {
+			t.Errorf("Expected sensitive values to be redacted from the synthesis request, got %q", text)
+		}
+	}
+	if sent.Instructions != "Fetch __REDACTED_1__ for account __REDACTED_2__ every hour" {
+		t.Errorf("Unexpected redacted instructions %q", sent.Instructions)
+	}
+	if !strings.Contains(req.ErrorContext.ValidationErrors[0], "internal.acme.corp") {
+		t.Error("Expected the caller's request to be left unmodified")
+	}
+
+	if strings.Contains(resp.DSLCode, "__REDACTED_") {
+		t.Errorf("Expected placeholders to be restored in the generated code, got:\n%s", resp.DSLCode)
+	}
+	if !strings.Contains(resp.DSLCode, "url: 'https://billing.internal.acme.corp/v2/usage'") ||
+		!strings.Contains(resp.DSLCode, "url: 'ACCT-004217'") {
+		t.Errorf("Expected the original values in the generated code, got:\n%s", resp.DSLCode)
+	}
+	if resp.Redactions != 2 {
+		t.Errorf("Expected 2 redactions to be recorded, got %d", resp.Redactions)
+	}
+}
+
+func TestRedactingSynthesizer_SynthesizeTasks(t *testing.T) {
+	inner := &recordingSynthesizer{}
+	synthesizer := &RedactingSynthesizer{Synthesizer: inner, Redactor: newTestRedactor(t)}
+
+	resp, err := synthesizer.SynthesizeTasks(context.Background(), TaskSynthesisRequest{
+		AgentSynthesisRequest: AgentSynthesisRequest{Instructions: "Report usage for ACCT-004217"},
+		ExistingCode:          "task :report do\n  execute_tool('billing', 'usage', account: 'ACCT-004217')\nend",
+		ChangedSections:       []string{"Report usage for ACCT-004217"},
+		Tasks:                 []string{"report"},
+	})
+	if err != nil {
+		t.Fatalf("SynthesizeTasks failed: %v", err)
+	}
+	if strings.Contains(inner.taskReq.ExistingCode, "ACCT-004217") || strings.Contains(inner.taskReq.ChangedSections[0], "ACCT-004217") {
+		t.Errorf("Expected existing code and changed sections to be redacted, got %+v", inner.taskReq)
+	}
+	if !strings.Contains(inner.taskReq.ExistingCode, "__REDACTED_1__") {
+		t.Errorf("Expected existing code to share the instructions' placeholder, got %q", inner.taskReq.ExistingCode)
+	}
+	if !strings.Contains(resp.DSLCode, "ACCT-004217") || resp.Redactions != 1 {
+		t.Errorf("Expected the account to be restored in the regenerated code, got %d redactions:\n%s", resp.Redactions, resp.DSLCode)
+	}
+}
+
+func TestRedactingSynthesizer_DistillPersona(t *testing.T) {
+	synthesizer := &RedactingSynthesizer{Synthesizer: &recordingSynthesizer{}, Redactor: newTestRedactor(t)}
+
+	distilled, err := synthesizer.DistillPersona(context.Background(), PersonaInfo{SystemPrompt: "You support ACCT-004217"}, AgentContext{})
+	if err != nil {
+		t.Fatalf("DistillPersona failed: %v", err)
+	}
+	if distilled != "Answer questions about ACCT-004217" {
+		t.Errorf("Expected the distilled persona to have its placeholders restored, got %q", distilled)
+	}
+}
+
+func TestRedactingSynthesizer_NoMatches(t *testing.T) {
+	inner := &recordingSynthesizer{}
+	synthesizer := &RedactingSynthesizer{Synthesizer: inner, Redactor: newTestRedactor(t)}
+
+	resp, err := synthesizer.SynthesizeAgent(context.Background(), AgentSynthesisRequest{Instructions: "Summarize the news"})
+	if err != nil {
+		t.Fatalf("SynthesizeAgent failed: %v", err)
+	}
+	if inner.agentReq.Instructions != "Summarize the news" || resp.Redactions != 0 {
+		t.Errorf("Expected instructions without sensitive values to be sent as-is, got %q with %d redactions",
+			inner.agentReq.Instructions, resp.Redactions)
+	}
+}
+
+func TestRedactingSynthesizer_SynthesizeAgentStream(t *testing.T) {
+	inner := &recordingSynthesizer{}
+	synthesizer := &RedactingSynthesizer{Synthesizer: inner, Redactor: newTestRedactor(t)}
+
+	updates, err := synthesizer.SynthesizeAgentStream(context.Background(), AgentSynthesisRequest{
+		Instructions: "Fetch https://billing.internal.acme.corp/v2/usage every hour",
+		AgentName:    "reporter",
+	})
+	if err != nil {
+		t.Fatalf("SynthesizeAgentStream failed: %v", err)
+	}
+
+	var final *SynthesisProgress
+	for update := range updates {
+		if update.Done {
+			final = &update
+		}
+	}
+	if strings.Contains(inner.agentReq.Instructions, "internal.acme.corp") {
+		t.Errorf("Expected the streamed request to be redacted, got %q", inner.agentReq.Instructions)
+	}
+	if final == nil || final.Err != nil || final.Response == nil {
+		t.Fatalf("Expected a successful final update, got %+v", final)
+	}
+	if !strings.Contains(final.Response.DSLCode, "https://billing.internal.acme.corp/v2/usage") {
+		t.Errorf("Expected the redacted URL to be restored in the code, got %q", final.Response.DSLCode)
+	}
+	if final.Response.Redactions != 1 {
+		t.Errorf("Expected 1 redacted value, got %d", final.Response.Redactions)
+	}
+}
+
+func (s *recordingSynthesizer) SynthesizeDocs(_ context.Context, req DocsSynthesisRequest) (*DocsSynthesisResponse, error) {
+	s.docsReq = &req
+	return &DocsSynthesisResponse{Docs: "Reports usage for " + placeholderPattern.FindString(req.Code)}, nil
+}
+
+func TestRedactingSynthesizer_SynthesizeDocs(t *testing.T) {
+	inner := &recordingSynthesizer{}
+	synthesizer := &RedactingSynthesizer{Synthesizer: inner, Redactor: newTestRedactor(t)}
+
+	docs, err := synthesizer.SynthesizeDocs(context.Background(), DocsSynthesisRequest{
+		AgentSynthesisRequest: AgentSynthesisRequest{Instructions: "Report usage for ACCT-004217"},
+		Code:                  "task :report do\n  execute_tool('billing', 'usage', account: 'ACCT-004217')\nend",
+	})
+	if err != nil {
+		t.Fatalf("SynthesizeDocs failed: %v", err)
+	}
+	if strings.Contains(inner.docsReq.Code, "ACCT-004217") || strings.Contains(inner.docsReq.Instructions, "ACCT-004217") {
+		t.Errorf("Expected the documented code and instructions to be redacted, got %+v", inner.docsReq)
+	}
+	if docs.Docs != "Reports usage for ACCT-004217" {
+		t.Errorf("Expected the documentation to have its placeholders restored, got %q", docs.Docs)
+	}
+}
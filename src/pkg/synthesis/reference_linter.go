@@ -0,0 +1,92 @@
+package synthesis
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ReasonReferencesMissingTool is the condition reason used when synthesized code calls an undeclared tool
+const ReasonReferencesMissingTool = "SynthesisReferencesMissingTool"
+
+// executeToolPattern matches the first argument of execute_tool calls, written as a string or symbol
+var executeToolPattern = regexp.MustCompile(`execute_tool\(\s*(?:'([^']+)'|"([^"]+)"|:([A-Za-z_][A-Za-z0-9_]*))`)
+
+// toolCallPattern extends executeToolPattern with an optional second argument naming the tool function,
+// as in execute_tool('github', 'get_pr_diff', ...)
+var toolCallPattern = regexp.MustCompile(`execute_tool\(\s*(?:'([^']+)'|"([^"]+)"|:([A-Za-z_][A-Za-z0-9_]*))` +
+	`(?:\s*,\s*(?:'([^']+)'|"([^"]+)"|:([A-Za-z_][A-Za-z0-9_]*)))?`)
+
+// ToolCall is a tool invocation found in DSL code
+type ToolCall struct {
+	// Name is the first argument: a LanguageTool name or the name of a tool function it exposes
+	Name string
+	// Method is the tool function named by the second argument, if any
+	Method string
+}
+
+// ToolCalls returns the distinct execute_tool calls in the DSL code, in order of first appearance
+func ToolCalls(code string) []ToolCall {
+	seen := make(map[ToolCall]struct{})
+	var calls []ToolCall
+	for _, match := range toolCallPattern.FindAllStringSubmatch(code, -1) {
+		call := ToolCall{Name: match[1] + match[2] + match[3], Method: match[4] + match[5] + match[6]}
+		if _, ok := seen[call]; ok {
+			continue
+		}
+		seen[call] = struct{}{}
+		calls = append(calls, call)
+	}
+	return calls
+}
+
+// MissingToolReferenceError is returned when synthesized code references tools the agent does not declare
+type MissingToolReferenceError struct {
+	Tools []string
+}
+
+// Error implements the error interface
+func (e *MissingToolReferenceError) Error() string {
+	return fmt.Sprintf("synthesized code references tools not available to the agent: %s", strings.Join(e.Tools, ", "))
+}
+
+// IsMissingToolReference reports whether err was caused by synthesized code referencing an undeclared tool
+func IsMissingToolReference(err error) bool {
+	var refErr *MissingToolReferenceError
+	return errors.As(err, &refErr)
+}
+
+// LintToolReferences cross-checks the tools called by the DSL code against the tools available to the agent.
+// A call is accepted if its first argument names a referenced LanguageTool or one of the tool schemas it exposes.
+// DSL v1 code does not address models by name, so model references are not checked here.
+func LintToolReferences(code string, tools []string, toolSchemaNames []string) error {
+	available := make(map[string]struct{}, len(tools)+len(toolSchemaNames))
+	for _, name := range tools {
+		available[name] = struct{}{}
+	}
+	for _, name := range toolSchemaNames {
+		available[name] = struct{}{}
+	}
+
+	missing := make(map[string]struct{})
+	for _, match := range executeToolPattern.FindAllStringSubmatch(code, -1) {
+		name := match[1] + match[2] + match[3]
+		if _, ok := available[name]; !ok {
+			missing[name] = struct{}{}
+		}
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return &MissingToolReferenceError{Tools: names}
+}
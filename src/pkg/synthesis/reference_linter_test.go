@@ -0,0 +1,114 @@
+package synthesis
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestLintToolReferences(t *testing.T) {
+	tests := []struct {
+		name            string
+		code            string
+		tools           []string
+		toolSchemaNames []string
+		expectMissing   []string
+	}{
+		{
+			name: "declared tool server is accepted",
+			code: `task :fetch_pr_diff do |inputs|
+  { diff: execute_tool('github', 'get_pr_diff', pr_number: inputs[:pr_number]) }
+end`,
+			tools: []string{"github"},
+		},
+		{
+			name: "tool schema name is accepted",
+			code: `task :read do |inputs|
+  execute_tool("read_file", { path: inputs[:file_path] })
+end`,
+			tools:           []string{"workspace"},
+			toolSchemaNames: []string{"read_file", "write_file"},
+		},
+		{
+			name:  "code without tool calls is accepted",
+			code:  `task(:summarize, instructions: "summarize", inputs: {}, outputs: { summary: 'string' })`,
+			tools: nil,
+		},
+		{
+			name: "undeclared tool is rejected",
+			code: `task :notify do |inputs|
+  execute_tool('slack', 'post_message', text: inputs[:text])
+  execute_tool('github', 'get_pr_diff', pr_number: 1)
+end`,
+			tools:         []string{"github"},
+			expectMissing: []string{"slack"},
+		},
+		{
+			name: "symbol tool reference without declared tools is rejected",
+			code: `task :search do |inputs|
+  execute_tool(:web_search, query: inputs[:query])
+  execute_tool('email', 'send', to: 'a@example.com')
+end`,
+			expectMissing: []string{"email", "web_search"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := LintToolReferences(tt.code, tt.tools, tt.toolSchemaNames)
+
+			if len(tt.expectMissing) == 0 {
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			refErr, ok := err.(*MissingToolReferenceError)
+			if !ok {
+				t.Fatalf("Expected MissingToolReferenceError, got %v", err)
+			}
+			if fmt.Sprint(refErr.Tools) != fmt.Sprint(tt.expectMissing) {
+				t.Errorf("Expected missing tools %v, got %v", tt.expectMissing, refErr.Tools)
+			}
+		})
+	}
+}
+
+func TestIsMissingToolReference(t *testing.T) {
+	err := LintToolReferences(`execute_tool('slack', 'post', text: 'hi')`, nil, nil)
+	if err == nil {
+		t.Fatal("Expected undeclared tool to be rejected")
+	}
+
+	wrapped := fmt.Errorf("synthesis failed: %w", err)
+	if !IsMissingToolReference(wrapped) {
+		t.Error("Expected wrapped error to be detected as a missing tool reference")
+	}
+	if IsMissingToolReference(fmt.Errorf("some other failure")) {
+		t.Error("Expected unrelated error not to be detected as a missing tool reference")
+	}
+}
+
+func TestToolCalls(t *testing.T) {
+	code := `task :review do |inputs|
+  diff = execute_tool('github', 'get_pr_diff', pr_number: inputs[:pr_number])
+  again = execute_tool("github", "get_pr_diff", pr_number: 2)
+  files = execute_tool(:github, :list_files, pr_number: 2)
+  content = execute_tool('read_file', { path: inputs[:file_path] })
+  execute_tool(:web_search, query: inputs[:query])
+end`
+
+	expected := []ToolCall{
+		{Name: "github", Method: "get_pr_diff"},
+		{Name: "github", Method: "list_files"},
+		{Name: "read_file"},
+		{Name: "web_search"},
+	}
+	if got := ToolCalls(code); fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Expected tool calls %v, got %v", expected, got)
+	}
+
+	if got := ToolCalls(`task(:summarize, instructions: "summarize")`); len(got) != 0 {
+		t.Errorf("Expected no tool calls, got %v", got)
+	}
+}
@@ -15,6 +15,8 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+
+	"github.com/language-operator/language-operator/pkg/validation"
 )
 
 // DSLSchema represents the JSON Schema for the Agent DSL
@@ -238,13 +240,6 @@ func ValidateGeneratedCodeAgainstSchema(ctx context.Context, code string) ([]Sch
 		return nil, nil
 	}
 
-	// Default timeout if none specified in context
-	if _, ok := ctx.Deadline(); !ok {
-		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, 5*time.Second)
-		defer cancel()
-	}
-
 	// Find and validate the validator script
 	scriptPath, err := findSchemaValidatorScript()
 	if err != nil {
@@ -262,18 +257,14 @@ func ValidateGeneratedCodeAgainstSchema(ctx context.Context, code string) ([]Sch
 		return nil, fmt.Errorf("security validation failed: %w", err)
 	}
 
-	// Execute Ruby validator script via bundle exec
-	cmd := exec.CommandContext(ctx, "bundle", "exec", "ruby", scriptPath)
-	cmd.Stdin = strings.NewReader(code)
-
-	// Capture STDOUT and STDERR separately
+	// Execute Ruby validator script via bundle exec, bounded by the configured validator limits
 	// STDOUT contains JSON violations
 	// STDERR may contain Ruby warnings
-	output, err := cmd.Output()
+	output, err := validation.RunValidator(ctx, 5*time.Second, code, "bundle", args...)
 
-	// Check for timeout
-	if ctx.Err() == context.DeadlineExceeded {
-		return nil, fmt.Errorf("schema validation timeout: code too large or complex (>5s)")
+	// A validator over its time or memory budget fails validation rather than hanging
+	if validation.IsLimitExceeded(err) {
+		return nil, fmt.Errorf("schema validation timeout: %w", err)
 	}
 
 	// Parse JSON output from validator (STDOUT only)
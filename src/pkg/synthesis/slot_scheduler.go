@@ -0,0 +1,222 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// Fairness keys for queuing synthesis slot requests
+const (
+	// FairnessByNamespace shares synthesis slots evenly between namespaces (tenants)
+	FairnessByNamespace = "namespace"
+	// FairnessByAgent shares synthesis slots evenly between agents
+	FairnessByAgent = "agent"
+)
+
+// DefaultMaxSlotWait bounds how long Acquire waits for a synthesis slot, so queued synthesis
+// requests release their reconcile worker instead of stalling every other reconcile
+const DefaultMaxSlotWait = 10 * time.Second
+
+// ErrNoSynthesisSlot reports that no synthesis slot freed up within the scheduler's maximum wait
+var ErrNoSynthesisSlot = errors.New("no synthesis slot free")
+
+// IsNoSynthesisSlot reports whether err is a synthesis request that gave up waiting for a slot
+func IsNoSynthesisSlot(err error) bool {
+	return errors.Is(err, ErrNoSynthesisSlot)
+}
+
+var (
+	// SynthesisSlots tracks synthesis requests holding or waiting for a synthesis slot
+	SynthesisSlots = prometheus.NewGaugeVec(
+		prometheus.GaugeOpts{
+			Name: "synthesis_slots",
+			Help: "Number of synthesis requests by state (queued or in_flight)",
+		},
+		[]string{"state"},
+	)
+
+	// SynthesisSlotWaitSeconds tracks how long synthesis requests wait for a slot
+	SynthesisSlotWaitSeconds = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "synthesis_slot_wait_seconds",
+			Help:    "Time synthesis requests waited for a synthesis slot by namespace",
+			Buckets: prometheus.ExponentialBuckets(0.1, 2, 12), // 0.1s to ~7m
+		},
+		[]string{"namespace"},
+	)
+)
+
+func init() {
+	metrics.Registry.MustRegister(SynthesisSlots, SynthesisSlotWaitSeconds)
+}
+
+// slotWaiter is a synthesis request queued for a slot
+type slotWaiter struct {
+	granted chan struct{}
+}
+
+// SlotScheduler bounds the number of concurrent synthesis requests across all agents. When every
+// slot is busy, requests queue per fairness key and freed slots are handed to the keys in
+// round-robin order, so an agent or namespace that retries rapidly can't starve the others.
+type SlotScheduler struct {
+	slots    int
+	fairness string
+	// maxWait is how long Acquire waits for a slot before giving up
+	maxWait time.Duration
+
+	mu     sync.Mutex
+	inUse  int
+	queues map[string][]*slotWaiter
+	// order holds the keys with queued requests; the key at the front is served next
+	order []string
+}
+
+// NewSlotScheduler creates a scheduler allowing maxConcurrent synthesis requests at once, queued by
+// the given fairness key. Zero or less means unlimited.
+func NewSlotScheduler(maxConcurrent int, fairness string) (*SlotScheduler, error) {
+	switch fairness {
+	case FairnessByNamespace, FairnessByAgent:
+	default:
+		return nil, fmt.Errorf("unknown synthesis fairness key %q, expected %q or %q", fairness, FairnessByNamespace, FairnessByAgent)
+	}
+	return &SlotScheduler{
+		slots:    maxConcurrent,
+		fairness: fairness,
+		maxWait:  DefaultMaxSlotWait,
+		queues:   make(map[string][]*slotWaiter),
+	}, nil
+}
+
+// Slots returns the maximum number of concurrent synthesis requests (zero or less is unlimited)
+// and the fairness key queued requests are shared by
+func (s *SlotScheduler) Slots() (maxConcurrent int, fairness string) {
+	return s.slots, s.fairness
+}
+
+// Acquire blocks until a synthesis slot is free for the agent or ctx is done. It gives up with
+// ErrNoSynthesisSlot after DefaultMaxSlotWait, so the caller can retry later instead of holding a
+// reconcile worker. Every successful Acquire must be paired with Release. A nil scheduler never
+// blocks.
+func (s *SlotScheduler) Acquire(ctx context.Context, namespace, agent string) error {
+	if s == nil || s.slots <= 0 {
+		return nil
+	}
+
+	start := time.Now()
+	key := namespace
+	if s.fairness == FairnessByAgent {
+		key = namespace + "/" + agent
+	}
+
+	s.mu.Lock()
+	if s.inUse < s.slots && len(s.order) == 0 {
+		s.inUse++
+		s.mu.Unlock()
+		s.granted(namespace, start)
+		return nil
+	}
+
+	waiter := &slotWaiter{granted: make(chan struct{})}
+	if len(s.queues[key]) == 0 {
+		s.order = append(s.order, key)
+	}
+	s.queues[key] = append(s.queues[key], waiter)
+	s.mu.Unlock()
+
+	SynthesisSlots.WithLabelValues("queued").Inc()
+	defer SynthesisSlots.WithLabelValues("queued").Dec()
+
+	timer := time.NewTimer(s.maxWait)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-waiter.granted:
+		s.granted(namespace, start)
+		return nil
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timer.C:
+		err = ErrNoSynthesisSlot
+	}
+
+	s.mu.Lock()
+	dequeued := s.dequeue(key, waiter)
+	s.mu.Unlock()
+	if !dequeued {
+		// The slot was handed over while the waiter was giving up, so pass it on
+		SynthesisSlots.WithLabelValues("in_flight").Inc()
+		s.Release()
+	}
+	return err
+}
+
+// Release frees the slot held by a completed synthesis request, handing it to the next fairness
+// key in round-robin order
+func (s *SlotScheduler) Release() {
+	if s == nil || s.slots <= 0 {
+		return
+	}
+
+	SynthesisSlots.WithLabelValues("in_flight").Dec()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.order) == 0 {
+		s.inUse--
+		return
+	}
+
+	key := s.order[0]
+	s.order = s.order[1:]
+	queue := s.queues[key]
+	next := queue[0]
+	if len(queue) > 1 {
+		s.queues[key] = queue[1:]
+		s.order = append(s.order, key)
+	} else {
+		delete(s.queues, key)
+	}
+
+	// The slot passes directly to the waiter, so inUse is unchanged
+	close(next.granted)
+}
+
+// granted records a slot being granted after waiting since start
+func (s *SlotScheduler) granted(namespace string, start time.Time) {
+	SynthesisSlots.WithLabelValues("in_flight").Inc()
+	SynthesisSlotWaitSeconds.WithLabelValues(namespace).Observe(time.Since(start).Seconds())
+}
+
+// dequeue removes a waiter that gave up. Returns false if the waiter was already granted a slot.
+func (s *SlotScheduler) dequeue(key string, waiter *slotWaiter) bool {
+	queue := s.queues[key]
+	for i, queued := range queue {
+		if queued != waiter {
+			continue
+		}
+
+		queue = append(queue[:i], queue[i+1:]...)
+		if len(queue) > 0 {
+			s.queues[key] = queue
+			return true
+		}
+
+		delete(s.queues, key)
+		for j, queuedKey := range s.order {
+			if queuedKey == key {
+				s.order = append(s.order[:j], s.order[j+1:]...)
+				break
+			}
+		}
+		return true
+	}
+	return false
+}
@@ -0,0 +1,202 @@
+package synthesis
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// queuedRequests returns the number of requests waiting for a slot
+func (s *SlotScheduler) queuedRequests() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	total := 0
+	for _, queue := range s.queues {
+		total += len(queue)
+	}
+	return total
+}
+
+// waitForQueued blocks until n requests are waiting for a slot
+func waitForQueued(t *testing.T, s *SlotScheduler, n int) {
+	t.Helper()
+	deadline := time.Now().Add(5 * time.Second)
+	for s.queuedRequests() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("Timed out waiting for %d queued requests, have %d", n, s.queuedRequests())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestSlotScheduler_RoundRobinAcrossNamespaces(t *testing.T) {
+	scheduler, err := NewSlotScheduler(1, FairnessByNamespace)
+	if err != nil {
+		t.Fatalf("NewSlotScheduler failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := scheduler.Acquire(ctx, "busy", "holder"); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	enqueue := func(namespace, agent string) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := scheduler.Acquire(ctx, namespace, agent); err != nil {
+				t.Errorf("Acquire failed: %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, namespace)
+			mu.Unlock()
+			scheduler.Release()
+		}()
+	}
+
+	// A rapidly retrying namespace queues first, then two others queue behind it
+	queued := 0
+	for _, namespace := range []string{"busy", "busy", "busy", "busy", "quiet", "other"} {
+		enqueue(namespace, "agent")
+		queued++
+		waitForQueued(t, scheduler, queued)
+	}
+
+	scheduler.Release()
+	wg.Wait()
+
+	expected := []string{"busy", "quiet", "other", "busy", "busy", "busy"}
+	if len(order) != len(expected) {
+		t.Fatalf("Expected %d grants, got %v", len(expected), order)
+	}
+	for i := range expected {
+		if order[i] != expected[i] {
+			t.Fatalf("Expected grant order %v, got %v", expected, order)
+		}
+	}
+}
+
+func TestSlotScheduler_FairnessByAgent(t *testing.T) {
+	scheduler, err := NewSlotScheduler(1, FairnessByAgent)
+	if err != nil {
+		t.Fatalf("NewSlotScheduler failed: %v", err)
+	}
+
+	ctx := context.Background()
+	if err := scheduler.Acquire(ctx, "default", "holder"); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	var mu sync.Mutex
+	var order []string
+	var wg sync.WaitGroup
+	queued := 0
+	for _, agent := range []string{"noisy", "noisy", "noisy", "polite"} {
+		agent := agent
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := scheduler.Acquire(ctx, "default", agent); err != nil {
+				t.Errorf("Acquire failed: %v", err)
+				return
+			}
+			mu.Lock()
+			order = append(order, agent)
+			mu.Unlock()
+			scheduler.Release()
+		}()
+		queued++
+		waitForQueued(t, scheduler, queued)
+	}
+
+	scheduler.Release()
+	wg.Wait()
+
+	// Agents in the same namespace are still served in turn
+	if len(order) != 4 || order[1] != "polite" {
+		t.Errorf("Expected polite agent to be served second, got %v", order)
+	}
+}
+
+func TestSlotScheduler_CancelledWaiterLeavesQueue(t *testing.T) {
+	scheduler, err := NewSlotScheduler(1, FairnessByNamespace)
+	if err != nil {
+		t.Fatalf("NewSlotScheduler failed: %v", err)
+	}
+
+	if err := scheduler.Acquire(context.Background(), "default", "holder"); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := scheduler.Acquire(ctx, "default", "impatient"); err == nil {
+		t.Fatal("Expected Acquire to fail when the context expires")
+	}
+	if queued := scheduler.queuedRequests(); queued != 0 {
+		t.Errorf("Expected cancelled request to leave the queue, %d still queued", queued)
+	}
+
+	// The slot is free again once the holder releases it
+	scheduler.Release()
+	if err := scheduler.Acquire(context.Background(), "default", "next"); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+	scheduler.Release()
+}
+
+func TestSlotScheduler_GivesUpAfterMaxWait(t *testing.T) {
+	scheduler, err := NewSlotScheduler(1, FairnessByNamespace)
+	if err != nil {
+		t.Fatalf("NewSlotScheduler failed: %v", err)
+	}
+	scheduler.maxWait = 20 * time.Millisecond
+
+	if err := scheduler.Acquire(context.Background(), "default", "holder"); err != nil {
+		t.Fatalf("Acquire failed: %v", err)
+	}
+
+	// A busy scheduler releases the caller instead of blocking until the holder is done
+	err = scheduler.Acquire(context.Background(), "default", "queued")
+	if !IsNoSynthesisSlot(err) {
+		t.Fatalf("Expected ErrNoSynthesisSlot, got %v", err)
+	}
+	if queued := scheduler.queuedRequests(); queued != 0 {
+		t.Errorf("Expected the request that gave up to leave the queue, %d still queued", queued)
+	}
+
+	scheduler.Release()
+	if err := scheduler.Acquire(context.Background(), "default", "retried"); err != nil {
+		t.Fatalf("Expected the retried request to get the freed slot, got %v", err)
+	}
+	scheduler.Release()
+}
+
+func TestSlotScheduler_Unlimited(t *testing.T) {
+	var nilScheduler *SlotScheduler
+	if err := nilScheduler.Acquire(context.Background(), "default", "agent"); err != nil {
+		t.Errorf("Expected nil scheduler never to block, got %v", err)
+	}
+	nilScheduler.Release()
+
+	scheduler, err := NewSlotScheduler(0, FairnessByNamespace)
+	if err != nil {
+		t.Fatalf("NewSlotScheduler failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		if err := scheduler.Acquire(context.Background(), "default", "agent"); err != nil {
+			t.Fatalf("Expected unlimited scheduler never to block, got %v", err)
+		}
+	}
+}
+
+func TestNewSlotScheduler_RejectsUnknownFairnessKey(t *testing.T) {
+	if _, err := NewSlotScheduler(2, "pod"); err == nil {
+		t.Error("Expected error for unknown fairness key")
+	}
+}
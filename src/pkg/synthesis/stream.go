@@ -0,0 +1,124 @@
+package synthesis
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+)
+
+// Synthesis phases reported by SynthesizeAgentStream
+const (
+	// SynthesisPhaseValidating covers checking the request and building the prompt
+	SynthesisPhaseValidating = "validating"
+	// SynthesisPhaseGenerating covers the model generating code
+	SynthesisPhaseGenerating = "generating"
+	// SynthesisPhaseValidatingOutput covers validating and linting the generated code
+	SynthesisPhaseValidatingOutput = "validating-output"
+)
+
+// progressBufferSize is how many progress updates are buffered for a slow consumer before
+// intermediate updates are dropped
+const progressBufferSize = 16
+
+// SynthesisProgress is a progress update of a streaming synthesis. The last update on the
+// channel has Done set and carries the result.
+type SynthesisProgress struct {
+	// Phase is the synthesis phase the update was reported in
+	Phase string
+	// OutputTokens is the estimated number of tokens generated so far
+	OutputTokens int64
+
+	// Done marks the final update
+	Done bool
+	// Response is the synthesis response, set on the final update
+	Response *AgentSynthesisResponse
+	// Err is the synthesis error, set on the final update
+	Err error
+}
+
+// StreamingChatModel is a ChatModel that can stream its output (eino)
+type StreamingChatModel interface {
+	ChatModel
+	Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error)
+}
+
+// SynthesizeAgentStream synthesizes agent code like SynthesizeAgent while reporting phase and
+// token count updates as they happen. The channel is closed after the final update. Token
+// counts are only reported when the chat model supports streaming.
+func (s *Synthesizer) SynthesizeAgentStream(ctx context.Context, req AgentSynthesisRequest) (<-chan SynthesisProgress, error) {
+	return streamProgress(ctx, func(progress func(SynthesisProgress)) (*AgentSynthesisResponse, error) {
+		return s.synthesizeAgent(ctx, req, progress)
+	})
+}
+
+// streamProgress runs synthesize in the background, delivering its progress updates and then
+// its result on the returned channel, which is closed after the final update
+func streamProgress(ctx context.Context, synthesize func(progress func(SynthesisProgress)) (*AgentSynthesisResponse, error)) (<-chan SynthesisProgress, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	updates := make(chan SynthesisProgress, progressBufferSize)
+	go func() {
+		defer close(updates)
+		resp, err := synthesize(func(update SynthesisProgress) {
+			// Never stall generation on a slow consumer; the next update supersedes this one
+			select {
+			case updates <- update:
+			default:
+			}
+		})
+		sendFinalProgress(ctx, updates, resp, err)
+	}()
+	return updates, nil
+}
+
+// sendFinalProgress delivers the final update, giving up only when ctx is done
+func sendFinalProgress(ctx context.Context, updates chan<- SynthesisProgress, resp *AgentSynthesisResponse, err error) {
+	select {
+	case updates <- SynthesisProgress{Done: true, Response: resp, Err: err}:
+	case <-ctx.Done():
+	}
+}
+
+// reportProgress calls progress when progress reporting is enabled
+func reportProgress(progress func(SynthesisProgress), update SynthesisProgress) {
+	if progress != nil {
+		progress(update)
+	}
+}
+
+// generate calls the chat model, streaming the output and reporting the tokens generated so
+// far when progress is requested and the model supports streaming
+func (s *Synthesizer) generate(ctx context.Context, messages []*schema.Message, progress func(SynthesisProgress)) (*schema.Message, error) {
+	reportProgress(progress, SynthesisProgress{Phase: SynthesisPhaseGenerating})
+	streaming, ok := s.chatModel.(StreamingChatModel)
+	if progress == nil || !ok {
+		return s.chatModel.Generate(ctx, messages)
+	}
+
+	reader, err := streaming.Stream(ctx, messages)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	var content strings.Builder
+	for {
+		chunk, err := reader.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		content.WriteString(chunk.Content)
+		// The estimate only depends on the length, so the content isn't copied on every chunk
+		progress(SynthesisProgress{Phase: SynthesisPhaseGenerating, OutputTokens: estimateTokensFromLength(content.Len())})
+	}
+	return &schema.Message{Role: schema.Assistant, Content: content.String()}, nil
+}
@@ -0,0 +1,112 @@
+package synthesis
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/cloudwego/eino/components/model"
+	"github.com/cloudwego/eino/schema"
+	"github.com/go-logr/logr"
+)
+
+// chunkedChatModel streams its response in fixed chunks
+type chunkedChatModel struct {
+	chunks []string
+}
+
+func (m *chunkedChatModel) Generate(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.Message, error) {
+	return &schema.Message{Role: schema.Assistant, Content: strings.Join(m.chunks, "")}, nil
+}
+
+func (m *chunkedChatModel) Stream(ctx context.Context, input []*schema.Message, opts ...model.Option) (*schema.StreamReader[*schema.Message], error) {
+	messages := make([]*schema.Message, len(m.chunks))
+	for i, chunk := range m.chunks {
+		messages[i] = &schema.Message{Role: schema.Assistant, Content: chunk}
+	}
+	return schema.StreamReaderFromArray(messages), nil
+}
+
+// collectProgress reads every update of a streaming synthesis
+func collectProgress(t *testing.T, synthesizer AgentSynthesizer) []SynthesisProgress {
+	t.Helper()
+	updates, err := synthesizer.SynthesizeAgentStream(context.Background(), newCandidateRequest())
+	if err != nil {
+		t.Fatalf("SynthesizeAgentStream failed: %v", err)
+	}
+	var progress []SynthesisProgress
+	for update := range updates {
+		progress = append(progress, update)
+	}
+	return progress
+}
+
+func TestSynthesizeAgentStream_ReportsPhasesAndTokens(t *testing.T) {
+	lines := strings.SplitAfter(verboseCandidate, "\n")
+	synthesizer := NewSynthesizer(&chunkedChatModel{chunks: lines}, logr.Discard())
+
+	progress := collectProgress(t, synthesizer)
+
+	var phases []string
+	var lastTokens int64
+	for _, update := range progress[:len(progress)-1] {
+		if len(phases) == 0 || phases[len(phases)-1] != update.Phase {
+			phases = append(phases, update.Phase)
+		}
+		if update.Phase == SynthesisPhaseGenerating {
+			if update.OutputTokens < lastTokens {
+				t.Errorf("Expected token counts to grow, got %d after %d", update.OutputTokens, lastTokens)
+			}
+			lastTokens = update.OutputTokens
+		}
+	}
+	expected := []string{SynthesisPhaseValidating, SynthesisPhaseGenerating, SynthesisPhaseValidatingOutput}
+	if strings.Join(phases, ",") != strings.Join(expected, ",") {
+		t.Errorf("Expected phases %v, got %v", expected, phases)
+	}
+	if lastTokens != EstimateTokens(verboseCandidate) {
+		t.Errorf("Expected %d generated tokens, got %d", EstimateTokens(verboseCandidate), lastTokens)
+	}
+
+	final := progress[len(progress)-1]
+	if !final.Done || final.Err != nil || final.Response == nil {
+		t.Fatalf("Expected a successful final update, got %+v", final)
+	}
+	if !strings.Contains(final.Response.DSLCode, `agent "researcher"`) {
+		t.Errorf("Expected the streamed code in the response, got %q", final.Response.DSLCode)
+	}
+}
+
+func TestSynthesizeAgentStream_NonStreamingModel(t *testing.T) {
+	synthesizer := NewSynthesizer(&sequenceChatModel{responses: []string{verboseCandidate}}, logr.Discard())
+
+	progress := collectProgress(t, synthesizer)
+
+	final := progress[len(progress)-1]
+	if !final.Done || final.Err != nil || final.Response == nil {
+		t.Fatalf("Expected a successful final update, got %+v", final)
+	}
+	reportedGenerating := false
+	for _, update := range progress {
+		if update.Phase == SynthesisPhaseGenerating {
+			reportedGenerating = true
+		}
+	}
+	if !reportedGenerating {
+		t.Error("Expected the generating phase to be reported without streaming")
+	}
+}
+
+func TestSynthesizeAgentStream_ReportsFailure(t *testing.T) {
+	synthesizer := NewSynthesizer(&chunkedChatModel{chunks: []string{missingToolCandidate}}, logr.Discard())
+
+	progress := collectProgress(t, synthesizer)
+
+	final := progress[len(progress)-1]
+	if !final.Done || final.Err == nil {
+		t.Fatalf("Expected the final update to carry the lint error, got %+v", final)
+	}
+	if final.Response == nil || len(final.Response.ValidationErrors) == 0 {
+		t.Errorf("Expected validation errors in the final response, got %+v", final.Response)
+	}
+}
@@ -61,6 +61,7 @@ func (t TemporalIntent) String() string {
 // AgentSynthesizer is the interface for synthesizing agent code
 type AgentSynthesizer interface {
 	SynthesizeAgent(ctx context.Context, req AgentSynthesisRequest) (*AgentSynthesisResponse, error)
+	SynthesizeAgentStream(ctx context.Context, req AgentSynthesisRequest) (<-chan SynthesisProgress, error)
 	DistillPersona(ctx context.Context, persona PersonaInfo, agentContext AgentContext) (string, error)
 }
 
@@ -75,7 +76,14 @@ type Synthesizer struct {
 	log           logr.Logger
 	costTracker   *CostTracker
 	modelName     string
+	provider      string // Provider API the chat model calls
 	schemaVersion string // DSL schema version for telemetry tracking
+
+	// contextWindow is the model's context window in tokens, with outputTokens reserved for
+	// the response; zero disables prompt trimming
+	contextWindow int64
+	outputTokens  int64
+	tokenizer     Tokenizer
 }
 
 // AgentSynthesisRequest contains all information needed to synthesize an agent
@@ -88,6 +96,12 @@ type AgentSynthesisRequest struct {
 	AgentName    string
 	Namespace    string
 
+	// PersonaConstraints are enforced on the synthesized code when set
+	PersonaConstraints *langopv1alpha1.PersonaConstraints
+
+	// Examples are curated few-shot examples from the agent's synthesis example set
+	Examples []SynthesisExample
+
 	// Self-Healing Context (NEW)
 	ErrorContext      *ErrorContext `json:"errorContext,omitempty"`
 	IsRetry           bool          `json:"isRetry"`
@@ -101,7 +115,11 @@ type AgentSynthesisResponse struct {
 	Error            string
 	DurationSeconds  float64
 	ValidationErrors []string
-	Cost             *SynthesisCost // Cost tracking for this synthesis
+	Cost             *SynthesisCost      // Cost tracking for this synthesis
+	Candidates       *CandidateSelection // Set when the code was selected among several candidates
+	Redactions       int                 // Number of distinct values redacted from the request
+	Provider         string              // Provider API the code was synthesized with
+	PromptTrimmed    []string            // Prompt sections trimmed to fit the model's context window
 }
 
 // PersonaInfo contains persona details for distillation
@@ -139,8 +157,15 @@ type RuntimeError struct {
 	SynthesisAttempt  int32    `json:"synthesisAttempt"`
 }
 
-// NewSynthesizerFromLanguageModel creates a synthesizer from a LanguageModel CRD
-func NewSynthesizerFromLanguageModel(ctx context.Context, k8sClient client.Client, model *langopv1alpha1.LanguageModel, log logr.Logger) (*Synthesizer, error) {
+// NewSynthesizerFromLanguageModel creates a synthesizer from a LanguageModel CRD. requestTimeout
+// bounds each model request; zero uses the model's spec.timeout.
+func NewSynthesizerFromLanguageModel(ctx context.Context, k8sClient client.Client, model *langopv1alpha1.LanguageModel, requestTimeout time.Duration, log logr.Logger) (*Synthesizer, error) {
+	return NewSynthesizerWithParameters(ctx, k8sClient, model, requestTimeout, ResolveGenerationParameters(model, nil), log)
+}
+
+// NewSynthesizerWithParameters creates a synthesizer from a LanguageModel CRD that generates with
+// params instead of the model's own configuration
+func NewSynthesizerWithParameters(ctx context.Context, k8sClient client.Client, model *langopv1alpha1.LanguageModel, requestTimeout time.Duration, params GenerationParameters, log logr.Logger) (*Synthesizer, error) {
 	// Get API key from secret
 	apiKey := ""
 	if model.Spec.APIKeySecretRef != nil {
@@ -167,10 +192,100 @@ func NewSynthesizerFromLanguageModel(ctx context.Context, k8sClient client.Clien
 		}
 	}
 
-	// Build eino OpenAI ChatModel config
+	chatModel, err := newChatModel(ctx, model, apiKey, synthesisRequestTimeout(model, requestTimeout), params)
+	if err != nil {
+		return nil, err
+	}
+
+	synth := NewSynthesizer(chatModel, log)
+	synth.modelName = model.Spec.ModelName
+	synth.provider = synthesisProvider(model)
+	synth.contextWindow = ModelContextWindow(model)
+	synth.outputTokens = params.OutputTokens()
+	synth.tokenizer = defaultTokenizer(model.Spec.Provider)
+
+	// Set up cost tracking if enabled in the model
+	costTracker := NewCostTracker(model)
+	synth.SetCostTracker(costTracker, model.Spec.ModelName)
+
+	return synth, nil
+}
+
+// defaultSynthesisTemperature is the synthesis temperature of models without a configuration
+const defaultSynthesisTemperature = 0.3
+
+// GenerationParameters are the sampling parameters of synthesis requests. Parameters left nil
+// aren't sent, so the provider's own defaults apply.
+type GenerationParameters struct {
+	Temperature *float64
+	MaxTokens   *int32
+}
+
+// OutputTokens returns how many tokens a synthesis response can use: MaxTokens, or the
+// synthesizer's default when it isn't set
+func (p GenerationParameters) OutputTokens() int64 {
+	if p.MaxTokens != nil {
+		return int64(*p.MaxTokens)
+	}
+	return defaultSynthesisOutputTokens
+}
+
+// ResolveGenerationParameters returns the parameters for synthesizing with model: the agent's
+// spec.synthesis overrides, then the model's configuration, or the synthesis defaults for models
+// without one. The temperature is capped at the most the model's provider accepts.
+func ResolveGenerationParameters(model *langopv1alpha1.LanguageModel, overrides *langopv1alpha1.AgentSynthesisSettings) GenerationParameters {
+	var params GenerationParameters
+	if config := model.Spec.Configuration; config != nil {
+		params.Temperature = config.Temperature
+		params.MaxTokens = config.MaxTokens
+	} else {
+		temperature := defaultSynthesisTemperature
+		maxTokens := int32(defaultSynthesisOutputTokens)
+		params.Temperature = &temperature
+		params.MaxTokens = &maxTokens
+	}
+	if overrides != nil {
+		if overrides.Temperature != nil {
+			params.Temperature = overrides.Temperature
+		}
+		if overrides.MaxTokens != nil {
+			params.MaxTokens = overrides.MaxTokens
+		}
+	}
+
+	// Cluster fallback models may come from a provider with a lower limit than the agent's own
+	if limit := langopv1alpha1.MaxModelTemperature(model.Spec.Provider); params.Temperature != nil && *params.Temperature > limit {
+		params.Temperature = &limit
+	}
+	return params
+}
+
+// newChatModel creates the chat model for a LanguageModel: the native Anthropic API for
+// anthropic models synthesized with it, the OpenAI chat completions API for every other model
+func newChatModel(ctx context.Context, model *langopv1alpha1.LanguageModel, apiKey string, timeout time.Duration, params GenerationParameters) (ChatModel, error) {
+	var temperature *float32
+	if params.Temperature != nil {
+		value := float32(*params.Temperature)
+		temperature = &value
+	}
+
+	if synthesisProvider(model) == ProviderAnthropic {
+		// The Messages API requires max_tokens
+		return NewAnthropicChatModel(model.Spec.Endpoint, apiKey, model.Spec.ModelName, int(params.OutputTokens()), temperature, timeout), nil
+	}
+
+	var maxTokens *int
+	if params.MaxTokens != nil {
+		value := int(*params.MaxTokens)
+		maxTokens = &value
+	}
+
 	config := &openai.ChatModelConfig{
-		Model:  model.Spec.ModelName,
-		APIKey: apiKey,
+		Model:       model.Spec.ModelName,
+		APIKey:      apiKey,
+		Timeout:     timeout,
+		Temperature: temperature,
+		MaxTokens:   maxTokens,
 	}
 
 	// Set endpoint for openai-compatible providers
@@ -183,38 +298,23 @@ func NewSynthesizerFromLanguageModel(ctx context.Context, k8sClient client.Clien
 		config.BaseURL = endpoint
 	}
 
-	// Apply configuration options
-	if model.Spec.Configuration != nil {
-		if model.Spec.Configuration.Temperature != nil {
-			temp := float32(*model.Spec.Configuration.Temperature)
-			config.Temperature = &temp
-		}
-		if model.Spec.Configuration.MaxTokens != nil {
-			maxTokens := int(*model.Spec.Configuration.MaxTokens)
-			config.MaxTokens = &maxTokens
-		}
-	} else {
-		// Default settings for synthesis
-		temp := float32(0.3)
-		config.Temperature = &temp
-		maxTokens := 8192
-		config.MaxTokens = &maxTokens
-	}
-
-	// Create ChatModel
 	chatModel, err := openai.NewChatModel(ctx, config)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create ChatModel: %w", err)
 	}
+	return chatModel, nil
+}
 
-	synth := NewSynthesizer(chatModel, log)
-	synth.modelName = model.Spec.ModelName
-
-	// Set up cost tracking if enabled in the model
-	costTracker := NewCostTracker(model)
-	synth.SetCostTracker(costTracker, model.Spec.ModelName)
-
-	return synth, nil
+// synthesisRequestTimeout returns requestTimeout if set, otherwise the model's spec.timeout.
+// Zero leaves model requests unbounded.
+func synthesisRequestTimeout(model *langopv1alpha1.LanguageModel, requestTimeout time.Duration) time.Duration {
+	if requestTimeout > 0 {
+		return requestTimeout
+	}
+	if timeout, err := time.ParseDuration(model.Spec.Timeout); err == nil && timeout > 0 {
+		return timeout
+	}
+	return 0
 }
 
 // NewSynthesizer creates a new synthesizer instance using eino ChatModel
@@ -252,6 +352,33 @@ func (s *Synthesizer) SetCostTracker(tracker *CostTracker, modelName string) {
 
 // SynthesizeAgent generates Ruby DSL code from natural language instructions
 func (s *Synthesizer) SynthesizeAgent(ctx context.Context, req AgentSynthesisRequest) (*AgentSynthesisResponse, error) {
+	return s.synthesizeAgent(ctx, req, nil)
+}
+
+// synthesizeAgent synthesizes agent code, reporting progress when progress is non-nil. The
+// request is trimmed first so its prompt fits the model's context window.
+func (s *Synthesizer) synthesizeAgent(ctx context.Context, req AgentSynthesisRequest, progress func(SynthesisProgress)) (*AgentSynthesisResponse, error) {
+	req, trimmed, err := s.fitContextWindow(req)
+	if len(trimmed) > 0 {
+		s.log.Info("Trimmed synthesis prompt to fit the model's context window",
+			"agent", req.AgentName,
+			"contextWindow", s.contextWindow,
+			"trimmed", trimmed)
+	}
+	if err != nil {
+		return &AgentSynthesisResponse{Error: err.Error(), PromptTrimmed: trimmed}, err
+	}
+
+	resp, err := s.generateAgent(ctx, req, progress)
+	if resp != nil {
+		resp.PromptTrimmed = trimmed
+		resp.Provider = s.provider
+	}
+	return resp, err
+}
+
+// generateAgent generates and validates agent code for a request that fits the model's context window
+func (s *Synthesizer) generateAgent(ctx context.Context, req AgentSynthesisRequest, progress func(SynthesisProgress)) (*AgentSynthesisResponse, error) {
 	// Start synthesis span
 	ctx, span := tracer.Start(ctx, "synthesis.agent.generate")
 	defer span.End()
@@ -282,6 +409,7 @@ func (s *Synthesizer) SynthesizeAgent(ctx context.Context, req AgentSynthesisReq
 		"models", len(req.Models))
 
 	// Build the synthesis prompt
+	reportProgress(progress, SynthesisProgress{Phase: SynthesisPhaseValidating})
 	prompt := s.buildSynthesisPrompt(req)
 
 	// Call LLM using eino ChatModel
@@ -293,8 +421,9 @@ func (s *Synthesizer) SynthesizeAgent(ctx context.Context, req AgentSynthesisReq
 	}
 
 	// Call the chat model (returns *schema.Message, not *schema.ChatCompletionResponse)
-	responseMsg, err := s.chatModel.Generate(ctx, messages)
+	responseMsg, err := s.generate(ctx, messages, progress)
 	if err != nil {
+		err = classifyModelError(err)
 		duration := time.Since(startTime).Seconds()
 		// Record error in span
 		span.RecordError(err)
@@ -334,6 +463,7 @@ func (s *Synthesizer) SynthesizeAgent(ctx context.Context, req AgentSynthesisReq
 	}
 
 	// Extract code from markdown blocks if present
+	reportProgress(progress, SynthesisProgress{Phase: SynthesisPhaseValidatingOutput, OutputTokens: EstimateTokens(dslCode)})
 	dslCode = extractCodeFromMarkdown(dslCode)
 
 	// Validate against DSL schema first
@@ -381,7 +511,7 @@ func (s *Synthesizer) SynthesizeAgent(ctx context.Context, req AgentSynthesisReq
 			DurationSeconds:  duration,
 			ValidationErrors: validationErrors,
 			Cost:             synthesisCost,
-		}, fmt.Errorf("schema validation failed with %d violations", len(schemaViolations))
+		}, validationFailed(fmt.Errorf("schema validation failed with %d violations", len(schemaViolations)))
 	} else {
 		// Schema validation passed - add telemetry event
 		span.AddEvent("schema_validation_passed", trace.WithAttributes(
@@ -401,7 +531,49 @@ func (s *Synthesizer) SynthesizeAgent(ctx context.Context, req AgentSynthesisReq
 			Error:            fmt.Sprintf("Validation failed: %v", err),
 			DurationSeconds:  duration,
 			ValidationErrors: validationErrors,
-		}, err
+		}, validationFailed(err)
+	}
+
+	// Semantic lint: every tool the code calls must be available to the agent
+	toolSchemaNames := make([]string, 0, len(req.ToolSchemas))
+	for _, toolSchema := range req.ToolSchemas {
+		toolSchemaNames = append(toolSchemaNames, toolSchema.Name)
+	}
+	if err := LintToolReferences(dslCode, req.Tools, toolSchemaNames); err != nil {
+		validationErrors = append(validationErrors, err.Error())
+		duration := time.Since(startTime).Seconds()
+		span.SetAttributes(attribute.String("validation.error_type", "missing_tool_reference"))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Synthesized code references missing tool")
+		s.log.Info("Synthesized code references unavailable tools",
+			"agent", req.AgentName,
+			"error", err.Error())
+		return &AgentSynthesisResponse{
+			DSLCode:          dslCode,
+			Error:            err.Error(),
+			DurationSeconds:  duration,
+			ValidationErrors: validationErrors,
+			Cost:             synthesisCost,
+		}, validationFailed(err)
+	}
+
+	// Persona lint: the code must respect the constraints of the persona it was synthesized for
+	if err := LintPersonaConstraints(dslCode, req.PersonaConstraints); err != nil {
+		validationErrors = append(validationErrors, err.Error())
+		duration := time.Since(startTime).Seconds()
+		span.SetAttributes(attribute.String("validation.error_type", "persona_constraint_violation"))
+		span.RecordError(err)
+		span.SetStatus(codes.Error, "Synthesized code violates persona constraints")
+		s.log.Info("Synthesized code violates persona constraints",
+			"agent", req.AgentName,
+			"error", err.Error())
+		return &AgentSynthesisResponse{
+			DSLCode:          dslCode,
+			Error:            err.Error(),
+			DurationSeconds:  duration,
+			ValidationErrors: validationErrors,
+			Cost:             synthesisCost,
+		}, validationFailed(err)
 	}
 
 	duration := time.Since(startTime).Seconds()
@@ -612,6 +784,7 @@ func (s *Synthesizer) buildSynthesisPrompt(req AgentSynthesisRequest) string {
 		"AttemptNumber":      req.AttemptNumber,
 		"MaxAttempts":        5, // TODO: Make this configurable
 		"LastKnownGoodCode":  req.LastKnownGoodCode,
+		"Examples":           req.Examples,
 	}
 
 	var buf bytes.Buffer
@@ -0,0 +1,58 @@
+package synthesis
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/cloudwego/eino/schema"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	langopv1alpha1 "github.com/language-operator/language-operator/api/v1alpha1"
+)
+
+func TestSynthesisRequestTimeout(t *testing.T) {
+	model := &langopv1alpha1.LanguageModel{Spec: langopv1alpha1.LanguageModelSpec{Timeout: "5m"}}
+
+	assert.Equal(t, 90*time.Second, synthesisRequestTimeout(model, 90*time.Second), "Expected the agent timeout to win")
+	assert.Equal(t, 5*time.Minute, synthesisRequestTimeout(model, 0), "Expected the model timeout as fallback")
+
+	model.Spec.Timeout = ""
+	assert.Equal(t, time.Duration(0), synthesisRequestTimeout(model, 0), "Expected no timeout when neither is set")
+}
+
+func TestNewSynthesizerFromLanguageModel_RespectsRequestTimeout(t *testing.T) {
+	// The model endpoint never answers within the timeout
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer server.Close()
+	defer close(release)
+
+	model := &langopv1alpha1.LanguageModel{
+		ObjectMeta: metav1.ObjectMeta{Name: "slow-model", Namespace: "default"},
+		Spec: langopv1alpha1.LanguageModelSpec{
+			Provider:  "openai-compatible",
+			ModelName: "slow-model",
+			Endpoint:  server.URL,
+			Timeout:   "5m",
+		},
+	}
+
+	synth, err := NewSynthesizerFromLanguageModel(context.Background(), nil, model, 200*time.Millisecond, logr.Discard())
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = synth.chatModel.Generate(context.Background(), []*schema.Message{schema.UserMessage("ping")})
+	require.Error(t, err, "Expected the request to time out")
+	assert.Less(t, time.Since(start), 5*time.Second, "Expected the agent timeout, not the model timeout, to apply")
+}
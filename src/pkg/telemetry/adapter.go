@@ -197,6 +197,8 @@ type Span struct {
 	// Common learning-relevant attributes:
 	// - "task.inputs": JSON string of task inputs
 	// - "task.outputs": JSON string of task outputs
+	// - "task.input.<name>", "task.output.<name>": individual task inputs/outputs
+	// - "task.name": Name of the task (takes precedence over TaskName)
 	// - "tool.name": Name of tool called
 	// - "tool.method": Method/function called on tool
 	// - "llm.model": Model name for LLM calls
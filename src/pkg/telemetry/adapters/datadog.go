@@ -0,0 +1,514 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+const (
+	// DefaultDatadogSite is the Datadog site used when none is configured
+	DefaultDatadogSite = "datadoghq.com"
+
+	// datadogSearchPageSize is the maximum number of spans Datadog returns per search request
+	datadogSearchPageSize = 1000
+
+	// datadogMaxSearchPages bounds pagination when the filter has no limit
+	datadogMaxSearchPages = 10
+)
+
+// datadogSites are the Datadog sites an account can live on
+var datadogSites = []string{
+	"datadoghq.com",
+	"us3.datadoghq.com",
+	"us5.datadoghq.com",
+	"datadoghq.eu",
+	"ap1.datadoghq.com",
+	"ddog-gov.com",
+}
+
+// datadogReservedAttributes are span filter attributes that Datadog indexes as reserved
+// attributes, queried without the @ prefix
+var datadogReservedAttributes = map[string]string{
+	"service.name":           "service",
+	"deployment.environment": "env",
+}
+
+// DatadogAdapter implements TelemetryAdapter for Datadog APM.
+//
+// Spans are found with the spans search API (POST /api/v2/spans/events/search), with the span
+// filter rendered in the Datadog query syntax: TaskName and Attributes become attribute
+// conditions (@task.name:"fetch_user" @agent.name:"reviewer"), except service.name and
+// deployment.environment, which Datadog indexes as the reserved service and env attributes.
+// Results are paged through with the cursor Datadog returns until the filter's limit is reached.
+//
+// A Datadog span's resource name becomes the operation name, and its service, tags, and custom
+// attributes become attributes, with nested custom attributes flattened to dotted keys.
+//
+// Datadog metrics are not read, so QueryMetrics returns no data points.
+//
+// Example usage:
+//
+//	adapter, err := NewDatadogAdapter(DatadogConfig{
+//	  Site:           "datadoghq.eu",
+//	  APIKey:         os.Getenv("DD_API_KEY"),
+//	  ApplicationKey: os.Getenv("DD_APPLICATION_KEY"),
+//	})
+//	spans, err := adapter.QuerySpans(ctx, telemetry.SpanFilter{
+//	  TaskName:   "fetch_user",
+//	  Attributes: map[string]string{"agent.name": "reviewer"},
+//	  TimeRange:  telemetry.TimeRange{Start: yesterday, End: now},
+//	  Limit:      100,
+//	})
+type DatadogAdapter struct {
+	// endpoint is the base URL of the Datadog API of the configured site
+	// Example: "https://api.datadoghq.eu"
+	endpoint string
+
+	// apiKey and applicationKey are sent as DD-API-KEY and DD-APPLICATION-KEY
+	apiKey         string
+	applicationKey string
+
+	// httpClient is the HTTP client for making requests
+	httpClient *http.Client
+
+	// maxResponseSize is the maximum allowed size for HTTP response bodies
+	maxResponseSize int64
+
+	// availabilityCache caches the result of Available() checks
+	// to avoid frequent health checks
+	availabilityCache struct {
+		sync.RWMutex
+		value     bool
+		timestamp time.Time
+		ttl       time.Duration
+		timeNow   func() time.Time // Injectable for testing
+	}
+}
+
+// DatadogConfig contains configuration options for DatadogAdapter.
+type DatadogConfig struct {
+	// Site is the Datadog site of the account, e.g. "datadoghq.com" or "datadoghq.eu"
+	// Defaults to DefaultDatadogSite if not specified
+	Site string
+
+	// APIKey is the Datadog API key
+	APIKey string
+
+	// ApplicationKey is a Datadog application key with the apm_read scope
+	ApplicationKey string
+
+	// Timeout is the HTTP request timeout
+	// Defaults to 30 seconds if not specified
+	Timeout time.Duration
+
+	// MaxResponseSize is the maximum allowed size for HTTP response bodies
+	// Defaults to 50MB (50 * 1024 * 1024 bytes) if not specified
+	MaxResponseSize int64
+}
+
+// NewDatadogAdapter creates a new DatadogAdapter.
+//
+// Returns error if either key is missing, the site is not a Datadog site, or the timeout or
+// response size limit is negative.
+func NewDatadogAdapter(config DatadogConfig) (*DatadogAdapter, error) {
+	if config.APIKey == "" {
+		return nil, fmt.Errorf("API key cannot be empty")
+	}
+	if config.ApplicationKey == "" {
+		return nil, fmt.Errorf("application key cannot be empty")
+	}
+
+	site := config.Site
+	if site == "" {
+		site = DefaultDatadogSite
+	}
+	site = strings.ToLower(site)
+	known := false
+	for _, s := range datadogSites {
+		if s == site {
+			known = true
+			break
+		}
+	}
+	if !known {
+		return nil, fmt.Errorf("unknown Datadog site %q, expected one of %s", config.Site, strings.Join(datadogSites, ", "))
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	if timeout < 0 {
+		return nil, fmt.Errorf("timeout must be positive, got %v", timeout)
+	}
+
+	maxResponseSize := config.MaxResponseSize
+	if maxResponseSize == 0 {
+		maxResponseSize = DefaultMaxResponseSize
+	}
+	if maxResponseSize < 0 {
+		return nil, fmt.Errorf("maxResponseSize must be positive, got %d", maxResponseSize)
+	}
+
+	adapter := &DatadogAdapter{
+		endpoint:        "https://api." + site,
+		apiKey:          config.APIKey,
+		applicationKey:  config.ApplicationKey,
+		maxResponseSize: maxResponseSize,
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+				MaxIdleConnsPerHost: 2,
+			},
+		},
+	}
+
+	adapter.availabilityCache.ttl = 30 * time.Second
+	adapter.availabilityCache.timeNow = time.Now
+
+	return adapter, nil
+}
+
+// do sends a request to the Datadog API and returns the response body
+func (d *DatadogAdapter) do(ctx context.Context, method, path string, payload interface{}) ([]byte, error) {
+	var body io.Reader
+	if payload != nil {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %w", err)
+		}
+		body = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, d.endpoint+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("DD-API-KEY", d.apiKey)
+	req.Header.Set("DD-APPLICATION-KEY", d.applicationKey)
+
+	resp, err := d.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, d.maxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(respBody)) > d.maxResponseSize {
+		return nil, fmt.Errorf("response body exceeds maximum allowed size of %d bytes", d.maxResponseSize)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Datadog API error: %d %s, body: %s", resp.StatusCode, resp.Status, string(respBody))
+	}
+
+	return respBody, nil
+}
+
+// datadogSearchRequest is the body of a spans search request
+type datadogSearchRequest struct {
+	Data struct {
+		Type       string `json:"type"`
+		Attributes struct {
+			Filter struct {
+				Query string `json:"query"`
+				From  string `json:"from,omitempty"`
+				To    string `json:"to,omitempty"`
+			} `json:"filter"`
+			Sort string `json:"sort"`
+			Page struct {
+				Limit  int    `json:"limit"`
+				Cursor string `json:"cursor,omitempty"`
+			} `json:"page"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// datadogSpan is a span of a spans search response
+type datadogSpan struct {
+	ID         string `json:"id"`
+	Attributes struct {
+		TraceID        string                 `json:"trace_id"`
+		SpanID         string                 `json:"span_id"`
+		ParentID       string                 `json:"parent_id"`
+		Service        string                 `json:"service"`
+		ResourceName   string                 `json:"resource_name"`
+		Env            string                 `json:"env"`
+		Host           string                 `json:"host"`
+		StartTimestamp time.Time              `json:"start_timestamp"`
+		EndTimestamp   time.Time              `json:"end_timestamp"`
+		Tags           []string               `json:"tags"`
+		Attributes     map[string]interface{} `json:"attributes"`
+		Custom         map[string]interface{} `json:"custom"`
+	} `json:"attributes"`
+}
+
+// datadogSearchResponse is a page of a spans search
+type datadogSearchResponse struct {
+	Data []datadogSpan `json:"data"`
+	Meta struct {
+		Page struct {
+			After string `json:"after"`
+		} `json:"page"`
+	} `json:"meta"`
+}
+
+// QuerySpans retrieves spans matching the filter from the spans search API.
+//
+// See the DatadogAdapter doc comment for how the query is built and paginated.
+// Returns spans ordered by timestamp (newest first) up to filter.Limit.
+func (d *DatadogAdapter) QuerySpans(ctx context.Context, filter telemetry.SpanFilter) ([]telemetry.Span, error) {
+	var request datadogSearchRequest
+	request.Data.Type = "search_request"
+	request.Data.Attributes.Filter.Query = buildDatadogQuery(filter)
+	if !filter.TimeRange.Start.IsZero() {
+		request.Data.Attributes.Filter.From = filter.TimeRange.Start.UTC().Format(time.RFC3339Nano)
+	}
+	if !filter.TimeRange.End.IsZero() {
+		request.Data.Attributes.Filter.To = filter.TimeRange.End.UTC().Format(time.RFC3339Nano)
+	}
+	request.Data.Attributes.Sort = "-timestamp"
+
+	spans := make([]telemetry.Span, 0)
+	for page := 0; page < datadogMaxSearchPages; page++ {
+		pageSize := datadogSearchPageSize
+		if filter.Limit > 0 && filter.Limit-len(spans) < pageSize {
+			pageSize = filter.Limit - len(spans)
+		}
+		request.Data.Attributes.Page.Limit = pageSize
+
+		body, err := d.do(ctx, http.MethodPost, "/api/v2/spans/events/search", request)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search Datadog spans: %w", err)
+		}
+		var response datadogSearchResponse
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal search response: %w", err)
+		}
+
+		for _, span := range response.Data {
+			spans = append(spans, convertDatadogSpan(span))
+		}
+
+		if response.Meta.Page.After == "" || len(response.Data) == 0 ||
+			(filter.Limit > 0 && len(spans) >= filter.Limit) {
+			break
+		}
+		request.Data.Attributes.Page.Cursor = response.Meta.Page.After
+	}
+
+	return limitSpans(spans, filter.Limit), nil
+}
+
+// buildDatadogQuery renders the span filter in the Datadog search syntax, with conditions in
+// key order. An empty filter matches every span.
+func buildDatadogQuery(filter telemetry.SpanFilter) string {
+	conditions := make(map[string]string, len(filter.Attributes)+1)
+	for key, value := range filter.Attributes {
+		conditions[key] = value
+	}
+	if filter.TaskName != "" {
+		conditions["task.name"] = filter.TaskName
+	}
+
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	clauses := make([]string, 0, len(keys)+1)
+	if filter.TraceID != "" {
+		clauses = append(clauses, "trace_id:"+datadogQueryValue(filter.TraceID))
+	}
+	for _, key := range keys {
+		attribute, reserved := datadogReservedAttributes[key]
+		if !reserved {
+			attribute = "@" + datadogQueryEscape(key)
+		}
+		clauses = append(clauses, attribute+":"+datadogQueryValue(conditions[key]))
+	}
+	if len(clauses) == 0 {
+		return "*"
+	}
+	return strings.Join(clauses, " ")
+}
+
+// datadogQueryValue quotes a value for an exact match
+func datadogQueryValue(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// datadogQueryEscape escapes the characters with special meaning in attribute names
+func datadogQueryEscape(key string) string {
+	var escaped strings.Builder
+	for _, r := range key {
+		if strings.ContainsRune(`+-=&|><!(){}[]^"~*?:\/ `, r) {
+			escaped.WriteRune('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+// convertDatadogSpan converts a span of a search response. Tags, span attributes, and custom
+// attributes are merged into the span's attributes, in increasing order of precedence.
+func convertDatadogSpan(raw datadogSpan) telemetry.Span {
+	attributes := make(map[string]string)
+	for _, tag := range raw.Attributes.Tags {
+		if key, value, ok := strings.Cut(tag, ":"); ok {
+			attributes[key] = value
+		}
+	}
+	flattenDatadogAttributes("", raw.Attributes.Attributes, attributes)
+	flattenDatadogAttributes("", raw.Attributes.Custom, attributes)
+	if raw.Attributes.Service != "" {
+		attributes["service.name"] = raw.Attributes.Service
+	}
+	if raw.Attributes.Env != "" {
+		attributes["env"] = raw.Attributes.Env
+	}
+	if raw.Attributes.Host != "" {
+		attributes["host"] = raw.Attributes.Host
+	}
+
+	start := raw.Attributes.StartTimestamp
+	end := raw.Attributes.EndTimestamp
+	duration := end.Sub(start)
+	// Datadog records the duration in nanoseconds, more precisely than the timestamps
+	if nanos, err := strconv.ParseFloat(attributes["duration"], 64); err == nil && nanos > 0 {
+		duration = time.Duration(nanos)
+		if end.IsZero() {
+			end = start.Add(duration)
+		}
+	}
+
+	spanID := raw.Attributes.SpanID
+	if spanID == "" {
+		spanID = raw.ID
+	}
+	parentID := raw.Attributes.ParentID
+	if parentID == "0" {
+		parentID = ""
+	}
+
+	taskName := attributes["task.name"]
+	if taskName == "" {
+		taskName = raw.Attributes.ResourceName
+	}
+
+	span := telemetry.Span{
+		SpanID:        spanID,
+		TraceID:       raw.Attributes.TraceID,
+		ParentSpanID:  parentID,
+		OperationName: raw.Attributes.ResourceName,
+		TaskName:      taskName,
+		StartTime:     start,
+		EndTime:       end,
+		Duration:      duration,
+		Status:        attributes["status"] != "error" && attributes["error.message"] == "" && attributes["error.type"] == "",
+		Attributes:    attributes,
+	}
+	if !span.Status {
+		span.ErrorMessage = attributes["error.message"]
+	}
+	return span
+}
+
+// flattenDatadogAttributes flattens nested attributes to dotted keys with string values
+func flattenDatadogAttributes(prefix string, raw map[string]interface{}, out map[string]string) {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]interface{}:
+			flattenDatadogAttributes(key, v, out)
+		case string:
+			out[key] = v
+		case float64:
+			out[key] = strconv.FormatFloat(v, 'f', -1, 64)
+		case bool:
+			out[key] = strconv.FormatBool(v)
+		case nil:
+			// Attributes without a value are left out
+		default:
+			// Arrays are kept as JSON
+			encoded, _ := json.Marshal(v)
+			out[key] = string(encoded)
+		}
+	}
+}
+
+// QueryMetrics returns no data points: the adapter reads APM spans only.
+func (d *DatadogAdapter) QueryMetrics(ctx context.Context, filter telemetry.MetricFilter) ([]telemetry.MetricPoint, error) {
+	return []telemetry.MetricPoint{}, nil
+}
+
+// Available returns true if Datadog accepts the API key.
+//
+// Uses caching to avoid frequent health checks (30 second TTL).
+func (d *DatadogAdapter) Available() bool {
+	now := d.availabilityCache.timeNow()
+
+	d.availabilityCache.RLock()
+	if now.Sub(d.availabilityCache.timestamp) < d.availabilityCache.ttl {
+		value := d.availabilityCache.value
+		d.availabilityCache.RUnlock()
+		return value
+	}
+	d.availabilityCache.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	available := false
+	if body, err := d.do(ctx, http.MethodGet, "/api/v1/validate", nil); err == nil {
+		var response struct {
+			Valid bool `json:"valid"`
+		}
+		available = json.Unmarshal(body, &response) == nil && response.Valid
+	}
+
+	d.availabilityCache.Lock()
+	d.availabilityCache.value = available
+	d.availabilityCache.timestamp = now
+	d.availabilityCache.Unlock()
+
+	return available
+}
@@ -0,0 +1,294 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+// newTestDatadogAdapter creates an adapter that sends its requests to the given test server
+func newTestDatadogAdapter(t *testing.T, server *httptest.Server) *DatadogAdapter {
+	t.Helper()
+	adapter, err := NewDatadogAdapter(DatadogConfig{APIKey: "api-key", ApplicationKey: "app-key"})
+	require.NoError(t, err)
+	adapter.endpoint = server.URL
+	return adapter
+}
+
+func TestNewDatadogAdapter(t *testing.T) {
+	t.Run("Default site", func(t *testing.T) {
+		adapter, err := NewDatadogAdapter(DatadogConfig{APIKey: "api-key", ApplicationKey: "app-key"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.datadoghq.com", adapter.endpoint)
+		assert.Equal(t, 30*time.Second, adapter.httpClient.Timeout)
+		assert.Equal(t, int64(DefaultMaxResponseSize), adapter.maxResponseSize)
+	})
+
+	t.Run("EU site", func(t *testing.T) {
+		adapter, err := NewDatadogAdapter(DatadogConfig{Site: "datadoghq.eu", APIKey: "api-key", ApplicationKey: "app-key"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "https://api.datadoghq.eu", adapter.endpoint)
+	})
+
+	t.Run("Unknown site", func(t *testing.T) {
+		_, err := NewDatadogAdapter(DatadogConfig{Site: "example.com", APIKey: "api-key", ApplicationKey: "app-key"})
+		assert.ErrorContains(t, err, "unknown Datadog site")
+	})
+
+	t.Run("Missing API key", func(t *testing.T) {
+		_, err := NewDatadogAdapter(DatadogConfig{ApplicationKey: "app-key"})
+		assert.ErrorContains(t, err, "API key cannot be empty")
+	})
+
+	t.Run("Missing application key", func(t *testing.T) {
+		_, err := NewDatadogAdapter(DatadogConfig{APIKey: "api-key"})
+		assert.ErrorContains(t, err, "application key cannot be empty")
+	})
+
+	t.Run("Negative timeout", func(t *testing.T) {
+		_, err := NewDatadogAdapter(DatadogConfig{APIKey: "api-key", ApplicationKey: "app-key", Timeout: -time.Second})
+		assert.ErrorContains(t, err, "timeout must be positive")
+	})
+}
+
+func TestBuildDatadogQuery(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   telemetry.SpanFilter
+		expected string
+	}{
+		{name: "no conditions", expected: "*"},
+		{
+			name:     "task name",
+			filter:   telemetry.SpanFilter{TaskName: "fetch_user"},
+			expected: `@task.name:"fetch_user"`,
+		},
+		{
+			name: "attributes in key order",
+			filter: telemetry.SpanFilter{
+				TaskName:   "fetch_user",
+				TraceID:    "1234",
+				Attributes: map[string]string{"agent.name": "reviewer", "service.name": "reviewer-agent"},
+			},
+			expected: `trace_id:"1234" @agent.name:"reviewer" service:"reviewer-agent" @task.name:"fetch_user"`,
+		},
+		{
+			name:     "escaped values and keys",
+			filter:   telemetry.SpanFilter{Attributes: map[string]string{"http route": `/users/"me"`}},
+			expected: `@http\ route:"/users/\"me\""`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildDatadogQuery(tt.filter))
+		})
+	}
+}
+
+func TestDatadogAdapter_QuerySpans(t *testing.T) {
+	start := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	// A page with a root span and a failed child task, with nested custom attributes
+	page := map[string]interface{}{
+		"data": []interface{}{
+			map[string]interface{}{
+				"id":   "AAAAAYqcZ1",
+				"type": "spans",
+				"attributes": map[string]interface{}{
+					"trace_id":        "6543210987654321",
+					"span_id":         "1111",
+					"parent_id":       "0",
+					"service":         "reviewer",
+					"resource_name":   "agent.run",
+					"env":             "prod",
+					"start_timestamp": start.Format(time.RFC3339Nano),
+					"end_timestamp":   start.Add(2 * time.Second).Format(time.RFC3339Nano),
+					"tags":            []string{"team:platform", "agent.name:reviewer"},
+					"custom":          map[string]interface{}{"duration": 2e9},
+				},
+			},
+			map[string]interface{}{
+				"id":   "AAAAAYqcZ2",
+				"type": "spans",
+				"attributes": map[string]interface{}{
+					"trace_id":        "6543210987654321",
+					"span_id":         "2222",
+					"parent_id":       "1111",
+					"service":         "reviewer",
+					"resource_name":   "task",
+					"start_timestamp": start.Add(500 * time.Millisecond).Format(time.RFC3339Nano),
+					"end_timestamp":   start.Add(1500 * time.Millisecond).Format(time.RFC3339Nano),
+					"tags":            []string{"agent.name:reviewer"},
+					"custom": map[string]interface{}{
+						"duration": 1.25e9,
+						"task":     map[string]interface{}{"name": "fetch_user", "retries": 2},
+						"error":    map[string]interface{}{"message": "user not found", "type": "NotFound"},
+						"cached":   false,
+					},
+				},
+			},
+		},
+		"meta": map[string]interface{}{"page": map[string]interface{}{}},
+	}
+
+	var mu sync.Mutex
+	var requests []datadogSearchRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/api/v2/spans/events/search", r.URL.Path)
+		assert.Equal(t, "api-key", r.Header.Get("DD-API-KEY"))
+		assert.Equal(t, "app-key", r.Header.Get("DD-APPLICATION-KEY"))
+
+		var request datadogSearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		mu.Lock()
+		requests = append(requests, request)
+		mu.Unlock()
+
+		body, _ := json.Marshal(page)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	adapter := newTestDatadogAdapter(t, server)
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{
+		Attributes: map[string]string{"agent.name": "reviewer"},
+		TimeRange:  telemetry.TimeRange{Start: start.Add(-time.Hour), End: start.Add(time.Hour)},
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, spans, 2)
+
+	require.Len(t, requests, 1)
+	attributes := requests[0].Data.Attributes
+	assert.Equal(t, "search_request", requests[0].Data.Type)
+	assert.Equal(t, `@agent.name:"reviewer"`, attributes.Filter.Query)
+	assert.Equal(t, "2026-10-16T08:00:00Z", attributes.Filter.From)
+	assert.Equal(t, "2026-10-16T10:00:00Z", attributes.Filter.To)
+	assert.Equal(t, "-timestamp", attributes.Sort)
+	assert.Equal(t, 10, attributes.Page.Limit)
+
+	// Newest first: the child task started after the root span
+	task := spans[0]
+	assert.Equal(t, "6543210987654321", task.TraceID)
+	assert.Equal(t, "2222", task.SpanID)
+	assert.Equal(t, "1111", task.ParentSpanID)
+	assert.Equal(t, "task", task.OperationName)
+	assert.Equal(t, "fetch_user", task.TaskName)
+	assert.Equal(t, 1250*time.Millisecond, task.Duration)
+	assert.False(t, task.Status)
+	assert.Equal(t, "user not found", task.ErrorMessage)
+	assert.Equal(t, "reviewer", task.Attributes["service.name"])
+	assert.Equal(t, "reviewer", task.Attributes["agent.name"])
+	assert.Equal(t, "2", task.Attributes["task.retries"])
+	assert.Equal(t, "false", task.Attributes["cached"])
+
+	root := spans[1]
+	assert.Equal(t, "1111", root.SpanID)
+	assert.Empty(t, root.ParentSpanID)
+	assert.Equal(t, "agent.run", root.OperationName)
+	assert.Equal(t, "agent.run", root.TaskName)
+	assert.Equal(t, 2*time.Second, root.Duration)
+	assert.True(t, root.Status)
+	assert.Equal(t, "platform", root.Attributes["team"])
+	assert.Equal(t, "prod", root.Attributes["env"])
+}
+
+func TestDatadogAdapter_QuerySpans_Pagination(t *testing.T) {
+	var mu sync.Mutex
+	var cursors []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request datadogSearchRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		mu.Lock()
+		page := len(cursors)
+		cursors = append(cursors, request.Data.Attributes.Page.Cursor)
+		mu.Unlock()
+
+		started := time.Unix(1700000000-int64(page), 0).UTC()
+		response := map[string]interface{}{
+			"data": []interface{}{map[string]interface{}{"attributes": map[string]interface{}{
+				"span_id":         started.Format("150405"),
+				"resource_name":   "task",
+				"start_timestamp": started.Format(time.RFC3339Nano),
+				"end_timestamp":   started.Add(time.Second).Format(time.RFC3339Nano),
+			}}},
+			"meta": map[string]interface{}{"page": map[string]interface{}{"after": "page-" + string(rune('a'+page))}},
+		}
+		body, _ := json.Marshal(response)
+		_, _ = w.Write(body)
+	}))
+	defer server.Close()
+
+	adapter := newTestDatadogAdapter(t, server)
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{Limit: 3})
+	require.NoError(t, err)
+
+	assert.Len(t, spans, 3)
+	assert.Equal(t, []string{"", "page-a", "page-b"}, cursors)
+}
+
+func TestDatadogAdapter_QuerySpans_Error(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(`{"errors":["Forbidden"]}`))
+	}))
+	defer server.Close()
+
+	adapter := newTestDatadogAdapter(t, server)
+	_, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{})
+	assert.ErrorContains(t, err, "403")
+}
+
+func TestDatadogAdapter_Available(t *testing.T) {
+	valid := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/validate", r.URL.Path)
+		if !valid {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte(`{"valid":true}`))
+	}))
+	defer server.Close()
+
+	adapter := newTestDatadogAdapter(t, server)
+	now := time.Now()
+	adapter.availabilityCache.timeNow = func() time.Time { return now }
+
+	assert.True(t, adapter.Available())
+
+	// Cached until the TTL passes
+	valid = false
+	assert.True(t, adapter.Available())
+	now = now.Add(time.Minute)
+	assert.False(t, adapter.Available())
+}
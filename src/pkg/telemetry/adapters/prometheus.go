@@ -0,0 +1,575 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+const (
+	// PrometheusTaskDurationMetric is the histogram agents record task execution time in
+	PrometheusTaskDurationMetric = "langop_task_duration_seconds"
+
+	// PrometheusTaskErrorsMetric is the counter agents increment when a task fails
+	PrometheusTaskErrorsMetric = "langop_task_errors_total"
+
+	// prometheusTaskLabel is the label carrying the task name on both task metrics
+	prometheusTaskLabel = "task_name"
+
+	// prometheusTraceIDLabel is the exemplar label carrying the trace ID of a failed task
+	prometheusTraceIDLabel = "trace_id"
+
+	// DefaultPrometheusSpanLimit caps the synthesized spans when the filter sets no limit
+	DefaultPrometheusSpanLimit = 1000
+)
+
+// PrometheusAdapter implements TelemetryAdapter for Prometheus-compatible metrics backends.
+//
+// Prometheus has no traces, so QuerySpans synthesizes approximate spans from the aggregated
+// task metrics: for each task it reads how many executions and errors happened in the time
+// range and their total duration, then returns that many execute_task spans with the average
+// duration, spread evenly across the range. When agents attach exemplars to the error counter,
+// failed spans take the trace ID and time of those exemplars; failures without one are spread
+// evenly among the other spans. This is enough for success-rate and duration based learning
+// triggers, but the spans carry no per-call data:
+//   - task inputs, outputs, and tool calls are never populated, so TaskTrace.Inputs/Outputs are empty
+//   - durations are the task average, not individual execution times
+//   - span IDs, and trace IDs of spans without an exemplar, are synthetic, so TraceID filters match nothing
+//
+// Without a filter limit at most DefaultPrometheusSpanLimit spans are returned.
+//
+// Span attribute filters are translated to metric label matchers, with dots replaced by
+// underscores ("agent.name" matches the agent_name label).
+//
+// Example usage:
+//
+//	adapter, err := NewPrometheusAdapter("http://prometheus.monitoring:9090", "", "", 30*time.Second)
+//	spans, err := adapter.QuerySpans(ctx, telemetry.SpanFilter{
+//	  Attributes: map[string]string{"agent.name": "reviewer"},
+//	  TimeRange:  telemetry.TimeRange{Start: yesterday, End: now},
+//	  Limit:      1000,
+//	})
+type PrometheusAdapter struct {
+	// endpoint is the base URL of the Prometheus HTTP API
+	// Example: "http://prometheus.monitoring.svc:9090"
+	endpoint string
+
+	// username and password are sent as HTTP basic auth when username is set
+	username string
+	password string
+
+	// httpClient is the HTTP client for making requests
+	httpClient *http.Client
+
+	// maxResponseSize is the maximum allowed size for HTTP response bodies
+	maxResponseSize int64
+
+	// availabilityCache caches the result of Available() checks
+	// to avoid frequent health checks
+	availabilityCache struct {
+		sync.RWMutex
+		value     bool
+		timestamp time.Time
+		ttl       time.Duration
+		timeNow   func() time.Time // Injectable for testing
+	}
+}
+
+// NewPrometheusAdapter creates a new PrometheusAdapter.
+//
+// Parameters:
+//   - endpoint: Base URL of the Prometheus HTTP API (e.g., "http://prometheus:9090")
+//   - username, password: Optional basic auth credentials; leave username empty to disable
+//   - timeout: HTTP request timeout (use 30*time.Second for default)
+//
+// Returns error if the endpoint is not a valid http(s) URL or the timeout is not positive.
+func NewPrometheusAdapter(endpoint, username, password string, timeout time.Duration) (*PrometheusAdapter, error) {
+	if endpoint == "" {
+		return nil, fmt.Errorf("endpoint cannot be empty")
+	}
+
+	if timeout <= 0 {
+		return nil, fmt.Errorf("timeout must be positive, got %v", timeout)
+	}
+
+	parsedURL, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint URL: %w", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint URL scheme must be http or https, got: %q", parsedURL.Scheme)
+	}
+	if parsedURL.Host == "" {
+		return nil, fmt.Errorf("endpoint URL must include host: %s", endpoint)
+	}
+	if err := validateHost(parsedURL.Host); err != nil {
+		return nil, fmt.Errorf("invalid endpoint host: %w", err)
+	}
+
+	adapter := &PrometheusAdapter{
+		endpoint:        strings.TrimSuffix(endpoint, "/"),
+		username:        username,
+		password:        password,
+		maxResponseSize: DefaultMaxResponseSize,
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+				MaxIdleConnsPerHost: 2,
+			},
+		},
+	}
+
+	adapter.availabilityCache.ttl = 30 * time.Second
+	adapter.availabilityCache.timeNow = time.Now
+
+	return adapter, nil
+}
+
+// prometheusSample is one series of a Prometheus query result. Instant queries fill Value,
+// range queries fill Values.
+type prometheusSample struct {
+	Metric map[string]string `json:"metric"`
+	Value  []interface{}     `json:"value"`
+	Values [][]interface{}   `json:"values"`
+}
+
+// get performs a GET request against the Prometheus HTTP API and returns the response body
+func (p *PrometheusAdapter) get(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	reqURL := p.endpoint + path
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if p.username != "" {
+		req.SetBasicAuth(p.username, p.password)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, p.maxResponseSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > p.maxResponseSize {
+		return nil, fmt.Errorf("response body exceeds maximum allowed size of %d bytes", p.maxResponseSize)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, fmt.Errorf("Prometheus API error: %d %s, body: %s", resp.StatusCode, resp.Status, string(body))
+	}
+
+	return body, nil
+}
+
+// query runs a PromQL query against the given API path and returns the result series
+func (p *PrometheusAdapter) query(ctx context.Context, path string, params url.Values) ([]prometheusSample, error) {
+	body, err := p.get(ctx, path, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   struct {
+			ResultType string             `json:"resultType"`
+			Result     []prometheusSample `json:"result"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("Prometheus query failed: %s", response.Error)
+	}
+
+	return response.Data.Result, nil
+}
+
+// QuerySpans synthesizes task execution spans from the aggregated task metrics.
+//
+// See the PrometheusAdapter doc comment for the fidelity of the returned spans.
+// Returns spans ordered by timestamp (newest first) up to filter.Limit.
+func (p *PrometheusAdapter) QuerySpans(ctx context.Context, filter telemetry.SpanFilter) ([]telemetry.Span, error) {
+	// Synthesized spans can't belong to a real trace
+	if filter.TraceID != "" {
+		return []telemetry.Span{}, nil
+	}
+
+	window := filter.TimeRange.Duration()
+	if window <= 0 {
+		return []telemetry.Span{}, nil
+	}
+
+	matchers := spanFilterMatchers(filter)
+	executions, err := p.taskTotals(ctx, PrometheusTaskDurationMetric+"_count", matchers, filter.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task executions: %w", err)
+	}
+	durations, err := p.taskTotals(ctx, PrometheusTaskDurationMetric+"_sum", matchers, filter.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task durations: %w", err)
+	}
+	errorCounts, err := p.taskTotals(ctx, PrometheusTaskErrorsMetric, matchers, filter.TimeRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query task errors: %w", err)
+	}
+
+	// Exemplar storage is optional in Prometheus; without it failures are spread across the range
+	failedTraces, err := p.failedTaskTraces(ctx, matchers, filter.TimeRange)
+	if err != nil {
+		failedTraces = nil
+	}
+
+	return synthesizeTaskSpans(filter, executions, durations, errorCounts, failedTraces), nil
+}
+
+// prometheusExemplar is a failed task execution an agent attached its trace ID to
+type prometheusExemplar struct {
+	TraceID string
+	Time    time.Time
+}
+
+// failedTaskTraces returns the exemplars recorded on the task error counter in the time range,
+// by task, newest first and with one exemplar per trace
+func (p *PrometheusAdapter) failedTaskTraces(ctx context.Context, matchers string, timeRange telemetry.TimeRange) (map[string][]prometheusExemplar, error) {
+	params := url.Values{}
+	params.Set("query", PrometheusTaskErrorsMetric+matchers)
+	params.Set("start", strconv.FormatInt(timeRange.Start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(timeRange.End.Unix(), 10))
+
+	body, err := p.get(ctx, "/api/v1/query_exemplars", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var response struct {
+		Status string `json:"status"`
+		Error  string `json:"error"`
+		Data   []struct {
+			SeriesLabels map[string]string `json:"seriesLabels"`
+			Exemplars    []struct {
+				Labels    map[string]string `json:"labels"`
+				Timestamp float64           `json:"timestamp"`
+			} `json:"exemplars"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	if response.Status != "success" {
+		return nil, fmt.Errorf("Prometheus exemplar query failed: %s", response.Error)
+	}
+
+	traces := make(map[string][]prometheusExemplar)
+	seen := make(map[string]bool)
+	for _, series := range response.Data {
+		task := series.SeriesLabels[prometheusTaskLabel]
+		for _, exemplar := range series.Exemplars {
+			traceID := exemplar.Labels[prometheusTraceIDLabel]
+			if task == "" || traceID == "" || seen[traceID] {
+				continue
+			}
+			ts := time.Unix(0, int64(exemplar.Timestamp*float64(time.Second)))
+			if !timeRange.Contains(ts) {
+				continue
+			}
+			seen[traceID] = true
+			traces[task] = append(traces[task], prometheusExemplar{TraceID: traceID, Time: ts})
+		}
+	}
+	for _, exemplars := range traces {
+		sort.SliceStable(exemplars, func(i, j int) bool { return exemplars[i].Time.After(exemplars[j].Time) })
+	}
+	return traces, nil
+}
+
+// taskTotals returns how much a counter increased over the time range, summed by task
+func (p *PrometheusAdapter) taskTotals(ctx context.Context, metric, matchers string, timeRange telemetry.TimeRange) (map[string]float64, error) {
+	seconds := int64(math.Ceil(timeRange.Duration().Seconds()))
+	promQL := fmt.Sprintf("sum by (%s) (increase(%s%s[%ds]))", prometheusTaskLabel, metric, matchers, seconds)
+
+	params := url.Values{}
+	params.Set("query", promQL)
+	params.Set("time", strconv.FormatInt(timeRange.End.Unix(), 10))
+
+	samples, err := p.query(ctx, "/api/v1/query", params)
+	if err != nil {
+		return nil, err
+	}
+
+	totals := make(map[string]float64, len(samples))
+	for _, sample := range samples {
+		if len(sample.Value) != 2 {
+			continue
+		}
+		value, ok := parsePrometheusValue(sample.Value[1])
+		if !ok || math.IsNaN(value) {
+			continue
+		}
+		totals[sample.Metric[prometheusTaskLabel]] = value
+	}
+	return totals, nil
+}
+
+// synthesizeTaskSpans turns per-task execution, duration, and error totals into execute_task spans.
+// When the executions exceed the limit, every task is scaled down by the same factor so the
+// error rates stay representative. Failures are matched to the newest failed traces first; the
+// remaining ones are spread evenly among the task's other spans.
+func synthesizeTaskSpans(filter telemetry.SpanFilter, executions, durations, errorCounts map[string]float64, failedTraces map[string][]prometheusExemplar) []telemetry.Span {
+	tasks := make([]string, 0, len(executions))
+	total := 0
+	for task, count := range executions {
+		if task == "" || math.Round(count) < 1 {
+			continue
+		}
+		tasks = append(tasks, task)
+		total += int(math.Round(count))
+	}
+	sort.Strings(tasks)
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = DefaultPrometheusSpanLimit
+	}
+	scale := 1.0
+	if total > limit {
+		scale = float64(limit) / float64(total)
+	}
+
+	spans := make([]telemetry.Span, 0, min(total, limit))
+	window := filter.TimeRange.Duration()
+	for _, task := range tasks {
+		count := executions[task]
+		n := int(math.Floor(math.Round(count) * scale))
+		if n == 0 {
+			continue
+		}
+		failures := int(math.Round(math.Min(errorCounts[task], count) * scale))
+		if failures > n {
+			failures = n
+		}
+		duration := time.Duration(durations[task] / count * float64(time.Second))
+		if duration > window {
+			duration = window
+		}
+		latest := filter.TimeRange.End.Add(-duration)
+
+		newSpan := func(i int, traceID string, start time.Time, failed bool) telemetry.Span {
+			attributes := map[string]string{"task.name": task}
+			for key, value := range filter.Attributes {
+				attributes[key] = value
+			}
+			span := telemetry.Span{
+				SpanID:        fmt.Sprintf("prometheus-%s-%d", task, i),
+				TraceID:       traceID,
+				OperationName: "execute_task",
+				TaskName:      task,
+				StartTime:     start,
+				EndTime:       start.Add(duration),
+				Duration:      duration,
+				Status:        !failed,
+				Attributes:    attributes,
+			}
+			if failed {
+				span.ErrorMessage = "task failed (aggregated from Prometheus metrics)"
+			}
+			return span
+		}
+
+		// Failures with an exemplar keep their trace; the exemplar is recorded as the task ends
+		traced := failedTraces[task]
+		if len(traced) > failures {
+			traced = traced[:failures]
+		}
+		for i, exemplar := range traced {
+			start := exemplar.Time.Add(-duration)
+			if start.Before(filter.TimeRange.Start) {
+				start = filter.TimeRange.Start
+			}
+			if start.After(latest) {
+				start = latest
+			}
+			spans = append(spans, newSpan(i, exemplar.TraceID, start, true))
+		}
+
+		// Spread the other executions evenly, keeping each one inside the time range, and
+		// the failures without an exemplar evenly among them
+		rest := n - len(traced)
+		untraced := failures - len(traced)
+		for i := 0; i < rest; i++ {
+			start := filter.TimeRange.Start.Add(time.Duration(float64(window) * float64(i+1) / float64(rest+1)))
+			if start.After(latest) {
+				start = latest
+			}
+			failed := (i+1)*untraced/rest > i*untraced/rest
+			id := len(traced) + i
+			spans = append(spans, newSpan(id, fmt.Sprintf("prometheus-%s-%d", task, id), start, failed))
+		}
+	}
+
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime.After(spans[j].StartTime) })
+	return spans
+}
+
+// spanFilterMatchers builds the PromQL label matchers for a span filter
+func spanFilterMatchers(filter telemetry.SpanFilter) string {
+	labels := make(map[string]string, len(filter.Attributes)+1)
+	for key, value := range filter.Attributes {
+		labels[strings.NewReplacer(".", "_", "-", "_").Replace(key)] = value
+	}
+	if filter.TaskName != "" {
+		labels[prometheusTaskLabel] = filter.TaskName
+	}
+	return labelMatchers(labels)
+}
+
+// labelMatchers renders exact-match label matchers in a stable order
+func labelMatchers(labels map[string]string) string {
+	if len(labels) == 0 {
+		return ""
+	}
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	matchers := make([]string, 0, len(keys))
+	for _, key := range keys {
+		matchers = append(matchers, fmt.Sprintf("%s=%s", key, strconv.Quote(labels[key])))
+	}
+	return "{" + strings.Join(matchers, ",") + "}"
+}
+
+// parsePrometheusValue parses a sample value, which the API encodes as a string
+func parsePrometheusValue(raw interface{}) (float64, bool) {
+	switch v := raw.(type) {
+	case string:
+		value, err := strconv.ParseFloat(v, 64)
+		return value, err == nil
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// QueryMetrics retrieves metric data points with a PromQL range query.
+//
+// Returns metrics ordered by timestamp (newest first) up to filter.Limit.
+func (p *PrometheusAdapter) QueryMetrics(ctx context.Context, filter telemetry.MetricFilter) ([]telemetry.MetricPoint, error) {
+	promQL := filter.MetricName + labelMatchers(filter.Labels)
+	if filter.Aggregation != "" {
+		switch strings.ToLower(filter.Aggregation) {
+		case "avg", "sum", "max", "min", "count":
+			promQL = fmt.Sprintf("%s(%s)", strings.ToLower(filter.Aggregation), promQL)
+		default:
+			promQL = fmt.Sprintf("avg(%s)", promQL)
+		}
+	}
+
+	params := url.Values{}
+	params.Set("query", promQL)
+	params.Set("start", strconv.FormatInt(filter.TimeRange.Start.Unix(), 10))
+	params.Set("end", strconv.FormatInt(filter.TimeRange.End.Unix(), 10))
+	params.Set("step", "60s")
+
+	samples, err := p.query(ctx, "/api/v1/query_range", params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query Prometheus metrics: %w", err)
+	}
+
+	metrics := make([]telemetry.MetricPoint, 0)
+	for _, series := range samples {
+		for _, pair := range series.Values {
+			if len(pair) != 2 {
+				continue
+			}
+			timestamp, ok := pair[0].(float64)
+			if !ok {
+				continue
+			}
+			value, ok := parsePrometheusValue(pair[1])
+			if !ok {
+				continue
+			}
+			metrics = append(metrics, telemetry.MetricPoint{
+				Time:   time.Unix(int64(timestamp), 0),
+				Value:  value,
+				Labels: series.Metric,
+			})
+		}
+	}
+
+	sort.SliceStable(metrics, func(i, j int) bool { return metrics[i].Time.After(metrics[j].Time) })
+	if filter.Limit > 0 && len(metrics) > filter.Limit {
+		metrics = metrics[:filter.Limit]
+	}
+	return metrics, nil
+}
+
+// Available returns true if Prometheus reports ready.
+//
+// Uses caching to avoid frequent health checks (30 second TTL).
+func (p *PrometheusAdapter) Available() bool {
+	now := p.availabilityCache.timeNow()
+
+	p.availabilityCache.RLock()
+	if now.Sub(p.availabilityCache.timestamp) < p.availabilityCache.ttl {
+		value := p.availabilityCache.value
+		p.availabilityCache.RUnlock()
+		return value
+	}
+	p.availabilityCache.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := p.get(ctx, "/-/ready", nil)
+	available := err == nil
+
+	p.availabilityCache.Lock()
+	p.availabilityCache.value = available
+	p.availabilityCache.timestamp = now
+	p.availabilityCache.Unlock()
+
+	return available
+}
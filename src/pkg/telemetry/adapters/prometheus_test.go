@@ -0,0 +1,319 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+func TestNewPrometheusAdapter(t *testing.T) {
+	t.Run("Valid configuration", func(t *testing.T) {
+		adapter, err := NewPrometheusAdapter("http://prometheus.monitoring:9090/", "user", "secret", 30*time.Second)
+
+		require.NoError(t, err)
+		assert.Equal(t, "http://prometheus.monitoring:9090", adapter.endpoint)
+		assert.Equal(t, "user", adapter.username)
+	})
+
+	t.Run("Empty endpoint", func(t *testing.T) {
+		_, err := NewPrometheusAdapter("", "", "", 30*time.Second)
+		assert.ErrorContains(t, err, "endpoint cannot be empty")
+	})
+
+	t.Run("Unsupported scheme", func(t *testing.T) {
+		_, err := NewPrometheusAdapter("ftp://prometheus:9090", "", "", 30*time.Second)
+		assert.ErrorContains(t, err, "scheme must be http or https")
+	})
+
+	t.Run("Zero timeout", func(t *testing.T) {
+		_, err := NewPrometheusAdapter("http://prometheus:9090", "", "", 0)
+		assert.ErrorContains(t, err, "timeout must be positive")
+	})
+}
+
+// prometheusVector renders an instant query response with one series per task
+func prometheusVector(values map[string]string) []byte {
+	result := make([]map[string]interface{}, 0, len(values))
+	for task, value := range values {
+		result = append(result, map[string]interface{}{
+			"metric": map[string]string{"task_name": task},
+			"value":  []interface{}{1700000000.0, value},
+		})
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"status": "success",
+		"data":   map[string]interface{}{"resultType": "vector", "result": result},
+	})
+	return body
+}
+
+// prometheusExemplars renders an exemplar query response for a task's error counter
+func prometheusExemplars(task string, traces map[string]int64) []byte {
+	exemplars := make([]map[string]interface{}, 0, len(traces))
+	for traceID, ts := range traces {
+		exemplars = append(exemplars, map[string]interface{}{
+			"labels":    map[string]string{"trace_id": traceID},
+			"value":     "1",
+			"timestamp": float64(ts),
+		})
+	}
+	body, _ := json.Marshal(map[string]interface{}{
+		"status": "success",
+		"data": []interface{}{map[string]interface{}{
+			"seriesLabels": map[string]string{"__name__": "langop_task_errors_total", "task_name": task},
+			"exemplars":    exemplars,
+		}},
+	})
+	return body
+}
+
+func TestPrometheusAdapter_QuerySpans(t *testing.T) {
+	end := time.Now().Truncate(time.Second)
+	var mu sync.Mutex
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "user" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		query := r.URL.Query().Get("query")
+		if r.URL.Path == "/api/v1/query_exemplars" {
+			assert.Equal(t, `langop_task_errors_total{agent_name="reviewer"}`, query)
+			_, _ = w.Write(prometheusExemplars("fetch_user", map[string]int64{
+				"trace-a": end.Add(-10 * time.Minute).Unix(),
+				"trace-b": end.Add(-20 * time.Minute).Unix(),
+				"trace-c": end.Add(-2 * time.Hour).Unix(),
+			}))
+			return
+		}
+		mu.Lock()
+		queries = append(queries, query)
+		mu.Unlock()
+		switch {
+		case strings.Contains(query, "langop_task_duration_seconds_count"):
+			_, _ = w.Write(prometheusVector(map[string]string{"fetch_user": "10", "notify": "2"}))
+		case strings.Contains(query, "langop_task_duration_seconds_sum"):
+			_, _ = w.Write(prometheusVector(map[string]string{"fetch_user": "25", "notify": "1"}))
+		case strings.Contains(query, "langop_task_errors_total"):
+			_, _ = w.Write(prometheusVector(map[string]string{"fetch_user": "3"}))
+		}
+	}))
+	defer server.Close()
+
+	adapter, err := NewPrometheusAdapter(server.URL, "user", "secret", 5*time.Second)
+	require.NoError(t, err)
+
+	timeRange := telemetry.TimeRange{Start: end.Add(-time.Hour), End: end}
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{
+		TimeRange:  timeRange,
+		Attributes: map[string]string{"agent.name": "reviewer"},
+		Limit:      1000,
+	})
+	require.NoError(t, err)
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, queries, 3)
+	assert.Equal(t, `sum by (task_name) (increase(langop_task_duration_seconds_count{agent_name="reviewer"}[3600s]))`, queries[0])
+
+	require.Len(t, spans, 12)
+	failures := map[string]int{}
+	var failedTraces []string
+	for i, span := range spans {
+		assert.Equal(t, "execute_task", span.OperationName)
+		assert.Equal(t, span.TaskName, span.Attributes["task.name"])
+		assert.Equal(t, "reviewer", span.Attributes["agent.name"])
+		assert.True(t, timeRange.Contains(span.StartTime) && timeRange.Contains(span.EndTime), "span outside the time range")
+		if i > 0 {
+			assert.False(t, span.StartTime.After(spans[i-1].StartTime), "spans not ordered newest first")
+		}
+		if span.TaskName == "fetch_user" {
+			assert.Equal(t, 2500*time.Millisecond, span.Duration)
+		}
+		if !span.Status {
+			failures[span.TaskName]++
+			failedTraces = append(failedTraces, span.TraceID)
+		}
+	}
+	assert.Equal(t, map[string]int{"fetch_user": 3}, failures)
+
+	// Failures with an exemplar in the range keep its trace, the other one gets a synthetic trace
+	assert.Contains(t, failedTraces, "trace-a")
+	assert.Contains(t, failedTraces, "trace-b")
+	assert.NotContains(t, failedTraces, "trace-c")
+	for _, span := range spans {
+		if span.TraceID == "trace-a" {
+			assert.Equal(t, end.Add(-10*time.Minute), span.EndTime)
+		}
+	}
+}
+
+func TestPrometheusAdapter_QuerySpansLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		switch {
+		case strings.Contains(query, "_count"):
+			_, _ = w.Write(prometheusVector(map[string]string{"fetch_user": "100", "notify": "100"}))
+		case strings.Contains(query, "_sum"):
+			_, _ = w.Write(prometheusVector(map[string]string{"fetch_user": "100", "notify": "100"}))
+		default:
+			_, _ = w.Write(prometheusVector(map[string]string{"notify": "50"}))
+		}
+	}))
+	defer server.Close()
+
+	adapter, err := NewPrometheusAdapter(server.URL, "", "", 5*time.Second)
+	require.NoError(t, err)
+
+	end := time.Now()
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{
+		TimeRange: telemetry.TimeRange{Start: end.Add(-time.Hour), End: end},
+		Limit:     20,
+	})
+	require.NoError(t, err)
+
+	// Both tasks are scaled down alike, keeping notify's 50% error rate
+	require.Len(t, spans, 20)
+	failures := 0
+	for _, span := range spans {
+		if !span.Status {
+			assert.Equal(t, "notify", span.TaskName)
+			failures++
+		}
+	}
+	assert.Equal(t, 5, failures)
+}
+
+func TestPrometheusAdapter_QuerySpansDefaultLimit(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		switch {
+		case r.URL.Path == "/api/v1/query_exemplars":
+			// Exemplar storage disabled
+			w.WriteHeader(http.StatusNotFound)
+		case strings.Contains(query, "_count"), strings.Contains(query, "_sum"):
+			_, _ = w.Write(prometheusVector(map[string]string{"fetch_user": "4000"}))
+		default:
+			_, _ = w.Write(prometheusVector(map[string]string{"fetch_user": "400"}))
+		}
+	}))
+	defer server.Close()
+
+	adapter, err := NewPrometheusAdapter(server.URL, "", "", 5*time.Second)
+	require.NoError(t, err)
+
+	end := time.Now()
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{
+		TimeRange: telemetry.TimeRange{Start: end.Add(-time.Hour), End: end},
+	})
+	require.NoError(t, err)
+	require.Len(t, spans, DefaultPrometheusSpanLimit)
+
+	// Without exemplars the failures are spread across the range, not bunched on the oldest spans
+	failures := 0
+	for _, span := range spans {
+		if !span.Status {
+			failures++
+		}
+	}
+	assert.Equal(t, 100, failures)
+	newestFailures := 0
+	for _, span := range spans[:len(spans)/2] {
+		if !span.Status {
+			newestFailures++
+		}
+	}
+	assert.Equal(t, 50, newestFailures)
+}
+
+func TestPrometheusAdapter_QuerySpansTraceID(t *testing.T) {
+	adapter, err := NewPrometheusAdapter("http://prometheus:9090", "", "", 5*time.Second)
+	require.NoError(t, err)
+
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{
+		TraceID:   "abc123",
+		TimeRange: telemetry.TimeRange{Start: time.Now().Add(-time.Hour), End: time.Now()},
+	})
+	require.NoError(t, err)
+	assert.Empty(t, spans)
+}
+
+func TestPrometheusAdapter_QueryMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/query_range", r.URL.Path)
+		assert.Equal(t, `avg(langop_task_duration_seconds_sum{task_name="fetch_user"})`, r.URL.Query().Get("query"))
+		_, _ = w.Write([]byte(`{"status":"success","data":{"resultType":"matrix","result":[
+			{"metric":{"task_name":"fetch_user"},"values":[[1700000000,"1.5"],[1700000060,"2.5"]]}]}}`))
+	}))
+	defer server.Close()
+
+	adapter, err := NewPrometheusAdapter(server.URL, "", "", 5*time.Second)
+	require.NoError(t, err)
+
+	metrics, err := adapter.QueryMetrics(context.Background(), telemetry.MetricFilter{
+		MetricName:  "langop_task_duration_seconds_sum",
+		Labels:      map[string]string{"task_name": "fetch_user"},
+		Aggregation: "avg",
+		TimeRange:   telemetry.TimeRange{Start: time.Unix(1700000000, 0), End: time.Unix(1700000060, 0)},
+	})
+	require.NoError(t, err)
+	require.Len(t, metrics, 2)
+	assert.Equal(t, 2.5, metrics[0].Value)
+	assert.Equal(t, time.Unix(1700000060, 0), metrics[0].Time)
+}
+
+func TestPrometheusAdapter_Available(t *testing.T) {
+	var mu sync.Mutex
+	ready := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/-/ready", r.URL.Path)
+		mu.Lock()
+		defer mu.Unlock()
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	adapter, err := NewPrometheusAdapter(server.URL, "", "", 5*time.Second)
+	require.NoError(t, err)
+	now := time.Now()
+	adapter.availabilityCache.timeNow = func() time.Time { return now }
+
+	assert.True(t, adapter.Available())
+
+	// Cached until the TTL expires
+	mu.Lock()
+	ready = false
+	mu.Unlock()
+	assert.True(t, adapter.Available())
+	now = now.Add(time.Minute)
+	assert.False(t, adapter.Available())
+}
@@ -0,0 +1,758 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+const (
+	// tempoSearchPageSize is the number of traces requested per /api/search call
+	tempoSearchPageSize = 100
+
+	// tempoSpansPerSpanSet raises Tempo's default of 3 matched spans returned per trace
+	tempoSpansPerSpanSet = 100
+
+	// tempoMaxSearchPages bounds pagination when the filter has no limit
+	tempoMaxSearchPages = 10
+
+	// tempoMaxConcurrentFetches bounds the full traces fetched from Tempo at once
+	tempoMaxConcurrentFetches = 8
+)
+
+// tempoAttributeKey matches attribute names usable unquoted in TraceQL
+var tempoAttributeKey = regexp.MustCompile(`^[A-Za-z0-9_.\-]+$`)
+
+// TempoAdapter implements TelemetryAdapter for Grafana Tempo.
+//
+// Spans are found with a TraceQL query against /api/search, built from the span filter:
+// TaskName and Attributes become attribute equality conditions
+// ({ .agent.name = "x" && .task.name = "y" }), matching span or resource attributes. Tempo's
+// search returns at most a page of traces per call, so older pages are requested by moving the
+// end of the time range back to the oldest trace returned. Search results only carry a subset of
+// each span, so every matched trace is then fetched from /api/traces to resolve parent spans,
+// status, and events. Spans of traces that can't be fetched are returned with the data from the
+// search result alone.
+//
+// Tempo stores traces only, so QueryMetrics returns no data points.
+//
+// Example usage:
+//
+//	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: "http://tempo.monitoring:3200"})
+//	spans, err := adapter.QuerySpans(ctx, telemetry.SpanFilter{
+//	  TaskName:   "fetch_user",
+//	  Attributes: map[string]string{"agent.name": "reviewer"},
+//	  TimeRange:  telemetry.TimeRange{Start: yesterday, End: now},
+//	  Limit:      100,
+//	})
+type TempoAdapter struct {
+	// endpoint is the base URL of the Tempo HTTP API
+	// Example: "http://tempo.monitoring.svc:3200"
+	endpoint string
+
+	// tenantID is sent as X-Scope-OrgID to multi-tenant Tempo deployments
+	tenantID string
+
+	// username and password are sent as HTTP basic auth when username is set
+	username string
+	password string
+
+	// apiKey is sent as a bearer token when set
+	apiKey string
+
+	// httpClient is the HTTP client for making requests
+	httpClient *http.Client
+
+	// maxResponseSize is the maximum allowed size for HTTP response bodies
+	maxResponseSize int64
+
+	// availabilityCache caches the result of Available() checks
+	// to avoid frequent health checks
+	availabilityCache struct {
+		sync.RWMutex
+		value     bool
+		timestamp time.Time
+		ttl       time.Duration
+		timeNow   func() time.Time // Injectable for testing
+	}
+}
+
+// TempoConfig contains configuration options for TempoAdapter.
+type TempoConfig struct {
+	// Endpoint is the base URL of the Tempo HTTP API
+	Endpoint string
+
+	// TenantID is the tenant of multi-tenant Tempo deployments, sent as X-Scope-OrgID
+	TenantID string
+
+	// Username and Password are optional basic auth credentials, e.g. for Grafana Cloud
+	Username string
+	Password string
+
+	// APIKey is an optional bearer token
+	APIKey string
+
+	// Timeout is the HTTP request timeout
+	// Defaults to 30 seconds if not specified
+	Timeout time.Duration
+
+	// MaxResponseSize is the maximum allowed size for HTTP response bodies
+	// Defaults to 50MB (50 * 1024 * 1024 bytes) if not specified
+	MaxResponseSize int64
+}
+
+// NewTempoAdapter creates a new TempoAdapter.
+//
+// Returns error if the endpoint is not a valid http(s) URL or the timeout or response size
+// limit is negative.
+func NewTempoAdapter(config TempoConfig) (*TempoAdapter, error) {
+	if config.Endpoint == "" {
+		return nil, fmt.Errorf("endpoint cannot be empty")
+	}
+
+	timeout := config.Timeout
+	if timeout == 0 {
+		timeout = 30 * time.Second
+	}
+	if timeout < 0 {
+		return nil, fmt.Errorf("timeout must be positive, got %v", timeout)
+	}
+
+	maxResponseSize := config.MaxResponseSize
+	if maxResponseSize == 0 {
+		maxResponseSize = DefaultMaxResponseSize
+	}
+	if maxResponseSize < 0 {
+		return nil, fmt.Errorf("maxResponseSize must be positive, got %d", maxResponseSize)
+	}
+
+	parsedURL, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid endpoint URL: %w", err)
+	}
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return nil, fmt.Errorf("endpoint URL scheme must be http or https, got: %q", parsedURL.Scheme)
+	}
+	if parsedURL.Host == "" {
+		return nil, fmt.Errorf("endpoint URL must include host: %s", config.Endpoint)
+	}
+	if err := validateHost(parsedURL.Host); err != nil {
+		return nil, fmt.Errorf("invalid endpoint host: %w", err)
+	}
+
+	adapter := &TempoAdapter{
+		endpoint:        strings.TrimSuffix(config.Endpoint, "/"),
+		tenantID:        config.TenantID,
+		username:        config.Username,
+		password:        config.Password,
+		apiKey:          config.APIKey,
+		maxResponseSize: maxResponseSize,
+		httpClient: &http.Client{
+			Timeout: timeout,
+			Transport: &http.Transport{
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+				MaxIdleConnsPerHost: 2,
+			},
+		},
+	}
+
+	adapter.availabilityCache.ttl = 30 * time.Second
+	adapter.availabilityCache.timeNow = time.Now
+
+	return adapter, nil
+}
+
+// get performs a GET request against the Tempo HTTP API and returns the response body
+func (t *TempoAdapter) get(ctx context.Context, path string, params url.Values) ([]byte, int, error) {
+	reqURL := t.endpoint + path
+	if len(params) > 0 {
+		reqURL += "?" + params.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if t.tenantID != "" {
+		req.Header.Set("X-Scope-OrgID", t.tenantID)
+	}
+	if t.username != "" {
+		req.SetBasicAuth(t.username, t.password)
+	} else if t.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+t.apiKey)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, t.maxResponseSize+1))
+	if err != nil {
+		return nil, resp.StatusCode, fmt.Errorf("failed to read response body: %w", err)
+	}
+	if int64(len(body)) > t.maxResponseSize {
+		return nil, resp.StatusCode, fmt.Errorf("response body exceeds maximum allowed size of %d bytes", t.maxResponseSize)
+	}
+
+	if resp.StatusCode >= 400 {
+		return nil, resp.StatusCode, fmt.Errorf("Tempo API error: %d %s, body: %s", resp.StatusCode, resp.Status, string(body))
+	}
+
+	return body, resp.StatusCode, nil
+}
+
+// tempoAttribute is an OTLP key-value attribute as Tempo encodes it in JSON
+type tempoAttribute struct {
+	Key   string `json:"key"`
+	Value struct {
+		StringValue *string     `json:"stringValue"`
+		IntValue    interface{} `json:"intValue"`
+		DoubleValue interface{} `json:"doubleValue"`
+		BoolValue   *bool       `json:"boolValue"`
+	} `json:"value"`
+}
+
+// tempoSearchSpan is a matched span of a search result
+type tempoSearchSpan struct {
+	SpanID            string           `json:"spanID"`
+	Name              string           `json:"name"`
+	StartTimeUnixNano string           `json:"startTimeUnixNano"`
+	DurationNanos     string           `json:"durationNanos"`
+	Attributes        []tempoAttribute `json:"attributes"`
+}
+
+// tempoSpanSet is the set of spans of a trace matching the query
+type tempoSpanSet struct {
+	Spans   []tempoSearchSpan `json:"spans"`
+	Matched int               `json:"matched"`
+}
+
+// tempoSearchTrace is one trace of a search result. Tempo 2.2 and later return every matching
+// span set in SpanSets and keep SpanSet for compatibility.
+type tempoSearchTrace struct {
+	TraceID           string         `json:"traceID"`
+	RootServiceName   string         `json:"rootServiceName"`
+	RootTraceName     string         `json:"rootTraceName"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	SpanSet           *tempoSpanSet  `json:"spanSet"`
+	SpanSets          []tempoSpanSet `json:"spanSets"`
+}
+
+// matchedSpans returns the matched spans of the trace across its span sets, without duplicates
+func (tr tempoSearchTrace) matchedSpans() []tempoSearchSpan {
+	sets := tr.SpanSets
+	if len(sets) == 0 && tr.SpanSet != nil {
+		sets = []tempoSpanSet{*tr.SpanSet}
+	}
+	seen := map[string]bool{}
+	var spans []tempoSearchSpan
+	for _, set := range sets {
+		for _, span := range set.Spans {
+			id := normalizeTempoID(span.SpanID, 8)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			spans = append(spans, span)
+		}
+	}
+	return spans
+}
+
+// tempoTraceSpan is a span of a full trace in OTLP JSON
+type tempoTraceSpan struct {
+	TraceID           string           `json:"traceId"`
+	SpanID            string           `json:"spanId"`
+	ParentSpanID      string           `json:"parentSpanId"`
+	Name              string           `json:"name"`
+	StartTimeUnixNano string           `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string           `json:"endTimeUnixNano"`
+	Attributes        []tempoAttribute `json:"attributes"`
+	Status            struct {
+		Code    interface{} `json:"code"`
+		Message string      `json:"message"`
+	} `json:"status"`
+	Events []struct {
+		TimeUnixNano string           `json:"timeUnixNano"`
+		Name         string           `json:"name"`
+		Attributes   []tempoAttribute `json:"attributes"`
+	} `json:"events"`
+}
+
+// tempoScopeSpans groups spans by instrumentation scope. Older Tempo versions name it
+// instrumentationLibrarySpans.
+type tempoScopeSpans struct {
+	Spans []tempoTraceSpan `json:"spans"`
+}
+
+// tempoResourceSpans groups spans by the resource that emitted them
+type tempoResourceSpans struct {
+	Resource struct {
+		Attributes []tempoAttribute `json:"attributes"`
+	} `json:"resource"`
+	ScopeSpans                  []tempoScopeSpans `json:"scopeSpans"`
+	InstrumentationLibrarySpans []tempoScopeSpans `json:"instrumentationLibrarySpans"`
+}
+
+// tempoTrace is a full trace as returned by /api/traces/<id> ("batches") or the v2 API
+// ("trace.resourceSpans")
+type tempoTrace struct {
+	Batches       []tempoResourceSpans `json:"batches"`
+	ResourceSpans []tempoResourceSpans `json:"resourceSpans"`
+	Trace         *struct {
+		ResourceSpans []tempoResourceSpans `json:"resourceSpans"`
+	} `json:"trace"`
+}
+
+// QuerySpans retrieves spans matching the filter with a TraceQL search.
+//
+// See the TempoAdapter doc comment for how the query is built and paginated.
+// Returns spans ordered by timestamp (newest first) up to filter.Limit.
+func (t *TempoAdapter) QuerySpans(ctx context.Context, filter telemetry.SpanFilter) ([]telemetry.Span, error) {
+	// A single trace is fetched directly instead of searched for
+	if filter.TraceID != "" {
+		spans, found, err := t.fetchTrace(ctx, filter.TraceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch trace %s: %w", filter.TraceID, err)
+		}
+		if !found {
+			return []telemetry.Span{}, nil
+		}
+		matching := make([]telemetry.Span, 0, len(spans))
+		for _, span := range spans {
+			if tempoSpanMatches(span, filter) {
+				matching = append(matching, span)
+			}
+		}
+		return limitSpans(matching, filter.Limit), nil
+	}
+
+	traces, err := t.search(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the traces holding the first filter.Limit matched spans are fetched in full
+	if filter.Limit > 0 {
+		matched := 0
+		for i, trace := range traces {
+			if matched += len(trace.matchedSpans()); matched >= filter.Limit {
+				traces = traces[:i+1]
+				break
+			}
+		}
+	}
+
+	fetched := t.fetchTraces(ctx, traces)
+	if ctx.Err() != nil {
+		return nil, fmt.Errorf("failed to fetch traces: %w", ctx.Err())
+	}
+
+	spans := make([]telemetry.Span, 0)
+	for i, trace := range traces {
+		for _, match := range trace.matchedSpans() {
+			if span, ok := fetched[i][normalizeTempoID(match.SpanID, 8)]; ok {
+				spans = append(spans, span)
+				continue
+			}
+			spans = append(spans, convertTempoSearchSpan(trace, match))
+		}
+	}
+
+	return limitSpans(spans, filter.Limit), nil
+}
+
+// fetchTraces fetches the full traces with matched spans, at most tempoMaxConcurrentFetches at a
+// time, and returns the spans of each trace by span ID. Traces that couldn't be fetched are left
+// empty, so their matched spans fall back to the search results.
+func (t *TempoAdapter) fetchTraces(ctx context.Context, traces []tempoSearchTrace) []map[string]telemetry.Span {
+	fetched := make([]map[string]telemetry.Span, len(traces))
+	slots := make(chan struct{}, tempoMaxConcurrentFetches)
+	var wg sync.WaitGroup
+	for i, trace := range traces {
+		if len(trace.matchedSpans()) == 0 {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, traceID string) {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-slots }()
+
+			full, found, err := t.fetchTrace(ctx, traceID)
+			if err != nil || !found {
+				return
+			}
+			byID := make(map[string]telemetry.Span, len(full))
+			for _, span := range full {
+				byID[span.SpanID] = span
+			}
+			fetched[i] = byID
+		}(i, trace.TraceID)
+	}
+	wg.Wait()
+	return fetched
+}
+
+// search pages through /api/search results, newest first, until enough spans were matched
+func (t *TempoAdapter) search(ctx context.Context, filter telemetry.SpanFilter) ([]tempoSearchTrace, error) {
+	query := buildTraceQL(filter)
+	start := filter.TimeRange.Start
+	end := filter.TimeRange.End
+
+	seen := map[string]bool{}
+	var traces []tempoSearchTrace
+	matched := 0
+	for page := 0; page < tempoMaxSearchPages; page++ {
+		params := url.Values{}
+		params.Set("q", query)
+		params.Set("limit", strconv.Itoa(tempoSearchPageSize))
+		params.Set("spss", strconv.Itoa(tempoSpansPerSpanSet))
+		if !start.IsZero() {
+			params.Set("start", strconv.FormatInt(start.Unix(), 10))
+		}
+		if !end.IsZero() {
+			params.Set("end", strconv.FormatInt(end.Unix(), 10))
+		}
+
+		body, _, err := t.get(ctx, "/api/search", params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search Tempo: %w", err)
+		}
+		var response struct {
+			Traces []tempoSearchTrace `json:"traces"`
+		}
+		if err := json.Unmarshal(body, &response); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal search response: %w", err)
+		}
+
+		added := 0
+		oldest := end
+		for _, trace := range response.Traces {
+			id := normalizeTempoID(trace.TraceID, 16)
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			added++
+			traces = append(traces, trace)
+			matched += len(trace.matchedSpans())
+			if traceStart := parseUnixNano(trace.StartTimeUnixNano); !traceStart.IsZero() && (oldest.IsZero() || traceStart.Before(oldest)) {
+				oldest = traceStart
+			}
+		}
+
+		// Tempo has no cursor: the next page ends where the oldest trace of this one started.
+		// Traces starting in the same second are returned again and skipped.
+		if len(response.Traces) < tempoSearchPageSize || added == 0 || oldest.IsZero() ||
+			(filter.Limit > 0 && matched >= filter.Limit) {
+			break
+		}
+		end = oldest
+	}
+	return traces, nil
+}
+
+// fetchTrace retrieves every span of a trace. found is false when Tempo doesn't know the trace.
+func (t *TempoAdapter) fetchTrace(ctx context.Context, traceID string) ([]telemetry.Span, bool, error) {
+	body, status, err := t.get(ctx, "/api/traces/"+url.PathEscape(normalizeTempoID(traceID, 16)), nil)
+	if status == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	var trace tempoTrace
+	if err := json.Unmarshal(body, &trace); err != nil {
+		return nil, false, fmt.Errorf("failed to unmarshal trace: %w", err)
+	}
+	return convertTempoTrace(trace), true, nil
+}
+
+// convertTempoTrace converts the spans of a full trace. Resource attributes such as
+// service.name are included in each span's attributes, with span attributes taking precedence.
+func convertTempoTrace(trace tempoTrace) []telemetry.Span {
+	resourceSpans := trace.Batches
+	resourceSpans = append(resourceSpans, trace.ResourceSpans...)
+	if trace.Trace != nil {
+		resourceSpans = append(resourceSpans, trace.Trace.ResourceSpans...)
+	}
+
+	var spans []telemetry.Span
+	for _, resource := range resourceSpans {
+		resourceAttributes := tempoAttributes(resource.Resource.Attributes)
+		scopes := append(resource.ScopeSpans, resource.InstrumentationLibrarySpans...)
+		for _, scope := range scopes {
+			for _, raw := range scope.Spans {
+				attributes := make(map[string]string, len(resourceAttributes)+len(raw.Attributes))
+				for key, value := range resourceAttributes {
+					attributes[key] = value
+				}
+				for key, value := range tempoAttributes(raw.Attributes) {
+					attributes[key] = value
+				}
+
+				start := parseUnixNano(raw.StartTimeUnixNano)
+				end := parseUnixNano(raw.EndTimeUnixNano)
+				span := telemetry.Span{
+					SpanID:        normalizeTempoID(raw.SpanID, 8),
+					TraceID:       normalizeTempoID(raw.TraceID, 16),
+					ParentSpanID:  normalizeTempoID(raw.ParentSpanID, 8),
+					OperationName: raw.Name,
+					TaskName:      tempoTaskName(raw.Name, attributes),
+					StartTime:     start,
+					EndTime:       end,
+					Duration:      end.Sub(start),
+					Status:        !tempoStatusIsError(raw.Status.Code),
+					Attributes:    attributes,
+				}
+				if !span.Status {
+					span.ErrorMessage = raw.Status.Message
+				}
+				for _, event := range raw.Events {
+					span.Events = append(span.Events, telemetry.SpanEvent{
+						Time:       parseUnixNano(event.TimeUnixNano),
+						Name:       event.Name,
+						Attributes: tempoAttributes(event.Attributes),
+					})
+				}
+				spans = append(spans, span)
+			}
+		}
+	}
+	return spans
+}
+
+// convertTempoSearchSpan converts a matched span of a search result. Search results don't
+// carry the parent, status, or events of a span.
+func convertTempoSearchSpan(trace tempoSearchTrace, match tempoSearchSpan) telemetry.Span {
+	attributes := tempoAttributes(match.Attributes)
+	if _, ok := attributes["service.name"]; !ok && trace.RootServiceName != "" {
+		attributes["service.name"] = trace.RootServiceName
+	}
+	start := parseUnixNano(match.StartTimeUnixNano)
+	durationNanos, _ := strconv.ParseInt(match.DurationNanos, 10, 64)
+	duration := time.Duration(durationNanos)
+	return telemetry.Span{
+		SpanID:        normalizeTempoID(match.SpanID, 8),
+		TraceID:       normalizeTempoID(trace.TraceID, 16),
+		OperationName: match.Name,
+		TaskName:      tempoTaskName(match.Name, attributes),
+		StartTime:     start,
+		EndTime:       start.Add(duration),
+		Duration:      duration,
+		Status:        true,
+		Attributes:    attributes,
+	}
+}
+
+// buildTraceQL renders the span filter as a TraceQL query. Unscoped attributes match span and
+// resource attributes alike.
+func buildTraceQL(filter telemetry.SpanFilter) string {
+	conditions := make(map[string]string, len(filter.Attributes)+1)
+	for key, value := range filter.Attributes {
+		conditions[key] = value
+	}
+	if filter.TaskName != "" {
+		conditions["task.name"] = filter.TaskName
+	}
+	if len(conditions) == 0 {
+		return "{}"
+	}
+
+	keys := make([]string, 0, len(conditions))
+	for key := range conditions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	clauses := make([]string, 0, len(keys))
+	for _, key := range keys {
+		clauses = append(clauses, fmt.Sprintf("%s = %s", traceQLAttribute(key), strconv.Quote(conditions[key])))
+	}
+	return "{ " + strings.Join(clauses, " && ") + " }"
+}
+
+// traceQLAttribute renders an attribute reference, quoting names TraceQL can't parse bare
+func traceQLAttribute(key string) string {
+	if tempoAttributeKey.MatchString(key) {
+		return "." + key
+	}
+	return "." + strconv.Quote(key)
+}
+
+// tempoSpanMatches reports whether a span satisfies the task and attribute conditions of the filter
+func tempoSpanMatches(span telemetry.Span, filter telemetry.SpanFilter) bool {
+	if filter.TaskName != "" && span.TaskName != filter.TaskName {
+		return false
+	}
+	for key, value := range filter.Attributes {
+		if span.Attributes[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// tempoTaskName returns the task a span executed, falling back to its operation name
+func tempoTaskName(operationName string, attributes map[string]string) string {
+	if taskName := attributes["task.name"]; taskName != "" {
+		return taskName
+	}
+	return operationName
+}
+
+// tempoStatusIsError reports whether an OTLP status code, encoded as a name or a number, is an error
+func tempoStatusIsError(code interface{}) bool {
+	switch c := code.(type) {
+	case string:
+		return c == "STATUS_CODE_ERROR" || c == "2"
+	case float64:
+		return c == 2
+	default:
+		return false
+	}
+}
+
+// tempoAttributes flattens OTLP attributes to strings
+func tempoAttributes(raw []tempoAttribute) map[string]string {
+	attributes := make(map[string]string, len(raw))
+	for _, attribute := range raw {
+		value := attribute.Value
+		switch {
+		case value.StringValue != nil:
+			attributes[attribute.Key] = *value.StringValue
+		case value.IntValue != nil:
+			attributes[attribute.Key] = tempoNumber(value.IntValue)
+		case value.DoubleValue != nil:
+			attributes[attribute.Key] = tempoNumber(value.DoubleValue)
+		case value.BoolValue != nil:
+			attributes[attribute.Key] = strconv.FormatBool(*value.BoolValue)
+		}
+	}
+	return attributes
+}
+
+// tempoNumber renders a number that OTLP JSON may encode as a string or a JSON number
+func tempoNumber(raw interface{}) string {
+	switch v := raw.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// normalizeTempoID returns a trace or span ID of size bytes as lowercase hex. Search results
+// use hex with leading zeros trimmed, full traces use base64. Base64 IDs of 8 or 16 bytes always
+// end in padding, so they are never mistaken for hex.
+func normalizeTempoID(id string, size int) string {
+	if id == "" {
+		return ""
+	}
+	if _, err := hex.DecodeString(padHex(id)); err == nil && len(id) <= 2*size {
+		return strings.ToLower(strings.Repeat("0", 2*size-len(id)) + id)
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(id); err == nil {
+		return hex.EncodeToString(decoded)
+	}
+	return strings.ToLower(id)
+}
+
+// padHex pads an odd-length hex string so it decodes
+func padHex(id string) string {
+	if len(id)%2 == 1 {
+		return "0" + id
+	}
+	return id
+}
+
+// parseUnixNano parses a nanosecond Unix timestamp encoded as a string
+func parseUnixNano(raw string) time.Time {
+	nanos, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil || nanos <= 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// limitSpans orders spans newest first and keeps at most limit of them
+func limitSpans(spans []telemetry.Span, limit int) []telemetry.Span {
+	sort.SliceStable(spans, func(i, j int) bool { return spans[i].StartTime.After(spans[j].StartTime) })
+	if limit > 0 && len(spans) > limit {
+		spans = spans[:limit]
+	}
+	return spans
+}
+
+// QueryMetrics returns no data points: Tempo stores traces only.
+func (t *TempoAdapter) QueryMetrics(ctx context.Context, filter telemetry.MetricFilter) ([]telemetry.MetricPoint, error) {
+	return []telemetry.MetricPoint{}, nil
+}
+
+// Available returns true if Tempo reports ready.
+//
+// Uses caching to avoid frequent health checks (30 second TTL).
+func (t *TempoAdapter) Available() bool {
+	now := t.availabilityCache.timeNow()
+
+	t.availabilityCache.RLock()
+	if now.Sub(t.availabilityCache.timestamp) < t.availabilityCache.ttl {
+		value := t.availabilityCache.value
+		t.availabilityCache.RUnlock()
+		return value
+	}
+	t.availabilityCache.RUnlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, _, err := t.get(ctx, "/ready", nil)
+	available := err == nil
+
+	t.availabilityCache.Lock()
+	t.availabilityCache.value = available
+	t.availabilityCache.timestamp = now
+	t.availabilityCache.Unlock()
+
+	return available
+}
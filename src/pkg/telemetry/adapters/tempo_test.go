@@ -0,0 +1,496 @@
+/*
+Copyright 2025 Langop Team.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package adapters
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/language-operator/language-operator/pkg/telemetry"
+)
+
+func TestNewTempoAdapter(t *testing.T) {
+	t.Run("Valid configuration", func(t *testing.T) {
+		adapter, err := NewTempoAdapter(TempoConfig{Endpoint: "http://tempo.monitoring:3200/", TenantID: "team-a"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "http://tempo.monitoring:3200", adapter.endpoint)
+		assert.Equal(t, "team-a", adapter.tenantID)
+		assert.Equal(t, 30*time.Second, adapter.httpClient.Timeout)
+		assert.Equal(t, int64(DefaultMaxResponseSize), adapter.maxResponseSize)
+	})
+
+	t.Run("Empty endpoint", func(t *testing.T) {
+		_, err := NewTempoAdapter(TempoConfig{})
+		assert.ErrorContains(t, err, "endpoint cannot be empty")
+	})
+
+	t.Run("Unsupported scheme", func(t *testing.T) {
+		_, err := NewTempoAdapter(TempoConfig{Endpoint: "ftp://tempo:3200"})
+		assert.ErrorContains(t, err, "scheme must be http or https")
+	})
+
+	t.Run("Negative timeout", func(t *testing.T) {
+		_, err := NewTempoAdapter(TempoConfig{Endpoint: "http://tempo:3200", Timeout: -time.Second})
+		assert.ErrorContains(t, err, "timeout must be positive")
+	})
+}
+
+func TestBuildTraceQL(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   telemetry.SpanFilter
+		expected string
+	}{
+		{name: "no conditions", expected: "{}"},
+		{
+			name:     "task name",
+			filter:   telemetry.SpanFilter{TaskName: "fetch_user"},
+			expected: `{ .task.name = "fetch_user" }`,
+		},
+		{
+			name: "attributes in key order",
+			filter: telemetry.SpanFilter{
+				TaskName:   "fetch_user",
+				Attributes: map[string]string{"agent.name": "reviewer", "agent.namespace": "default"},
+			},
+			expected: `{ .agent.name = "reviewer" && .agent.namespace = "default" && .task.name = "fetch_user" }`,
+		},
+		{
+			name:     "quoted values and keys",
+			filter:   telemetry.SpanFilter{Attributes: map[string]string{"http route": `/users/"me"`}},
+			expected: `{ ."http route" = "/users/\"me\"" }`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildTraceQL(tt.filter))
+		})
+	}
+}
+
+// tempoString renders an OTLP string attribute
+func tempoString(key, value string) map[string]interface{} {
+	return map[string]interface{}{"key": key, "value": map[string]interface{}{"stringValue": value}}
+}
+
+// tempoSearchResponse renders a search response with one span set per trace
+func tempoSearchResponse(traces ...map[string]interface{}) []byte {
+	body, _ := json.Marshal(map[string]interface{}{"traces": traces})
+	return body
+}
+
+func TestTempoAdapter_QuerySpans(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+
+	// Full trace in OTLP JSON with base64 IDs: a root span and a failed child task
+	trace := map[string]interface{}{
+		"batches": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{"attributes": []interface{}{
+				tempoString("service.name", "reviewer"),
+				tempoString("agent.name", "reviewer"),
+			}},
+			"scopeSpans": []interface{}{map[string]interface{}{"spans": []interface{}{
+				map[string]interface{}{
+					"traceId":           "AAAAAAAAAAAAAAAAAAAAAQ==",
+					"spanId":            "AAAAAAAAAAE=",
+					"name":              "agent.run",
+					"startTimeUnixNano": "1700000000000000000",
+					"endTimeUnixNano":   "1700000002000000000",
+					"status":            map[string]interface{}{},
+				},
+				map[string]interface{}{
+					"traceId":           "AAAAAAAAAAAAAAAAAAAAAQ==",
+					"spanId":            "AAAAAAAAAAI=",
+					"parentSpanId":      "AAAAAAAAAAE=",
+					"name":              "task",
+					"startTimeUnixNano": "1700000000500000000",
+					"endTimeUnixNano":   "1700000001500000000",
+					"attributes": []interface{}{
+						tempoString("task.name", "fetch_user"),
+						map[string]interface{}{"key": "retries", "value": map[string]interface{}{"intValue": "2"}},
+					},
+					"status": map[string]interface{}{"code": "STATUS_CODE_ERROR", "message": "user not found"},
+					"events": []interface{}{map[string]interface{}{
+						"timeUnixNano": "1700000001000000000",
+						"name":         "exception",
+						"attributes":   []interface{}{tempoString("exception.type", "NotFound")},
+					}},
+				},
+			}}},
+		}},
+	}
+
+	var mu sync.Mutex
+	var queries []string
+	var tenants []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tenants = append(tenants, r.Header.Get("X-Scope-OrgID"))
+		mu.Unlock()
+
+		switch {
+		case r.URL.Path == "/api/search":
+			mu.Lock()
+			queries = append(queries, r.URL.Query().Get("q"))
+			mu.Unlock()
+			assert.Equal(t, "1699996400", r.URL.Query().Get("start"))
+			assert.Equal(t, "1700003600", r.URL.Query().Get("end"))
+			_, _ = w.Write(tempoSearchResponse(map[string]interface{}{
+				"traceID":           "1",
+				"rootServiceName":   "reviewer",
+				"startTimeUnixNano": "1700000000000000000",
+				"spanSets": []interface{}{map[string]interface{}{
+					"matched": 1,
+					"spans": []interface{}{map[string]interface{}{
+						"spanID":            "2",
+						"name":              "task",
+						"startTimeUnixNano": "1700000000500000000",
+						"durationNanos":     "1000000000",
+					}},
+				}},
+			}))
+		case r.URL.Path == "/api/traces/00000000000000000000000000000001":
+			body, _ := json.Marshal(trace)
+			_, _ = w.Write(body)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL, TenantID: "team-a"})
+	require.NoError(t, err)
+
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{
+		TaskName:   "fetch_user",
+		Attributes: map[string]string{"agent.name": "reviewer"},
+		TimeRange:  telemetry.TimeRange{Start: start.Add(-time.Hour), End: start.Add(time.Hour)},
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+
+	assert.Equal(t, []string{`{ .agent.name = "reviewer" && .task.name = "fetch_user" }`}, queries)
+	for _, tenant := range tenants {
+		assert.Equal(t, "team-a", tenant)
+	}
+
+	span := spans[0]
+	assert.Equal(t, "00000000000000000000000000000001", span.TraceID)
+	assert.Equal(t, "0000000000000002", span.SpanID)
+	assert.Equal(t, "0000000000000001", span.ParentSpanID)
+	assert.Equal(t, "task", span.OperationName)
+	assert.Equal(t, "fetch_user", span.TaskName)
+	assert.Equal(t, time.Second, span.Duration)
+	assert.False(t, span.Status)
+	assert.Equal(t, "user not found", span.ErrorMessage)
+	assert.Equal(t, "reviewer", span.Attributes["service.name"])
+	assert.Equal(t, "2", span.Attributes["retries"])
+	require.Len(t, span.Events, 1)
+	assert.Equal(t, "exception", span.Events[0].Name)
+	assert.Equal(t, "NotFound", span.Events[0].Attributes["exception.type"])
+}
+
+func TestTempoAdapter_QuerySpans_SearchResultFallback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		// Pre-2.2 Tempo returns a single spanSet per trace
+		_, _ = w.Write(tempoSearchResponse(map[string]interface{}{
+			"traceID":           "abc",
+			"rootServiceName":   "reviewer",
+			"startTimeUnixNano": "1700000000000000000",
+			"spanSet": map[string]interface{}{
+				"matched": 1,
+				"spans": []interface{}{map[string]interface{}{
+					"spanID":            "def",
+					"name":              "task",
+					"startTimeUnixNano": "1700000000000000000",
+					"durationNanos":     "250000000",
+					"attributes":        []interface{}{tempoString("task.name", "summarize")},
+				}},
+			},
+		}))
+	}))
+	defer server.Close()
+
+	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL})
+	require.NoError(t, err)
+
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{TaskName: "summarize"})
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	assert.Equal(t, "00000000000000000000000000000abc", spans[0].TraceID)
+	assert.Equal(t, "0000000000000def", spans[0].SpanID)
+	assert.Equal(t, "summarize", spans[0].TaskName)
+	assert.Equal(t, 250*time.Millisecond, spans[0].Duration)
+	assert.Equal(t, "reviewer", spans[0].Attributes["service.name"])
+	assert.True(t, spans[0].Status)
+}
+
+func TestTempoAdapter_QuerySpans_Pagination(t *testing.T) {
+	// Two full pages followed by a partial one, each page older than the last
+	var mu sync.Mutex
+	var ends []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		mu.Lock()
+		page := len(ends)
+		ends = append(ends, r.URL.Query().Get("end"))
+		mu.Unlock()
+
+		count := tempoSearchPageSize
+		if page == 2 {
+			count = 5
+		}
+		traces := make([]map[string]interface{}, 0, count)
+		for i := 0; i < count; i++ {
+			n := page*tempoSearchPageSize + i + 1
+			started := time.Unix(1700000000-int64(n), 0)
+			traces = append(traces, map[string]interface{}{
+				"traceID":           fmt.Sprintf("%x", n),
+				"startTimeUnixNano": unixNanoString(started),
+				"spanSets": []interface{}{map[string]interface{}{"spans": []interface{}{map[string]interface{}{
+					"spanID":            "1",
+					"name":              "task",
+					"startTimeUnixNano": unixNanoString(started),
+					"durationNanos":     "1",
+				}}}},
+			})
+		}
+		_, _ = w.Write(tempoSearchResponse(traces...))
+	}))
+	defer server.Close()
+
+	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL})
+	require.NoError(t, err)
+
+	end := time.Unix(1700000000, 0)
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{
+		TimeRange: telemetry.TimeRange{Start: end.Add(-time.Hour), End: end},
+	})
+	require.NoError(t, err)
+
+	assert.Len(t, spans, 2*tempoSearchPageSize+5)
+	assert.Equal(t, []string{"1700000000", "1699999900", "1699999800"}, ends)
+	assert.True(t, spans[0].StartTime.After(spans[len(spans)-1].StartTime), "spans should be ordered newest first")
+}
+
+func TestTempoAdapter_QuerySpans_Limit(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		requests++
+		traces := make([]map[string]interface{}, 0, tempoSearchPageSize)
+		for i := 0; i < tempoSearchPageSize; i++ {
+			traces = append(traces, map[string]interface{}{
+				"traceID":           fmt.Sprintf("%x", requests*1000+i+1),
+				"startTimeUnixNano": unixNanoString(time.Unix(1700000000-int64(i), 0)),
+				"spanSets":          []interface{}{map[string]interface{}{"spans": []interface{}{map[string]interface{}{"spanID": "1", "name": "task"}}}},
+			})
+		}
+		_, _ = w.Write(tempoSearchResponse(traces...))
+	}))
+	defer server.Close()
+
+	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL})
+	require.NoError(t, err)
+
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, spans, 10)
+	assert.Equal(t, 1, requests, "a page with enough spans should end pagination")
+}
+
+func TestTempoAdapter_QuerySpans_FetchesWithinLimit(t *testing.T) {
+	var mu sync.Mutex
+	fetches, inFlight, maxInFlight := 0, 0, 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/api/traces/") {
+			mu.Lock()
+			fetches++
+			inFlight++
+			maxInFlight = max(maxInFlight, inFlight)
+			mu.Unlock()
+			time.Sleep(5 * time.Millisecond)
+			mu.Lock()
+			inFlight--
+			mu.Unlock()
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		traces := make([]map[string]interface{}, 0, tempoSearchPageSize)
+		for i := 0; i < tempoSearchPageSize; i++ {
+			traces = append(traces, map[string]interface{}{
+				"traceID":           fmt.Sprintf("%x", i+1),
+				"startTimeUnixNano": unixNanoString(time.Unix(1700000000-int64(i), 0)),
+				"spanSets":          []interface{}{map[string]interface{}{"spans": []interface{}{map[string]interface{}{"spanID": "1", "name": "task"}}}},
+			})
+		}
+		_, _ = w.Write(tempoSearchResponse(traces...))
+	}))
+	defer server.Close()
+
+	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL})
+	require.NoError(t, err)
+
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{Limit: 20})
+	require.NoError(t, err)
+	assert.Len(t, spans, 20)
+	assert.Equal(t, 20, fetches, "only the traces within the limit should be fetched")
+	assert.LessOrEqual(t, maxInFlight, tempoMaxConcurrentFetches)
+}
+
+func TestTempoAdapter_QuerySpans_TraceID(t *testing.T) {
+	searched := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/search":
+			searched = true
+		case "/api/traces/0000000000000000000000000000abcd":
+			_, _ = w.Write([]byte(`{"batches":[{"scopeSpans":[{"spans":[
+				{"traceId":"abcd","spanId":"01","name":"a","attributes":[{"key":"task.name","value":{"stringValue":"keep"}}]},
+				{"traceId":"abcd","spanId":"02","name":"b","attributes":[{"key":"task.name","value":{"stringValue":"skip"}}]}
+			]}]}]}`))
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL})
+	require.NoError(t, err)
+
+	spans, err := adapter.QuerySpans(context.Background(), telemetry.SpanFilter{TraceID: "ABCD", TaskName: "keep"})
+	require.NoError(t, err)
+	require.Len(t, spans, 1)
+	assert.Equal(t, "keep", spans[0].TaskName)
+	assert.False(t, searched, "a trace ID filter should not search")
+
+	spans, err = adapter.QuerySpans(context.Background(), telemetry.SpanFilter{TraceID: "ffff"})
+	require.NoError(t, err)
+	assert.Empty(t, spans)
+}
+
+func TestTempoAdapter_Errors(t *testing.T) {
+	t.Run("API error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`invalid TraceQL query`))
+		}))
+		defer server.Close()
+
+		adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL})
+		require.NoError(t, err)
+
+		_, err = adapter.QuerySpans(context.Background(), telemetry.SpanFilter{TaskName: "x"})
+		assert.ErrorContains(t, err, "invalid TraceQL query")
+	})
+
+	t.Run("Response too large", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"traces":[]}` + strings.Repeat(" ", 64)))
+		}))
+		defer server.Close()
+
+		adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL, MaxResponseSize: 32})
+		require.NoError(t, err)
+
+		_, err = adapter.QuerySpans(context.Background(), telemetry.SpanFilter{})
+		assert.ErrorContains(t, err, "exceeds maximum allowed size")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(200 * time.Millisecond)
+		}))
+		defer server.Close()
+
+		adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL, Timeout: 20 * time.Millisecond})
+		require.NoError(t, err)
+
+		_, err = adapter.QuerySpans(context.Background(), telemetry.SpanFilter{})
+		assert.ErrorContains(t, err, "request failed")
+	})
+}
+
+func TestTempoAdapter_QueryMetrics(t *testing.T) {
+	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: "http://tempo:3200"})
+	require.NoError(t, err)
+
+	metrics, err := adapter.QueryMetrics(context.Background(), telemetry.MetricFilter{MetricName: "task_duration"})
+	require.NoError(t, err)
+	assert.Empty(t, metrics)
+}
+
+func TestTempoAdapter_Available(t *testing.T) {
+	var mu sync.Mutex
+	checks := 0
+	ready := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/ready", r.URL.Path)
+		mu.Lock()
+		defer mu.Unlock()
+		checks++
+		if !ready {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	adapter, err := NewTempoAdapter(TempoConfig{Endpoint: server.URL})
+	require.NoError(t, err)
+
+	now := time.Now()
+	adapter.availabilityCache.timeNow = func() time.Time { return now }
+
+	assert.True(t, adapter.Available())
+	mu.Lock()
+	ready = false
+	mu.Unlock()
+	assert.True(t, adapter.Available(), "result should be cached")
+
+	now = now.Add(31 * time.Second)
+	assert.False(t, adapter.Available())
+	assert.Equal(t, 2, checks)
+}
+
+// unixNanoString renders a time as a nanosecond Unix timestamp string
+func unixNanoString(t time.Time) string {
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
@@ -0,0 +1,118 @@
+package validation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ReasonValidationTimeout is the condition reason used when a validator exceeds its time or memory budget
+const ReasonValidationTimeout = "ValidationTimeout"
+
+// Limits bounds the resources a validator subprocess may use
+type Limits struct {
+	// Timeout is the wall-clock budget of a single validation run.
+	// Zero keeps each validator's built-in timeout.
+	Timeout time.Duration
+	// MemoryLimitBytes caps the validator's address space. Zero, the default, disables the cap.
+	// Runtimes reserve far more address space than they use, so the cap must be generous.
+	MemoryLimitBytes uint64
+}
+
+var (
+	limitsMu sync.RWMutex
+	limits   Limits
+)
+
+// SetLimits configures the limits applied to every validator subprocess
+func SetLimits(l Limits) {
+	limitsMu.Lock()
+	defer limitsMu.Unlock()
+	limits = l
+}
+
+// CurrentLimits returns the limits applied to validator subprocesses
+func CurrentLimits() Limits {
+	limitsMu.RLock()
+	defer limitsMu.RUnlock()
+	return limits
+}
+
+// LimitExceededError is returned when a validator is stopped for exceeding its time or memory budget
+type LimitExceededError struct {
+	// Resource is the exhausted budget, "time" or "memory"
+	Resource string
+	Limit    string
+}
+
+// Error implements the error interface
+func (e *LimitExceededError) Error() string {
+	return fmt.Sprintf("validation timeout: validator exceeded its %s limit (%s)", e.Resource, e.Limit)
+}
+
+// IsLimitExceeded reports whether err was caused by a validator exceeding its budget
+func IsLimitExceeded(err error) bool {
+	var limitErr *LimitExceededError
+	return errors.As(err, &limitErr)
+}
+
+// RunValidator runs a validator command with code on stdin, bounded by the configured limits.
+// defaultTimeout applies when no timeout is configured. Like exec.Cmd.Output it returns stdout,
+// with stderr attached to any *exec.ExitError; a *LimitExceededError replaces the error when
+// the validator ran out of time or memory.
+func RunValidator(ctx context.Context, defaultTimeout time.Duration, code string, name string, args ...string) ([]byte, error) {
+	l := CurrentLimits()
+	timeout := l.Timeout
+	if timeout <= 0 {
+		timeout = defaultTimeout
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	// The memory cap is applied before the validator is exec'd, so it holds from its first allocation
+	cmd := limitedCommand(ctx, l.MemoryLimitBytes, name, args...)
+	cmd.Stdin = strings.NewReader(code)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Start(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, &LimitExceededError{Resource: "time", Limit: timeout.String()}
+		}
+		return nil, err
+	}
+
+	err := cmd.Wait()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitErr.Stderr = stderr.Bytes()
+	}
+
+	if ctx.Err() == context.DeadlineExceeded {
+		return stdout.Bytes(), &LimitExceededError{Resource: "time", Limit: timeout.String()}
+	}
+	if l.MemoryLimitBytes > 0 && exitErr != nil && outOfMemory(exitErr) {
+		return stdout.Bytes(), &LimitExceededError{Resource: "memory", Limit: fmt.Sprintf("%d bytes", l.MemoryLimitBytes)}
+	}
+	return stdout.Bytes(), err
+}
+
+// outOfMemory reports whether a validator failed on an allocation under its memory cap, or was
+// killed by the kernel OOM killer. Other signals and crashes are ordinary validator failures.
+func outOfMemory(exitErr *exec.ExitError) bool {
+	if status, ok := exitErr.Sys().(syscall.WaitStatus); ok && status.Signaled() {
+		return status.Signal() == syscall.SIGKILL
+	}
+	stderr := string(exitErr.Stderr)
+	return strings.Contains(stderr, "NoMemoryError") ||
+		strings.Contains(stderr, "failed to allocate memory") ||
+		strings.Contains(stderr, "out of memory")
+}
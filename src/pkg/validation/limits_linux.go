@@ -0,0 +1,19 @@
+package validation
+
+import (
+	"context"
+	"os/exec"
+	"strconv"
+)
+
+// limitedCommand returns a command running name with its address space capped at bytes. The cap
+// is set by a shell that then execs the validator, so it is in place before the validator runs.
+func limitedCommand(ctx context.Context, bytes uint64, name string, args ...string) *exec.Cmd {
+	if bytes == 0 {
+		return exec.CommandContext(ctx, name, args...)
+	}
+	// ulimit -v takes KiB; round up so the cap is never below the configured limit
+	kib := strconv.FormatUint((bytes+1023)/1024, 10)
+	shellArgs := append([]string{"-c", `ulimit -v ` + kib + ` && exec "$0" "$@"`, name}, args...)
+	return exec.CommandContext(ctx, "/bin/sh", shellArgs...)
+}
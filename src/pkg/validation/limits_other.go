@@ -0,0 +1,13 @@
+//go:build !linux
+
+package validation
+
+import (
+	"context"
+	"os/exec"
+)
+
+// limitedCommand ignores the memory cap where per-process limits cannot be set; only the timeout applies
+func limitedCommand(ctx context.Context, bytes uint64, name string, args ...string) *exec.Cmd {
+	return exec.CommandContext(ctx, name, args...)
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package validation
+
+// abortHelperValidator crashes the helper validator where signals can't be sent to a process
+func abortHelperValidator() {
+	panic("helper validator crashed")
+}
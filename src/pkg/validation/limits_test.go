@@ -0,0 +1,98 @@
+package validation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime"
+	"testing"
+	"time"
+)
+
+// TestHelperValidator is not a real test. RunValidator tests run the test binary as a
+// misbehaving validator selected by GO_WANT_HELPER_VALIDATOR.
+func TestHelperValidator(t *testing.T) {
+	switch os.Getenv("GO_WANT_HELPER_VALIDATOR") {
+	case "hang":
+		time.Sleep(time.Minute)
+	case "allocate":
+		// Untouched allocations count against the address space cap without using real memory
+		var chunks [][]byte
+		for i := 0; i < 64; i++ {
+			chunks = append(chunks, make([]byte, 256<<20))
+		}
+		fmt.Println(len(chunks))
+	case "crash":
+		abortHelperValidator()
+		time.Sleep(time.Minute)
+	case "ok":
+		fmt.Print("[]")
+	default:
+		return
+	}
+	os.Exit(0)
+}
+
+// testMemoryLimitBytes is the address space cap the helper validator runs under
+const testMemoryLimitBytes = 2 << 30
+
+func runHelperValidator(t *testing.T, mode string, l Limits) ([]byte, time.Duration, error) {
+	t.Helper()
+	t.Setenv("GO_WANT_HELPER_VALIDATOR", mode)
+
+	previous := CurrentLimits()
+	SetLimits(l)
+	t.Cleanup(func() { SetLimits(previous) })
+
+	start := time.Now()
+	output, err := RunValidator(context.Background(), time.Minute, "agent 'test' do\nend", os.Args[0], "-test.run=TestHelperValidator")
+	return output, time.Since(start), err
+}
+
+func TestRunValidator_Timeout(t *testing.T) {
+	_, elapsed, err := runHelperValidator(t, "hang", Limits{Timeout: 200 * time.Millisecond})
+
+	if !IsLimitExceeded(err) {
+		t.Fatalf("Expected a limit exceeded error, got %v", err)
+	}
+	if elapsed > 10*time.Second {
+		t.Errorf("Expected a hanging validator to be stopped at its timeout, took %s", elapsed)
+	}
+	if limitErr := err.(*LimitExceededError); limitErr.Resource != "time" {
+		t.Errorf("Expected the time budget to be exhausted, got %s", limitErr.Resource)
+	}
+}
+
+func TestRunValidator_MemoryLimit(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("Validator memory limits are only enforced on Linux")
+	}
+
+	_, _, err := runHelperValidator(t, "allocate", Limits{Timeout: 30 * time.Second, MemoryLimitBytes: testMemoryLimitBytes})
+
+	if !IsLimitExceeded(err) {
+		t.Fatalf("Expected a limit exceeded error, got %v", err)
+	}
+	if limitErr := err.(*LimitExceededError); limitErr.Resource != "memory" {
+		t.Errorf("Expected the memory budget to be exhausted, got %s", limitErr.Resource)
+	}
+}
+
+func TestRunValidator_CrashIsNotOutOfMemory(t *testing.T) {
+	_, _, err := runHelperValidator(t, "crash", Limits{Timeout: 30 * time.Second, MemoryLimitBytes: testMemoryLimitBytes})
+
+	if err == nil || IsLimitExceeded(err) {
+		t.Fatalf("Expected a crash to fail as an ordinary validator error, got %v", err)
+	}
+}
+
+func TestRunValidator_WithinLimits(t *testing.T) {
+	output, _, err := runHelperValidator(t, "ok", Limits{Timeout: 30 * time.Second, MemoryLimitBytes: testMemoryLimitBytes})
+
+	if err != nil {
+		t.Fatalf("Expected validator to succeed, got %v", err)
+	}
+	if string(output) != "[]" {
+		t.Errorf("Expected validator output, got %q", output)
+	}
+}
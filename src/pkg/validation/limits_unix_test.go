@@ -0,0 +1,13 @@
+//go:build unix
+
+package validation
+
+import (
+	"os"
+	"syscall"
+)
+
+// abortHelperValidator crashes the helper validator with a signal other than the SIGKILL of the OOM killer
+func abortHelperValidator() {
+	_ = syscall.Kill(os.Getpid(), syscall.SIGABRT)
+}
@@ -59,23 +59,16 @@ func ValidateRubyCode(code string) error {
 		return nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
-	defer cancel()
-
 	// Find the validator script
 	scriptPath := findValidatorScript()
 
 	// Execute Ruby wrapper script that calls the gem's AST validator
-	cmd := exec.CommandContext(ctx, "ruby", scriptPath)
-	cmd.Stdin = strings.NewReader(code)
-
-	// Capture STDOUT and STDERR separately
 	// STDERR may contain parser warnings that should not interfere with JSON parsing
-	output, err := cmd.Output()
+	output, err := RunValidator(context.Background(), 1*time.Second, code, "ruby", scriptPath)
 
-	// Check for timeout
-	if ctx.Err() == context.DeadlineExceeded {
-		return fmt.Errorf("validation timeout: code too large or complex (>1s)")
+	// A validator over its time or memory budget fails validation rather than hanging
+	if IsLimitExceeded(err) {
+		return err
 	}
 
 	// Parse JSON output from validator (STDOUT only)
@@ -121,15 +114,11 @@ func ValidateGeneratedCodeAgainstSchema(ctx context.Context, code string) ([]Vio
 	scriptPath := findValidatorScript()
 
 	// Execute Ruby wrapper script that calls the gem's AST validator
-	cmd := exec.CommandContext(ctx, "ruby", scriptPath)
-	cmd.Stdin = strings.NewReader(code)
-
-	// Capture STDOUT and STDERR separately
-	output, err := cmd.Output()
+	output, err := RunValidator(ctx, 5*time.Second, code, "ruby", scriptPath)
 
-	// Check for timeout
-	if ctx.Err() == context.DeadlineExceeded {
-		return nil, fmt.Errorf("validation timeout: code too large or complex")
+	// A validator over its time or memory budget fails validation rather than hanging
+	if IsLimitExceeded(err) {
+		return nil, err
 	}
 
 	// Parse JSON output from validator (STDOUT only)